@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
 	"github.com/openGemini/openGemini/engine/executor/spdy"
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/cpu"
@@ -51,6 +52,7 @@ type Command struct {
 	ServiceName   string
 	Server        Server
 	Config        config.Config
+	configPath    string
 	NewServerFunc func(config.Config, *cobra.Command, *logger.Logger) (Server, error)
 }
 
@@ -135,5 +137,48 @@ func (cmd *Command) InitConfig(conf config.Config, path string) error {
 	}
 
 	cmd.Config = conf
+	cmd.configPath = path
 	return nil
 }
+
+// WatchConfigReload makes the configuration file reloadable, it is read again
+// when the process receives SIGHUP. newConf returns the default configuration
+// of the command. Must be called after the server is opened.
+func (cmd *Command) WatchConfigReload(newConf func() config.Config) *config.Reloader {
+	r := config.NewReloader(cmd.configPath, cmd.Config, newConf)
+	r.Register(config.ReloadHandler{
+		Module: "logging",
+		Keys:   []string{"logging.level"},
+		Apply: func(conf config.Config) error {
+			logger.SetLevel(conf.GetLogging().Level)
+			return nil
+		},
+	})
+	if s, ok := cmd.Server.(ReloadableServer); ok {
+		s.RegisterReloadHandlers(r)
+	}
+
+	sighup := procutil.NewSighupChan()
+	go func() {
+		for {
+			select {
+			case <-sighup:
+				cmd.reloadConfig(r)
+			case <-cmd.closing:
+				return
+			}
+		}
+	}()
+	return r
+}
+
+func (cmd *Command) reloadConfig(r *config.Reloader) {
+	res, err := r.Reload()
+	if err != nil {
+		cmd.Logger.Error("reload configuration failed", zap.String("path", cmd.configPath), zap.Error(err))
+	}
+	if res != nil {
+		cmd.Logger.Info("configuration reloaded", zap.String("path", cmd.configPath),
+			zap.Strings("applied", res.Applied), zap.Strings("restart_required", res.RestartRequired))
+	}
+}
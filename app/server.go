@@ -17,6 +17,7 @@ limitations under the License.
 package app
 
 import (
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/open_src/github.com/hashicorp/serf/serf"
 )
 
@@ -26,6 +27,11 @@ type Server interface {
 	Err() <-chan error
 }
 
+// ReloadableServer is a server having settings which can be changed without a restart.
+type ReloadableServer interface {
+	RegisterReloadHandlers(r *config.Reloader)
+}
+
 func CreateSerfInstance(conf *serf.Config, clock uint64, members []string, preNodes []*serf.PreviousNode) (*serf.Serf, error) {
 	if conf == nil {
 		return nil, nil
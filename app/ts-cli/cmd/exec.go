@@ -36,6 +36,15 @@ var (
 
 // Execute executes the root command.
 func Execute() error {
+	// offline commands do not connect to the server, they are not part of the compatible options
+	if len(os.Args) > 1 && os.Args[1] == inspectCmd.Name() {
+		return rootCmd.Execute()
+	}
+	// import sends files to the server with the connection flags of cobra
+	if len(os.Args) > 1 && os.Args[1] == importCmd.Name() {
+		return executeCobra()
+	}
+
 	if COMPATIBLE {
 		return executeCompatible()
 	} else {
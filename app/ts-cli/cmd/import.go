@@ -0,0 +1,365 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/openGemini/openGemini/app/ts-cli/geminicli"
+	"github.com/openGemini/openGemini/lib/importer"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DefaultImportBatchLines = 10000
+
+	importProgressSuffix = ".progress"
+)
+
+type importFlags struct {
+	format     string
+	mapping    string
+	rp         string
+	precision  string
+	progress   string
+	batchLines int
+}
+
+var (
+	impFlags = importFlags{}
+)
+
+func init() {
+	importCmd.Flags().StringVar(&impFlags.format, "format", "", "Format of the file, csv or json. Default is detected by the file extension.")
+	importCmd.Flags().StringVar(&impFlags.mapping, "mapping", "", "Json file mapping the columns to measurement, tags, fields and time.")
+	importCmd.Flags().StringVar(&impFlags.rp, "rp", "", "Retention policy to write.")
+	importCmd.Flags().StringVar(&impFlags.precision, "precision", "ns", "Precision of numeric timestamps, ns, u, ms, s, m or h.")
+	importCmd.Flags().StringVar(&impFlags.progress, "progress", "", "File to record the imported lines, default is <file>.progress.")
+	importCmd.Flags().IntVar(&impFlags.batchLines, "batch-lines", DefaultImportBatchLines, "Number of lines sent in one request.")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+var (
+	importCmd = &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import a csv or json lines file",
+		Long: `Import a csv or json lines file through the /api/v1/import endpoint.
+The file is sent in batches, the last committed line is recorded in the progress file
+and an interrupted import resumes after it when run again.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			imp, err := newFileImporter(&gFlags, &impFlags, args[0])
+			if err != nil {
+				return err
+			}
+			return imp.run(cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+)
+
+type fileImporter struct {
+	client   *http.Client
+	url      string
+	username string
+	password string
+	params   url.Values
+
+	file       string
+	progress   string
+	csv        bool
+	batchLines int
+}
+
+func newFileImporter(c *geminicli.CommandLineConfig, flags *importFlags, file string) (*fileImporter, error) {
+	if c.Database == "" {
+		return nil, errors.New("database is required")
+	}
+	format := flags.format
+	if format == "" {
+		format = importFormatOf(file)
+	}
+	if format != importer.FormatCSV && format != importer.FormatJSON {
+		return nil, fmt.Errorf("unknown format %q, csv or json is required", format)
+	}
+
+	params := url.Values{}
+	params.Set("db", c.Database)
+	params.Set("format", format)
+	if flags.rp != "" {
+		params.Set("rp", flags.rp)
+	}
+	if flags.precision != "" {
+		params.Set("precision", flags.precision)
+	}
+	if flags.mapping != "" {
+		b, err := ioutil.ReadFile(flags.mapping)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := importer.ParseMapping(b); err != nil {
+			return nil, fmt.Errorf("invalid mapping %s: %v", flags.mapping, err)
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, b); err != nil {
+			return nil, err
+		}
+		params.Set("mapping", buf.String())
+	}
+
+	imp := &fileImporter{
+		client:     importHttpClient(c),
+		url:        importURL(c),
+		username:   c.Username,
+		password:   c.Password,
+		params:     params,
+		file:       file,
+		progress:   flags.progress,
+		csv:        format == importer.FormatCSV,
+		batchLines: flags.batchLines,
+	}
+	if imp.progress == "" {
+		imp.progress = file + importProgressSuffix
+	}
+	if imp.batchLines <= 0 {
+		imp.batchLines = DefaultImportBatchLines
+	}
+	return imp, nil
+}
+
+func importFormatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return importer.FormatCSV
+	case ".json", ".jsonl", ".ndjson":
+		return importer.FormatJSON
+	default:
+		return ""
+	}
+}
+
+func importURL(c *geminicli.CommandLineConfig) string {
+	scheme := "http"
+	if c.Ssl {
+		scheme = "https"
+	}
+	host := c.Host
+	if c.UnixSocket != "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s/api/v1/import", scheme, net.JoinHostPort(host, strconv.Itoa(c.Port)))
+}
+
+func importHttpClient(c *geminicli.CommandLineConfig) *http.Client {
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: c.IgnoreSsl},
+	}
+	if c.UnixSocket != "" {
+		socket := c.UnixSocket
+		tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+	}
+	return &http.Client{Transport: tr}
+}
+
+// run sends the file in batches of lines, the csv header is sent again with every batch
+func (imp *fileImporter) run(stdout, stderr io.Writer) error {
+	committed, err := imp.readProgress()
+	if err != nil {
+		return err
+	}
+	if committed > 0 {
+		fmt.Fprintf(stdout, "resuming after line %d\n", committed)
+	}
+
+	f, err := os.Open(imp.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var header []byte
+	headerLines, line := 0, 0
+	if imp.csv {
+		header, headerLines, err = readCSVHeader(r)
+		if err != nil {
+			return err
+		}
+		line = headerLines
+	}
+
+	total := importer.Result{}
+	var batch bytes.Buffer
+	for {
+		batch.Reset()
+		batch.Write(header)
+		first, n := line+1, 0
+		for n < imp.batchLines {
+			b, err := r.ReadBytes('\n')
+			if len(b) > 0 {
+				line++
+				if line > committed {
+					batch.Write(b)
+					if b[len(b)-1] != '\n' {
+						batch.WriteByte('\n')
+					}
+					n++
+				} else {
+					first = line + 1
+				}
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if n == 0 {
+			break
+		}
+
+		res, err := imp.send(batch.Bytes(), first-1-headerLines)
+		if res != nil {
+			total.Lines += res.Lines
+			total.Written += res.Written
+			total.Rejected += res.Rejected
+			for _, e := range res.Errors {
+				fmt.Fprintf(stderr, "line %d: %s\n", e.Line, e.Error)
+			}
+		}
+		if err != nil {
+			if res != nil && res.Committed > committed {
+				committed = res.Committed
+				if perr := imp.saveProgress(committed); perr != nil {
+					return perr
+				}
+			}
+			return fmt.Errorf("import failed after line %d: %v", committed, err)
+		}
+		committed = line
+		if err := imp.saveProgress(committed); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(imp.progress); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	fmt.Fprintf(stdout, "imported %d lines: %d written, %d rejected\n", total.Lines, total.Written, total.Rejected)
+	return nil
+}
+
+func (imp *fileImporter) send(body []byte, lineOffset int) (*importer.Result, error) {
+	params := url.Values{}
+	for k, v := range imp.params {
+		params[k] = v
+	}
+	params.Set("line_offset", strconv.Itoa(lineOffset))
+
+	req, err := http.NewRequest(http.MethodPost, imp.url+"?"+params.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if imp.username != "" {
+		req.SetBasicAuth(imp.username, imp.password)
+	}
+	resp, err := imp.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	res := &importer.Result{}
+	if err := json.Unmarshal(b, res); err != nil {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	if resp.StatusCode != http.StatusOK {
+		if res.Error == "" {
+			res.Error = resp.Status
+		}
+		return res, errors.New(res.Error)
+	}
+	return res, nil
+}
+
+// readCSVHeader reads the annotation and comment lines and the header line
+func readCSVHeader(r *bufio.Reader) ([]byte, int, error) {
+	var header []byte
+	lines := 0
+	for {
+		b, err := r.ReadBytes('\n')
+		if len(b) > 0 {
+			lines++
+			header = append(header, b...)
+			if b[len(b)-1] != '\n' {
+				header = append(header, '\n')
+			}
+			s := strings.TrimSpace(string(b))
+			if s != "" && !strings.HasPrefix(s, "#") {
+				return header, lines, nil
+			}
+		}
+		if err == io.EOF {
+			return nil, 0, importer.ErrMissingHeader
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+func (imp *fileImporter) readProgress() (int, error) {
+	b, err := ioutil.ReadFile(imp.progress)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid progress file %s: %v", imp.progress, err)
+	}
+	return n, nil
+}
+
+func (imp *fileImporter) saveProgress(line int) error {
+	return ioutil.WriteFile(imp.progress, []byte(strconv.Itoa(line)+"\n"), 0640)
+}
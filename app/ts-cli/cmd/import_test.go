@@ -0,0 +1,131 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/openGemini/openGemini/app/ts-cli/geminicli"
+	"github.com/openGemini/openGemini/lib/importer"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+type importTestServer struct {
+	failAfter int
+	written   []string
+	offsets   []string
+}
+
+func (s *importTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	offset, _ := strconv.Atoi(q.Get("line_offset"))
+	s.offsets = append(s.offsets, q.Get("line_offset"))
+	dec, err := importer.NewDecoder(r.Body, importer.Options{Format: q.Get("format"), LineOffset: offset, TsMultiplier: 1})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	res, err := importer.Import(dec, 1, func(rows []influx.Row) error {
+		if s.failAfter >= 0 && len(s.written) >= s.failAfter {
+			return errors.New("write failed")
+		}
+		for i := range rows {
+			s.written = append(s.written, rows[i].Tags[0].Value)
+		}
+		return nil
+	})
+	if err != nil {
+		res.Error = err.Error()
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	b, _ := json.Marshal(res)
+	w.Write(b)
+}
+
+func TestImport(t *testing.T) {
+	server := &importTestServer{failAfter: 3}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	port, _ := strconv.Atoi(u.Port())
+
+	file := filepath.Join(t.TempDir(), "data.csv")
+	data := "#datatype measurement,tag,double,dateTime:number\n" +
+		"m,host,value,time\n" +
+		"cpu,a,1,1\n" +
+		"cpu,b,bad,2\n" +
+		"\n" +
+		"cpu,c,3,3\n" +
+		"cpu,d,4,4\n" +
+		"cpu,e,5,5\n"
+	require.NoError(t, ioutil.WriteFile(file, []byte(data), 0600))
+
+	c := &geminicli.CommandLineConfig{Host: u.Hostname(), Port: port, Database: "db0"}
+	imp, err := newFileImporter(c, &importFlags{batchLines: 2}, file)
+	require.NoError(t, err)
+	require.True(t, imp.csv)
+
+	var stdout, stderr bytes.Buffer
+	err = imp.run(&stdout, &stderr)
+	require.Error(t, err)
+	require.Equal(t, "line 4: ", stderr.String()[:8])
+	require.Equal(t, []string{"a", "c", "d"}, server.written)
+
+	progress, err := imp.readProgress()
+	require.NoError(t, err)
+	require.Equal(t, 7, progress)
+
+	server.failAfter = -1
+	server.offsets = nil
+	stdout.Reset()
+	stderr.Reset()
+	require.NoError(t, imp.run(&stdout, &stderr))
+	require.Equal(t, []string{"a", "c", "d", "e"}, server.written)
+	require.Equal(t, []string{"5"}, server.offsets)
+	require.Contains(t, stdout.String(), "resuming after line 7")
+	require.Contains(t, stdout.String(), "imported 1 lines: 1 written, 0 rejected")
+
+	_, err = os.Stat(imp.progress)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestImportFormat(t *testing.T) {
+	c := &geminicli.CommandLineConfig{Host: "localhost", Port: 8086, Database: "db0"}
+	_, err := newFileImporter(c, &importFlags{}, "data.txt")
+	require.Error(t, err)
+
+	imp, err := newFileImporter(c, &importFlags{}, "data.jsonl")
+	require.NoError(t, err)
+	require.False(t, imp.csv)
+	require.Equal(t, "data.jsonl.progress", imp.progress)
+	require.Equal(t, "http://localhost:8086/api/v1/import", imp.url)
+
+	_, err = newFileImporter(&geminicli.CommandLineConfig{}, &importFlags{}, "data.csv")
+	require.Error(t, err)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/openGemini/openGemini/engine"
+	"github.com/spf13/cobra"
+)
+
+type indexFlags struct {
+	indexID uint64
+}
+
+var (
+	idxFlags = indexFlags{}
+)
+
+func init() {
+	indexCmd.AddCommand(indexRebuildCmd)
+
+	indexRebuildCmd.Flags().Uint64Var(&idxFlags.indexID, "index-id", 0, "Id of the index to rebuild.")
+	_ = indexRebuildCmd.MarkFlagRequired("index-id")
+
+	rootCmd.AddCommand(indexCmd)
+}
+
+var (
+	indexCmd = &cobra.Command{
+		Use:   "index",
+		Short: "Maintain the series index of a stopped store",
+		Long: `Maintain the series index of a stopped store. The index is repaired from the series keys it
+still keeps, a lost or emptied index can not be regenerated from the tssp files, which keep the
+series ids only.`,
+	}
+
+	indexRebuildCmd = &cobra.Command{
+		Use:   "rebuild <rp dir>",
+		Short: "Rebuild an index from the series of the tssp files of its shards",
+		Long: `Rebuild the index entries of the series found in the tssp files of the shards of an index,
+from the series keys kept in the index. The rp dir is the data directory of a retention policy,
+such as <data-dir>/data/<db>/<pt>/<rp>. The store must be stopped.
+
+The series whose key is lost are counted as without key, their data can not be queried by the
+series key any more. The rebuild fails if the keys of all the series are lost, a lost index
+can not be regenerated.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rebuildIndex(cmd.OutOrStdout(), args[0], idxFlags.indexID)
+		},
+	}
+)
+
+func rebuildIndex(w io.Writer, rpDir string, indexID uint64) error {
+	rebuilt, missing, err := engine.RebuildIndexOffline(rpDir, indexID)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "index %d rebuilt: %d series, %d series without key\n", indexID, rebuilt, missing)
+	return err
+}
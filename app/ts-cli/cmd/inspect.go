@@ -0,0 +1,371 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ExportFormatLine = "line"
+	ExportFormatCSV  = "csv"
+
+	// the tag added to exported points, tssp files only keep the series id instead of the series key
+	ExportSeriesTag = "sid"
+)
+
+type inspectFlags struct {
+	sid      uint64
+	columns  bool
+	segments bool
+	format   string
+	output   string
+}
+
+var (
+	iFlags = inspectFlags{}
+)
+
+func init() {
+	inspectCmd.AddCommand(inspectSummaryCmd, inspectChunksCmd, inspectVerifyCmd, inspectExportCmd)
+
+	inspectChunksCmd.Flags().Uint64Var(&iFlags.sid, "sid", 0, "Only show the chunk of the series, 0 means all series.")
+	inspectChunksCmd.Flags().BoolVar(&iFlags.columns, "columns", false, "Show the columns and pre-aggregations of chunks.")
+	inspectChunksCmd.Flags().BoolVar(&iFlags.segments, "segments", false, "Show the time range of segments.")
+
+	inspectExportCmd.Flags().Uint64Var(&iFlags.sid, "sid", 0, "Series to export, 0 means all series.")
+	inspectExportCmd.Flags().StringVar(&iFlags.format, "format", ExportFormatLine, "Export format, line or csv.")
+	inspectExportCmd.Flags().StringVar(&iFlags.output, "output", "", "File to write, default is stdout.")
+
+	rootCmd.AddCommand(inspectCmd)
+}
+
+var (
+	inspectCmd = &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect tssp files offline",
+		Long: `Inspect the structure of tssp files without a running server.
+The files are opened read only.`,
+	}
+
+	inspectSummaryCmd = &cobra.Command{
+		Use:   "summary <file>",
+		Short: "Show the trailer and meta index of a tssp file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInspect(args[0], func(fi *immutable.FileInspector) error {
+				return inspectSummary(cmd.OutOrStdout(), fi)
+			})
+		},
+	}
+
+	inspectChunksCmd = &cobra.Command{
+		Use:   "chunks <file>",
+		Short: "Show the chunk metas of a tssp file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInspect(args[0], func(fi *immutable.FileInspector) error {
+				return inspectChunks(cmd.OutOrStdout(), fi, &iFlags)
+			})
+		},
+	}
+
+	inspectVerifyCmd = &cobra.Command{
+		Use:   "verify <file>",
+		Short: "Verify the meta data and decode every segment of a tssp file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInspect(args[0], func(fi *immutable.FileInspector) error {
+				return inspectVerify(cmd.OutOrStdout(), fi)
+			})
+		},
+	}
+
+	inspectExportCmd = &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export the rows of a tssp file as line protocol or csv",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			if iFlags.output != "" {
+				f, err := os.Create(iFlags.output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			return runInspect(args[0], func(fi *immutable.FileInspector) error {
+				return inspectExport(w, fi, &iFlags)
+			})
+		},
+	}
+)
+
+func runInspect(file string, fn func(fi *immutable.FileInspector) error) error {
+	fi, err := immutable.NewFileInspector(file)
+	if err != nil {
+		return err
+	}
+	defer fi.Close()
+	return fn(fi)
+}
+
+func formatTime(t int64) string {
+	return fmt.Sprintf("%d (%s)", t, time.Unix(0, t).UTC().Format(time.RFC3339Nano))
+}
+
+func inspectSummary(w io.Writer, fi *immutable.FileInspector) error {
+	s := fi.Summary()
+	fmt.Fprintf(w, "File:            %s\n", s.Name)
+	fmt.Fprintf(w, "Measurement:     %s\n", s.Measurement)
+	fmt.Fprintf(w, "Version:         %d\n", s.Version)
+	fmt.Fprintf(w, "File size:       %d\n", s.FileSize)
+	fmt.Fprintf(w, "Series:          %d [%d, %d]\n", s.SeriesCount, s.MinSeriesID, s.MaxSeriesID)
+	fmt.Fprintf(w, "Min time:        %s\n", formatTime(s.MinTime))
+	fmt.Fprintf(w, "Max time:        %s\n", formatTime(s.MaxTime))
+	fmt.Fprintf(w, "Data:            offset %d, size %d\n", s.DataOffset, s.DataSize)
+	fmt.Fprintf(w, "Chunk meta size: %d\n", s.IndexSize)
+	fmt.Fprintf(w, "Meta index:      %d items, size %d\n", s.MetaIndexCount, s.MetaIndexSize)
+	fmt.Fprintf(w, "Bloom filter:    size %d, m %d, k %d\n", s.BloomSize, s.BloomM, s.BloomK)
+	fmt.Fprintf(w, "Id time size:    %d\n", s.IdTimeSize)
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"#", "SID", "MIN TIME", "MAX TIME", "OFFSET", "SIZE", "CHUNKS"})
+	for i, m := range fi.MetaIndexes() {
+		t.AppendRow(table.Row{i, m.SeriesID, m.MinTime, m.MaxTime, m.Offset, m.Size, m.ChunkCount})
+	}
+	t.Render()
+	return nil
+}
+
+func inspectChunks(w io.Writer, fi *immutable.FileInspector, flags *inspectFlags) error {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"SID", "OFFSET", "SIZE", "ROWS", "SEGMENTS", "MIN TIME", "MAX TIME", "BLOOM", "DETAIL"})
+	err := fi.WalkChunkMetas(func(_ int, cm *immutable.ChunkMeta) error {
+		if flags.sid != 0 && cm.GetSid() != flags.sid {
+			return nil
+		}
+		info, err := fi.ChunkMetaInfo(cm)
+		if err != nil {
+			return err
+		}
+		t.AppendRow(table.Row{info.SeriesID, info.Offset, info.Size, info.Rows, len(info.Segments),
+			info.MinTime, info.MaxTime, fi.BloomContains(info.SeriesID)})
+
+		if flags.segments {
+			for i, seg := range info.Segments {
+				t.AppendRow(table.Row{"", "", "", "", "", "", "", "", fmt.Sprintf("segment %d: [%d, %d]", i, seg.Min, seg.Max)})
+			}
+		}
+		if flags.columns {
+			for _, col := range info.Columns {
+				t.AppendRow(table.Row{"", "", "", "", "", "", "", "",
+					fmt.Sprintf("%s (%s): %s", col.Name, influx.FieldTypeName[col.Type], formatPreAgg(&col.PreAgg))})
+			}
+		}
+		return nil
+	})
+	t.Render()
+	return err
+}
+
+func formatPreAgg(p *immutable.PreAggInfo) string {
+	if p.Min == nil {
+		return fmt.Sprintf("count=%d", p.Count)
+	}
+	s := fmt.Sprintf("count=%d min=%v@%d max=%v@%d", p.Count, p.Min, p.MinTime, p.Max, p.MaxTime)
+	if p.Sum != nil {
+		s += fmt.Sprintf(" sum=%v", p.Sum)
+	}
+	return s
+}
+
+func inspectVerify(w io.Writer, fi *immutable.FileInspector) error {
+	res := fi.Verify()
+	fmt.Fprintf(w, "Series: %d, segments: %d, rows: %d\n", res.Series, res.Segments, res.Rows)
+	for _, err := range res.Errors {
+		fmt.Fprintf(w, "ERROR: %v\n", err)
+	}
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("verify %s failed, %d errors", fi.Summary().Name, len(res.Errors))
+	}
+	fmt.Fprintln(w, "OK")
+	return nil
+}
+
+func inspectExport(w io.Writer, fi *immutable.FileInspector, flags *inspectFlags) error {
+	var exp rowExporter
+	switch flags.format {
+	case ExportFormatLine:
+		exp = &lineExporter{w: w, name: fi.Summary().Measurement}
+	case ExportFormatCSV:
+		exp = &csvExporter{w: csv.NewWriter(w)}
+	default:
+		return fmt.Errorf("unsupported export format: %s", flags.format)
+	}
+
+	if flags.sid != 0 {
+		err := fi.ReadSeries(flags.sid, func(rec *record.Record) error {
+			return exp.export(flags.sid, rec)
+		})
+		if err != nil {
+			return err
+		}
+		return exp.flush()
+	}
+
+	err := fi.WalkChunkMetas(func(_ int, cm *immutable.ChunkMeta) error {
+		return fi.ReadChunk(cm, func(_ int, rec *record.Record) error {
+			return exp.export(cm.GetSid(), rec)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return exp.flush()
+}
+
+type rowExporter interface {
+	export(sid uint64, rec *record.Record) error
+	flush() error
+}
+
+// columnValue returns the value of the row, nil if the value is null
+func columnValue(rec *record.Record, col, row int) interface{} {
+	cv := rec.Column(col)
+	switch rec.Schema[col].Type {
+	case influx.Field_Type_Int:
+		if v, isNil := cv.IntegerValue(row); !isNil {
+			return v
+		}
+	case influx.Field_Type_Float:
+		if v, isNil := cv.FloatValue(row); !isNil {
+			return v
+		}
+	case influx.Field_Type_String:
+		if v, isNil := cv.StringValueSafe(row); !isNil {
+			return v
+		}
+	case influx.Field_Type_Boolean:
+		if v, isNil := cv.BooleanValue(row); !isNil {
+			return v
+		}
+	}
+	return nil
+}
+
+type lineExporter struct {
+	w    io.Writer
+	name string
+}
+
+func (e *lineExporter) export(sid uint64, rec *record.Record) error {
+	tags := models.NewTags(map[string]string{ExportSeriesTag: strconv.FormatUint(sid, 10)})
+	times := rec.Times()
+	for row := range times {
+		fields := make(models.Fields, rec.ColNums()-1)
+		for col := 0; col < rec.ColNums()-1; col++ {
+			if v := columnValue(rec, col, row); v != nil {
+				fields[rec.Schema[col].Name] = v
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		pt, err := models.NewPoint(e.name, tags, fields, time.Unix(0, times[row]))
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintln(e.w, pt.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *lineExporter) flush() error {
+	return nil
+}
+
+type csvExporter struct {
+	w      *csv.Writer
+	header []string
+}
+
+// export writes a header whenever the columns change, series in a file may have different columns
+func (e *csvExporter) export(sid uint64, rec *record.Record) error {
+	header := make([]string, 0, rec.ColNums()+1)
+	header = append(header, ExportSeriesTag, record.TimeField)
+	for col := 0; col < rec.ColNums()-1; col++ {
+		header = append(header, rec.Schema[col].Name)
+	}
+	if !equalStrings(header, e.header) {
+		e.header = header
+		if err := e.w.Write(header); err != nil {
+			return err
+		}
+	}
+
+	line := make([]string, len(header))
+	times := rec.Times()
+	for row := range times {
+		line[0] = strconv.FormatUint(sid, 10)
+		line[1] = strconv.FormatInt(times[row], 10)
+		for col := 0; col < rec.ColNums()-1; col++ {
+			line[col+2] = ""
+			if v := columnValue(rec, col, row); v != nil {
+				line[col+2] = fmt.Sprintf("%v", v)
+			}
+		}
+		if err := e.w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvExporter) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
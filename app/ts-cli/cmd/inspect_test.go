@@ -0,0 +1,121 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestTSSPFile(t *testing.T) string {
+	dir := t.TempDir()
+	conf := immutable.NewConfig()
+	tier := uint64(meta.Hot)
+	store := immutable.NewTableStore(dir, &tier, false, conf)
+	defer store.Close()
+
+	schema := []record.Field{
+		{Name: "f1", Type: influx.Field_Type_Int},
+		{Name: "f2", Type: influx.Field_Type_String},
+		{Name: "time", Type: influx.Field_Type_Int},
+	}
+	fileName := immutable.NewTSSPFileName(1, 0, 0, 0, true)
+	msb := immutable.AllocMsBuilder(dir, "cpu", conf, 2, fileName, 0, store.Sequencer(), 2)
+	for sid := uint64(1); sid <= 2; sid++ {
+		rec := record.NewRecordBuilder(schema)
+		rec.Column(0).AppendInteger(int64(sid))
+		rec.Column(0).AppendIntegerNull()
+		rec.Column(1).AppendString("a b")
+		rec.Column(1).AppendString("c")
+		rec.Column(2).AppendInteger(1)
+		rec.Column(2).AppendInteger(2)
+		require.NoError(t, msb.WriteData(sid, rec))
+	}
+	store.AddTable(msb, true, false)
+
+	files, err := filepath.Glob(filepath.Join(dir, "cpu", "*.tssp"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	return files[0]
+}
+
+func TestInspect(t *testing.T) {
+	file := writeTestTSSPFile(t)
+	run := func(flags inspectFlags, fn func(w *bytes.Buffer, fi *immutable.FileInspector) error) string {
+		var buf bytes.Buffer
+		err := runInspect(file, func(fi *immutable.FileInspector) error {
+			return fn(&buf, fi)
+		})
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	out := run(inspectFlags{}, func(w *bytes.Buffer, fi *immutable.FileInspector) error {
+		return inspectSummary(w, fi)
+	})
+	require.Contains(t, out, "Measurement:     cpu")
+	require.Contains(t, out, "Series:          2 [1, 2]")
+
+	flags := inspectFlags{sid: 2, columns: true, segments: true}
+	out = run(flags, func(w *bytes.Buffer, fi *immutable.FileInspector) error {
+		return inspectChunks(w, fi, &flags)
+	})
+	require.Contains(t, out, "f1 (Integer): count=1 min=2@1 max=2@1 sum=2")
+	require.Contains(t, out, "segment 0: [1, 2]")
+	require.NotContains(t, out, "| 1   |")
+
+	out = run(inspectFlags{}, func(w *bytes.Buffer, fi *immutable.FileInspector) error {
+		return inspectVerify(w, fi)
+	})
+	require.Contains(t, out, "Series: 2, segments: 2, rows: 4")
+	require.Contains(t, out, "OK")
+
+	flags = inspectFlags{sid: 1, format: ExportFormatLine}
+	out = run(flags, func(w *bytes.Buffer, fi *immutable.FileInspector) error {
+		return inspectExport(w, fi, &flags)
+	})
+	require.Equal(t, "cpu,sid=1 f1=1i,f2=\"a b\" 1\ncpu,sid=1 f2=\"c\" 2\n", out)
+
+	flags = inspectFlags{format: ExportFormatCSV}
+	out = run(flags, func(w *bytes.Buffer, fi *immutable.FileInspector) error {
+		return inspectExport(w, fi, &flags)
+	})
+	lines, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"sid", "time", "f1", "f2"},
+		{"1", "1", "1", "a b"},
+		{"1", "2", "", "c"},
+		{"2", "1", "2", "a b"},
+		{"2", "2", "", "c"},
+	}, lines)
+
+	flags = inspectFlags{format: "json"}
+	err = runInspect(file, func(fi *immutable.FileInspector) error {
+		return inspectExport(&bytes.Buffer{}, fi, &flags)
+	})
+	require.EqualError(t, err, "unsupported export format: json")
+}
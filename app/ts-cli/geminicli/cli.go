@@ -24,15 +24,12 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
-	"sort"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/influxdata/influxdb/client"
-	"github.com/influxdata/influxdb/models"
-	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/openGemini/openGemini/app/ts-cli/geminiql"
 	"github.com/openGemini/openGemini/open_src/influx/influxql"
 )
@@ -40,7 +37,7 @@ import (
 const (
 	CLIENT_VERSION    = "0.1.0"
 	DEFAULT_PRECISION = "ns"
-	DEFAULT_FORMAT    = "column"
+	DEFAULT_FORMAT    = FORMAT_TABLE
 )
 
 var (
@@ -63,12 +60,14 @@ type HttpClient interface {
 	Ping() (time.Duration, string, error)
 	QueryContext(context.Context, client.Query) (*client.Response, error)
 	Write(bp client.BatchPoints) (*client.Response, error)
+	SetPrecision(precision string)
+	QueryChunked(ctx context.Context, q client.Query, fn func(*client.Response) error) error
 }
 
 type HttpClientCreator func(client.Config) (HttpClient, error)
 
 func defaultHttpClientCreator(c client.Config) (HttpClient, error) {
-	return client.NewClient(c)
+	return newHttpClient(c)
 }
 
 type CommandLineFactory struct {
@@ -80,6 +79,10 @@ func (f CommandLineFactory) CreateCommandLine(config CommandLineConfig) (*Comman
 		osSignals:     make(chan os.Signal, 1),
 		parser:        geminiql.QLNewParser(),
 		clientCreator: defaultHttpClientCreator,
+		format:        DEFAULT_FORMAT,
+		timing:        true,
+		output:        os.Stdout,
+		history:       historyPath(),
 	}
 
 	addr := fmt.Sprintf("%s:%d/%s", config.Host, config.Port, "")
@@ -95,7 +98,8 @@ func (f CommandLineFactory) CreateCommandLine(config CommandLineConfig) (*Comman
 	c.config.Password = config.Password
 	c.config.UnsafeSsl = config.IgnoreSsl
 
-	c.config.Precision = DEFAULT_PRECISION
+	c.precision = DEFAULT_PRECISION
+	c.config.Precision = precisions[c.precision]
 
 	c.database = config.Database
 
@@ -120,6 +124,13 @@ type CommandLine struct {
 	chunked         bool
 	chunkSize       int
 	nodeID          int
+	format          string
+	precision       string
+	timing          bool
+
+	output  io.Writer
+	pending strings.Builder
+	history string
 
 	startTime time.Time
 
@@ -165,8 +176,11 @@ func (c *CommandLine) begin() {
 }
 
 func (c *CommandLine) elapse() {
+	if !c.timing {
+		return
+	}
 	d := time.Since(c.startTime)
-	fmt.Printf("Elapsed: %v\n", d)
+	fmt.Fprintf(c.output, "Elapsed: %v\n", d)
 }
 
 func (c *CommandLine) Execute(s string) error {
@@ -197,7 +211,12 @@ func (c *CommandLine) Execute(s string) error {
 	return err
 }
 
-func (c *CommandLine) executor(s string) {
+func (c *CommandLine) executor(line string) {
+	s, ok := c.appendLine(line)
+	if !ok {
+		return
+	}
+	saveHistory(c.history, s)
 	if err := c.Execute(s); err != nil {
 		fmt.Printf("ERR: %s\n", err)
 	}
@@ -209,6 +228,16 @@ func (c *CommandLine) executeOnLocal(stmt geminiql.Statement) error {
 		return c.executeInsert(stmt)
 	case *geminiql.UseStatement:
 		return c.executeUse(stmt)
+	case *geminiql.FormatStatement:
+		return c.executeFormat(stmt)
+	case *geminiql.ChunkedStatement:
+		return c.executeChunked(stmt)
+	case *geminiql.TimingStatement:
+		return c.executeTiming(stmt)
+	case *geminiql.PrecisionStatement:
+		return c.executePrecision(stmt)
+	case *geminiql.ExportStatement:
+		return c.executeExport(stmt)
 	default:
 		return fmt.Errorf("unsupport stmt %s", stmt)
 	}
@@ -239,11 +268,79 @@ func (c *CommandLine) executeUse(stmt *geminiql.UseStatement) error {
 	return nil
 }
 
+func (c *CommandLine) executeFormat(stmt *geminiql.FormatStatement) error {
+	if !validFormat(stmt.Format) {
+		return fmt.Errorf("unsupported format %s, table, csv, json or vertical", stmt.Format)
+	}
+	c.format = stmt.Format
+	return nil
+}
+
+func (c *CommandLine) executeChunked(stmt *geminiql.ChunkedStatement) error {
+	c.chunked = stmt.Enable
+	c.chunkSize = stmt.Size
+	return nil
+}
+
+func (c *CommandLine) executeTiming(stmt *geminiql.TimingStatement) error {
+	c.timing = stmt.Enable
+	return nil
+}
+
+func (c *CommandLine) executePrecision(stmt *geminiql.PrecisionStatement) error {
+	precision, ok := precisions[stmt.Precision]
+	if !ok {
+		return fmt.Errorf("unsupported precision %s, rfc3339, h, m, s, ms, u or ns", stmt.Precision)
+	}
+	c.precision = stmt.Precision
+	c.config.Precision = precision
+	c.client.SetPrecision(precision)
+	return nil
+}
+
 func (c *CommandLine) executeQuery(query string) error {
+	query, err := c.rewriteQuery(query)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.signalContext()
+	defer cancel()
+
+	if c.chunked {
+		// render each chunk as soon as it arrives
+		return c.client.QueryChunked(ctx, c.clientQuery(query), c.formatResponse)
+	}
+
+	response, err := c.client.QueryContext(ctx, c.clientQuery(query))
+	if err != nil {
+		return err
+	}
+	return c.formatResponse(response)
+}
+
+func (c *CommandLine) formatResponse(response *client.Response) error {
+	if err := response.Error(); err != nil {
+		return err
+	}
+
+	for _, result := range response.Results {
+		for _, m := range result.Messages {
+			fmt.Fprintf(c.output, "%s: %s.\n", m.Level, m.Text)
+		}
+		if err := c.formatResult(result, c.format, c.output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteQuery sets the database and retention policy in use to the sources of the select statements
+func (c *CommandLine) rewriteQuery(query string) (string, error) {
 	if c.retentionPolicy != "" {
 		pq, err := influxql.NewParser(strings.NewReader(query)).ParseQuery()
 		if err != nil {
-			return err
+			return "", err
 		}
 		for _, stmt := range pq.Statements {
 			if selectStmt, ok := stmt.(*influxql.SelectStatement); ok {
@@ -261,13 +358,13 @@ func (c *CommandLine) executeQuery(query string) error {
 		}
 		query = pq.String()
 	}
+	return query, nil
+}
 
-	ctx := context.Background()
-	var cancel context.CancelFunc
-	ctx, cancel = context.WithCancel(ctx)
-
+// signalContext returns a context which is cancelled by an interrupt signal or the returned function
+func (c *CommandLine) signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{})
-	defer close(done)
 
 	go func() {
 		select {
@@ -277,64 +374,10 @@ func (c *CommandLine) executeQuery(query string) error {
 		}
 	}()
 
-	response, err := c.client.QueryContext(ctx, c.clientQuery(query))
-	if err != nil {
-		return err
-	}
-
-	if err := response.Error(); err != nil {
-		return err
-	}
-
-	for _, result := range response.Results {
-		for _, m := range result.Messages {
-			fmt.Printf("%s: %s.\n", m.Level, m.Text)
-		}
-		c.prettyResult(result, os.Stdout)
-	}
-
-	return nil
-}
-
-func (c *CommandLine) prettyResult(result client.Result, w io.Writer) {
-	for _, serie := range result.Series {
-		tags := []string{}
-		for k, v := range serie.Tags {
-			tags = append(tags, fmt.Sprintf("%s=%s", k, v))
-			sort.Strings(tags)
-		}
-
-		if serie.Name != "" {
-			fmt.Fprintf(w, "name: %s\n", serie.Name)
-		}
-		if len(tags) != 0 {
-			fmt.Fprintf(w, "tags: %s\n", strings.Join(tags, ", "))
-		}
-
-		writer := table.NewWriter()
-		writer.SetOutputMirror(w)
-		c.prettyTable(serie, writer)
-		writer.Render()
-		fmt.Println("")
-	}
-}
-
-func (c *CommandLine) prettyTable(serie models.Row, w table.Writer) {
-	columnNames := table.Row{}
-	for _, col := range serie.Columns {
-		columnNames = append(columnNames, col)
-	}
-	w.AppendRow(columnNames)
-	w.AppendSeparator()
-
-	for _, value := range serie.Values {
-		tuple := table.Row{}
-		for _, v := range value {
-			tuple = append(tuple, v)
-		}
-		w.AppendRow(tuple)
+	return ctx, func() {
+		close(done)
+		cancel()
 	}
-	w.SetCaption("%d columns, %d rows in set", len(serie.Columns), len(serie.Values))
 }
 
 func (c *CommandLine) clientQuery(query string) client.Query {
@@ -378,7 +421,9 @@ func (c *CommandLine) Run() error {
 		completer.completer,
 		prompt.OptionTitle("openGemini: interactive openGemini client"),
 		prompt.OptionPrefix(">>> "),
+		prompt.OptionLivePrefix(c.livePrefix),
 		prompt.OptionPrefixTextColor(prompt.DefaultColor),
+		prompt.OptionHistory(loadHistory(c.history)),
 		prompt.OptionCompletionWordSeparator(FilePathCompletionSeparator),
 	)
 	p.Run()
@@ -0,0 +1,230 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geminicli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRows = []string{
+	`[1,"a",1.5,2,"x"]`,
+	`[2,"b",2.5,3,"y \"q\""]`,
+}
+
+type queryTestServer struct {
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func (s *queryTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/ping":
+		w.Header().Set("X-Influxdb-Version", "test")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case "/query":
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	r.ParseForm()
+	s.mu.Lock()
+	s.queries = append(s.queries, r.Form)
+	s.mu.Unlock()
+
+	if strings.HasPrefix(r.Form.Get("q"), "SHOW FIELD KEYS") {
+		fmt.Fprint(w, `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["fieldKey","fieldType"],"values":[["n","integer"],["s","string"],["v","float"]]}]}]}`)
+		return
+	}
+
+	serie := `{"name":"cpu","columns":["time","host","v","n","s"],"values":[%s]}`
+	if r.Form.Get("chunked") != "true" {
+		fmt.Fprintf(w, `{"results":[{"statement_id":0,"series":[`+serie+`]}]}`, strings.Join(testRows, ","))
+		return
+	}
+	for i, row := range testRows {
+		fmt.Fprintf(w, `{"results":[{"statement_id":0,"series":[`+serie+`],"partial":%t}]}`+"\n", row, i < len(testRows)-1)
+	}
+}
+
+func (s *queryTestServer) lastQuery() url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries[len(s.queries)-1]
+}
+
+func newTestCommandLine(t *testing.T) (*CommandLine, *queryTestServer, *bytes.Buffer) {
+	s := &queryTestServer{}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	c, err := CommandLineFactory{}.CreateCommandLine(CommandLineConfig{Host: u.Hostname(), Port: port})
+	require.NoError(t, err)
+	require.NoError(t, c.Connect(""))
+
+	out := &bytes.Buffer{}
+	c.output = out
+	c.history = ""
+	return c, s, out
+}
+
+func TestCommandLine_Format(t *testing.T) {
+	c, _, out := newTestCommandLine(t)
+	require.NoError(t, c.Execute("timing off"))
+
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Contains(t, out.String(), "5 columns, 2 rows in set")
+
+	out.Reset()
+	require.NoError(t, c.Execute("format csv"))
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Equal(t, "name,time,host,v,n,s\ncpu,1,a,1.5,2,x\ncpu,2,b,2.5,3,\"y \"\"q\"\"\"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, c.Execute("format json"))
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Equal(t, `{"series":[{"name":"cpu","columns":["time","host","v","n","s"],"values":[[1,"a",1.5,2,"x"],[2,"b",2.5,3,"y \"q\""]]}]}`+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, c.Execute("format vertical"))
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Contains(t, out.String(), "*************************** 2. row ***************************\ntime: 2\nhost: b\n   v: 2.5\n")
+
+	require.EqualError(t, c.Execute("format xml"), "unsupported format xml, table, csv, json or vertical")
+}
+
+func TestCommandLine_Chunked(t *testing.T) {
+	c, s, out := newTestCommandLine(t)
+	require.NoError(t, c.Execute("timing off"))
+	require.NoError(t, c.Execute("format csv"))
+	require.NoError(t, c.Execute("chunked on 1"))
+
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Equal(t, "true", s.lastQuery().Get("chunked"))
+	require.Equal(t, "1", s.lastQuery().Get("chunk_size"))
+	require.Equal(t, "name,time,host,v,n,s\ncpu,1,a,1.5,2,x\nname,time,host,v,n,s\ncpu,2,b,2.5,3,\"y \"\"q\"\"\"\n", out.String())
+
+	require.NoError(t, c.Execute("chunked off"))
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Equal(t, "", s.lastQuery().Get("chunked"))
+}
+
+func TestCommandLine_TimingAndPrecision(t *testing.T) {
+	c, s, out := newTestCommandLine(t)
+
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Contains(t, out.String(), "Elapsed: ")
+	require.Equal(t, "ns", s.lastQuery().Get("epoch"))
+
+	out.Reset()
+	require.NoError(t, c.Execute("timing off"))
+	require.NoError(t, c.Execute("precision rfc3339"))
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.NotContains(t, out.String(), "Elapsed: ")
+	require.Equal(t, "", s.lastQuery().Get("epoch"))
+
+	require.NoError(t, c.Execute("precision ms"))
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Equal(t, "ms", s.lastQuery().Get("epoch"))
+
+	require.EqualError(t, c.Execute("precision ps"), "unsupported precision ps, rfc3339, h, m, s, ms, u or ns")
+}
+
+func TestCommandLine_Export(t *testing.T) {
+	c, s, out := newTestCommandLine(t)
+	require.NoError(t, c.Execute("timing off"))
+	require.NoError(t, c.Execute("precision rfc3339"))
+
+	file := filepath.Join(t.TempDir(), "cpu.csv")
+	require.NoError(t, c.Execute(fmt.Sprintf("export select * from cpu to '%s'", file)))
+	require.Equal(t, fmt.Sprintf("Exported 2 rows to %s\n", file), out.String())
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, "name,time,host,v,n,s\ncpu,1,a,1.5,2,x\ncpu,2,b,2.5,3,\"y \"\"q\"\"\"\n", string(b))
+
+	file = filepath.Join(t.TempDir(), "cpu.txt")
+	require.NoError(t, c.Execute(fmt.Sprintf("export select * from cpu to '%s' format line", file)))
+	b, err = ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, "cpu,host=a v=1.5,n=2i,s=\"x\" 1\ncpu,host=b v=2.5,n=3i,s=\"y \\\"q\\\"\" 2\n", string(b))
+
+	// the precision is restored after the export
+	require.NoError(t, c.Execute("select * from cpu"))
+	require.Equal(t, "", s.lastQuery().Get("epoch"))
+
+	require.EqualError(t, c.Execute(fmt.Sprintf("export select * from cpu to '%s' format json", file)),
+		"unsupported export format json, csv or line")
+}
+
+func TestCommandLine_MultiLine(t *testing.T) {
+	c := &CommandLine{}
+
+	_, ok := c.appendLine("select * \\")
+	require.False(t, ok)
+	prefix, live := c.livePrefix()
+	require.Equal(t, "... ", prefix)
+	require.True(t, live)
+	_, ok = c.appendLine("from cpu where host = 'a")
+	require.False(t, ok)
+	s, ok := c.appendLine("' and (v > 1")
+	require.False(t, ok)
+	require.Equal(t, "", s)
+	s, ok = c.appendLine("or v < 0)")
+	require.True(t, ok)
+	require.Equal(t, "select * \nfrom cpu where host = 'a\n' and (v > 1\nor v < 0)", s)
+
+	_, live = c.livePrefix()
+	require.False(t, live)
+
+	s, ok = c.appendLine(`insert cpu,host=a s="it's" 1`)
+	require.True(t, ok)
+	require.Equal(t, `insert cpu,host=a s="it's" 1`, s)
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HISTORY_FILE)
+	require.Empty(t, loadHistory(path))
+
+	saveHistory(path, "select *\nfrom cpu")
+	saveHistory(path, "use db")
+	require.Equal(t, []string{"select * from cpu", "use db"}, loadHistory(path))
+
+	for i := 0; i < HISTORY_LIMIT; i++ {
+		saveHistory(path, fmt.Sprintf("show databases %d", i))
+	}
+	history := loadHistory(path)
+	require.Equal(t, HISTORY_LIMIT, len(history))
+	require.Equal(t, "show databases 0", history[0])
+}
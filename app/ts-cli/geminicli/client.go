@@ -0,0 +1,132 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geminicli
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/influxdata/influxdb/client"
+)
+
+// httpClient adds the streaming of chunked responses to the influxdb client,
+// which collects all the chunks of a query before returning them
+type httpClient struct {
+	*client.Client
+
+	config     client.Config
+	httpClient *http.Client
+}
+
+func newHttpClient(c client.Config) (*httpClient, error) {
+	cli, err := client.NewClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := &http.Transport{
+		Proxy:           c.Proxy,
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: c.UnsafeSsl},
+	}
+	if c.UnixSocket != "" {
+		tr.DisableCompression = true
+		tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", c.UnixSocket)
+		}
+	}
+
+	return &httpClient{
+		Client:     cli,
+		config:     c,
+		httpClient: &http.Client{Timeout: c.Timeout, Transport: tr},
+	}, nil
+}
+
+func (c *httpClient) SetPrecision(precision string) {
+	c.config.Precision = precision
+	c.Client.SetPrecision(precision)
+}
+
+// QueryChunked sends a chunked query and calls fn with each chunk as soon as it arrives
+func (c *httpClient) QueryChunked(ctx context.Context, q client.Query, fn func(*client.Response) error) error {
+	u := c.config.URL
+	u.Path = path.Join(u.Path, "query")
+
+	values := url.Values{}
+	values.Set("q", q.Command)
+	values.Set("db", q.Database)
+	if q.RetentionPolicy != "" {
+		values.Set("rp", q.RetentionPolicy)
+	}
+	values.Set("chunked", "true")
+	if q.ChunkSize > 0 {
+		values.Set("chunk_size", strconv.Itoa(q.ChunkSize))
+	}
+	if q.NodeID > 0 {
+		values.Set("node_id", strconv.Itoa(q.NodeID))
+	}
+	if c.config.Precision != "" {
+		values.Set("epoch", c.config.Precision)
+	}
+	u.RawQuery = values.Encode()
+
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.config.UserAgent != "" {
+		req.Header.Set("User-Agent", c.config.UserAgent)
+	}
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	cr := client.NewChunkedResponse(resp.Body)
+	for {
+		r, err := cr.NextResponse()
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			break
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+		if err := r.Error(); err != nil {
+			return err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received status code %d from server", resp.StatusCode)
+	}
+	return nil
+}
@@ -26,6 +26,11 @@ var cmds = []prompt.Suggest{
 	{Text: "use", Description: "use <db>.[<rp>], specify the database and retention policy to be used"},
 	{Text: "insert", Description: "insert datapoint to openGemini."},
 	{Text: "select", Description: "query dataset from openGemini."},
+	{Text: "format", Description: "format <table|csv|json|vertical>, specify the output format of query results."},
+	{Text: "chunked", Description: "chunked <on|off> [<size>], specify whether query results are returned and shown in chunks."},
+	{Text: "timing", Description: "timing <on|off>, specify whether the elapsed time of statements is shown."},
+	{Text: "precision", Description: "precision <rfc3339|h|m|s|ms|u|ns>, specify the precision of timestamps."},
+	{Text: "export", Description: "export <query> to '<file>' [format <csv|line>], write query results to a file."},
 }
 
 var subcmds = map[string][]prompt.Suggest{
@@ -33,6 +38,29 @@ var subcmds = map[string][]prompt.Suggest{
 		{Text: "into", Description: "into [<db>.<cp>], specify the database and retention policy to be written to."},
 		{Text: "line protocol", Description: "<measurement>[,<tag_key>=<tag_value>[,<tag_key>=<tag_value>]] <field_key>=<field_value>[,<field_key>=<field_value>] [<time_stamp>]"},
 	},
+	"format": {
+		{Text: "table"},
+		{Text: "csv"},
+		{Text: "json"},
+		{Text: "vertical"},
+	},
+	"chunked": {
+		{Text: "on"},
+		{Text: "off"},
+	},
+	"timing": {
+		{Text: "on"},
+		{Text: "off"},
+	},
+	"precision": {
+		{Text: "rfc3339"},
+		{Text: "h"},
+		{Text: "m"},
+		{Text: "s"},
+		{Text: "ms"},
+		{Text: "u"},
+		{Text: "ns"},
+	},
 	"select": {
 		{Text: "from", Description: "from [<db>.<rp>].<measurement>, specify the datasource of query."},
 		{Text: "where", Description: "where <expression>[ <predicate> <expression>], specify the predicate expression of query."},
@@ -0,0 +1,322 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geminicli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/client"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/escape"
+	"github.com/openGemini/openGemini/app/ts-cli/geminiql"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+)
+
+const (
+	EXPORT_CSV  = "csv"
+	EXPORT_LINE = "line"
+)
+
+// seriesExporter writes the rows of the series of a query and returns the number of rows written
+type seriesExporter interface {
+	export(serie models.Row) (int, error)
+	flush() error
+}
+
+func (c *CommandLine) executeExport(stmt *geminiql.ExportStatement) error {
+	format := stmt.Format
+	if format == "" {
+		format = EXPORT_CSV
+	}
+	if format != EXPORT_CSV && format != EXPORT_LINE {
+		return fmt.Errorf("unsupported export format %s, csv or line", format)
+	}
+
+	query, err := c.rewriteQuery(stmt.Query)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(stmt.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, cancel := c.signalContext()
+	defer cancel()
+
+	var exporter seriesExporter
+	if format == EXPORT_LINE {
+		// line protocol needs the timestamps in nanoseconds
+		c.client.SetPrecision("ns")
+		defer c.client.SetPrecision(precisions[c.precision])
+		exporter = newLineExporter(f, func(mst string) (map[string]string, error) {
+			return c.fieldTypes(ctx, mst)
+		})
+	} else {
+		exporter = newCsvExporter(f)
+	}
+
+	q := c.clientQuery(query)
+	q.Chunked = true
+	rows := 0
+	err = c.client.QueryChunked(ctx, q, func(response *client.Response) error {
+		for _, result := range response.Results {
+			for _, serie := range result.Series {
+				n, err := exporter.export(serie)
+				rows += n
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := exporter.flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.output, "Exported %d rows to %s\n", rows, stmt.File)
+	return nil
+}
+
+// fieldTypes returns the types of the fields of the measurement in the current database
+func (c *CommandLine) fieldTypes(ctx context.Context, mst string) (map[string]string, error) {
+	response, err := c.client.QueryContext(ctx, client.Query{
+		Command:         fmt.Sprintf("SHOW FIELD KEYS FROM %s", influxql.QuoteIdent(mst)),
+		Database:        c.database,
+		RetentionPolicy: c.retentionPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := response.Error(); err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]string)
+	for _, result := range response.Results {
+		for _, serie := range result.Series {
+			for _, value := range serie.Values {
+				if len(value) < 2 {
+					continue
+				}
+				key, ok1 := value[0].(string)
+				typ, ok2 := value[1].(string)
+				if ok1 && ok2 {
+					types[key] = typ
+				}
+			}
+		}
+	}
+	return types, nil
+}
+
+type csvExporter struct {
+	w      *csv.Writer
+	header []string
+}
+
+func newCsvExporter(w io.Writer) *csvExporter {
+	return &csvExporter{w: csv.NewWriter(w)}
+}
+
+// export writes the tags of the series as columns, the header is written again when it changes
+func (e *csvExporter) export(serie models.Row) (int, error) {
+	keys := make([]string, 0, len(serie.Tags))
+	for k := range serie.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	header := make([]string, 0, 1+len(keys)+len(serie.Columns))
+	header = append(header, "name")
+	header = append(header, keys...)
+	header = append(header, serie.Columns...)
+	if !stringsEqual(header, e.header) {
+		if err := e.w.Write(header); err != nil {
+			return 0, err
+		}
+		e.header = header
+	}
+
+	record := make([]string, len(header))
+	for _, value := range serie.Values {
+		record = record[:0]
+		record = append(record, serie.Name)
+		for _, k := range keys {
+			record = append(record, serie.Tags[k])
+		}
+		for _, v := range value {
+			record = append(record, formatValue(v))
+		}
+		if err := e.w.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	return len(serie.Values), e.w.Error()
+}
+
+func (e *csvExporter) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type lineExporter struct {
+	w          *bufio.Writer
+	fieldTypes func(mst string) (map[string]string, error)
+	types      map[string]map[string]string
+	buf        []byte
+}
+
+func newLineExporter(w io.Writer, fieldTypes func(mst string) (map[string]string, error)) *lineExporter {
+	return &lineExporter{
+		w:          bufio.NewWriter(w),
+		fieldTypes: fieldTypes,
+		types:      make(map[string]map[string]string),
+	}
+}
+
+// export writes the rows of the series as line protocol. The columns which are fields of
+// the measurement are written as fields, the other string columns are taken as tags, and
+// the other columns, such as the results of functions, as fields typed by their values.
+func (e *lineExporter) export(serie models.Row) (int, error) {
+	timeIdx := -1
+	for i, col := range serie.Columns {
+		if col == "time" {
+			timeIdx = i
+			break
+		}
+	}
+	if timeIdx < 0 {
+		return 0, fmt.Errorf("export %s as line protocol without time column", serie.Name)
+	}
+
+	types, ok := e.types[serie.Name]
+	if !ok {
+		var err error
+		if types, err = e.fieldTypes(serie.Name); err != nil {
+			return 0, err
+		}
+		e.types[serie.Name] = types
+	}
+
+	rows := 0
+	for _, value := range serie.Values {
+		if timeIdx >= len(value) || value[timeIdx] == nil {
+			continue
+		}
+
+		tags := make(map[string]string, len(serie.Tags))
+		for k, v := range serie.Tags {
+			if v != "" {
+				tags[k] = v
+			}
+		}
+		var fields []string
+		for i, v := range value {
+			if i == timeIdx || i >= len(serie.Columns) || v == nil {
+				continue
+			}
+			col := serie.Columns[i]
+			typ, isField := types[col]
+			if s, ok := v.(string); ok && !isField {
+				if s != "" {
+					tags[col] = s
+				}
+				continue
+			}
+			fields = append(fields, escape.String(col)+"="+lineFieldValue(v, typ))
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		e.buf = append(e.buf[:0], models.EscapeMeasurement([]byte(serie.Name))...)
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			e.buf = append(e.buf, ',')
+			e.buf = append(e.buf, escape.String(k)...)
+			e.buf = append(e.buf, '=')
+			e.buf = append(e.buf, escape.String(tags[k])...)
+		}
+		e.buf = append(e.buf, ' ')
+		e.buf = append(e.buf, strings.Join(fields, ",")...)
+		e.buf = append(e.buf, ' ')
+		e.buf = append(e.buf, formatValue(value[timeIdx])...)
+		e.buf = append(e.buf, '\n')
+		if _, err := e.w.Write(e.buf); err != nil {
+			return rows, err
+		}
+		rows++
+	}
+	return rows, nil
+}
+
+func (e *lineExporter) flush() error {
+	return e.w.Flush()
+}
+
+// lineFieldValue formats the value of a field by the type of the field, the
+// numbers of unknown fields are written as floats
+func lineFieldValue(v interface{}, typ string) string {
+	switch typ {
+	case "integer":
+		return fmt.Sprintf("%vi", v)
+	case "unsigned":
+		return fmt.Sprintf("%vu", v)
+	case "string":
+		return `"` + models.EscapeStringField(fmt.Sprintf("%v", v)) + `"`
+	}
+
+	switch v := v.(type) {
+	case string:
+		return `"` + models.EscapeStringField(v) + `"`
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
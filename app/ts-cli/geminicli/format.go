@@ -0,0 +1,199 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geminicli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/client"
+	"github.com/influxdata/influxdb/models"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+const (
+	FORMAT_TABLE    = "table"
+	FORMAT_CSV      = "csv"
+	FORMAT_JSON     = "json"
+	FORMAT_VERTICAL = "vertical"
+)
+
+const (
+	PRECISION_RFC3339 = "rfc3339"
+)
+
+var precisions = map[string]string{
+	PRECISION_RFC3339: "",
+	"h":               "h",
+	"m":               "m",
+	"s":               "s",
+	"ms":              "ms",
+	"u":               "u",
+	"ns":              "ns",
+}
+
+func validFormat(format string) bool {
+	switch format {
+	case FORMAT_TABLE, FORMAT_CSV, FORMAT_JSON, FORMAT_VERTICAL:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatResult writes the series of the result to w in the given format
+func (c *CommandLine) formatResult(result client.Result, format string, w io.Writer) error {
+	switch format {
+	case FORMAT_CSV:
+		return c.csvResult(result, w)
+	case FORMAT_JSON:
+		return c.jsonResult(result, w)
+	case FORMAT_VERTICAL:
+		c.verticalResult(result, w)
+	default:
+		c.prettyResult(result, w)
+	}
+	return nil
+}
+
+func (c *CommandLine) csvResult(result client.Result, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	for i, serie := range result.Series {
+		if i > 0 {
+			writer.Write([]string{})
+		}
+		tags := sortedTags(serie)
+		header := make([]string, 0, len(serie.Columns)+2)
+		header = append(header, "name")
+		if len(tags) != 0 {
+			header = append(header, "tags")
+		}
+		header = append(header, serie.Columns...)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+
+		for _, value := range serie.Values {
+			record := make([]string, 0, len(header))
+			record = append(record, serie.Name)
+			if len(tags) != 0 {
+				record = append(record, strings.Join(tags, ","))
+			}
+			for _, v := range value {
+				record = append(record, formatValue(v))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonResult writes the result as one json line, so that the chunks of a query can be parsed one by one
+func (c *CommandLine) jsonResult(result client.Result, w io.Writer) error {
+	b, err := json.Marshal(&result)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+func (c *CommandLine) verticalResult(result client.Result, w io.Writer) {
+	row := 0
+	for _, serie := range result.Series {
+		c.serieHeader(serie, w)
+
+		width := 0
+		for _, col := range serie.Columns {
+			if len(col) > width {
+				width = len(col)
+			}
+		}
+		for _, value := range serie.Values {
+			row++
+			fmt.Fprintf(w, "*************************** %d. row ***************************\n", row)
+			for i, v := range value {
+				if i < len(serie.Columns) {
+					fmt.Fprintf(w, "%*s: %s\n", width, serie.Columns[i], formatValue(v))
+				}
+			}
+		}
+		fmt.Fprintf(w, "%d columns, %d rows in set\n\n", len(serie.Columns), len(serie.Values))
+	}
+}
+
+func (c *CommandLine) prettyResult(result client.Result, w io.Writer) {
+	for _, serie := range result.Series {
+		c.serieHeader(serie, w)
+
+		writer := table.NewWriter()
+		writer.SetOutputMirror(w)
+		c.prettyTable(serie, writer)
+		writer.Render()
+		fmt.Fprintln(w, "")
+	}
+}
+
+func (c *CommandLine) serieHeader(serie models.Row, w io.Writer) {
+	if serie.Name != "" {
+		fmt.Fprintf(w, "name: %s\n", serie.Name)
+	}
+	if tags := sortedTags(serie); len(tags) != 0 {
+		fmt.Fprintf(w, "tags: %s\n", strings.Join(tags, ", "))
+	}
+}
+
+func (c *CommandLine) prettyTable(serie models.Row, w table.Writer) {
+	columnNames := table.Row{}
+	for _, col := range serie.Columns {
+		columnNames = append(columnNames, col)
+	}
+	w.AppendRow(columnNames)
+	w.AppendSeparator()
+
+	for _, value := range serie.Values {
+		tuple := table.Row{}
+		for _, v := range value {
+			tuple = append(tuple, v)
+		}
+		w.AppendRow(tuple)
+	}
+	w.SetCaption("%d columns, %d rows in set", len(serie.Columns), len(serie.Values))
+}
+
+func sortedTags(serie models.Row) []string {
+	tags := make([]string, 0, len(serie.Tags))
+	for k, v := range serie.Tags {
+		tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geminicli
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	HISTORY_FILE  = ".ts_cli_history"
+	HISTORY_LIMIT = 1000
+)
+
+// statementComplete returns false when a quote or a parenthesis of the statement is not closed
+func statementComplete(s string) bool {
+	var quote rune
+	escaped := false
+	depth := 0
+	for _, ch := range s {
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch {
+		case ch == '\\':
+			escaped = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		}
+	}
+	return quote == 0 && depth <= 0
+}
+
+// appendLine adds a line to the pending statement and returns the statement once it is complete,
+// a line ending with a backslash is continued on the next line
+func (c *CommandLine) appendLine(line string) (string, bool) {
+	trimmed := strings.TrimRight(line, " \t")
+	continued := strings.HasSuffix(trimmed, "\\")
+	if continued {
+		line = strings.TrimSuffix(trimmed, "\\")
+	}
+	if c.pending.Len() > 0 {
+		c.pending.WriteString("\n")
+	}
+	c.pending.WriteString(line)
+
+	s := c.pending.String()
+	if continued || !statementComplete(s) {
+		return "", false
+	}
+	c.pending.Reset()
+	return strings.TrimSpace(s), true
+}
+
+func (c *CommandLine) livePrefix() (string, bool) {
+	if c.pending.Len() > 0 {
+		return "... ", true
+	}
+	return "", false
+}
+
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, HISTORY_FILE)
+}
+
+// loadHistory returns the last HISTORY_LIMIT statements of the history file
+func loadHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var history []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			history = append(history, line)
+		}
+	}
+	if len(history) > HISTORY_LIMIT {
+		history = history[len(history)-HISTORY_LIMIT:]
+	}
+	return history
+}
+
+// saveHistory appends the statement to the history file, the lines of a multi-line statement are joined
+func saveHistory(path string, s string) {
+	if path == "" || s == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.WriteString(strings.Join(strings.Fields(s), " ") + "\n")
+}
@@ -66,3 +66,41 @@ type SetStatement struct {
 }
 
 func (s *SetStatement) stmt() {}
+
+// FormatStatement sets the output format of query results: table, csv, json or vertical
+type FormatStatement struct {
+	Format string
+}
+
+func (s *FormatStatement) stmt() {}
+
+// ChunkedStatement sets whether the query results are returned in chunks, Size 0 means the server default
+type ChunkedStatement struct {
+	Enable bool
+	Size   int
+}
+
+func (s *ChunkedStatement) stmt() {}
+
+// TimingStatement sets whether the elapsed time of statements is shown
+type TimingStatement struct {
+	Enable bool
+}
+
+func (s *TimingStatement) stmt() {}
+
+// PrecisionStatement sets the precision of the timestamps: rfc3339, h, m, s, ms, u or ns
+type PrecisionStatement struct {
+	Precision string
+}
+
+func (s *PrecisionStatement) stmt() {}
+
+// ExportStatement writes the results of Query to File, as csv or line protocol
+type ExportStatement struct {
+	Query  string
+	File   string
+	Format string
+}
+
+func (s *ExportStatement) stmt() {}
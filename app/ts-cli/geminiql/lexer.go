@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -39,6 +40,9 @@ const (
 	ILLEGAL_TOKEN
 )
 
+// exportTargetRe splits an export statement after its query, at the last TO followed by a string
+var exportTargetRe = regexp.MustCompile(`(?is)^(.*\S)(\s+to\s+['"].*)$`)
+
 func isChar(ch rune) bool {
 	return unicode.IsLetter(ch) || ch == '_'
 }
@@ -94,6 +98,9 @@ func (t *Tokenizer) Scan() (tok int, val string) {
 	if t.firstToken() == INSERT && t.lastToken() == EQ {
 		return t.scanRaw()
 	}
+	if t.firstToken() == EXPORT && t.lastToken() == EXPORT {
+		return t.scanExportQuery()
+	}
 
 	ch := t.Lookahead()
 
@@ -146,10 +153,15 @@ func (t *Tokenizer) scanIdentifier() (int, string) {
 }
 
 func (t *Tokenizer) scanKeywords(s string) int {
-	if tok, ok := t.keywords[strings.ToUpper(s)]; ok {
-		return tok
+	tok, ok := t.keywords[strings.ToUpper(s)]
+	if !ok {
+		return IDENT
+	}
+	// the keys of the line protocol of an insert statement are not keywords
+	if t.firstToken() == INSERT && tok != INTO {
+		return IDENT
 	}
-	return IDENT
+	return tok
 }
 
 func (t *Tokenizer) scanRaw() (int, string) {
@@ -167,6 +179,22 @@ func (t *Tokenizer) scanRaw() (int, string) {
 	}
 }
 
+// scanExportQuery scans the query of an export statement as a raw token, the query is
+// written in influxql which is not known by the tokenizer. The rest is scanned as usual.
+func (t *Tokenizer) scanExportQuery() (int, string) {
+	var buf bytes.Buffer
+	for ch := t.read(); ch != EOF; ch = t.read() {
+		buf.WriteRune(ch)
+	}
+
+	m := exportTargetRe.FindStringSubmatch(buf.String())
+	if m == nil {
+		return BAD_RAW, strings.TrimSpace(buf.String())
+	}
+	t.r = bufio.NewReader(strings.NewReader(m[2]))
+	return RAW, strings.TrimSpace(m[1])
+}
+
 func (t *Tokenizer) scanString() (int, string) {
 	end := t.read()
 
@@ -293,6 +321,8 @@ func (l *QLLexerImpl) Lex(lval *QLSymType) int {
 			l.Error(fmt.Sprintf("bad string %s", val))
 		case BAD_ESCAPE:
 			l.Error(fmt.Sprintf("bad escape %s", val))
+		case BAD_RAW:
+			l.Error(fmt.Sprintf("bad raw %s", val))
 		case INTEGER:
 			var err error
 			lval.integer, err = strconv.ParseInt(val, 10, 64)
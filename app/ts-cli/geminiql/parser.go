@@ -20,15 +20,28 @@ limitations under the License.
 package geminiql
 
 import (
+	"fmt"
 	__yyfmt__ "fmt"
 	"strconv"
+	"strings"
 )
 
 func updateStmt(QLlex interface{}, stmt Statement) {
 	QLlex.(*QLLexerImpl).UpdateStmt(stmt)
 }
 
-//line parser.y:32
+func parseSwitch(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "true":
+		return true, nil
+	case "off", "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s must be on or off", s)
+	}
+}
+
+//line parser.y:45
 type QLSymType struct {
 	yys      int
 	stmts    []Statement
@@ -39,20 +52,27 @@ type QLSymType struct {
 	decimal  float64
 	pair     Pair
 	pairs    Pairs
+	boolean  bool
 }
 
 const INSERT = 57346
 const INTO = 57347
 const USE = 57348
 const SET = 57349
-const DOT = 57350
-const COMMA = 57351
-const EQ = 57352
-const IDENT = 57353
-const INTEGER = 57354
-const DECIMAL = 57355
-const STRING = 57356
-const RAW = 57357
+const FORMAT = 57350
+const CHUNKED = 57351
+const TIMING = 57352
+const PRECISION = 57353
+const EXPORT = 57354
+const TO = 57355
+const DOT = 57356
+const COMMA = 57357
+const EQ = 57358
+const IDENT = 57359
+const INTEGER = 57360
+const DECIMAL = 57361
+const STRING = 57362
+const RAW = 57363
 
 var QLToknames = [...]string{
 	"$end",
@@ -62,6 +82,12 @@ var QLToknames = [...]string{
 	"INTO",
 	"USE",
 	"SET",
+	"FORMAT",
+	"CHUNKED",
+	"TIMING",
+	"PRECISION",
+	"EXPORT",
+	"TO",
 	"DOT",
 	"COMMA",
 	"EQ",
@@ -78,69 +104,79 @@ const QLEofCode = 1
 const QLErrCode = 2
 const QLInitialStackSize = 16
 
-//line parser.y:215
+//line parser.y:317
 
 //line yacctab:1
 var QLExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 20,
-	11, 9,
-	-2, 23,
+	-1, 36,
+	17, 22,
+	-2, 36,
 }
 
 const QLPrivate = 57344
 
-const QLLast = 42
+const QLLast = 63
 
 var QLAct = [...]int8{
-	28, 15, 9, 13, 33, 35, 36, 34, 22, 41,
-	8, 19, 18, 30, 17, 14, 12, 12, 20, 39,
-	26, 27, 38, 25, 23, 24, 16, 32, 31, 37,
-	5, 21, 6, 7, 29, 11, 10, 4, 3, 40,
-	2, 1,
+	46, 25, 19, 23, 51, 53, 54, 52, 43, 61,
+	33, 55, 10, 38, 11, 12, 13, 14, 15, 16,
+	17, 35, 34, 18, 62, 29, 48, 27, 24, 22,
+	36, 32, 30, 28, 57, 22, 58, 45, 42, 41,
+	39, 31, 40, 50, 49, 44, 59, 56, 26, 37,
+	47, 21, 20, 9, 8, 7, 6, 5, 60, 4,
+	3, 2, 1,
 }
 
 var QLPact = [...]int16{
-	26, -1000, -1000, -1000, -1000, 5, 4, 3, 7, -1000,
-	-4, 15, -1000, -1000, 17, -1000, 14, 10, 6, -1000,
-	17, -1000, -1000, 2, 4, 3, -7, -1000, 2, 13,
-	9, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 2, -6,
-	-1000, -1000,
+	8, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	18, 11, 10, 16, 15, 15, 14, -11, 13, -1000,
+	-5, 25, -1000, -1000, 28, -1000, 24, 22, -1000, -10,
+	-1000, -1000, -1000, 32, 12, -1000, 28, -1000, -1000, 9,
+	11, 10, -13, -1000, -9, -1000, 9, 19, 20, -1000,
+	-1000, -1000, -1000, -1000, -1000, 38, -1000, 9, -12, 7,
+	-1000, -1000, -1000,
 }
 
 var QLPgo = [...]int8{
-	0, 41, 40, 38, 37, 2, 36, 35, 34, 0,
-	31, 3, 26, 1,
+	0, 62, 61, 60, 59, 57, 56, 55, 54, 53,
+	2, 52, 51, 50, 0, 49, 3, 48, 1, 25,
 }
 
 var QLR1 = [...]int8{
-	0, 1, 1, 1, 4, 3, 2, 2, 2, 11,
-	11, 5, 5, 6, 12, 12, 12, 12, 13, 13,
-	9, 9, 8, 7, 10,
+	0, 1, 1, 1, 1, 1, 1, 1, 1, 5,
+	6, 6, 7, 8, 9, 9, 19, 4, 3, 2,
+	2, 2, 16, 16, 10, 10, 11, 17, 17, 17,
+	17, 18, 18, 14, 14, 13, 12, 15,
 }
 
 var QLR2 = [...]int8{
-	0, 1, 1, 1, 2, 2, 4, 3, 2, 1,
-	3, 1, 2, 4, 3, 3, 3, 3, 1, 3,
-	1, 3, 3, 1, 1,
+	0, 1, 1, 1, 1, 1, 1, 1, 1, 2,
+	2, 3, 2, 2, 4, 6, 1, 2, 2, 4,
+	3, 2, 1, 3, 1, 2, 4, 3, 3, 3,
+	3, 1, 3, 1, 3, 3, 1, 1,
 }
 
 var QLChk = [...]int16{
-	-1000, -1, -2, -3, -4, 4, 6, 7, 5, -5,
-	-6, -7, 11, -11, 11, -13, -12, 11, -11, -5,
-	11, -10, 12, 9, 8, 9, 10, -5, -9, -8,
-	11, -11, -13, 11, 14, 12, 13, -9, 9, 10,
-	-9, 15,
+	-1000, -1, -2, -3, -4, -5, -6, -7, -8, -9,
+	4, 6, 7, 8, 9, 10, 11, 12, 5, -10,
+	-11, -12, 17, -16, 17, -18, -17, 17, 17, -19,
+	17, -19, 17, 21, -16, -10, 17, -15, 18, 15,
+	14, 15, 16, 18, 13, -10, -14, -13, 17, -16,
+	-18, 17, 20, 18, 19, 20, -14, 15, 16, 8,
+	-14, 21, 17,
 }
 
 var QLDef = [...]int8{
-	0, -2, 1, 2, 3, 0, 0, 0, 0, 8,
-	11, 0, 23, 5, 9, 4, 18, 0, 0, 7,
-	-2, 12, 24, 0, 0, 0, 0, 6, 0, 20,
-	0, 10, 19, 14, 15, 16, 17, 13, 0, 0,
-	21, 22,
+	0, -2, 1, 2, 3, 4, 5, 6, 7, 8,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 21,
+	24, 0, 36, 18, 22, 17, 31, 0, 9, 10,
+	16, 12, 13, 0, 0, 20, -2, 25, 37, 0,
+	0, 0, 0, 11, 0, 19, 0, 33, 0, 23,
+	32, 27, 28, 29, 30, 14, 26, 0, 0, 0,
+	34, 35, 15,
 }
 
 var QLTok1 = [...]int8{
@@ -149,7 +185,7 @@ var QLTok1 = [...]int8{
 
 var QLTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
-	12, 13, 14, 15,
+	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 }
 
 var QLTok3 = [...]int8{
@@ -495,33 +531,133 @@ QLdefault:
 
 	case 1:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:66
+//line parser.y:82
 		{
 			updateStmt(QLlex, QLDollar[1].stmt)
 		}
 	case 2:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:70
+//line parser.y:86
 		{
 			updateStmt(QLlex, QLDollar[1].stmt)
 		}
 	case 3:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:74
+//line parser.y:90
 		{
 			updateStmt(QLlex, QLDollar[1].stmt)
 		}
 	case 4:
+		QLDollar = QLS[QLpt-1 : QLpt+1]
+//line parser.y:94
+		{
+			updateStmt(QLlex, QLDollar[1].stmt)
+		}
+	case 5:
+		QLDollar = QLS[QLpt-1 : QLpt+1]
+//line parser.y:98
+		{
+			updateStmt(QLlex, QLDollar[1].stmt)
+		}
+	case 6:
+		QLDollar = QLS[QLpt-1 : QLpt+1]
+//line parser.y:102
+		{
+			updateStmt(QLlex, QLDollar[1].stmt)
+		}
+	case 7:
+		QLDollar = QLS[QLpt-1 : QLpt+1]
+//line parser.y:106
+		{
+			updateStmt(QLlex, QLDollar[1].stmt)
+		}
+	case 8:
+		QLDollar = QLS[QLpt-1 : QLpt+1]
+//line parser.y:110
+		{
+			updateStmt(QLlex, QLDollar[1].stmt)
+		}
+	case 9:
+		QLDollar = QLS[QLpt-2 : QLpt+1]
+//line parser.y:116
+		{
+			stmt := &FormatStatement{}
+			stmt.Format = strings.ToLower(QLDollar[2].str)
+			QLVAL.stmt = stmt
+		}
+	case 10:
+		QLDollar = QLS[QLpt-2 : QLpt+1]
+//line parser.y:124
+		{
+			stmt := &ChunkedStatement{}
+			stmt.Enable = QLDollar[2].boolean
+			QLVAL.stmt = stmt
+		}
+	case 11:
+		QLDollar = QLS[QLpt-3 : QLpt+1]
+//line parser.y:130
+		{
+			stmt := &ChunkedStatement{}
+			stmt.Enable = QLDollar[2].boolean
+			stmt.Size = int(QLDollar[3].integer)
+			QLVAL.stmt = stmt
+		}
+	case 12:
+		QLDollar = QLS[QLpt-2 : QLpt+1]
+//line parser.y:139
+		{
+			stmt := &TimingStatement{}
+			stmt.Enable = QLDollar[2].boolean
+			QLVAL.stmt = stmt
+		}
+	case 13:
+		QLDollar = QLS[QLpt-2 : QLpt+1]
+//line parser.y:147
+		{
+			stmt := &PrecisionStatement{}
+			stmt.Precision = strings.ToLower(QLDollar[2].str)
+			QLVAL.stmt = stmt
+		}
+	case 14:
+		QLDollar = QLS[QLpt-4 : QLpt+1]
+//line parser.y:155
+		{
+			stmt := &ExportStatement{}
+			stmt.Query = QLDollar[2].str
+			stmt.File = QLDollar[4].str
+			QLVAL.stmt = stmt
+		}
+	case 15:
+		QLDollar = QLS[QLpt-6 : QLpt+1]
+//line parser.y:162
+		{
+			stmt := &ExportStatement{}
+			stmt.Query = QLDollar[2].str
+			stmt.File = QLDollar[4].str
+			stmt.Format = strings.ToLower(QLDollar[6].str)
+			QLVAL.stmt = stmt
+		}
+	case 16:
+		QLDollar = QLS[QLpt-1 : QLpt+1]
+//line parser.y:172
+		{
+			on, err := parseSwitch(QLDollar[1].str)
+			if err != nil {
+				QLlex.Error(err.Error())
+			}
+			QLVAL.boolean = on
+		}
+	case 17:
 		QLDollar = QLS[QLpt-2 : QLpt+1]
-//line parser.y:80
+//line parser.y:182
 		{
 			stmt := &SetStatement{}
 			stmt.KVS = QLDollar[2].pairs
 			QLVAL.stmt = stmt
 		}
-	case 5:
+	case 18:
 		QLDollar = QLS[QLpt-2 : QLpt+1]
-//line parser.y:88
+//line parser.y:190
 		{
 			stmt := &UseStatement{}
 			if len(QLDollar[2].strslice) == 1 {
@@ -535,9 +671,9 @@ QLdefault:
 				QLlex.Error("namespace must be <db>.<rp>")
 			}
 		}
-	case 6:
+	case 19:
 		QLDollar = QLS[QLpt-4 : QLpt+1]
-//line parser.y:104
+//line parser.y:206
 		{
 			stmt := &InsertStatement{}
 			stmt.LineProtocol = QLDollar[4].str
@@ -550,120 +686,120 @@ QLdefault:
 				QLVAL.stmt = stmt
 			}
 		}
-	case 7:
+	case 20:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:117
+//line parser.y:219
 		{
 			stmt := &InsertStatement{}
 			stmt.LineProtocol = QLDollar[3].str
 			QLVAL.stmt = stmt
 		}
-	case 8:
+	case 21:
 		QLDollar = QLS[QLpt-2 : QLpt+1]
-//line parser.y:123
+//line parser.y:225
 		{
 			stmt := &InsertStatement{}
 			stmt.LineProtocol = QLDollar[2].str
 			QLVAL.stmt = stmt
 		}
-	case 9:
+	case 22:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:131
+//line parser.y:233
 		{
 			QLVAL.strslice = []string{QLDollar[1].str}
 		}
-	case 10:
+	case 23:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:135
+//line parser.y:237
 		{
 			ns := []string{QLDollar[1].str}
 			QLVAL.strslice = append(ns, QLDollar[3].strslice...)
 		}
-	case 11:
+	case 24:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:142
+//line parser.y:244
 		{
 			QLVAL.str = QLDollar[1].str
 		}
-	case 12:
+	case 25:
 		QLDollar = QLS[QLpt-2 : QLpt+1]
-//line parser.y:146
+//line parser.y:248
 		{
 			QLVAL.str = QLDollar[1].str + " " + QLDollar[2].str
 		}
-	case 13:
+	case 26:
 		QLDollar = QLS[QLpt-4 : QLpt+1]
-//line parser.y:152
+//line parser.y:254
 		{
 			QLVAL.str = QLDollar[1].str + QLDollar[2].str + QLDollar[3].str + " " + QLDollar[4].str
 		}
-	case 14:
+	case 27:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:158
+//line parser.y:260
 		{
 			p := NewPair(QLDollar[1].str, QLDollar[3].str)
 			QLVAL.pair = *p
 		}
-	case 15:
+	case 28:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:163
+//line parser.y:265
 		{
 			p := NewPair(QLDollar[1].str, QLDollar[3].str)
 			QLVAL.pair = *p
 		}
-	case 16:
+	case 29:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:168
+//line parser.y:270
 		{
 			p := NewPair(QLDollar[1].str, QLDollar[3].integer)
 			QLVAL.pair = *p
 		}
-	case 17:
+	case 30:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:173
+//line parser.y:275
 		{
 			p := NewPair(QLDollar[1].str, QLDollar[3].decimal)
 			QLVAL.pair = *p
 		}
-	case 18:
+	case 31:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:180
+//line parser.y:282
 		{
 			QLVAL.pairs = Pairs{QLDollar[1].pair}
 		}
-	case 19:
+	case 32:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:184
+//line parser.y:286
 		{
 			QLVAL.pairs = append(QLDollar[3].pairs, QLDollar[1].pair)
 		}
-	case 20:
+	case 33:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:190
+//line parser.y:292
 		{
 			QLVAL.str = QLDollar[1].str
 		}
-	case 21:
+	case 34:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:194
+//line parser.y:296
 		{
 			QLVAL.str = QLDollar[1].str + QLDollar[2].str + QLDollar[3].str
 		}
-	case 22:
+	case 35:
 		QLDollar = QLS[QLpt-3 : QLpt+1]
-//line parser.y:200
+//line parser.y:302
 		{
 			QLVAL.str = QLDollar[1].str + QLDollar[2].str + QLDollar[3].str
 		}
-	case 23:
+	case 36:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:206
+//line parser.y:308
 		{
 			QLVAL.str = QLDollar[1].str
 		}
-	case 24:
+	case 37:
 		QLDollar = QLS[QLpt-1 : QLpt+1]
-//line parser.y:212
+//line parser.y:314
 		{
 			QLVAL.str = strconv.FormatInt(QLDollar[1].integer, 10)
 		}
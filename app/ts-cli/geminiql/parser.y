@@ -18,13 +18,26 @@ limitations under the License.
 package geminiql
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 func updateStmt(QLlex interface{}, stmt Statement) {
     QLlex.(*QLLexerImpl).UpdateStmt(stmt)
 }
 
+func parseSwitch(s string) (bool, error) {
+    switch strings.ToLower(s) {
+    case "on", "true":
+        return true, nil
+    case "off", "false":
+        return false, nil
+    default:
+        return false, fmt.Errorf("%s must be on or off", s)
+    }
+}
+
 %}
 
 // fields inside this union end up as the fields in a structure known
@@ -38,19 +51,22 @@ func updateStmt(QLlex interface{}, stmt Statement) {
     decimal float64
     pair Pair
     pairs Pairs
+    boolean bool
 }
 
 // any non-terminal which returns a value needs a type, which is
 // really a field name in the above union struct
 %type <stmts> STATEMENTS
 %type <stmt> INSERT_STATEMENT USE_STATEMENT SET_STATEMENT
+%type <stmt> FORMAT_STATEMENT CHUNKED_STATEMENT TIMING_STATEMENT PRECISION_STATEMENT EXPORT_STATEMENT
 %type <str> LINE_PROTOCOL TIME_SERIE MEASUREMENT KV_RAW KV_RAWS TIME
 %type <strslice> NAMESPACE
 %type <pair> KEY_VALUE
 %type <pairs> KEY_VALUES
+%type <boolean> SWITCH
 
 // same for terminals
-%token <str> INSERT INTO USE SET
+%token <str> INSERT INTO USE SET FORMAT CHUNKED TIMING PRECISION EXPORT TO
 %token <str> DOT COMMA
 %token <str> EQ
 %token <str> IDENT
@@ -74,6 +90,92 @@ STATEMENTS:
     {
         updateStmt(QLlex, $1)
     }
+    |FORMAT_STATEMENT
+    {
+        updateStmt(QLlex, $1)
+    }
+    |CHUNKED_STATEMENT
+    {
+        updateStmt(QLlex, $1)
+    }
+    |TIMING_STATEMENT
+    {
+        updateStmt(QLlex, $1)
+    }
+    |PRECISION_STATEMENT
+    {
+        updateStmt(QLlex, $1)
+    }
+    |EXPORT_STATEMENT
+    {
+        updateStmt(QLlex, $1)
+    }
+
+FORMAT_STATEMENT:
+    FORMAT IDENT
+    {
+        stmt := &FormatStatement{}
+        stmt.Format = strings.ToLower($2)
+        $$ = stmt
+    }
+
+CHUNKED_STATEMENT:
+    CHUNKED SWITCH
+    {
+        stmt := &ChunkedStatement{}
+        stmt.Enable = $2
+        $$ = stmt
+    }
+    |CHUNKED SWITCH INTEGER
+    {
+        stmt := &ChunkedStatement{}
+        stmt.Enable = $2
+        stmt.Size = int($3)
+        $$ = stmt
+    }
+
+TIMING_STATEMENT:
+    TIMING SWITCH
+    {
+        stmt := &TimingStatement{}
+        stmt.Enable = $2
+        $$ = stmt
+    }
+
+PRECISION_STATEMENT:
+    PRECISION IDENT
+    {
+        stmt := &PrecisionStatement{}
+        stmt.Precision = strings.ToLower($2)
+        $$ = stmt
+    }
+
+EXPORT_STATEMENT:
+    EXPORT RAW TO STRING
+    {
+        stmt := &ExportStatement{}
+        stmt.Query = $2
+        stmt.File = $4
+        $$ = stmt
+    }
+    |EXPORT RAW TO STRING FORMAT IDENT
+    {
+        stmt := &ExportStatement{}
+        stmt.Query = $2
+        stmt.File = $4
+        stmt.Format = strings.ToLower($6)
+        $$ = stmt
+    }
+
+SWITCH:
+    IDENT
+    {
+        on, err := parseSwitch($1)
+        if err != nil {
+            QLlex.Error(err.Error())
+        }
+        $$ = on
+    }
 
 SET_STATEMENT:
     SET KEY_VALUES
@@ -128,6 +128,68 @@ func TestParser(t *testing.T) {
 				RP: "",
 			},
 		},
+		{
+			name: "insert with keyword as key",
+			cmd:  "insert format,to=a timing=1,export=2",
+			expect: &InsertStatement{
+				DB:           "",
+				RP:           "",
+				LineProtocol: "format,to=a timing=1,export=2",
+			},
+		},
+		{
+			name: "format",
+			cmd:  "format CSV",
+			expect: &FormatStatement{
+				Format: "csv",
+			},
+		},
+		{
+			name: "chunked on",
+			cmd:  "chunked on",
+			expect: &ChunkedStatement{
+				Enable: true,
+			},
+		},
+		{
+			name: "chunked with size",
+			cmd:  "chunked on 1000",
+			expect: &ChunkedStatement{
+				Enable: true,
+				Size:   1000,
+			},
+		},
+		{
+			name: "timing off",
+			cmd:  "timing off",
+			expect: &TimingStatement{
+				Enable: false,
+			},
+		},
+		{
+			name: "precision",
+			cmd:  "precision RFC3339",
+			expect: &PrecisionStatement{
+				Precision: "rfc3339",
+			},
+		},
+		{
+			name: "export",
+			cmd:  "export select * from cpu where host = 'a' to '/tmp/cpu.csv'",
+			expect: &ExportStatement{
+				Query: "select * from cpu where host = 'a'",
+				File:  "/tmp/cpu.csv",
+			},
+		},
+		{
+			name: "export with format",
+			cmd:  "export select * from \"to\" to \"/tmp/to.txt\" format line",
+			expect: &ExportStatement{
+				Query:  "select * from \"to\"",
+				File:   "/tmp/to.txt",
+				Format: "line",
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			ast := &QLAst{}
@@ -138,3 +200,19 @@ func TestParser(t *testing.T) {
 		})
 	}
 }
+
+func TestParserError(t *testing.T) {
+	p := QLNewParser()
+	for _, cmd := range []string{
+		"chunked maybe",
+		"timing",
+		"export select * from cpu",
+	} {
+		t.Run(cmd, func(t *testing.T) {
+			ast := &QLAst{}
+			l := QLNewLexer(NewTokenizer(strings.NewReader(cmd)), ast)
+			p.Parse(l)
+			assert.Equal(t, ast.Error != nil, true)
+		})
+	}
+}
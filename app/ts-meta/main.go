@@ -99,6 +99,7 @@ func doRun(args ...string) error {
 				}
 
 				mainCmd.Server = s
+				mainCmd.WatchConfigReload(func() config.Config { return config.NewTSMeta() })
 				mainCmd.Logger.Info("Meta:RunE")
 				return nil
 			},
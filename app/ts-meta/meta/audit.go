@@ -0,0 +1,190 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/openGemini/openGemini/lib/audit"
+	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
+)
+
+// auditCommand records a meta command requested by a client in the audit log. It is called once, on
+// the request path of the leader, the commands replayed from the raft log are not recorded again.
+// The internal commands, such as the shard reports and the heartbeats, are not recorded.
+func auditCommand(cmd *proto2.Command, err error) {
+	user, object, ok := commandSubject(cmd)
+	if !ok {
+		return
+	}
+	audit.Log(&audit.Event{
+		Type:      audit.MetaCommand,
+		User:      user,
+		Object:    object,
+		Statement: cmd.GetType().String(),
+		Err:       err,
+	})
+}
+
+// commandSubject returns the user and the object affected by a command, ok is false if the command is
+// not recorded by the audit log.
+func commandSubject(cmd *proto2.Command) (user string, object string, ok bool) {
+	desc := commandExtension(cmd.GetType())
+	if desc == nil {
+		return "", "", false
+	}
+	ext, err := proto.GetExtension(cmd, desc)
+	if err != nil {
+		return "", "", true
+	}
+
+	switch v := ext.(type) {
+	case *proto2.CreateDatabaseCommand:
+		return "", v.GetName(), true
+	case *proto2.DropDatabaseCommand:
+		return "", v.GetName(), true
+	case *proto2.MarkDatabaseDeleteCommand:
+		return "", v.GetName(), true
+	case *proto2.CreateRetentionPolicyCommand:
+		return "", rpObject(v.GetDatabase(), v.GetRetentionPolicy().GetName()), true
+	case *proto2.DropRetentionPolicyCommand:
+		return "", rpObject(v.GetDatabase(), v.GetName()), true
+	case *proto2.SetDefaultRetentionPolicyCommand:
+		return "", rpObject(v.GetDatabase(), v.GetName()), true
+	case *proto2.UpdateRetentionPolicyCommand:
+		return "", rpObject(v.GetDatabase(), v.GetName()), true
+	case *proto2.MarkRetentionPolicyDeleteCommand:
+		return "", rpObject(v.GetDatabase(), v.GetName()), true
+	case *proto2.CreateMeasurementCommand:
+		return "", mstObject(v.GetDBName(), v.GetRpName(), v.GetName()), true
+	case *proto2.AlterShardKeyCmd:
+		return "", mstObject(v.GetDBName(), v.GetRpName(), v.GetName()), true
+	case *proto2.MarkMeasurementDeleteCommand:
+		return "", mstObject(v.GetDatabase(), v.GetPolicy(), v.GetMeasurement()), true
+	case *proto2.DropMeasurementCommand:
+		return "", mstObject(v.GetDatabase(), v.GetPolicy(), v.GetMeasurement()), true
+	case *proto2.CreateSubscriptionCommand:
+		return "", mstObject(v.GetDatabase(), v.GetRetentionPolicy(), v.GetName()), true
+	case *proto2.DropSubscriptionCommand:
+		return "", mstObject(v.GetDatabase(), v.GetRetentionPolicy(), v.GetName()), true
+	case *proto2.DropShardCommand:
+		return "", fmt.Sprintf("shard %d", v.GetID()), true
+	case *proto2.CreateUserCommand:
+		return v.GetName(), "", true
+	case *proto2.DropUserCommand:
+		return v.GetName(), "", true
+	case *proto2.UpdateUserCommand:
+		return v.GetName(), "", true
+	case *proto2.SetPrivilegeCommand:
+		return v.GetUsername(), v.GetDatabase(), true
+	case *proto2.SetAdminPrivilegeCommand:
+		return v.GetUsername(), "", true
+	case *proto2.CreateRoleCommand:
+		return "", v.GetName(), true
+	case *proto2.DropRoleCommand:
+		return "", v.GetName(), true
+	case *proto2.SetRolePrivilegeCommand:
+		return "", v.GetRole(), true
+	case *proto2.SetUserRoleCommand:
+		return v.GetUsername(), v.GetRole(), true
+	case *proto2.CreateTokenCommand:
+		return v.GetToken().GetUser(), v.GetToken().GetName(), true
+	case *proto2.DropTokenCommand:
+		return "", v.GetName(), true
+	case *proto2.DeleteDataNodeCommand:
+		return "", fmt.Sprintf("node %d", v.GetID()), true
+	case *proto2.DeleteMetaNodeCommand:
+		return "", fmt.Sprintf("node %d", v.GetID()), true
+	case *proto2.DecommissionDataNodeCommand:
+		return "", fmt.Sprintf("node %d", v.GetID()), true
+	}
+	return "", "", true
+}
+
+// commandExtension returns the extension of the commands recorded by the audit log.
+func commandExtension(ty proto2.Command_Type) *proto.ExtensionDesc {
+	switch ty {
+	case proto2.Command_CreateDatabaseCommand:
+		return proto2.E_CreateDatabaseCommand_Command
+	case proto2.Command_DropDatabaseCommand:
+		return proto2.E_DropDatabaseCommand_Command
+	case proto2.Command_MarkDatabaseDeleteCommand:
+		return proto2.E_MarkDatabaseDeleteCommand_Command
+	case proto2.Command_CreateRetentionPolicyCommand:
+		return proto2.E_CreateRetentionPolicyCommand_Command
+	case proto2.Command_DropRetentionPolicyCommand:
+		return proto2.E_DropRetentionPolicyCommand_Command
+	case proto2.Command_SetDefaultRetentionPolicyCommand:
+		return proto2.E_SetDefaultRetentionPolicyCommand_Command
+	case proto2.Command_UpdateRetentionPolicyCommand:
+		return proto2.E_UpdateRetentionPolicyCommand_Command
+	case proto2.Command_MarkRetentionPolicyDeleteCommand:
+		return proto2.E_MarkRetentionPolicyDeleteCommand_Command
+	case proto2.Command_CreateMeasurementCommand:
+		return proto2.E_CreateMeasurementCommand_Command
+	case proto2.Command_AlterShardKeyCmd:
+		return proto2.E_AlterShardKeyCmd_Command
+	case proto2.Command_MarkMeasurementDeleteCommand:
+		return proto2.E_MarkMeasurementDeleteCommand_Command
+	case proto2.Command_DropMeasurementCommand:
+		return proto2.E_DropMeasurementCommand_Command
+	case proto2.Command_CreateSubscriptionCommand:
+		return proto2.E_CreateSubscriptionCommand_Command
+	case proto2.Command_DropSubscriptionCommand:
+		return proto2.E_DropSubscriptionCommand_Command
+	case proto2.Command_DropShardCommand:
+		return proto2.E_DropShardCommand_Command
+	case proto2.Command_CreateUserCommand:
+		return proto2.E_CreateUserCommand_Command
+	case proto2.Command_DropUserCommand:
+		return proto2.E_DropUserCommand_Command
+	case proto2.Command_UpdateUserCommand:
+		return proto2.E_UpdateUserCommand_Command
+	case proto2.Command_SetPrivilegeCommand:
+		return proto2.E_SetPrivilegeCommand_Command
+	case proto2.Command_SetAdminPrivilegeCommand:
+		return proto2.E_SetAdminPrivilegeCommand_Command
+	case proto2.Command_CreateRoleCommand:
+		return proto2.E_CreateRoleCommand_Command
+	case proto2.Command_DropRoleCommand:
+		return proto2.E_DropRoleCommand_Command
+	case proto2.Command_SetRolePrivilegeCommand:
+		return proto2.E_SetRolePrivilegeCommand_Command
+	case proto2.Command_SetUserRoleCommand:
+		return proto2.E_SetUserRoleCommand_Command
+	case proto2.Command_CreateTokenCommand:
+		return proto2.E_CreateTokenCommand_Command
+	case proto2.Command_DropTokenCommand:
+		return proto2.E_DropTokenCommand_Command
+	case proto2.Command_DeleteDataNodeCommand:
+		return proto2.E_DeleteDataNodeCommand_Command
+	case proto2.Command_DeleteMetaNodeCommand:
+		return proto2.E_DeleteMetaNodeCommand_Command
+	case proto2.Command_DecommissionDataNodeCommand:
+		return proto2.E_DecommissionDataNodeCommand_Command
+	}
+	return nil
+}
+
+func rpObject(db, rp string) string {
+	return db + "." + rp
+}
+
+func mstObject(db, rp, name string) string {
+	return db + "." + rp + "." + name
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/open_src/github.com/hashicorp/serf/serf"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"go.uber.org/zap"
+)
+
+type ptMove struct {
+	db   string
+	ptId uint32
+	src  uint64
+	dst  uint64
+}
+
+type nodeLoad struct {
+	id    uint64
+	load  uint64
+	dbPts map[string]int
+}
+
+// ptWeight is the load of a pt, the rows reported for its newest shards. An unreported pt
+// weighs 1, so the pt number is balanced before the stores report their loads.
+func ptWeight(ptLoads map[string]map[uint32]uint64, db string, ptId uint32) uint64 {
+	return ptLoads[db][ptId] + 1
+}
+
+// planPtMoves plans the moves which drain the decommissioning nodes, and if balance is true,
+// the moves which even the loads of the other alive nodes. The load of a node is the sum of
+// the weights of its pts. Only online pts owned by alive nodes are moved, pts of failed nodes
+// are taken over by failover.
+func planPtMoves(dataNodes meta.DataNodeInfos, ptView map[string]meta.DBPtInfos, ptLoads map[string]map[uint32]uint64,
+	balance bool, imbalanceFactor float64) []ptMove {
+	loads := make(map[uint64]*nodeLoad, len(dataNodes))
+	draining := make(map[uint64]bool)
+	for i := range dataNodes {
+		if dataNodes[i].Status != serf.StatusAlive {
+			continue
+		}
+		if dataNodes[i].Decommissioning {
+			draining[dataNodes[i].ID] = true
+			continue
+		}
+		loads[dataNodes[i].ID] = &nodeLoad{id: dataNodes[i].ID, dbPts: make(map[string]int)}
+	}
+	if len(loads) == 0 {
+		return nil
+	}
+
+	dbs := make([]string, 0, len(ptView))
+	for db := range ptView {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+
+	var drains []ptMove
+	for _, db := range dbs {
+		for _, pt := range ptView[db] {
+			if l, ok := loads[pt.Owner.NodeID]; ok {
+				l.load += ptWeight(ptLoads, db, pt.PtId)
+				l.dbPts[db]++
+				continue
+			}
+			if draining[pt.Owner.NodeID] && pt.Status == meta.Online {
+				drains = append(drains, ptMove{db: db, ptId: pt.PtId, src: pt.Owner.NodeID})
+			}
+		}
+	}
+
+	// the heaviest pts are placed first
+	sort.SliceStable(drains, func(i, j int) bool {
+		return ptWeight(ptLoads, drains[i].db, drains[i].ptId) > ptWeight(ptLoads, drains[j].db, drains[j].ptId)
+	})
+	moves := make([]ptMove, 0, len(drains))
+	for _, m := range drains {
+		dst := lightestNode(loads, m.db)
+		m.dst = dst.id
+		dst.load += ptWeight(ptLoads, m.db, m.ptId)
+		dst.dbPts[m.db]++
+		moves = append(moves, m)
+	}
+
+	if !balance || len(loads) < 2 {
+		return moves
+	}
+
+	var total uint64
+	for _, l := range loads {
+		total += l.load
+	}
+	limit := float64(total) / float64(len(loads)) * (1 + imbalanceFactor)
+	for {
+		heavy, light := heaviestNode(loads), lightestNode(loads, "")
+		if float64(heavy.load) <= limit {
+			break
+		}
+		db, ptId, ok := pickPtToMove(ptView, ptLoads, dbs, heavy, light, moves)
+		if !ok {
+			break
+		}
+		w := ptWeight(ptLoads, db, ptId)
+		heavy.load -= w
+		heavy.dbPts[db]--
+		light.load += w
+		light.dbPts[db]++
+		moves = append(moves, ptMove{db: db, ptId: ptId, src: heavy.id, dst: light.id})
+	}
+	return moves
+}
+
+// lightestNode prefers the node with the least load, then the fewest pts of db
+func lightestNode(loads map[uint64]*nodeLoad, db string) *nodeLoad {
+	var res *nodeLoad
+	for _, l := range loads {
+		if res == nil || l.load < res.load ||
+			(l.load == res.load && (l.dbPts[db] < res.dbPts[db] || (l.dbPts[db] == res.dbPts[db] && l.id < res.id))) {
+			res = l
+		}
+	}
+	return res
+}
+
+func heaviestNode(loads map[uint64]*nodeLoad) *nodeLoad {
+	var res *nodeLoad
+	for _, l := range loads {
+		if res == nil || l.load > res.load || (l.load == res.load && l.id < res.id) {
+			res = l
+		}
+	}
+	return res
+}
+
+// pickPtToMove picks the online pt of heavy which makes the loads of heavy and light the closest,
+// then the pt of the db which is the most unevenly spread between them. The pt must be
+// lighter than the load difference, so every move lowers the imbalance.
+func pickPtToMove(ptView map[string]meta.DBPtInfos, ptLoads map[string]map[uint32]uint64, dbs []string,
+	heavy, light *nodeLoad, planned []ptMove) (string, uint32, bool) {
+	moved := make(map[string]map[uint32]bool)
+	for _, m := range planned {
+		if moved[m.db] == nil {
+			moved[m.db] = make(map[uint32]bool)
+		}
+		moved[m.db][m.ptId] = true
+	}
+
+	diff := heavy.load - light.load
+	bestDb, bestPt, found := "", uint32(0), false
+	var bestGap uint64
+	bestSpread := 0
+	for _, db := range dbs {
+		spread := heavy.dbPts[db] - light.dbPts[db]
+		for _, pt := range ptView[db] {
+			if pt.Owner.NodeID != heavy.id || pt.Status != meta.Online || moved[db][pt.PtId] {
+				continue
+			}
+			w := ptWeight(ptLoads, db, pt.PtId)
+			if w >= diff {
+				continue
+			}
+			gap := diff - 2*w
+			if 2*w > diff {
+				gap = 2*w - diff
+			}
+			if !found || gap < bestGap || (gap == bestGap && spread > bestSpread) {
+				bestDb, bestPt, bestGap, bestSpread, found = db, pt.PtId, gap, spread, true
+			}
+		}
+	}
+	return bestDb, bestPt, found
+}
+
+func (cm *ClusterManager) setBalanceConfig(c *config.Meta) {
+	cm.balanceEnabled = c.PtBalanceEnabled
+	cm.balanceInterval = time.Duration(c.PtBalanceInterval)
+	cm.imbalanceFactor = c.ImbalanceFactor
+}
+
+func (cm *ClusterManager) checkBalance(stop, reOpen chan struct{}) {
+	defer cm.wg.Done()
+	ticker := time.NewTicker(cm.balanceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cm.closing:
+			return
+		case <-stop:
+			return
+		case <-reOpen:
+			return
+		case <-ticker.C:
+			cm.balanceOnce(stop)
+		}
+	}
+}
+
+// balanceOnce executes the planned moves one by one and deletes the drained decommissioning nodes
+func (cm *ClusterManager) balanceOnce(stop chan struct{}) {
+	log := logger.NewLogger(errno.ModuleHA)
+	if cm.store.hasMigrateEvents() {
+		cm.recoverMoves(stop)
+		return
+	}
+
+	dataNodes := cm.store.dataNodes()
+	if globalService != nil && globalService.msm.canExecute() {
+		moves := planPtMoves(cm.movableNodes(dataNodes), cm.store.dbPtView(), cm.store.ptLoads(), cm.balanceEnabled, cm.imbalanceFactor)
+		for _, m := range moves {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			pt := &meta.DbPtInfo{Db: m.db, Pti: &meta.PtInfo{PtId: m.ptId, Status: meta.Online, Owner: meta.PtOwner{NodeID: m.src}}}
+			log.Info("start to move pt", zap.String("db pt", pt.String()), zap.Uint64("src", m.src), zap.Uint64("dst", m.dst))
+			if err := globalService.msm.executeEvent(NewMoveEvent(pt, m.src, m.dst, false)); err != nil {
+				log.Error("move pt failed", zap.String("db pt", pt.String()), zap.Error(err))
+				return
+			}
+		}
+	}
+
+	ptView := cm.store.dbPtView()
+	for i := range dataNodes {
+		if !dataNodes[i].Decommissioning || ownsPt(ptView, dataNodes[i].ID) {
+			continue
+		}
+		if err := cm.store.deleteDataNode(dataNodes[i].ID); err != nil {
+			log.Error("delete decommissioned data node failed", zap.Uint64("id", dataNodes[i].ID), zap.Error(err))
+			continue
+		}
+		log.Info("decommissioned data node is deleted", zap.Uint64("id", dataNodes[i].ID))
+	}
+}
+
+// recoverMoves finishes the moves interrupted by a change of the meta leader, the balancing is
+// resumed once no move is left
+func (cm *ClusterManager) recoverMoves(stop chan struct{}) {
+	if globalService == nil || !globalService.msm.canExecute() {
+		return
+	}
+	log := logger.NewLogger(errno.ModuleHA)
+	for _, info := range cm.store.migrateEvents() {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if EventType(info.GetEventType()) != Move {
+			continue
+		}
+		e := recoverMoveEvent(info)
+		globalService.store.refreshDbPt(e.pt)
+		log.Info("recover pt move", zap.String("db pt", e.pt.String()), zap.String("state", e.curState.String()))
+		if err := globalService.msm.executeEvent(e); err != nil {
+			log.Error("recover pt move failed", zap.String("db pt", e.pt.String()), zap.Error(err))
+		}
+	}
+}
+
+// setNodeEngine records the engine tag of a store node, the pts of the tssp2 engine can not be
+// moved. The stores older than the engine tag run the tssp1 engine.
+func (cm *ClusterManager) setNodeEngine(name, engine string) {
+	id, err := strconv.ParseUint(name, 10, 64)
+	if err != nil {
+		return
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if engine == config.EngineType2 {
+		cm.pinnedNodes[id] = true
+	} else {
+		delete(cm.pinnedNodes, id)
+	}
+}
+
+// movableNodes returns the data nodes whose pts can be moved. The pts of the other nodes are
+// neither drained nor balanced, and no pt is moved to them, so a decommissioning node running
+// the tssp2 engine is never deleted.
+func (cm *ClusterManager) movableNodes(dataNodes meta.DataNodeInfos) meta.DataNodeInfos {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	if len(cm.pinnedNodes) == 0 {
+		return dataNodes
+	}
+	nodes := make(meta.DataNodeInfos, 0, len(dataNodes))
+	for i := range dataNodes {
+		if !cm.pinnedNodes[dataNodes[i].ID] {
+			nodes = append(nodes, dataNodes[i])
+		}
+	}
+	return nodes
+}
+
+func ownsPt(ptView map[string]meta.DBPtInfos, id uint64) bool {
+	for db := range ptView {
+		for i := range ptView[db] {
+			if ptView[db][i].Owner.NodeID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
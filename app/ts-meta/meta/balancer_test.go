@@ -0,0 +1,140 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/openGemini/openGemini/open_src/github.com/hashicorp/serf/serf"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDataNode(id uint64, status serf.MemberStatus, decommissioning bool) meta.DataNode {
+	return meta.DataNode{NodeInfo: meta.NodeInfo{ID: id, Status: status}, Decommissioning: decommissioning}
+}
+
+func newTestPtView(owners ...uint64) meta.DBPtInfos {
+	pts := make(meta.DBPtInfos, len(owners))
+	for i, o := range owners {
+		pts[i] = meta.PtInfo{PtId: uint32(i), Owner: meta.PtOwner{NodeID: o}, Status: meta.Online}
+	}
+	return pts
+}
+
+func TestPlanPtMoves_Decommission(t *testing.T) {
+	nodes := meta.DataNodeInfos{
+		newTestDataNode(1, serf.StatusAlive, false),
+		newTestDataNode(2, serf.StatusAlive, false),
+		newTestDataNode(3, serf.StatusAlive, true),
+	}
+	ptView := map[string]meta.DBPtInfos{"db0": newTestPtView(1, 2, 3, 3)}
+	moves := planPtMoves(nodes, ptView, nil, false, 0.3)
+	assert.Equal(t, []ptMove{{db: "db0", ptId: 2, src: 3, dst: 1}, {db: "db0", ptId: 3, src: 3, dst: 2}}, moves)
+
+	// pts of a failed decommissioning node are taken over by failover
+	nodes[2].Status = serf.StatusFailed
+	assert.Empty(t, planPtMoves(nodes, ptView, nil, false, 0.3))
+
+	// no node can receive pts
+	nodes = meta.DataNodeInfos{newTestDataNode(3, serf.StatusAlive, true)}
+	assert.Empty(t, planPtMoves(nodes, ptView, nil, true, 0.3))
+}
+
+func TestPlanPtMoves_Balance(t *testing.T) {
+	nodes := meta.DataNodeInfos{
+		newTestDataNode(1, serf.StatusAlive, false),
+		newTestDataNode(2, serf.StatusAlive, false),
+		newTestDataNode(3, serf.StatusAlive, false),
+	}
+	ptView := map[string]meta.DBPtInfos{
+		"db0": newTestPtView(1, 1, 1, 2),
+		"db1": newTestPtView(1, 1, 2, 2),
+	}
+	assert.Empty(t, planPtMoves(nodes, ptView, nil, false, 0.3))
+
+	moves := planPtMoves(nodes, ptView, nil, true, 0.3)
+	load := map[uint64]int{}
+	for _, pts := range ptView {
+		for _, pt := range pts {
+			load[pt.Owner.NodeID]++
+		}
+	}
+	for _, m := range moves {
+		assert.NotEqual(t, m.src, m.dst)
+		load[m.src]--
+		load[m.dst]++
+	}
+	assert.Equal(t, map[uint64]int{1: 3, 2: 3, 3: 2}, load)
+	assert.Equal(t, "db0", moves[0].db)
+
+	// offline pts are never moved
+	for i := range ptView["db0"] {
+		ptView["db0"][i].Status = meta.Offline
+	}
+	ptView["db1"] = newTestPtView(1, 2, 3, 3)
+	moves = planPtMoves(nodes, ptView, nil, true, 0.3)
+	assert.Equal(t, []ptMove{{db: "db1", ptId: 0, src: 1, dst: 2}}, moves)
+}
+
+func TestPlanPtMoves_BalanceByLoad(t *testing.T) {
+	nodes := meta.DataNodeInfos{
+		newTestDataNode(1, serf.StatusAlive, false),
+		newTestDataNode(2, serf.StatusAlive, false),
+	}
+	ptView := map[string]meta.DBPtInfos{"db0": newTestPtView(1, 1, 2, 2)}
+	// the pt numbers are even
+	assert.Empty(t, planPtMoves(nodes, ptView, nil, true, 0.3))
+
+	loads := map[string]map[uint32]uint64{"db0": {0: 100, 1: 100, 2: 10, 3: 10}}
+	assert.Equal(t, []ptMove{{db: "db0", ptId: 0, src: 1, dst: 2}}, planPtMoves(nodes, ptView, loads, true, 0.3))
+
+	// a pt heavier than the load difference is not moved
+	ptView = map[string]meta.DBPtInfos{"db0": newTestPtView(1, 2)}
+	loads = map[string]map[uint32]uint64{"db0": {0: 1000, 1: 400}}
+	assert.Empty(t, planPtMoves(nodes, ptView, loads, true, 0.3))
+
+	// the heaviest pts of a decommissioning node are placed first
+	nodes = append(nodes, newTestDataNode(3, serf.StatusAlive, true))
+	ptView = map[string]meta.DBPtInfos{"db0": newTestPtView(1, 2, 3, 3)}
+	loads = map[string]map[uint32]uint64{"db0": {0: 50, 1: 10, 2: 5, 3: 100}}
+	assert.Equal(t, []ptMove{{db: "db0", ptId: 3, src: 3, dst: 2}, {db: "db0", ptId: 2, src: 3, dst: 1}},
+		planPtMoves(nodes, ptView, loads, false, 0.3))
+}
+
+func TestPlanPtMoves_PinnedNodes(t *testing.T) {
+	cm := &ClusterManager{pinnedNodes: make(map[uint64]bool)}
+	nodes := meta.DataNodeInfos{
+		newTestDataNode(1, serf.StatusAlive, false),
+		newTestDataNode(2, serf.StatusAlive, false),
+		newTestDataNode(3, serf.StatusAlive, true),
+		newTestDataNode(4, serf.StatusAlive, false),
+	}
+	ptView := map[string]meta.DBPtInfos{"db0": newTestPtView(1, 1, 1, 1, 3)}
+	cm.setNodeEngine("2", "tssp2")
+	cm.setNodeEngine("3", "tssp2")
+	cm.setNodeEngine("4", "tssp1")
+	cm.setNodeEngine("invalid", "tssp2")
+
+	// the pts of the tssp2 nodes are not drained and no pt is moved to them
+	moves := planPtMoves(cm.movableNodes(nodes), ptView, nil, true, 0.3)
+	assert.Equal(t, []ptMove{{db: "db0", ptId: 0, src: 1, dst: 4}, {db: "db0", ptId: 1, src: 1, dst: 4}}, moves)
+
+	cm.setNodeEngine("3", "tssp1")
+	moves = planPtMoves(cm.movableNodes(nodes), ptView, nil, false, 0.3)
+	assert.Equal(t, []ptMove{{db: "db0", ptId: 4, src: 3, dst: 4}}, moves)
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/raft"
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/lib/logger"
 	"github.com/openGemini/openGemini/open_src/github.com/hashicorp/serf/serf"
@@ -32,6 +33,11 @@ import (
 type storeInterface interface {
 	updateNodeStatus(id uint64, status int32, lTime uint64, gossipAddr string) error
 	dataNodes() meta.DataNodeInfos
+	dbPtView() map[string]meta.DBPtInfos
+	ptLoads() map[string]map[uint32]uint64
+	migrateEvents() map[string]*meta.MigrateEventInfo
+	hasMigrateEvents() bool
+	deleteDataNode(id uint64) error
 }
 
 type ClusterManager struct {
@@ -47,16 +53,23 @@ type ClusterManager struct {
 	stop         chan struct{}                // used for meta leader step down and do not process any event
 	eventWg      sync.WaitGroup
 	memberIds    map[uint64]struct{} // alive members
+	pinnedNodes  map[uint64]bool     // store nodes whose engine can not move pts
+
+	balanceEnabled  bool
+	balanceInterval time.Duration
+	imbalanceFactor float64
 }
 
 func NewClusterManager(store storeInterface) *ClusterManager {
 	c := &ClusterManager{
-		store:    store,
-		reOpen:   make(chan struct{}),
-		closing:  make(chan struct{}),
-		eventCh:  make(chan serf.Event, 1024),
-		eventMap: make(map[string]*serf.MemberEvent),
-		stop:     make(chan struct{})}
+		store:       store,
+		reOpen:      make(chan struct{}),
+		closing:     make(chan struct{}),
+		eventCh:     make(chan serf.Event, 1024),
+		eventMap:    make(map[string]*serf.MemberEvent),
+		pinnedNodes: make(map[uint64]bool),
+		stop:        make(chan struct{})}
+	c.setBalanceConfig(config.NewMeta())
 
 	c.handlerMap = map[serf.EventType]memberEventHandler{
 		serf.EventMemberJoin:   &joinHandler{baseHandler{c}},
@@ -98,6 +111,8 @@ func (cm *ClusterManager) Start() {
 	cm.wg.Add(1)
 	go cm.checkEvents()
 	cm.stop = make(chan struct{})
+	cm.wg.Add(1)
+	go cm.checkBalance(cm.stop, cm.reOpen)
 }
 
 func (cm *ClusterManager) Stop() {
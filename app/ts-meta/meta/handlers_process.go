@@ -137,9 +137,9 @@ func (h *Execute) Process() (transport.Codec, error) {
 	}
 
 	body := h.req.Body
-	var err error
 	// Make sure it's a valid command.
-	if _, err = validateCommand(body); err != nil {
+	cmd, err := validateCommand(body)
+	if err != nil {
 		rsp.Err = err.Error()
 		return rsp, nil
 	}
@@ -149,11 +149,14 @@ func (h *Execute) Process() (transport.Codec, error) {
 		// We aren't the leader
 		if errno.Equal(err, errno.MetaIsNotLeader) || errno.Equal(err, errno.RaftIsNotOpen) {
 			rsp.Err = err.Error()
+		} else {
+			auditCommand(cmd, err)
 		}
 		// Error wasn't a leadership error so pass it back to client.
 		rsp.ErrCommand = err.Error()
 		return rsp, nil
 	} else {
+		auditCommand(cmd, nil)
 		// Apply was successful. Return the new store index to the client.
 		rsp.Index = h.store.index()
 	}
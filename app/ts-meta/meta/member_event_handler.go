@@ -41,6 +41,9 @@ func (bh *baseHandler) handleEvent(m *serf.Member, e *serf.MemberEvent) error {
 	}
 
 	bh.cm.addEventMap(m.Name, e)
+	if engine, ok := m.Tags["engine"]; ok {
+		bh.cm.setNodeEngine(m.Name, engine)
+	}
 	logger.NewLogger(errno.ModuleHA).Error("handle event", zap.String("eventType", e.String()), zap.String("addr", m.Addr.String()),
 		zap.String("name", m.Name), zap.Int("status", int(m.Status)))
 	if bh.cm.isStopped() {
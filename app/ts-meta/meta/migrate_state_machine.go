@@ -16,9 +16,83 @@ limitations under the License.
 
 package meta
 
+import (
+	"time"
+
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"go.uber.org/zap"
+)
+
+const migrateRetryInterval = 100 * time.Millisecond
+
+// MigrateCmdSender delivers the command of the current state of an event to the store node
+// returned by getTarget and waits for the result
+type MigrateCmdSender func(e MigrateEvent) error
+
 type MigrateStateMachine struct {
+	sender MigrateCmdSender
+	logger *logger.Logger
+}
+
+func NewMigrateStateMachine() *MigrateStateMachine {
+	return &MigrateStateMachine{
+		logger: logger.NewLogger(errno.ModuleHA),
+	}
+}
+
+func (m *MigrateStateMachine) SetCmdSender(sender MigrateCmdSender) {
+	m.sender = sender
+}
+
+func (m *MigrateStateMachine) canExecute() bool {
+	return m != nil && m.sender != nil
 }
 
 func (m *MigrateStateMachine) sendMigrateCommand(e MigrateEvent) (NextAction, error) {
 	return ActionWait, nil
 }
+
+// executeEvent drives the event until it is finished, the returned error is the result of the event
+func (m *MigrateStateMachine) executeEvent(e MigrateEvent) error {
+	if !m.canExecute() {
+		return errno.NewError(errno.StateMachineIsNotRunning)
+	}
+
+	retries := 0
+	for {
+		action, err := e.getNextAction()
+		if err != nil {
+			if errno.Equal(err, errno.EventIsInterrupted) || errno.Equal(err, errno.MetaIsNotLeader) {
+				m.logger.Error("execute event failed", zap.String("event", e.getEventId()), zap.Error(err))
+				return err
+			}
+			if retries >= maxRetryNum {
+				// the event would block the following ones
+				m.logger.Error("execute event failed", zap.String("event", e.getEventId()), zap.Error(err))
+				e.removeEventFromStore()
+				return err
+			}
+			retries++
+			time.Sleep(migrateRetryInterval)
+			continue
+		}
+
+		switch action {
+		case ActionContinue:
+		case ActionWait:
+			if e.handleCmdResult(m.sender(e)) == ScheduleRetry {
+				time.Sleep(migrateRetryInterval)
+			}
+		case ActionFinish:
+			e.removeEventFromStore()
+			return nil
+		default:
+			e.removeEventFromStore()
+			if err = e.getEventRes().err; err == nil {
+				err = errno.NewError(errno.EventNotFound)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,373 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	mproto "github.com/openGemini/openGemini/open_src/influx/meta/proto"
+	"go.uber.org/zap"
+)
+
+// MoveEvent moves a db pt from the src node to the dst node:
+// preOffload(src) -> preAssign(dst) -> offload(src) -> assign(dst).
+// The pt stays on src if any step before offload fails.
+type MoveEvent struct {
+	BaseEvent
+	curState      MoveState
+	preState      MoveState
+	rollbackState MoveState
+}
+
+var moveHandlerMap map[MoveState]func(e *MoveEvent) (NextAction, error)
+
+func init() {
+	moveHandlerMap = map[MoveState]func(e *MoveEvent) (NextAction, error){
+		MoveInit:               moveInitHandler,
+		MovePreOffload:         moveSendHandler,
+		MoveRollbackPreOffload: moveSendHandler,
+		MovePreAssign:          moveSendHandler,
+		MoveOffload:            moveSendHandler,
+		MoveAssign:             moveAssignHandler,
+		MoveAssignFailed:       moveAssignFailedHandler,
+		MoveFinal:              moveFinalHandler,
+	}
+}
+
+func NewMoveEvent(pt *meta.DbPtInfo, src, dst uint64, isUserCommand bool) *MoveEvent {
+	me := &MoveEvent{
+		curState:      MoveInit,
+		preState:      MoveInit,
+		rollbackState: MoveInit}
+
+	me.pt = pt
+	me.eventId = pt.String()
+	me.eventType = Move
+	me.eventRes = &EventResultInfo{}
+	me.src = src
+	me.dst = dst
+	if isUserCommand {
+		me.userCommand = isUserCommand
+		me.eventRes.ch = make(chan error)
+	}
+	return me
+}
+
+// recoverMoveEvent restores a move event persisted by the previous meta leader
+func recoverMoveEvent(info *meta.MigrateEventInfo) *MoveEvent {
+	pt := info.GetPtInfo()
+	// the pt info of the event is shared with the meta data
+	pti := *pt.Pti
+	me := NewMoveEvent(&meta.DbPtInfo{Db: pt.Db, Pti: &pti}, info.GetSrc(), info.GetDst(), false)
+	me.curState = MoveState(info.GetCurrentState())
+	me.preState = MoveState(info.GetPreState())
+	me.operateId = info.GetOpId()
+	me.processed = true
+	me.inRecover = true
+	return me
+}
+
+func (e *MoveEvent) marshalEvent() *mproto.MigrateEventInfo {
+	return &mproto.MigrateEventInfo{
+		EventId:   proto.String(e.eventId),
+		EventType: proto.Int(int(e.eventType)),
+		Pti:       e.pt.Marshal(),
+		CurrState: proto.Int(e.getCurrState()),
+		PreState:  proto.Int(e.getPreState()),
+		Src:       proto.Uint64(e.getSrc()),
+		Dest:      proto.Uint64(e.getDst()),
+	}
+}
+
+func (e *MoveEvent) getTarget() uint64 {
+	switch e.curState {
+	case MovePreAssign, MoveAssign:
+		return e.dst
+	default:
+		return e.src
+	}
+}
+
+func (e *MoveEvent) getCurrState() int {
+	return int(e.curState)
+}
+
+func (e *MoveEvent) getPreState() int {
+	return int(e.preState)
+}
+
+func (e *MoveEvent) handleCmdResult(err error) ScheduleType {
+	scheduleType := ScheduleNormal
+	if err != nil && e.curState == MoveOffload {
+		// the offload is retried until it succeeds, rolling back would load a partly deleted pt
+		logger.NewLogger(errno.ModuleHA).Warn("retry offloading the moved pt", zap.String("db pt", e.getPtInfo().String()),
+			zap.Int("retries", e.retryNum), zap.Uint64("opId", e.operateId), zap.Error(err))
+		e.increaseRetryCnt()
+		return ScheduleRetry
+	}
+	if err != nil {
+		if e.exhaustRetries() {
+			logger.NewLogger(errno.ModuleHA).Error("event exhaust retries", zap.String("db pt", e.getPtInfo().String()),
+				zap.Int("retries", e.retryNum), zap.Uint64("opId", e.operateId), zap.String("state", e.curState.String()))
+			if e.curState == MoveAssign {
+				e.setIsolate(true)
+			}
+		} else {
+			if globalService.clusterManager.isNodeAlive(e.getTarget()) && !strings.Contains(err.Error(), errno.NewError(errno.NeedChangeStore).Error()) {
+				e.increaseRetryCnt()
+				scheduleType = ScheduleRetry
+			}
+		}
+	}
+
+	if ScheduleNormal == scheduleType {
+		e.retryNum = 0
+		e.stateTransition(err)
+	}
+	return scheduleType
+}
+
+func (e *MoveEvent) stateTransition(err error) {
+	nextState := MoveFinal
+	switch e.curState {
+	case MovePreOffload:
+		if err == nil {
+			nextState = MovePreAssign
+		} else {
+			// src may have closed the pt partly
+			nextState = MoveRollbackPreOffload
+			e.eventRes.err = err
+		}
+	case MovePreAssign:
+		if err == nil {
+			nextState = MoveOffload
+		} else {
+			nextState = MoveRollbackPreOffload
+			e.eventRes.err = err
+		}
+	case MoveOffload:
+		// dst holds a complete copy and src may have started deleting the pt, the move only rolls forward
+		nextState = MoveAssign
+		if err != nil {
+			nextState = MoveOffload
+		}
+	case MoveRollbackPreOffload:
+		// the pt is still on src, the move is finished with the error of preOffload or preAssign
+		nextState = MoveFinal
+		if err != nil {
+			// src failed to serve the pt again
+			e.setIsolate(true)
+		}
+	case MoveAssign:
+		if err != nil {
+			nextState = MoveAssignFailed
+			e.eventRes.err = err
+		}
+	case MoveAssignFailed:
+		nextState = MoveAssignFailed
+	default:
+		logger.GetLogger().Error("Fail to transit the state, state is invalid", zap.String("state", e.curState.String()))
+	}
+
+	e.rollbackState = e.preState
+	e.preState = e.curState
+	e.curState = nextState
+
+	if e.curState != e.preState {
+		e.setNeedPersist(true)
+	}
+}
+
+// isReassignNeeded only when the pt is offloaded from src but dst failed to load it
+func (e *MoveEvent) isReassignNeeded() bool {
+	return e.curState == MoveAssignFailed && !e.userCommand && !e.interrupt && !e.needIsolate
+}
+
+func (e *MoveEvent) getNextAction() (NextAction, error) {
+	if e.interrupt {
+		return ActionContinue, errno.NewError(errno.EventIsInterrupted)
+	}
+
+	err := e.storeTransitionState()
+	if err != nil {
+		e.rollbackLastTransition()
+		return ActionContinue, err
+	}
+
+	return moveHandlerMap[e.curState](e)
+}
+
+func (e *MoveEvent) storeTransitionState() error {
+	if !e.needPersist {
+		return nil
+	}
+
+	err := globalService.store.updateMigrateEvent(e)
+	if err != nil {
+		return err
+	}
+	e.setNeedPersist(false)
+	return nil
+}
+
+func (e *MoveEvent) rollbackLastTransition() {
+	e.curState, e.preState = e.preState, e.rollbackState
+}
+
+func moveInitHandler(e *MoveEvent) (NextAction, error) {
+	if !e.inRecover {
+		err := globalService.store.createMigrateEvent(e)
+		if err != nil {
+			return ActionContinue, err
+		}
+
+		e.processed = true
+		e.operateId = globalService.store.getEventOpId(e)
+		if e.operateId == 0 {
+			return ActionContinue, errno.NewError(errno.OpIdIsInvalid)
+		}
+	}
+
+	// ts-sql stops writing to and querying the pt until it is served by dst or by src again
+	if e.pt.Pti.Status != meta.Offline {
+		err := globalService.store.updatePtInfo(e.pt.Db, e.pt.Pti, e.src, meta.Offline)
+		if errno.Equal(err, errno.PtChanged) {
+			globalService.store.refreshDbPt(e.pt)
+			if e.pt.Pti.Owner.NodeID != e.src {
+				e.eventRes.err = err
+				return ActionError, nil
+			}
+		}
+		if err != nil {
+			return ActionContinue, err
+		}
+		e.pt.Pti.Status = meta.Offline
+	}
+	e.curState = MovePreOffload
+	return ActionContinue, nil
+}
+
+func moveSendHandler(e *MoveEvent) (NextAction, error) {
+	return globalService.msm.sendMigrateCommand(e)
+}
+
+func moveAssignHandler(e *MoveEvent) (NextAction, error) {
+	// the pt is offloaded from src, dst owns it from now on
+	if e.pt.Pti.Owner.NodeID != e.dst {
+		err := globalService.store.updatePtInfo(e.pt.Db, e.pt.Pti, e.dst, meta.Offline)
+		if errno.Equal(err, errno.PtChanged) {
+			globalService.store.refreshDbPt(e.pt)
+		}
+		if err != nil {
+			return ActionContinue, err
+		}
+		e.pt.Pti.Owner.NodeID = e.dst
+		e.pt.Pti.Status = meta.Offline
+	}
+	return globalService.msm.sendMigrateCommand(e)
+}
+
+func moveAssignFailedHandler(e *MoveEvent) (NextAction, error) {
+	if e.needIsolate && !e.interrupt {
+		err := globalService.store.updatePtInfo(e.pt.Db, e.pt.Pti, e.pt.Pti.Owner.NodeID, meta.Disabled)
+		if err != nil {
+			return ActionContinue, err
+		}
+		e.pt.Pti.Status = meta.Disabled
+	}
+	return ActionError, nil
+}
+
+// moveFinalHandler serves the pt again, by dst if the move succeeded or by src if it was rolled back
+func moveFinalHandler(e *MoveEvent) (NextAction, error) {
+	status := meta.Online
+	if e.needIsolate {
+		status = meta.Disabled
+	}
+
+	if !e.interrupt && e.pt.Pti.Status != status {
+		err := globalService.store.updatePtInfo(e.pt.Db, e.pt.Pti, e.pt.Pti.Owner.NodeID, status)
+		if err != nil {
+			return ActionContinue, err
+		}
+		e.pt.Pti.Status = status
+	}
+
+	if e.eventRes.err != nil {
+		return ActionError, nil
+	}
+	return ActionFinish, nil
+}
+
+// migratePtType returns the command sent to the store node in the current state
+func (e *MoveEvent) migratePtType() netstorage.MigratePtType {
+	switch e.curState {
+	case MovePreOffload:
+		return netstorage.MigratePtPreOffload
+	case MoveRollbackPreOffload:
+		return netstorage.MigratePtRollbackPreOffload
+	case MovePreAssign:
+		return netstorage.MigratePtPreAssign
+	case MoveOffload:
+		return netstorage.MigratePtOffload
+	case MoveAssign:
+		return netstorage.MigratePtAssign
+	default:
+		return 0
+	}
+}
+
+type MoveState int
+
+const (
+	MoveInit MoveState = iota
+	MovePreOffload
+	MoveRollbackPreOffload
+	MovePreAssign
+	MoveOffload
+	MoveAssign
+	MoveAssignFailed
+	MoveFinal
+)
+
+func (s MoveState) String() string {
+	switch s {
+	case MoveInit:
+		return "moveInit"
+	case MovePreOffload:
+		return "movePreOffload"
+	case MoveRollbackPreOffload:
+		return "moveRollbackPreOffload"
+	case MovePreAssign:
+		return "movePreAssign"
+	case MoveOffload:
+		return "moveOffload"
+	case MoveAssign:
+		return "moveAssign"
+	case MoveAssignFailed:
+		return "moveAssignFailed"
+	case MoveFinal:
+		return "moveFinal"
+	default:
+		return "unknown move state"
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/open_src/github.com/hashicorp/serf/serf"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initMoveEventTest(t *testing.T) (*MockMetaService, string) {
+	mms, err := NewMockMetaService(t.TempDir(), "127.0.0.1")
+	require.NoError(t, err)
+	require.NoError(t, mms.service.Open())
+	require.NoError(t, globalService.store.ApplyCmd(GenerateCreateDataNodeCmd("127.0.0.1:8400", "127.0.0.1:8401")))
+	require.NoError(t, globalService.store.ApplyCmd(GenerateCreateDataNodeCmd("127.0.0.2:8400", "127.0.0.2:8401")))
+	db := "db0"
+	require.NoError(t, globalService.store.ApplyCmd(GenerateCreateDatabaseCmd(db)))
+	require.NoError(t, globalService.store.updateNodeStatus(2, int32(serf.StatusAlive), 2, "127.0.0.1:8011"))
+	require.NoError(t, globalService.store.updateNodeStatus(3, int32(serf.StatusAlive), 2, "127.0.0.2:8011"))
+	globalService.clusterManager = NewClusterManager(globalService.store)
+	return mms, db
+}
+
+func TestMoveEventStateTransition(t *testing.T) {
+	mms, db := initMoveEventTest(t)
+	defer mms.close()
+	defer globalService.clusterManager.Close()
+
+	dbPt := &meta.DbPtInfo{Db: db, Pti: &meta.PtInfo{PtId: 0, Status: meta.Online, Owner: meta.PtOwner{NodeID: 2}}}
+	event := NewMoveEvent(dbPt, 2, 3, false)
+	assert.Equal(t, Move, event.getEventType())
+
+	action, err := event.getNextAction()
+	require.NoError(t, err)
+	assert.Equal(t, ActionContinue, action)
+	assert.Equal(t, MovePreOffload, event.curState)
+	assert.Equal(t, 1, len(globalService.store.data.MigrateEvents))
+	// ts-sql stops routing to the pt during the move
+	assert.Equal(t, meta.Offline, globalService.store.GetData().PtView[db][0].Status)
+
+	states := []MoveState{MovePreOffload, MovePreAssign, MoveOffload, MoveAssign}
+	targets := []uint64{2, 3, 2, 3}
+	for i, s := range states {
+		action, err = event.getNextAction()
+		require.NoError(t, err)
+		assert.Equal(t, ActionWait, action, s.String())
+		assert.Equal(t, s, event.curState)
+		assert.Equal(t, targets[i], event.getTarget())
+		assert.Equal(t, ScheduleNormal, event.handleCmdResult(nil))
+	}
+	assert.Equal(t, MoveFinal, event.curState)
+	pti := globalService.store.GetData().PtView[db][0]
+	assert.Equal(t, uint64(3), pti.Owner.NodeID)
+	assert.Equal(t, meta.Offline, pti.Status)
+
+	action, err = event.getNextAction()
+	require.NoError(t, err)
+	assert.Equal(t, ActionFinish, action)
+	event.removeEventFromStore()
+
+	data := globalService.store.GetData()
+	assert.Equal(t, meta.Online, data.PtView[db][0].Status)
+	assert.Equal(t, uint64(3), data.PtView[db][0].Owner.NodeID)
+	assert.Equal(t, 0, len(data.MigrateEvents))
+}
+
+func TestMoveEventRollback(t *testing.T) {
+	mms, db := initMoveEventTest(t)
+	defer mms.close()
+	defer globalService.clusterManager.Close()
+
+	var sent []MoveState
+	globalService.msm.SetCmdSender(func(e MigrateEvent) error {
+		s := MoveState(e.getCurrState())
+		sent = append(sent, s)
+		if s == MovePreAssign {
+			return errno.NewError(errno.NeedChangeStore)
+		}
+		return nil
+	})
+	defer globalService.msm.SetCmdSender(nil)
+
+	dbPt := &meta.DbPtInfo{Db: db, Pti: &meta.PtInfo{PtId: 1, Status: meta.Online, Owner: meta.PtOwner{NodeID: 3}}}
+	err := globalService.msm.executeEvent(NewMoveEvent(dbPt, 3, 2, false))
+	assert.True(t, errno.Equal(err, errno.NeedChangeStore))
+	assert.Equal(t, []MoveState{MovePreOffload, MovePreAssign, MoveRollbackPreOffload}, sent)
+
+	data := globalService.store.GetData()
+	assert.Equal(t, meta.Online, data.PtView[db][1].Status)
+	assert.Equal(t, uint64(3), data.PtView[db][1].Owner.NodeID)
+	assert.Equal(t, 0, len(data.MigrateEvents))
+
+	globalService.msm.SetCmdSender(nil)
+	assert.True(t, errno.Equal(globalService.msm.executeEvent(NewMoveEvent(dbPt, 3, 2, false)), errno.StateMachineIsNotRunning))
+}
+
+func TestMoveEventRollbackPreOffload(t *testing.T) {
+	mms, db := initMoveEventTest(t)
+	defer mms.close()
+	defer globalService.clusterManager.Close()
+
+	var sent []MoveState
+	globalService.msm.SetCmdSender(func(e MigrateEvent) error {
+		s := MoveState(e.getCurrState())
+		sent = append(sent, s)
+		if s == MovePreOffload {
+			return errno.NewError(errno.NeedChangeStore)
+		}
+		return nil
+	})
+	defer globalService.msm.SetCmdSender(nil)
+
+	dbPt := &meta.DbPtInfo{Db: db, Pti: &meta.PtInfo{PtId: 0, Status: meta.Online, Owner: meta.PtOwner{NodeID: 2}}}
+	err := globalService.msm.executeEvent(NewMoveEvent(dbPt, 2, 3, false))
+	assert.True(t, errno.Equal(err, errno.NeedChangeStore))
+	// src may have closed the pt partly
+	assert.Equal(t, []MoveState{MovePreOffload, MoveRollbackPreOffload}, sent)
+
+	data := globalService.store.GetData()
+	assert.Equal(t, meta.Online, data.PtView[db][0].Status)
+	assert.Equal(t, uint64(2), data.PtView[db][0].Owner.NodeID)
+}
+
+func TestMoveEventRetryOffload(t *testing.T) {
+	mms, db := initMoveEventTest(t)
+	defer mms.close()
+	defer globalService.clusterManager.Close()
+
+	var sent []MoveState
+	offloadErrs := 2
+	globalService.msm.SetCmdSender(func(e MigrateEvent) error {
+		s := MoveState(e.getCurrState())
+		sent = append(sent, s)
+		if s == MoveOffload && offloadErrs > 0 {
+			offloadErrs--
+			return errno.NewError(errno.NeedChangeStore)
+		}
+		return nil
+	})
+	defer globalService.msm.SetCmdSender(nil)
+
+	dbPt := &meta.DbPtInfo{Db: db, Pti: &meta.PtInfo{PtId: 0, Status: meta.Online, Owner: meta.PtOwner{NodeID: 2}}}
+	require.NoError(t, globalService.msm.executeEvent(NewMoveEvent(dbPt, 2, 3, false)))
+	// src may have deleted the pt partly, the move is never rolled back after preAssign
+	assert.Equal(t, []MoveState{MovePreOffload, MovePreAssign, MoveOffload, MoveOffload, MoveOffload, MoveAssign}, sent)
+
+	data := globalService.store.GetData()
+	assert.Equal(t, meta.Online, data.PtView[db][0].Status)
+	assert.Equal(t, uint64(3), data.PtView[db][0].Owner.NodeID)
+}
+
+func TestMoveEventRecover(t *testing.T) {
+	mms, db := initMoveEventTest(t)
+	defer mms.close()
+	defer globalService.clusterManager.Close()
+
+	dbPt := &meta.DbPtInfo{Db: db, Pti: &meta.PtInfo{PtId: 0, Status: meta.Online, Owner: meta.PtOwner{NodeID: 2}}}
+	event := NewMoveEvent(dbPt, 2, 3, false)
+	for _, err := range []error{nil, nil} {
+		_, err = event.getNextAction()
+		require.NoError(t, err)
+	}
+	event.handleCmdResult(nil)
+	_, err := event.getNextAction()
+	require.NoError(t, err)
+	assert.Equal(t, MovePreAssign, event.curState)
+
+	// the new meta leader goes on with the persisted event
+	var sent []MoveState
+	globalService.msm.SetCmdSender(func(e MigrateEvent) error {
+		sent = append(sent, MoveState(e.getCurrState()))
+		return nil
+	})
+	defer globalService.msm.SetCmdSender(nil)
+	globalService.clusterManager.recoverMoves(make(chan struct{}))
+	assert.Equal(t, []MoveState{MovePreAssign, MoveOffload, MoveAssign}, sent)
+
+	data := globalService.store.GetData()
+	assert.Equal(t, meta.Online, data.PtView[db][0].Status)
+	assert.Equal(t, uint64(3), data.PtView[db][0].Owner.NodeID)
+	assert.Equal(t, 0, len(data.MigrateEvents))
+}
+
+type mockMigrateNetStore struct {
+	netstorage.Storage
+	node *meta.DataNode
+	req  *netstorage.MigratePtRequest
+}
+
+func (s *mockMigrateNetStore) MigratePt(node *meta.DataNode, req *netstorage.MigratePtRequest) error {
+	s.node, s.req = node, req
+	return nil
+}
+
+func TestSendMigratePtCmd(t *testing.T) {
+	mms, db := initMoveEventTest(t)
+	defer mms.close()
+	defer globalService.clusterManager.Close()
+
+	netStore := &mockMigrateNetStore{}
+	globalService.store.NetStore = netStore
+
+	dbPt := &meta.DbPtInfo{Db: db, Pti: &meta.PtInfo{PtId: 0, Status: meta.Online, Owner: meta.PtOwner{NodeID: 2}}}
+	event := NewMoveEvent(dbPt, 2, 3, false)
+	_, err := event.getNextAction()
+	require.NoError(t, err)
+
+	require.NoError(t, globalService.store.sendMigratePtCmd(event))
+	assert.Equal(t, uint64(2), netStore.node.ID)
+	assert.Equal(t, netstorage.MigratePtPreOffload, netStore.req.Type)
+	assert.Equal(t, db, netStore.req.Db)
+	assert.Equal(t, uint64(2), netStore.req.SrcNodeId)
+	assert.NotEqual(t, uint64(0), netStore.req.OpId)
+	assert.Empty(t, netStore.req.Durations)
+
+	event.curState = MoveAssign
+	require.NoError(t, globalService.store.sendMigratePtCmd(event))
+	assert.Equal(t, uint64(3), netStore.node.ID)
+	assert.Equal(t, netstorage.MigratePtAssign, netStore.req.Type)
+
+	event.dst = 10
+	assert.Equal(t, meta.ErrNodeNotFound, globalService.store.sendMigratePtCmd(event))
+}
@@ -18,15 +18,20 @@ package meta
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	ast "github.com/influxdata/influxdb/pkg/testing/assert"
 	"github.com/openGemini/openGemini/app/ts-meta/meta/message"
 	"github.com/openGemini/openGemini/engine/executor/spdy"
 	"github.com/openGemini/openGemini/engine/executor/spdy/transport"
+	"github.com/openGemini/openGemini/lib/audit"
 	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/lib/metaclient"
 	"github.com/openGemini/openGemini/open_src/influx/meta"
+	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
 )
 
 const address = "127.0.0.1:18298"
@@ -60,6 +65,7 @@ func sendTestMsg(msg *message.MetaMessage, callback transport.Callback) error {
 }
 
 type MockRPCStore struct {
+	applyErr error
 }
 
 func NewMockRPCStore() *MockRPCStore {
@@ -100,11 +106,11 @@ func (s *MockRPCStore) isCandidate() bool {
 }
 
 func (s *MockRPCStore) apply(b []byte) error {
-	panic("implement me")
+	return s.applyErr
 }
 
 func (s *MockRPCStore) index() uint64 {
-	panic("implement me")
+	return 1
 }
 
 func (s *MockRPCStore) UpdateLoad(b []byte) error {
@@ -217,3 +223,65 @@ func Test_Join(t *testing.T) {
 	ast.Equal(t, uint64(0), callback.NodeInfo.ID)
 	ast.Equal(t, "", callback.NodeInfo.Host)
 }
+
+type auditSink struct {
+	events []*audit.Event
+}
+
+func (s *auditSink) WriteEvent(e *audit.Event) {
+	s.events = append(s.events, e)
+}
+
+func TestExecute_Audit(t *testing.T) {
+	logConf := config.NewLogger(config.AppMeta)
+	logConf.Path = t.TempDir()
+	auditConf := config.NewAudit()
+	auditConf.Enabled = true
+	audit.Open(auditConf, logConf)
+	defer audit.Close()
+	sink := &auditSink{}
+	audit.AddSink(sink)
+
+	store := NewMockRPCStore()
+	execute := func(ty proto2.Command_Type, desc *proto.ExtensionDesc, value interface{}) {
+		cmd := &proto2.Command{Type: &ty}
+		if err := proto.SetExtension(cmd, desc, value); err != nil {
+			t.Fatal(err)
+		}
+		body, err := proto.Marshal(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := &Execute{req: &message.ExecuteRequest{Body: body}}
+		h.InitHandler(store, config.NewMeta(), make(chan struct{}))
+		if _, err := h.Process(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createDatabase := func() {
+		execute(proto2.Command_CreateDatabaseCommand, proto2.E_CreateDatabaseCommand_Command,
+			&proto2.CreateDatabaseCommand{Name: proto.String("db0")})
+	}
+
+	// the requests of the clients are recorded with the affected object
+	createDatabase()
+	store.applyErr = errors.New("database already exists")
+	execute(proto2.Command_SetPrivilegeCommand, proto2.E_SetPrivilegeCommand_Command,
+		&proto2.SetPrivilegeCommand{Username: proto.String("bob"), Database: proto.String("db0"), Privilege: proto.Int32(1)})
+	// a follower does not record the requests, the clients retry them on the leader
+	store.applyErr = errno.NewError(errno.MetaIsNotLeader)
+	createDatabase()
+	// the internal commands are not recorded
+	store.applyErr = nil
+	execute(proto2.Command_UpdateTokenLastUsedCommand, proto2.E_UpdateTokenLastUsedCommand_Command,
+		&proto2.UpdateTokenLastUsedCommand{Name: proto.String("t0"), LastUsed: proto.Int64(1)})
+
+	ast.Equal(t, 2, len(sink.events))
+	ast.Equal(t, audit.MetaCommand, sink.events[0].Type)
+	ast.Equal(t, "CreateDatabaseCommand", sink.events[0].Statement)
+	ast.Equal(t, "db0", sink.events[0].Object)
+	ast.Equal(t, audit.ResultSuccess, sink.events[0].Result())
+	ast.Equal(t, "bob", sink.events[1].User)
+	ast.Equal(t, "db0", sink.events[1].Object)
+	ast.Equal(t, audit.ResultFailure, sink.events[1].Result())
+}
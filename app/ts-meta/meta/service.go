@@ -70,6 +70,7 @@ func NewService(c *config.Meta, tls *tlsconfig.Config) *Service {
 		tls:      tls,
 		raftAddr: c.BindAddress,
 		Logger:   logger.NewLogger(errno.ModuleMeta).With(zap.String("service", "meta")),
+		msm:      NewMigrateStateMachine(),
 	}
 	return globalService
 }
@@ -117,7 +118,9 @@ func (s *Service) Open() error {
 
 	if len(s.config.JoinPeers) > 1 {
 		s.clusterManager = NewClusterManager(s.store)
+		s.clusterManager.setBalanceConfig(s.config)
 		s.store.cm = s.clusterManager
+		s.msm.SetCmdSender(s.store.sendMigratePtCmd)
 	}
 
 	if err := s.store.Open(s.RaftListener); err != nil {
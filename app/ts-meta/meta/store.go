@@ -351,6 +351,7 @@ type Store struct {
 		DeleteDatabase(node *meta.DataNode, database string, pt uint32) error
 		DeleteRetentionPolicy(node *meta.DataNode, db string, rp string, pt uint32) error
 		DeleteMeasurement(node *meta.DataNode, db string, rp string, name string, shardIds []uint64) error
+		MigratePt(node *meta.DataNode, req *netstorage.MigratePtRequest) error
 	}
 
 	statMu       sync.RWMutex
@@ -1326,6 +1327,68 @@ func (s *Store) dataNodes() meta.DataNodeInfos {
 	return s.data.CloneDataNodes()
 }
 
+func (s *Store) dbPtView() map[string]meta.DBPtInfos {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	view := make(map[string]meta.DBPtInfos, len(s.data.PtView))
+	for db, pts := range s.data.PtView {
+		view[db] = append(meta.DBPtInfos(nil), pts...)
+	}
+	return view
+}
+
+// ptLoads sums the rows of the newest shards reported by the stores for every db pt
+func (s *Store) ptLoads() map[string]map[uint32]uint64 {
+	s.statMu.Lock()
+	dbs := make(map[string]*dbInfo, len(s.dbStatistics))
+	for db, dbinfo := range s.dbStatistics {
+		dbs[db] = dbinfo
+	}
+	s.statMu.Unlock()
+
+	loads := make(map[string]map[uint32]uint64, len(dbs))
+	for db, dbinfo := range dbs {
+		pts := make(map[uint32]uint64)
+		dbinfo.rpMu.RLock()
+		for _, rpinfo := range dbinfo.rpStatistics {
+			rpinfo.mu.RLock()
+			for _, stat := range rpinfo.shardStat {
+				if stat.ownerPT != math.MaxUint32 {
+					pts[stat.ownerPT] += stat.shardSize
+				}
+			}
+			rpinfo.mu.RUnlock()
+		}
+		dbinfo.rpMu.RUnlock()
+		loads[db] = pts
+	}
+	return loads
+}
+
+func (s *Store) migrateEvents() map[string]*meta.MigrateEventInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data.CloneMigrateEvents()
+}
+
+func (s *Store) hasMigrateEvents() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data.MigrateEvents) > 0
+}
+
+func (s *Store) deleteDataNode(id uint64) error {
+	val := &mproto.DeleteDataNodeCommand{
+		ID: proto.Uint64(id),
+	}
+	t := mproto.Command_DeleteDataNodeCommand
+	cmd := &mproto.Command{Type: &t}
+	if err := proto.SetExtension(cmd, mproto.E_DeleteDataNodeCommand_Command, val); err != nil {
+		panic(err)
+	}
+	return s.ApplyCmd(cmd)
+}
+
 func (s *Store) removeEvent(eventId string) error {
 	val := &mproto.RemoveEventCommand{
 		EventId: proto.String(eventId),
@@ -1363,6 +1426,40 @@ func (s *Store) updateMigrateEvent(e MigrateEvent) error {
 	return s.ApplyCmd(cmd)
 }
 
+// sendMigratePtCmd sends the command of the current state of a move event to the store node
+// executing it and waits for the result
+func (s *Store) sendMigratePtCmd(e MigrateEvent) error {
+	me, ok := e.(*MoveEvent)
+	if !ok {
+		return fmt.Errorf("unsupported migrate event %s of type %d", e.getEventId(), e.getEventType())
+	}
+	pt := me.getPtInfo()
+	req := &netstorage.MigratePtRequest{
+		Type:      me.migratePtType(),
+		Db:        pt.Db,
+		PtId:      pt.Pti.PtId,
+		OpId:      me.getOpId(),
+		SrcNodeId: me.getSrc(),
+	}
+
+	s.mu.RLock()
+	node := s.data.DataNode(me.getTarget())
+	if node != nil {
+		node = &meta.DataNode{NodeInfo: node.NodeInfo}
+	}
+	if (req.Type == netstorage.MigratePtRollbackPreOffload || req.Type == netstorage.MigratePtAssign) && s.data.Database(pt.Db) != nil {
+		for _, d := range s.data.GetShardDurationsByDbPt(pt.Db, pt.Pti.PtId) {
+			req.Durations = append(req.Durations, d)
+		}
+	}
+	s.mu.RUnlock()
+
+	if node == nil {
+		return meta.ErrNodeNotFound
+	}
+	return s.NetStore.MigratePt(node, req)
+}
+
 func (s *Store) updatePtInfo(db string, ptInfo *meta.PtInfo, ownerNode uint64, status meta.PtStatus) error {
 	val := &mproto.UpdatePtInfoCommand{
 		Db:     proto.String(db),
@@ -172,6 +172,22 @@ func (fsm *storeFSM) executeCmd(cmd proto2.Command) interface{} {
 		return fsm.applyUpdatePtInfoCommand(&cmd)
 	case proto2.Command_RemoveEventCommand:
 		return fsm.applyRemoveEvent(&cmd)
+	case proto2.Command_DecommissionDataNodeCommand:
+		return fsm.applyDecommissionDataNodeCommand(&cmd)
+	case proto2.Command_CreateRoleCommand:
+		return fsm.applyCreateRoleCommand(&cmd)
+	case proto2.Command_DropRoleCommand:
+		return fsm.applyDropRoleCommand(&cmd)
+	case proto2.Command_SetRolePrivilegeCommand:
+		return fsm.applySetRolePrivilegeCommand(&cmd)
+	case proto2.Command_SetUserRoleCommand:
+		return fsm.applySetUserRoleCommand(&cmd)
+	case proto2.Command_CreateTokenCommand:
+		return fsm.applyCreateTokenCommand(&cmd)
+	case proto2.Command_DropTokenCommand:
+		return fsm.applyDropTokenCommand(&cmd)
+	case proto2.Command_UpdateTokenLastUsedCommand:
+		return fsm.applyUpdateTokenLastUsedCommand(&cmd)
 	default:
 		panic(fmt.Errorf("cannot apply command: %x", cmd.GetType()))
 	}
@@ -394,6 +410,71 @@ func (fsm *storeFSM) applySetPrivilegeCommand(cmd *proto2.Command) interface{} {
 	return err
 }
 
+func (fsm *storeFSM) applyCreateRoleCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_CreateRoleCommand_Command)
+	v := ext.(*proto2.CreateRoleCommand)
+	err := fsm.data.CreateRole(v.GetName())
+	fsm.Logger.Info("apply create role command", zap.String("role", v.GetName()), zap.Error(err))
+	return err
+}
+
+func (fsm *storeFSM) applyDropRoleCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_DropRoleCommand_Command)
+	v := ext.(*proto2.DropRoleCommand)
+	err := fsm.data.DropRole(v.GetName())
+	fsm.Logger.Info("apply drop role command", zap.String("role", v.GetName()), zap.Error(err))
+	return err
+}
+
+func (fsm *storeFSM) applySetRolePrivilegeCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_SetRolePrivilegeCommand_Command)
+	v := ext.(*proto2.SetRolePrivilegeCommand)
+	pb := v.GetPrivilege()
+	p, err := meta2.NewRolePrivilege(pb.GetDatabase(), pb.GetRetentionPolicy(), pb.GetMeasurement(), pb.GetRegex(),
+		originql.Privilege(pb.GetPrivilege()))
+	if err == nil {
+		err = fsm.data.SetRolePrivilege(v.GetRole(), p, v.GetRevoke())
+	}
+	fsm.Logger.Info("apply set role privilege command", zap.String("role", v.GetRole()),
+		zap.String("db", pb.GetDatabase()), zap.String("rp", pb.GetRetentionPolicy()),
+		zap.String("measurement", pb.GetMeasurement()), zap.Int32("privilege", pb.GetPrivilege()),
+		zap.Bool("revoke", v.GetRevoke()), zap.Error(err))
+	return err
+}
+
+func (fsm *storeFSM) applySetUserRoleCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_SetUserRoleCommand_Command)
+	v := ext.(*proto2.SetUserRoleCommand)
+	err := fsm.data.SetUserRole(v.GetUsername(), v.GetRole(), v.GetRevoke())
+	fsm.Logger.Info("apply set user role command", zap.String("userID", v.GetUsername()),
+		zap.String("role", v.GetRole()), zap.Bool("revoke", v.GetRevoke()), zap.Error(err))
+	return err
+}
+
+func (fsm *storeFSM) applyCreateTokenCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_CreateTokenCommand_Command)
+	v := ext.(*proto2.CreateTokenCommand)
+	var ti meta2.TokenInfo
+	ti.Unmarshal(v.GetToken())
+	err := fsm.data.CreateToken(ti)
+	fsm.Logger.Info("apply create token command", zap.String("token", ti.Name), zap.String("userID", ti.User), zap.Error(err))
+	return err
+}
+
+func (fsm *storeFSM) applyDropTokenCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_DropTokenCommand_Command)
+	v := ext.(*proto2.DropTokenCommand)
+	err := fsm.data.DropToken(v.GetName())
+	fsm.Logger.Info("apply drop token command", zap.String("token", v.GetName()), zap.Error(err))
+	return err
+}
+
+func (fsm *storeFSM) applyUpdateTokenLastUsedCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_UpdateTokenLastUsedCommand_Command)
+	v := ext.(*proto2.UpdateTokenLastUsedCommand)
+	return fsm.data.UpdateTokenLastUsed(v.GetName(), time.Unix(0, v.GetLastUsed()).UTC())
+}
+
 func (fsm *storeFSM) applySetAdminPrivilegeCommand(cmd *proto2.Command) interface{} {
 	ext, _ := proto.GetExtension(cmd, proto2.E_SetAdminPrivilegeCommand_Command)
 	v := ext.(*proto2.SetAdminPrivilegeCommand)
@@ -466,6 +547,12 @@ func (fsm *storeFSM) applyDeleteDataNodeCommand(cmd *proto2.Command) interface{}
 	return fsm.data.DeleteDataNode(v.GetID())
 }
 
+func (fsm *storeFSM) applyDecommissionDataNodeCommand(cmd *proto2.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, proto2.E_DecommissionDataNodeCommand_Command)
+	v := ext.(*proto2.DecommissionDataNodeCommand)
+	return fsm.data.DecommissionDataNode(v.GetID())
+}
+
 func (fsm *storeFSM) Snapshot() (raft.FSMSnapshot, error) {
 	s := (*Store)(fsm)
 	s.mu.Lock()
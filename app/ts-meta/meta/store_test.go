@@ -31,6 +31,7 @@ import (
 	"github.com/openGemini/openGemini/app/ts-meta/meta/message"
 	"github.com/openGemini/openGemini/lib/errno"
 	logger2 "github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/netstorage"
 	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
 	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
 	"github.com/stretchr/testify/assert"
@@ -176,6 +177,7 @@ type MockStore interface {
 	DeleteDatabase(node *meta2.DataNode, database string, ptId uint32) error
 	DeleteRetentionPolicy(node *meta2.DataNode, db string, rp string, ptId uint32) error
 	DeleteMeasurement(node *meta2.DataNode, db string, rp, name string, shardIds []uint64) error
+	MigratePt(node *meta2.DataNode, req *netstorage.MigratePtRequest) error
 }
 
 type MockNetStorage struct {
@@ -213,6 +215,10 @@ func (s *MockNetStorage) DeleteMeasurement(node *meta2.DataNode, db, rp, name st
 	return nil
 }
 
+func (s *MockNetStorage) MigratePt(node *meta2.DataNode, req *netstorage.MigratePtRequest) error {
+	return nil
+}
+
 func NewMockNetStorage() MockStore {
 	return &MockNetStorage{}
 }
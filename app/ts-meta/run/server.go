@@ -28,6 +28,7 @@ import (
 	"github.com/influxdata/influxdb/tcp"
 	"github.com/openGemini/openGemini/app"
 	"github.com/openGemini/openGemini/app/ts-meta/meta"
+	"github.com/openGemini/openGemini/lib/audit"
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/cpu"
 	"github.com/openGemini/openGemini/lib/fileops"
@@ -110,6 +111,8 @@ func (s *Server) Open() error {
 	//Mark start-up in extra log
 	fmt.Printf("%v TSMeta starting\n", time.Now())
 
+	audit.Open(s.config.Audit, s.config.Logging)
+
 	// Open shared TCP connection.
 	ln, err := net.Listen("tcp", s.BindAddress)
 	if err != nil {
@@ -173,6 +176,8 @@ func (s *Server) Close() error {
 	if s.MetaService != nil {
 		err = s.MetaService.Close()
 	}
+
+	audit.Close()
 	return err
 }
 
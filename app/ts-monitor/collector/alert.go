@@ -0,0 +1,384 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/valyala/fastjson"
+	"go.uber.org/zap"
+)
+
+type AlertState string
+
+const (
+	AlertPending  AlertState = "pending"
+	AlertFiring   AlertState = "firing"
+	AlertResolved AlertState = "resolved"
+)
+
+// Alert is the state of one rule for one series returned by the rule query.
+type Alert struct {
+	Rule        string            `json:"rule"`
+	State       AlertState        `json:"state"`
+	Series      string            `json:"series,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Value       float64           `json:"value"`
+	ActiveAt    time.Time         `json:"active_at"`
+	FiredAt     time.Time         `json:"fired_at,omitempty"`
+	ResolvedAt  time.Time         `json:"resolved_at,omitempty"`
+}
+
+// alertSample is the last value of one series returned by a rule query.
+type alertSample struct {
+	key   string
+	tags  map[string]string
+	value float64
+}
+
+type ruleState struct {
+	rule   *config.AlertRule
+	active map[string]*Alert      // series key -> pending or firing alert
+	last   map[string]float64     // series key -> value of the previous evaluation, used by rate rules
+	lastAt map[string]time.Time   // series key -> time of the previous evaluation
+	seen   map[string]alertSample // reuse map
+}
+
+type AlertManager struct {
+	done chan struct{}
+
+	url       string
+	conf      *config.MonitorAlert
+	Client    HTTPClient
+	Notifiers []AlertNotifier
+	logger    *logger.Logger
+
+	mu    sync.Mutex
+	rules []*ruleState
+}
+
+func NewAlertManager(logger *logger.Logger, conf *config.MonitorAlert) *AlertManager {
+	am := &AlertManager{
+		done:   make(chan struct{}),
+		url:    fmt.Sprintf("http://%s/query", conf.HttpEndpoint),
+		conf:   conf,
+		Client: http.DefaultClient,
+		logger: logger,
+	}
+	for i := range conf.Rules {
+		am.rules = append(am.rules, &ruleState{
+			rule:   &conf.Rules[i],
+			active: make(map[string]*Alert),
+			last:   make(map[string]float64),
+			lastAt: make(map[string]time.Time),
+			seen:   make(map[string]alertSample),
+		})
+	}
+	for _, u := range conf.WebhookURLs {
+		am.Notifiers = append(am.Notifiers, NewWebhookNotifier(u))
+	}
+	if conf.FilePath != "" {
+		am.Notifiers = append(am.Notifiers, NewFileNotifier(conf.FilePath))
+	}
+	return am
+}
+
+func (am *AlertManager) Start() {
+	am.logger.Info("start AlertManager", zap.Int("rules", len(am.rules)))
+	ticker := time.NewTicker(time.Duration(am.conf.EvaluationInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			am.Evaluate(time.Now())
+		case <-am.done:
+			am.logger.Info("alert manager closed")
+			return
+		}
+	}
+}
+
+// Evaluate runs every rule once and notifies the alerts which changed to firing or resolved.
+func (am *AlertManager) Evaluate(now time.Time) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	var changed []*Alert
+	for _, rs := range am.rules {
+		samples, err := am.queryRule(rs.rule)
+		if err != nil {
+			// keep the previous state, a failed query says nothing about the rule
+			am.logger.Error("evaluate alert rule failed", zap.String("rule", rs.rule.Name), zap.Error(err))
+			continue
+		}
+		changed = append(changed, rs.evaluate(samples, now)...)
+	}
+
+	changed = am.filterSilenced(changed, now)
+	if len(changed) == 0 {
+		return
+	}
+	for _, n := range am.Notifiers {
+		if err := n.Notify(changed); err != nil {
+			am.logger.Error("notify alerts failed", zap.Error(err))
+		}
+	}
+}
+
+// Alerts returns a copy of all pending and firing alerts.
+func (am *AlertManager) Alerts() []Alert {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	var alerts []Alert
+	for _, rs := range am.rules {
+		for _, a := range rs.active {
+			alerts = append(alerts, *a)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Rule != alerts[j].Rule {
+			return alerts[i].Rule < alerts[j].Rule
+		}
+		return alerts[i].Series < alerts[j].Series
+	})
+	return alerts
+}
+
+func (am *AlertManager) Close() {
+	close(am.done)
+}
+
+func (rs *ruleState) evaluate(samples []alertSample, now time.Time) []*Alert {
+	rule := rs.rule
+	for k := range rs.seen {
+		delete(rs.seen, k)
+	}
+
+	switch rule.Type {
+	case config.AlertRuleAbsence:
+		if len(samples) == 0 {
+			rs.seen[""] = alertSample{}
+		}
+	case config.AlertRuleThreshold:
+		for _, s := range samples {
+			if compareAlertValue(s.value, rule.Operator, rule.Threshold) {
+				rs.seen[s.key] = s
+			}
+		}
+	case config.AlertRuleRate:
+		for _, s := range samples {
+			prev, ok := rs.last[s.key]
+			prevAt := rs.lastAt[s.key]
+			rs.last[s.key], rs.lastAt[s.key] = s.value, now
+			if !ok || !now.After(prevAt) {
+				continue
+			}
+			rate := (s.value - prev) / now.Sub(prevAt).Seconds()
+			if compareAlertValue(rate, rule.Operator, rule.Threshold) {
+				s.value = rate
+				rs.seen[s.key] = s
+			}
+		}
+	}
+
+	var changed []*Alert
+	for key, s := range rs.seen {
+		a, ok := rs.active[key]
+		if !ok {
+			a = &Alert{
+				Rule:        rule.Name,
+				State:       AlertPending,
+				Series:      key,
+				Tags:        s.tags,
+				Labels:      rule.Labels,
+				Description: rule.Description,
+				ActiveAt:    now,
+			}
+			rs.active[key] = a
+		}
+		a.Value = s.value
+		if a.State == AlertPending && now.Sub(a.ActiveAt) >= time.Duration(rule.For) {
+			a.State = AlertFiring
+			a.FiredAt = now
+			c := *a
+			changed = append(changed, &c)
+		}
+	}
+
+	for key, a := range rs.active {
+		if _, ok := rs.seen[key]; ok {
+			continue
+		}
+		delete(rs.active, key)
+		if a.State != AlertFiring {
+			// pending alerts disappear without notification
+			continue
+		}
+		a.State = AlertResolved
+		a.ResolvedAt = now
+		changed = append(changed, a)
+	}
+	return changed
+}
+
+func (am *AlertManager) filterSilenced(alerts []*Alert, now time.Time) []*Alert {
+	if len(am.conf.Silences) == 0 {
+		return alerts
+	}
+	n := 0
+	for _, a := range alerts {
+		if am.isSilenced(a, now) {
+			am.logger.Info("alert silenced", zap.String("rule", a.Rule), zap.String("series", a.Series), zap.String("state", string(a.State)))
+			continue
+		}
+		alerts[n] = a
+		n++
+	}
+	return alerts[:n]
+}
+
+func (am *AlertManager) isSilenced(a *Alert, now time.Time) bool {
+	for i := range am.conf.Silences {
+		s := &am.conf.Silences[i]
+		if s.Rule != "" && s.Rule != a.Rule {
+			continue
+		}
+		if now.Before(s.StartsAt) || (!s.EndsAt.IsZero() && !now.Before(s.EndsAt)) {
+			continue
+		}
+		matched := true
+		for k, v := range s.Tags {
+			if a.Tags[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func compareAlertValue(v float64, op string, threshold float64) bool {
+	switch op {
+	case ">":
+		return v > threshold
+	case ">=":
+		return v >= threshold
+	case "<":
+		return v < threshold
+	case "<=":
+		return v <= threshold
+	case "==":
+		return v == threshold
+	case "!=":
+		return v != threshold
+	}
+	return false
+}
+
+func (am *AlertManager) queryRule(rule *config.AlertRule) ([]alertSample, error) {
+	db := rule.Database
+	if db == "" {
+		db = am.conf.Database
+	}
+	params := url.Values{}
+	params.Add("db", db)
+	params.Add("q", rule.Query)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?%s", am.url, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := am.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query status %d: %s", resp.StatusCode, body)
+	}
+	return parseAlertSamples(body)
+}
+
+// parseAlertSamples takes the first value column of the last row of every series.
+func parseAlertSamples(body []byte) ([]alertSample, error) {
+	var p fastjson.Parser
+	v, err := p.ParseBytes(body)
+	if err != nil {
+		return nil, err
+	}
+	result := v.Get("results", "0")
+	if result == nil {
+		return nil, nil
+	}
+	if e := result.GetStringBytes("error"); len(e) > 0 {
+		return nil, fmt.Errorf("query error: %s", e)
+	}
+
+	var samples []alertSample
+	for _, series := range result.GetArray("series") {
+		rows := series.GetArray("values")
+		if len(rows) == 0 {
+			continue
+		}
+		row := rows[len(rows)-1].GetArray()
+		if len(row) < 2 || row[1].Type() != fastjson.TypeNumber {
+			continue
+		}
+
+		tags := make(map[string]string)
+		keys := make([]string, 0)
+		if obj := series.GetObject("tags"); obj != nil {
+			obj.Visit(func(k []byte, v *fastjson.Value) {
+				tags[string(k)] = string(v.GetStringBytes())
+				keys = append(keys, string(k))
+			})
+		}
+		sort.Strings(keys)
+		var sb strings.Builder
+		sb.Write(series.GetStringBytes("name"))
+		for _, k := range keys {
+			sb.WriteString(",")
+			sb.WriteString(k)
+			sb.WriteString("=")
+			sb.WriteString(tags[k])
+		}
+
+		samples = append(samples, alertSample{
+			key:   sb.String(),
+			tags:  tags,
+			value: row[1].GetFloat64(),
+		})
+	}
+	return samples, nil
+}
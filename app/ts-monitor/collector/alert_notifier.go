@@ -0,0 +1,101 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+)
+
+// AlertNotifier sends the alerts which changed to firing or resolved to a sink.
+type AlertNotifier interface {
+	Notify(alerts []*Alert) error
+}
+
+// WebhookNotifier posts alerts as a JSON document: {"alerts": [...]}
+type WebhookNotifier struct {
+	url    string
+	Client HTTPClient
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		Client: &http.Client{Timeout: HttpTimeout},
+	}
+}
+
+func (n *WebhookNotifier) Notify(alerts []*Alert) error {
+	body, err := json.Marshal(map[string][]*Alert{"alerts": alerts})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = http.Header{}
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s status %d: %s", n.url, resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// FileNotifier appends alerts to a file, one JSON document per line.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Notify(alerts []*Alert) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, a := range alerts {
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	fd, err := os.OpenFile(path.Clean(n.path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	if _, err = fd.Write(buf.Bytes()); err != nil {
+		_ = fd.Close()
+		return err
+	}
+	return fd.Close()
+}
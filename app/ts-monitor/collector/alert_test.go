@@ -0,0 +1,221 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNotifier struct {
+	alerts []Alert
+}
+
+func (n *mockNotifier) Notify(alerts []*Alert) error {
+	for _, a := range alerts {
+		n.alerts = append(n.alerts, *a)
+	}
+	return nil
+}
+
+func alertQueryFn(body *string) func(r *http.Request) (*http.Response, error) {
+	return func(r *http.Request) (*http.Response, error) {
+		var buff TempBuffer
+		buff.WriteString(*body)
+		return &http.Response{StatusCode: http.StatusOK, Body: &buff}, nil
+	}
+}
+
+func TestAlertManager_Threshold(t *testing.T) {
+	conf := config.MonitorAlert{
+		Enabled:            true,
+		HttpEndpoint:       "127.0.0.1:8086",
+		Database:           "monitor",
+		EvaluationInterval: toml.Duration(time.Minute),
+		Rules: []config.AlertRule{{
+			Name:      "high_cpu",
+			Type:      config.AlertRuleThreshold,
+			Query:     "SELECT last(CpuUsage) FROM system GROUP BY host",
+			Operator:  ">",
+			Threshold: 90,
+			For:       toml.Duration(time.Minute),
+			Labels:    map[string]string{"severity": "critical"},
+		}},
+		Silences: []config.AlertSilence{{
+			Rule: "high_cpu",
+			Tags: map[string]string{"host": "h2"},
+		}},
+	}
+	am := NewAlertManager(logger.NewLogger(errno.ModuleUnknown), &conf)
+	notifier := &mockNotifier{}
+	am.Notifiers = []AlertNotifier{notifier}
+
+	body := `{"results":[{"statement_id":0,"series":[` +
+		`{"name":"system","tags":{"host":"h1"},"columns":["time","last"],"values":[["2022-01-01T00:00:00Z",95]]},` +
+		`{"name":"system","tags":{"host":"h2"},"columns":["time","last"],"values":[["2022-01-01T00:00:00Z",99.5]]},` +
+		`{"name":"system","tags":{"host":"h3"},"columns":["time","last"],"values":[["2022-01-01T00:00:00Z",10]]}]}]}`
+	am.Client = &mocks.MockClient{DoFunc: alertQueryFn(&body)}
+
+	now := time.Unix(1000, 0)
+	am.Evaluate(now)
+	alerts := am.Alerts()
+	require.Equal(t, 2, len(alerts))
+	assert.Equal(t, AlertPending, alerts[0].State)
+	assert.Equal(t, "system,host=h1", alerts[0].Series)
+	assert.Equal(t, 95.0, alerts[0].Value)
+	assert.Empty(t, notifier.alerts)
+
+	// pending for the whole "for" duration, h2 is silenced
+	am.Evaluate(now.Add(time.Minute))
+	require.Equal(t, 1, len(notifier.alerts))
+	assert.Equal(t, AlertFiring, notifier.alerts[0].State)
+	assert.Equal(t, "h1", notifier.alerts[0].Tags["host"])
+	assert.Equal(t, "critical", notifier.alerts[0].Labels["severity"])
+
+	// still firing, nothing is notified again
+	am.Evaluate(now.Add(2 * time.Minute))
+	require.Equal(t, 1, len(notifier.alerts))
+
+	body = `{"results":[{"statement_id":0,"series":[` +
+		`{"name":"system","tags":{"host":"h1"},"columns":["time","last"],"values":[["2022-01-01T00:00:00Z",20]]}]}]}`
+	am.Evaluate(now.Add(3 * time.Minute))
+	require.Equal(t, 2, len(notifier.alerts))
+	assert.Equal(t, AlertResolved, notifier.alerts[1].State)
+	assert.Empty(t, am.Alerts())
+}
+
+func TestAlertManager_AbsenceAndRate(t *testing.T) {
+	conf := config.MonitorAlert{
+		Enabled:      true,
+		HttpEndpoint: "127.0.0.1:8086",
+		Rules: []config.AlertRule{
+			{
+				Name:  "no_data",
+				Type:  config.AlertRuleAbsence,
+				Query: "SELECT count(CpuUsage) FROM system WHERE time > now() - 5m",
+			},
+			{
+				Name:      "fast_growth",
+				Type:      config.AlertRuleRate,
+				Query:     "SELECT last(IndexUsed) FROM system",
+				Operator:  ">=",
+				Threshold: 1,
+			},
+		},
+	}
+	am := NewAlertManager(logger.NewLogger(errno.ModuleUnknown), &conf)
+	notifier := &mockNotifier{}
+	am.Notifiers = []AlertNotifier{notifier}
+
+	body := `{"results":[{"statement_id":0}]}`
+	am.Client = &mocks.MockClient{DoFunc: func(r *http.Request) (*http.Response, error) {
+		resp := body
+		if strings.Contains(r.URL.RawQuery, "IndexUsed") {
+			resp = `{"results":[{"statement_id":0,"series":[{"name":"system","columns":["time","last"],"values":[[0,100]]}]}]}`
+		}
+		return alertQueryFn(&resp)(r)
+	}}
+
+	now := time.Unix(1000, 0)
+	am.Evaluate(now)
+	require.Equal(t, 1, len(notifier.alerts))
+	assert.Equal(t, "no_data", notifier.alerts[0].Rule)
+	assert.Equal(t, AlertFiring, notifier.alerts[0].State)
+
+	body = `{"results":[{"statement_id":0,"series":[{"name":"system","columns":["time","count"],"values":[[0,6]]}]}]}`
+	am.Client = &mocks.MockClient{DoFunc: func(r *http.Request) (*http.Response, error) {
+		resp := body
+		if strings.Contains(r.URL.RawQuery, "IndexUsed") {
+			resp = `{"results":[{"statement_id":0,"series":[{"name":"system","columns":["time","last"],"values":[[0,160]]}]}]}`
+		}
+		return alertQueryFn(&resp)(r)
+	}}
+	am.Evaluate(now.Add(time.Minute))
+	require.Equal(t, 3, len(notifier.alerts))
+	states := map[string]AlertState{}
+	for _, a := range notifier.alerts[1:] {
+		states[a.Rule] = a.State
+	}
+	assert.Equal(t, map[string]AlertState{"no_data": AlertResolved, "fast_growth": AlertFiring}, states)
+	assert.Equal(t, 1.0, am.Alerts()[0].Value)
+}
+
+func TestAlertManager_QueryError(t *testing.T) {
+	conf := config.MonitorAlert{
+		HttpEndpoint: "127.0.0.1:8086",
+		Rules: []config.AlertRule{{
+			Name:  "no_data",
+			Type:  config.AlertRuleAbsence,
+			Query: "SELECT count(CpuUsage) FROM system",
+		}},
+	}
+	am := NewAlertManager(logger.NewLogger(errno.ModuleUnknown), &conf)
+	notifier := &mockNotifier{}
+	am.Notifiers = []AlertNotifier{notifier}
+
+	body := `{"results":[{"statement_id":0,"error":"database not found: monitor"}]}`
+	am.Client = &mocks.MockClient{DoFunc: alertQueryFn(&body)}
+	am.Evaluate(time.Now())
+	assert.Empty(t, notifier.alerts)
+	assert.Empty(t, am.Alerts())
+}
+
+func TestAlertNotifier(t *testing.T) {
+	alerts := []*Alert{{Rule: "r1", State: AlertFiring, Value: 1}, {Rule: "r2", State: AlertResolved}}
+
+	file := filepath.Join(t.TempDir(), "alert.log")
+	fn := NewFileNotifier(file)
+	require.NoError(t, fn.Notify(alerts))
+	require.NoError(t, fn.Notify(alerts[:1]))
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Equal(t, 3, len(lines))
+	var a Alert
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &a))
+	assert.Equal(t, "r2", a.Rule)
+	assert.Equal(t, AlertResolved, a.State)
+
+	var received []byte
+	wn := NewWebhookNotifier("http://127.0.0.1:9000/hook")
+	wn.Client = &mocks.MockClient{DoFunc: func(r *http.Request) (*http.Response, error) {
+		var buff TempBuffer
+		_, _ = buff.ReadFrom(r.Body)
+		received = append(received, buff.Bytes()...)
+		return &http.Response{StatusCode: http.StatusOK, Body: &TempBuffer{}}, nil
+	}}
+	require.NoError(t, wn.Notify(alerts))
+	assert.Contains(t, string(received), `"rule":"r1","state":"firing"`)
+
+	wn.Client = &mocks.MockClient{DoFunc: func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusInternalServerError, Body: &TempBuffer{}}, nil
+	}}
+	assert.Error(t, wn.Notify(alerts))
+}
@@ -97,6 +97,7 @@ func doRun(args ...string) error {
 				}
 
 				mainCmd.Server = s
+				mainCmd.WatchConfigReload(func() config.Config { return config.NewTSMonitor() })
 				mainCmd.Logger.Info("Monitor:RunE")
 				return nil
 			},
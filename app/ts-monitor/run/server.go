@@ -37,6 +37,7 @@ type Server struct {
 	collector   *collector.Collector
 	nodeMonitor *collector.NodeCollector
 	queryMetric *collector.QueryMetric
+	alert       *collector.AlertManager
 
 	cmd    *cobra.Command
 	logger *logger.Logger
@@ -61,6 +62,9 @@ func NewServer(conf config.Config, cmd *cobra.Command, logger *logger.Logger) (a
 	s.collector.Reporter = reporterJob
 	s.nodeMonitor.Reporter = reporterJob
 	s.queryMetric.Reporter = reporterJob
+	if c.AlertConfig.Enabled {
+		s.alert = collector.NewAlertManager(logger, &c.AlertConfig)
+	}
 
 	go func() {
 		for {
@@ -97,6 +101,9 @@ func (s *Server) Open() error {
 	if s.config.QueryConfig.QueryEnable {
 		go s.queryMetric.Start()
 	}
+	if s.alert != nil {
+		go s.alert.Start()
+	}
 	return nil
 }
 
@@ -120,5 +127,8 @@ func (s *Server) Close() error {
 	if s.config.QueryConfig.QueryEnable {
 		s.queryMetric.Close()
 	}
+	if s.alert != nil {
+		s.alert.Close()
+	}
 	return nil
 }
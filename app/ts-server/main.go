@@ -113,6 +113,7 @@ func runMeta(args ...string) (*app.Command, error) {
 	if err := cmdMeta.Run(args...); err != nil {
 		return cmdMeta, err
 	}
+	cmdMeta.WatchConfigReload(func() config.Config { return config.NewTSMeta() })
 	return cmdMeta, nil
 }
 
@@ -132,6 +133,7 @@ func runSql(args ...string) (*app.Command, error) {
 	if err := cmdSql.Run(args...); err != nil {
 		return cmdSql, err
 	}
+	cmdSql.WatchConfigReload(func() config.Config { return config.NewTSSql() })
 
 	return cmdSql, nil
 }
@@ -152,6 +154,7 @@ func runStore(args ...string) (*app.Command, error) {
 	if err := cmdStore.Run(args...); err != nil {
 		return cmdStore, err
 	}
+	cmdStore.WatchConfigReload(func() config.Config { return config.NewTSStore() })
 
 	return cmdStore, nil
 }
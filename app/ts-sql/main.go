@@ -97,6 +97,7 @@ func doRun(args ...string) error {
 				}
 
 				mainCmd.Server = s
+				mainCmd.WatchConfigReload(func() config.Config { return config.NewTSSql() })
 				mainCmd.Logger.Info("Sql:RunE")
 				return nil
 			},
@@ -29,6 +29,7 @@ import (
 	"github.com/openGemini/openGemini/coordinator"
 	"github.com/openGemini/openGemini/engine/executor"
 	"github.com/openGemini/openGemini/engine/executor/spdy/transport"
+	"github.com/openGemini/openGemini/lib/audit"
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/cpu"
 	"github.com/openGemini/openGemini/lib/errno"
@@ -36,6 +37,7 @@ import (
 	"github.com/openGemini/openGemini/lib/machine"
 	meta "github.com/openGemini/openGemini/lib/metaclient"
 	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/resultcache"
 	"github.com/openGemini/openGemini/lib/statisticsPusher"
 	stat "github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 	"github.com/openGemini/openGemini/lib/syscontrol"
@@ -43,7 +45,10 @@ import (
 	coordinator2 "github.com/openGemini/openGemini/open_src/influx/coordinator"
 	"github.com/openGemini/openGemini/open_src/influx/httpd"
 	"github.com/openGemini/openGemini/open_src/influx/query"
+	auditsvc "github.com/openGemini/openGemini/services/audit"
 	"github.com/openGemini/openGemini/services/castor"
+	"github.com/openGemini/openGemini/services/graphite"
+	"github.com/openGemini/openGemini/services/opentsdb"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -73,6 +78,12 @@ type Server struct {
 	config *config.TSSql
 
 	castorService *castor.Service
+
+	graphiteService *graphite.Service
+	openTSDBService *opentsdb.Service
+	auditService    *auditsvc.Service
+
+	resultCacheRetention *coordinator2.ResultCacheRetention
 }
 
 // updateTLSConfig stores with into the tls config pointed at by into but only if with is not nil
@@ -133,6 +144,7 @@ func NewServer(conf config.Config, cmd *cobra.Command, logger *Logger.Logger) (a
 
 	s.PointsWriter = coordinator.NewPointsWriter(time.Duration(c.Coordinator.ShardWriterTimeout))
 	s.PointsWriter.TSDBStore = s.TSDBStore
+	s.PointsWriter.Cardinality = &c.Cardinality
 
 	syscontrol.SysCtrl.MetaClient = s.MetaClient
 	syscontrol.SysCtrl.NetStore = store
@@ -157,11 +169,20 @@ func NewServer(conf config.Config, cmd *cobra.Command, logger *Logger.Logger) (a
 		RetentionPolicyLimit:    c.Coordinator.RetentionPolicyLimit,
 		StmtExecLogger:          Logger.NewLogger(errno.ModuleQueryEngine).With(zap.String("query", "StatementExecutor")),
 	}
+	if c.ResultCache.Enabled {
+		cache := resultcache.NewCache(int64(c.ResultCache.MaxMemorySize), time.Duration(c.ResultCache.MaxAge))
+		s.PointsWriter.ResultCache = cache
+		executor := s.QueryExecutor.StatementExecutor.(*coordinator2.StatementExecutor)
+		executor.ResultCache = cache
+		executor.ResultCacheRecentInterval = time.Duration(c.ResultCache.RecentInterval)
+		s.resultCacheRetention = &coordinator2.ResultCacheRetention{Cache: cache}
+	}
 	s.QueryExecutor.TaskManager.QueryTimeout = time.Duration(c.Coordinator.QueryTimeout)
 	s.QueryExecutor.TaskManager.LogQueriesAfter = time.Duration(c.Coordinator.LogQueriesAfter)
 	s.QueryExecutor.TaskManager.MaxConcurrentQueries = c.Coordinator.MaxConcurrentQueries
 	s.httpService.Handler.QueryExecutor = s.QueryExecutor
 	s.httpService.Handler.ExtSysCtrl = s.TSDBStore
+	stat.SetSlowQueryThreshold(time.Duration(c.HTTP.SlowQueryTime))
 
 	s.initStatisticsPusher()
 	syscontrol.SetQueryParallel(int64(c.HTTP.ChunkReaderParallel))
@@ -170,9 +191,52 @@ func NewServer(conf config.Config, cmd *cobra.Command, logger *Logger.Logger) (a
 	machine.InitMachineID(c.HTTP.BindAddress)
 
 	s.castorService = castor.NewService(c.Analysis)
+
+	if c.Graphite.Enabled {
+		s.graphiteService, err = graphite.NewService(c.Graphite)
+		if err != nil {
+			return nil, fmt.Errorf("graphite configuration: %v", err)
+		}
+	}
+	if c.OpenTSDB.Enabled {
+		s.openTSDBService = opentsdb.NewService(c.OpenTSDB)
+	}
+	if c.Audit.Enabled && c.Audit.WriteDatabase {
+		s.auditService = auditsvc.NewService(c.Audit)
+	}
 	return s, nil
 }
 
+// RegisterReloadHandlers registers the settings of the HTTP service and the
+// coordinator which can be changed without a restart.
+func (s *Server) RegisterReloadHandlers(r *config.Reloader) {
+	r.Register(config.ReloadHandler{
+		Module: "http",
+		Keys: []string{
+			"http.max-concurrent-write-limit", "http.max-enqueued-write-limit", "http.enqueued-write-timeout",
+			"http.write-request-ratelimit", "http.max-concurrent-query-limit", "http.max-enqueued-query-limit",
+			"http.enqueued-query-timeout", "http.query-request-ratelimit", "http.slow-query-time",
+		},
+		Apply: func(conf config.Config) error {
+			c := conf.(*config.TSSql)
+			s.httpService.Handler.SetRequestLimits(c.HTTP)
+			stat.SetSlowQueryThreshold(time.Duration(c.HTTP.SlowQueryTime))
+			return nil
+		},
+	})
+	r.Register(config.ReloadHandler{
+		Module: "coordinator",
+		Keys:   []string{"coordinator.query-timeout", "coordinator.log-queries-after", "coordinator.max-concurrent-queries"},
+		Apply: func(conf config.Config) error {
+			c := conf.(*config.TSSql)
+			s.QueryExecutor.TaskManager.SetLimits(time.Duration(c.Coordinator.QueryTimeout),
+				time.Duration(c.Coordinator.LogQueriesAfter), c.Coordinator.MaxConcurrentQueries)
+			return nil
+		},
+	})
+	s.httpService.Handler.ConfigReloader = r
+}
+
 func (s *Server) Open() error {
 	// Mark start-up in log.
 	s.Logger.Info("TSSQL starting",
@@ -191,12 +255,18 @@ func (s *Server) Open() error {
 		executor.SetEnableForceBroadcastQuery(int64(1))
 	}
 
+	audit.Open(s.config.Audit, s.config.Logging)
+
 	if err := s.initializeMetaClient(); err != nil {
 		return err
 	}
 
 	s.PointsWriter.MetaClient = s.MetaClient
 	s.httpService.Handler.MetaClient = s.MetaClient
+	if s.resultCacheRetention != nil {
+		s.resultCacheRetention.MetaClient = s.MetaClient
+		s.resultCacheRetention.Open()
+	}
 
 	if err := s.httpService.Open(); err != nil {
 		return err
@@ -207,6 +277,28 @@ func (s *Server) Open() error {
 	if err := s.castorService.Open(); err != nil {
 		return err
 	}
+
+	if s.graphiteService != nil {
+		s.graphiteService.MetaClient = s.MetaClient
+		s.graphiteService.PointsWriter = s.PointsWriter
+		if err := s.graphiteService.Open(); err != nil {
+			return err
+		}
+	}
+	if s.openTSDBService != nil {
+		s.openTSDBService.MetaClient = s.MetaClient
+		s.openTSDBService.PointsWriter = s.PointsWriter
+		if err := s.openTSDBService.Open(); err != nil {
+			return err
+		}
+	}
+	if s.auditService != nil {
+		s.auditService.MetaClient = s.MetaClient
+		s.auditService.PointsWriter = s.PointsWriter
+		if err := s.auditService.Open(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -220,6 +312,14 @@ func (s *Server) Close() error {
 		util.MustClose(s.Listener)
 	}
 
+	if s.graphiteService != nil {
+		util.MustClose(s.graphiteService)
+	}
+
+	if s.openTSDBService != nil {
+		util.MustClose(s.openTSDBService)
+	}
+
 	if s.httpService != nil {
 		util.MustClose(s.httpService)
 	}
@@ -228,10 +328,19 @@ func (s *Server) Close() error {
 		util.MustClose(s.QueryExecutor)
 	}
 
+	if s.auditService != nil {
+		util.MustClose(s.auditService)
+	}
+
+	if s.resultCacheRetention != nil {
+		util.MustClose(s.resultCacheRetention)
+	}
+
 	if s.MetaClient != nil {
 		util.MustClose(s.MetaClient)
 	}
 
+	audit.Close()
 	return nil
 }
 
@@ -289,6 +398,7 @@ func (s *Server) initStatisticsPusher() {
 	stat.NewMetaStatistics().Init(globalTags)
 	stat.InitExecutorStatistics(globalTags)
 	stat.NewErrnoStat().Init(globalTags)
+	stat.InitListenerStatistics(globalTags)
 
 	s.statisticsPusher.Register(
 		stat.CollectHandlerStatistics,
@@ -298,6 +408,7 @@ func (s *Server) initStatisticsPusher() {
 		stat.NewMetaStatistics().Collect,
 		stat.CollectExecutorStatistics,
 		stat.NewErrnoStat().Collect,
+		stat.CollectListenerStatistics,
 	)
 	s.statisticsPusher.Start()
 }
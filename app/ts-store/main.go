@@ -98,6 +98,7 @@ func doRun(args ...string) error {
 				}
 
 				mainCmd.Server = s
+				mainCmd.WatchConfigReload(func() config.Config { return config.NewTSStore() })
 				mainCmd.Logger.Info("Store RunE")
 				return nil
 			},
@@ -29,8 +29,10 @@ import (
 	"github.com/openGemini/openGemini/app/ts-store/storage"
 	"github.com/openGemini/openGemini/app/ts-store/transport"
 	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/immutable"
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/cpu"
+	"github.com/openGemini/openGemini/lib/encrypt"
 	"github.com/openGemini/openGemini/lib/httpserver"
 	Logger "github.com/openGemini/openGemini/lib/logger"
 	"github.com/openGemini/openGemini/lib/metaclient"
@@ -78,6 +80,10 @@ func NewServer(c config.Config, cmd *cobra.Command, logger *Logger.Logger) (app.
 	s.config = conf
 	Logger.SetLogger(Logger.GetLogger().With(zap.String("hostname", conf.Data.IngesterAddress)))
 
+	if err := initEncryption(conf.Encryption); err != nil {
+		return nil, err
+	}
+
 	s.storageDataPath = conf.Data.DataDir
 	s.metaPath = conf.Data.MetaDir
 	s.ingestAddr = conf.Data.IngesterAddress
@@ -101,10 +107,59 @@ func NewServer(c config.Config, cmd *cobra.Command, logger *Logger.Logger) (app.
 	s.node = node
 
 	executor.SetPipelineExecutorResourceManagerParas(int64(conf.Common.MemoryLimitSize), time.Duration(conf.Common.MemoryWaitTime))
+	stat.SetSlowQueryThreshold(time.Duration(conf.HTTPD.SlowQueryTime))
 
 	return s, nil
 }
 
+// RegisterReloadHandlers registers the settings of the storage engine which can be changed without a restart.
+func (s *Server) RegisterReloadHandlers(r *config.Reloader) {
+	r.Prepare = func(c config.Config) {
+		conf := c.(*config.TSStore)
+		conf.Data.Corrector(conf.Common.CPUNum, conf.Common.MemorySize)
+	}
+	r.Register(config.ReloadHandler{
+		Module: "data",
+		Keys: []string{
+			"data.compact-throughput", "data.compact-throughput-burst",
+			"data.snapshot-throughput", "data.snapshot-throughput-burst", "data.read-cache-limit",
+		},
+		Apply: func(c config.Config) error {
+			conf := c.(*config.TSStore)
+			immutable.SetCompactLimit(int64(conf.Data.CompactThroughput), int64(conf.Data.CompactThroughputBurst))
+			immutable.SetSnapshotLimit(int64(conf.Data.SnapshotThroughput), int64(conf.Data.SnapshotThroughputBurst))
+			immutable.EnableReadCache(conf.Data.ReadCacheLimit)
+			return nil
+		},
+	})
+	r.Register(config.ReloadHandler{
+		Module: "http",
+		Keys:   []string{"http.slow-query-time"},
+		Apply: func(c config.Config) error {
+			stat.SetSlowQueryThreshold(time.Duration(c.(*config.TSStore).HTTPD.SlowQueryTime))
+			return nil
+		},
+	})
+}
+
+// initEncryption loads the keyring encrypting the files written by ts-store.
+func initEncryption(c config.Encryption) error {
+	if !c.Enabled {
+		return nil
+	}
+	keyring, err := encrypt.LoadKeyring(c.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load encryption key file: %v", err)
+	}
+	if c.ActiveKey != 0 {
+		if err = keyring.SetActive(c.ActiveKey); err != nil {
+			return fmt.Errorf("encryption active-key %d: %v", c.ActiveKey, err)
+		}
+	}
+	encrypt.SetDefaultKeyring(keyring)
+	return nil
+}
+
 // Err returns an error channel that multiplexes all out of band errors received from all services.
 func (s *Server) Err() <-chan error { return s.err }
 
@@ -163,6 +218,8 @@ func (s *Server) Open() error {
 
 	if s.config.Gossip.Enabled {
 		conf := s.config.Gossip.BuildSerf(s.config.Logging, config.AppStore, strconv.Itoa(int(nid)), nil)
+		// the meta nodes do not move the pts of the engines which can not move them
+		conf.Tags["engine"] = s.config.Data.Engine
 		s.serfInstance, err = app.CreateSerfInstance(conf, clock, s.config.Gossip.Members, nil)
 	}
 	s.initStatisticsPusher()
@@ -222,6 +279,9 @@ func (s *Server) initStatisticsPusher() {
 	stat.InitExecutorStatistics(globalTags)
 	stat.InitFileStatistics(globalTags)
 	stat.NewErrnoStat().Init(globalTags)
+	stat.NewCardinalityStat().Init(globalTags)
+	stat.NewReadStatistics().Init(globalTags)
+	stat.NewIndexGCStatistics().Init(globalTags)
 
 	s.statisticsPusher.Register(
 		stat.CollectPerfStatistics,
@@ -235,6 +295,9 @@ func (s *Server) initStatisticsPusher() {
 		stat.CollectEngineStatStatistics,
 		stat.CollectExecutorStatistics,
 		s.storage.GetEngine().Statistics,
-		stat.NewErrnoStat().Collect)
+		stat.NewErrnoStat().Collect,
+		stat.NewCardinalityStat().Collect,
+		stat.NewReadStatistics().Collect,
+		stat.NewIndexGCStatistics().Collect)
 	s.statisticsPusher.Start()
 }
@@ -17,10 +17,14 @@ limitations under the License.
 package run
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/openGemini/openGemini/app"
 	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/encrypt"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInitStatisticsPusher(t *testing.T) {
@@ -31,3 +35,22 @@ func TestInitStatisticsPusher(t *testing.T) {
 	app.SwitchToSingle()
 	server.initStatisticsPusher()
 }
+
+func TestInitEncryption(t *testing.T) {
+	c := config.NewEncryption()
+	require.NoError(t, initEncryption(c))
+	require.Nil(t, encrypt.DefaultKeyring())
+
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(
+		"1 000102030405060708090a0b0c0d0e0f\n2 0f0e0d0c0b0a09080706050403020100\n"), 0600))
+	c.Enabled = true
+	c.KeyFile = keyFile
+	c.ActiveKey = 3
+	require.Error(t, initEncryption(c))
+
+	c.ActiveKey = 1
+	require.NoError(t, initEncryption(c))
+	defer encrypt.SetDefaultKeyring(nil)
+	require.Equal(t, uint32(1), encrypt.DefaultKeyring().Active())
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"go.uber.org/zap"
+)
+
+// MigratePt executes one step of moving a db pt between the store nodes. The steps are
+// serialized, a step retried by the meta node waits for the previous try to finish.
+func (s *Storage) MigratePt(req *netstorage.MigratePtRequest) error {
+	s.migrateMu.Lock()
+	defer s.migrateMu.Unlock()
+
+	s.log.Info("migrate pt", zap.Uint8("type", uint8(req.Type)), zap.String("db", req.Db),
+		zap.Uint32("pt", req.PtId), zap.Uint64("opId", req.OpId), zap.Uint64("src", req.SrcNodeId))
+
+	switch req.Type {
+	case netstorage.MigratePtPreOffload:
+		return s.engine.PreOffloadDBPT(req.Db, req.PtId)
+	case netstorage.MigratePtRollbackPreOffload:
+		return s.engine.LoadDBPT(req.Db, req.PtId, req.DurationInfos())
+	case netstorage.MigratePtPreAssign:
+		return s.receivePt(req)
+	case netstorage.MigratePtOffload:
+		return s.engine.OffloadDBPT(req.Db, req.PtId)
+	case netstorage.MigratePtAssign:
+		if err := s.engine.LoadDBPT(req.Db, req.PtId, req.DurationInfos()); err != nil {
+			return err
+		}
+		// the shard durations of the pt are updated from now on
+		s.metaClient.AddPt(req.PtId)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate pt type %d", req.Type)
+	}
+}
+
+// receivePt copies the files of the pt from the src node, which has pre offloaded it
+func (s *Storage) receivePt(req *netstorage.MigratePtRequest) error {
+	node, err := s.metaClient.DataNode(req.SrcNodeId)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := s.netStore.PtFiles(node, &netstorage.PtFilesRequest{Db: req.Db, PtId: req.PtId})
+	if err != nil {
+		return err
+	}
+
+	return s.engine.ReceiveDBPT(req.Db, req.PtId, rsp.Files, func(name string, offset int64, size int) ([]byte, error) {
+		rsp, err := s.netStore.PtFiles(node, &netstorage.PtFilesRequest{
+			Db:     req.Db,
+			PtId:   req.PtId,
+			Name:   name,
+			Offset: offset,
+			Size:   uint32(size),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return rsp.Data, nil
+	})
+}
+
+// PtFiles lists the files of a pre offloaded pt or reads a chunk of one of them
+func (s *Storage) PtFiles(req *netstorage.PtFilesRequest, rsp *netstorage.PtFilesResponse) error {
+	var err error
+	if req.Name == "" {
+		rsp.Files, err = s.engine.DBPTFiles(req.Db, req.PtId)
+		return err
+	}
+	rsp.Data, err = s.engine.ReadDBPTFile(req.Db, req.PtId, req.Name, req.Offset, int(req.Size))
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -27,8 +28,10 @@ import (
 	"github.com/influxdata/influxdb/pkg/limiter"
 	retention2 "github.com/influxdata/influxdb/services/retention"
 	"github.com/openGemini/openGemini/engine"
+	_ "github.com/openGemini/openGemini/engine/columnstore" // registers the tssp2 engine
 	"github.com/openGemini/openGemini/engine/executor"
 	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/engine/index/tsi"
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/lib/logger"
@@ -83,6 +86,10 @@ type Storage struct {
 	loadCtx *metaclient.LoadCtx
 
 	WriteLimit limiter.Fixed
+
+	// moves the pts from or to the other store nodes
+	netStore  netstorage.Storage
+	migrateMu sync.Mutex
 }
 
 func (s *Storage) GetPath() string {
@@ -132,28 +139,8 @@ func OpenStorage(path string, node *metaclient.Node, cli *metaclient.Client, con
 
 	loadCtx := metaclient.LoadCtx{}
 	loadCtx.LoadCh = make(chan *metaclient.DBPTCtx)
-	opt := netstorage.NewEngineOptions()
-	opt.ImmTableMaxMemoryPercentage = conf.Data.ImmTableMaxMemoryPercentage
-	opt.WriteColdDuration = time.Duration(conf.Data.WriteColdDuration)
-	opt.ShardMutableSizeLimit = int64(conf.Data.ShardMutableSizeLimit)
-	opt.NodeMutableSizeLimit = int64(conf.Data.NodeMutableSizeLimit)
-	opt.MaxWriteHangTime = time.Duration(conf.Data.MaxWriteHangTime)
-	opt.CompactThroughput = int64(conf.Data.CompactThroughput)
-	opt.CompactThroughputBurst = int64(conf.Data.CompactThroughputBurst)
-	opt.CompactRecovery = conf.Data.CompactRecovery
-	opt.SnapshotThroughput = int64(conf.Data.SnapshotThroughput)
-	opt.SnapshotThroughputBurst = int64(conf.Data.SnapshotThroughputBurst)
-	opt.MaxConcurrentCompactions = conf.Data.MaxConcurrentCompactions
-	opt.MaxFullCompactions = conf.Data.MaxFullCompactions
-	opt.FullCompactColdDuration = time.Duration(conf.Data.CompactFullWriteColdDuration)
-	opt.CacheDataBlock = conf.Data.CacheDataBlock
-	opt.CacheMetaBlock = conf.Data.CacheMetaBlock
-	opt.EnableMmapRead = conf.Data.EnableMmapRead
-	opt.ReadCacheLimit = conf.Data.ReadCacheLimit
-	opt.WalSyncInterval = time.Duration(conf.Data.WalSyncInterval)
-	opt.WalEnabled = conf.Data.WalEnabled
-	opt.WalReplayParallel = conf.Data.WalReplayParallel
-	opt.CompactionMethod = conf.Data.CompactionMethod
+	opt := netstorage.NewEngineOptionsWithConfig(&conf.Data)
+	tsi.SetCardinalityLimits(&conf.Data)
 
 	eng, err := newEngineFn(conf.Data.DataDir, conf.Data.WALDir, opt, &loadCtx)
 	if err != nil {
@@ -172,6 +159,7 @@ func OpenStorage(path string, node *metaclient.Node, cli *metaclient.Client, con
 		stop:       make(chan struct{}),
 		loadCtx:    &loadCtx,
 		WriteLimit: limiter.NewFixed(conf.Data.WriteConcurrentLimit),
+		netStore:   netstorage.NewNetStorage(cli),
 	}
 
 	s.log = logger.NewLogger(errno.ModuleStorageEngine)
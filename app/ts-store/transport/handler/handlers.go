@@ -44,6 +44,10 @@ func newHandler(typ uint8) RPCHandler {
 		return &Delete{}
 	case netstorage.CreateDataBaseRequestMessage:
 		return &CreateDataBase{}
+	case netstorage.MigratePtRequestMessage:
+		return &MigratePt{}
+	case netstorage.PtFilesRequestMessage:
+		return &PtFiles{}
 	default:
 		return nil
 	}
@@ -184,3 +188,37 @@ func (h *CreateDataBase) SetMessage(msg codec.BinaryCodec) error {
 	h.req = req
 	return nil
 }
+
+type MigratePt struct {
+	BaseHandler
+
+	req *netstorage.MigratePtRequest
+	rsp *netstorage.MigratePtResponse
+}
+
+func (h *MigratePt) SetMessage(msg codec.BinaryCodec) error {
+	h.rsp = &netstorage.MigratePtResponse{}
+	req, ok := msg.(*netstorage.MigratePtRequest)
+	if !ok {
+		return executor.NewInvalidTypeError("*netstorage.MigratePtRequest", msg)
+	}
+	h.req = req
+	return nil
+}
+
+type PtFiles struct {
+	BaseHandler
+
+	req *netstorage.PtFilesRequest
+	rsp *netstorage.PtFilesResponse
+}
+
+func (h *PtFiles) SetMessage(msg codec.BinaryCodec) error {
+	h.rsp = &netstorage.PtFilesResponse{}
+	req, ok := msg.(*netstorage.PtFilesRequest)
+	if !ok {
+		return executor.NewInvalidTypeError("*netstorage.PtFilesRequest", msg)
+	}
+	h.req = req
+	return nil
+}
@@ -155,3 +155,17 @@ func createDir(dataPath, db string, pt uint32, rp string) error {
 
 	return nil
 }
+
+func (h *MigratePt) Process() (codec.BinaryCodec, error) {
+	if err := h.store.MigratePt(h.req); err != nil {
+		h.rsp.Err = err.Error()
+	}
+	return h.rsp, nil
+}
+
+func (h *PtFiles) Process() (codec.BinaryCodec, error) {
+	if err := h.store.PtFiles(h.req, h.rsp); err != nil {
+		h.rsp.Err = err.Error()
+	}
+	return h.rsp, nil
+}
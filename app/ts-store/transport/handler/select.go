@@ -314,7 +314,7 @@ func (s *Select) startDuration(db string, query string) *statistics.StoreSlowQue
 
 func (s *Select) finishDuration(qd *statistics.StoreSlowQueryStatistics, start time.Time) {
 	d := time.Since(start).Nanoseconds()
-	if d > time.Second.Nanoseconds()*10 {
+	if d > statistics.SlowQueryThreshold().Nanoseconds() {
 		qd.AddDuration("TotalDuration", d)
 		statistics.AppendStoreQueryDuration(qd)
 	}
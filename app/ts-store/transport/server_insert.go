@@ -24,6 +24,7 @@ import (
 	"github.com/openGemini/openGemini/engine/executor"
 	"github.com/openGemini/openGemini/engine/executor/spdy"
 	"github.com/openGemini/openGemini/engine/executor/spdy/transport"
+	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/lib/netstorage"
 	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 )
@@ -93,9 +94,19 @@ func (p *InsertProcessor) Handle(w spdy.Responser, data interface{}) error {
 	err := ww.WritePoints()
 	putWritePointsWork(ww)
 
-	rsp := netstorage.NewWritePointsResponse(0, "")
-	if err != nil {
-		rsp = netstorage.NewWritePointsResponse(1, err.Error())
+	rsp := netstorage.NewWritePointsResponse(netstorage.WritePointsOK, "")
+	werr, ok := err.(netstorage.PartialWriteError)
+	if ok && w.Session().PeerVersion() < spdy.SessionVersion {
+		// the ts-sql of the older versions can not parse a partial write
+		rsp = netstorage.NewWritePointsResponse(netstorage.WritePointsFailed, werr.Error())
+	} else if ok {
+		rsp = netstorage.NewWritePointsResponse(netstorage.WritePointsPartial, werr.Reason.Error())
+		rsp.Dropped = uint32(werr.Dropped)
+		if e, ok := werr.Reason.(*errno.Error); ok {
+			rsp.Errno = uint16(e.Errno())
+		}
+	} else if err != nil {
+		rsp = netstorage.NewWritePointsResponse(netstorage.WritePointsFailed, err.Error())
 	}
 
 	return w.Response(rsp, true)
@@ -28,9 +28,11 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
 	"github.com/gogo/protobuf/proto"
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/lib/logger"
 	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/resultcache"
 	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 	strings2 "github.com/openGemini/openGemini/lib/strings"
 	"github.com/openGemini/openGemini/open_src/github.com/savsgio/dictpool"
@@ -62,6 +64,12 @@ type PointsWriter struct {
 		WriteRows(nodeID uint64, database, rp string, pt uint32, shard uint64, rows *[]influx.Row, timeout time.Duration) error
 	}
 
+	// ResultCache holds the query results invalidated by the written points, nil if disabled.
+	ResultCache *resultcache.Cache
+
+	// Cardinality is the cardinality limits of the stores, nil if the points are only checked by the stores.
+	Cardinality *config.Cardinality
+
 	logger *logger.Logger
 }
 
@@ -73,6 +81,102 @@ func NewPointsWriter(timeout time.Duration) *PointsWriter {
 	}
 }
 
+// cardinalityCheck drops the points of a write request which can not fit in the stores. A store holds
+// at most the limits of a database, so all the nodes of the database hold at most limit*nodes series,
+// series of a measurement or values of a tag key. The new series of a request beyond that are certainly
+// rejected, they are dropped before they are sent and the stores check the rest.
+type cardinalityCheck struct {
+	db        string
+	limit     config.CardinalityLimit
+	nodes     int
+	series    map[string]struct{}
+	mstSeries map[string]int
+	tagValues map[string]map[string]struct{} // measurement and tag key -> values
+	key       []byte
+}
+
+func newCardinalityCheck(db string, limit config.CardinalityLimit, ptView meta2.DBPtInfos) *cardinalityCheck {
+	nodes := make(map[uint64]struct{})
+	for i := range ptView {
+		nodes[ptView[i].Owner.NodeID] = struct{}{}
+	}
+	return &cardinalityCheck{
+		db:        db,
+		limit:     limit,
+		nodes:     len(nodes),
+		series:    make(map[string]struct{}),
+		mstSeries: make(map[string]int),
+		tagValues: make(map[string]map[string]struct{}),
+	}
+}
+
+// check returns an error if the series of r is new and exceeds the limits, otherwise the series is counted.
+func (c *cardinalityCheck) check(r *influx.Row) error {
+	c.key = append(c.key[:0], r.Name...)
+	for i := range r.Tags {
+		c.key = append(c.key, 0)
+		c.key = append(c.key, r.Tags[i].Key...)
+		c.key = append(c.key, 0)
+		c.key = append(c.key, r.Tags[i].Value...)
+	}
+	if _, ok := c.series[string(c.key)]; ok {
+		return nil
+	}
+
+	if max := c.limit.MaxSeries * c.nodes; c.limit.MaxSeries > 0 && len(c.series) >= max {
+		return errno.NewError(errno.SeriesLimitExceeded, c.db, r.Name, len(c.series), max)
+	}
+	if max := c.limit.MaxSeriesPerMeasurement * c.nodes; c.limit.MaxSeriesPerMeasurement > 0 && c.mstSeries[r.Name] >= max {
+		return errno.NewError(errno.SeriesLimitExceeded, c.db, r.Name, c.mstSeries[r.Name], max)
+	}
+	if c.limit.MaxValuesPerTag > 0 {
+		max := c.limit.MaxValuesPerTag * c.nodes
+		for i := range r.Tags {
+			values := c.tagValues[r.Name+"\x00"+r.Tags[i].Key]
+			if _, ok := values[r.Tags[i].Value]; !ok && len(values) >= max {
+				return errno.NewError(errno.TagValuesLimitExceeded, c.db, r.Name, r.Tags[i].Key, len(values), max)
+			}
+		}
+		for i := range r.Tags {
+			key := r.Name + "\x00" + r.Tags[i].Key
+			values, ok := c.tagValues[key]
+			if !ok {
+				values = make(map[string]struct{})
+				c.tagValues[key] = values
+			}
+			values[r.Tags[i].Value] = struct{}{}
+		}
+	}
+
+	c.series[string(c.key)] = struct{}{}
+	c.mstSeries[r.Name]++
+	return nil
+}
+
+// invalidateResultCache drops the cached query results of the time ranges of
+// the rows, for each measurement.
+func invalidateResultCache(cache *resultcache.Cache, database string, rows []influx.Row) {
+	type timeRange struct{ min, max int64 }
+	ranges := make(map[string]*timeRange)
+	for i := range rows {
+		r := &rows[i]
+		tr, ok := ranges[r.Name]
+		if !ok {
+			ranges[r.Name] = &timeRange{min: r.Timestamp, max: r.Timestamp}
+			continue
+		}
+		if r.Timestamp < tr.min {
+			tr.min = r.Timestamp
+		}
+		if r.Timestamp > tr.max {
+			tr.max = r.Timestamp
+		}
+	}
+	for name, tr := range ranges {
+		cache.Invalidate(database, name, tr.min, tr.max)
+	}
+}
+
 // ShardMapping contains a mapping of shards to points.
 type injestionCtx struct {
 	fieldToCreatePool []*proto2.FieldSchema
@@ -259,6 +363,11 @@ func (w *PointsWriter) WritePointRows(database, retentionPolicy string, rows []i
 		min = int64(fasttime.UnixTimestamp()*1e9) - rp.Duration.Nanoseconds()
 	}
 
+	if w.ResultCache != nil {
+		// invalidate after the points are written, so no query caches the results without them
+		defer invalidateResultCache(w.ResultCache, database, rows)
+	}
+
 	ctx := getInjestionCtx()
 	defer putInjestionCtx(ctx)
 
@@ -268,6 +377,17 @@ func (w *PointsWriter) WritePointRows(database, retentionPolicy string, rows []i
 	var partialErr error
 	var dropped int
 
+	var cc *cardinalityCheck
+	if w.Cardinality != nil {
+		if limit := w.Cardinality.CardinalityLimit(database); limit.Enabled() {
+			ptView, err := w.MetaClient.DBPtView(database)
+			if err != nil {
+				return err
+			}
+			cc = newCardinalityCheck(database, limit, ptView)
+		}
+	}
+
 	//validate, map and push point to bach transport buffer
 	for i := range rows {
 		r := &rows[i]
@@ -287,6 +407,14 @@ func (w *PointsWriter) WritePointRows(database, retentionPolicy string, rows []i
 			continue
 		}
 
+		if cc != nil {
+			if err := cc.check(r); err != nil {
+				partialErr = err
+				dropped++
+				continue
+			}
+		}
+
 		start := time.Now()
 		mst, err := w.MetaClient.Measurement(database, retentionPolicy, r.Name)
 		if err == meta2.ErrMeasurementNotFound {
@@ -420,6 +548,12 @@ func (w *PointsWriter) WritePointRows(database, retentionPolicy string, rows []i
 
 	for i := 0; i < shardrowmap.Len(); i++ {
 		errShard := <-errC
+		if werr, ok := errShard.(netstorage.PartialWriteError); ok {
+			// series rejected by the cardinality limits of the store
+			partialErr = werr.Reason
+			dropped += werr.Dropped
+			continue
+		}
 		if errShard != nil {
 			err = errShard
 		}
@@ -25,7 +25,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/netstorage"
 	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
 	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
 	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
@@ -184,6 +186,70 @@ func TestPointsWriter_WritePointRows(t *testing.T) {
 	}
 }
 
+func TestPointsWriter_WritePointRows_CardinalityLimit(t *testing.T) {
+	pw := NewPointsWriter(time.Second)
+	pw.MetaClient = NewMockMetaClient()
+	store := NewMockNetStore()
+	store.WriteRowsFn = func(nodeID uint64, database, rp string, pt uint32, shard uint64, rows *[]influx.Row, timeout time.Duration) error {
+		return netstorage.PartialWriteError{
+			Reason:  errno.NewError(errno.SeriesLimitExceeded, database, "mst0", 10, 10),
+			Dropped: 2,
+		}
+	}
+	pw.TSDBStore = store
+
+	err := pw.WritePointRows("db0", "rp0", generateRows())
+	werr, ok := err.(netstorage.PartialWriteError)
+	if !ok {
+		t.Fatalf("expect partial write error, got: %v", err)
+	}
+	assert.Equal(t, 2, werr.Dropped)
+	assert.True(t, errno.Equal(werr.Reason, errno.SeriesLimitExceeded))
+}
+
+func TestPointsWriter_WritePointRows_CardinalityPrecheck(t *testing.T) {
+	pw := NewPointsWriter(time.Second)
+	pw.MetaClient = NewMockMetaClient()
+	store := NewMockNetStore()
+	var written int
+	store.WriteRowsFn = func(nodeID uint64, database, rp string, pt uint32, shard uint64, rows *[]influx.Row, timeout time.Duration) error {
+		written += len(*rows)
+		return nil
+	}
+	pw.TSDBStore = store
+
+	// the series and the tag values beyond the limits of the only node are dropped before they are sent
+	for _, c := range []struct {
+		limit config.Cardinality
+		code  errno.Errno
+	}{
+		{config.Cardinality{MaxSeriesPerDatabase: 1}, errno.SeriesLimitExceeded},
+		{config.Cardinality{MaxSeriesPerMeasurement: 1}, errno.SeriesLimitExceeded},
+		{config.Cardinality{MaxValuesPerTag: 1}, errno.TagValuesLimitExceeded},
+	} {
+		written = 0
+		pw.Cardinality = &c.limit
+		rows := generateRows()
+		// the points of a series already in the request are kept
+		rows = append(rows, generateRows()[0])
+
+		err := pw.WritePointRows("db0", "rp0", rows)
+		werr, ok := err.(netstorage.PartialWriteError)
+		if !ok {
+			t.Fatalf("expect partial write error, got: %v", err)
+		}
+		assert.Equal(t, 1, werr.Dropped)
+		assert.True(t, errno.Equal(werr.Reason, c.code))
+		assert.Equal(t, 2, written)
+	}
+
+	// a limit of another database
+	written = 0
+	pw.Cardinality = &config.Cardinality{CardinalityLimits: []config.CardinalityLimit{{Database: "db1", MaxSeries: 1}}}
+	assert.NoError(t, pw.WritePointRows("db0", "rp0", generateRows()))
+	assert.Equal(t, 2, written)
+}
+
 func TestPointsWriter_updateSchemaIfNeeded(t *testing.T) {
 	mi := &meta2.MeasurementInfo{
 		Name:      "mst",
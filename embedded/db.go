@@ -0,0 +1,332 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package embedded runs openGemini inside the calling process, without
+// ts-meta, ts-sql or ts-store. The meta data is kept by the library itself,
+// the points are written and queried through the storage engine and the
+// query executor directly, and the data files have the same format as the
+// ones of a ts-store.
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	query2 "github.com/influxdata/influxdb/query"
+	"github.com/openGemini/openGemini/coordinator"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/yacc"
+	"go.uber.org/zap"
+)
+
+const defaultWriteTimeout = 10 * time.Second
+
+// ErrClosed is returned when the database is used after it is closed.
+var ErrClosed = errors.New("embedded database is closed")
+
+// Options configures an embedded database.
+type Options struct {
+	// Data is the configuration of the storage engine, the same as the [data]
+	// section of ts-store. The directories are set by Open.
+	Data config.Store
+
+	// WriteTimeout is the timeout of writing the points of a shard.
+	WriteTimeout time.Duration
+}
+
+// DefaultOptions returns the options of a ts-store with default configuration.
+func DefaultOptions() Options {
+	return Options{
+		Data:         config.NewStore(),
+		WriteTimeout: defaultWriteTimeout,
+	}
+}
+
+// DB is an embedded database. It is safe for concurrent use.
+type DB struct {
+	mu     sync.RWMutex
+	closed bool
+
+	meta     *metaStore
+	store    *localStore
+	writer   *coordinator.PointsWriter
+	executor *query.Executor
+}
+
+// Open opens the embedded database in dir, creating it if needed. The data
+// files are kept in dir/data, the wal in dir/wal and the meta data in dir/meta.
+func Open(dir string, opts Options) (*DB, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine absolute path for %q: %w", dir, err)
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = defaultWriteTimeout
+	}
+	opts.Data.DataDir = dir
+	opts.Data.WALDir = dir
+	opts.Data.MetaDir = filepath.Join(dir, "meta")
+	// the limits left zero are derived from the resources of the machine, as ts-store does
+	opts.Data.Corrector(0, 0)
+
+	ms, err := openMetaStore(opts.Data.MetaDir)
+	if err != nil {
+		return nil, fmt.Errorf("open meta: %s", err)
+	}
+	store, err := openLocalStore(&opts.Data, ms)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := coordinator.NewPointsWriter(opts.WriteTimeout)
+	writer.MetaClient = ms
+	writer.TSDBStore = store
+
+	log := logger.NewLogger(errno.ModuleQueryEngine).With(zap.String("service", "embedded"))
+	exec := query.NewExecutor()
+	exec.StatementExecutor = &statementExecutor{
+		meta:   ms,
+		mapper: &localShardMapper{meta: ms, store: store},
+		logger: log,
+	}
+
+	return &DB{
+		meta:     ms,
+		store:    store,
+		writer:   writer,
+		executor: exec,
+	}, nil
+}
+
+// Close kills the running queries and closes the storage engine.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return nil
+	}
+	db.closed = true
+
+	if err := db.executor.Close(); err != nil {
+		return err
+	}
+	return db.store.Close()
+}
+
+// CreateDatabase creates a database with the default retention policy autogen.
+// Creating an existing database is a no-op.
+func (db *DB) CreateDatabase(name string) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return ErrClosed
+	}
+	return db.meta.CreateDatabase(name)
+}
+
+// Point is a point written to an embedded database. Field values can be
+// float64, int64, int, uint64, string or bool.
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{}
+	Time        time.Time
+}
+
+// WritePoints writes points to the default retention policy of database.
+// Points rejected by the engine, like the ones with a field type conflict,
+// are reported by a netstorage.PartialWriteError, the other points are written.
+// As on a ts-store, new series are visible to queries once the index is
+// flushed, which happens every second or on Flush.
+func (db *DB) WritePoints(database string, points []Point) error {
+	rows := make([]influx.Row, len(points))
+	for i := range points {
+		if err := points[i].toRow(&rows[i]); err != nil {
+			return err
+		}
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return ErrClosed
+	}
+	return db.writer.WritePointRows(database, "", rows)
+}
+
+// Flush writes the in-memory data of all shards to TSSP files and flushes
+// the series index, everything written before is visible to queries after it.
+func (db *DB) Flush() error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return ErrClosed
+	}
+	db.store.engine.ForceFlush()
+	return nil
+}
+
+func (p *Point) toRow(r *influx.Row) error {
+	r.Name = p.Measurement
+	r.Timestamp = p.Time.UnixNano()
+	if p.Time.IsZero() {
+		r.Timestamp = time.Now().UnixNano()
+	}
+
+	r.Tags = make(influx.PointTags, 0, len(p.Tags))
+	for k, v := range p.Tags {
+		r.Tags = append(r.Tags, influx.Tag{Key: k, Value: v})
+	}
+	sort.Sort(&r.Tags)
+
+	r.Fields = make(influx.Fields, 0, len(p.Fields))
+	for k, v := range p.Fields {
+		f := influx.Field{Key: k}
+		switch v := v.(type) {
+		case float64:
+			f.Type, f.NumValue = influx.Field_Type_Float, v
+		case float32:
+			f.Type, f.NumValue = influx.Field_Type_Float, float64(v)
+		case int64:
+			f.Type, f.NumValue = influx.Field_Type_Int, float64(v)
+		case int:
+			f.Type, f.NumValue = influx.Field_Type_Int, float64(v)
+		case int32:
+			f.Type, f.NumValue = influx.Field_Type_Int, float64(v)
+		case uint64:
+			f.Type, f.NumValue = influx.Field_Type_UInt, float64(v)
+		case string:
+			f.Type, f.StrValue = influx.Field_Type_String, v
+		case bool:
+			f.Type = influx.Field_Type_Boolean
+			if v {
+				f.NumValue = 1
+			}
+		default:
+			return fmt.Errorf("unsupported type %T of field %q", v, k)
+		}
+		r.Fields = append(r.Fields, f)
+	}
+	sort.Sort(r.Fields)
+	return r.CheckValid()
+}
+
+// Query executes the InfluxQL statements of q against database, which is
+// the default of the measurements without one. The results are streamed by
+// the returned cursor, which must be closed.
+func (db *DB) Query(ctx context.Context, database string, q string) (*Cursor, error) {
+	p := influxql.NewParser(strings.NewReader(q))
+	yaccParser := yacc.NewYyParser(p.GetScanner())
+	yaccParser.ParseTokens()
+	stmts, err := yaccParser.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return nil, ErrClosed
+	}
+
+	c := &Cursor{closing: make(chan struct{})}
+	opts := query.ExecutionOptions{
+		Database:       database,
+		ChunkSize:      defaultChunkSize,
+		InnerChunkSize: defaultInnerChunkSize,
+		Quiet:          true,
+		AbortCh:        c.closing,
+	}
+	var qStat *statistics.SQLSlowQueryStatistics
+	c.results = db.executor.ExecuteQuery(stmts, opts, c.closing, qStat)
+
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				c.abort()
+			case <-c.closing:
+			}
+		}()
+	}
+	return c, nil
+}
+
+// Cursor iterates the results of a query, one result for each statement or,
+// for the selects returning many rows, for each chunk of rows.
+type Cursor struct {
+	results <-chan *query2.Result
+	closing chan struct{}
+	once    sync.Once
+	result  *query2.Result
+	err     error
+}
+
+// Next advances to the next result, it returns false once all the results
+// are read or the query failed.
+func (c *Cursor) Next() bool {
+	if c.err != nil {
+		return false
+	}
+	r, ok := <-c.results
+	if !ok {
+		c.result = nil
+		return false
+	}
+	if r.Err != nil {
+		c.err = r.Err
+		c.result = nil
+		return false
+	}
+	c.result = r
+	return true
+}
+
+// Result returns the current result.
+func (c *Cursor) Result() *query2.Result {
+	return c.result
+}
+
+// Err returns the error of the query, if any.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Close stops the query and releases its resources.
+func (c *Cursor) Close() error {
+	c.abort()
+	for range c.results {
+	}
+	return nil
+}
+
+func (c *Cursor) abort() {
+	c.once.Do(func() {
+		close(c.closing)
+	})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package embedded
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queryRows(t *testing.T, db *DB, database, q string) models.Rows {
+	cur, err := db.Query(context.Background(), database, q)
+	require.NoError(t, err)
+	defer cur.Close()
+
+	var rows models.Rows
+	for cur.Next() {
+		rows = append(rows, cur.Result().Series...)
+	}
+	require.NoError(t, cur.Err())
+	return rows
+}
+
+func writeTestPoints(t *testing.T, db *DB, start time.Time, n int) {
+	points := make([]Point, 0, n*2)
+	for i := 0; i < n; i++ {
+		for _, host := range []string{"h1", "h2"} {
+			points = append(points, Point{
+				Measurement: "cpu",
+				Tags:        map[string]string{"host": host},
+				Fields:      map[string]interface{}{"value": float64(i), "count": int64(i)},
+				Time:        start.Add(time.Duration(i) * time.Second),
+			})
+		}
+	}
+	require.NoError(t, db.WritePoints("db0", points))
+	require.NoError(t, db.Flush())
+}
+
+func TestDB_WriteAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(dir, DefaultOptions())
+	require.NoError(t, err)
+
+	require.NoError(t, db.CreateDatabase("db0"))
+	start := time.Now().Truncate(time.Hour)
+	writeTestPoints(t, db, start, 10)
+
+	rows := queryRows(t, db, "", "SHOW DATABASES")
+	require.Len(t, rows, 1)
+	assert.Equal(t, [][]interface{}{{"db0"}}, rows[0].Values)
+
+	rows = queryRows(t, db, "db0", "SHOW MEASUREMENTS")
+	require.Len(t, rows, 1)
+	assert.Equal(t, [][]interface{}{{"cpu"}}, rows[0].Values)
+
+	check := func() {
+		rows := queryRows(t, db, "db0", "SELECT count(value), sum(count) FROM cpu GROUP BY host")
+		require.Len(t, rows, 2)
+		for _, row := range rows {
+			assert.Equal(t, []string{"time", "count", "sum"}, row.Columns)
+			require.Len(t, row.Values, 1)
+			assert.Equal(t, int64(10), row.Values[0][1])
+			assert.Equal(t, int64(45), row.Values[0][2])
+		}
+		assert.Equal(t, map[string]string{"host": "h1"}, rows[0].Tags)
+
+		rows = queryRows(t, db, "db0", "SELECT value FROM cpu WHERE host = 'h2' AND value >= 8")
+		require.Len(t, rows, 1)
+		assert.Equal(t, [][]interface{}{
+			{start.Add(8 * time.Second).UTC(), float64(8)},
+			{start.Add(9 * time.Second).UTC(), float64(9)},
+		}, rows[0].Values)
+	}
+	check()
+
+	// the data and the meta data survive a restart
+	require.NoError(t, db.Close())
+	db, err = Open(dir, DefaultOptions())
+	require.NoError(t, err)
+	defer db.Close()
+	check()
+
+	writeTestPoints(t, db, start.Add(time.Minute), 1)
+	rows = queryRows(t, db, "db0", "SELECT count(value) FROM cpu")
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(22), rows[0].Values[0][1])
+}
+
+func TestDB_Errors(t *testing.T) {
+	db, err := Open(t.TempDir(), DefaultOptions())
+	require.NoError(t, err)
+
+	err = db.WritePoints("db0", []Point{{Measurement: "cpu", Fields: map[string]interface{}{"value": 1.0}}})
+	assert.Error(t, err, "database not found")
+
+	require.NoError(t, db.CreateDatabase("db0"))
+	err = db.WritePoints("db0", []Point{{Measurement: "cpu", Fields: map[string]interface{}{"value": []int{1}}}})
+	assert.EqualError(t, err, `unsupported type []int of field "value"`)
+
+	cur, err := db.Query(context.Background(), "db0", "DROP DATABASE db0")
+	require.NoError(t, err)
+	assert.False(t, cur.Next())
+	assert.Error(t, cur.Err())
+	require.NoError(t, cur.Close())
+
+	_, err = db.Query(context.Background(), "db0", "SELECT FROM")
+	assert.Error(t, err)
+
+	require.NoError(t, db.Close())
+	assert.Equal(t, ErrClosed, db.CreateDatabase("db1"))
+	_, err = db.Query(context.Background(), "db0", "SHOW DATABASES")
+	assert.Equal(t, ErrClosed, err)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package embedded
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/open_src/github.com/hashicorp/serf/serf"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+const (
+	metaFile = "meta.data"
+
+	// all the data of an embedded database lives on one node with a single partition
+	localNodeID = 1
+	localPtID   = 0
+)
+
+// metaStore keeps the meta data of an embedded database in process and
+// persists it on every change, it stands in for ts-meta and the meta client.
+// Changes are applied to a clone which then replaces the current data, so
+// the infos handed out to readers are never modified.
+type metaStore struct {
+	mu   sync.RWMutex
+	path string
+	data *meta2.Data
+}
+
+func openMetaStore(dir string) (*metaStore, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	m := &metaStore{
+		path: filepath.Join(dir, metaFile),
+		data: &meta2.Data{},
+	}
+
+	buf, err := ioutil.ReadFile(m.path)
+	if err == nil {
+		if err = m.data.UnmarshalBinary(buf); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	m.data.PtNumPerNode = 1
+	err, id := m.data.CreateDataNode("localhost", "localhost")
+	if err != nil {
+		return nil, err
+	}
+	m.data.ClusterChangeState(id, serf.StatusAlive)
+	return m, m.save(m.data)
+}
+
+// save writes data to a temporary file first, so a crash never leaves a
+// truncated meta file behind.
+func (m *metaStore) save(data *meta2.Data) error {
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	tmp := m.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(buf); err == nil {
+		err = f.Sync()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.path)
+}
+
+// apply runs fn on a clone of the meta data and makes it current once it is persisted.
+func (m *metaStore) apply(fn func(data *meta2.Data) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data := m.data.Clone()
+	data.Index++
+	if err := fn(data); err != nil {
+		return err
+	}
+	if err := m.save(data); err != nil {
+		return err
+	}
+	m.data = data
+	return nil
+}
+
+func (m *metaStore) current() *meta2.Data {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data
+}
+
+func (m *metaStore) CreateDatabase(name string) error {
+	if m.current().Database(name) != nil {
+		return nil
+	}
+	return m.apply(func(data *meta2.Data) error {
+		return data.CreateDatabase(name, meta2.NewRetentionPolicyInfo(meta2.DefaultRetentionPolicyName), nil)
+	})
+}
+
+func (m *metaStore) Databases() map[string]*meta2.DatabaseInfo {
+	return m.current().Databases
+}
+
+func (m *metaStore) Database(name string) (*meta2.DatabaseInfo, error) {
+	return m.current().GetDatabase(name)
+}
+
+func (m *metaStore) RetentionPolicy(database, policy string) (*meta2.RetentionPolicyInfo, error) {
+	return m.current().RetentionPolicy(database, policy)
+}
+
+func (m *metaStore) CreateShardGroup(database, policy string, timestamp time.Time) (*meta2.ShardGroupInfo, error) {
+	sg, tier, err := m.current().GetTierOfShardGroup(database, policy, timestamp, meta2.Hot)
+	if err != nil || sg != nil {
+		return sg, err
+	}
+
+	err = m.apply(func(data *meta2.Data) error {
+		return data.CreateShardGroup(database, policy, timestamp, tier)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.current().ShardGroupByTimestamp(database, policy, timestamp)
+}
+
+func (m *metaStore) DBPtView(database string) (meta2.DBPtInfos, error) {
+	view := m.current().DBPtView(database)
+	if view == nil {
+		return nil, errno.NewError(errno.DatabaseNotFound, database)
+	}
+	return view, nil
+}
+
+func (m *metaStore) Measurement(database string, rpName string, mstName string) (*meta2.MeasurementInfo, error) {
+	return m.current().Measurement(database, rpName, mstName)
+}
+
+func (m *metaStore) UpdateSchema(database string, retentionPolicy string, mst string, fieldToCreate []*proto2.FieldSchema) error {
+	return m.apply(func(data *meta2.Data) error {
+		return data.UpdateSchema(database, retentionPolicy, mst, fieldToCreate)
+	})
+}
+
+func (m *metaStore) CreateMeasurement(database string, retentionPolicy string, mst string, shardKey *meta2.ShardKeyInfo, indexR *meta2.IndexRelation) (*meta2.MeasurementInfo, error) {
+	msti, err := m.Measurement(database, retentionPolicy, mst)
+	if msti != nil || err != meta2.ErrMeasurementNotFound {
+		return msti, err
+	}
+
+	err = m.apply(func(data *meta2.Data) error {
+		var ski *proto2.ShardKeyInfo
+		if shardKey != nil {
+			ski = shardKey.Marshal()
+		}
+		var ir *proto2.IndexRelation
+		if indexR != nil {
+			ir = indexR.Marshal()
+		}
+		return data.CreateMeasurement(database, retentionPolicy, mst, ski, ir)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.Measurement(database, retentionPolicy, mst)
+}
+
+func (m *metaStore) GetAliveShards(database string, sgi *meta2.ShardGroupInfo) []int {
+	idxes := make([]int, 0, len(sgi.Shards))
+	for i := range sgi.Shards {
+		idxes = append(idxes, i)
+	}
+	return idxes
+}
+
+// GetMeasurements returns the measurements matched by m, which can be a regex.
+func (m *metaStore) GetMeasurements(mst *influxql.Measurement) ([]*meta2.MeasurementInfo, error) {
+	dbi, err := m.Database(mst.Database)
+	if err != nil {
+		return nil, err
+	}
+	rpi, err := dbi.GetRetentionPolicy(mst.RetentionPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if mst.Regex == nil {
+		msti, err := rpi.GetMeasurement(mst.Name)
+		if err != nil {
+			return nil, err
+		}
+		return []*meta2.MeasurementInfo{msti}, nil
+	}
+
+	var measurements []*meta2.MeasurementInfo
+	rpi.EachMeasurements(func(msti *meta2.MeasurementInfo) {
+		if mst.Regex.Val.MatchString(msti.Name) {
+			measurements = append(measurements, msti)
+		}
+	})
+	sort.Slice(measurements, func(i, j int) bool {
+		return measurements[i].Name < measurements[j].Name
+	})
+	return measurements, nil
+}
+
+// Schema splits the schema of a measurement into its fields and tags.
+func (m *metaStore) Schema(database string, retentionPolicy string, mst string) (map[string]int32, map[string]struct{}, error) {
+	msti, err := m.Measurement(database, retentionPolicy, mst)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := make(map[string]int32)
+	dimensions := make(map[string]struct{})
+	for key, typ := range msti.Schema {
+		if typ == influx.Field_Type_Tag {
+			dimensions[key] = struct{}{}
+		} else {
+			fields[key] = typ
+		}
+	}
+	return fields, dimensions, nil
+}
+
+func (m *metaStore) ShardGroupsByTimeRange(database, policy string, tmin, tmax time.Time) ([]meta2.ShardGroupInfo, error) {
+	return m.current().ShardGroupsByTimeRange(database, policy, tmin, tmax)
+}
+
+func (m *metaStore) TimeRangeInfo(database, policy string, shardID uint64) (*meta2.ShardTimeRangeInfo, error) {
+	rpi, err := m.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	}
+	info := rpi.TimeRangeInfo(shardID)
+	if info == nil {
+		return nil, errno.NewError(errno.ShardMetaNotFound, shardID)
+	}
+	return info, nil
+}
+
+func (m *metaStore) DurationInfos() map[uint64]*meta2.ShardDurationInfo {
+	return m.current().GetDurationInfos([]uint32{localPtID})
+}
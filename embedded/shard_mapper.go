@@ -0,0 +1,319 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+)
+
+// source is the database and retention policy of a measurement.
+type source struct {
+	database        string
+	retentionPolicy string
+}
+
+// localShardMapper maps the sources of a query to the shards of the local store.
+type localShardMapper struct {
+	meta      *metaStore
+	store     *localStore
+	seriesKey []byte
+}
+
+func (m *localShardMapper) MapShards(sources influxql.Sources, t influxql.TimeRange, opt query.SelectOptions, condition influxql.Expr) (query.ShardGroup, error) {
+	a := &localShardMapping{
+		mapper:   m,
+		shardMap: make(map[source][]uint64),
+		minTime:  time.Unix(0, t.MinTimeNano()),
+		maxTime:  time.Unix(0, t.MaxTimeNano()),
+	}
+	if err := m.mapShards(a, sources, condition, &opt); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (m *localShardMapper) Close() error {
+	return nil
+}
+
+func (m *localShardMapper) GetSeriesKey() []byte {
+	return m.seriesKey
+}
+
+func (m *localShardMapper) mapShards(a *localShardMapping, sources influxql.Sources, condition influxql.Expr, opt *query.SelectOptions) error {
+	for _, s := range sources {
+		switch s := s.(type) {
+		case *influxql.Measurement:
+			src := source{database: s.Database, retentionPolicy: s.RetentionPolicy}
+			if _, ok := a.shardMap[src]; ok {
+				continue
+			}
+
+			dbi, err := m.meta.Database(s.Database)
+			if err != nil {
+				return err
+			}
+			measurements, err := m.meta.GetMeasurements(s)
+			if err != nil {
+				return err
+			}
+			if len(measurements) == 0 {
+				continue
+			}
+
+			groups, err := m.meta.ShardGroupsByTimeRange(s.Database, s.RetentionPolicy, a.minTime, a.maxTime)
+			if err != nil {
+				return err
+			}
+
+			var shardKeyInfo *meta2.ShardKeyInfo
+			if len(dbi.ShardKey.ShardKey) > 0 {
+				shardKeyInfo = &dbi.ShardKey
+			}
+			var shardIDs []uint64
+			for i := range groups {
+				ski := shardKeyInfo
+				if ski == nil {
+					ski = measurements[0].GetShardKey(groups[i].ID)
+				}
+
+				aliveShardIdxes := m.meta.GetAliveShards(s.Database, &groups[i])
+				var shs []meta2.ShardInfo
+				if opt.HintType == hybridqp.FullSeriesQuery || opt.HintType == hybridqp.SpecificSeriesQuery {
+					shs, m.seriesKey = groups[i].TargetShardsHintQuery(s.Name, measurements[0], condition, opt, aliveShardIdxes)
+				} else {
+					shs = groups[i].TargetShards(s.Name, measurements[0], ski, condition, aliveShardIdxes)
+				}
+				for j := range shs {
+					shardIDs = append(shardIDs, shs[j].ID)
+				}
+			}
+			a.shardMap[src] = shardIDs
+		case *influxql.SubQuery:
+			if err := m.mapShards(a, s.Statement.Sources, condition, opt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// localShardMapping holds the shards of the local store a query reads from.
+type localShardMapping struct {
+	mapper   *localShardMapper
+	shardMap map[source][]uint64
+
+	// the time range of the query, the options of the plan are clamped to it
+	minTime time.Time
+	maxTime time.Time
+}
+
+func (a *localShardMapping) FieldDimensions(m *influxql.Measurement) (fields map[string]influxql.DataType, dimensions map[string]struct{}, schema *influxql.Schema, err error) {
+	if a.shardMap[source{database: m.Database, retentionPolicy: m.RetentionPolicy}] == nil {
+		return nil, nil, nil, nil
+	}
+
+	measurements, err := a.mapper.meta.GetMeasurements(m)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	fields = make(map[string]influxql.DataType)
+	dimensions = make(map[string]struct{})
+	schema = &influxql.Schema{MinTime: math.MaxInt64, MaxTime: math.MinInt64}
+	for i := range measurements {
+		metaFields, metaDimensions, err := a.mapper.meta.Schema(m.Database, m.RetentionPolicy, measurements[i].Name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for k, typ := range metaFields {
+			fields[k] = record.ToInfluxqlTypes(int(typ))
+		}
+		for k := range metaDimensions {
+			dimensions[k] = struct{}{}
+		}
+	}
+	return fields, dimensions, schema, nil
+}
+
+func (a *localShardMapping) MapType(m *influxql.Measurement, field string) influxql.DataType {
+	measurements, err := a.mapper.meta.GetMeasurements(m)
+	if err != nil {
+		return influxql.Unknown
+	}
+	for i := range measurements {
+		metaFields, metaDimensions, err := a.mapper.meta.Schema(m.Database, m.RetentionPolicy, measurements[i].Name)
+		if err != nil {
+			return influxql.Unknown
+		}
+		if typ, ok := metaFields[field]; ok {
+			return record.ToInfluxqlTypes(int(typ))
+		}
+		if _, ok := metaDimensions[field]; ok {
+			return influxql.Tag
+		}
+	}
+	return influxql.Unknown
+}
+
+func (a *localShardMapping) MapTypeBatch(m *influxql.Measurement, fields map[string]influxql.DataType, schema *influxql.Schema) error {
+	measurements, err := a.mapper.meta.GetMeasurements(m)
+	if err != nil {
+		return err
+	}
+	for i := range measurements {
+		metaFields, metaDimensions, err := a.mapper.meta.Schema(m.Database, m.RetentionPolicy, measurements[i].Name)
+		if err != nil {
+			return err
+		}
+		for k := range fields {
+			typ, isField := metaFields[k]
+			_, isTag := metaDimensions[k]
+			switch {
+			case isField && isTag:
+				return fmt.Errorf("column (%s) in measurement (%s) in both fields and tags", k, measurements[i].Name)
+			case isField:
+				fields[k] = record.ToInfluxqlTypes(int(typ))
+			case isTag:
+				fields[k] = influxql.Tag
+			default:
+				fields[k] = influxql.Unknown
+			}
+		}
+	}
+	return nil
+}
+
+// CreateLogicalPlan creates the same node exchange plan as a cluster would,
+// its remote query is executed against the local store.
+func (a *localShardMapping) CreateLogicalPlan(ctx context.Context, sources influxql.Sources, schema hybridqp.Catalog) (hybridqp.QueryNode, error) {
+	opts := schema.Options().(*query.ProcessorOptions)
+
+	// one remote query per database, each reads all the shards of it
+	var databases []string
+	srcsByDB := make(map[string]influxql.Sources)
+	shardsByDB := make(map[string][]uint64)
+	for _, src := range sources {
+		switch src := src.(type) {
+		case *influxql.Measurement:
+			ids := a.shardMap[source{database: src.Database, retentionPolicy: src.RetentionPolicy}]
+			if ids == nil {
+				continue
+			}
+			measurements, err := a.mapper.meta.GetMeasurements(src)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := srcsByDB[src.Database]; !ok {
+				databases = append(databases, src.Database)
+			}
+			for i := range measurements {
+				clone := src.Clone()
+				clone.Regex = nil
+				clone.Name = measurements[i].Name
+				srcsByDB[src.Database] = append(srcsByDB[src.Database], clone)
+			}
+			shardsByDB[src.Database] = append(shardsByDB[src.Database], ids...)
+		default:
+			return nil, fmt.Errorf("unsupported source %s", src)
+		}
+	}
+	if len(databases) == 0 {
+		return nil, nil
+	}
+
+	if !a.minTime.IsZero() && opts.StartTime < a.minTime.UnixNano() {
+		opts.StartTime = a.minTime.UnixNano()
+	}
+	if !a.maxTime.IsZero() && opts.EndTime > a.maxTime.UnixNano() {
+		opts.EndTime = a.maxTime.UnixNano()
+	}
+
+	eTraits := make([]hybridqp.Trait, 0, len(databases))
+	for _, db := range databases {
+		opt := *opts
+		opt.Sources = srcsByDB[db]
+		eTraits = append(eTraits, &executor.RemoteQuery{
+			Database: db,
+			PtID:     localPtID,
+			NodeID:   localNodeID,
+			ShardIDs: shardsByDB[db],
+			Opt:      opt,
+			Local:    a.mapper.store,
+		})
+		opts.Sources = srcsByDB[db]
+	}
+
+	builder := executor.NewLogicalPlanBuilderImpl(schema)
+	plan, err := builder.CreateSeriesPlan()
+	if err != nil {
+		return nil, err
+	}
+	if plan, err = builder.CreateMeasurementPlan(plan); err != nil {
+		return nil, err
+	}
+	if plan, err = builder.CreateScanPlan(plan); err != nil {
+		return nil, err
+	}
+	if plan, err = builder.CreateShardPlan(plan); err != nil {
+		return nil, err
+	}
+	if plan, err = builder.CreateNodePlan(plan, eTraits); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (a *localShardMapping) LogicalPlanCost(m *influxql.Measurement, opt query.ProcessorOptions) (hybridqp.LogicalPlanCost, error) {
+	return hybridqp.LogicalPlanCost{}, nil
+}
+
+func (a *localShardMapping) GetSources(sources influxql.Sources) influxql.Sources {
+	var srcs influxql.Sources
+	for _, src := range sources {
+		switch src := src.(type) {
+		case *influxql.Measurement:
+			measurements, err := a.mapper.meta.GetMeasurements(src)
+			if err != nil {
+				return nil
+			}
+			for i := range measurements {
+				clone := src.Clone()
+				clone.Regex = nil
+				clone.Name = measurements[i].Name
+				srcs = append(srcs, clone)
+			}
+		case *influxql.SubQuery:
+			srcs = append(srcs, src)
+		}
+	}
+	return srcs
+}
+
+func (a *localShardMapping) Close() error {
+	a.shardMap = nil
+	return nil
+}
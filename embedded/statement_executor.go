@@ -0,0 +1,264 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+
+	"github.com/influxdata/influxdb/models"
+	query2 "github.com/influxdata/influxdb/query"
+	"github.com/openGemini/openGemini/coordinator"
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultChunkSize is the maximum number of values of a series in one
+	// result, longer series are split into partial results
+	defaultChunkSize = 10000
+
+	defaultInnerChunkSize = 1024
+)
+
+// statementExecutor executes the statements supported by an embedded
+// database: selects and the few meta statements which need no cluster.
+type statementExecutor struct {
+	meta   *metaStore
+	mapper *localShardMapper
+	logger *logger.Logger
+}
+
+func (e *statementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+	switch stmt := stmt.(type) {
+	case *influxql.SelectStatement:
+		return e.executeSelectStatement(stmt, ctx)
+	case *influxql.CreateDatabaseStatement:
+		if stmt.RetentionPolicyCreate {
+			return fmt.Errorf("retention policy options are not supported by the embedded database")
+		}
+		if err := e.meta.CreateDatabase(stmt.Name); err != nil {
+			return err
+		}
+		return ctx.Send(&query2.Result{})
+	case *influxql.ShowDatabasesStatement:
+		return ctx.Send(&query2.Result{Series: e.showDatabases()})
+	case *influxql.ShowMeasurementsStatement:
+		return e.executeShowMeasurementsStatement(stmt, ctx)
+	default:
+		return fmt.Errorf("statement %q is not supported by the embedded database", stmt.String())
+	}
+}
+
+func (e *statementExecutor) Statistics(buffer []byte) ([]byte, error) {
+	return buffer, nil
+}
+
+func (e *statementExecutor) executeSelectStatement(stmt *influxql.SelectStatement, ctx *query.ExecutionContext) error {
+	// omit Time field for stmt
+	stmt.OmitTime = true
+	ctx.ExecutionOptions.RowsChan = make(chan query.RowsChan)
+	pipelineExecutor, err := e.createPipelineExecutor(ctx, stmt, ctx.ExecutionOptions)
+	if err == influxql.ErrDeclareEmptyCollection {
+		err = nil
+		pipelineExecutor = nil
+	}
+	if err != nil || pipelineExecutor == nil {
+		close(ctx.ExecutionOptions.RowsChan)
+		if err != nil {
+			return err
+		}
+		return ctx.Send(&query2.Result{Series: make([]*models.Row, 0)})
+	}
+
+	ec := make(chan error, 1)
+	go func() {
+		err := pipelineExecutor.ExecuteExecutor(context.Background())
+		if err != nil && errno.Equal(err, errno.BucketLacks) {
+			close(ctx.ExecutionOptions.RowsChan)
+		}
+		ec <- err
+	}()
+
+	emitted := false
+	for {
+		select {
+		case rowsChan, ok := <-ctx.ExecutionOptions.RowsChan:
+			if !ok {
+				if err := <-ec; err != nil {
+					return err
+				}
+				// Always emit at least one result.
+				if !emitted {
+					return ctx.Send(&query2.Result{Series: make([]*models.Row, 0)})
+				}
+				return nil
+			}
+			if err := ctx.Send(&query2.Result{Series: rowsChan.Rows, Partial: rowsChan.Partial}); err != nil {
+				pipelineExecutor.Abort()
+				return err
+			}
+			emitted = true
+		case <-ctx.Done():
+			pipelineExecutor.Abort()
+			return ctx.Err()
+		}
+	}
+}
+
+func (e *statementExecutor) createPipelineExecutor(ctx context.Context, stmt *influxql.SelectStatement, opt query.ExecutionOptions) (pipelineExecutor *executor.PipelineExecutor, err error) {
+	sopt := query.SelectOptions{
+		NodeID:      opt.NodeID,
+		Chunked:     opt.Chunked,
+		ChunkedSize: opt.ChunkSize,
+		RowsChan:    opt.RowsChan,
+		ChunkSize:   opt.InnerChunkSize,
+		Traceid:     opt.Traceid,
+		AbortChan:   opt.AbortCh,
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("runtime panic: %v", r)
+			e.logger.Error(err.Error(), zap.String("stack", string(debug.Stack())))
+		}
+	}()
+
+	p, err := executor.Select(ctx, stmt, e.mapper, sopt)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return p.(*executor.PipelineExecutor), nil
+}
+
+func (e *statementExecutor) showDatabases() models.Rows {
+	row := &models.Row{Name: "databases", Columns: []string{"name"}}
+	dbs := e.meta.Databases()
+	names := make([]string, 0, len(dbs))
+	for name, dbi := range dbs {
+		if !dbi.MarkDeleted {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		row.Values = append(row.Values, []interface{}{name})
+	}
+	return models.Rows{row}
+}
+
+func (e *statementExecutor) executeShowMeasurementsStatement(stmt *influxql.ShowMeasurementsStatement, ctx *query.ExecutionContext) error {
+	if stmt.Database == "" {
+		return coordinator.ErrDatabaseNameRequired
+	}
+	if stmt.Condition != nil {
+		return fmt.Errorf("WHERE clause of SHOW MEASUREMENTS is not supported by the embedded database")
+	}
+	dbi, err := e.meta.Database(stmt.Database)
+	if err != nil {
+		return err
+	}
+
+	var source *influxql.Measurement
+	if stmt.Source != nil {
+		source, _ = stmt.Source.(*influxql.Measurement)
+	}
+	set := make(map[string]struct{})
+	dbi.WalkRetentionPolicy(func(rpi *meta2.RetentionPolicyInfo) {
+		rpi.EachMeasurements(func(msti *meta2.MeasurementInfo) {
+			if source != nil && source.Regex != nil && !source.Regex.Val.MatchString(msti.Name) {
+				return
+			}
+			if source != nil && source.Regex == nil && source.Name != msti.Name {
+				return
+			}
+			set[msti.Name] = struct{}{}
+		})
+	})
+	if len(set) == 0 {
+		return ctx.Send(&query2.Result{})
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([][]interface{}, len(names))
+	for i := range names {
+		values[i] = []interface{}{names[i]}
+	}
+	return ctx.Send(&query2.Result{
+		Series: []*models.Row{{
+			Name:    "measurements",
+			Columns: []string{"name"},
+			Values:  values,
+		}},
+	})
+}
+
+// NormalizeStatement adds the default database and retention policy to the statement.
+func (e *statementExecutor) NormalizeStatement(stmt influxql.Statement, defaultDatabase, defaultRetentionPolicy string) (err error) {
+	influxql.WalkFunc(stmt, func(node influxql.Node) {
+		if err != nil {
+			return
+		}
+		switch node := node.(type) {
+		case *influxql.ShowMeasurementsStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
+		case *influxql.Measurement:
+			err = e.normalizeMeasurement(node, defaultDatabase, defaultRetentionPolicy)
+		}
+	})
+	return
+}
+
+func (e *statementExecutor) normalizeMeasurement(m *influxql.Measurement, defaultDatabase, defaultRetentionPolicy string) error {
+	if !m.IsTarget && m.Name == "" && m.SystemIterator == "" && m.Regex == nil {
+		return fmt.Errorf("invalid measurement")
+	}
+	if m.Database == "" {
+		m.Database = defaultDatabase
+	}
+	if m.Database == "" {
+		return coordinator.ErrDatabaseNameRequired
+	}
+
+	di, err := e.meta.Database(m.Database)
+	if err != nil {
+		return err
+	}
+	if m.RetentionPolicy == "" {
+		if defaultRetentionPolicy != "" {
+			m.RetentionPolicy = defaultRetentionPolicy
+		} else if di.DefaultRetentionPolicy != "" {
+			m.RetentionPolicy = di.DefaultRetentionPolicy
+		} else {
+			return fmt.Errorf("default retention policy not set for: %s", di.Name)
+		}
+	}
+	return nil
+}
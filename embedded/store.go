@@ -0,0 +1,140 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openGemini/openGemini/engine"
+	_ "github.com/openGemini/openGemini/engine/columnstore" // registers the tssp2 engine
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/engine/index/tsi"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/metaclient"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/stringinterner"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// localStore runs the storage engine in process, it plays the part of
+// ts-store for both the points writer and the query executor.
+type localStore struct {
+	engine  netstorage.Engine
+	meta    *metaStore
+	loadCtx *metaclient.LoadCtx
+	stop    chan struct{}
+}
+
+func openLocalStore(conf *config.Store, meta *metaStore) (*localStore, error) {
+	newEngineFn := netstorage.GetNewEngineFunction(conf.Engine)
+	if newEngineFn == nil {
+		return nil, fmt.Errorf("unknown tsm engine:%v", conf.Engine)
+	}
+
+	loadCtx := &metaclient.LoadCtx{}
+	loadCtx.LoadCh = make(chan *metaclient.DBPTCtx)
+	tsi.SetCardinalityLimits(conf)
+
+	eng, err := newEngineFn(conf.DataDir, conf.WALDir, netstorage.NewEngineOptionsWithConfig(conf), loadCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &localStore{
+		engine:  eng,
+		meta:    meta,
+		loadCtx: loadCtx,
+		stop:    make(chan struct{}),
+	}
+	go s.ReportLoad()
+
+	if err := eng.Open([]uint32{localPtID}, meta.DurationInfos()); err != nil {
+		close(s.stop)
+		return nil, fmt.Errorf("err open engine %s", err)
+	}
+	return s, nil
+}
+
+// WriteRows writes the rows of a shard. The rows go through the same encoding
+// as on the wire to ts-store, the encoded rows are what the wal records.
+func (s *localStore) WriteRows(_ uint64, db, rp string, ptId uint32, shardID uint64, rows *[]influx.Row, _ time.Duration) error {
+	if len(*rows) == 0 {
+		return nil
+	}
+	binaryRows, err := influx.FastMarshalMultiRows(nil, *rows)
+	if err != nil {
+		return err
+	}
+	decoded, _, _, _, _, err := influx.FastUnmarshalMultiRows(binaryRows, nil, nil, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	db = stringinterner.InternSafe(db)
+	rp = stringinterner.InternSafe(rp)
+	err = s.engine.WriteRows(db, rp, ptId, shardID, decoded, binaryRows)
+	switch err {
+	case engine.ErrPTNotFound:
+		s.engine.CreateDBPT(db, ptId)
+		fallthrough
+	case engine.ErrShardNotFound:
+		timeRangeInfo, err := s.meta.TimeRangeInfo(db, rp, shardID)
+		if err != nil {
+			return err
+		}
+		if err = s.engine.CreateShard(db, rp, ptId, shardID, timeRangeInfo); err != nil {
+			return err
+		}
+		return s.engine.WriteRows(db, rp, ptId, shardID, decoded, binaryRows)
+	default:
+		return err
+	}
+}
+
+// ReportLoad drains the load reports of the partitions, there is no ts-meta to receive them.
+func (s *localStore) ReportLoad() {
+	for {
+		select {
+		case <-s.stop:
+			return
+		case rCtx := <-s.loadCtx.LoadCh:
+			s.loadCtx.PutReportCtx(rCtx)
+		}
+	}
+}
+
+func (s *localStore) RefEngineDbPt(db string, ptId uint32) error {
+	return s.engine.DbPTRef(db, ptId)
+}
+
+func (s *localStore) UnrefEngineDbPt(db string, ptId uint32) {
+	s.engine.DbPTUnref(db, ptId)
+}
+
+func (s *localStore) CreateLogicPlanV2(ctx context.Context, db string, ptId uint32, shardID uint64, sources influxql.Sources, schema hybridqp.Catalog) (hybridqp.QueryNode, error) {
+	return s.engine.CreateLogicalPlan(ctx, db, ptId, shardID, sources, schema.(*executor.QuerySchema))
+}
+
+func (s *localStore) Close() error {
+	err := s.engine.Close()
+	close(s.stop)
+	return err
+}
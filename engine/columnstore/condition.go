@@ -0,0 +1,379 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"regexp"
+
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// condition filters the rows of a record on its columns. The rows are given by
+// their indexes in ascending order, filter returns the ones matching, in place.
+// The result of each row is the one of influxql.EvalBool on the row.
+type condition interface {
+	filter(cols *columns, rows []int) []int
+}
+
+// columns reads the columns of a record used by a condition. A name referenced
+// as a tag reads the tag column, so tags win over the fields of the same name.
+type columns struct {
+	rec    *record.Record
+	tags   []string
+	values map[string]*columnValues
+}
+
+func newColumns(rec *record.Record, tags []string) *columns {
+	return &columns{rec: rec, tags: tags, values: make(map[string]*columnValues)}
+}
+
+func (c *columns) get(name string) *columnValues {
+	v, ok := c.values[name]
+	if !ok {
+		v = newColumnValues(c.rec, name, contains(c.tags, name))
+		c.values[name] = v
+	}
+	return v
+}
+
+// compileCondition returns the condition evaluating expr on the columns. The
+// comparisons of a column with a literal and their AND and OR are evaluated on
+// the columns, the other expressions row by row.
+func compileCondition(expr influxql.Expr) condition {
+	switch e := expr.(type) {
+	case *influxql.ParenExpr:
+		return compileCondition(e.Expr)
+	case *influxql.BooleanLiteral:
+		return boolCond(e.Val)
+	case *influxql.BinaryExpr:
+		switch e.Op {
+		case influxql.AND, influxql.OR:
+			// a child which is not a bool or null changes the result
+			if !isBoolExpr(e.LHS) || !isBoolExpr(e.RHS) {
+				break
+			}
+			lhs, rhs := compileCondition(e.LHS), compileCondition(e.RHS)
+			if e.Op == influxql.AND {
+				return &andCond{lhs: lhs, rhs: rhs}
+			}
+			return &orCond{lhs: lhs, rhs: rhs}
+		case influxql.EQ, influxql.NEQ, influxql.LT, influxql.LTE, influxql.GT, influxql.GTE,
+			influxql.EQREGEX, influxql.NEQREGEX:
+			if c := newCompareCond(e); c != nil {
+				return c
+			}
+		}
+	}
+	return &evalCond{expr: expr, names: influxql.ExprNames(expr)}
+}
+
+// isBoolExpr is whether the value of expr is always a bool or null
+func isBoolExpr(expr influxql.Expr) bool {
+	switch e := expr.(type) {
+	case *influxql.ParenExpr:
+		return isBoolExpr(e.Expr)
+	case *influxql.BooleanLiteral:
+		return true
+	case *influxql.BinaryExpr:
+		switch e.Op {
+		case influxql.AND, influxql.OR, influxql.EQ, influxql.NEQ, influxql.LT, influxql.LTE,
+			influxql.GT, influxql.GTE, influxql.EQREGEX, influxql.NEQREGEX:
+			return true
+		}
+	}
+	return false
+}
+
+type boolCond bool
+
+func (c boolCond) filter(cols *columns, rows []int) []int {
+	if c {
+		return rows
+	}
+	return rows[:0]
+}
+
+type andCond struct {
+	lhs, rhs condition
+}
+
+func (c *andCond) filter(cols *columns, rows []int) []int {
+	if rows = c.lhs.filter(cols, rows); len(rows) == 0 {
+		return rows
+	}
+	return c.rhs.filter(cols, rows)
+}
+
+// orCond checks the rows not matching the lhs against the rhs
+type orCond struct {
+	lhs, rhs condition
+}
+
+func (c *orCond) filter(cols *columns, rows []int) []int {
+	left := c.lhs.filter(cols, append([]int(nil), rows...))
+	if len(left) == len(rows) {
+		return rows
+	}
+	rest := make([]int, 0, len(rows)-len(left))
+	i := 0
+	for _, row := range rows {
+		if i < len(left) && left[i] == row {
+			i++
+			continue
+		}
+		rest = append(rest, row)
+	}
+	right := c.rhs.filter(cols, rest)
+
+	// merge the two sorted results
+	n, i, j := 0, 0, 0
+	for i < len(left) || j < len(right) {
+		if j == len(right) || (i < len(left) && left[i] < right[j]) {
+			rows[n] = left[i]
+			i++
+		} else {
+			rows[n] = right[j]
+			j++
+		}
+		n++
+	}
+	return rows[:n]
+}
+
+// compareCond compares a column with a literal
+type compareCond struct {
+	name string
+	op   influxql.Token
+
+	lit   interface{}
+	isInt bool
+	i     int64
+	f     float64
+	s     string
+	b     bool
+	re    *regexp.Regexp
+}
+
+// mirrored is the operator comparing the rhs with the lhs
+var mirrored = map[influxql.Token]influxql.Token{
+	influxql.EQ:  influxql.EQ,
+	influxql.NEQ: influxql.NEQ,
+	influxql.LT:  influxql.GT,
+	influxql.LTE: influxql.GTE,
+	influxql.GT:  influxql.LT,
+	influxql.GTE: influxql.LTE,
+}
+
+// newCompareCond returns nil if expr is not a comparison of a column with a
+// literal
+func newCompareCond(expr *influxql.BinaryExpr) *compareCond {
+	op := expr.Op
+	ref, ok := expr.LHS.(*influxql.VarRef)
+	lit := expr.RHS
+	if !ok {
+		if ref, ok = expr.RHS.(*influxql.VarRef); !ok {
+			return nil
+		}
+		if op, ok = mirrored[op]; !ok {
+			return nil
+		}
+		lit = expr.LHS
+	}
+
+	c := &compareCond{name: ref.Val, op: op}
+	switch l := lit.(type) {
+	case *influxql.IntegerLiteral:
+		c.lit, c.isInt, c.i, c.f = l.Val, true, l.Val, float64(l.Val)
+	case *influxql.NumberLiteral:
+		c.lit, c.f = l.Val, l.Val
+	case *influxql.StringLiteral:
+		c.lit, c.s = l.Val, l.Val
+	case *influxql.BooleanLiteral:
+		c.lit, c.b = l.Val, l.Val
+	case *influxql.RegexLiteral:
+		if l.Val == nil {
+			return nil
+		}
+		c.lit, c.re = l.Val, l.Val
+	default:
+		return nil
+	}
+	return c
+}
+
+func (c *compareCond) filter(cols *columns, rows []int) []int {
+	v := cols.get(c.name)
+	n := 0
+	for _, row := range rows {
+		if c.match(v, row) {
+			rows[n] = row
+			n++
+		}
+	}
+	return rows[:n]
+}
+
+func (c *compareCond) match(v *columnValues, row int) bool {
+	if v.col == nil {
+		if v.tag {
+			return c.matchString("")
+		}
+		return c.matchNull()
+	}
+	switch v.typ {
+	case influx.Field_Type_Int:
+		if i := v.idx[row]; i >= 0 {
+			return c.matchInt(v.ints[i])
+		}
+	case influx.Field_Type_Float:
+		if i := v.idx[row]; i >= 0 {
+			return c.matchFloat(v.fs[i])
+		}
+	case influx.Field_Type_Boolean:
+		if i := v.idx[row]; i >= 0 {
+			return c.matchBool(v.bs[i])
+		}
+	default:
+		s, isNil := v.col.StringValueUnsafe(row)
+		if !isNil || v.tag {
+			return c.matchString(s)
+		}
+	}
+	return c.matchNull()
+}
+
+// matchNull compares a null, it is false for a bool
+func (c *compareCond) matchNull() bool {
+	if _, ok := c.lit.(bool); ok {
+		return c.matchBool(false)
+	}
+	return false
+}
+
+func (c *compareCond) matchBool(b bool) bool {
+	if _, ok := c.lit.(bool); !ok {
+		return false
+	}
+	switch c.op {
+	case influxql.EQ:
+		return b == c.b
+	case influxql.NEQ:
+		return b != c.b
+	}
+	return false
+}
+
+func (c *compareCond) matchInt(i int64) bool {
+	if c.isInt {
+		return compareInt(c.op, i, c.i)
+	}
+	if _, ok := c.lit.(float64); ok {
+		return compareFloat(c.op, float64(i), c.f)
+	}
+	return false
+}
+
+func (c *compareCond) matchFloat(f float64) bool {
+	switch c.lit.(type) {
+	case int64, float64:
+		return compareFloat(c.op, f, c.f)
+	}
+	return false
+}
+
+func (c *compareCond) matchString(s string) bool {
+	switch c.op {
+	case influxql.EQ, influxql.NEQ:
+		if _, ok := c.lit.(string); !ok {
+			return false
+		}
+		return (s == c.s) == (c.op == influxql.EQ)
+	case influxql.EQREGEX, influxql.NEQREGEX:
+		if c.re == nil {
+			return false
+		}
+		return c.re.MatchString(s) == (c.op == influxql.EQREGEX)
+	}
+	return false
+}
+
+func compareInt(op influxql.Token, a, b int64) bool {
+	switch op {
+	case influxql.EQ:
+		return a == b
+	case influxql.NEQ:
+		return a != b
+	case influxql.LT:
+		return a < b
+	case influxql.LTE:
+		return a <= b
+	case influxql.GT:
+		return a > b
+	case influxql.GTE:
+		return a >= b
+	}
+	return false
+}
+
+func compareFloat(op influxql.Token, a, b float64) bool {
+	switch op {
+	case influxql.EQ:
+		return a == b
+	case influxql.NEQ:
+		return a != b
+	case influxql.LT:
+		return a < b
+	case influxql.LTE:
+		return a <= b
+	case influxql.GT:
+		return a > b
+	case influxql.GTE:
+		return a >= b
+	}
+	return false
+}
+
+// evalCond evaluates an expression row by row
+type evalCond struct {
+	expr  influxql.Expr
+	names []influxql.VarRef
+}
+
+func (c *evalCond) filter(cols *columns, rows []int) []int {
+	values := make([]*columnValues, len(c.names))
+	for i := range c.names {
+		values[i] = cols.get(c.names[i].Val)
+	}
+	m := make(map[string]interface{}, len(values))
+	n := 0
+	for _, row := range rows {
+		for i, v := range values {
+			if value := v.value(row); value != nil {
+				m[c.names[i].Val] = value
+			} else {
+				delete(m, c.names[i].Val)
+			}
+		}
+		if influxql.EvalBool(c.expr, m) {
+			rows[n] = row
+			n++
+		}
+	}
+	return rows[:n]
+}
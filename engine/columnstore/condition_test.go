@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondition_SameAsEvalBool(t *testing.T) {
+	rec := record.NewRecord(record.Schemas{
+		{Name: "host", Type: influx.Field_Type_Tag},
+		{Name: "b", Type: influx.Field_Type_Boolean},
+		{Name: "f", Type: influx.Field_Type_Float},
+		{Name: "s", Type: influx.Field_Type_String},
+		{Name: "v", Type: influx.Field_Type_Int},
+		{Name: record.TimeField, Type: influx.Field_Type_Int},
+	}, false)
+	hosts := []string{"a", "", "b", "a", "c", ""}
+	for i, host := range hosts {
+		rec.ColVals[0].AppendString(host)
+		if i%3 == 2 {
+			rec.ColVals[1].AppendBooleanNull()
+			rec.ColVals[2].AppendFloatNull()
+			rec.ColVals[3].AppendStringNull()
+			rec.ColVals[4].AppendIntegerNull()
+		} else {
+			rec.ColVals[1].AppendBoolean(i%2 == 0)
+			rec.ColVals[2].AppendFloat(float64(i) + 0.5)
+			rec.ColVals[3].AppendString(host + "x")
+			rec.ColVals[4].AppendInteger(int64(i))
+		}
+		rec.ColVals[5].AppendInteger(int64(i))
+	}
+
+	tags := []string{"host", "missing"}
+	for _, cond := range []string{
+		"host = 'a'", "'a' = host", "host != ''", "host =~ /a|b/", "host !~ /a/", "host > 'a'",
+		"missing = ''", "missing != 'x'", "nofield = 1", "nofield != 1", "nofield = false", "nofield != true",
+		"v > 2", "2 > v", "v >= 1.5", "v = 3", "v != 3", "v = 'a'", "v =~ /1/", "v = true",
+		"f < 3", "f <= 3.5", "3 < f", "f != 1", "f = 'x'",
+		"s = 'ax'", "s != 'ax'", "s =~ /x/", "s < 'b'", "s = 1",
+		"b = true", "b != true", "b = false", "true = b", "b > false", "b = 1",
+		"host = 'a' AND v > 0", "host = 'a' OR v > 2", "(v > 3 OR s = 'ax') AND host != 'c'",
+		"v > 1 OR nofield = 1", "nofield = 1 OR true", "v =~ /1/ OR v = 0", "v > 0 AND true",
+		"v + 1 > 3", "v = f", "v OR true", "v AND true", "(v > 0) = true", "false",
+	} {
+		expr := influxql.MustParseExpr(cond)
+		influxql.WalkFunc(expr, func(n influxql.Node) {
+			if ref, ok := n.(*influxql.VarRef); ok && contains(tags, ref.Val) {
+				ref.Type = influxql.Tag
+			}
+		})
+
+		var expect []int
+		for row := range hosts {
+			m := make(map[string]interface{})
+			for _, ref := range influxql.ExprNames(expr) {
+				if v := newColumnValues(rec, ref.Val, contains(tags, ref.Val)).value(row); v != nil {
+					m[ref.Val] = v
+				}
+			}
+			if influxql.EvalBool(expr, m) {
+				expect = append(expect, row)
+			}
+		}
+
+		rows := []int{0, 1, 2, 3, 4, 5}
+		got := compileCondition(expr).filter(newColumns(rec, tags), rows)
+		require.Equal(t, len(expect), len(got), cond)
+		if len(expect) > 0 {
+			require.Equal(t, expect, got, cond)
+		}
+	}
+}
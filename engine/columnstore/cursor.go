@@ -0,0 +1,942 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/openGemini/openGemini/engine"
+	"github.com/openGemini/openGemini/engine/comm"
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/lib/tracing"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// defaultBatchSize is the size of the rows of the series read at once when the
+// query has no max-query-mem
+const defaultBatchSize = 64 * 1024 * 1024
+
+// seriesInfo is the series of the rows returned by a cursor. There is no series
+// index in tssp2, a series is made of the rows with the same values of the group
+// by and auxiliary tags of the query.
+type seriesInfo struct {
+	key  []byte
+	tags influx.PointTags
+}
+
+func (s *seriesInfo) GetSeriesKey() []byte {
+	return s.key
+}
+
+func (s *seriesInfo) GetSeriesTags() *influx.PointTags {
+	return &s.tags
+}
+
+// readPlan is what a query reads of a measurement
+type readPlan struct {
+	schema  *executor.QuerySchema
+	opt     *query.ProcessorOptions
+	fields  record.Schemas // the fields read and the time
+	dims    []string
+	auxTags []string
+	tags    []string // the tags of the series, dims and auxTags sorted
+}
+
+func newReadPlan(schema *executor.QuerySchema) *readPlan {
+	opt := schema.Options().(*query.ProcessorOptions)
+	p := &readPlan{
+		schema: schema,
+		opt:    opt,
+		dims:   opt.GetOptDimension(),
+	}
+	p.auxTags, p.fields = engine.NewRecordSchema(schema, nil, nil, nil)
+	sort.Strings(p.auxTags)
+
+	seen := make(map[string]struct{}, len(p.dims)+len(p.auxTags))
+	for _, names := range [][]string{p.dims, p.auxTags} {
+		for _, name := range names {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				p.tags = append(p.tags, name)
+			}
+		}
+	}
+	sort.Strings(p.tags)
+	return p
+}
+
+func (p *readPlan) fieldNames() []string {
+	names := make([]string, 0, len(p.fields)-1)
+	for _, f := range p.fields[:len(p.fields)-1] {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// seriesData is the rows of a series in time order
+type seriesData struct {
+	info  *seriesInfo
+	group []byte // the encoded group by tags, see executor.ChunkTags
+	rec   *record.Record
+}
+
+// seriesEntry is a series found by the first pass of a read
+type seriesEntry struct {
+	info  *seriesInfo
+	group []byte
+	size  int64 // the size of the rows of the series read
+	ord   int   // the position of the series in the order of the reads
+}
+
+// less orders the series by group then by key
+func (e *seriesEntry) less(o *seriesEntry) bool {
+	if c := bytes.Compare(e.group, o.group); c != 0 {
+		return c < 0
+	}
+	return bytes.Compare(e.info.key, o.info.key) < 0
+}
+
+// tableReader reads the rows of a measurement matching a query by batches of
+// series, so the rows in memory are bounded by max-query-mem. The first pass
+// reads the tags and the columns of the condition granule by granule to find
+// the series and the size of their rows. The series are sorted by group and
+// split into batches of whole groups. Each batch then reads only the units
+// holding its series, the rows of a file are sorted by the sort key so the
+// units of a batch are the ones of a range of the sort key when the group by
+// tags lead the sort key.
+type tableReader struct {
+	plan    *readPlan
+	sc      *scanner
+	snap    *tableSnapshot
+	units   []*scanUnit
+	series  []*seriesEntry
+	index   map[string]*seriesEntry
+	batches []int // the end of each batch in series
+}
+
+// newTableReader finds the series of the rows of the snapshot matching the
+// query. The snapshot is released by close.
+func (p *readPlan) newTableReader(snap *tableSnapshot) (*tableReader, error) {
+	r := &tableReader{
+		plan:  p,
+		sc:    newScanner(record.TimeRange{Min: p.opt.StartTime, Max: p.opt.EndTime}, p.opt.Condition, p.tags, p.fieldNames()),
+		snap:  snap,
+		index: make(map[string]*seriesEntry),
+	}
+	ctx := immutable.NewCoderContext()
+	defer ctx.Release()
+
+	var rows []int
+	var key []byte
+	for _, u := range r.sc.units(snap) {
+		rec, err := r.sc.read(u, r.sc.wantKeys, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if rows = r.sc.match(rec, rows[:0]); len(rows) == 0 {
+			continue
+		}
+		tags := p.tagColumns(rec)
+		src := p.sourceColumns(rec)
+		for _, row := range rows {
+			key = p.seriesKey(key[:0], tags, row)
+			e, ok := r.index[string(key)]
+			if !ok {
+				e = p.newEntry(key, tags, row)
+				r.index[string(e.info.key)] = e
+				r.series = append(r.series, e)
+			}
+			e.size += p.rowSize(rec, src, row)
+			if u.lo == nil || e.less(u.lo) {
+				u.lo = e
+			}
+			if u.hi == nil || u.hi.less(e) {
+				u.hi = e
+			}
+		}
+		r.units = append(r.units, u)
+	}
+
+	sort.Slice(r.series, func(i, j int) bool {
+		return r.series[i].less(r.series[j])
+	})
+	for i, e := range r.series {
+		e.ord = i
+	}
+	if err := r.split(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// split splits the series into batches of whole groups whose rows fit in
+// max-query-mem, or in defaultBatchSize without limit
+func (r *tableReader) split() error {
+	limit := r.plan.opt.MaxQueryMem
+	budget := limit
+	if budget <= 0 {
+		budget = defaultBatchSize
+	}
+	var size int64
+	for i := 0; i < len(r.series); {
+		j, groupSize := i, int64(0)
+		for ; j < len(r.series) && bytes.Equal(r.series[j].group, r.series[i].group); j++ {
+			groupSize += r.series[j].size
+		}
+		if limit > 0 && groupSize > limit {
+			return fmt.Errorf("the rows of a group of %s take %d bytes, more than max-query-mem %d bytes",
+				r.plan.opt.Name, groupSize, limit)
+		}
+		if size > 0 && size+groupSize > budget {
+			r.batches = append(r.batches, i)
+			size = 0
+		}
+		size += groupSize
+		i = j
+	}
+	if len(r.series) > 0 {
+		r.batches = append(r.batches, len(r.series))
+	}
+	return nil
+}
+
+// read returns the series of the batch b, with their rows in time order. The
+// rows with the same time are all kept.
+func (r *tableReader) read(b int) ([]*seriesData, error) {
+	p := r.plan
+	start, end := 0, r.batches[b]
+	if b > 0 {
+		start = r.batches[b-1]
+	}
+	list := make([]*seriesData, end-start)
+	for i := range list {
+		e := r.series[start+i]
+		list[i] = &seriesData{info: e.info, group: e.group, rec: record.NewRecord(p.fields, false)}
+	}
+
+	ctx := immutable.NewCoderContext()
+	defer ctx.Release()
+	raw := !p.schema.HasCall()
+	var rows []int
+	var key []byte
+	for _, u := range r.units {
+		if u.hi.ord < start || u.lo.ord >= end {
+			continue
+		}
+		rec, err := r.sc.read(u, r.sc.want, ctx)
+		if err != nil {
+			return nil, err
+		}
+		rows = r.sc.match(rec, rows[:0])
+		tags := p.tagColumns(rec)
+		src := p.sourceColumns(rec)
+
+		var cur *seriesData
+		from, to := 0, 0
+		for _, row := range rows {
+			// like tssp1, a raw query returns no row without any of the fields
+			if raw && allNull(rec, src, row) {
+				continue
+			}
+			e := r.index[string(p.seriesKey(key[:0], tags, row))]
+			if e == nil || e.ord < start || e.ord >= end {
+				continue
+			}
+			sd := list[e.ord-start]
+			if sd == cur && row == to {
+				to++
+				continue
+			}
+			if cur != nil {
+				p.appendRows(cur.rec, rec, src, from, to)
+			}
+			cur, from, to = sd, row, row+1
+		}
+		if cur != nil {
+			p.appendRows(cur.rec, rec, src, from, to)
+		}
+	}
+
+	ascending := p.opt.IsAscending()
+	for _, sd := range list {
+		sd.rec = sortByTime(sd.rec, ascending)
+	}
+	if b == len(r.batches)-1 {
+		r.close()
+	}
+	return list, nil
+}
+
+// close releases the snapshot, the last batch is read
+func (r *tableReader) close() {
+	if r.snap != nil {
+		r.snap.release()
+		r.snap = nil
+		r.units = nil
+	}
+}
+
+func (p *readPlan) tagColumns(rec *record.Record) []*columnValues {
+	tags := make([]*columnValues, len(p.tags))
+	for i, name := range p.tags {
+		tags[i] = newColumnValues(rec, name, true)
+	}
+	return tags
+}
+
+func (p *readPlan) seriesKey(dst []byte, tags []*columnValues, row int) []byte {
+	for i := range p.tags {
+		if v := tags[i].str(row); v != "" {
+			dst = append(append(append(dst, p.tags[i]...), 0), v...)
+			dst = append(dst, 0)
+		}
+	}
+	return dst
+}
+
+func (p *readPlan) newEntry(key []byte, tags []*columnValues, row int) *seriesEntry {
+	info := &seriesInfo{key: append([]byte(nil), key...)}
+	for i, name := range p.tags {
+		if v := tags[i].str(row); v != "" {
+			info.tags = append(info.tags, influx.Tag{Key: name, Value: string(append([]byte(nil), v...))})
+		}
+	}
+	return &seriesEntry{
+		info:  info,
+		group: executor.NewChunkTags(info.tags, p.dims).GetTag(),
+	}
+}
+
+// rowSize is the size of a row once read, the numeric fields are counted
+// whether they are read or not
+func (p *readPlan) rowSize(rec *record.Record, src []int, row int) int64 {
+	size := int64(8)
+	for k, f := range p.fields[:len(p.fields)-1] {
+		switch f.Type {
+		case influx.Field_Type_String:
+			if src[k] >= 0 {
+				v, _ := rec.ColVals[src[k]].StringValueUnsafe(row)
+				size += int64(len(v)) + 4
+			}
+		case influx.Field_Type_Boolean:
+			size++
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
+// sourceColumns returns the index in rec of each field read, -1 if rec has no
+// column of the field with the type of the query
+func (p *readPlan) sourceColumns(rec *record.Record) []int {
+	src := make([]int, len(p.fields)-1)
+	for k, f := range p.fields[:len(p.fields)-1] {
+		i := columnIndex(rec.Schema, f.Name, false)
+		if i >= 0 && rec.Schema[i].Type != f.Type {
+			i = -1
+		}
+		src[k] = i
+	}
+	return src
+}
+
+func allNull(rec *record.Record, src []int, row int) bool {
+	if len(src) == 0 {
+		return false
+	}
+	for _, i := range src {
+		if i >= 0 && !rec.ColVals[i].IsNil(row) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *readPlan) appendRows(dst, rec *record.Record, src []int, start, end int) {
+	for k, i := range src {
+		if i < 0 {
+			dst.ColVals[k].PadColVal(p.fields[k].Type, end-start)
+			continue
+		}
+		dst.ColVals[k].AppendColVal(&rec.ColVals[i], p.fields[k].Type, start, end)
+	}
+	dst.ColVals[len(src)].AppendColVal(&rec.ColVals[len(rec.ColVals)-1], influx.Field_Type_Int, start, end)
+}
+
+// sortByTime returns the rows of rec in time order, the order of the rows with
+// the same time is kept
+func sortByTime(rec *record.Record, ascending bool) *record.Record {
+	times := rec.Times()
+	less := func(i, j int) bool {
+		if ascending {
+			return times[i] < times[j]
+		}
+		return times[i] > times[j]
+	}
+	sorted := true
+	for i := 1; i < len(times); i++ {
+		if less(i, i-1) {
+			sorted = false
+			break
+		}
+	}
+	if sorted {
+		return rec
+	}
+
+	perm := make([]int, len(times))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool {
+		return less(perm[i], perm[j])
+	})
+
+	dst := record.NewRecord(rec.Schema, false)
+	for i := 0; i < len(perm); {
+		j := i + 1
+		for j < len(perm) && perm[j] == perm[j-1]+1 {
+			j++
+		}
+		for c := range rec.ColVals {
+			dst.ColVals[c].AppendColVal(&rec.ColVals[c], rec.Schema[c].Type, perm[i], perm[j-1]+1)
+		}
+		i = j
+	}
+	return dst
+}
+
+// seriesCursor returns the rows of a series, or their pre-aggregation once the
+// calls are set by SetOps
+type seriesCursor struct {
+	plan   *readPlan
+	data   *seriesData
+	schema record.Schemas
+	keep   []int // the column of data.rec of each field of schema
+	ops    []*comm.CallOption
+	pos    int
+}
+
+func newSeriesCursor(plan *readPlan, data *seriesData) *seriesCursor {
+	c := &seriesCursor{plan: plan, data: data, schema: plan.fields}
+	c.keep = make([]int, len(plan.fields)-1)
+	for i := range c.keep {
+		c.keep[i] = i
+	}
+	return c
+}
+
+func (c *seriesCursor) SetOps(ops []*comm.CallOption) {
+	c.ops = ops
+}
+
+// SinkPlan renames the fields as the series plan outputs them and drops the
+// ones it does not output, like the series cursor of tssp1
+func (c *seriesCursor) SinkPlan(plan hybridqp.QueryNode) {
+	for {
+		if _, ok := plan.(*executor.LogicalSeries); ok {
+			break
+		}
+		if len(plan.Children()) == 0 {
+			return
+		}
+		plan = plan.Children()[0]
+	}
+
+	ops := plan.RowExprOptions()
+	schema := make(record.Schemas, 0, len(c.plan.fields))
+	c.keep = c.keep[:0]
+	for i, field := range c.plan.fields[:len(c.plan.fields)-1] {
+		for _, op := range ops {
+			if ref, ok := op.Expr.(*influxql.VarRef); ok && ref.Val == field.Name {
+				schema = append(schema, record.Field{Name: op.Ref.Val, Type: record.ToModelTypes(op.Ref.Type)})
+				c.keep = append(c.keep, i)
+				break
+			}
+		}
+	}
+	c.schema = append(schema, record.Field{Name: record.TimeField, Type: influx.Field_Type_Int})
+}
+
+func (c *seriesCursor) Next() (*record.Record, comm.SeriesInfoIntf, error) {
+	src := c.data.rec
+	rows := src.RowNums()
+	if c.pos >= rows {
+		return nil, nil, nil
+	}
+	if len(c.ops) > 0 {
+		c.pos = rows
+		rec := c.preAgg()
+		if rec == nil {
+			return nil, nil, nil
+		}
+		return rec, c.data.info, nil
+	}
+
+	end := c.pos + c.plan.opt.ChunkSizeNum()
+	if end > rows {
+		end = rows
+	}
+	rec := record.NewRecord(c.schema, false)
+	for k, i := range c.keep {
+		rec.ColVals[k].AppendColVal(&src.ColVals[i], src.Schema[i].Type, c.pos, end)
+	}
+	rec.ColVals[len(c.keep)].AppendColVal(&src.ColVals[len(src.ColVals)-1], influx.Field_Type_Int, c.pos, end)
+	c.pos = end
+	return rec, c.data.info, nil
+}
+
+// preAgg returns a record of one row with the results of the calls in the
+// column metas, nil if all their fields are null. The row is the one selected
+// by the call if there is only one, else the first one.
+func (c *seriesCursor) preAgg() *record.Record {
+	src := c.data.rec
+	times := src.Times()
+	rec := record.NewRecord(src.Schema, true)
+	row, found := 0, false
+	for _, op := range c.ops {
+		i := src.Schema.FieldIndex(op.Ref.Val)
+		if i < 0 || i == len(src.Schema)-1 {
+			continue
+		}
+		sel, ok := aggregate(&rec.ColMeta[i], op.Call.Name, &src.ColVals[i], src.Schema[i].Type, times)
+		found = found || ok
+		if sel >= 0 && len(c.ops) == 1 {
+			row = sel
+		}
+	}
+	if !found {
+		return nil
+	}
+	for i := range src.ColVals {
+		rec.ColVals[i].AppendColVal(&src.ColVals[i], src.Schema[i].Type, row, row+1)
+	}
+	return rec
+}
+
+// aggregate sets the result of a call on a column in meta. It returns the row
+// selected by the call, -1 if it is not a selector, and false if the column
+// has no value.
+func aggregate(meta *record.ColMeta, name string, col *record.ColVal, typ int, times []int64) (int, bool) {
+	values := columnValuesOf(col, typ, false)
+	var count, sumInt int64
+	var sumFloat float64
+	sel := -1
+	for j := 0; j < col.Len; j++ {
+		v := values.value(j)
+		if v == nil {
+			continue
+		}
+		count++
+		switch name {
+		case "sum":
+			switch x := v.(type) {
+			case int64:
+				sumInt += x
+			case float64:
+				sumFloat += x
+			}
+		case "min", "max":
+			if sel < 0 {
+				sel = j
+				continue
+			}
+			c := compareValue(v, values.value(sel))
+			if name == "max" {
+				c = -c
+			}
+			if c < 0 || (c == 0 && times[j] < times[sel]) {
+				sel = j
+			}
+		case "first":
+			if sel < 0 || times[j] < times[sel] {
+				sel = j
+			}
+		case "last":
+			if sel < 0 || times[j] > times[sel] {
+				sel = j
+			}
+		}
+	}
+	if count == 0 {
+		return -1, false
+	}
+
+	switch name {
+	case "count":
+		meta.SetCount(count)
+	case "sum":
+		if typ == influx.Field_Type_Float {
+			meta.SetSum(sumFloat)
+		} else {
+			meta.SetSum(sumInt)
+		}
+	case "min":
+		meta.SetMin(values.value(sel), times[sel])
+	case "max":
+		meta.SetMax(values.value(sel), times[sel])
+	case "first":
+		meta.SetFirst(values.value(sel), times[sel])
+	case "last":
+		meta.SetLast(values.value(sel), times[sel])
+	}
+	return sel, true
+}
+
+func compareValue(a, b interface{}) int {
+	switch x := a.(type) {
+	case int64:
+		y := b.(int64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	case float64:
+		y := b.(float64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	case bool:
+		y := b.(bool)
+		if !x && y {
+			return -1
+		} else if x && !y {
+			return 1
+		}
+	case string:
+		return bytes.Compare([]byte(x), []byte(b.(string)))
+	}
+	return 0
+}
+
+func (c *seriesCursor) Name() string {
+	return c.plan.opt.OptionsName()
+}
+
+func (c *seriesCursor) Close() error {
+	return nil
+}
+
+func (c *seriesCursor) GetSchema() record.Schemas {
+	return c.schema
+}
+
+func (c *seriesCursor) StartSpan(span *tracing.Span) {
+}
+
+func (c *seriesCursor) EndSpan() {
+}
+
+func (c *seriesCursor) NextAggData() (*record.Record, *comm.FileInfo, error) {
+	return nil, nil, nil
+}
+
+// tableCursor returns the rows of a measurement of a shard. The rows of each
+// series are returned in turn, with the auxiliary tags of the query as columns.
+// If the auxiliary tags split the groups of the query in several series, the
+// rows of the series of a group are merged in time order. The series are read
+// by batches of whole groups, the next batch is read once the series of the
+// previous one are returned.
+type tableCursor struct {
+	plan   *readPlan
+	reader *tableReader
+	batch  int
+	series []*seriesCursor
+	inputs []comm.KeyCursor
+	schema record.Schemas
+	preAgg bool
+	pos    int
+
+	// applied to the series of each batch
+	ops  []*comm.CallOption
+	sink hybridqp.QueryNode
+
+	// the merged rows of a group of series
+	group    *record.Record
+	groupTag []byte
+	groupPos int
+	info     *seriesInfo
+}
+
+// newTableCursor finds the series of the measurement of the query in a shard
+// and reads the first batch of their rows, it returns nil if no row matches.
+func newTableCursor(s *shard, schema *executor.QuerySchema) (comm.KeyCursor, error) {
+	plan := newReadPlan(schema)
+	snap := s.snapshot(plan.opt.Name)
+	reader, err := plan.newTableReader(snap)
+	if err != nil || len(reader.series) == 0 {
+		snap.release()
+		return nil, err
+	}
+	c := &tableCursor{plan: plan, reader: reader}
+	if err = c.readBatch(); err != nil {
+		reader.close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// readBatch reads the series of the next batch
+func (c *tableCursor) readBatch() error {
+	series, err := c.reader.read(c.batch)
+	if err != nil {
+		return err
+	}
+	c.batch++
+	c.series = c.series[:0]
+	c.inputs = c.inputs[:0]
+	for _, sd := range series {
+		sc := newSeriesCursor(c.plan, sd)
+		sc.SetOps(c.ops)
+		c.series = append(c.series, sc)
+		c.inputs = append(c.inputs, sc)
+	}
+	c.pos = 0
+	if c.sink != nil {
+		c.sinkSeries()
+	}
+	return nil
+}
+
+// nextBatch reads the next batch once the series of the current one are
+// returned, it returns false after the last batch.
+func (c *tableCursor) nextBatch() (bool, error) {
+	if c.pos < len(c.inputs) {
+		return true, nil
+	}
+	if c.batch >= len(c.reader.batches) {
+		return false, nil
+	}
+	return true, c.readBatch()
+}
+
+func (c *tableCursor) SetOps(ops []*comm.CallOption) {
+	c.ops = ops
+	c.preAgg = len(ops) > 0
+	for _, s := range c.series {
+		s.SetOps(ops)
+	}
+	c.schema = nil
+}
+
+// SinkPlan aggregates each series like tssp1 does when the aggregation is
+// pushed down to the series
+func (c *tableCursor) SinkPlan(plan hybridqp.QueryNode) {
+	c.sink = plan
+	c.sinkSeries()
+	c.schema = nil
+}
+
+func (c *tableCursor) sinkSeries() {
+	plan := c.sink
+	for {
+		switch node := plan.(type) {
+		case *executor.LogicalAggregate:
+			for i, s := range c.series {
+				agg := engine.NewAggregateCursor(s, c.plan.schema, engine.AggPool, len(c.plan.auxTags) > 0)
+				agg.SinkPlan(node)
+				c.inputs[i] = agg
+			}
+			return
+		case *executor.LogicalSeries:
+			for _, s := range c.series {
+				s.SinkPlan(node)
+			}
+			return
+		}
+		if len(plan.Children()) == 0 {
+			return
+		}
+		plan = plan.Children()[0]
+	}
+}
+
+// GetSchema returns the schema of the inputs with the auxiliary tags before time
+func (c *tableCursor) GetSchema() record.Schemas {
+	if c.schema != nil {
+		return c.schema
+	}
+	base := c.inputs[0].GetSchema()
+	schema := make(record.Schemas, 0, len(base)+len(c.plan.auxTags))
+	schema = append(schema, base[:len(base)-1]...)
+	for _, name := range c.plan.auxTags {
+		schema = append(schema, record.Field{Name: name, Type: influx.Field_Type_Tag})
+	}
+	c.schema = append(schema, base[len(base)-1])
+	return c.schema
+}
+
+func (c *tableCursor) Next() (*record.Record, comm.SeriesInfoIntf, error) {
+	if len(c.plan.auxTags) > 0 && !c.preAgg && !multiCallsWithFirst(c.plan.schema) {
+		return c.nextMerged()
+	}
+	for {
+		ok, err := c.nextBatch()
+		if !ok || err != nil {
+			return nil, nil, err
+		}
+		rec, _, err := c.inputs[c.pos].Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if rec == nil {
+			c.pos++
+			continue
+		}
+		sd := c.series[c.pos].data
+		rec = c.withTags(rec, sd.info)
+		tag := sd.group
+		rec.AddTagIndexAndKey(&tag, 0)
+		return rec, sd.info, nil
+	}
+}
+
+func (c *tableCursor) nextMerged() (*record.Record, comm.SeriesInfoIntf, error) {
+	for c.group == nil || c.groupPos >= c.group.RowNums() {
+		ok, err := c.nextBatch()
+		if !ok || err != nil {
+			return nil, nil, err
+		}
+		if err = c.mergeGroup(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	end := c.groupPos + c.plan.opt.ChunkSizeNum()
+	if rows := c.group.RowNums(); end > rows {
+		end = rows
+	}
+	rec := record.NewRecord(c.group.Schema, false)
+	rec.RecMeta = &record.RecMeta{}
+	for i := range rec.ColVals {
+		rec.ColVals[i].AppendColVal(&c.group.ColVals[i], c.group.Schema[i].Type, c.groupPos, end)
+	}
+	c.groupPos = end
+	tag := c.groupTag
+	rec.AddTagIndexAndKey(&tag, 0)
+	return rec, c.info, nil
+}
+
+// mergeGroup reads all the rows of the series of the next group, the groups
+// are not split across batches
+func (c *tableCursor) mergeGroup() error {
+	first := c.series[c.pos].data
+	group := record.NewRecord(c.GetSchema(), false)
+	for ; c.pos < len(c.inputs) && bytes.Equal(c.series[c.pos].data.group, first.group); c.pos++ {
+		for {
+			rec, _, err := c.inputs[c.pos].Next()
+			if err != nil {
+				return err
+			}
+			if rec == nil {
+				break
+			}
+			rec = c.withTags(rec, c.series[c.pos].data.info)
+			for i := range rec.ColVals {
+				group.ColVals[i].AppendColVal(&rec.ColVals[i], rec.Schema[i].Type, 0, rec.RowNums())
+			}
+		}
+	}
+	c.group = sortByTime(group, c.plan.opt.IsAscending())
+	c.groupTag = first.group
+	c.groupPos = 0
+	c.info = first.info
+	return nil
+}
+
+// withTags copies rec with the values of the auxiliary tags of the series
+func (c *tableCursor) withTags(rec *record.Record, info *seriesInfo) *record.Record {
+	schema := c.GetSchema()
+	colMeta := rec.RecMeta != nil && rec.ColMeta != nil
+	dst := record.NewRecord(schema, colMeta)
+	if dst.RecMeta == nil {
+		dst.RecMeta = &record.RecMeta{}
+	}
+	rows := rec.RowNums()
+	fields := len(rec.Schema) - 1
+	for i := 0; i < fields; i++ {
+		dst.ColVals[i].AppendColVal(&rec.ColVals[i], rec.Schema[i].Type, 0, rows)
+	}
+	for k, name := range c.plan.auxTags {
+		col := &dst.ColVals[fields+k]
+		tag := info.tags.FindPointTag(name)
+		for j := 0; j < rows; j++ {
+			if tag != nil {
+				col.AppendString(tag.Value)
+			} else {
+				col.AppendStringNull()
+			}
+		}
+	}
+	timeIdx := len(schema) - 1
+	dst.ColVals[timeIdx].AppendColVal(&rec.ColVals[fields], influx.Field_Type_Int, 0, rows)
+
+	if colMeta {
+		copy(dst.ColMeta, rec.ColMeta[:fields])
+		dst.ColMeta[timeIdx] = rec.ColMeta[fields]
+		if len(rec.RecMeta.Times) > fields {
+			dst.RecMeta.Times = make([][]int64, len(schema))
+			copy(dst.RecMeta.Times, rec.RecMeta.Times[:fields])
+			dst.RecMeta.Times[timeIdx] = rec.RecMeta.Times[fields]
+		}
+	}
+	return dst
+}
+
+// multiCallsWithFirst is whether the ChunkReader reads a record of each series
+// for a query with several calls including first or last
+func multiCallsWithFirst(schema *executor.QuerySchema) bool {
+	if len(schema.Calls()) > 1 {
+		for _, c := range schema.Calls() {
+			if c.Name == "first" || c.Name == "last" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *tableCursor) Name() string {
+	return c.plan.opt.OptionsName()
+}
+
+// Close releases the files read, if the cursor is closed before the last batch
+func (c *tableCursor) Close() error {
+	c.reader.close()
+	return nil
+}
+
+func (c *tableCursor) StartSpan(span *tracing.Span) {
+}
+
+func (c *tableCursor) EndSpan() {
+}
+
+func (c *tableCursor) NextAggData() (*record.Record, *comm.FileInfo, error) {
+	return nil, nil, nil
+}
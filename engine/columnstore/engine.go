@@ -0,0 +1,911 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openGemini/openGemini/engine"
+	"github.com/openGemini/openGemini/engine/comm"
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/lib/interruptsignal"
+	"github.com/openGemini/openGemini/lib/logger"
+	meta "github.com/openGemini/openGemini/lib/metaclient"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	stat "github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"go.uber.org/zap"
+)
+
+const (
+	flushCheckInterval           = time.Second
+	defaultShardMutableSizeLimit = 64 * 1024 * 1024
+)
+
+var log *zap.Logger
+
+func init() {
+	log = logger.GetLogger()
+	netstorage.RegisterNewEngineFun(config.EngineType2, NewEngine)
+}
+
+func errNotSupported(op string) error {
+	return fmt.Errorf("%s is not supported by the %s engine", op, config.EngineType2)
+}
+
+// Engine is the tssp2 engine, for data with unbounded tag cardinality like logs
+// and traces. The tags of the rows are stored as columns of the column files,
+// sorted by the sort key of the store configuration, instead of series of an
+// index. The files have a sparse index of the sort key by granule of rows.
+type Engine struct {
+	mu       sync.RWMutex
+	closed   *interruptsignal.InterruptSignal
+	wg       sync.WaitGroup
+	dataPath string
+	walPath  string
+	readOnly bool
+
+	opt        netstorage.EngineOptions
+	loadCtx    *meta.LoadCtx
+	partitions map[string]map[uint32]*partition
+
+	statCount int64
+}
+
+func NewEngine(dataPath, walPath string, options netstorage.EngineOptions, ctx *meta.LoadCtx) (netstorage.Engine, error) {
+	log = logger.GetLogger()
+	return &Engine{
+		closed:     interruptsignal.NewInterruptSignal(),
+		dataPath:   dataPath,
+		walPath:    walPath,
+		opt:        options,
+		loadCtx:    ctx,
+		partitions: make(map[string]map[uint32]*partition),
+	}, nil
+}
+
+// partition is the shards of a database partition
+type partition struct {
+	mu      sync.RWMutex
+	db      string
+	id      uint32
+	path    string
+	walPath string
+	shards  map[uint64]*shard
+
+	refMu   sync.Mutex
+	refCond *sync.Cond
+	refs    int
+	closing bool
+}
+
+func newPartition(db string, id uint32, dataPath, walPath string) *partition {
+	pt := &partition{
+		db:      db,
+		id:      id,
+		path:    path.Join(dataPath, engine.DataDirectory, db, strconv.Itoa(int(id))),
+		walPath: path.Join(walPath, engine.WalDirectory, db, strconv.Itoa(int(id))),
+		shards:  make(map[uint64]*shard),
+	}
+	pt.refCond = sync.NewCond(&pt.refMu)
+	return pt
+}
+
+func (pt *partition) ref() bool {
+	pt.refMu.Lock()
+	defer pt.refMu.Unlock()
+	if pt.closing {
+		return false
+	}
+	pt.refs++
+	return true
+}
+
+func (pt *partition) unref() {
+	pt.refMu.Lock()
+	pt.refs--
+	if pt.refs == 0 {
+		pt.refCond.Broadcast()
+	}
+	pt.refMu.Unlock()
+}
+
+// offload refuses the new references and waits for the current ones
+func (pt *partition) offload() {
+	pt.refMu.Lock()
+	pt.closing = true
+	for pt.refs > 0 {
+		pt.refCond.Wait()
+	}
+	pt.refMu.Unlock()
+}
+
+func (pt *partition) shard(id uint64) *shard {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+	return pt.shards[id]
+}
+
+func (pt *partition) allShards() []*shard {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+	shards := make([]*shard, 0, len(pt.shards))
+	for _, sh := range pt.shards {
+		shards = append(shards, sh)
+	}
+	return shards
+}
+
+func (e *Engine) Open(ptIds []uint32, durationInfos map[uint64]*meta2.ShardDurationInfo) error {
+	log.Info("start open engine...", zap.String("engine", config.EngineType2))
+	start := time.Now()
+	defer func(tm time.Time) {
+		d := time.Since(tm)
+		atomic.AddInt64(&stat.EngineStat.OpenDurations, d.Nanoseconds())
+		stat.UpdateEngineStatS()
+		log.Info("open engine done", zap.Duration("time used", d))
+	}(start)
+
+	if err := fileops.MkdirAll(e.dataPath, 0750, fileops.FileLockOption("")); err != nil {
+		atomic.AddInt64(&stat.EngineStat.OpenErrors, 1)
+		return err
+	}
+	if err := e.loadShards(durationInfos); err != nil {
+		atomic.AddInt64(&stat.EngineStat.OpenErrors, 1)
+		return err
+	}
+
+	e.wg.Add(1)
+	go e.flushLoop()
+	return nil
+}
+
+func (e *Engine) loadShards(durationInfos map[uint64]*meta2.ShardDurationInfo) error {
+	loaded := make(map[string]struct{})
+	for _, sdi := range durationInfos {
+		db, ptID, rp := sdi.Ident.OwnerDb, sdi.Ident.OwnerPt, sdi.Ident.Policy
+		rpPath := path.Join(e.dataPath, engine.DataDirectory, db, strconv.Itoa(int(ptID)), rp)
+		if _, ok := loaded[rpPath]; ok {
+			continue
+		}
+		loaded[rpPath] = struct{}{}
+
+		dirs, err := fileops.ReadDir(rpPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		for _, d := range dirs {
+			if !d.IsDir() {
+				continue
+			}
+			shardID, tr, err := parseShardDir(d.Name())
+			if err != nil {
+				log.Warn("skip invalid shard directory", zap.String("dir", path.Join(rpPath, d.Name())))
+				continue
+			}
+			info, ok := durationInfos[shardID]
+			if !ok {
+				log.Warn("skip shard without duration info", zap.Uint64("shard", shardID))
+				continue
+			}
+			pt := e.createPartition(db, ptID)
+			sh := newShard(path.Join(rpPath, d.Name()), path.Join(pt.walPath, rp, d.Name()),
+				&info.Ident, tr, &info.DurationInfo, &e.opt)
+			if err = sh.open(); err != nil {
+				log.Error("open shard failed", zap.String("db", db), zap.Uint32("pt", ptID),
+					zap.Uint64("shard", shardID), zap.Error(err))
+				return err
+			}
+			pt.mu.Lock()
+			pt.shards[shardID] = sh
+			pt.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+func (e *Engine) flushLoop() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(flushCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.closed.Signal():
+			return
+		case <-ticker.C:
+			e.flushShards(false)
+		}
+	}
+}
+
+// flushShards flushes the shards reaching the size limit or cold for the write
+// cold duration, or all of them if force is true, and syncs the wal of the others
+func (e *Engine) flushShards(force bool) {
+	sizeLimit := e.opt.ShardMutableSizeLimit
+	if sizeLimit <= 0 {
+		sizeLimit = defaultShardMutableSizeLimit
+	}
+	coldDuration := e.opt.WriteColdDuration
+	if coldDuration <= 0 {
+		coldDuration = config.DefaultWriteColdDuration
+	}
+
+	for _, sh := range e.allShards() {
+		if !force && !sh.needFlush(sizeLimit, coldDuration) {
+			sh.syncWal()
+			continue
+		}
+		if err := sh.flush(); err != nil && err != engine.ErrShardClosed {
+			log.Error("flush shard failed", zap.Uint64("shard", sh.ident.ShardID), zap.Error(err))
+		}
+	}
+}
+
+func (e *Engine) allShards() []*shard {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var shards []*shard
+	for _, pts := range e.partitions {
+		for _, pt := range pts {
+			shards = append(shards, pt.allShards()...)
+		}
+	}
+	return shards
+}
+
+func (e *Engine) Close() error {
+	e.closed.Close()
+	e.wg.Wait()
+
+	start := time.Now()
+	log.Info("start close engine...", zap.String("engine", config.EngineType2))
+	defer func(tm time.Time) {
+		d := time.Since(tm)
+		atomic.AddInt64(&stat.EngineStat.CloseDurations, d.Nanoseconds())
+		stat.UpdateEngineStatS()
+		log.Info("close engine done", zap.Duration("time used", d))
+	}(start)
+
+	wg := sync.WaitGroup{}
+	for _, sh := range e.allShards() {
+		wg.Add(1)
+		go func(sh *shard) {
+			defer wg.Done()
+			if err := sh.close(); err != nil {
+				atomic.AddInt64(&stat.EngineStat.CloseErrors, 1)
+				log.Error("failed to close shard", zap.String("path", sh.dataPath), zap.Error(err))
+			}
+		}(sh)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (e *Engine) ForceFlush() {
+	start := time.Now()
+	log.Info("start force flush shard...")
+	e.flushShards(true)
+	log.Info("shard flush done", zap.Duration("time used(s)", time.Since(start)))
+}
+
+func (e *Engine) SetReadOnly(readonly bool) {
+	e.mu.Lock()
+	e.readOnly = readonly
+	e.mu.Unlock()
+}
+
+// refPartition returns the referenced partition, unref it once done
+func (e *Engine) refPartition(db string, ptID uint32) (*partition, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	pt, ok := e.partitions[db][ptID]
+	if !ok {
+		return nil, engine.ErrPTNotFound
+	}
+	if !pt.ref() {
+		return nil, meta2.ErrDBPTClose
+	}
+	return pt, nil
+}
+
+// refPartitions returns the referenced partitions of a database, the missing
+// ones are skipped like the partitions of tssp1 without index
+func (e *Engine) refPartitions(db string, ptIDs []uint32) ([]*partition, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	pts := make([]*partition, 0, len(ptIDs))
+	for _, id := range ptIDs {
+		pt, ok := e.partitions[db][id]
+		if !ok {
+			unrefPartitions(pts)
+			return nil, engine.ErrPTNotFound
+		}
+		if !pt.ref() {
+			unrefPartitions(pts)
+			return nil, meta2.ErrDBPTClose
+		}
+		pts = append(pts, pt)
+	}
+	return pts, nil
+}
+
+func unrefPartitions(pts []*partition) {
+	for _, pt := range pts {
+		pt.unref()
+	}
+}
+
+func (e *Engine) createPartition(db string, ptID uint32) *partition {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	pts, ok := e.partitions[db]
+	if !ok {
+		pts = make(map[uint32]*partition)
+		e.partitions[db] = pts
+	}
+	pt, ok := pts[ptID]
+	if !ok {
+		pt = newPartition(db, ptID, e.dataPath, e.walPath)
+		pts[ptID] = pt
+	}
+	return pt
+}
+
+func (e *Engine) CreateDBPT(db string, pt uint32) {
+	e.createPartition(db, pt)
+}
+
+func (e *Engine) DbPTRef(db string, ptId uint32) error {
+	_, err := e.refPartition(db, ptId)
+	return err
+}
+
+func (e *Engine) DbPTUnref(db string, ptId uint32) {
+	e.mu.RLock()
+	pt, ok := e.partitions[db][ptId]
+	e.mu.RUnlock()
+	if ok {
+		pt.unref()
+	}
+}
+
+func (e *Engine) CreateShard(db, rp string, ptId uint32, shardID uint64, timeRangeInfo *meta2.ShardTimeRangeInfo) error {
+	pt, err := e.refPartition(db, ptId)
+	if err != nil {
+		return err
+	}
+	defer pt.unref()
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if _, ok := pt.shards[shardID]; ok {
+		return nil
+	}
+	dir := shardDirName(shardID, &timeRangeInfo.TimeRange)
+	ident := &meta2.ShardIdentifier{ShardID: shardID, Policy: rp, OwnerDb: db, OwnerPt: ptId}
+	durationInfo := &meta2.DurationDescriptor{}
+	if timeRangeInfo.ShardDuration != nil {
+		ident.ShardGroupID = timeRangeInfo.ShardDuration.Ident.ShardGroupID
+		ident.ShardType = timeRangeInfo.ShardDuration.Ident.ShardType
+		durationInfo = &timeRangeInfo.ShardDuration.DurationInfo
+	}
+	sh := newShard(path.Join(pt.path, rp, dir), path.Join(pt.walPath, rp, dir), ident, &timeRangeInfo.TimeRange,
+		durationInfo, &e.opt)
+	if err = sh.open(); err != nil {
+		return err
+	}
+	pt.shards[shardID] = sh
+	return nil
+}
+
+func (e *Engine) WriteRows(db, rp string, ptId uint32, shardID uint64, rows []influx.Row, binaryRows []byte) error {
+	e.mu.RLock()
+	readOnly := e.readOnly
+	e.mu.RUnlock()
+	if readOnly {
+		return errno.NewError(errno.ErrWriteReadonly)
+	}
+
+	pt, err := e.refPartition(db, ptId)
+	if err != nil {
+		return err
+	}
+	defer pt.unref()
+
+	sh := pt.shard(shardID)
+	if sh == nil {
+		return engine.ErrShardNotFound
+	}
+	return sh.writeRows(rows, binaryRows)
+}
+
+func (e *Engine) DeleteShard(db string, ptId uint32, shardID uint64) error {
+	log.Info("start delete shard...", zap.String("db", db), zap.Uint64("shardID", shardID))
+	start := time.Now()
+	atomic.AddInt64(&stat.EngineStat.DelShardCount, 1)
+	defer func(tm time.Time) {
+		d := time.Since(tm)
+		atomic.AddInt64(&stat.EngineStat.DelShardDuration, d.Nanoseconds())
+		stat.UpdateEngineStatS()
+		log.Info("delete shard done", zap.String("db", db), zap.Uint64("shardID", shardID),
+			zap.Duration("time used", d))
+	}(start)
+
+	pt, err := e.refPartition(db, ptId)
+	if err != nil {
+		return err
+	}
+	defer pt.unref()
+
+	pt.mu.Lock()
+	sh, ok := pt.shards[shardID]
+	delete(pt.shards, shardID)
+	pt.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if err = sh.remove(); err != nil {
+		atomic.AddInt64(&stat.EngineStat.DelShardErr, 1)
+		return err
+	}
+	return nil
+}
+
+// DeleteIndex does nothing, tssp2 has no index
+func (e *Engine) DeleteIndex(db string, pt uint32, shardID uint64) error {
+	return nil
+}
+
+func (e *Engine) ExpiredShards() []*meta2.ShardIdentifier {
+	now := time.Now()
+	var res []*meta2.ShardIdentifier
+	for _, sh := range e.allShards() {
+		if sh.expired(now) {
+			ident := sh.ident
+			res = append(res, &ident)
+		}
+	}
+	return res
+}
+
+func (e *Engine) ExpiredIndexes() []*meta2.IndexIdentifier {
+	return nil
+}
+
+// FetchShardsNeedChangeStore returns no shard, the tssp2 shards are not moved
+// between the storage tiers
+func (e *Engine) FetchShardsNeedChangeStore() ([]*meta2.ShardIdentifier, []*meta2.ShardIdentifier) {
+	return nil, nil
+}
+
+func (e *Engine) ChangeShardTierToWarm(db string, ptId uint32, shardID uint64) error {
+	return errNotSupported("changing the tier of a shard")
+}
+
+func (e *Engine) GetShardSplitPoints(db string, ptId uint32, shardID uint64, idxes []int64) ([]string, error) {
+	return nil, errNotSupported("splitting a shard")
+}
+
+func (e *Engine) UpdateShardDurationInfo(info *meta2.ShardDurationInfo) error {
+	pt, err := e.refPartition(info.Ident.OwnerDb, info.Ident.OwnerPt)
+	if err != nil {
+		return err
+	}
+	defer pt.unref()
+
+	sh := pt.shard(info.Ident.ShardID)
+	if sh == nil {
+		return nil
+	}
+	sh.mu.Lock()
+	sh.ident.ShardGroupID = info.Ident.ShardGroupID
+	sh.durationInfo = info.DurationInfo
+	sh.mu.Unlock()
+	return nil
+}
+
+// PreOffloadDBPT fails the moves of the pts between the nodes, the tssp2 pts stay where they are
+func (e *Engine) PreOffloadDBPT(db string, ptId uint32) error {
+	return errNotSupported("moving a pt")
+}
+
+func (e *Engine) LoadDBPT(db string, ptId uint32, durationInfos map[uint64]*meta2.ShardDurationInfo) error {
+	return errNotSupported("moving a pt")
+}
+
+func (e *Engine) OffloadDBPT(db string, ptId uint32) error {
+	return errNotSupported("moving a pt")
+}
+
+func (e *Engine) DBPTFiles(db string, ptId uint32) ([]string, error) {
+	return nil, errNotSupported("moving a pt")
+}
+
+func (e *Engine) ReadDBPTFile(db string, ptId uint32, name string, offset int64, size int) ([]byte, error) {
+	return nil, errNotSupported("moving a pt")
+}
+
+func (e *Engine) ReceiveDBPT(db string, ptId uint32, files []string, read netstorage.DBPTFileReader) error {
+	return errNotSupported("moving a pt")
+}
+
+func (e *Engine) DeleteDatabase(db string, ptId uint32) error {
+	log.Info("drop database begin", zap.String("db", db))
+	start := time.Now()
+	atomic.AddInt64(&stat.EngineStat.DropDatabaseCount, 1)
+	defer func(tm time.Time) {
+		d := time.Since(tm)
+		atomic.AddInt64(&stat.EngineStat.DropDatabaseDurations, d.Nanoseconds())
+		stat.UpdateEngineStatS()
+		log.Info("drop database finish", zap.String("db", db), zap.Duration("time used", d))
+	}(start)
+
+	e.mu.Lock()
+	pts := e.partitions[db]
+	delete(e.partitions, db)
+	e.mu.Unlock()
+
+	lock := fileops.FileLockOption("")
+	if len(pts) == 0 {
+		pt := newPartition(db, ptId, e.dataPath, e.walPath)
+		pts = map[uint32]*partition{ptId: pt}
+	}
+	for _, pt := range pts {
+		pt.offload()
+		for _, sh := range pt.allShards() {
+			if err := sh.remove(); err != nil {
+				atomic.AddInt64(&stat.EngineStat.DropDatabaseErrs, 1)
+				return err
+			}
+		}
+		for _, dir := range []string{pt.path, pt.walPath} {
+			if err := fileops.RemoveAll(dir, lock); err != nil && !os.IsNotExist(err) {
+				atomic.AddInt64(&stat.EngineStat.DropDatabaseErrs, 1)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Engine) DropRetentionPolicy(db string, rp string, ptId uint32) error {
+	pt, err := e.refPartition(db, ptId)
+	if err != nil {
+		if err == engine.ErrPTNotFound {
+			return nil
+		}
+		return err
+	}
+	defer pt.unref()
+
+	pt.mu.Lock()
+	var shards []*shard
+	for id, sh := range pt.shards {
+		if sh.ident.Policy == rp {
+			shards = append(shards, sh)
+			delete(pt.shards, id)
+		}
+	}
+	pt.mu.Unlock()
+
+	for _, sh := range shards {
+		if err = sh.remove(); err != nil {
+			return err
+		}
+	}
+	lock := fileops.FileLockOption("")
+	for _, dir := range []string{path.Join(pt.path, rp), path.Join(pt.walPath, rp)} {
+		if err = fileops.RemoveAll(dir, lock); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) DropMeasurement(db string, rp string, name string, shardIds []uint64) error {
+	ids := make(map[uint64]struct{}, len(shardIds))
+	for _, id := range shardIds {
+		ids[id] = struct{}{}
+	}
+	for _, sh := range e.allShards() {
+		if _, ok := ids[sh.ident.ShardID]; !ok || sh.ident.OwnerDb != db || sh.ident.Policy != rp {
+			continue
+		}
+		if err := sh.dropMeasurement(name); err != nil && err != engine.ErrShardClosed {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropSeries is not supported, the rows of a series are not indexed
+func (e *Engine) DropSeries(database string, sources []influxql.Source, ptId []uint32, condition influxql.Expr) (int, error) {
+	return 0, errNotSupported("drop series")
+}
+
+// walkShards calls fn with the shards of the partitions of a database
+func (e *Engine) walkShards(db string, ptIDs []uint32, fn func(sh *shard) error) error {
+	pts, err := e.refPartitions(db, ptIDs)
+	if err != nil {
+		return err
+	}
+	defer unrefPartitions(pts)
+	for _, pt := range pts {
+		for _, sh := range pt.allShards() {
+			if err = fn(sh); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Engine) SeriesKeys(db string, ptIDs []uint32, measurements [][]byte, condition influxql.Expr) ([]string, error) {
+	keys := make(map[string]struct{})
+	err := e.walkShards(db, ptIDs, func(sh *shard) error {
+		for _, name := range measurements {
+			if err := sh.seriesKeys(string(name), condition, keys); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(keys))
+	for key := range keys {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (e *Engine) SeriesCardinality(db string, ptIDs []uint32, measurements [][]byte, condition influxql.Expr) ([]meta2.MeasurementCardinalityInfo, error) {
+	infos := make([]meta2.MeasurementCardinalityInfo, len(measurements))
+	for i, name := range measurements {
+		infos[i].Name = string(name)
+	}
+	err := e.walkShards(db, ptIDs, func(sh *shard) error {
+		for i, name := range measurements {
+			keys := make(map[string]struct{})
+			if err := sh.seriesKeys(string(name), condition, keys); err != nil {
+				return err
+			}
+			if len(keys) == 0 {
+				continue
+			}
+			infos[i].CardinalityInfos = append(infos[i].CardinalityInfos, meta2.CardinalityInfo{
+				TimeRange:   meta2.TimeRangeInfo{StartTime: sh.startTime, EndTime: sh.endTime},
+				Cardinality: uint64(len(keys)),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := infos[:0]
+	for _, info := range infos {
+		if len(info.CardinalityInfos) > 0 {
+			result = append(result, info)
+		}
+	}
+	return result, nil
+}
+
+func (e *Engine) SeriesExactCardinality(db string, ptIDs []uint32, measurements [][]byte, condition influxql.Expr) (map[string]uint64, error) {
+	keys := make([]map[string]struct{}, len(measurements))
+	for i := range keys {
+		keys[i] = make(map[string]struct{})
+	}
+	err := e.walkShards(db, ptIDs, func(sh *shard) error {
+		for i, name := range measurements {
+			if err := sh.seriesKeys(string(name), condition, keys[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]uint64, len(measurements))
+	for i, name := range measurements {
+		result[string(name)] = uint64(len(keys[i]))
+	}
+	return result, nil
+}
+
+// tagValues returns the values of the tag keys of each measurement
+func (e *Engine) tagValues(db string, ptIDs []uint32, tagKeys map[string][][]byte, condition influxql.Expr) (map[string][]map[string]struct{}, error) {
+	values := make(map[string][]map[string]struct{}, len(tagKeys))
+	for name, tks := range tagKeys {
+		values[name] = make([]map[string]struct{}, len(tks))
+		for i := range tks {
+			values[name][i] = make(map[string]struct{})
+		}
+	}
+	err := e.walkShards(db, ptIDs, func(sh *shard) error {
+		for name, tks := range tagKeys {
+			keys := make([]string, len(tks))
+			for i := range tks {
+				keys[i] = string(tks[i])
+			}
+			if err := sh.tagValues(name, keys, condition, values[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return values, err
+}
+
+func (e *Engine) TagValues(db string, ptIDs []uint32, tagKeys map[string][][]byte, condition influxql.Expr) (netstorage.TablesTagSets, error) {
+	values, err := e.tagValues(db, ptIDs, tagKeys, condition)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(netstorage.TablesTagSets, 0, len(values))
+	for name, tks := range tagKeys {
+		tv := netstorage.TableTagSets{Name: name}
+		for i, tk := range tks {
+			vs := make([]string, 0, len(values[name][i]))
+			for v := range values[name][i] {
+				vs = append(vs, v)
+			}
+			sort.Strings(vs)
+			for _, v := range vs {
+				tv.Values = append(tv.Values, netstorage.TagSet{Key: string(tk), Value: v})
+			}
+		}
+		if len(tv.Values) > 0 {
+			result = append(result, tv)
+		}
+	}
+	return result, nil
+}
+
+func (e *Engine) TagValuesCardinality(db string, ptIDs []uint32, tagKeys map[string][][]byte, condition influxql.Expr) (map[string]uint64, error) {
+	values, err := e.tagValues(db, ptIDs, tagKeys, condition)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]uint64, len(tagKeys))
+	for name, vs := range values {
+		seen := make(map[string]struct{})
+		for i := range vs {
+			for v := range vs[i] {
+				seen[v] = struct{}{}
+			}
+		}
+		result[name] = uint64(len(seen))
+	}
+	return result, nil
+}
+
+func (e *Engine) CreateLogicalPlan(ctx context.Context, db string, ptId uint32, shardID uint64,
+	sources influxql.Sources, schema *executor.QuerySchema) (hybridqp.QueryNode, error) {
+	e.mu.RLock()
+	pt, ok := e.partitions[db][ptId]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+	sh := pt.shard(shardID)
+	if sh == nil {
+		return nil, nil
+	}
+
+	opt := schema.Options().(*query.ProcessorOptions)
+	opt.Sources = sources
+	var cursors []comm.KeyCursor
+	for _, source := range sources {
+		mm, ok := source.(*influxql.Measurement)
+		if !ok {
+			closeCursors(cursors)
+			return nil, fmt.Errorf("%v not a measurement", source.String())
+		}
+		opt.Name = mm.Name
+		cur, err := newTableCursor(sh, schema)
+		if err != nil {
+			closeCursors(cursors)
+			return nil, err
+		}
+		if cur != nil {
+			cursors = append(cursors, cur)
+		}
+	}
+
+	if len(cursors) == 0 {
+		return executor.NewLogicalDummyShard([][]interface{}{nil}), nil
+	}
+	// one reader reads the cursors of all the measurements, sorted like tssp1 does
+	sort.Slice(cursors, func(i, j int) bool {
+		return cursors[i].Name() < cursors[j].Name()
+	})
+	reader := make([]interface{}, 0, len(cursors))
+	for _, cur := range cursors {
+		reader = append(reader, cur)
+	}
+	return executor.NewLogicalDummyShard([][]interface{}{reader}), nil
+}
+
+// closeCursors releases the files held by the cursors of a plan not returned
+func closeCursors(cursors []comm.KeyCursor) {
+	for _, cur := range cursors {
+		_ = cur.Close()
+	}
+}
+
+func (e *Engine) LogicalPlanCost(db string, ptId uint32, sources influxql.Sources, opt query.ProcessorOptions) (hybridqp.LogicalPlanCost, error) {
+	return hybridqp.LogicalPlanCost{}, nil
+}
+
+func (e *Engine) SysCtrl(req *netstorage.SysCtrlRequest) error {
+	switch req.Mod() {
+	case "flush":
+		e.ForceFlush()
+		return nil
+	case "readonly":
+		readonly, err := strconv.ParseBool(req.Param()["switchon"])
+		if err != nil {
+			return err
+		}
+		e.SetReadOnly(readonly)
+		return nil
+	default:
+		return fmt.Errorf("unknown sys cmd %v", req.Mod())
+	}
+}
+
+func (e *Engine) Statistics(buffer []byte) ([]byte, error) {
+	e.statCount++
+	if stat.FileStatisticsLimited(e.statCount) {
+		return nil, nil
+	}
+	if e.closed.Closed() {
+		return buffer, nil
+	}
+	for _, sh := range e.allShards() {
+		buffer, _ = sh.statistics(buffer)
+	}
+	return buffer, nil
+}
@@ -0,0 +1,466 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/engine/comm"
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testDb      = "db0"
+	testRp      = "rp0"
+	testPt      = uint32(0)
+	testShard   = uint64(1)
+	testMst     = "logs_0000"
+	testBaseSec = 1600000000
+)
+
+func openTestEngine(t *testing.T, dir string, sortKey []string, load bool) *Engine {
+	opt := netstorage.NewEngineOptions()
+	opt.SortKey = sortKey
+	eng, err := NewEngine(dir, dir, opt, nil)
+	require.NoError(t, err)
+
+	ident := meta2.ShardIdentifier{ShardID: testShard, Policy: testRp, OwnerDb: testDb, OwnerPt: testPt}
+	infos := map[uint64]*meta2.ShardDurationInfo{}
+	if load {
+		infos[testShard] = &meta2.ShardDurationInfo{Ident: ident}
+	}
+	require.NoError(t, eng.Open(nil, infos))
+	e := eng.(*Engine)
+	if !load {
+		e.CreateDBPT(testDb, testPt)
+		err = e.CreateShard(testDb, testRp, testPt, testShard, &meta2.ShardTimeRangeInfo{
+			TimeRange: meta2.TimeRangeInfo{
+				StartTime: time.Unix(testBaseSec, 0),
+				EndTime:   time.Unix(testBaseSec, 0).Add(24 * time.Hour),
+			},
+			ShardDuration: &meta2.ShardDurationInfo{Ident: ident},
+		})
+		require.NoError(t, err)
+	}
+	return e
+}
+
+// crash stops an engine without flushing its shards
+func crash(e *Engine) {
+	e.closed.Close()
+	e.wg.Wait()
+	for _, sh := range e.allShards() {
+		_ = sh.wal.close()
+		sh.closeTables()
+	}
+}
+
+func testRow(host, trace string, sec int64, v int64) influx.Row {
+	row := influx.Row{
+		Name:      testMst,
+		Timestamp: time.Unix(testBaseSec+sec, 0).UnixNano(),
+		Fields:    influx.Fields{{Key: "v", NumValue: float64(v), Type: influx.Field_Type_Int}},
+	}
+	if host != "" {
+		row.Tags = append(row.Tags, influx.Tag{Key: "host", Value: host})
+	}
+	if trace != "" {
+		row.Tags = append(row.Tags, influx.Tag{Key: "trace", Value: trace})
+	}
+	return row
+}
+
+func testRows() []influx.Row {
+	return []influx.Row{
+		testRow("b", "t1", 3, 30),
+		testRow("a", "t2", 2, 20),
+		testRow("a", "t1", 1, 10),
+		testRow("b", "t2", 4, 40),
+		testRow("", "t3", 5, 50),
+	}
+}
+
+// readRows reads the rows of the test measurement, each row formatted as
+// "<group tags>|<second>|<columns>"
+func readRows(t *testing.T, e *Engine, fields influxql.Fields, dims []string, cond string) []string {
+	cur, err := testCursor(e, testSchema(fields, dims, cond, 0))
+	require.NoError(t, err)
+	if cur == nil {
+		return nil
+	}
+	return readCursor(t, cur)
+}
+
+func testSchema(fields influxql.Fields, dims []string, cond string, maxQueryMem int64) *executor.QuerySchema {
+	opt := query.ProcessorOptions{
+		StartTime:   influxql.MinTime,
+		EndTime:     influxql.MaxTime,
+		ChunkSize:   2,
+		Ascending:   true,
+		Dimensions:  dims,
+		MaxQueryMem: maxQueryMem,
+	}
+	if cond != "" {
+		opt.Condition = influxql.MustParseExpr(cond)
+		influxql.WalkFunc(opt.Condition, func(n influxql.Node) {
+			if ref, ok := n.(*influxql.VarRef); ok && ref.Val != "v" {
+				ref.Type = influxql.Tag
+			}
+		})
+	}
+	names := make([]string, len(fields))
+	for i := range fields {
+		names[i] = fields[i].Name()
+	}
+	return executor.NewQuerySchema(fields, names, &opt)
+}
+
+// testCursor returns the cursor of the test measurement, nil if no row matches
+func testCursor(e *Engine, schema *executor.QuerySchema) (comm.KeyCursor, error) {
+	plan, err := e.CreateLogicalPlan(context.Background(), testDb, testPt, testShard,
+		influxql.Sources{&influxql.Measurement{Name: testMst}}, schema)
+	if err != nil {
+		return nil, err
+	}
+	readers := plan.(*executor.LogicalDummyShard).Readers()
+	if readers[0] == nil {
+		return nil, nil
+	}
+	return readers[0][0].(comm.KeyCursor), nil
+}
+
+func readCursor(t *testing.T, cur comm.KeyCursor) []string {
+	defer func() {
+		require.NoError(t, cur.Close())
+	}()
+	var res []string
+	for {
+		rec, _, err := cur.Next()
+		require.NoError(t, err)
+		if rec == nil {
+			return res
+		}
+		require.True(t, rec.RowNums() <= 2)
+		tags, _ := rec.GetTagIndexAndKey()
+		require.Equal(t, 1, len(tags))
+		group := executor.NewChunkTagsV2(*tags[0]).KeyValues()
+		for i, tm := range rec.Times() {
+			var cols []string
+			for c := 0; c < len(rec.Schema)-1; c++ {
+				cols = append(cols, formatValue(rec, c, i))
+			}
+			res = append(res, fmt.Sprintf("%v|%d|%s", group, (tm/1e9)-testBaseSec, strings.Join(cols, ",")))
+		}
+	}
+}
+
+func formatValue(rec *record.Record, c, row int) string {
+	col := rec.Column(c)
+	if col.IsNil(row) {
+		return "null"
+	}
+	switch rec.Schema[c].Type {
+	case influx.Field_Type_Int:
+		return fmt.Sprint(newColumnValues(rec, rec.Schema[c].Name, false).value(row))
+	default:
+		s, _ := col.StringValueSafe(row)
+		return s
+	}
+}
+
+func fieldsOf(refs ...*influxql.VarRef) influxql.Fields {
+	fields := make(influxql.Fields, 0, len(refs))
+	for _, ref := range refs {
+		fields = append(fields, &influxql.Field{Expr: ref})
+	}
+	return fields
+}
+
+var (
+	refV     = &influxql.VarRef{Val: "v", Type: influxql.Integer}
+	refTrace = &influxql.VarRef{Val: "trace", Type: influxql.Tag}
+)
+
+func TestEngine_WriteAndQuery(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), []string{"host"}, false)
+	defer e.Close()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows(), nil))
+
+	expect := []string{
+		"map[]|1|10",
+		"map[]|2|20",
+		"map[]|3|30",
+		"map[]|4|40",
+		"map[]|5|50",
+	}
+	require.Equal(t, expect, readRows(t, e, fieldsOf(refV), nil, ""))
+	e.ForceFlush()
+	require.Equal(t, expect, readRows(t, e, fieldsOf(refV), nil, ""))
+
+	// rows in the files and in the memTable
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, []influx.Row{testRow("a", "t4", 0, 0)}, nil))
+	require.Equal(t, []string{"map[]|0|0", "map[]|1|10", "map[]|2|20"}, readRows(t, e, fieldsOf(refV), nil, "host = 'a'"))
+	require.Equal(t, []string{"map[]|3|30", "map[]|4|40"}, readRows(t, e, fieldsOf(refV), nil, "host = 'b' AND v > 0"))
+	require.Nil(t, readRows(t, e, fieldsOf(refV), nil, "host = 'c'"))
+}
+
+func TestEngine_GroupByAndAuxTags(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), []string{"host", "trace"}, false)
+	defer e.Close()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows(), nil))
+	e.ForceFlush()
+
+	require.Equal(t, []string{
+		"map[host:]|5|50",
+		"map[host:a]|1|10",
+		"map[host:a]|2|20",
+		"map[host:b]|3|30",
+		"map[host:b]|4|40",
+	}, readRows(t, e, fieldsOf(refV), []string{"host"}, ""))
+
+	// the series of a group are merged in time order
+	require.Equal(t, []string{
+		"map[host:]|5|50,t3",
+		"map[host:a]|1|10,t1",
+		"map[host:a]|2|20,t2",
+		"map[host:b]|3|30,t1",
+		"map[host:b]|4|40,t2",
+	}, readRows(t, e, fieldsOf(refV, refTrace), []string{"host"}, ""))
+}
+
+func TestEngine_PreAgg(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), nil, false)
+	defer e.Close()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows(), nil))
+
+	var fields influxql.Fields
+	var ops []*comm.CallOption
+	for _, name := range []string{"count", "sum", "max"} {
+		call := &influxql.Call{Name: name, Args: []influxql.Expr{refV}}
+		fields = append(fields, &influxql.Field{Expr: call})
+		ops = append(ops, &comm.CallOption{Call: call, Ref: refV})
+	}
+	opt := query.ProcessorOptions{StartTime: influxql.MinTime, EndTime: influxql.MaxTime, ChunkSize: 10,
+		Ascending: true, Dimensions: []string{"host"}}
+	schema := executor.NewQuerySchema(fields, []string{"count", "sum", "max"}, &opt)
+	plan, err := e.CreateLogicalPlan(context.Background(), testDb, testPt, testShard,
+		influxql.Sources{&influxql.Measurement{Name: testMst}}, schema)
+	require.NoError(t, err)
+	cur := plan.(*executor.LogicalDummyShard).Readers()[0][0].(comm.KeyCursor)
+	cur.SetOps(ops)
+
+	var res []string
+	for {
+		rec, info, err := cur.Next()
+		require.NoError(t, err)
+		if rec == nil {
+			break
+		}
+		meta := rec.ColMeta[rec.Schema.FieldIndex("v")]
+		max, tm := meta.Max()
+		res = append(res, fmt.Sprintf("%s %v %v %v %d", info.GetSeriesKey(), meta.Count(), meta.Sum(), max,
+			tm/1e9-testBaseSec))
+	}
+	require.Equal(t, []string{
+		" 1 50 50 5",
+		"host\x00a\x00 2 30 20 2",
+		"host\x00b\x00 2 70 40 4",
+	}, res)
+}
+
+func TestEngine_ReplayWal(t *testing.T) {
+	dir := t.TempDir()
+	e := openTestEngine(t, dir, []string{"host"}, false)
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows()[:2], nil))
+	e.ForceFlush()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows()[2:], nil))
+	crash(e)
+
+	e = openTestEngine(t, dir, []string{"host"}, true)
+	expect := []string{"map[]|1|10", "map[]|2|20", "map[]|3|30", "map[]|4|40", "map[]|5|50"}
+	require.Equal(t, expect, readRows(t, e, fieldsOf(refV), nil, ""))
+	require.NoError(t, e.Close())
+
+	e = openTestEngine(t, dir, []string{"host"}, true)
+	defer e.Close()
+	require.Equal(t, expect, readRows(t, e, fieldsOf(refV), nil, ""))
+}
+
+func TestEngine_FieldTypeConflict(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), nil, false)
+	defer e.Close()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows(), nil))
+	e.ForceFlush()
+
+	row := testRow("a", "", 6, 0)
+	row.Fields[0].Type = influx.Field_Type_Float
+	err := e.WriteRows(testDb, testRp, testPt, testShard, []influx.Row{row}, nil)
+	require.True(t, errno.Equal(err, errno.FieldTypeConflict))
+
+	rows := []influx.Row{testRow("a", "", 6, 0), row}
+	rows[0].Fields[0].Key = "w"
+	rows[1].Fields[0].Key = "w"
+	err = e.WriteRows(testDb, testRp, testPt, testShard, rows, nil)
+	require.True(t, errno.Equal(err, errno.FieldTypeConflict))
+}
+
+func TestEngine_Metadata(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), []string{"host"}, false)
+	defer e.Close()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows()[:3], nil))
+	e.ForceFlush()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows()[3:], nil))
+
+	names := [][]byte{[]byte(testMst)}
+	keys, err := e.SeriesKeys(testDb, []uint32{testPt}, names, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		testMst + ",host=a,trace=t1",
+		testMst + ",host=a,trace=t2",
+		testMst + ",host=b,trace=t1",
+		testMst + ",host=b,trace=t2",
+		testMst + ",trace=t3",
+	}, keys)
+
+	cardinality, err := e.SeriesExactCardinality(testDb, []uint32{testPt}, names, influxql.MustParseExpr("host = 'a'"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), cardinality[testMst])
+
+	tagKeys := map[string][][]byte{testMst: {[]byte("trace")}}
+	values, err := e.TagValues(testDb, []uint32{testPt}, tagKeys, influxql.MustParseExpr("host = 'b'"))
+	require.NoError(t, err)
+	require.Equal(t, netstorage.TablesTagSets{{Name: testMst, Values: netstorage.TagSets{
+		{Key: "trace", Value: "t1"}, {Key: "trace", Value: "t2"},
+	}}}, values)
+
+	n, err := e.TagValuesCardinality(testDb, []uint32{testPt}, tagKeys, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), n[testMst])
+
+	_, err = e.SeriesKeys(testDb, []uint32{1}, names, nil)
+	require.Error(t, err)
+}
+
+func TestEngine_DropMeasurementAndShard(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), nil, false)
+	defer e.Close()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows(), nil))
+	e.ForceFlush()
+
+	require.NoError(t, e.DropMeasurement(testDb, testRp, testMst, []uint64{testShard}))
+	require.Nil(t, readRows(t, e, fieldsOf(refV), nil, ""))
+
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, testRows(), nil))
+	require.NoError(t, e.DeleteShard(testDb, testPt, testShard))
+	err := e.WriteRows(testDb, testRp, testPt, testShard, testRows(), nil)
+	require.Error(t, err)
+}
+
+func TestEngine_AggregateOnSeries(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), []string{"host"}, false)
+	defer e.Close()
+	rows := append(testRows(), testRow("a", "t1", 3, 5))
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, rows, nil))
+
+	call := &influxql.Call{Name: "sum", Args: []influxql.Expr{refV}}
+	fields := influxql.Fields{&influxql.Field{Expr: call}}
+	opt := query.ProcessorOptions{StartTime: influxql.MinTime, EndTime: influxql.MaxTime, ChunkSize: 10,
+		Ascending: true, Dimensions: []string{"host"}, Interval: hybridqp.Interval{Duration: 2 * time.Second}}
+	schema := executor.NewQuerySchema(fields, []string{"sum"}, &opt)
+	plan, err := e.CreateLogicalPlan(context.Background(), testDb, testPt, testShard,
+		influxql.Sources{&influxql.Measurement{Name: testMst}}, schema)
+	require.NoError(t, err)
+	cur := plan.(*executor.LogicalDummyShard).Readers()[0][0].(comm.KeyCursor)
+	series := executor.NewLogicalSeries(schema)
+	cur.SinkPlan(executor.NewLogicalExchange(executor.NewLogicalAggregate(series, schema), executor.SERIES_EXCHANGE, nil, schema))
+
+	var res []string
+	for {
+		rec, _, err := cur.Next()
+		require.NoError(t, err)
+		if rec == nil {
+			break
+		}
+		tags, _ := rec.GetTagIndexAndKey()
+		group := executor.NewChunkTagsV2(*tags[0]).KeyValues()
+		for i, tm := range rec.Times() {
+			res = append(res, fmt.Sprintf("%v|%d|%s", group, tm/1e9-testBaseSec, formatValue(rec, 0, i)))
+		}
+	}
+	require.Equal(t, []string{
+		"map[host:]|5|50",
+		"map[host:a]|1|10",
+		"map[host:a]|2|25",
+		"map[host:b]|3|30",
+		"map[host:b]|4|40",
+	}, res)
+}
+
+func TestEngine_ReadByBatches(t *testing.T) {
+	e := openTestEngine(t, t.TempDir(), []string{"host", "trace"}, false)
+	defer e.Close()
+	var rows []influx.Row
+	for i := 0; i < 200; i++ {
+		rows = append(rows, testRow(fmt.Sprintf("h%02d", i%20), fmt.Sprintf("t%d", i%3), int64(200-i), int64(i)))
+	}
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, rows[:120], nil))
+	e.ForceFlush()
+	require.NoError(t, e.WriteRows(testDb, testRp, testPt, testShard, rows[120:], nil))
+
+	for _, fields := range []influxql.Fields{fieldsOf(refV), fieldsOf(refV, refTrace)} {
+		cur, err := testCursor(e, testSchema(fields, []string{"host"}, "v >= 10", 0))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(cur.(*tableCursor).reader.batches))
+		expect := readCursor(t, cur)
+		require.Equal(t, 190, len(expect))
+
+		// a group of 10 rows of 16 bytes, 3 groups by batch
+		cur, err = testCursor(e, testSchema(fields, []string{"host"}, "v >= 10", 500))
+		require.NoError(t, err)
+		reader := cur.(*tableCursor).reader
+		require.Equal(t, 7, len(reader.batches))
+		require.Equal(t, expect, readCursor(t, cur))
+		require.Nil(t, reader.snap)
+	}
+
+	// the rows of a group do not fit
+	_, err := testCursor(e, testSchema(fieldsOf(refV), nil, "", 500))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max-query-mem")
+
+	// the files are released by a cursor closed before its last batch
+	cur, err := testCursor(e, testSchema(fieldsOf(refV), []string{"host"}, "", 500))
+	require.NoError(t, err)
+	files := cur.(*tableCursor).reader.snap.files
+	require.Equal(t, 1, len(files))
+	require.Equal(t, int32(2), files[0].refs)
+	require.NoError(t, cur.Close())
+	require.Equal(t, int32(1), files[0].refs)
+}
@@ -0,0 +1,622 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sync/atomic"
+
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/lib/numberenc"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"go.uber.org/zap"
+)
+
+const (
+	fileSuffix    = ".tssp2"
+	tmpFileSuffix = ".init"
+	fileVersion   = 1
+
+	// granuleRows is the number of rows of a granule, the unit of the sparse index
+	granuleRows = 1024
+
+	footerSize     = 8 + 4 + 4 + 8
+	writeBufSize   = 256 * 1024
+	maxIndexLength = 1 << 30
+)
+
+var fileMagic = []byte("TSSP2COL")
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// block is the location in the file of the values of one column in a granule
+type block struct {
+	offset int64
+	size   int64
+}
+
+// granule is a block of consecutive rows of a file. The sparse primary index
+// keeps the time range and the sort key values of its first and last rows, so
+// granules are skipped without reading the column data.
+type granule struct {
+	rows    int
+	minTime int64
+	maxTime int64
+	first   []string
+	last    []string
+	blocks  []block
+}
+
+// mayContain returns true if the rows of the granule may have the sort key
+// prefix, the rows of a file are sorted by the sort key values.
+func (g *granule) mayContain(prefix []string) bool {
+	return compareTuple(g.first, prefix) <= 0 && compareTuple(prefix, g.last) <= 0
+}
+
+// compareTuple compares the tuple a with the first len(b) values of b, or b
+// with the first len(a) values of a if a is the shorter.
+func compareTuple(a, b []string) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// tableFile is an immutable column file of one measurement. The file holds the
+// rows sorted by the sort key then by time, split into granules:
+//
+//	magic | granule 0: block of column 0 ... block of column n | granule 1 ... | index | footer
+//
+// The index describes the columns, the sort key and the granules. The footer
+// holds the offset, the length and the checksum of the index.
+type tableFile struct {
+	path     string
+	fd       fileops.File
+	size     int64
+	columns  record.Schemas
+	sortKey  []string
+	granules []granule
+	rows     int
+	minTime  int64
+	maxTime  int64
+
+	refs    int32
+	removed int32
+}
+
+func (f *tableFile) ref() {
+	atomic.AddInt32(&f.refs, 1)
+}
+
+// unref closes the file when the last reader is done with a removed file
+func (f *tableFile) unref() {
+	if atomic.AddInt32(&f.refs, -1) == 0 && atomic.LoadInt32(&f.removed) == 1 {
+		if err := f.fd.Close(); err != nil {
+			log.Warn("close column file failed", zap.String("path", f.path), zap.Error(err))
+		}
+		if err := fileops.Remove(f.path); err != nil {
+			log.Warn("remove column file failed", zap.String("path", f.path), zap.Error(err))
+		}
+	}
+}
+
+// close releases the file, the file is removed too when remove is true. The
+// readers still using the file finish before it is closed.
+func (f *tableFile) close(remove bool) {
+	if remove {
+		atomic.StoreInt32(&f.removed, 1)
+	} else {
+		atomic.StoreInt32(&f.removed, 2)
+	}
+	if atomic.AddInt32(&f.refs, -1) > 0 {
+		return
+	}
+	if err := f.fd.Close(); err != nil {
+		log.Warn("close column file failed", zap.String("path", f.path), zap.Error(err))
+	}
+	if remove {
+		if err := fileops.Remove(f.path); err != nil {
+			log.Warn("remove column file failed", zap.String("path", f.path), zap.Error(err))
+		}
+	}
+}
+
+func (f *tableFile) columnIndex(name string, tag bool) int {
+	for i := range f.columns {
+		if f.columns[i].Name == name && (f.columns[i].Type == influx.Field_Type_Tag) == tag {
+			return i
+		}
+	}
+	return -1
+}
+
+// readGranule reads the columns of the granule g selected by want, the time
+// column is always read and is the last column of the record.
+func (f *tableFile) readGranule(g int, want func(field *record.Field) bool, ctx *immutable.CoderContext) (*record.Record, error) {
+	gr := &f.granules[g]
+	timeIdx := len(f.columns) - 1
+
+	schema := make(record.Schemas, 0, len(f.columns))
+	idxes := make([]int, 0, len(f.columns))
+	for i := 0; i < timeIdx; i++ {
+		if want(&f.columns[i]) {
+			schema = append(schema, f.columns[i])
+			idxes = append(idxes, i)
+		}
+	}
+	schema = append(schema, f.columns[timeIdx])
+	idxes = append(idxes, timeIdx)
+
+	// the blocks of a granule are contiguous, read them at once
+	start, end := gr.blocks[idxes[0]].offset, int64(0)
+	for _, i := range idxes {
+		b := gr.blocks[i]
+		if b.offset < start {
+			start = b.offset
+		}
+		if b.offset+b.size > end {
+			end = b.offset + b.size
+		}
+	}
+	buf := make([]byte, end-start)
+	if _, err := f.fd.ReadAt(buf, start); err != nil {
+		return nil, err
+	}
+
+	rec := record.NewRecord(schema, false)
+	for i, idx := range idxes {
+		b := gr.blocks[idx]
+		data := buf[b.offset-start : b.offset-start+b.size]
+		if err := decodeBlock(schema[i].Type, schema[i].Name == record.TimeField, gr.rows, data, &rec.ColVals[i], ctx); err != nil {
+			return nil, fmt.Errorf("decode column %s of %s: %v", schema[i].Name, f.path, err)
+		}
+	}
+	return rec, nil
+}
+
+// writeTableFile writes the record to a new column file. The rows of the record
+// are sorted by the sort key then by time.
+func writeTableFile(path string, rec *record.Record, sortKey []string, lock fileops.FileLockOption) (*tableFile, error) {
+	tmp := path + tmpFileSuffix
+	fd, err := fileops.Create(tmp, lock)
+	if err != nil {
+		return nil, err
+	}
+	ok := false
+	defer func() {
+		if !ok {
+			_ = fd.Close()
+			_ = fileops.Remove(tmp, lock)
+		}
+	}()
+
+	w := bufio.NewWriterSize(fd, writeBufSize)
+	if _, err = w.Write(fileMagic); err != nil {
+		return nil, err
+	}
+
+	f := &tableFile{
+		path:    path,
+		columns: append(record.Schemas{}, rec.Schema...),
+		sortKey: sortKey,
+		rows:    rec.RowNums(),
+	}
+	keyIdx := make([]int, len(sortKey))
+	for i, name := range sortKey {
+		keyIdx[i] = f.columnIndex(name, true)
+	}
+
+	ctx := immutable.NewCoderContext()
+	defer ctx.Release()
+	var sub record.ColVal
+	var data []byte
+	offset := int64(len(fileMagic))
+	times := rec.Times()
+	for start := 0; start < f.rows; start += granuleRows {
+		end := start + granuleRows
+		if end > f.rows {
+			end = f.rows
+		}
+		g := granule{
+			rows:    end - start,
+			minTime: times[start],
+			maxTime: times[start],
+			first:   make([]string, len(sortKey)),
+			last:    make([]string, len(sortKey)),
+			blocks:  make([]block, len(f.columns)),
+		}
+		for _, t := range times[start:end] {
+			if t < g.minTime {
+				g.minTime = t
+			}
+			if t > g.maxTime {
+				g.maxTime = t
+			}
+		}
+		for i, idx := range keyIdx {
+			if idx >= 0 {
+				g.first[i], _ = rec.ColVals[idx].StringValueSafe(start)
+				g.last[i], _ = rec.ColVals[idx].StringValueSafe(end - 1)
+			}
+		}
+
+		for i := range f.columns {
+			typ := storeType(f.columns[i].Type)
+			sub.Init()
+			sub.AppendColVal(&rec.ColVals[i], typ, start, end)
+			data, err = encodeBlock(typ, f.columns[i].Name == record.TimeField, &sub, data[:0], ctx)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = w.Write(data); err != nil {
+				return nil, err
+			}
+			g.blocks[i] = block{offset: offset, size: int64(len(data))}
+			offset += int64(len(data))
+		}
+		f.granules = append(f.granules, g)
+	}
+	f.setTimeRange()
+
+	index := f.marshalIndex(nil)
+	footer := make([]byte, 0, footerSize)
+	footer = numberenc.MarshalUint64Append(footer, uint64(offset))
+	footer = numberenc.MarshalUint32Append(footer, uint32(len(index)))
+	footer = numberenc.MarshalUint32Append(footer, crc32.Checksum(index, crcTable))
+	footer = append(footer, fileMagic...)
+	if _, err = w.Write(index); err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(footer); err != nil {
+		return nil, err
+	}
+	if err = w.Flush(); err != nil {
+		return nil, err
+	}
+	if err = fd.Sync(); err != nil {
+		return nil, err
+	}
+	if err = fd.Close(); err != nil {
+		return nil, err
+	}
+	if err = fileops.RenameFile(tmp, path, lock); err != nil {
+		return nil, err
+	}
+	ok = true
+
+	f.size = offset + int64(len(index)) + footerSize
+	if f.fd, err = fileops.Open(path); err != nil {
+		return nil, err
+	}
+	f.refs = 1
+	return f, nil
+}
+
+// openTableFile opens a column file and loads its index
+func openTableFile(path string) (*tableFile, error) {
+	fd, err := fileops.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &tableFile{path: path, fd: fd, refs: 1}
+	if err = f.loadIndex(); err != nil {
+		_ = fd.Close()
+		return nil, fmt.Errorf("load index of %s: %v", path, err)
+	}
+	return f, nil
+}
+
+func (f *tableFile) loadIndex() error {
+	fi, err := f.fd.Stat()
+	if err != nil {
+		return err
+	}
+	f.size = fi.Size()
+	if f.size < int64(len(fileMagic))+footerSize {
+		return fmt.Errorf("file too small, %d bytes", f.size)
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err = f.fd.ReadAt(footer, f.size-footerSize); err != nil {
+		return err
+	}
+	if !bytes.Equal(footer[16:], fileMagic) {
+		return fmt.Errorf("invalid magic %q", footer[16:])
+	}
+	offset := int64(binary.BigEndian.Uint64(footer))
+	length := int64(binary.BigEndian.Uint32(footer[8:]))
+	checksum := binary.BigEndian.Uint32(footer[12:])
+	if length > maxIndexLength || offset+length+footerSize != f.size {
+		return fmt.Errorf("invalid index offset %d length %d", offset, length)
+	}
+
+	index := make([]byte, length)
+	if _, err = f.fd.ReadAt(index, offset); err != nil && err != io.EOF {
+		return err
+	}
+	if crc32.Checksum(index, crcTable) != checksum {
+		return fmt.Errorf("index checksum mismatch")
+	}
+	if err = f.unmarshalIndex(index); err != nil {
+		return err
+	}
+	f.setTimeRange()
+	return nil
+}
+
+func (f *tableFile) setTimeRange() {
+	f.rows = 0
+	for i := range f.granules {
+		g := &f.granules[i]
+		if i == 0 || g.minTime < f.minTime {
+			f.minTime = g.minTime
+		}
+		if i == 0 || g.maxTime > f.maxTime {
+			f.maxTime = g.maxTime
+		}
+		f.rows += g.rows
+	}
+}
+
+func (f *tableFile) marshalIndex(dst []byte) []byte {
+	dst = appendUvarint(dst, fileVersion)
+	dst = appendUvarint(dst, uint64(len(f.columns)))
+	for i := range f.columns {
+		dst = appendString(dst, f.columns[i].Name)
+		dst = append(dst, byte(f.columns[i].Type))
+	}
+	dst = appendUvarint(dst, uint64(len(f.sortKey)))
+	for _, name := range f.sortKey {
+		dst = appendString(dst, name)
+	}
+	dst = appendUvarint(dst, uint64(len(f.granules)))
+	for i := range f.granules {
+		g := &f.granules[i]
+		dst = appendUvarint(dst, uint64(g.rows))
+		dst = appendVarint(dst, g.minTime)
+		dst = appendVarint(dst, g.maxTime)
+		for j := range f.sortKey {
+			dst = appendString(dst, g.first[j])
+			dst = appendString(dst, g.last[j])
+		}
+		for _, b := range g.blocks {
+			dst = appendUvarint(dst, uint64(b.offset))
+			dst = appendUvarint(dst, uint64(b.size))
+		}
+	}
+	return dst
+}
+
+func (f *tableFile) unmarshalIndex(src []byte) error {
+	d := &indexDecoder{src: src}
+	if v := d.uvarint(); v != fileVersion && d.err == nil {
+		return fmt.Errorf("unsupported file version %d", v)
+	}
+
+	n := int(d.uvarint())
+	f.columns = make(record.Schemas, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		name := d.string()
+		f.columns = append(f.columns, record.Field{Name: name, Type: int(d.byte())})
+	}
+	if d.err == nil && (len(f.columns) == 0 || f.columns[len(f.columns)-1].Name != record.TimeField) {
+		return fmt.Errorf("time column missing")
+	}
+
+	n = int(d.uvarint())
+	f.sortKey = make([]string, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		f.sortKey = append(f.sortKey, d.string())
+	}
+
+	n = int(d.uvarint())
+	f.granules = make([]granule, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		g := granule{
+			rows:    int(d.uvarint()),
+			minTime: d.varint(),
+			maxTime: d.varint(),
+			first:   make([]string, len(f.sortKey)),
+			last:    make([]string, len(f.sortKey)),
+			blocks:  make([]block, len(f.columns)),
+		}
+		for j := range f.sortKey {
+			g.first[j] = d.string()
+			g.last[j] = d.string()
+		}
+		for j := range g.blocks {
+			g.blocks[j].offset = int64(d.uvarint())
+			g.blocks[j].size = int64(d.uvarint())
+		}
+		f.granules = append(f.granules, g)
+	}
+	return d.err
+}
+
+func appendUvarint(dst []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(dst, buf[:n]...)
+}
+
+func appendVarint(dst []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	return append(dst, buf[:n]...)
+}
+
+func appendString(dst []byte, s string) []byte {
+	dst = appendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+type indexDecoder struct {
+	src []byte
+	err error
+}
+
+func (d *indexDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.src)
+	if n <= 0 {
+		d.err = fmt.Errorf("invalid index data")
+		return 0
+	}
+	d.src = d.src[n:]
+	return v
+}
+
+func (d *indexDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.src)
+	if n <= 0 {
+		d.err = fmt.Errorf("invalid index data")
+		return 0
+	}
+	d.src = d.src[n:]
+	return v
+}
+
+func (d *indexDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.src) == 0 {
+		d.err = fmt.Errorf("invalid index data")
+		return 0
+	}
+	b := d.src[0]
+	d.src = d.src[1:]
+	return b
+}
+
+func (d *indexDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if uint64(len(d.src)) < n {
+		d.err = fmt.Errorf("invalid index data")
+		return ""
+	}
+	s := string(d.src[:n])
+	d.src = d.src[n:]
+	return s
+}
+
+// encodeBlock encodes the values of a column of one granule:
+//
+//	null count (uvarint) | null bitmap if the null count is not zero | encoded values
+func encodeBlock(typ int, isTime bool, col *record.ColVal, dst []byte, ctx *immutable.CoderContext) ([]byte, error) {
+	dst = appendUvarint(dst, uint64(col.NilCount))
+	if col.NilCount > 0 {
+		bitmap := make([]byte, (col.Len+7)/8)
+		for i := 0; i < col.Len; i++ {
+			if !col.IsNil(i) {
+				bitmap[i>>3] |= record.BitMask[i&0x07]
+			}
+		}
+		dst = append(dst, bitmap...)
+	}
+	if col.NilCount == col.Len && typ != influx.Field_Type_String {
+		return dst, nil
+	}
+
+	switch {
+	case isTime:
+		return immutable.EncodeTimestampBlock(col.Val, dst, ctx)
+	case typ == influx.Field_Type_Int:
+		return immutable.EncodeIntegerBlock(col.Val, dst, ctx)
+	case typ == influx.Field_Type_Float:
+		return immutable.EncodeFloatBlock(col.Val, dst, ctx)
+	case typ == influx.Field_Type_Boolean:
+		return immutable.EncodeBooleanBlock(col.Val, dst, ctx)
+	case typ == influx.Field_Type_String:
+		return immutable.EncodeStringBlock(col.Val, col.Offset, dst, ctx)
+	}
+	return nil, fmt.Errorf("unsupported column type %d", typ)
+}
+
+func decodeBlock(typ int, isTime bool, rows int, data []byte, col *record.ColVal, ctx *immutable.CoderContext) error {
+	nilCount, n := binary.Uvarint(data)
+	if n <= 0 || int(nilCount) > rows {
+		return fmt.Errorf("invalid null count")
+	}
+	data = data[n:]
+
+	col.Init()
+	col.Len = rows
+	col.NilCount = int(nilCount)
+	bitmapLen := (rows + 7) / 8
+	if nilCount > 0 {
+		if len(data) < bitmapLen {
+			return fmt.Errorf("invalid null bitmap")
+		}
+		col.Bitmap = append(col.Bitmap[:0], data[:bitmapLen]...)
+		data = data[bitmapLen:]
+	} else {
+		col.Bitmap = col.Bitmap[:0]
+		for i := 0; i < bitmapLen; i++ {
+			col.Bitmap = append(col.Bitmap, 0xFF)
+		}
+	}
+	if len(data) == 0 {
+		if typ == influx.Field_Type_String || typ == influx.Field_Type_Tag {
+			col.Offset = make([]uint32, rows)
+		}
+		return nil
+	}
+
+	var err error
+	switch typ {
+	case influx.Field_Type_Int:
+		if isTime {
+			_, err = immutable.DecodeTimestampBlock(data, &col.Val, ctx)
+		} else {
+			_, err = immutable.DecodeIntegerBlock(data, &col.Val, ctx)
+		}
+	case influx.Field_Type_Float:
+		_, err = immutable.DecodeFloatBlock(data, &col.Val, ctx)
+	case influx.Field_Type_Boolean:
+		_, err = immutable.DecodeBooleanBlock(data, &col.Val, ctx)
+	case influx.Field_Type_String, influx.Field_Type_Tag:
+		col.Val, col.Offset, err = immutable.DecodeStringBlock(data, &col.Val, &col.Offset, ctx)
+	default:
+		err = fmt.Errorf("unsupported column type %d", typ)
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableFile_SparseIndex(t *testing.T) {
+	mt := newMemTable("mst")
+	rows := 3 * granuleRows
+	for i := 0; i < rows; i++ {
+		row := influx.Row{
+			Name:      "mst",
+			Tags:      influx.PointTags{{Key: "host", Value: fmt.Sprintf("h%d", i%3)}},
+			Timestamp: int64(i),
+			Fields:    influx.Fields{{Key: "v", NumValue: float64(i), Type: influx.Field_Type_Float}},
+		}
+		if i%2 == 0 {
+			row.Fields = append(row.Fields, influx.Field{Key: "s", StrValue: fmt.Sprint(i), Type: influx.Field_Type_String})
+		}
+		mt.appendRow(&row)
+	}
+
+	sortKey := []string{"host"}
+	name := filepath.Join(t.TempDir(), "0000000000000001"+fileSuffix)
+	f, err := writeTableFile(name, mt.record(sortKey, true), sortKey, fileops.FileLockOption(""))
+	require.NoError(t, err)
+	f.close(false)
+
+	f, err = openTableFile(name)
+	require.NoError(t, err)
+	defer f.close(false)
+	require.Equal(t, rows, f.rows)
+	require.Equal(t, 3, len(f.granules))
+	require.Equal(t, int64(0), f.minTime)
+	require.Equal(t, int64(rows-1), f.maxTime)
+
+	// the rows are sorted by host, each host fills a granule
+	for i := range f.granules {
+		require.True(t, f.granules[i].mayContain([]string{fmt.Sprintf("h%d", i)}))
+		require.False(t, f.granules[i].mayContain([]string{fmt.Sprintf("h%d", (i+1)%3)}))
+	}
+
+	ctx := immutable.NewCoderContext()
+	defer ctx.Release()
+	rec, err := f.readGranule(1, func(*record.Field) bool { return true }, ctx)
+	require.NoError(t, err)
+	require.Equal(t, granuleRows, rec.RowNums())
+	host := newColumnValues(rec, "host", true)
+	v := newColumnValues(rec, "v", false)
+	s := newColumnValues(rec, "s", false)
+	times := rec.Times()
+	for i := 0; i < rec.RowNums(); i++ {
+		require.Equal(t, "h1", host.str(i))
+		require.Equal(t, float64(times[i]), v.value(i))
+		if times[i]%2 == 0 {
+			require.Equal(t, fmt.Sprint(times[i]), s.value(i))
+		} else {
+			require.Nil(t, s.value(i))
+		}
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"sort"
+
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+type columnKey struct {
+	name string
+	tag  bool
+}
+
+type memColumn struct {
+	name string
+	typ  int
+	col  record.ColVal
+}
+
+// memTable buffers the rows of one measurement written since the last flush,
+// one column per tag key and per field, with the missing values set to null.
+type memTable struct {
+	name    string
+	columns []*memColumn
+	index   map[columnKey]int
+	times   []int64
+	size    int64
+}
+
+func newMemTable(name string) *memTable {
+	return &memTable{
+		name:  name,
+		index: make(map[columnKey]int),
+	}
+}
+
+func (mt *memTable) rows() int {
+	return len(mt.times)
+}
+
+// fieldType returns the type of the field in the memTable, or Field_Type_Unknown
+func (mt *memTable) fieldType(name string) int {
+	idx, ok := mt.index[columnKey{name: name}]
+	if !ok {
+		return influx.Field_Type_Unknown
+	}
+	return mt.columns[idx].typ
+}
+
+func (mt *memTable) column(name string, typ int) *memColumn {
+	key := columnKey{name: name, tag: typ == influx.Field_Type_Tag}
+	if idx, ok := mt.index[key]; ok {
+		return mt.columns[idx]
+	}
+	c := &memColumn{name: name, typ: typ}
+	c.col.PadColVal(storeType(typ), mt.rows())
+	mt.index[key] = len(mt.columns)
+	mt.columns = append(mt.columns, c)
+	return c
+}
+
+// checkRow returns an error if a field of the row conflicts with the type of the
+// column already written, either in the memTable or in the files of the shard.
+func checkRow(row *influx.Row, types map[string]int) error {
+	for i := range row.Fields {
+		f := &row.Fields[i]
+		typ := fieldType(f.Type)
+		if exist, ok := types[f.Key]; ok && exist != typ {
+			return errno.NewError(errno.FieldTypeConflict, f.Key, row.Name,
+				influx.FieldTypeString(int32(typ)), influx.FieldTypeString(int32(exist)))
+		}
+	}
+	return nil
+}
+
+func (mt *memTable) appendRow(row *influx.Row) {
+	n := mt.rows()
+	for i := range row.Tags {
+		c := mt.column(row.Tags[i].Key, influx.Field_Type_Tag)
+		if c.col.Len > n {
+			continue
+		}
+		c.col.AppendString(row.Tags[i].Value)
+		mt.size += int64(len(row.Tags[i].Key) + len(row.Tags[i].Value))
+	}
+
+	for i := range row.Fields {
+		f := &row.Fields[i]
+		c := mt.column(f.Key, fieldType(f.Type))
+		if c.col.Len > n {
+			continue
+		}
+		switch c.typ {
+		case influx.Field_Type_Int:
+			c.col.AppendInteger(int64(f.NumValue))
+		case influx.Field_Type_Float:
+			c.col.AppendFloat(f.NumValue)
+		case influx.Field_Type_Boolean:
+			c.col.AppendBoolean(f.NumValue != 0)
+		case influx.Field_Type_String:
+			c.col.AppendString(f.StrValue)
+			mt.size += int64(len(f.StrValue))
+		}
+		mt.size += int64(len(f.Key)) + 8
+	}
+
+	for _, c := range mt.columns {
+		if c.col.Len == n {
+			c.col.PadColVal(storeType(c.typ), 1)
+		}
+	}
+	mt.times = append(mt.times, row.Timestamp)
+	mt.size += 8
+}
+
+// record copies the rows of the memTable into a record: the tag columns sorted
+// by name, then the fields sorted by name and the time column last. If sorted is
+// true, the rows are ordered by the values of the sort key tags then by time.
+func (mt *memTable) record(sortKey []string, sorted bool) *record.Record {
+	schema := make(record.Schemas, 0, len(mt.columns)+1)
+	cols := make([]*memColumn, 0, len(mt.columns))
+	for _, c := range mt.columns {
+		schema = append(schema, record.Field{Name: c.name, Type: c.typ})
+		cols = append(cols, c)
+	}
+	sort.Sort(&memColumns{schema: schema, cols: cols})
+	schema = append(schema, record.Field{Name: record.TimeField, Type: influx.Field_Type_Int})
+
+	rec := record.NewRecord(schema, false)
+	if !sorted {
+		for i, c := range cols {
+			rec.ColVals[i].AppendColVal(&c.col, storeType(c.typ), 0, c.col.Len)
+		}
+		rec.TimeColumn().AppendIntegers(mt.times...)
+		return rec
+	}
+
+	perm := mt.sortPermutation(sortKey)
+	for i, c := range cols {
+		dst := &rec.ColVals[i]
+		typ := storeType(c.typ)
+		for _, row := range perm {
+			dst.AppendColVal(&c.col, typ, row, row+1)
+		}
+	}
+	timeCol := rec.TimeColumn()
+	for _, row := range perm {
+		timeCol.AppendInteger(mt.times[row])
+	}
+	return rec
+}
+
+func (mt *memTable) sortPermutation(sortKey []string) []int {
+	keys := make([][]string, 0, len(sortKey))
+	for _, name := range sortKey {
+		idx, ok := mt.index[columnKey{name: name, tag: true}]
+		if !ok {
+			// rows without the tag sort first, there is nothing to compare
+			continue
+		}
+		values := make([]string, mt.rows())
+		col := &mt.columns[idx].col
+		for i := range values {
+			values[i], _ = col.StringValueUnsafe(i)
+		}
+		keys = append(keys, values)
+	}
+
+	perm := make([]int, mt.rows())
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool {
+		a, b := perm[i], perm[j]
+		for _, values := range keys {
+			if values[a] != values[b] {
+				return values[a] < values[b]
+			}
+		}
+		return mt.times[a] < mt.times[b]
+	})
+	return perm
+}
+
+// memColumns sorts the tag columns before the fields, each by name
+type memColumns struct {
+	schema record.Schemas
+	cols   []*memColumn
+}
+
+func (m *memColumns) Len() int {
+	return len(m.cols)
+}
+
+func (m *memColumns) Less(i, j int) bool {
+	ti, tj := m.schema[i].Type == influx.Field_Type_Tag, m.schema[j].Type == influx.Field_Type_Tag
+	if ti != tj {
+		return ti
+	}
+	return m.schema[i].Name < m.schema[j].Name
+}
+
+func (m *memColumns) Swap(i, j int) {
+	m.schema[i], m.schema[j] = m.schema[j], m.schema[i]
+	m.cols[i], m.cols[j] = m.cols[j], m.cols[i]
+}
+
+// fieldType maps the type of a written field to the type of its column
+func fieldType(typ int32) int {
+	if typ == influx.Field_Type_UInt {
+		return influx.Field_Type_Int
+	}
+	return int(typ)
+}
+
+// storeType is the type used to append and encode the values of a column, the
+// tags are stored as strings.
+func storeType(typ int) int {
+	if typ == influx.Field_Type_Tag {
+		return influx.Field_Type_String
+	}
+	return typ
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"sort"
+
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// The metadata of a measurement, the tag values and the series, comes from
+// its tag columns: tssp2 has no series index.
+
+// tagKeys returns the tag keys of the data of a snapshot, sorted
+func (snap *tableSnapshot) tagKeys() []string {
+	seen := make(map[string]struct{})
+	add := func(schema record.Schemas) {
+		for i := range schema {
+			if schema[i].Type == influx.Field_Type_Tag {
+				seen[schema[i].Name] = struct{}{}
+			}
+		}
+	}
+	for _, f := range snap.files {
+		add(f.columns)
+	}
+	for _, rec := range snap.mem {
+		add(rec.Schema)
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scanTags calls fn with the values of the tags of each row of a measurement
+// matching the condition, empty for a missing tag. The tags are all the tags of
+// the measurement if keys is nil. The values are only valid during the call.
+func (s *shard) scanTags(mst string, keys []string, cond influxql.Expr, fn func(keys, values []string)) error {
+	snap := s.snapshot(mst)
+	defer snap.release()
+
+	all := snap.tagKeys()
+	if keys == nil {
+		keys = all
+	}
+	sc := newScanner(record.TimeRange{Min: influxql.MinTime, Max: influxql.MaxTime}, typeTags(cond, all), keys, nil)
+	values := make([]string, len(keys))
+	cols := make([]*columnValues, len(keys))
+	return sc.scan(snap, func(rec *record.Record, rows []int) error {
+		for i, key := range keys {
+			cols[i] = newColumnValues(rec, key, true)
+		}
+		for _, row := range rows {
+			for i := range cols {
+				values[i] = cols[i].str(row)
+			}
+			fn(keys, values)
+		}
+		return nil
+	})
+}
+
+// typeTags sets the type of the untyped references of the condition to the tags
+// of a measurement, the conditions of the metadata statements are not typed.
+func typeTags(cond influxql.Expr, tags []string) influxql.Expr {
+	if cond == nil {
+		return nil
+	}
+	return influxql.RewriteExpr(influxql.CloneExpr(cond), func(e influxql.Expr) influxql.Expr {
+		if ref, ok := e.(*influxql.VarRef); ok && ref.Type == influxql.Unknown && contains(tags, ref.Val) {
+			return &influxql.VarRef{Val: ref.Val, Type: influxql.Tag}
+		}
+		return e
+	})
+}
+
+// tagValues adds the values of the tag keys of a measurement to dst
+func (s *shard) tagValues(mst string, keys []string, cond influxql.Expr, dst []map[string]struct{}) error {
+	return s.scanTags(mst, keys, cond, func(_, values []string) {
+		for i, v := range values {
+			if v == "" {
+				continue
+			}
+			if _, ok := dst[i][v]; !ok {
+				dst[i][string(append([]byte(nil), v...))] = struct{}{}
+			}
+		}
+	})
+}
+
+// seriesKeys adds the series keys of a measurement to dst. A series key is the
+// measurement and the tags of a row like in the series index of tssp1.
+func (s *shard) seriesKeys(mst string, cond influxql.Expr, dst map[string]struct{}) error {
+	var key []byte
+	return s.scanTags(mst, nil, cond, func(keys, values []string) {
+		key = append(key[:0], mst...)
+		for i, v := range values {
+			if v != "" {
+				key = append(append(append(append(key, ','), keys[i]...), '='), v...)
+			}
+		}
+		if _, ok := dst[string(key)]; !ok {
+			dst[string(key)] = struct{}{}
+		}
+	})
+}
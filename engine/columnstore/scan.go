@@ -0,0 +1,337 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// scanner selects the rows of a measurement in a time range matching a condition.
+// The sort key equality conditions of the condition skip the granules of the
+// files that can not hold the rows, the condition is evaluated on the columns
+// of the remaining ones.
+type scanner struct {
+	tr     record.TimeRange
+	cond   influxql.Expr
+	filter condition
+
+	// the columns read besides the ones of the condition
+	tags   map[string]struct{}
+	fields map[string]struct{}
+
+	condTags   []string
+	condFields []string
+	equals     map[string]string
+}
+
+func newScanner(tr record.TimeRange, cond influxql.Expr, tags, fields []string) *scanner {
+	s := &scanner{
+		tr:     tr,
+		cond:   trimTimeCondition(cond),
+		tags:   make(map[string]struct{}, len(tags)),
+		fields: make(map[string]struct{}, len(fields)),
+		equals: make(map[string]string),
+	}
+	for _, name := range tags {
+		s.tags[name] = struct{}{}
+	}
+	for _, name := range fields {
+		s.fields[name] = struct{}{}
+	}
+	if s.cond == nil {
+		return s
+	}
+
+	for _, ref := range influxql.ExprNames(s.cond) {
+		if ref.Type == influxql.Tag {
+			s.condTags = append(s.condTags, ref.Val)
+		} else {
+			s.condFields = append(s.condFields, ref.Val)
+		}
+	}
+	equalTags(s.cond, s.equals)
+	s.filter = compileCondition(s.cond)
+	return s
+}
+
+// trimTimeCondition replaces the time comparisons by true, the time range of the
+// query already holds them.
+func trimTimeCondition(cond influxql.Expr) influxql.Expr {
+	if cond == nil {
+		return nil
+	}
+	cond = influxql.RewriteExpr(influxql.CloneExpr(cond), func(e influxql.Expr) influxql.Expr {
+		if be, ok := e.(*influxql.BinaryExpr); ok {
+			if isTimeRef(be.LHS) || isTimeRef(be.RHS) {
+				return &influxql.BooleanLiteral{Val: true}
+			}
+		}
+		return e
+	})
+	cond = influxql.Reduce(cond, nil)
+	if lit, ok := cond.(*influxql.BooleanLiteral); ok && lit.Val {
+		return nil
+	}
+	return cond
+}
+
+func isTimeRef(e influxql.Expr) bool {
+	ref, ok := e.(*influxql.VarRef)
+	return ok && ref.Val == record.TimeField
+}
+
+// equalTags collects the tag = 'value' conditions that all the rows must match
+func equalTags(cond influxql.Expr, dst map[string]string) {
+	switch e := cond.(type) {
+	case *influxql.ParenExpr:
+		equalTags(e.Expr, dst)
+	case *influxql.BinaryExpr:
+		switch e.Op {
+		case influxql.AND:
+			equalTags(e.LHS, dst)
+			equalTags(e.RHS, dst)
+		case influxql.EQ:
+			ref, ok := e.LHS.(*influxql.VarRef)
+			lit, ok2 := e.RHS.(*influxql.StringLiteral)
+			if !ok || !ok2 {
+				ref, ok = e.RHS.(*influxql.VarRef)
+				lit, ok2 = e.LHS.(*influxql.StringLiteral)
+			}
+			if ok && ok2 && ref.Type == influxql.Tag {
+				dst[ref.Val] = lit.Val
+			}
+		}
+	}
+}
+
+// keyPrefix returns the values of the longest prefix of the sort key with an
+// equality condition.
+func (s *scanner) keyPrefix(sortKey []string) []string {
+	var prefix []string
+	for _, name := range sortKey {
+		v, ok := s.equals[name]
+		if !ok {
+			break
+		}
+		prefix = append(prefix, v)
+	}
+	return prefix
+}
+
+func (s *scanner) want(f *record.Field) bool {
+	if f.Type == influx.Field_Type_Tag {
+		if _, ok := s.tags[f.Name]; ok {
+			return true
+		}
+		return contains(s.condTags, f.Name)
+	}
+	if _, ok := s.fields[f.Name]; ok {
+		return true
+	}
+	return contains(s.condFields, f.Name)
+}
+
+// wantKeys selects the columns read to find the series: the tags, the columns
+// of the condition and the string fields, the size of their values varies.
+func (s *scanner) wantKeys(f *record.Field) bool {
+	if f.Type == influx.Field_Type_Tag || f.Type == influx.Field_Type_String {
+		return s.want(f)
+	}
+	return contains(s.condFields, f.Name)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// scanUnit is the rows read at once by a scan, a granule of a column file or
+// the rows of a memTable
+type scanUnit struct {
+	file    *tableFile
+	granule int
+	rec     *record.Record
+
+	// the first and last series of the rows of the unit matching the query
+	lo, hi *seriesEntry
+}
+
+// units returns the units of the data which may hold rows in the time range
+// and matching the sort key equality conditions
+func (s *scanner) units(snap *tableSnapshot) []*scanUnit {
+	var units []*scanUnit
+	for _, f := range snap.files {
+		if f.maxTime < s.tr.Min || f.minTime > s.tr.Max {
+			continue
+		}
+		prefix := s.keyPrefix(f.sortKey)
+		for i := range f.granules {
+			g := &f.granules[i]
+			if g.maxTime < s.tr.Min || g.minTime > s.tr.Max || !g.mayContain(prefix) {
+				continue
+			}
+			units = append(units, &scanUnit{file: f, granule: i})
+		}
+	}
+	for _, rec := range snap.mem {
+		units = append(units, &scanUnit{rec: rec})
+	}
+	return units
+}
+
+// read returns the columns of the unit selected by want, all the columns of a
+// memTable are returned
+func (s *scanner) read(u *scanUnit, want func(f *record.Field) bool, ctx *immutable.CoderContext) (*record.Record, error) {
+	if u.rec != nil {
+		return u.rec, nil
+	}
+	return u.file.readGranule(u.granule, want, ctx)
+}
+
+// scan calls fn with the records of the data and the indexes of their rows
+// matching the time range and the condition.
+func (s *scanner) scan(snap *tableSnapshot, fn func(rec *record.Record, rows []int) error) error {
+	ctx := immutable.NewCoderContext()
+	defer ctx.Release()
+
+	var rows []int
+	for _, u := range s.units(snap) {
+		rec, err := s.read(u, s.want, ctx)
+		if err != nil {
+			return err
+		}
+		if rows = s.match(rec, rows[:0]); len(rows) == 0 {
+			continue
+		}
+		if err = fn(rec, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *scanner) match(rec *record.Record, rows []int) []int {
+	times := rec.Times()
+	for i, t := range times {
+		if t >= s.tr.Min && t <= s.tr.Max {
+			rows = append(rows, i)
+		}
+	}
+	if s.filter == nil || len(rows) == 0 {
+		return rows
+	}
+	return s.filter.filter(newColumns(rec, s.condTags), rows)
+}
+
+// columnValues reads the values of a column by row, the values of a missing
+// column are null, or empty for a tag.
+type columnValues struct {
+	col  *record.ColVal
+	typ  int
+	tag  bool
+	idx  []int
+	ints []int64
+	fs   []float64
+	bs   []bool
+}
+
+func newColumnValues(rec *record.Record, name string, tag bool) *columnValues {
+	i := columnIndex(rec.Schema, name, tag)
+	if i < 0 {
+		return &columnValues{tag: tag}
+	}
+	return columnValuesOf(&rec.ColVals[i], rec.Schema[i].Type, tag)
+}
+
+func columnValuesOf(col *record.ColVal, typ int, tag bool) *columnValues {
+	v := &columnValues{col: col, typ: typ, tag: tag}
+	switch v.typ {
+	case influx.Field_Type_Int:
+		v.ints = v.col.IntegerValues()
+	case influx.Field_Type_Float:
+		v.fs = v.col.FloatValues()
+	case influx.Field_Type_Boolean:
+		v.bs = v.col.BooleanValues()
+	default:
+		return v
+	}
+	v.idx = make([]int, v.col.Len)
+	n := 0
+	for j := range v.idx {
+		if v.col.IsNil(j) {
+			v.idx[j] = -1
+			continue
+		}
+		v.idx[j] = n
+		n++
+	}
+	return v
+}
+
+func (v *columnValues) value(row int) interface{} {
+	if v.col == nil {
+		if v.tag {
+			return ""
+		}
+		return nil
+	}
+	switch v.typ {
+	case influx.Field_Type_Int:
+		if v.idx[row] >= 0 {
+			return v.ints[v.idx[row]]
+		}
+	case influx.Field_Type_Float:
+		if v.idx[row] >= 0 {
+			return v.fs[v.idx[row]]
+		}
+	case influx.Field_Type_Boolean:
+		if v.idx[row] >= 0 {
+			return v.bs[v.idx[row]]
+		}
+	default:
+		s, isNil := v.col.StringValueUnsafe(row)
+		if !isNil || v.tag {
+			return s
+		}
+	}
+	return nil
+}
+
+// str returns the value of a tag or string column, empty if it is null
+func (v *columnValues) str(row int) string {
+	if v.col == nil {
+		return ""
+	}
+	s, _ := v.col.StringValueUnsafe(row)
+	return s
+}
+
+func columnIndex(schema record.Schemas, name string, tag bool) int {
+	for i := range schema {
+		if schema[i].Name == name && (schema[i].Type == influx.Field_Type_Tag) == tag {
+			return i
+		}
+	}
+	return -1
+}
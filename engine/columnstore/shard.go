@@ -0,0 +1,518 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openGemini/openGemini/engine"
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"go.uber.org/zap"
+)
+
+// shard keeps the rows of one measurement as a memTable until they are flushed
+// to a column file. The files of a measurement are in their own directory:
+//
+//	<shard>/<escaped measurement>/<sequence>.tssp2
+type shard struct {
+	mu      sync.RWMutex
+	flushMu sync.Mutex
+
+	ident        meta.ShardIdentifier
+	dataPath     string
+	startTime    time.Time
+	endTime      time.Time
+	durationInfo meta.DurationDescriptor
+	sortKey      []string
+	lock         fileops.FileLockOption
+
+	active     map[string]*memTable
+	flushing   map[string][]*memTable
+	tables     map[string][]*tableFile
+	fieldTypes map[string]map[string]int
+	wal        *wal
+	walFiles   []string
+	memSize    int64
+	lastWrite  time.Time
+	seq        uint64
+	closed     bool
+
+	defaultTags map[string]string
+	fileStat    *statistics.FileStatistics
+}
+
+func newShard(dataPath, walPath string, ident *meta.ShardIdentifier, tr *meta.TimeRangeInfo,
+	durationInfo *meta.DurationDescriptor, opt *netstorage.EngineOptions) *shard {
+	lock := fileops.FileLockOption("")
+	return &shard{
+		ident:        *ident,
+		dataPath:     dataPath,
+		startTime:    tr.StartTime,
+		endTime:      tr.EndTime,
+		durationInfo: *durationInfo,
+		sortKey:      opt.SortKey,
+		lock:         lock,
+		active:       make(map[string]*memTable),
+		flushing:     make(map[string][]*memTable),
+		tables:       make(map[string][]*tableFile),
+		fieldTypes:   make(map[string]map[string]int),
+		wal:          newWal(walPath, lock),
+		defaultTags: map[string]string{
+			"path":            dataPath,
+			"id":              strconv.FormatUint(ident.ShardID, 10),
+			"database":        ident.OwnerDb,
+			"retentionPolicy": ident.Policy,
+		},
+		fileStat: statistics.NewFileStatistics(),
+	}
+}
+
+// shardDirName is the directory of a shard, like the tssp1 shards without the index ID
+func shardDirName(shardID uint64, tr *meta.TimeRangeInfo) string {
+	return strconv.FormatUint(shardID, 10) + "_" + strconv.FormatInt(meta.MarshalTime(tr.StartTime), 10) +
+		"_" + strconv.FormatInt(meta.MarshalTime(tr.EndTime), 10)
+}
+
+func parseShardDir(name string) (uint64, *meta.TimeRangeInfo, error) {
+	parts := strings.Split(name, "_")
+	if len(parts) != 3 {
+		return 0, nil, engine.ErrInvalidDir
+	}
+	shardID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, nil, engine.ErrInvalidDir
+	}
+	start, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, nil, engine.ErrInvalidDir
+	}
+	end, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, nil, engine.ErrInvalidDir
+	}
+	return shardID, &meta.TimeRangeInfo{StartTime: meta.UnmarshalTime(start), EndTime: meta.UnmarshalTime(end)}, nil
+}
+
+// open loads the column files of the shard and replays its wal
+func (s *shard) open() error {
+	if err := fileops.MkdirAll(s.dataPath, 0750, s.lock); err != nil {
+		return err
+	}
+	dirs, err := fileops.ReadDir(s.dataPath)
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		mst, err := url.PathUnescape(d.Name())
+		if err != nil {
+			log.Warn("skip invalid measurement directory", zap.String("dir", filepath.Join(s.dataPath, d.Name())))
+			continue
+		}
+		if err = s.loadTables(mst, filepath.Join(s.dataPath, d.Name())); err != nil {
+			s.closeTables()
+			return err
+		}
+	}
+
+	if err = s.wal.open(); err != nil {
+		s.closeTables()
+		return err
+	}
+	err = s.wal.replay(func(rows []influx.Row) error {
+		for i := range rows {
+			if err := checkRow(&rows[i], s.measurementTypes(rows[i].Name)); err != nil {
+				// the row was rejected when it was written
+				continue
+			}
+			s.appendRow(&rows[i])
+		}
+		return nil
+	})
+	if err != nil {
+		_ = s.wal.close()
+		s.closeTables()
+		return err
+	}
+	s.lastWrite = time.Now()
+	return nil
+}
+
+func (s *shard) loadTables(mst, dir string) error {
+	names, err := fileops.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var files []*tableFile
+	for _, d := range names {
+		name := filepath.Join(dir, d.Name())
+		if strings.HasSuffix(name, tmpFileSuffix) {
+			// a flush interrupted before the rename, its rows are still in the wal
+			if err = fileops.Remove(name, s.lock); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(d.Name(), fileSuffix), 16, 64)
+		if err != nil {
+			log.Warn("skip invalid column file", zap.String("file", name))
+			continue
+		}
+		f, err := openTableFile(name)
+		if err != nil {
+			for _, f := range files {
+				f.close(false)
+			}
+			return err
+		}
+		if seq > s.seq {
+			s.seq = seq
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	if len(files) == 0 {
+		return nil
+	}
+
+	s.tables[mst] = files
+	types := s.measurementTypes(mst)
+	for _, f := range files {
+		for _, c := range f.columns[:len(f.columns)-1] {
+			if c.Type != influx.Field_Type_Tag {
+				types[c.Name] = c.Type
+			}
+		}
+	}
+	return nil
+}
+
+func (s *shard) measurementTypes(mst string) map[string]int {
+	types, ok := s.fieldTypes[mst]
+	if !ok {
+		types = make(map[string]int)
+		s.fieldTypes[mst] = types
+	}
+	return types
+}
+
+func (s *shard) appendRow(row *influx.Row) {
+	mt, ok := s.active[row.Name]
+	if !ok {
+		mt = newMemTable(row.Name)
+		s.active[row.Name] = mt
+	}
+	size := mt.size
+	mt.appendRow(row)
+	s.memSize += mt.size - size
+
+	types := s.measurementTypes(row.Name)
+	for i := range row.Fields {
+		types[row.Fields[i].Key] = fieldType(row.Fields[i].Type)
+	}
+}
+
+func (s *shard) writeRows(rows []influx.Row, binaryRows []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return engine.ErrShardClosed
+	}
+
+	for i := range rows {
+		if err := checkRow(&rows[i], s.measurementTypes(rows[i].Name)); err != nil {
+			return err
+		}
+	}
+	// the rows of one write request may conflict with each other
+	types := make(map[string]map[string]int)
+	for i := range rows {
+		mt, ok := types[rows[i].Name]
+		if !ok {
+			mt = make(map[string]int)
+			types[rows[i].Name] = mt
+		}
+		if err := checkRow(&rows[i], mt); err != nil {
+			return err
+		}
+		for j := range rows[i].Fields {
+			mt[rows[i].Fields[j].Key] = fieldType(rows[i].Fields[j].Type)
+		}
+	}
+
+	if err := s.wal.write(rows, binaryRows); err != nil {
+		return err
+	}
+	for i := range rows {
+		s.appendRow(&rows[i])
+	}
+	s.lastWrite = time.Now()
+	return nil
+}
+
+// needFlush returns true if the memTables reach the size limit or are not written
+// for the cold duration.
+func (s *shard) needFlush(sizeLimit int64, coldDuration time.Duration) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed || (len(s.active) == 0 && len(s.flushing) == 0) {
+		return false
+	}
+	return s.memSize >= sizeLimit || time.Since(s.lastWrite) >= coldDuration
+}
+
+// flush writes the memTables to column files. The memTables are still read by
+// the queries until their file is added, and the ones that fail to flush are
+// retried by the next flush.
+func (s *shard) flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return engine.ErrShardClosed
+	}
+	if len(s.active) > 0 {
+		files, err := s.wal.rotate()
+		if err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		s.walFiles = append(s.walFiles, files...)
+		for mst, mt := range s.active {
+			s.flushing[mst] = append(s.flushing[mst], mt)
+		}
+		s.active = make(map[string]*memTable)
+		s.memSize = 0
+	}
+	msts := make([]string, 0, len(s.flushing))
+	for mst := range s.flushing {
+		msts = append(msts, mst)
+	}
+	s.mu.Unlock()
+
+	sort.Strings(msts)
+	for _, mst := range msts {
+		if err := s.flushMeasurement(mst); err != nil {
+			log.Error("flush column file failed", zap.String("measurement", mst),
+				zap.Uint64("shard", s.ident.ShardID), zap.Error(err))
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	files := s.walFiles
+	s.walFiles = nil
+	s.mu.Unlock()
+	s.wal.remove(files)
+	return nil
+}
+
+func (s *shard) flushMeasurement(mst string) error {
+	s.mu.RLock()
+	mts := s.flushing[mst]
+	s.mu.RUnlock()
+
+	dir := filepath.Join(s.dataPath, url.PathEscape(mst))
+	if err := fileops.MkdirAll(dir, 0750, s.lock); err != nil {
+		return err
+	}
+	for len(mts) > 0 {
+		s.seq++
+		name := filepath.Join(dir, fmt.Sprintf("%016x%s", s.seq, fileSuffix))
+		f, err := writeTableFile(name, mts[0].record(s.sortKey, true), s.sortKey, s.lock)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.tables[mst] = append(s.tables[mst], f)
+		mts = s.flushing[mst][1:]
+		if len(mts) == 0 {
+			delete(s.flushing, mst)
+		} else {
+			s.flushing[mst] = mts
+		}
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// tableSnapshot is the data of a measurement read by a query: the column files,
+// referenced until release, and a copy of the rows of the memTables.
+type tableSnapshot struct {
+	files []*tableFile
+	mem   []*record.Record
+}
+
+func (s *shard) snapshot(mst string) *tableSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap := &tableSnapshot{}
+	for _, f := range s.tables[mst] {
+		f.ref()
+		snap.files = append(snap.files, f)
+	}
+	for _, mt := range s.flushing[mst] {
+		snap.mem = append(snap.mem, mt.record(nil, false))
+	}
+	if mt, ok := s.active[mst]; ok {
+		snap.mem = append(snap.mem, mt.record(nil, false))
+	}
+	return snap
+}
+
+func (snap *tableSnapshot) release() {
+	for _, f := range snap.files {
+		f.unref()
+	}
+	snap.files = nil
+}
+
+// measurements returns the names of the measurements of the shard, sorted
+func (s *shard) measurements() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.fieldTypes))
+	for mst := range s.fieldTypes {
+		if len(s.tables[mst]) > 0 || len(s.flushing[mst]) > 0 || s.active[mst] != nil {
+			names = append(names, mst)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *shard) dropMeasurement(mst string) error {
+	// flush first, the rows of the measurement left in the wal would be replayed on the next open
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.mu.Lock()
+	files := s.tables[mst]
+	delete(s.tables, mst)
+	delete(s.flushing, mst)
+	delete(s.fieldTypes, mst)
+	if mt, ok := s.active[mst]; ok {
+		s.memSize -= mt.size
+		delete(s.active, mst)
+	}
+	s.mu.Unlock()
+
+	for _, f := range files {
+		f.close(true)
+	}
+	return fileops.RemoveAll(filepath.Join(s.dataPath, url.PathEscape(mst)), s.lock)
+}
+
+func (s *shard) expired(now time.Time) bool {
+	return s.durationInfo.Duration != 0 && s.endTime.Add(s.durationInfo.Duration).Before(now)
+}
+
+func (s *shard) syncWal() {
+	if err := s.wal.sync(); err != nil {
+		log.Error("sync wal failed", zap.Uint64("shard", s.ident.ShardID), zap.Error(err))
+	}
+}
+
+// close flushes the memTables and closes the files of the shard
+func (s *shard) close() error {
+	err := s.flush()
+	if err == engine.ErrShardClosed {
+		return nil
+	}
+
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if e := s.wal.close(); err == nil {
+		err = e
+	}
+	s.closeTables()
+	return err
+}
+
+func (s *shard) closeTables() {
+	for _, files := range s.tables {
+		for _, f := range files {
+			f.close(false)
+		}
+	}
+	s.tables = make(map[string][]*tableFile)
+}
+
+// remove closes the shard and removes its files and its wal
+func (s *shard) remove() error {
+	s.flushMu.Lock()
+	s.mu.Lock()
+	s.closed = true
+	_ = s.wal.close()
+	for _, files := range s.tables {
+		for _, f := range files {
+			f.close(true)
+		}
+	}
+	s.tables = make(map[string][]*tableFile)
+	s.mu.Unlock()
+	s.flushMu.Unlock()
+
+	if err := fileops.RemoveAll(s.dataPath, s.lock); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return fileops.RemoveAll(s.wal.dir, s.lock)
+}
+
+func (s *shard) statistics(buffer []byte) ([]byte, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return buffer, nil
+	}
+	fileStat := statistics.NewFileStat()
+	for mst, files := range s.tables {
+		for _, f := range files {
+			fileStat.AddMst(mst, 1, f.size)
+			fileStat.AddLevel(0, f.size)
+		}
+	}
+	s.mu.RUnlock()
+	return s.fileStat.Collect(buffer, s.defaultTags, fileStat)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package columnstore
+
+import (
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/lib/numberenc"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"go.uber.org/zap"
+)
+
+const (
+	walFileSuffix = ".wal"
+	walHeaderSize = 8
+)
+
+// wal logs the rows written to the memTables of a shard. Each record is
+//
+//	length (uint32) | crc32 of the rows (uint32) | rows marshaled by influx.FastMarshalMultiRows
+//
+// A new file is started each time the memTables are flushed, the files older
+// than the flush are removed once the flushed column files are synced.
+type wal struct {
+	mu    sync.Mutex
+	dir   string
+	lock  fileops.FileLockOption
+	fd    fileops.File
+	seq   uint64
+	files []string
+	dirty bool
+	buf   []byte
+}
+
+func newWal(dir string, lock fileops.FileLockOption) *wal {
+	return &wal{dir: dir, lock: lock}
+}
+
+// open lists the existing files for replay and starts a new file after them
+func (w *wal) open() error {
+	if err := fileops.MkdirAll(w.dir, 0750, w.lock); err != nil {
+		return err
+	}
+	dirs, err := fileops.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+
+	var seqs []uint64
+	for _, d := range dirs {
+		name := d.Name()
+		if d.IsDir() || !strings.HasSuffix(name, walFileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, walFileSuffix), 10, 64)
+		if err != nil {
+			log.Warn("skip invalid wal file", zap.String("file", filepath.Join(w.dir, name)))
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	for _, seq := range seqs {
+		w.files = append(w.files, w.fileName(seq))
+		w.seq = seq
+	}
+	return w.newFile()
+}
+
+func (w *wal) fileName(seq uint64) string {
+	return filepath.Join(w.dir, strconv.FormatUint(seq, 10)+walFileSuffix)
+}
+
+func (w *wal) newFile() error {
+	w.seq++
+	name := w.fileName(w.seq)
+	pri := fileops.FilePriorityOption(fileops.IO_PRIORITY_ULTRA_HIGH)
+	fd, err := fileops.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640, w.lock, pri)
+	if err != nil {
+		return err
+	}
+	w.fd = fd
+	w.files = append(w.files, name)
+	return nil
+}
+
+// replay calls fn with the rows of every record of the files written before
+// open. A corrupt or truncated record ends the replay of its file, the rows after
+// it are lost.
+func (w *wal) replay(fn func(rows []influx.Row) error) error {
+	var rows []influx.Row
+	var tagPool []influx.Tag
+	var fieldPool []influx.Field
+	var indexOptionPool []influx.IndexOption
+	var indexKeyPool []byte
+
+	for _, name := range w.files[:len(w.files)-1] {
+		data, err := fileops.ReadFile(name, w.lock)
+		if err != nil {
+			return err
+		}
+		for len(data) > 0 {
+			if len(data) < walHeaderSize {
+				log.Warn("truncated wal record", zap.String("file", name))
+				break
+			}
+			size := int(numberenc.UnmarshalUint32(data))
+			checksum := numberenc.UnmarshalUint32(data[4:])
+			data = data[walHeaderSize:]
+			if size > len(data) || crc32.Checksum(data[:size], crcTable) != checksum {
+				log.Warn("corrupt wal record", zap.String("file", name), zap.Int("size", size))
+				break
+			}
+
+			rows, tagPool, fieldPool, indexOptionPool, indexKeyPool, err = influx.FastUnmarshalMultiRows(
+				data[:size], rows[:0], tagPool[:0], fieldPool[:0], indexOptionPool[:0], indexKeyPool[:0])
+			if err != nil {
+				return fmt.Errorf("unmarshal wal record of %s: %v", name, err)
+			}
+			if err = fn(rows); err != nil {
+				return err
+			}
+			data = data[size:]
+		}
+	}
+	return nil
+}
+
+// write logs the rows, binaryRows are the rows already marshaled or nil
+func (w *wal) write(rows []influx.Row, binaryRows []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	w.buf = append(w.buf[:0], make([]byte, walHeaderSize)...)
+	if binaryRows != nil {
+		w.buf = append(w.buf, binaryRows...)
+	} else if w.buf, err = influx.FastMarshalMultiRows(w.buf, rows); err != nil {
+		return err
+	}
+	payload := w.buf[walHeaderSize:]
+	numberenc.MarshalUint32Copy(w.buf, uint32(len(payload)))
+	numberenc.MarshalUint32Copy(w.buf[4:], crc32.Checksum(payload, crcTable))
+
+	if _, err = w.fd.Write(w.buf); err != nil {
+		return err
+	}
+	w.dirty = true
+	return nil
+}
+
+// sync flushes the written records to the disk
+func (w *wal) sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.dirty || w.fd == nil {
+		return nil
+	}
+	w.dirty = false
+	return w.fd.Sync()
+}
+
+// rotate starts a new file and returns the previous files, they are removed by
+// remove once the rows they hold are flushed.
+func (w *wal) rotate() ([]string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.fd.Sync(); err != nil {
+		return nil, err
+	}
+	if err := w.fd.Close(); err != nil {
+		return nil, err
+	}
+	w.dirty = false
+	files := w.files
+	w.files = nil
+	if err := w.newFile(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (w *wal) remove(files []string) {
+	for _, name := range files {
+		if err := fileops.Remove(name, w.lock); err != nil && !os.IsNotExist(err) {
+			log.Warn("remove wal file failed", zap.String("file", name), zap.Error(err))
+		}
+	}
+}
+
+func (w *wal) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.fd == nil {
+		return nil
+	}
+	err := w.fd.Sync()
+	if e := w.fd.Close(); err == nil {
+		err = e
+	}
+	w.fd = nil
+	return err
+}
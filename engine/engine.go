@@ -48,6 +48,7 @@ import (
 	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
 	"github.com/openGemini/openGemini/open_src/influx/query"
 	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
 	"go.uber.org/zap"
 )
 
@@ -81,6 +82,14 @@ type Engine struct {
 	droppingMst map[string]string
 
 	statCount int64
+
+	// orphan series of the indexes found by the last round of the index gc
+	gcMu         sync.Mutex
+	gcCandidates map[indexGroupKey]*uint64set.Set
+	gcWg         sync.WaitGroup
+
+	// pts moving or moved to other nodes
+	offloadedPts map[dbPtKey]struct{}
 }
 
 const maxInt = int(^uint(0) >> 1)
@@ -119,6 +128,8 @@ func NewEngine(dataPath, walPath string, options netstorage.EngineOptions, ctx *
 		droppingDB:   make(map[string]string),
 		droppingRP:   make(map[string]string),
 		droppingMst:  make(map[string]string),
+		gcCandidates: make(map[indexGroupKey]*uint64set.Set),
+		offloadedPts: make(map[dbPtKey]struct{}),
 	}
 
 	SetFullCompColdDuration(options.FullCompactColdDuration)
@@ -132,6 +143,7 @@ func NewEngine(dataPath, walPath string, options netstorage.EngineOptions, ctx *
 	immutable.SetCompactLimit(options.CompactThroughput, options.CompactThroughputBurst)
 	immutable.SetSnapshotLimit(options.SnapshotThroughput, options.SnapshotThroughputBurst)
 	immutable.SegMergeFlag(int32(options.CompactionMethod))
+	immutable.SetFloatCompression(options.FloatCompression)
 	immutable.Init()
 
 	return eng, nil
@@ -164,6 +176,11 @@ func (e *Engine) Open(ptIds []uint32, durationInfos map[uint64]*meta2.ShardDurat
 		return err
 	}
 
+	if e.engOpt.IndexGCInterval > 0 {
+		e.gcWg.Add(1)
+		go e.indexGCLoop(e.engOpt.IndexGCInterval)
+	}
+
 	return nil
 }
 
@@ -208,11 +225,13 @@ func (e *Engine) loadShards(ptIds []uint32, durationInfos map[uint64]*meta2.Shar
 }
 
 func (e *Engine) Close() error {
+	// the index gc refers the db pts, it is stopped before they are closed
+	e.closed.Close()
+	e.gcWg.Wait()
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	e.closed.Close()
-
 	start := time.Now()
 	log.Info("start close engine...")
 	defer func(tm time.Time) {
@@ -731,6 +750,14 @@ func (e *Engine) DeleteDatabase(db string, ptId uint32) (err error) {
 	}
 	defer e.endDrop(db, e.droppingDB)
 
+	e.mu.Lock()
+	for key := range e.offloadedPts {
+		if key.db == db {
+			delete(e.offloadedPts, key)
+		}
+	}
+	e.mu.Unlock()
+
 	atomic.AddInt64(&stat.EngineStat.DropDatabaseCount, 1)
 	defer func(tm time.Time) {
 		d := time.Since(tm)
@@ -1362,12 +1389,12 @@ func (e *Engine) checkAndAddRefPTNoLock(database string, ptID uint32) error {
 			} else {
 				return meta2.ErrDBPTClose
 			}
-		} else {
-			return ErrPTNotFound
 		}
-	} else {
-		return ErrPTNotFound
 	}
+	if e.isOffloaded(database, ptID) {
+		return meta2.ErrDBPTClose
+	}
+	return ErrPTNotFound
 }
 
 func (e *Engine) unrefDBPT(database string, ptID uint32) {
@@ -83,6 +83,9 @@ func NewProcessors(inRowDataType, outRowDataType hybridqp.RowDataType, exprOpt [
 			case "median":
 				routine, err = NewMedianRoutineImpl(inRowDataType, outRowDataType, exprOpt[i], isSingleCall)
 				coProcessor.AppendRoutine(routine)
+			case "hll_sketch", "hll_merge", "tdigest_sketch", "tdigest_merge":
+				routine, err = NewSketchRoutineImpl(inRowDataType, outRowDataType, exprOpt[i], isSingleCall)
+				coProcessor.AppendRoutine(routine)
 			case "mode":
 				routine, err = NewModeRoutineImpl(inRowDataType, outRowDataType, exprOpt[i], isSingleCall)
 				coProcessor.AppendRoutine(routine)
@@ -526,6 +529,33 @@ func NewPercentileRoutineImpl(inRowDataType, outRowDataType hybridqp.RowDataType
 	}
 }
 
+func NewSketchRoutineImpl(inRowDataType, outRowDataType hybridqp.RowDataType, opt hybridqp.ExprOptions, isSingleCall bool) (Routine, error) {
+	name := opt.Expr.(*influxql.Call).Name
+	inOrdinal := inRowDataType.FieldIndex(opt.Expr.(*influxql.Call).Args[0].(*influxql.VarRef).Val)
+	outOrdinal := outRowDataType.FieldIndex(opt.Ref.Val)
+	if inOrdinal < 0 || outOrdinal < 0 {
+		panic("input and output schemas are not aligned for sketch iterator")
+	}
+	dataType := inRowDataType.Field(inOrdinal).Expr.(*influxql.VarRef).Type
+	var s sketch
+	switch {
+	case name == "hll_sketch" && (dataType == influxql.Float || dataType == influxql.Integer ||
+		dataType == influxql.Boolean || dataType == influxql.String || dataType == influxql.Tag):
+		s = newHLLSketch()
+	case name == "tdigest_sketch" && (dataType == influxql.Float || dataType == influxql.Integer):
+		s = newTDigestSketch()
+	case name == "hll_merge" && dataType == influxql.String:
+		s = newHLLSketch()
+	case name == "tdigest_merge" && dataType == influxql.String:
+		s = newTDigestSketch()
+	default:
+		return nil, errno.NewError(errno.UnsupportedDataType, name, dataType.String())
+	}
+	merge := name == "hll_merge" || name == "tdigest_merge"
+	return NewRoutineImpl(NewSketchIterator(s, merge, isSingleCall, dataType, inOrdinal, outOrdinal),
+		inOrdinal, outOrdinal), nil
+}
+
 func NewTopRoutineImpl(inRowDataType, outRowDataType hybridqp.RowDataType, opt hybridqp.ExprOptions, auxProcessor []*AuxProcessor) (Routine, error) {
 	expr, ok := opt.Expr.(*influxql.Call)
 	if !ok {
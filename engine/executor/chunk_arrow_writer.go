@@ -0,0 +1,454 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/influxdb/models"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+)
+
+const (
+	ArrowMeasurementKey = "measurement"
+	ArrowTimeField      = "time"
+)
+
+var errArrowWriterClosed = errors.New("arrow chunk writer is closed")
+
+// ArrowChunkWriter encodes the results of the statements as Arrow IPC streams.
+// A new stream is started whenever the schema changes, readers consume the streams one after another.
+type ArrowChunkWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	mem    memory.Allocator
+	schema *arrow.Schema
+	writer *ipc.Writer
+	rows   int64
+	closed bool
+}
+
+func NewArrowChunkWriter(w io.Writer) *ArrowChunkWriter {
+	return &ArrowChunkWriter{
+		w:   w,
+		mem: memory.NewGoAllocator(),
+	}
+}
+
+// WriteChunk implements query.ChunkWriter, the chunk is encoded before returning
+func (w *ArrowChunkWriter) WriteChunk(chunk interface{}) error {
+	c, ok := chunk.(Chunk)
+	if !ok {
+		return errno.NewError(errno.TypeAssertFail, "executor.Chunk")
+	}
+	if c.NumberOfRows() == 0 {
+		return nil
+	}
+	statistics.ExecutorStat.SinkRows.Push(int64(c.NumberOfRows()))
+
+	rec, err := ChunkToArrowRecord(c, w.mem)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+	return w.write(rec)
+}
+
+// WriteRow encodes a row returned by the statements that are not executed by the pipeline,
+// such as SHOW statements. The type of a column is the type of its first non-null value,
+// a column without values keeps its type in the current stream.
+func (w *ArrowChunkWriter) WriteRow(row *models.Row) error {
+	if len(row.Values) == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	prev := w.schema
+	w.mu.Unlock()
+
+	rec, err := RowToArrowRecord(row, w.mem, prev)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+	return w.write(rec)
+}
+
+// Rows returns the number of rows written
+func (w *ArrowChunkWriter) Rows() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rows
+}
+
+// Close ends the current stream, the chunks written after closing are rejected
+func (w *ArrowChunkWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return w.closeStream()
+}
+
+func (w *ArrowChunkWriter) write(rec array.Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return errArrowWriterClosed
+	}
+
+	if w.writer == nil || !arrowSchemaEqual(w.schema, rec.Schema()) {
+		if err := w.closeStream(); err != nil {
+			return err
+		}
+		w.schema = rec.Schema()
+		w.writer = ipc.NewWriter(w.w, ipc.WithSchema(w.schema), ipc.WithAllocator(w.mem))
+	}
+	if err := w.writer.Write(rec); err != nil {
+		return err
+	}
+	w.rows += rec.NumRows()
+
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (w *ArrowChunkWriter) closeStream() error {
+	if w.writer == nil {
+		return nil
+	}
+	err := w.writer.Close()
+	w.writer = nil
+	w.schema = nil
+	return err
+}
+
+func arrowSchemaEqual(a, b *arrow.Schema) bool {
+	if len(a.Fields()) != len(b.Fields()) {
+		return false
+	}
+	for i := range a.Fields() {
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Name != fb.Name || !arrow.TypeEqual(fa.Type, fb.Type) {
+			return false
+		}
+	}
+	return arrowMeasurement(a) == arrowMeasurement(b)
+}
+
+func arrowMeasurement(s *arrow.Schema) string {
+	md := s.Metadata()
+	if i := md.FindKey(ArrowMeasurementKey); i >= 0 {
+		return md.Values()[i]
+	}
+	return ""
+}
+
+func arrowDataType(dt influxql.DataType) (arrow.DataType, error) {
+	switch dt {
+	case influxql.Float:
+		return arrow.PrimitiveTypes.Float64, nil
+	case influxql.Integer:
+		return arrow.PrimitiveTypes.Int64, nil
+	case influxql.Boolean:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case influxql.String, influxql.Tag:
+		return arrow.BinaryTypes.String, nil
+	}
+	return nil, errno.NewError(errno.UnsupportedDataType, "arrow", dt.String())
+}
+
+// chunkTagKeys returns the sorted tag keys of all series in the chunk
+func chunkTagKeys(c Chunk) ([]string, [][]string, [][]string) {
+	set := make(map[string]struct{})
+	keys := make([][]string, len(c.Tags()))
+	values := make([][]string, len(c.Tags()))
+	for i := range c.Tags() {
+		keys[i], values[i] = c.Tags()[i].GetChunkTagAndValues()
+		for _, k := range keys[i] {
+			set[k] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for k := range set {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names, keys, values
+}
+
+// ChunkToArrowRecord converts the chunk to a record with the columns: tag keys, time and the fields
+func ChunkToArrowRecord(c Chunk, mem memory.Allocator) (array.Record, error) {
+	return chunkToArrowRecord(c, mem, 0, len(c.TagIndex()))
+}
+
+// ChunkToArrowRecords converts each series of the chunk to its own record, all of them
+// sharing the schema of ChunkToArrowRecord
+func ChunkToArrowRecords(c Chunk, mem memory.Allocator) ([]array.Record, error) {
+	if len(c.TagIndex()) == 0 {
+		rec, err := ChunkToArrowRecord(c, mem)
+		if err != nil {
+			return nil, err
+		}
+		return []array.Record{rec}, nil
+	}
+
+	recs := make([]array.Record, 0, len(c.TagIndex()))
+	for i := range c.TagIndex() {
+		rec, err := chunkToArrowRecord(c, mem, i, i+1)
+		if err != nil {
+			for _, r := range recs {
+				r.Release()
+			}
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// chunkToArrowRecord converts the rows of the series [first, last) of the chunk
+func chunkToArrowRecord(c Chunk, mem memory.Allocator, first, last int) (array.Record, error) {
+	tagNames, keys, values := chunkTagKeys(c)
+	refs := c.RowDataType().MakeRefs()
+
+	fields := make([]arrow.Field, 0, len(tagNames)+1+len(refs))
+	for _, name := range tagNames {
+		fields = append(fields, arrow.Field{Name: name, Type: arrow.BinaryTypes.String, Nullable: true})
+	}
+	fields = append(fields, arrow.Field{Name: ArrowTimeField, Type: arrow.FixedWidthTypes.Timestamp_ns})
+	for _, ref := range refs {
+		dt, err := arrowDataType(ref.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, arrow.Field{Name: ref.Val, Type: dt, Nullable: true})
+	}
+	metadata := arrow.NewMetadata([]string{ArrowMeasurementKey}, []string{c.Name()})
+	b := array.NewRecordBuilder(mem, arrow.NewSchema(fields, &metadata))
+	defer b.Release()
+
+	rows := c.NumberOfRows()
+	tagIdx := c.TagIndex()
+	seriesEnd := func(i int) int {
+		if i < len(tagIdx) {
+			return tagIdx[i]
+		}
+		return rows
+	}
+	begin, end := 0, rows
+	if len(tagIdx) > 0 {
+		begin, end = tagIdx[first], seriesEnd(last)
+	}
+	for i := first; i < last; i++ {
+		start, stop := tagIdx[i], seriesEnd(i+1)
+		for j, name := range tagNames {
+			sb := b.Field(j).(*array.StringBuilder)
+			k := indexOfString(keys[i], name)
+			for n := start; n < stop; n++ {
+				if k < 0 {
+					sb.AppendNull()
+				} else {
+					sb.Append(values[i][k])
+				}
+			}
+		}
+	}
+
+	tb := b.Field(len(tagNames)).(*array.TimestampBuilder)
+	for _, t := range c.Time()[begin:end] {
+		tb.Append(arrow.Timestamp(t))
+	}
+
+	for i, col := range c.Columns() {
+		idx := len(tagNames) + 1 + i
+		switch refs[i].Type {
+		case influxql.Float:
+			appendArrowFloat64(b, col, idx, begin, end)
+		case influxql.Integer:
+			appendArrowInt64(b, col, idx, begin, end)
+		case influxql.Boolean:
+			appendArrowBoolean(b.Field(idx).(*array.BooleanBuilder), col, begin, end)
+		case influxql.String, influxql.Tag:
+			appendArrowString(b.Field(idx).(*array.StringBuilder), col, begin, end)
+		}
+	}
+	return b.NewRecord(), nil
+}
+
+func appendArrowBoolean(b *array.BooleanBuilder, col Column, begin, end int) {
+	if col.NilCount() == 0 {
+		b.AppendValues(col.BooleanValues()[begin:end], nil)
+		return
+	}
+	for j := begin; j < end; j++ {
+		if col.IsNilV2(j) {
+			b.AppendNull()
+		} else {
+			b.Append(col.BooleanValue(col.GetValueIndexV2(j)))
+		}
+	}
+}
+
+func appendArrowString(b *array.StringBuilder, col Column, begin, end int) {
+	for j := begin; j < end; j++ {
+		if col.NilCount() == 0 {
+			b.Append(col.StringValue(j))
+		} else if col.IsNilV2(j) {
+			b.AppendNull()
+		} else {
+			b.Append(col.StringValue(col.GetValueIndexV2(j)))
+		}
+	}
+}
+
+func indexOfString(s []string, v string) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// RowToArrowRecord converts the row to a record with the columns: tag keys and the row columns.
+// A column without any value takes its type from prev when prev has a column of the same name
+func RowToArrowRecord(row *models.Row, mem memory.Allocator, prev *arrow.Schema) (array.Record, error) {
+	tagNames := make([]string, 0, len(row.Tags))
+	for k := range row.Tags {
+		tagNames = append(tagNames, k)
+	}
+	sort.Strings(tagNames)
+
+	fields := make([]arrow.Field, 0, len(tagNames)+len(row.Columns))
+	for _, name := range tagNames {
+		fields = append(fields, arrow.Field{Name: name, Type: arrow.BinaryTypes.String, Nullable: true})
+	}
+	for i, name := range row.Columns {
+		fields = append(fields, arrow.Field{Name: name, Type: rowColumnArrowType(row, i, prev), Nullable: true})
+	}
+	metadata := arrow.NewMetadata([]string{ArrowMeasurementKey}, []string{row.Name})
+	b := array.NewRecordBuilder(mem, arrow.NewSchema(fields, &metadata))
+	defer b.Release()
+
+	for j, name := range tagNames {
+		sb := b.Field(j).(*array.StringBuilder)
+		for range row.Values {
+			sb.Append(row.Tags[name])
+		}
+	}
+	for i := range row.Columns {
+		fb := b.Field(len(tagNames) + i)
+		for _, values := range row.Values {
+			if i >= len(values) || values[i] == nil {
+				fb.AppendNull()
+				continue
+			}
+			if err := appendArrowValue(fb, values[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.NewRecord(), nil
+}
+
+func rowColumnArrowType(row *models.Row, col int, prev *arrow.Schema) arrow.DataType {
+	for _, values := range row.Values {
+		if col >= len(values) || values[col] == nil {
+			continue
+		}
+		switch values[col].(type) {
+		case float64:
+			return arrow.PrimitiveTypes.Float64
+		case int64, int, int32:
+			return arrow.PrimitiveTypes.Int64
+		case uint64:
+			return arrow.PrimitiveTypes.Uint64
+		case bool:
+			return arrow.FixedWidthTypes.Boolean
+		case time.Time:
+			return arrow.FixedWidthTypes.Timestamp_ns
+		}
+		break
+	}
+
+	if prev != nil {
+		if idx := prev.FieldIndices(row.Columns[col]); len(idx) == 1 {
+			return prev.Field(idx[0]).Type
+		}
+	}
+	return arrow.BinaryTypes.String
+}
+
+func appendArrowValue(b array.Builder, value interface{}) error {
+	switch fb := b.(type) {
+	case *array.Float64Builder:
+		v, ok := value.(float64)
+		if !ok {
+			return errno.NewError(errno.TypeAssertFail, "float64")
+		}
+		fb.Append(v)
+	case *array.Int64Builder:
+		switch v := value.(type) {
+		case int64:
+			fb.Append(v)
+		case int:
+			fb.Append(int64(v))
+		case int32:
+			fb.Append(int64(v))
+		default:
+			return errno.NewError(errno.TypeAssertFail, "int64")
+		}
+	case *array.Uint64Builder:
+		v, ok := value.(uint64)
+		if !ok {
+			return errno.NewError(errno.TypeAssertFail, "uint64")
+		}
+		fb.Append(v)
+	case *array.BooleanBuilder:
+		v, ok := value.(bool)
+		if !ok {
+			return errno.NewError(errno.TypeAssertFail, "bool")
+		}
+		fb.Append(v)
+	case *array.TimestampBuilder:
+		v, ok := value.(time.Time)
+		if !ok {
+			return errno.NewError(errno.TypeAssertFail, "time.Time")
+		}
+		fb.Append(arrow.Timestamp(v.UnixNano()))
+	case *array.StringBuilder:
+		v, ok := value.(string)
+		if !ok {
+			return errno.NewError(errno.TypeAssertFail, "string")
+		}
+		fb.Append(v)
+	}
+	return nil
+}
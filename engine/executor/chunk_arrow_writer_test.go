@@ -0,0 +1,188 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/influxdata/influxdb/models"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildArrowWriterChunk() Chunk {
+	row := hybridqp.NewRowDataTypeImpl(
+		influxql.VarRef{Val: "f", Type: influxql.Float},
+		influxql.VarRef{Val: "i", Type: influxql.Integer},
+		influxql.VarRef{Val: "b", Type: influxql.Boolean},
+		influxql.VarRef{Val: "s", Type: influxql.String},
+	)
+	ch := NewChunkBuilder(row).NewChunk("cpu")
+	ch.AppendTime(1, 2, 3, 4)
+	ch.AppendTagsAndIndexes([]ChunkTags{*ParseChunkTags("host=a"), *ParseChunkTags("host=b,region=r1")}, []int{0, 2})
+	ch.AppendIntervalIndex(0, 2)
+	ch.Column(0).AppendFloatValues(1.5, 3.5)
+	ch.Column(0).AppendNilsV2(true, false, true, false)
+	ch.Column(1).AppendIntegerValues(1, 2, 3, 4)
+	ch.Column(1).AppendNilsV2(true, true, true, true)
+	ch.Column(2).AppendBooleanValues(true, false)
+	ch.Column(2).AppendNilsV2(false, true, true, false)
+	ch.Column(3).AppendStringValues("x", "y", "z")
+	ch.Column(3).AppendNilsV2(true, true, false, true)
+	return ch
+}
+
+func TestChunkToArrowRecord(t *testing.T) {
+	w := NewArrowChunkWriter(nil)
+	rec, err := ChunkToArrowRecord(buildArrowWriterChunk(), w.mem)
+	require.NoError(t, err)
+	defer rec.Release()
+
+	names := make([]string, 0, rec.NumCols())
+	for _, f := range rec.Schema().Fields() {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"host", "region", "time", "f", "i", "b", "s"}, names)
+	assert.Equal(t, "cpu", arrowMeasurement(rec.Schema()))
+	assert.Equal(t, int64(4), rec.NumRows())
+
+	host := rec.Column(0).(*array.String)
+	region := rec.Column(1).(*array.String)
+	assert.Equal(t, "a", host.Value(1))
+	assert.Equal(t, "b", host.Value(2))
+	assert.True(t, region.IsNull(0))
+	assert.Equal(t, "r1", region.Value(3))
+
+	tm := rec.Column(2).(*array.Timestamp)
+	assert.Equal(t, arrow.Timestamp(3), tm.Value(2))
+
+	f := rec.Column(3).(*array.Float64)
+	assert.True(t, f.IsNull(1))
+	assert.Equal(t, 3.5, f.Value(2))
+	assert.Equal(t, []int64{1, 2, 3, 4}, rec.Column(4).(*array.Int64).Int64Values())
+
+	b := rec.Column(5).(*array.Boolean)
+	assert.True(t, b.IsNull(0))
+	assert.True(t, b.Value(1))
+	assert.False(t, b.Value(2))
+	assert.True(t, b.IsNull(3))
+
+	s := rec.Column(6).(*array.String)
+	assert.Equal(t, "y", s.Value(1))
+	assert.True(t, s.IsNull(2))
+	assert.Equal(t, "z", s.Value(3))
+}
+
+func TestChunkToArrowRecords(t *testing.T) {
+	w := NewArrowChunkWriter(nil)
+	recs, err := ChunkToArrowRecords(buildArrowWriterChunk(), w.mem)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(recs))
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+
+	assert.True(t, recs[0].Schema().Equal(recs[1].Schema()))
+	assert.Equal(t, int64(2), recs[0].NumRows())
+	assert.Equal(t, int64(2), recs[1].NumRows())
+
+	second := recs[1]
+	assert.Equal(t, "b", second.Column(0).(*array.String).Value(0))
+	assert.Equal(t, "r1", second.Column(1).(*array.String).Value(1))
+	assert.Equal(t, []arrow.Timestamp{3, 4}, second.Column(2).(*array.Timestamp).TimestampValues())
+	assert.Equal(t, 3.5, second.Column(3).(*array.Float64).Value(0))
+	assert.True(t, second.Column(3).(*array.Float64).IsNull(1))
+	assert.Equal(t, []int64{3, 4}, second.Column(4).(*array.Int64).Int64Values())
+	assert.False(t, second.Column(5).(*array.Boolean).Value(0))
+	assert.True(t, second.Column(5).(*array.Boolean).IsNull(1))
+	assert.True(t, second.Column(6).(*array.String).IsNull(0))
+	assert.Equal(t, "z", second.Column(6).(*array.String).Value(1))
+}
+
+func TestArrowChunkWriter_Streams(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewArrowChunkWriter(&buf)
+	require.NoError(t, w.WriteChunk(buildArrowWriterChunk()))
+	require.NoError(t, w.WriteChunk(buildArrowWriterChunk()))
+	require.Error(t, w.WriteChunk("not a chunk"))
+
+	// a different schema starts a new stream
+	require.NoError(t, w.WriteRow(&models.Row{
+		Name:    "databases",
+		Columns: []string{"name", "created"},
+		Values:  [][]interface{}{{"db0", time.Unix(0, 10)}, {"db1", nil}},
+	}))
+	require.NoError(t, w.Close())
+	assert.Equal(t, int64(10), w.Rows())
+
+	rd, err := ipc.NewReader(&buf)
+	require.NoError(t, err)
+	var rows int64
+	for rd.Next() {
+		rows += rd.Record().NumRows()
+	}
+	rd.Release()
+	assert.Equal(t, int64(8), rows)
+
+	rd, err = ipc.NewReader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "databases", arrowMeasurement(rd.Schema()))
+	require.True(t, rd.Next())
+	rec := rd.Record()
+	assert.Equal(t, "db1", rec.Column(0).(*array.String).Value(1))
+	assert.Equal(t, arrow.Timestamp(10), rec.Column(1).(*array.Timestamp).Value(0))
+	assert.True(t, rec.Column(1).IsNull(1))
+	assert.False(t, rd.Next())
+	rd.Release()
+}
+
+type countChunkWriter struct {
+	rows int
+	err  error
+}
+
+func (w *countChunkWriter) WriteChunk(chunk interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.rows += chunk.(Chunk).NumberOfRows()
+	return nil
+}
+
+func TestHttpChunkSender_ChunkWriter(t *testing.T) {
+	cw := &countChunkWriter{}
+	s := NewHttpChunkSender(query.ProcessorOptions{ChunkWriter: cw}, 4)
+	assert.False(t, s.Write(buildArrowWriterChunk(), false))
+	assert.False(t, s.Write(nil, true))
+	assert.Equal(t, 4, cw.rows)
+	assert.NoError(t, s.Err())
+
+	cw.err = errors.New("client closed")
+	s.Write(buildArrowWriterChunk(), false)
+	assert.EqualError(t, s.Err(), "client closed")
+}
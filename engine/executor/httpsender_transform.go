@@ -39,6 +39,7 @@ type HttpChunkSender struct {
 	RowChunk  RowChunk
 	opt       query.ProcessorOptions
 	ResetTime bool
+	err       error
 }
 
 func NewHttpChunkSender(opt query.ProcessorOptions, colLength int) *HttpChunkSender {
@@ -51,6 +52,11 @@ func NewHttpChunkSender(opt query.ProcessorOptions, colLength int) *HttpChunkSen
 }
 
 func (w *HttpChunkSender) Write(chunk Chunk, lastChunk bool) bool {
+	if w.opt.ChunkWriter != nil {
+		w.writeChunk(chunk)
+		return false
+	}
+
 	w.buffRows = w.GetRows(chunk)
 
 	var chunkedRow models.Rows
@@ -107,6 +113,19 @@ func (w *HttpChunkSender) Write(chunk Chunk, lastChunk bool) bool {
 	return partial
 }
 
+// writeChunk passes the chunk to the ChunkWriter without converting it to rows
+func (w *HttpChunkSender) writeChunk(chunk Chunk) {
+	if chunk == nil || w.err != nil {
+		return
+	}
+	w.err = w.opt.ChunkWriter.WriteChunk(chunk)
+}
+
+// Err returns the error of the ChunkWriter
+func (w *HttpChunkSender) Err() error {
+	return w.err
+}
+
 // GetRows transfer Chunk to models.Rows
 func (w *HttpChunkSender) GetRows(chunk Chunk) models.Rows {
 	if chunk == nil {
@@ -288,6 +307,7 @@ var aggregationCall = map[string]bool{
 	"count": true, "distinct": true, "sum": true,
 	"mean": true, "median": true, "spread": true,
 	"mode": true, "stddev": true, "integral": true,
+	"hll_sketch": true, "tdigest_sketch": true,
 }
 
 var transformationCall = map[string]bool{
@@ -389,7 +409,7 @@ func (trans *HttpSenderTransform) Work(ctx context.Context) error {
 				for partial {
 					partial = trans.Writer.Write(nil, true)
 				}
-				return nil
+				return trans.Writer.Err()
 			}
 
 			partial := trans.Writer.Write(chunk, false)
@@ -397,6 +417,9 @@ func (trans *HttpSenderTransform) Work(ctx context.Context) error {
 				partial = trans.Writer.Write(nil, false)
 			}
 			tracing.EndPP(span)
+			if err := trans.Writer.Err(); err != nil {
+				return err
+			}
 		case <-ctx.Done():
 			return nil
 		}
@@ -507,7 +530,7 @@ func (trans *HttpSenderHintTransform) Work(ctx context.Context) error {
 						partial = trans.Writer.Write(nil, true)
 					}
 				}
-				return nil
+				return trans.Writer.Err()
 			}
 
 			if trans.init && len(trans.ridIdxMap) == 0 && chunk != nil {
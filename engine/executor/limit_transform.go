@@ -216,8 +216,11 @@ func (trans *LimitTransform) Visit(vertex *TransformVertex) TransformVertexVisit
 		return trans
 	}
 
-	if rpc, ok := vertex.transform.(*RPCReaderTransform); ok {
-		rpc.Abort()
+	switch reader := vertex.transform.(type) {
+	case *RPCReaderTransform:
+		reader.Abort()
+	case *LocalReaderTransform:
+		reader.Abort()
 	}
 
 	return trans
@@ -0,0 +1,286 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/lib/tracing"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"go.uber.org/zap"
+)
+
+// LocalStore is a store engine living in the same process as the query
+// engine. A RemoteQuery with Local set is executed against it directly
+// instead of being sent to a ts-store node.
+type LocalStore interface {
+	hybridqp.StoreEngine
+	RefEngineDbPt(db string, ptId uint32) error
+}
+
+func NewLocalReaderTransform(outRowDataType hybridqp.RowDataType, opt query.ProcessorOptions, rq *RemoteQuery) *LocalReaderTransform {
+	trans := &LocalReaderTransform{
+		Output:      NewChunkPort(outRowDataType),
+		opt:         opt,
+		query:       rq,
+		abortSignal: make(chan struct{}),
+		logger: logger.NewLogger(errno.ModuleQueryEngine).With(
+			zap.String("query", "local executor"),
+			zap.Uint64("trace_id", opt.Traceid)),
+	}
+	trans.InitOnce()
+	return trans
+}
+
+// LocalReaderTransform is the in-process counterpart of RPCReaderTransform.
+// It builds the store side of the distributed plan against RemoteQuery.Local
+// and feeds the produced chunks to its output port.
+type LocalReaderTransform struct {
+	BaseProcessor
+
+	Output *ChunkPort
+
+	opt         query.ProcessorOptions
+	query       *RemoteQuery
+	distributed hybridqp.QueryNode
+	abortSignal chan struct{}
+	aborted     bool
+	logger      *logger.Logger
+
+	span *tracing.Span
+}
+
+func (t *LocalReaderTransform) IsSink() bool {
+	return true
+}
+
+func (t *LocalReaderTransform) Name() string {
+	return "LocalReaderTransform"
+}
+
+func (t *LocalReaderTransform) Explain() []ValuePair {
+	return nil
+}
+
+func (t *LocalReaderTransform) Distribute(node hybridqp.QueryNode) {
+	t.distributed = node
+}
+
+func (t *LocalReaderTransform) Abort() {
+	t.aborted = true
+	t.Once(func() {
+		close(t.abortSignal)
+	})
+}
+
+func (t *LocalReaderTransform) Close() {
+	t.Abort()
+	t.Output.Close()
+}
+
+func (t *LocalReaderTransform) Release() error {
+	t.Once(func() {
+		close(t.abortSignal)
+	})
+	return nil
+}
+
+func (t *LocalReaderTransform) Work(ctx context.Context) error {
+	t.span = t.StartSpan("read_local_chunk", false)
+	defer func() {
+		tracing.Finish(t.span)
+		t.Output.Close()
+	}()
+
+	statistics.ExecutorStat.SourceWidth.Push(int64(t.Output.RowDataType.NumColumn()))
+
+	// the store side plan gets its own copy of the node, exactly as a
+	// ts-store would after decoding the request
+	buf, err := MarshalQueryNode(t.distributed)
+	if err != nil {
+		return err
+	}
+	node, err := UnmarshalQueryNode(buf)
+	if err != nil {
+		return err
+	}
+
+	store := t.query.Local
+	unrefs := make([]UnRefDbPt, 0, len(t.query.ShardIDs))
+	m := make(map[uint64][][]interface{}, len(t.query.ShardIDs))
+	for _, sid := range t.query.ShardIDs {
+		if err := store.RefEngineDbPt(t.query.Database, t.query.PtID); err != nil {
+			continue
+		}
+		unrefs = append(unrefs, UnRefDbPt{Db: t.query.Database, Pt: t.query.PtID})
+		m[sid] = nil
+	}
+	if len(m) == 0 {
+		return nil
+	}
+
+	// the query context carries sql side statistics which the store side
+	// transforms must not see
+	storeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	traits := NewLocalStoreExchangeTraits(t.send, m)
+	builder := NewScannerStoreExecutorBuilder(traits, store, t.query, storeCtx, &unrefs)
+	p, err := builder.Build(node)
+	if err != nil {
+		for i := range unrefs {
+			store.UnrefEngineDbPt(unrefs[i].Db, unrefs[i].Pt)
+		}
+		return err
+	}
+	exec := p.(*PipelineExecutor)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-t.abortSignal:
+			if t.aborted {
+				exec.Abort()
+			}
+		case <-ctx.Done():
+			exec.Abort()
+		case <-done:
+		}
+	}()
+
+	if err := exec.ExecuteExecutor(storeCtx); err != nil {
+		if exec.Aborted() {
+			t.logger.Error("local executor aborted", zap.Error(err))
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// send hands a chunk produced by the store side plan over to the consumer.
+// Store side transforms reuse their chunks, so the chunk is copied through
+// its codec just like it would be on the wire. The decoded chunk refers to
+// the encoded buffer, so the buffer is not returned to the pool.
+func (t *LocalReaderTransform) send(c Chunk) error {
+	if t.aborted {
+		return nil
+	}
+	if t.span != nil {
+		t.span.Count("count", 1)
+	}
+
+	buf, err := c.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	chunk := &ChunkImpl{}
+	if err = chunk.Unmarshal(buf); err != nil {
+		return err
+	}
+	chunk.SetRowDataType(t.Output.RowDataType)
+
+	statistics.ExecutorStat.SourceRows.Push(int64(chunk.NumberOfRows()))
+
+	select {
+	case t.Output.State <- chunk:
+	case <-t.abortSignal:
+	}
+	return nil
+}
+
+func (t *LocalReaderTransform) GetOutputs() Ports {
+	return Ports{t.Output}
+}
+
+func (t *LocalReaderTransform) GetInputs() Ports {
+	return Ports{}
+}
+
+func (t *LocalReaderTransform) GetOutputNumber(_ Port) int {
+	return 1
+}
+
+func (t *LocalReaderTransform) GetInputNumber(_ Port) int {
+	return INVALID_NUMBER
+}
+
+// LocalSenderTransform is the producer end of a local node exchange.
+type LocalSenderTransform struct {
+	BaseProcessor
+
+	Input *ChunkPort
+	send  func(Chunk) error
+}
+
+func NewLocalSenderTransform(rt hybridqp.RowDataType, send func(Chunk) error) *LocalSenderTransform {
+	return &LocalSenderTransform{
+		Input: NewChunkPort(rt),
+		send:  send,
+	}
+}
+
+func (t *LocalSenderTransform) Name() string {
+	return "LocalSenderTransform"
+}
+
+func (t *LocalSenderTransform) Explain() []ValuePair {
+	return nil
+}
+
+func (t *LocalSenderTransform) Close() {
+}
+
+func (t *LocalSenderTransform) Work(ctx context.Context) error {
+	statistics.ExecutorStat.SinkWidth.Push(int64(t.Input.RowDataType.NumColumn()))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case chunk, ok := <-t.Input.State:
+			if !ok {
+				return nil
+			}
+			statistics.ExecutorStat.SinkRows.Push(int64(chunk.NumberOfRows()))
+			if err := t.send(chunk); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (t *LocalSenderTransform) GetOutputs() Ports {
+	return Ports{}
+}
+
+func (t *LocalSenderTransform) GetInputs() Ports {
+	return Ports{t.Input}
+}
+
+func (t *LocalSenderTransform) GetOutputNumber(_ Port) int {
+	return INVALID_NUMBER
+}
+
+func (t *LocalSenderTransform) GetInputNumber(_ Port) int {
+	return 1
+}
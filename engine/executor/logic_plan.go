@@ -512,6 +512,8 @@ func (p *LogicalAggregate) CountToSum() {
 	for _, call := range p.calls {
 		if call.Name == "count" {
 			call.Name = "sum"
+		} else if name, ok := sketchMergeCall[call.Name]; ok {
+			call.Name = name
 		}
 	}
 }
@@ -245,6 +245,7 @@ func NewMaterializeTransform(inRowDataType hybridqp.RowDataType, outRowDataType
 			op.Valuer{},
 			query.MathValuer{},
 			StringValuer{},
+			SketchValuer{},
 			trans.chunkValuer,
 		),
 		IntegerFloatDivision: true,
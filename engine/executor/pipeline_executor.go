@@ -401,6 +401,7 @@ func (dag *TransformDag) WalkVertex(vertex *TransformVertex, fn func(to, from *T
 
 type StoreExchangeTraits struct {
 	w                  spdy.Responser
+	send               func(Chunk) error
 	mapShardsToReaders map[uint64][][]interface{}
 	shards             []uint64
 	shardIndex         int
@@ -423,6 +424,14 @@ func NewStoreExchangeTraits(w spdy.Responser, mapShardsToReaders map[uint64][][]
 	return traits
 }
 
+// NewLocalStoreExchangeTraits creates traits whose node producer hands chunks to send
+// instead of writing them to a spdy.Responser.
+func NewLocalStoreExchangeTraits(send func(Chunk) error, mapShardsToReaders map[uint64][][]interface{}) *StoreExchangeTraits {
+	traits := NewStoreExchangeTraits(nil, mapShardsToReaders)
+	traits.send = send
+	return traits
+}
+
 func (t *StoreExchangeTraits) Reset() {
 	t.shardIndex = 0
 	t.readerIndex = 0
@@ -609,6 +618,14 @@ func (builder *ExecutorBuilder) addNodeProducer(exchange *LogicalExchange) (*Tra
 	childNode := exchange.Children()[0]
 	child, err := builder.addNodeToDag(childNode)
 
+	if builder.traits.send != nil {
+		sender := NewLocalSenderTransform(exchange.RowDataType(), builder.traits.send)
+		vertex := NewTransformVertex(exchange, sender)
+		builder.dag.AddVertex(vertex)
+		builder.dag.AddEdge(child, vertex)
+		return vertex, err
+	}
+
 	if builder.traits.w == nil {
 		return nil, errno.NewError(errno.LogicalPlanBuildFail, "missing  spdy.Responser in node exchange produce")
 		//panic("missing  spdy.Responser in node exchange produce")
@@ -621,13 +638,26 @@ func (builder *ExecutorBuilder) addNodeProducer(exchange *LogicalExchange) (*Tra
 	return vertex, err
 }
 
+// createNodeReader creates the transform reading the producer side of a node exchange,
+// remote queries against a LocalStore are executed in process.
+func (builder *ExecutorBuilder) createNodeReader(exchange *LogicalExchange, rq *RemoteQuery) Processor {
+	opt := *exchange.schema.Options().(*query.ProcessorOptions)
+	clone := exchange.Clone()
+	clone.(*LogicalExchange).ToProducer()
+	if rq.Local != nil {
+		reader := NewLocalReaderTransform(exchange.RowDataType(), opt, rq)
+		reader.Distribute(clone)
+		return reader
+	}
+	reader := NewRPCReaderTransform(exchange.RowDataType(), opt, rq)
+	reader.Distribute(clone)
+	return reader
+}
+
 func (builder *ExecutorBuilder) addConsumerToDag(exchange *LogicalExchange) *TransformVertex {
 	if rq, ok := exchange.eTraits[builder.currConsumer].(*RemoteQuery); ok {
 		builder.currConsumer++
-		reader := NewRPCReaderTransform(exchange.RowDataType(), *exchange.schema.Options().(*query.ProcessorOptions), rq)
-		clone := exchange.Clone()
-		clone.(*LogicalExchange).ToProducer()
-		reader.Distribute(clone)
+		reader := builder.createNodeReader(exchange, rq)
 		vertex := NewTransformVertex(exchange, reader)
 		builder.dag.AddVertex(vertex)
 		return vertex
@@ -651,10 +681,7 @@ func (builder *ExecutorBuilder) addNodeConsumer(exchange *LogicalExchange) (*Tra
 
 	for _, trait := range exchange.eTraits {
 		if rq, ok := trait.(*RemoteQuery); ok {
-			reader := NewRPCReaderTransform(exchange.RowDataType(), *exchange.schema.Options().(*query.ProcessorOptions), rq)
-			clone := exchange.Clone()
-			clone.(*LogicalExchange).ToProducer()
-			reader.Distribute(clone)
+			reader := builder.createNodeReader(exchange, rq)
 			v := NewTransformVertex(exchange, reader)
 			builder.dag.AddVertex(v)
 			readers = append(readers, v)
@@ -173,6 +173,9 @@ type RemoteQuery struct {
 	Opt      query.ProcessorOptions
 	Analyze  bool
 	Node     []byte
+
+	// Local is set when the shards live in the same process, it is never encoded
+	Local LocalStore
 }
 
 func (c *RemoteQuery) Marshal(buf []byte) ([]byte, error) {
@@ -33,7 +33,7 @@ var NotAggOnSeries = map[string]bool{
 	"derivative": true, "non_negative_derivative": true,
 	"rate": true, "irate": true, "absent": true, "stddev": true, "mode": true, "median": true,
 	"elapsed": true, "moving_average": true, "cumulative_sum": true, "integral": true, "sample": true,
-	"sliding_window": true,
+	"sliding_window": true, "hll_sketch": true, "tdigest_sketch": true,
 }
 
 func init() {
@@ -324,6 +324,14 @@ func (qs *QuerySchema) rewriteBaseCallTransformExprCall(expr influxql.Expr) infl
 			}
 			qs.mapDeriveType[replacement] = typ
 			return replacement
+		} else if expr.Name == "approx_count_distinct" || expr.Name == "approx_percentile" || expr.Name == "approx_median" {
+			replacement := qs.rewriteApproxCall(expr)
+			typ, err := qs.deriveType(expr)
+			if err != nil {
+				panic(err.Error())
+			}
+			qs.mapDeriveType[replacement] = typ
+			return replacement
 		}
 		return influxql.CloneExpr(expr)
 	default:
@@ -349,6 +357,21 @@ func (qs *QuerySchema) spreadToMaxSubMin(call *influxql.Call) influxql.Expr {
 	return be
 }
 
+// rewriteApproxCall splits an approximate aggregate into a mergeable sketch call
+// and the function that reads the result out of the merged sketch.
+func (qs *QuerySchema) rewriteApproxCall(call *influxql.Call) influxql.Expr {
+	if call.Name == "approx_count_distinct" {
+		sketch := &influxql.Call{Name: "hll_sketch", Args: []influxql.Expr{influxql.CloneExpr(call.Args[0])}}
+		return &influxql.Call{Name: "hll_count", Args: []influxql.Expr{sketch}}
+	}
+	var percentile influxql.Expr = &influxql.NumberLiteral{Val: 50}
+	if call.Name == "approx_percentile" {
+		percentile = influxql.CloneExpr(call.Args[1])
+	}
+	sketch := &influxql.Call{Name: "tdigest_sketch", Args: []influxql.Expr{influxql.CloneExpr(call.Args[0])}}
+	return &influxql.Call{Name: "tdigest_quantile", Args: []influxql.Expr{sketch, percentile}}
+}
+
 func (qs *QuerySchema) HasCall() bool {
 	return len(qs.calls) > 0
 }
@@ -574,7 +597,8 @@ func (qs *QuerySchema) HasCastorCall() bool {
 
 func (qs *QuerySchema) isMathFunction(call *influxql.Call) bool {
 	switch call.Name {
-	case "abs", "sin", "cos", "tan", "asin", "acos", "atan", "atan2", "exp", "log", "ln", "log2", "log10", "sqrt", "pow", "floor", "ceil", "round":
+	case "abs", "sin", "cos", "tan", "asin", "acos", "atan", "atan2", "exp", "log", "ln", "log2", "log10", "sqrt", "pow", "floor", "ceil", "round",
+		"hll_count", "tdigest_quantile":
 		return true
 	}
 	return false
@@ -255,3 +255,40 @@ func TestHasMathAndString(t *testing.T) {
 	assert.Equal(t, schema.HasMath(), true)
 	assert.Equal(t, schema.HasString(), true)
 }
+
+func TestQuerySchemaApproxCall(t *testing.T) {
+	fields := influxql.Fields{
+		&influxql.Field{
+			Expr: &influxql.Call{
+				Name: "approx_count_distinct",
+				Args: []influxql.Expr{&influxql.VarRef{Val: "name", Type: influxql.String}},
+			},
+		},
+		&influxql.Field{
+			Expr: &influxql.Call{
+				Name: "approx_percentile",
+				Args: []influxql.Expr{&influxql.VarRef{Val: "age", Type: influxql.Integer}, &influxql.IntegerLiteral{Val: 90}},
+			},
+		},
+		&influxql.Field{
+			Expr: &influxql.Call{
+				Name: "approx_median",
+				Args: []influxql.Expr{&influxql.VarRef{Val: "age", Type: influxql.Integer}},
+			},
+		},
+	}
+	opt := query.ProcessorOptions{}
+	schema := executor.NewQuerySchema(fields, []string{"approx_count_distinct", "approx_percentile", "approx_median"}, &opt)
+
+	assert.Equal(t, len(schema.Calls()), 2)
+	assert.Equal(t, schema.Calls()[`hll_sketch("name"::string)`] != nil, true)
+	assert.Equal(t, schema.Calls()[`tdigest_sketch(age::integer)`] != nil, true)
+	assert.Equal(t, schema.HasMath(), true)
+	assert.Equal(t, schema.HasNotInSeriesAgg(), true)
+	assert.Equal(t, schema.CanCallsPushdown(), true)
+
+	for i, name := range []string{"hll_count", "tdigest_quantile", "tdigest_quantile"} {
+		assert.Equal(t, schema.Fields()[i].Expr.(*influxql.Call).Name, name)
+	}
+	assert.Equal(t, schema.GetColumnNames(), []string{"approx_count_distinct", "approx_percentile", "approx_median"})
+}
@@ -0,0 +1,446 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/openGemini/openGemini/lib/codec"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+)
+
+// Partial sketches are exchanged between stores and ts-sql as string values.
+// The first byte of a serialized sketch identifies its layout.
+const (
+	sketchHLLDense uint8 = iota + 1
+	sketchHLLSparse
+	sketchTDigest
+)
+
+const (
+	hllPrecision = 12
+	hllRegisters = 1 << hllPrecision
+
+	tDigestCompression = 100
+	tDigestBufferSize  = 500
+)
+
+// HyperLogLog estimates the number of distinct values with a standard error of about 1.6%.
+type HyperLogLog struct {
+	registers []uint8
+}
+
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{registers: make([]uint8, hllRegisters)}
+}
+
+// mix64 is the finalizer of MurmurHash3, it spreads the bits of x over the whole word
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func hashBytes(b []byte) uint64 {
+	// FNV-1a
+	h := uint64(14695981039346656037)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= 1099511628211
+	}
+	return mix64(h)
+}
+
+func (h *HyperLogLog) addHash(x uint64) {
+	idx := x >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *HyperLogLog) AddFloat(v float64) {
+	h.addHash(mix64(math.Float64bits(v)))
+}
+
+func (h *HyperLogLog) AddInteger(v int64) {
+	h.addHash(mix64(uint64(v)))
+}
+
+func (h *HyperLogLog) AddString(v string) {
+	h.addHash(hashBytes(record.Str2bytes(v)))
+}
+
+func (h *HyperLogLog) AddBoolean(v bool) {
+	if v {
+		h.addHash(mix64(1))
+	} else {
+		h.addHash(mix64(0))
+	}
+}
+
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+}
+
+func (h *HyperLogLog) Count() int64 {
+	sum, zeros := 0.0, 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	m := float64(hllRegisters)
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int64(estimate + 0.5)
+}
+
+func (h *HyperLogLog) Reset() {
+	for i := range h.registers {
+		h.registers[i] = 0
+	}
+}
+
+// Marshal uses a sparse layout of (index, rank) pairs while it is smaller than the registers
+func (h *HyperLogLog) Marshal(dst []byte) []byte {
+	n := 0
+	for _, r := range h.registers {
+		if r > 0 {
+			n++
+		}
+	}
+
+	if n*3 >= hllRegisters {
+		dst = append(dst, sketchHLLDense)
+		return append(dst, h.registers...)
+	}
+
+	dst = append(dst, sketchHLLSparse)
+	for i, r := range h.registers {
+		if r > 0 {
+			dst = codec.AppendUint16(dst, uint16(i))
+			dst = append(dst, r)
+		}
+	}
+	return dst
+}
+
+func (h *HyperLogLog) Unmarshal(buf []byte) error {
+	h.Reset()
+	return h.unmarshalMerge(buf)
+}
+
+// unmarshalMerge merges the serialized sketch into h without decoding it into a new HyperLogLog
+func (h *HyperLogLog) unmarshalMerge(buf []byte) error {
+	if len(buf) == 0 {
+		return errno.NewError(errno.ShortBufferSize, 1, 0)
+	}
+
+	switch buf[0] {
+	case sketchHLLDense:
+		if len(buf) != hllRegisters+1 {
+			return errno.NewError(errno.ShortBufferSize, hllRegisters+1, len(buf))
+		}
+		for i, r := range buf[1:] {
+			if r > h.registers[i] {
+				h.registers[i] = r
+			}
+		}
+	case sketchHLLSparse:
+		buf = buf[1:]
+		if len(buf)%3 != 0 {
+			return errno.NewError(errno.ShortBufferSize, len(buf)/3*3+3, len(buf))
+		}
+		for ; len(buf) > 0; buf = buf[3:] {
+			idx := binary.BigEndian.Uint16(buf)
+			if idx >= hllRegisters {
+				return errno.NewError(errno.InvalidSketch, "hll")
+			}
+			if buf[2] > h.registers[idx] {
+				h.registers[idx] = buf[2]
+			}
+		}
+	default:
+		return errno.NewError(errno.InvalidSketch, "hll")
+	}
+	return nil
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+type centroids []centroid
+
+func (c centroids) Len() int           { return len(c) }
+func (c centroids) Less(i, j int) bool { return c[i].mean < c[j].mean }
+func (c centroids) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// TDigest is a merging t-digest which estimates quantiles with a small relative
+// error at the tails and keeps at most a few hundred centroids.
+type TDigest struct {
+	centroids centroids
+	buffer    centroids
+	count     float64
+	min       float64
+	max       float64
+}
+
+func NewTDigest() *TDigest {
+	return &TDigest{
+		min: math.Inf(1),
+		max: math.Inf(-1),
+	}
+}
+
+func (t *TDigest) add(mean, weight float64) {
+	t.buffer = append(t.buffer, centroid{mean: mean, weight: weight})
+	t.count += weight
+	if mean < t.min {
+		t.min = mean
+	}
+	if mean > t.max {
+		t.max = mean
+	}
+	if len(t.buffer) >= tDigestBufferSize {
+		t.compress()
+	}
+}
+
+func (t *TDigest) Add(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+	t.add(v, 1)
+}
+
+func (t *TDigest) Merge(other *TDigest) {
+	other.compress()
+	for _, c := range other.centroids {
+		t.add(c.mean, c.weight)
+	}
+	if other.min < t.min {
+		t.min = other.min
+	}
+	if other.max > t.max {
+		t.max = other.max
+	}
+}
+
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	all := append(t.centroids, t.buffer...)
+	sort.Sort(all)
+	t.buffer = t.buffer[:0]
+
+	merged := all[:1]
+	cumulative := 0.0
+	for _, c := range all[1:] {
+		last := &merged[len(merged)-1]
+		weight := last.weight + c.weight
+		q := (cumulative + weight/2) / t.count
+		if weight <= 4*t.count*q*(1-q)/tDigestCompression {
+			last.mean += (c.mean - last.mean) * c.weight / weight
+			last.weight = weight
+			continue
+		}
+		cumulative += last.weight
+		merged = append(merged, c)
+	}
+	t.centroids = merged
+}
+
+// Quantile returns the estimated value at q (0 <= q <= 1)
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return t.min
+	}
+	if q >= 1 {
+		return t.max
+	}
+
+	target := q * t.count
+	first := t.centroids[0]
+	if target < first.weight/2 {
+		return interpolate(t.min, first.mean, target/(first.weight/2))
+	}
+
+	cumulative := 0.0
+	for i := 0; i < len(t.centroids)-1; i++ {
+		curr, next := t.centroids[i], t.centroids[i+1]
+		left := cumulative + curr.weight/2
+		right := cumulative + curr.weight + next.weight/2
+		if target < right {
+			return interpolate(curr.mean, next.mean, (target-left)/(right-left))
+		}
+		cumulative += curr.weight
+	}
+
+	last := t.centroids[len(t.centroids)-1]
+	left := t.count - last.weight/2
+	return interpolate(last.mean, t.max, (target-left)/(last.weight/2))
+}
+
+func interpolate(lo, hi, f float64) float64 {
+	if f <= 0 {
+		return lo
+	}
+	if f >= 1 {
+		return hi
+	}
+	return lo + (hi-lo)*f
+}
+
+func (t *TDigest) Reset() {
+	t.centroids = t.centroids[:0]
+	t.buffer = t.buffer[:0]
+	t.count = 0
+	t.min = math.Inf(1)
+	t.max = math.Inf(-1)
+}
+
+func (t *TDigest) Marshal(dst []byte) []byte {
+	t.compress()
+	dst = append(dst, sketchTDigest)
+	dst = codec.AppendFloat64(dst, t.min)
+	dst = codec.AppendFloat64(dst, t.max)
+	dst = codec.AppendUint32(dst, uint32(len(t.centroids)))
+	for _, c := range t.centroids {
+		dst = codec.AppendFloat64(dst, c.mean)
+		dst = codec.AppendFloat64(dst, c.weight)
+	}
+	return dst
+}
+
+func (t *TDigest) Unmarshal(buf []byte) error {
+	t.Reset()
+	return t.unmarshalMerge(buf)
+}
+
+func (t *TDigest) unmarshalMerge(buf []byte) error {
+	const headerSize = 1 + 8 + 8 + 4
+	if len(buf) < headerSize {
+		return errno.NewError(errno.ShortBufferSize, headerSize, len(buf))
+	}
+	if buf[0] != sketchTDigest {
+		return errno.NewError(errno.InvalidSketch, "tdigest")
+	}
+
+	min := math.Float64frombits(binary.BigEndian.Uint64(buf[1:]))
+	max := math.Float64frombits(binary.BigEndian.Uint64(buf[9:]))
+	n := int(binary.BigEndian.Uint32(buf[17:]))
+	buf = buf[headerSize:]
+	if len(buf) != n*16 {
+		return errno.NewError(errno.ShortBufferSize, n*16, len(buf))
+	}
+
+	for i := 0; i < n; i++ {
+		mean := math.Float64frombits(binary.BigEndian.Uint64(buf[i*16:]))
+		weight := math.Float64frombits(binary.BigEndian.Uint64(buf[i*16+8:]))
+		t.add(mean, weight)
+	}
+	if n > 0 {
+		t.min = math.Min(t.min, min)
+		t.max = math.Max(t.max, max)
+	}
+	return nil
+}
+
+// SketchValuer reads the final result out of a merged serialized sketch.
+type SketchValuer struct{}
+
+var _ influxql.CallValuer = SketchValuer{}
+
+func (SketchValuer) Value(_ string) (interface{}, bool) {
+	return nil, false
+}
+
+func (SketchValuer) SetValuer(_ influxql.Valuer, _ int) {
+
+}
+
+func (v SketchValuer) Call(name string, args []interface{}) (interface{}, bool) {
+	switch name {
+	case "hll_count":
+		if len(args) != 1 {
+			return nil, false
+		}
+		arg0, ok := args[0].(string)
+		if !ok {
+			return nil, true
+		}
+		h := NewHyperLogLog()
+		if err := h.Unmarshal(record.Str2bytes(arg0)); err != nil {
+			return nil, true
+		}
+		return h.Count(), true
+	case "tdigest_quantile":
+		if len(args) != 2 {
+			return nil, false
+		}
+		arg0, ok := args[0].(string)
+		if !ok {
+			return nil, true
+		}
+		var percentile float64
+		switch arg1 := args[1].(type) {
+		case float64:
+			percentile = arg1
+		case int64:
+			percentile = float64(arg1)
+		default:
+			return nil, true
+		}
+		t := NewTDigest()
+		if err := t.Unmarshal(record.Str2bytes(arg0)); err != nil || t.Count() == 0 {
+			return nil, true
+		}
+		return t.Quantile(percentile / 100), true
+	default:
+		return nil, false
+	}
+}
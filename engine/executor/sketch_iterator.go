@@ -0,0 +1,222 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+)
+
+// approximate aggregates are planned as a partial sketch call, which is computed
+// next to the data and merged by the aggregates above the exchange, and a function
+// projecting the merged sketch into the result. See QuerySchema.rewriteApproxCall.
+var sketchMergeCall = map[string]string{
+	"hll_sketch":     "hll_merge",
+	"tdigest_sketch": "tdigest_merge",
+}
+
+type sketch interface {
+	appendValues(c Column, dataType influxql.DataType, start, end int)
+	mergeValues(c Column, start, end int) error
+	marshal(dst []byte) []byte
+	isEmpty() bool
+	reset()
+}
+
+type hllSketch struct {
+	HyperLogLog
+	empty bool
+}
+
+func newHLLSketch() *hllSketch {
+	return &hllSketch{HyperLogLog: *NewHyperLogLog(), empty: true}
+}
+
+func (s *hllSketch) appendValues(c Column, dataType influxql.DataType, start, end int) {
+	if start == end {
+		return
+	}
+	s.empty = false
+	switch dataType {
+	case influxql.Float:
+		for _, v := range c.FloatValues()[start:end] {
+			s.AddFloat(v)
+		}
+	case influxql.Integer:
+		for _, v := range c.IntegerValues()[start:end] {
+			s.AddInteger(v)
+		}
+	case influxql.Boolean:
+		for _, v := range c.BooleanValues()[start:end] {
+			s.AddBoolean(v)
+		}
+	case influxql.String, influxql.Tag:
+		for i := start; i < end; i++ {
+			s.AddString(c.StringValue(i))
+		}
+	}
+}
+
+func (s *hllSketch) mergeValues(c Column, start, end int) error {
+	for i := start; i < end; i++ {
+		if err := s.unmarshalMerge(record.Str2bytes(c.StringValue(i))); err != nil {
+			return err
+		}
+		s.empty = false
+	}
+	return nil
+}
+
+func (s *hllSketch) marshal(dst []byte) []byte {
+	return s.Marshal(dst)
+}
+
+func (s *hllSketch) isEmpty() bool {
+	return s.empty
+}
+
+func (s *hllSketch) reset() {
+	s.Reset()
+	s.empty = true
+}
+
+type tDigestSketch struct {
+	TDigest
+}
+
+func newTDigestSketch() *tDigestSketch {
+	return &tDigestSketch{TDigest: *NewTDigest()}
+}
+
+func (s *tDigestSketch) appendValues(c Column, dataType influxql.DataType, start, end int) {
+	switch dataType {
+	case influxql.Float:
+		for _, v := range c.FloatValues()[start:end] {
+			s.Add(v)
+		}
+	case influxql.Integer:
+		for _, v := range c.IntegerValues()[start:end] {
+			s.Add(float64(v))
+		}
+	}
+}
+
+func (s *tDigestSketch) mergeValues(c Column, start, end int) error {
+	for i := start; i < end; i++ {
+		if err := s.unmarshalMerge(record.Str2bytes(c.StringValue(i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *tDigestSketch) marshal(dst []byte) []byte {
+	return s.Marshal(dst)
+}
+
+func (s *tDigestSketch) isEmpty() bool {
+	return s.Count() == 0
+}
+
+func (s *tDigestSketch) reset() {
+	s.Reset()
+}
+
+// SketchIterator folds the values of every window into a sketch and outputs it serialized
+// as a string. With merge set, the input column holds serialized sketches instead of raw values.
+type SketchIterator struct {
+	isSingleCall bool
+	merge        bool
+	dataType     influxql.DataType
+	inOrdinal    int
+	outOrdinal   int
+	time         int64
+	sketch       sketch
+	buf          []byte
+	err          error
+}
+
+func NewSketchIterator(s sketch, merge, isSingleCall bool, dataType influxql.DataType, inOrdinal, outOrdinal int) *SketchIterator {
+	return &SketchIterator{
+		isSingleCall: isSingleCall,
+		merge:        merge,
+		dataType:     dataType,
+		inOrdinal:    inOrdinal,
+		outOrdinal:   outOrdinal,
+		sketch:       s,
+	}
+}
+
+func (r *SketchIterator) appendWindow(inChunk Chunk, start, end int) {
+	if r.sketch.isEmpty() && start < end {
+		r.time = inChunk.TimeByIndex(start)
+	}
+
+	column := inChunk.Column(r.inOrdinal)
+	start, end = column.GetRangeValueIndexV2(start, end)
+	if !r.merge {
+		r.sketch.appendValues(column, r.dataType, start, end)
+		return
+	}
+	if err := r.sketch.mergeValues(column, start, end); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *SketchIterator) flushWindow(outChunk Chunk) {
+	if r.sketch.isEmpty() {
+		if !r.isSingleCall {
+			outChunk.Column(r.outOrdinal).AppendNilsV2(false)
+		}
+		return
+	}
+
+	if r.isSingleCall {
+		outChunk.AppendTime(r.time)
+		outChunk.AppendIntervalIndex(outChunk.Len() - 1)
+	}
+	r.buf = r.sketch.marshal(r.buf[:0])
+	outChunk.Column(r.outOrdinal).AppendStringBytes(r.buf, []uint32{0})
+	outChunk.Column(r.outOrdinal).AppendNilsV2(true)
+	r.sketch.reset()
+}
+
+func (r *SketchIterator) Next(ie *IteratorEndpoint, p *IteratorParams) {
+	inChunk, outChunk := ie.InputPoint.Chunk, ie.OutputPoint.Chunk
+
+	var end int
+	lastIndex := len(inChunk.IntervalIndex()) - 1
+	for i, start := range inChunk.IntervalIndex() {
+		if i < lastIndex {
+			end = inChunk.IntervalIndex()[i+1]
+		} else {
+			end = inChunk.NumberOfRows()
+		}
+		r.appendWindow(inChunk, start, end)
+
+		// the last window may continue in the next chunk
+		if i == lastIndex && p.sameInterval {
+			continue
+		}
+		r.flushWindow(outChunk)
+	}
+
+	if r.err != nil {
+		p.err = r.err
+		r.err = nil
+	}
+}
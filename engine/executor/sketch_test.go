@@ -0,0 +1,270 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/testing/assert"
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+)
+
+func TestHyperLogLogCount(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		h := executor.NewHyperLogLog()
+		for i := 0; i < n; i++ {
+			h.AddInteger(int64(i))
+			h.AddInteger(int64(i))
+		}
+		got := float64(h.Count())
+		if math.Abs(got-float64(n))/float64(n) > 0.05 {
+			t.Fatalf("approx count of %d distinct values is %v", n, got)
+		}
+	}
+}
+
+func TestHyperLogLogMarshalMerge(t *testing.T) {
+	for _, n := range []int{100, 10000} {
+		h1, h2, all := executor.NewHyperLogLog(), executor.NewHyperLogLog(), executor.NewHyperLogLog()
+		for i := 0; i < n; i++ {
+			s := "host_" + strconv.Itoa(i)
+			all.AddString(s)
+			if i%2 == 0 {
+				h1.AddString(s)
+			} else {
+				h2.AddString(s)
+			}
+		}
+
+		decoded := executor.NewHyperLogLog()
+		if err := decoded.Unmarshal(h1.Marshal(nil)); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, decoded.Count(), h1.Count())
+
+		decoded.Merge(h2)
+		assert.Equal(t, decoded.Count(), all.Count())
+	}
+
+	h := executor.NewHyperLogLog()
+	assert.Equal(t, h.Unmarshal([]byte{1, 2, 3}) != nil, true)
+	assert.Equal(t, h.Unmarshal(nil) != nil, true)
+}
+
+func TestTDigestQuantile(t *testing.T) {
+	td := executor.NewTDigest()
+	for i := 1; i <= 10000; i++ {
+		td.Add(float64(i))
+	}
+	assert.Equal(t, td.Count(), float64(10000))
+	assert.Equal(t, td.Quantile(0), float64(1))
+	assert.Equal(t, td.Quantile(1), float64(10000))
+	for _, q := range []float64{0.01, 0.25, 0.5, 0.75, 0.99} {
+		got := td.Quantile(q)
+		if math.Abs(got-q*10000) > 100 {
+			t.Fatalf("quantile %v is %v", q, got)
+		}
+	}
+	assert.Equal(t, math.IsNaN(executor.NewTDigest().Quantile(0.5)), true)
+}
+
+func TestTDigestMarshalMerge(t *testing.T) {
+	td1, td2 := executor.NewTDigest(), executor.NewTDigest()
+	for i := 0; i < 5000; i++ {
+		td1.Add(float64(i))
+		td2.Add(float64(i + 5000))
+	}
+
+	decoded := executor.NewTDigest()
+	if err := decoded.Unmarshal(td1.Marshal(nil)); err != nil {
+		t.Fatal(err)
+	}
+	decoded.Merge(td2)
+	assert.Equal(t, decoded.Count(), float64(10000))
+	if got := decoded.Quantile(0.5); math.Abs(got-5000) > 100 {
+		t.Fatalf("median of merged digest is %v", got)
+	}
+
+	assert.Equal(t, decoded.Unmarshal([]byte{3, 0}) != nil, true)
+}
+
+func TestSketchValuer(t *testing.T) {
+	valuer := executor.SketchValuer{}
+
+	h := executor.NewHyperLogLog()
+	h.AddFloat(1.5)
+	h.AddFloat(2.5)
+	out, ok := valuer.Call("hll_count", []interface{}{string(h.Marshal(nil))})
+	assert.Equal(t, ok, true)
+	assert.Equal(t, out, int64(2))
+
+	td := executor.NewTDigest()
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		td.Add(v)
+	}
+	out, ok = valuer.Call("tdigest_quantile", []interface{}{string(td.Marshal(nil)), int64(50)})
+	assert.Equal(t, ok, true)
+	assert.Equal(t, out, float64(3))
+
+	out, ok = valuer.Call("tdigest_quantile", []interface{}{nil, int64(50)})
+	assert.Equal(t, ok, true)
+	assert.Equal(t, out, nil)
+
+	_, ok = valuer.Call("abs", []interface{}{float64(1)})
+	assert.Equal(t, ok, false)
+}
+
+func buildDstRowDataTypeSketch() hybridqp.RowDataType {
+	rowDataType := hybridqp.NewRowDataTypeImpl(
+		influxql.VarRef{Val: "hll_sketch(\"value1\")", Type: influxql.String},
+		influxql.VarRef{Val: "tdigest_sketch(\"value2\")", Type: influxql.String},
+	)
+
+	return rowDataType
+}
+
+func buildDstChunkSketch() []executor.Chunk {
+	rowDataType := buildDstRowDataTypeSketch()
+	dstChunks := make([]executor.Chunk, 0, 1)
+
+	b := executor.NewChunkBuilder(rowDataType)
+
+	chunk := b.NewChunk("mst")
+
+	chunk.AppendTagsAndIndexes([]executor.ChunkTags{
+		*ParseChunkTags("name=aaa"), *ParseChunkTags("name=bbb"),
+		*ParseChunkTags("name=ccc")}, []int{0, 1, 2})
+	chunk.AppendIntervalIndex([]int{0, 1, 2}...)
+	chunk.AppendTime([]int64{1, 6, 8}...)
+
+	for _, values := range [][]int64{{3, 1}, {4, 4, 3}, {8, 9, 10}} {
+		h := executor.NewHyperLogLog()
+		for _, v := range values {
+			h.AddInteger(v)
+		}
+		chunk.Column(0).AppendStringValues(string(h.Marshal(nil)))
+	}
+	chunk.Column(0).AppendManyNotNil(3)
+
+	for _, values := range [][]float64{{3.3, 1.1}, {4.4, 4.4, 3.3}, {8.8, 9.9, 10.1}} {
+		td := executor.NewTDigest()
+		for _, v := range values {
+			td.Add(v)
+		}
+		chunk.Column(1).AppendStringValues(string(td.Marshal(nil)))
+	}
+	chunk.Column(1).AppendManyNotNil(3)
+
+	dstChunks = append(dstChunks, chunk)
+
+	return dstChunks
+}
+
+func TestStreamAggregateTransformSketch(t *testing.T) {
+	inChunks := buildMedianInChunk()
+	dstChunks := buildDstChunkSketch()
+
+	exprOpt := []hybridqp.ExprOptions{
+		{
+			Expr: &influxql.Call{Name: "hll_sketch", Args: []influxql.Expr{hybridqp.MustParseExpr("value1")}},
+			Ref:  influxql.VarRef{Val: `hll_sketch("value1")`, Type: influxql.String},
+		},
+		{
+			Expr: &influxql.Call{Name: "tdigest_sketch", Args: []influxql.Expr{hybridqp.MustParseExpr("value2")}},
+			Ref:  influxql.VarRef{Val: `tdigest_sketch("value2")`, Type: influxql.String},
+		},
+	}
+
+	opt := query.ProcessorOptions{
+		Exprs:      []influxql.Expr{hybridqp.MustParseExpr(`hll_sketch("value1")`), hybridqp.MustParseExpr(`tdigest_sketch("value2")`)},
+		Dimensions: []string{"name"},
+		Interval:   hybridqp.Interval{Duration: 4 * time.Nanosecond},
+		Ordered:    true,
+		Ascending:  true,
+		ChunkSize:  10,
+	}
+
+	testStreamAggregateTransformBase(
+		t,
+		inChunks, dstChunks,
+		buildSourceRowDataType(), buildDstRowDataTypeSketch(),
+		exprOpt, opt,
+	)
+}
+
+func marshalHLL(values ...int64) string {
+	h := executor.NewHyperLogLog()
+	for _, v := range values {
+		h.AddInteger(v)
+	}
+	return string(h.Marshal(nil))
+}
+
+func TestStreamAggregateTransformSketchMerge(t *testing.T) {
+	inRowDataType := hybridqp.NewRowDataTypeImpl(influxql.VarRef{Val: "sketch", Type: influxql.String})
+	outRowDataType := hybridqp.NewRowDataTypeImpl(influxql.VarRef{Val: `hll_merge("sketch")`, Type: influxql.String})
+
+	b := executor.NewChunkBuilder(inRowDataType)
+	inCk1 := b.NewChunk("mst")
+	inCk1.AppendTagsAndIndexes([]executor.ChunkTags{*ParseChunkTags("name=aaa")}, []int{0})
+	inCk1.AppendIntervalIndex(0, 2)
+	inCk1.AppendTime(1, 2, 5)
+	inCk1.Column(0).AppendStringValues(marshalHLL(1, 2), marshalHLL(2, 3), marshalHLL(4))
+	inCk1.Column(0).AppendManyNotNil(3)
+
+	inCk2 := b.NewChunk("mst")
+	inCk2.AppendTagsAndIndexes([]executor.ChunkTags{*ParseChunkTags("name=aaa")}, []int{0})
+	inCk2.AppendIntervalIndex(0, 1)
+	inCk2.AppendTime(6, 9)
+	inCk2.Column(0).AppendStringValues(marshalHLL(4, 5))
+	inCk2.Column(0).AppendNilsV2(true, false)
+
+	dst := executor.NewChunkBuilder(outRowDataType).NewChunk("mst")
+	dst.AppendTagsAndIndexes([]executor.ChunkTags{*ParseChunkTags("name=aaa")}, []int{0})
+	dst.AppendIntervalIndex(0, 1)
+	dst.AppendTime(1, 5)
+	dst.Column(0).AppendStringValues(marshalHLL(1, 2, 3), marshalHLL(4, 5))
+	dst.Column(0).AppendManyNotNil(2)
+
+	exprOpt := []hybridqp.ExprOptions{
+		{
+			Expr: &influxql.Call{Name: "hll_merge", Args: []influxql.Expr{hybridqp.MustParseExpr("sketch")}},
+			Ref:  influxql.VarRef{Val: `hll_merge("sketch")`, Type: influxql.String},
+		},
+	}
+	opt := query.ProcessorOptions{
+		Exprs:      []influxql.Expr{hybridqp.MustParseExpr(`hll_merge("sketch")`)},
+		Dimensions: []string{"name"},
+		Interval:   hybridqp.Interval{Duration: 4 * time.Nanosecond},
+		Ordered:    true,
+		Ascending:  true,
+		ChunkSize:  10,
+	}
+
+	testStreamAggregateTransformBase(
+		t,
+		[]executor.Chunk{inCk1, inCk2}, []executor.Chunk{dst},
+		inRowDataType, outRowDataType,
+		exprOpt, opt,
+	)
+}
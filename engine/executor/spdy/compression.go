@@ -0,0 +1,142 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spdy
+
+import (
+	"encoding/binary"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/pierrec/lz4/v4"
+)
+
+const (
+	CompressNone uint8 = iota
+	CompressSnappy
+	CompressZstd
+	CompressLz4
+	CompressEnd
+)
+
+// frames with a smaller payload are always sent uncompressed
+const compressMinSize = 1024
+
+// maxLz4BlockSize bounds the buffer allocated for the size prefixed to an lz4 block
+const maxLz4BlockSize = 1 << 30
+
+var compressNames = [CompressEnd]string{
+	CompressNone:   config.SpdyCompressionNone,
+	CompressSnappy: config.SpdyCompressionSnappy,
+	CompressZstd:   config.SpdyCompressionZstd,
+	CompressLz4:    config.SpdyCompressionLz4,
+}
+
+// both are safe for concurrent use through EncodeAll and DecodeAll
+var zstdEncoder, _ = zstd.NewWriter(nil,
+	zstd.WithEncoderCRC(false),
+	zstd.WithEncoderLevel(zstd.SpeedFastest))
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+func CompressionType(name string) uint8 {
+	for i := range compressNames {
+		if compressNames[i] == name {
+			return uint8(i)
+		}
+	}
+	return CompressNone
+}
+
+func CompressionName(typ uint8) string {
+	if typ >= CompressEnd {
+		return ""
+	}
+	return compressNames[typ]
+}
+
+// compressBlock appends the compressed src to dst
+func compressBlock(typ uint8, dst []byte, src []byte) ([]byte, error) {
+	switch typ {
+	case CompressSnappy:
+		n := len(dst)
+		dst = growBuffer(dst, snappy.MaxEncodedLen(len(src)))
+		enc := snappy.Encode(dst[n:cap(dst)], src)
+		return dst[:n+len(enc)], nil
+	case CompressZstd:
+		return zstdEncoder.EncodeAll(src, dst), nil
+	case CompressLz4:
+		// an lz4 block does not hold its decompressed size, it is prefixed by it
+		var size [binary.MaxVarintLen64]byte
+		dst = append(dst, size[:binary.PutUvarint(size[:], uint64(len(src)))]...)
+		n := len(dst)
+		dst = growBuffer(dst, lz4.CompressBlockBound(len(src)))
+		c, err := lz4.CompressBlock(src, dst[n:cap(dst)], nil)
+		if err != nil {
+			return nil, err
+		}
+		if c == 0 {
+			return nil, errno.NewError(errno.UnknownCompression, typ)
+		}
+		return dst[:n+c], nil
+	default:
+		return nil, errno.NewError(errno.UnknownCompression, typ)
+	}
+}
+
+// decompressBlock appends the decompressed src to dst
+func decompressBlock(typ uint8, dst []byte, src []byte) ([]byte, error) {
+	switch typ {
+	case CompressSnappy:
+		size, err := snappy.DecodedLen(src)
+		if err != nil {
+			return nil, err
+		}
+		n := len(dst)
+		dst = growBuffer(dst, size)
+		dec, err := snappy.Decode(dst[n:n+size], src)
+		if err != nil {
+			return nil, err
+		}
+		return dst[:n+len(dec)], nil
+	case CompressZstd:
+		return zstdDecoder.DecodeAll(src, dst)
+	case CompressLz4:
+		size, l := binary.Uvarint(src)
+		if l <= 0 || size > maxLz4BlockSize {
+			return nil, errno.NewError(errno.UnknownCompression, typ)
+		}
+		n := len(dst)
+		dst = growBuffer(dst, int(size))
+		d, err := lz4.UncompressBlock(src[l:], dst[n:n+int(size)])
+		if err != nil {
+			return nil, err
+		}
+		return dst[:n+d], nil
+	default:
+		return nil, errno.NewError(errno.UnknownCompression, typ)
+	}
+}
+
+func growBuffer(b []byte, size int) []byte {
+	if cap(b)-len(b) >= size {
+		return b
+	}
+	buf := make([]byte, len(b), len(b)+size)
+	copy(buf, b)
+	return buf
+}
@@ -64,6 +64,19 @@ const (
 	FIN_FLAG
 	RST_FLAG
 	DATA_ACK_FLAG
+	COMPRESS_FLAG
+	// sent with every SYN and ACK by the nodes at SessionVersion 1 or later
+	VERSION_FLAG
+)
+
+// SessionVersion is the version of the requests and responses a node understands. A session
+// opened with a node of the older versions, which do not send VERSION_FLAG, has the peer version 0.
+const SessionVersion uint8 = 1
+
+// the compression negotiated by a SYN or an ACK with COMPRESS_FLAG is in the high byte of the flags
+const (
+	compressTypeShift = 8
+	compressTypeMask  = 0xFF
 )
 
 type header []byte
@@ -20,11 +20,13 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"net"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/errno"
 )
 
@@ -426,6 +428,124 @@ func TestHandleUnsupportedFlags(t *testing.T) {
 	assertError(t, err, fmt.Errorf("handle data with unsupported flags(%d)", flags))
 }
 
+// TestCompressionWithOldPeer opens a session requesting compression to a peer which does not
+// know it: it reads the SYN as a plain one and replies a plain ACK
+func TestCompressionWithOldPeer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.2:38081")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	peerErr := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			peerErr <- err
+			return
+		}
+		defer c.Close()
+
+		syn := make(header, HEADER_SIZE)
+		if _, err = io.ReadFull(c, syn); err != nil {
+			peerErr <- err
+			return
+		}
+		if !Flags(syn.Flags()).has(SYN_FLAG) || syn.Length() != 0 {
+			peerErr <- fmt.Errorf("unexpected SYN flags %d with %d bytes", syn.Flags(), syn.Length())
+			return
+		}
+		ack := make(header, HEADER_SIZE)
+		ack.encode(DATA_TYPE, ACK_FLAG, syn.ConnID(), 0)
+		_, err = c.Write(ack)
+		peerErr <- err
+		<-stop
+	}()
+
+	cfg := DefaultConfiguration()
+	cfg.Compression = config.SpdyCompressionZstd
+	uconn, err := net.Dial("tcp", "127.0.0.2:38081")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := NewMultiplexedConnection(cfg, uconn, true)
+	go func() {
+		HandleError(conn.ListenAndServed())
+	}()
+	defer func() {
+		HandleError(conn.Close())
+	}()
+
+	session, err := conn.OpenSession()()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-peerErr; err != nil {
+		t.Fatal(err)
+	}
+	if session.Compression() != CompressNone {
+		t.Fatalf("expect uncompressed frames with an old peer, but %d", session.Compression())
+	}
+	if session.PeerVersion() != 0 {
+		t.Fatalf("expect the version of an old peer is 0, but %d", session.PeerVersion())
+	}
+}
+
+// TestCompressionAccepted checks the compression is negotiated in the flags, without a payload
+func TestCompressionAccepted(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.2:38082")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan *MultiplexedSession, 1)
+	go func() {
+		uconn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		conn := NewMultiplexedConnection(DefaultConfiguration(), uconn, false)
+		go func() {
+			HandleError(conn.ListenAndServed())
+		}()
+		session, _ := conn.AcceptSession()()
+		accepted <- session
+	}()
+
+	c, err := net.Dial("tcp", "127.0.0.2:38082")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for _, typ := range []uint8{CompressLz4, CompressEnd} {
+		syn := make(header, HEADER_SIZE)
+		syn.encode(DATA_TYPE, SYN_FLAG|VERSION_FLAG|compressFlags([]byte{typ}), uint64(typ), 0)
+		if _, err = c.Write(syn); err != nil {
+			t.Fatal(err)
+		}
+		ack := make(header, HEADER_SIZE)
+		if _, err = io.ReadFull(c, ack); err != nil {
+			t.Fatal(err)
+		}
+		// unknown algorithms fall back to uncompressed frames
+		exp := typ
+		if typ >= CompressEnd {
+			exp = CompressNone
+		}
+		if ack.Flags() != ACK_FLAG|VERSION_FLAG|compressFlags([]byte{exp}) || ack.Length() != 0 {
+			t.Fatalf("unexpected ACK flags %d with %d bytes", ack.Flags(), ack.Length())
+		}
+	}
+
+	if session := <-accepted; session == nil || session.PeerVersion() != SessionVersion {
+		t.Fatalf("expect the peer version %d of the accepted session", SessionVersion)
+	}
+}
+
 func assertError(t *testing.T, got error, exp error) {
 	if got == nil && exp == nil {
 		return
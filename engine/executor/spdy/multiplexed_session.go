@@ -24,6 +24,7 @@ import (
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 	"go.uber.org/zap"
 )
 
@@ -63,6 +64,12 @@ type MultiplexedSession struct {
 	ackRecvSig    chan struct{}
 	selectTimeout time.Duration
 
+	// compression of response frames, negotiated by the SYN/ACK handshake
+	compression   uint8
+	peerVersion   uint8
+	rawJob        *statistics.SpdyJob
+	compressedJob *statistics.SpdyJob
+
 	closed    chan struct{}
 	closeOnce sync.Once
 	onClose   []func()
@@ -224,7 +231,12 @@ func (s *MultiplexedSession) handle(flags Flags, data []byte) error {
 		if err := s.RecvSyn(); err != nil {
 			return err
 		}
-		if err := s.SendAck(nil); err != nil {
+		s.acceptVersion(flags)
+		var ack []byte
+		if flags.has(COMPRESS_FLAG) {
+			ack = s.acceptCompression(flags)
+		}
+		if err := s.SendAck(ack); err != nil {
 			return err
 		}
 		if err := s.RecvData(data); err != nil {
@@ -234,6 +246,11 @@ func (s *MultiplexedSession) handle(flags Flags, data []byte) error {
 	}
 
 	if flags.has(ACK_FLAG) {
+		// must be accepted before RecvAck wakes up the session opener
+		s.acceptVersion(flags)
+		if flags.has(COMPRESS_FLAG) {
+			s.acceptCompression(flags)
+		}
 		if err := s.RecvAck(); err != nil {
 			return err
 		}
@@ -294,8 +311,9 @@ func (s *MultiplexedSession) SendSyn(data []byte, ackRecvSig chan struct{}) erro
 
 func (s *MultiplexedSession) sendSyn(event event, transition *FSMTransition, data []byte) error {
 	var flags uint16
-	flags |= SYN_FLAG
-	if err := s.sendDataInternal(flags, data); err != nil {
+	flags |= SYN_FLAG | VERSION_FLAG
+	flags |= compressFlags(data)
+	if err := s.sendDataInternal(flags, nil); err != nil {
 		return err
 	}
 	return nil
@@ -321,8 +339,9 @@ func (s *MultiplexedSession) SendAck(data []byte) error {
 
 func (s *MultiplexedSession) sendAck(event event, transition *FSMTransition, data []byte) error {
 	var flags uint16
-	flags |= ACK_FLAG
-	if err := s.sendDataInternal(flags, data); err != nil {
+	flags |= ACK_FLAG | VERSION_FLAG
+	flags |= compressFlags(data)
+	if err := s.sendDataInternal(flags, nil); err != nil {
 		return err
 	}
 	return nil
@@ -461,12 +480,92 @@ func (s *MultiplexedSession) enterClosed(event event, transition *FSMTransition)
 }
 
 func (s *MultiplexedSession) Open(ackSig chan struct{}) error {
-	if err := s.SendSyn(nil, ackSig); err != nil {
+	var data []byte
+	if typ := CompressionType(s.cfg.Compression); typ != CompressNone {
+		data = []byte{typ}
+	}
+	if err := s.SendSyn(data, ackSig); err != nil {
 		return err
 	}
 	return nil
 }
 
+// compressFlags returns the flags of a SYN or an ACK carrying the compression in data, if any.
+// The compression is negotiated in the flags instead of the payload, the nodes of the older
+// versions ignore the unknown flags of a SYN, reply with a plain ACK and get uncompressed frames.
+func compressFlags(data []byte) uint16 {
+	if len(data) != 1 {
+		return 0
+	}
+	return COMPRESS_FLAG | uint16(data[0])<<compressTypeShift
+}
+
+// acceptCompression picks the compression in the flags of the remote side,
+// falling back to CompressNone for algorithms this node does not know.
+func (s *MultiplexedSession) acceptCompression(flags Flags) []byte {
+	typ := uint8(uint16(flags) >> compressTypeShift & compressTypeMask)
+	if typ >= CompressEnd {
+		typ = CompressNone
+	}
+	s.compression = typ
+	return []byte{typ}
+}
+
+func (s *MultiplexedSession) Compression() uint8 {
+	return s.compression
+}
+
+func (s *MultiplexedSession) acceptVersion(flags Flags) {
+	if flags.has(VERSION_FLAG) {
+		s.peerVersion = SessionVersion
+	}
+}
+
+// PeerVersion returns the SessionVersion of the remote side, new responses are only sent
+// to the peers that understand them.
+func (s *MultiplexedSession) PeerVersion() uint8 {
+	return s.peerVersion
+}
+
+func (s *MultiplexedSession) SetStatisticsJob(raw, compressed *statistics.SpdyJob) {
+	s.rawJob = raw
+	s.compressedJob = compressed
+}
+
+// compressFrame compresses the payload of a frame which starts with a ProtocolHeader.
+// The frame is returned unchanged if compression is disabled or does not pay off.
+func (s *MultiplexedSession) compressFrame(buf []byte, flags uint16) ([]byte, uint16) {
+	if s.compression == CompressNone || len(buf)-ProtocolHeaderSize < compressMinSize {
+		return buf, flags
+	}
+
+	dst, err := compressBlock(s.compression, s.conn.AllocData(ProtocolHeaderSize), buf[ProtocolHeaderSize:])
+	if err != nil || len(dst) >= len(buf) {
+		return buf, flags
+	}
+	s.conn.FreeData(buf)
+	return dst, flags | CompressFlag
+}
+
+func (s *MultiplexedSession) decompressFrame(src []byte) ([]byte, error) {
+	if s.compression == CompressNone {
+		return nil, errno.NewError(errno.UnknownCompression, s.compression)
+	}
+
+	dst, err := decompressBlock(s.compression, s.conn.AllocData(0), src)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.rawJob != nil {
+		statistics.NewSpdyStatistics().Add(s.rawJob.Clone().SetValue(int64(len(dst))))
+	}
+	if s.compressedJob != nil {
+		statistics.NewSpdyStatistics().Add(s.compressedJob.Clone().SetValue(int64(len(src))))
+	}
+	return dst, nil
+}
+
 func (s *MultiplexedSession) Close() error {
 	if err := s.SendFin(nil); err != nil {
 		return err
@@ -46,6 +46,9 @@ type MultiplexedSessionPool struct {
 	successJob *statistics.SpdyJob
 	failedJob  *statistics.SpdyJob
 	closeJob   *statistics.SpdyJob
+
+	rawJob        *statistics.SpdyJob
+	compressedJob *statistics.SpdyJob
 }
 
 func NewMultiplexedSessionPool(cfg config.Spdy, network string, address string) *MultiplexedSessionPool {
@@ -71,6 +74,12 @@ func (c *MultiplexedSessionPool) SetStatisticsJob(job *statistics.SpdyJob) {
 
 	c.closeJob = job.Clone()
 	c.closeJob.SetItem(statistics.ClosedSessionTotal)
+
+	c.rawJob = job.Clone()
+	c.rawJob.SetItem(statistics.RawBytesTotal)
+
+	c.compressedJob = job.Clone()
+	c.compressedJob.SetItem(statistics.CompressedBytesTotal)
 }
 
 func (c *MultiplexedSessionPool) Dial() error {
@@ -157,6 +166,7 @@ func (c *MultiplexedSessionPool) create() (*MultiplexedSession, error) {
 		return nil, err
 	}
 
+	session.SetStatisticsJob(c.rawJob, c.compressedJob)
 	session.SetOnClose(func() {
 		statistics.NewSpdyStatistics().Add(c.closeJob)
 	})
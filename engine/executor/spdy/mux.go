@@ -40,6 +40,7 @@ const (
 	ReqFlag uint16 = 1 << iota
 	RspFlag
 	FullFlag
+	CompressFlag
 )
 
 const (
@@ -231,7 +232,8 @@ func (base *BaseResponser) Response(response interface{}, full bool) error {
 		return err
 	}
 
-	ProtocolHeader(buf).encode(base.derive.Type(), base.sendFlags(full), base.sequence, uint32(len(buf)-ProtocolHeaderSize))
+	buf, flags := base.session.compressFrame(buf, base.sendFlags(full))
+	ProtocolHeader(buf).encode(base.derive.Type(), flags, base.sequence, uint32(len(buf)-ProtocolHeaderSize))
 	tracing.AddPP(base.encodeSpan, begin)
 
 	begin = time.Now()
@@ -278,8 +280,7 @@ func (base *BaseResponser) Apply() error {
 		}
 
 		tracing.StartPP(base.decodeSpan)
-		response, err := base.derive.Decode(data[ProtocolHeaderSize:])
-		base.session.conn.FreeData(data)
+		response, err := base.decode(header.Flags(), data)
 		tracing.EndPP(base.decodeSpan)
 
 		if err != nil {
@@ -296,6 +297,21 @@ func (base *BaseResponser) Apply() error {
 	}
 }
 
+func (base *BaseResponser) decode(flags uint16, data []byte) (interface{}, error) {
+	defer base.session.conn.FreeData(data)
+
+	if flags&CompressFlag != CompressFlag {
+		return base.derive.Decode(data[ProtocolHeaderSize:])
+	}
+
+	raw, err := base.session.decompressFrame(data[ProtocolHeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+	defer base.session.conn.FreeData(raw)
+	return base.derive.Decode(raw)
+}
+
 func (base *BaseResponser) Session() *MultiplexedSession {
 	return base.session
 }
@@ -444,6 +444,43 @@ func TestSequenceRequest(t *testing.T) {
 	}
 }
 
+func TestCompressedSequenceRequest(t *testing.T) {
+	cfg := spdy.DefaultConfiguration()
+	defer spdy.SetDefaultConfiguration(cfg)
+
+	for i, name := range []string{config.SpdyCompressionSnappy, config.SpdyCompressionZstd, config.SpdyCompressionLz4} {
+		t.Run(name, func(t *testing.T) {
+			c := cfg
+			c.Compression = name
+			spdy.SetDefaultConfiguration(c)
+
+			address := fmt.Sprintf("127.0.0.8:%d", 18090+i)
+			server, spool, err := StartServer(address)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer func() {
+				spool.Close()
+				server.Stop()
+			}()
+
+			for _, tt := range messages {
+				session, err := spool.Get()
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, spdy.CompressionType(name), session.Compression())
+
+				requester := NewEchoRequester(session, session.GenerateNextSequence(), t, []byte(tt.data))
+				responser := requester.WarpResponser()
+				assert.NoError(t, requester.Request([]byte(tt.data)))
+				assert.NoError(t, responser.Apply())
+				spool.Put(session)
+			}
+		})
+	}
+}
+
 func saveMutualTLSPemToFile() error {
 	// CA pem
 	if err := savePemToFile(caPem, caPemPath); err != nil {
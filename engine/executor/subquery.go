@@ -34,6 +34,7 @@ func (b *SubQueryBuilder) newSubOptions(ctx context.Context, opt query.Processor
 	subOpt, err := query.NewProcessorOptionsStmt(b.stmt, query.SelectOptions{
 		Authorizer:  opt.Authorizer,
 		MaxSeriesN:  opt.MaxSeriesN,
+		MaxQueryMem: opt.MaxQueryMem,
 		ChunkedSize: opt.ChunkedSize,
 		Chunked:     opt.Chunked,
 		ChunkSize:   opt.ChunkSize,
@@ -145,6 +145,7 @@ type ChunkIterators struct {
 	merged       *record.Record
 	estimateSize int
 	maxN         int
+	floatStats   *FloatCodecStats
 
 	log *Log.Logger
 }
@@ -476,6 +477,7 @@ func (m *MmsTables) compactToLevel(group FilesInfo, full bool) error {
 	}
 
 	compItrs.WithLog(lcLog)
+	compItrs.floatStats = &FloatCodecStats{}
 	oldFilesSize := compItrs.estimateSize
 	newFiles, err := m.compact(compItrs, group.oldFiles, group.toLevel, true, lcLog)
 	if err != nil {
@@ -498,6 +500,7 @@ func (m *MmsTables) compactToLevel(group FilesInfo, full bool) error {
 		compactStatItem.CompactedFileCount = int64(len(newFiles))
 		compactStatItem.OriginalFileSize = int64(oldFilesSize)
 		compactStatItem.CompactedFileSize = sumFilesSize(newFiles)
+		compactStatItem.Codecs = compItrs.floatStats.statItems(compactStatItem.Codecs)
 	}
 	return nil
 }
@@ -510,6 +513,7 @@ func (m *MmsTables) compact(itrs *ChunkIterators, files []TSSPFile, level uint16
 		PutMsBuilder(*msb)
 	}(&tableBuilder)
 	tableBuilder.WithLog(cLog)
+	tableBuilder.WithFloatCodecStats(itrs.floatStats)
 	for {
 		select {
 		case <-m.closed:
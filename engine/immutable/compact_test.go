@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/openGemini/openGemini/engine/immutable/readcache"
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/cpu"
 	"github.com/openGemini/openGemini/lib/fileops"
 	"github.com/openGemini/openGemini/lib/interruptsignal"
@@ -778,6 +779,9 @@ func TestMmsTables_LevelCompact_SegmentLimit(t *testing.T) {
 		{1000, math.MaxUint16, minFileSizeLimit, 1000 * 10, AutoCompact},
 	}
 	testCompDir := t.TempDir()
+	// the file size limits are sized for the gorilla encoded values
+	SetFloatCompression(config.FloatCompressionGorilla)
+	defer SetFloatCompression(config.FloatCompressionAdaptive)
 	for _, cf := range confs {
 		_ = fileops.RemoveAll(testCompDir)
 		cacheIns := readcache.GetReadCacheIns()
@@ -41,6 +41,7 @@ var (
 	cacheMetaData     int32 = 0
 	maxTSSPFileSize   int64 = defaultFileSizeLimit
 	streamingCompact  int32 = AutoCompact
+	floatCompression  int32 = floatCompressedAdaptive
 )
 
 func SetMaxRowsPerSegment(maxRowsPerSegmentLimit int) {
@@ -152,3 +153,19 @@ func SegMergeFlag(v int32) {
 func MergeFlag() int32 {
 	return atomic.LoadInt32(&streamingCompact)
 }
+
+// SetFloatCompression sets the codec of the float blocks, an empty or unknown name selects adaptively
+func SetFloatCompression(name string) {
+	codec := floatCompressedAdaptive
+	for ty := range floatCodecNames {
+		if ty != floatCompressedAdaptive && floatCodecNames[ty] == name {
+			codec = ty
+		}
+	}
+	atomic.StoreInt32(&floatCompression, int32(codec))
+	log.Info("Set floatCompression", zap.String("codec", name))
+}
+
+func FloatCompression() int {
+	return int(atomic.LoadInt32(&floatCompression))
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"github.com/openGemini/openGemini/lib/numberenc"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// dictFilter checks the equality conditions on string fields against the dictionaries of a segment.
+// The condition is evaluated on the dictionary codes to a mask of the rows which may satisfy it,
+// only the dictionaries of the referenced columns are read and no row is expanded to a string.
+// The segment is skipped if no row is selected, otherwise only the selected rows are expanded and
+// checked by the condition filter. Anything that can not be proved is treated as a possible match.
+type dictFilter struct {
+	opts *FilterOptions
+	// the string fields compared with a literal in the condition, nil if there is none
+	fields []string
+
+	r      TSSPFile
+	cm     *ChunkMeta
+	segPos int
+
+	buf   []byte
+	dicts []dictColumn
+
+	// the rows of the segment selected by the condition, in the order of the file, nil for all
+	mask  []bool
+	masks [][]bool
+	used  int
+}
+
+type dictColumn struct {
+	name   string
+	loaded bool
+	// the column is missing in the chunk, all values are null
+	missing bool
+	// the column is dictionary encoded in the segment
+	encoded bool
+	dict    stringDict
+}
+
+func newDictFilter(opts *FilterOptions, schema record.Schemas) *dictFilter {
+	f := &dictFilter{opts: opts}
+	var filterFields []string
+	for _, idx := range opts.fieldsIdx {
+		if schema[idx].Type == influx.Field_Type_String {
+			filterFields = append(filterFields, schema[idx].Name)
+		}
+	}
+
+	influxql.WalkFunc(opts.cond, func(n influxql.Node) {
+		e, ok := n.(*influxql.BinaryExpr)
+		if !ok || e.Op != influxql.EQ {
+			return
+		}
+		if ref, _ := stringEquality(e); ref != nil && containsString(filterFields, ref.Val) &&
+			!containsString(f.fields, ref.Val) {
+			f.fields = append(f.fields, ref.Val)
+		}
+	})
+	for _, name := range f.fields {
+		f.dicts = append(f.dicts, dictColumn{name: name})
+	}
+	return f
+}
+
+func containsString(a []string, s string) bool {
+	for i := range a {
+		if a[i] == s {
+			return true
+		}
+	}
+	return false
+}
+
+// stringEquality returns the string field and the literal compared by the expression
+func stringEquality(e *influxql.BinaryExpr) (*influxql.VarRef, *influxql.StringLiteral) {
+	ref, ok := e.LHS.(*influxql.VarRef)
+	lit := e.RHS
+	if !ok {
+		if ref, ok = e.RHS.(*influxql.VarRef); !ok {
+			return nil, nil
+		}
+		lit = e.LHS
+	}
+
+	str, ok := lit.(*influxql.StringLiteral)
+	if !ok || ref.Type != influxql.String || ref.Val == record.TimeField {
+		return nil, nil
+	}
+	return ref, str
+}
+
+// segmentMayMatch checks the condition against the dictionaries of the segment, and keeps the
+// mask of the rows which may satisfy it
+func (f *dictFilter) segmentMayMatch(r TSSPFile, cm *ChunkMeta, segPos int) bool {
+	f.mask = nil
+	if len(f.fields) == 0 {
+		return true
+	}
+
+	f.r, f.cm, f.segPos = r, cm, segPos
+	for i := range f.dicts {
+		f.dicts[i].loaded = false
+	}
+	f.used = 0
+	mask := f.rows(f.opts.cond)
+	if mask == nil {
+		return true
+	}
+	switch countRows(mask) {
+	case 0:
+		return false
+	case len(mask):
+		return true
+	}
+	f.mask = mask
+	return true
+}
+
+func countRows(mask []bool) int {
+	n := 0
+	for _, ok := range mask {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// rows returns the rows of the segment which may satisfy the expression, nil for all the rows
+// and empty for none of them
+func (f *dictFilter) rows(expr influxql.Expr) []bool {
+	switch e := expr.(type) {
+	case *influxql.ParenExpr:
+		return f.rows(e.Expr)
+	case *influxql.BinaryExpr:
+		switch e.Op {
+		case influxql.AND:
+			lhs := f.rows(e.LHS)
+			if lhs != nil && len(lhs) == 0 {
+				return lhs
+			}
+			return andRows(lhs, f.rows(e.RHS))
+		case influxql.OR:
+			lhs := f.rows(e.LHS)
+			if lhs == nil {
+				return nil
+			}
+			return orRows(lhs, f.rows(e.RHS))
+		case influxql.EQ:
+			return f.equalityRows(e)
+		}
+	}
+	return nil
+}
+
+func andRows(a, b []bool) []bool {
+	if a == nil || (b != nil && len(b) == 0) {
+		return b
+	}
+	if b == nil || len(a) == 0 || len(a) != len(b) {
+		return a
+	}
+	for i := range a {
+		a[i] = a[i] && b[i]
+	}
+	return a
+}
+
+func orRows(a, b []bool) []bool {
+	if b == nil || len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	if len(a) != len(b) {
+		return nil
+	}
+	for i := range a {
+		a[i] = a[i] || b[i]
+	}
+	return a
+}
+
+func (f *dictFilter) equalityRows(e *influxql.BinaryExpr) []bool {
+	ref, lit := stringEquality(e)
+	if ref == nil {
+		return nil
+	}
+
+	col := f.column(ref.Val)
+	if col == nil {
+		return nil
+	}
+	if col.missing {
+		// null never equals a string
+		return []bool{}
+	}
+	if !col.encoded {
+		return nil
+	}
+	code := col.dict.lookup(lit.Val)
+	if code < 0 {
+		return []bool{}
+	}
+
+	// the null rows have a code too, they are left to the condition filter
+	mask := f.allocMask(col.dict.rows)
+	for i := range mask {
+		mask[i] = col.dict.code(i) == code
+	}
+	return mask
+}
+
+func (f *dictFilter) allocMask(rows int) []bool {
+	if f.used == len(f.masks) {
+		f.masks = append(f.masks, nil)
+	}
+	mask := f.masks[f.used]
+	if cap(mask) < rows {
+		mask = make([]bool, rows)
+	}
+	f.masks[f.used] = mask[:rows]
+	f.used++
+	return mask[:rows]
+}
+
+// column loads the dictionary of the column in the current segment, nil if the field is not filtered
+func (f *dictFilter) column(name string) *dictColumn {
+	var col *dictColumn
+	for i := range f.dicts {
+		if f.dicts[i].name == name {
+			col = &f.dicts[i]
+			break
+		}
+	}
+	if col == nil || col.loaded {
+		return col
+	}
+
+	col.loaded, col.missing, col.encoded = true, false, false
+	idx := f.cm.columnIndex(&record.Field{Name: name, Type: influx.Field_Type_String})
+	if idx < 0 {
+		col.missing = !f.hasColumn(name)
+		return col
+	}
+
+	entries := f.cm.colMeta[idx].entries
+	if f.segPos >= len(entries) {
+		return col
+	}
+	offset, size := entries[f.segPos].offsetSize()
+	data, err := f.r.ReadData(offset, size, &f.buf)
+	if err != nil {
+		return col
+	}
+
+	encData := columnEncodedData(data)
+	if isStringDictBlock(encData) && col.dict.unmarshal(encData) == nil {
+		col.encoded = true
+	}
+	return col
+}
+
+func (f *dictFilter) hasColumn(name string) bool {
+	for i := range f.cm.colMeta {
+		if f.cm.colMeta[i].name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// columnEncodedData skips the type and the nil bitmap of a column block
+func columnEncodedData(data []byte) []byte {
+	if len(data) < 5 {
+		return nil
+	}
+	nilBitmapLen := int(numberenc.UnmarshalUint32(data[1:]))
+	pos := 5 + nilBitmapLen + 8
+	if len(data) < pos {
+		return nil
+	}
+	return data[pos:]
+}
+
+// filterRows keeps the rows of the record selected by the mask, which is in the order of the file
+func filterRows(rec *record.Record, mask []bool, ascending bool) *record.Record {
+	n := rec.RowNums()
+	selected := func(i int) bool {
+		if ascending {
+			return mask[i]
+		}
+		return mask[n-1-i]
+	}
+
+	newRecord := record.NewRecordBuilder(rec.Schema)
+	newRecord.RecMeta = rec.RecMeta
+	for i := 0; i < n; {
+		if !selected(i) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < n && selected(j) {
+			j++
+		}
+		newRecord.AppendRec(rec, i, j)
+		i = j
+	}
+	if newRecord.RowNums() == 0 {
+		return nil
+	}
+	return newRecord
+}
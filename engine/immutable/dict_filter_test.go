@@ -0,0 +1,179 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dictFilterSchema = record.Schemas{
+	{Name: "status", Type: influx.Field_Type_String},
+	{Name: "value", Type: influx.Field_Type_Int},
+	{Name: "time", Type: influx.Field_Type_Int},
+}
+
+// writeDictFilterFile writes 7 segments of 16 rows, the status of the first 3 segments is OK or WARN
+// and the status of the others is OK or ERROR, every 7th status is null
+func writeDictFilterFile(t *testing.T, dir string) (*MmsTables, uint64, []*string) {
+	conf := NewConfig()
+	conf.SetMaxRowsPerSegment(16)
+	tier := uint64(meta.Hot)
+	store := NewTableStore(dir, &tier, false, conf)
+
+	rec := record.NewRecordBuilder(dictFilterSchema)
+	tm := time.Now().Truncate(time.Minute).UnixNano()
+	statuses := make([]*string, 0, 7*16)
+	for i := 0; i < 7*16; i++ {
+		status := "OK"
+		if i%3 == 0 && i < 3*16 {
+			status = "WARN"
+		} else if i%3 == 0 {
+			status = "ERROR"
+		}
+		if i%7 == 0 {
+			rec.ColVals[0].AppendStringNull()
+			statuses = append(statuses, nil)
+		} else {
+			rec.ColVals[0].AppendString(status)
+			statuses = append(statuses, &status)
+		}
+		rec.ColVals[1].AppendInteger(int64(i))
+		rec.ColVals[2].AppendInteger(tm + int64(i))
+	}
+
+	fileName := NewTSSPFileName(1, 0, 0, 0, true)
+	msb := AllocMsBuilder(dir, "mst", conf, 1, fileName, 0, store.Sequencer(), 2)
+	require.NoError(t, msb.WriteData(1, rec))
+	store.AddTable(msb, true, false)
+	return store, 1, statuses
+}
+
+func TestLocation_SkipByDict(t *testing.T) {
+	dir := t.TempDir()
+	store, id, statuses := writeDictFilterFile(t, dir)
+	defer store.Close()
+
+	fs := store.tableFiles("mst", true)
+	require.Equal(t, 1, fs.Len())
+	f := fs.Files()[0]
+
+	read := func(cond string, ascending bool) (int, *ReadContext) {
+		equal := ""
+		if e, ok := influxql.MustParseExpr(cond).(*influxql.BinaryExpr); ok && e.Op == influxql.EQ {
+			if _, lit := stringEquality(e); lit != nil {
+				equal = lit.Val
+			}
+		}
+		decs := NewReadContext(ascending)
+		decs.SetTr(record.MinMaxTimeRange)
+		loc := NewLocation(f, decs)
+		ok, err := loc.Contains(id, record.MinMaxTimeRange)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		opt := NewFilterOpts(influxql.MustParseExpr(cond), map[string]interface{}{}, []int{0, 1}, nil, nil)
+		var rows int
+		for {
+			rec, err := loc.ReadData(opt, record.NewRecordBuilder(dictFilterSchema))
+			require.NoError(t, err)
+			if rec == nil {
+				break
+			}
+			// the rows dropped by the dictionaries are not expanded, the others are intact
+			for i := 0; i < rec.RowNums(); i++ {
+				status, isNil := rec.ColVals[0].StringValueUnsafe(i)
+				require.False(t, isNil, cond)
+				require.NotEqual(t, "", status, cond)
+				if equal != "" {
+					require.Equal(t, equal, status, cond)
+				}
+			}
+			rows += rec.RowNums()
+		}
+		return rows, decs
+	}
+
+	count := func(match func(i int, status string) bool) int {
+		n := 0
+		for i, status := range statuses {
+			if status != nil && match(i, *status) {
+				n++
+			}
+		}
+		return n
+	}
+	is := func(values ...string) func(int, string) bool {
+		return func(_ int, status string) bool {
+			return containsString(values, status)
+		}
+	}
+
+	// the rows of the read segments dropped by the dictionaries
+	dropped := func(read func(seg int) bool, match func(i int, status string) bool) int64 {
+		var n int64
+		for i, status := range statuses {
+			if read(i/16) && (status == nil || !match(i, *status)) {
+				n++
+			}
+		}
+		return n
+	}
+
+	cases := []struct {
+		cond        string
+		rows        int
+		skipped     int64
+		skippedRows int64
+	}{
+		{"status::string = 'CRIT'", 0, 7, 0},
+		{"status::string = 'ERROR'", count(is("ERROR")), 3, 0},
+		{"'WARN' = status::string", count(is("WARN")), 4, 0},
+		{"status::string = 'WARN' OR status::string = 'ERROR'", count(is("WARN", "ERROR")), 0, 0},
+		{"status::string = 'ERROR' AND value::integer > 90", count(func(i int, status string) bool {
+			return i > 90 && status == "ERROR"
+		}), 5, 0},
+		// the min/max of the segments rule out both sides before the dictionaries are read
+		{"(status::string = 'CRIT') OR value::integer < 0", 0, 7, 0},
+		{"status::string = 'OK' AND status::string = 'CRIT'", 0, 7, 0},
+		{"status::string != 'OK'", count(is("WARN", "ERROR")), 0, 0},
+		// only the fields evaluated by the condition filter are checked
+		{"missing::string = 'OK'", 0, 0, 0},
+	}
+	errorSegs := func(seg int) bool { return seg >= 3 }
+	cases[1].skippedRows = dropped(errorSegs, is("ERROR"))
+	cases[2].skippedRows = dropped(func(seg int) bool { return seg < 3 }, is("WARN"))
+	cases[3].skippedRows = dropped(func(int) bool { return true }, is("WARN", "ERROR"))
+	// the segments 0-4 hold no value above 90
+	cases[4].skippedRows = dropped(func(seg int) bool { return seg >= 5 }, is("ERROR"))
+	for _, c := range cases {
+		for _, ascending := range []bool{true, false} {
+			rows, decs := read(c.cond, ascending)
+			assert.Equal(t, c.rows, rows, c.cond)
+			assert.Equal(t, c.skipped, decs.SkippedSegments(), c.cond)
+			assert.Equal(t, c.skippedRows, decs.SkippedRows(), c.cond)
+			decs.Release()
+		}
+	}
+}
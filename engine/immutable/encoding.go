@@ -184,6 +184,14 @@ type CoderContext struct {
 	stringCoder *String
 	boolCoder   *Boolean
 	buf         []byte
+
+	// floatStats accounts the float blocks encoded per codec if not nil
+	floatStats *FloatCodecStats
+
+	dictBuilder *stringDictBuilder
+	dict        *stringDict
+	// rowMask selects the rows of the dictionary encoded string blocks to expand, nil for all
+	rowMask []bool
 }
 
 func NewCoderContext() *CoderContext {
@@ -250,7 +258,13 @@ func EncodeFloatBlock(in []byte, out []byte, ctx *CoderContext) ([]byte, error)
 	if ctx.floatCoder == nil {
 		ctx.floatCoder = GetFloatCoder()
 	}
-	return ctx.floatCoder.Encoding(in, out)
+
+	pos := len(out)
+	out, err := ctx.floatCoder.Encoding(in, out)
+	if err == nil && ctx.floatStats != nil && len(out) > pos {
+		ctx.floatStats.add(int(out[pos]>>4), len(in), len(out)-pos)
+	}
+	return out, err
 }
 
 func DecodeFloatBlock(in []byte, out *[]byte, ctx *CoderContext) ([]float64, error) {
@@ -340,6 +354,13 @@ func EncodeStringBlock(in []byte, offset []uint32, out []byte, ctx *CoderContext
 		ctx.buf = ctx.buf[:0]
 	}
 
+	if ctx.dictBuilder == nil {
+		ctx.dictBuilder = newStringDictBuilder()
+	}
+	if ctx.dictBuilder.build(in, offset) {
+		return ctx.dictBuilder.marshal(out), nil
+	}
+
 	src := packString(in, offset, ctx)
 	return ctx.stringCoder.Encoding(src, out)
 }
@@ -349,6 +370,28 @@ func DecodeStringBlock(in []byte, out *[]byte, dstOffset *[]uint32, ctx *CoderCo
 		return *out, *dstOffset, nil
 	}
 
+	if isStringDictBlock(in) {
+		if ctx.dict == nil {
+			ctx.dict = &stringDict{}
+		}
+		if err := ctx.dict.unmarshal(in); err != nil {
+			return nil, nil, err
+		}
+		var values []byte
+		var offset []uint32
+		var err error
+		if ctx.rowMask != nil && len(ctx.rowMask) == ctx.dict.rows {
+			values, offset, err = ctx.dict.expandSelected(*out, (*dstOffset)[:0], ctx.rowMask)
+		} else {
+			values, offset, err = ctx.dict.expand(*out, (*dstOffset)[:0])
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		*out, *dstOffset = values, offset
+		return *out, *dstOffset, nil
+	}
+
 	var err error
 	if ctx.stringCoder == nil {
 		ctx.stringCoder = GetStringCoder()
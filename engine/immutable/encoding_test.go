@@ -19,11 +19,14 @@ package immutable
 import (
 	safeRand "crypto/rand"
 	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
 	"sort"
 	"testing"
 	"time"
 
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/record"
 )
 
@@ -180,6 +183,230 @@ func TestEncoding_FloatBlock_SameValues(t *testing.T) {
 	testFloat(false)
 }
 
+func genCodecFloats() map[string][]float64 {
+	rnd := rand.New(rand.NewSource(1))
+	data := map[string][]float64{
+		"decimal": make([]float64, 1000),
+		"random":  make([]float64, 1000),
+		"walk":    make([]float64, 1000),
+		"special": {0, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.MaxFloat64,
+			math.SmallestNonzeroFloat64, -1.5, 1e300, 1e-300, 0.1},
+		"single": {3.25},
+	}
+	v := 100.0
+	for i := 0; i < 1000; i++ {
+		data["decimal"][i] = float64(rnd.Intn(100000)) / 100
+		data["random"][i] = rnd.NormFloat64() * 1e6
+		v += rnd.Float64() - 0.5
+		data["walk"][i] = v
+	}
+	return data
+}
+
+func TestEncoding_FloatBlock_Codecs(t *testing.T) {
+	codecs := []int{floatCompressedAdaptive, floatCompressedGorilla, floatCompressedChimp, floatCompressedALP}
+	for name, values := range genCodecFloats() {
+		for _, codec := range codecs {
+			enc := NewCoderContext()
+			enc.floatCoder = GetFloatCoder()
+			enc.floatCoder.SetEncodingType(codec)
+
+			prefix := []byte{1, 2, 3}
+			out, err := EncodeFloatBlock(record.Float64Slice2byte(values), prefix, enc)
+			if err != nil {
+				t.Fatalf("%s/%d: unexpected error: %v", name, codec, err)
+			}
+
+			var decOut []byte
+			got, err := DecodeFloatBlock(out[len(prefix):], &decOut, NewCoderContext())
+			if err != nil {
+				t.Fatalf("%s/%d: unexpected error decoding block: %v", name, codec, err)
+			}
+			if len(got) != len(values) {
+				t.Fatalf("%s/%d: got %d values, exp %d", name, codec, len(got), len(values))
+			}
+			for i := range values {
+				if math.Float64bits(got[i]) != math.Float64bits(values[i]) {
+					t.Fatalf("%s/%d: value %d: got %v, exp %v", name, codec, i, got[i], values[i])
+				}
+			}
+		}
+	}
+}
+
+func TestEncoding_FloatBlock_AdaptiveSelection(t *testing.T) {
+	data := genCodecFloats()
+	blockType := func(values []float64) int {
+		out, err := EncodeFloatBlock(record.Float64Slice2byte(values), nil, NewCoderContext())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return int(out[0] >> 4)
+	}
+
+	if ty := blockType(data["decimal"]); ty != floatCompressedALP {
+		t.Fatalf("decimal values encoded by %v", floatCodecNames[ty])
+	}
+	if ty := blockType(data["random"]); ty == floatCompressedALP {
+		t.Fatalf("random values encoded by %v", floatCodecNames[ty])
+	}
+
+	// the sample is decimal, the block is not
+	values := append([]float64{}, data["decimal"]...)
+	values[len(values)-1] = math.Pi
+	if ty := blockType(values); ty != floatCompressedChimp {
+		t.Fatalf("not decimal values encoded by %v", floatCodecNames[ty])
+	}
+
+	SetFloatCompression(config.FloatCompressionChimp)
+	defer SetFloatCompression(config.FloatCompressionAdaptive)
+	if ty := blockType(data["decimal"]); ty != floatCompressedChimp {
+		t.Fatalf("decimal values encoded by %v with chimp compression", floatCodecNames[ty])
+	}
+}
+
+func TestEncoding_FloatBlock_InvalidType(t *testing.T) {
+	var decOut []byte
+	_, err := DecodeFloatBlock([]byte{0xf0, 0, 0, 0, 1, 0}, &decOut, NewCoderContext())
+	if err == nil {
+		t.Fatalf("expect error for unknown float codec")
+	}
+}
+
+func TestFloatCodecStats(t *testing.T) {
+	data := genCodecFloats()
+	stats := &FloatCodecStats{}
+	ctx := NewCoderContext()
+	ctx.floatStats = stats
+
+	var out []byte
+	var err error
+	for _, name := range []string{"decimal", "decimal", "random"} {
+		out, err = EncodeFloatBlock(record.Float64Slice2byte(data[name]), out[:0], ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	alp := stats[floatCompressedALP]
+	if alp.Blocks != 2 || alp.OriginalSize != 2*8000 || alp.EncodedSize <= 0 || alp.EncodedSize >= alp.OriginalSize {
+		t.Fatalf("unexpected alp stats: %+v", alp)
+	}
+
+	items := stats.statItems(nil)
+	if len(items) != 2 || items[len(items)-1].Codec != "alp" {
+		t.Fatalf("unexpected stat items: %+v", items)
+	}
+	blocks := int64(0)
+	for _, item := range items {
+		blocks += item.Blocks
+	}
+	if blocks != 3 {
+		t.Fatalf("got %d blocks, exp 3", blocks)
+	}
+}
+
+func TestEncoding_StringBlock_Dict(t *testing.T) {
+	pack := func(values []string) ([]byte, []uint32) {
+		var in []byte
+		var offset []uint32
+		for _, v := range values {
+			offset = append(offset, uint32(len(in)))
+			in = append(in, v...)
+		}
+		return in, offset
+	}
+	gen := func(rows, distinct int) []string {
+		values := make([]string, rows)
+		for i := range values {
+			values[i] = fmt.Sprintf("status_%d", (i*7)%distinct)
+		}
+		return values
+	}
+
+	cases := []struct {
+		values []string
+		dict   bool
+	}{
+		{gen(100, 1), true},
+		{gen(100, 3), true},
+		{gen(1000, 17), true},
+		{gen(512, 256), true},
+		{gen(1000, 257), false},
+		{gen(100, 51), false},
+		{[]string{"", "OK", "", "OK"}, true},
+		{[]string{"OK"}, false},
+	}
+	for _, c := range cases {
+		ctx := NewCoderContext()
+		in, offset := pack(c.values)
+		prefix := []byte{1, 2, 3}
+		out, err := EncodeStringBlock(in, offset, prefix, ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if isStringDictBlock(out[len(prefix):]) != c.dict {
+			t.Fatalf("%d values: dictionary encoded %v, exp %v", len(c.values), !c.dict, c.dict)
+		}
+
+		decOut := []byte("prefix")
+		var decOffset []uint32
+		got, gotOffset, err := DecodeStringBlock(out[len(prefix):], &decOut, &decOffset, NewCoderContext())
+		if err != nil {
+			t.Fatalf("unexpected error decoding block: %v", err)
+		}
+		if string(got) != "prefix"+string(in) || !reflect.DeepEqual(gotOffset, offset) {
+			t.Fatalf("unexpected results:\n\tgot: %v %v\n\texp: %v %v\n", string(got), gotOffset, string(in), offset)
+		}
+	}
+}
+
+func TestStringDict_Lookup(t *testing.T) {
+	b := newStringDictBuilder()
+	in := []byte("OKWARNOKERROROKOK")
+	offset := []uint32{0, 2, 6, 8, 13, 15}
+	if !b.build(in, offset) {
+		t.Fatalf("expect dictionary encoding")
+	}
+
+	var d stringDict
+	if err := d.unmarshal(b.marshal(nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.rows != 6 || d.width != 2 {
+		t.Fatalf("unexpected dictionary rows %d width %d", d.rows, d.width)
+	}
+	for _, v := range []string{"OK", "WARN", "ERROR"} {
+		if d.lookup(v) < 0 {
+			t.Fatalf("%s not found in dictionary", v)
+		}
+	}
+	if d.lookup("CRIT") >= 0 {
+		t.Fatalf("CRIT found in dictionary")
+	}
+	codes := []int{d.code(0), d.code(1), d.code(2), d.code(3), d.code(4), d.code(5)}
+	if !reflect.DeepEqual(codes, []int{0, 1, 0, 2, 0, 0}) {
+		t.Fatalf("unexpected codes %v", codes)
+	}
+
+	// only the selected rows are expanded, the others are empty
+	ctx := NewCoderContext()
+	ctx.rowMask = []bool{false, true, false, true, false, false}
+	var out []byte
+	var outOffset []uint32
+	got, gotOffset, err := DecodeStringBlock(b.marshal(nil), &out, &outOffset, ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != "WARNERROR" || !reflect.DeepEqual(gotOffset, []uint32{0, 0, 4, 4, 9, 9}) {
+		t.Fatalf("unexpected selected rows %s %v", got, gotOffset)
+	}
+
+	if err := d.unmarshal([]byte{stringCompressedDict << 4, 0, 0, 0, 1, 0, 0, 0, 0}); err == nil {
+		t.Fatalf("expect error for empty dictionary")
+	}
+}
+
 func TestEncoding_IntBlock(t *testing.T) {
 	testInt := func(prefix []byte, values []int64) {
 		var err error
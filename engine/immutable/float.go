@@ -25,16 +25,69 @@ import (
 	"math"
 	"math/bits"
 
+	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/numberenc"
 	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 	"github.com/openGemini/openGemini/open_src/github.com/dgryski/go-bitstream"
 )
 
 const (
+	// floatCompressedAdaptive selects the codec of each block by encoding a sample with every candidate
+	floatCompressedAdaptive = 0
 	// floatCompressedGorilla is a compressed format using the gorilla paper encoding
 	floatCompressedGorilla = 1
+	// floatCompressedChimp is a compressed format using the chimp paper encoding
+	floatCompressedChimp = 2
+	// floatCompressedALP stores decimal values as integers scaled by a power of ten
+	floatCompressedALP = 3
+
+	floatCodecCount = 4
+
+	// floatSampleSize is the number of values encoded with each candidate codec
+	floatSampleSize = 128
 )
 
+var floatCodecNames = [floatCodecCount]string{
+	"", config.FloatCompressionGorilla, config.FloatCompressionChimp, config.FloatCompressionALP,
+}
+
+var floatCandidates = []int{floatCompressedGorilla, floatCompressedChimp, floatCompressedALP}
+
+// FloatCodecStat is the size of the float blocks encoded by one codec
+type FloatCodecStat struct {
+	Blocks       int64
+	OriginalSize int64
+	EncodedSize  int64
+}
+
+// FloatCodecStats is indexed by the codec type recorded in the block header
+type FloatCodecStats [floatCodecCount]FloatCodecStat
+
+func (s *FloatCodecStats) add(ty int, originalSize, encodedSize int) {
+	if ty <= 0 || ty >= floatCodecCount {
+		return
+	}
+	s[ty].Blocks++
+	s[ty].OriginalSize += int64(originalSize)
+	s[ty].EncodedSize += int64(encodedSize)
+}
+
+func (s *FloatCodecStats) statItems(dst []statistics.CodecStatItem) []statistics.CodecStatItem {
+	for ty := range s {
+		if s[ty].Blocks == 0 {
+			continue
+		}
+		dst = append(dst, statistics.CodecStatItem{
+			Codec:        floatCodecNames[ty],
+			Blocks:       s[ty].Blocks,
+			OriginalSize: s[ty].OriginalSize,
+			EncodedSize:  s[ty].EncodedSize,
+		})
+	}
+	return dst
+}
+
 type Float struct {
 	encodingType int
 	leading      uint8
@@ -43,13 +96,18 @@ type Float struct {
 	buf *BytesBuffer
 	bw  *bitstream.BitWriter
 	br  *bitstream.BitReader
+
+	sample   []byte
+	scaled   []int64
+	ints     []byte
+	intCoder *Integer
 }
 
 func (enc *Float) Reset(dst []byte) {
 	enc.buf.Reset(dst)
 	enc.leading = ^uint8(0)
 	enc.trailing = 0
-	enc.encodingType = floatCompressedGorilla
+	enc.encodingType = floatCompressedAdaptive
 	enc.bw.Reset(enc.buf)
 }
 
@@ -111,20 +169,72 @@ func (enc *Float) Encoding(in []byte, out []byte) ([]byte, error) {
 		return out, nil
 	}
 
-	enc.buf.Reset(out)
+	values := record.Bytes2Float64Slice(in)
+	ty := enc.encodingType
+	if ty == floatCompressedAdaptive {
+		ty = FloatCompression()
+	}
+	if ty == floatCompressedAdaptive {
+		ty = enc.selectCodec(values)
+	}
+
+	if ty == floatCompressedALP {
+		if exp, ok := enc.alpExponent(values); ok {
+			return enc.alpEncoding(values, exp, out)
+		}
+		// the sample is decimal but the whole block is not
+		ty = floatCompressedChimp
+	}
+
+	return enc.bitsEncoding(ty, values, out)
+}
+
+// selectCodec encodes a sample of the values with every candidate and returns the smallest one
+func (enc *Float) selectCodec(values []float64) int {
+	sample := values
+	if len(sample) > floatSampleSize {
+		sample = sample[:floatSampleSize]
+	}
+
+	best, bestSize := floatCompressedGorilla, math.MaxInt32
+	for _, ty := range floatCandidates {
+		var err error
+		if ty == floatCompressedALP {
+			exp, ok := enc.alpExponent(sample)
+			if !ok {
+				continue
+			}
+			enc.sample, err = enc.alpEncoding(sample, exp, enc.sample[:0])
+		} else {
+			enc.sample, err = enc.bitsEncoding(ty, sample, enc.sample[:0])
+		}
+		if err == nil && len(enc.sample) < bestSize {
+			best, bestSize = ty, len(enc.sample)
+		}
+	}
+	return best
+}
+
+func writeFloatHeader(ty int, count int, out []byte) []byte {
+	out = append(out, byte(ty<<4))
+	return numberenc.MarshalUint32Append(out, uint32(count))
+}
+
+func (enc *Float) bitsEncoding(ty int, values []float64, out []byte) ([]byte, error) {
+	enc.buf.Reset(writeFloatHeader(ty, len(values), out))
 	if enc.bw == nil {
 		enc.bw = bitstream.NewWriter(enc.buf)
 	} else {
 		enc.bw.Reset(enc.buf)
 	}
 
-	values := record.Bytes2Float64Slice(in)
-	var count [4]byte
-	numberenc.MarshalUint32Copy(count[:], uint32(len(values)))
-	_ = enc.buf.WriteByte(byte(floatCompressedGorilla << 4))
-	_, _ = enc.buf.Write(count[:])
-
-	if err := enc.gorillaEncoding(values); err != nil {
+	var err error
+	if ty == floatCompressedChimp {
+		err = enc.chimpEncoding(values)
+	} else {
+		err = enc.gorillaEncoding(values)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -193,18 +303,16 @@ func (enc *Float) gorillaDecoding(out []float64) error {
 }
 
 func (enc *Float) Decoding(in []byte, out []byte) ([]byte, error) {
-	enc.encodingType, in = int(in[0]>>4), in[1:]
-	if enc.encodingType != floatCompressedGorilla {
-		return nil, fmt.Errorf("invalid input float encoded data, type = %v", enc.encodingType)
+	if len(in) < 5 {
+		return nil, fmt.Errorf("invalid input float encoded data, len = %v", len(in))
+	}
+	ty, in := int(in[0]>>4), in[1:]
+	if ty != floatCompressedGorilla && ty != floatCompressedChimp && ty != floatCompressedALP {
+		return nil, fmt.Errorf("invalid input float encoded data, type = %v", ty)
 	}
 
 	count := int(numberenc.UnmarshalUint32(in))
-	enc.buf.Reset(in[4:])
-	if enc.br == nil {
-		enc.br = bitstream.NewReader(enc.buf)
-	} else {
-		enc.br.Reset(enc.buf)
-	}
+	in = in[4:]
 
 	origLen := len(out)
 	n := record.Float64SizeBytes * count
@@ -216,7 +324,27 @@ func (enc *Float) Decoding(in []byte, out []byte) ([]byte, error) {
 	out = out[:origLen+n]
 	outValues := record.Bytes2Float64Slice(out[origLen:])
 
-	if err := enc.gorillaDecoding(outValues); err != nil {
+	if ty == floatCompressedALP {
+		if err := enc.alpDecoding(in, outValues); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	enc.buf.Reset(in)
+	if enc.br == nil {
+		enc.br = bitstream.NewReader(enc.buf)
+	} else {
+		enc.br.Reset(enc.buf)
+	}
+
+	var err error
+	if ty == floatCompressedChimp {
+		err = enc.chimpDecoding(outValues)
+	} else {
+		err = enc.gorillaDecoding(outValues)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+/*
+The decimal encoding is modified from ALP: https://dl.acm.org/doi/pdf/10.1145/3626717
+Values which are decimals with few fraction digits are scaled by a power of ten
+and the resulting integers are compressed with the integer codec.
+*/
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/openGemini/openGemini/lib/record"
+)
+
+const (
+	alpMaxExponent = 18
+	// alpMaxInt is the largest magnitude which float64 represents exactly as an integer
+	alpMaxInt = 1 << 52
+)
+
+var alpPow10 = [alpMaxExponent + 1]float64{
+	1, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18,
+}
+
+// alpScale returns v scaled by 10^exp, and false if the integer does not restore v exactly
+func alpScale(v float64, exp int) (int64, bool) {
+	n := math.Round(v * alpPow10[exp])
+	if !(math.Abs(n) <= alpMaxInt) {
+		return 0, false
+	}
+
+	i := int64(n)
+	if math.Float64bits(float64(i)/alpPow10[exp]) != math.Float64bits(v) {
+		return 0, false
+	}
+	return i, true
+}
+
+// alpExponent returns the smallest exponent that scales all values to integers without loss
+func (enc *Float) alpExponent(values []float64) (int, bool) {
+	for exp := 0; exp <= alpMaxExponent; exp++ {
+		ok := true
+		for _, v := range values {
+			if _, ok = alpScale(v, exp); !ok {
+				break
+			}
+		}
+		if ok {
+			return exp, true
+		}
+	}
+	return 0, false
+}
+
+func (enc *Float) initIntCoder() {
+	if enc.intCoder == nil {
+		enc.intCoder = &Integer{buf: NewBytesBuffer(nil)}
+	}
+}
+
+func (enc *Float) alpEncoding(values []float64, exp int, out []byte) ([]byte, error) {
+	out = writeFloatHeader(floatCompressedALP, len(values), out)
+	out = append(out, byte(exp))
+
+	enc.scaled = enc.scaled[:0]
+	for _, v := range values {
+		i, _ := alpScale(v, exp)
+		enc.scaled = append(enc.scaled, i)
+	}
+
+	enc.initIntCoder()
+	return enc.intCoder.Encoding(record.Int64Slice2byte(enc.scaled), out)
+}
+
+func (enc *Float) alpDecoding(in []byte, out []float64) error {
+	if len(in) < 1 {
+		return fmt.Errorf("invalid alp encoded data, len = %v", len(in))
+	}
+	exp := int(in[0])
+	if exp > alpMaxExponent {
+		return fmt.Errorf("invalid alp encoded data, exponent = %v", exp)
+	}
+
+	var err error
+	enc.initIntCoder()
+	enc.ints, err = enc.intCoder.Decoding(in[1:], enc.ints[:0])
+	if err != nil {
+		return err
+	}
+
+	ints := record.Bytes2Int64Slice(enc.ints)
+	if len(ints) != len(out) {
+		return fmt.Errorf("invalid alp encoded data, count %v != %v", len(ints), len(out))
+	}
+	for i := range out {
+		out[i] = float64(ints[i]) / alpPow10[exp]
+	}
+	return nil
+}
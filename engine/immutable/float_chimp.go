@@ -0,0 +1,146 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+/*
+The chimp compression follows: https://www.vldb.org/pvldb/vol15/p3058-liakos.pdf
+*/
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/openGemini/openGemini/open_src/github.com/dgryski/go-bitstream"
+)
+
+const (
+	chimpTrailingThreshold = 6
+
+	chimpFlagZero     = 0
+	chimpFlagTrailing = 1
+	chimpFlagSameLead = 2
+	chimpFlagNewLead  = 3
+)
+
+// chimpLeading are the leading zero counts representable by the 3 bits leading code
+var chimpLeading = [8]int{0, 8, 12, 16, 18, 20, 22, 24}
+
+func chimpLeadingCode(leading int) uint64 {
+	for i := len(chimpLeading) - 1; i > 0; i-- {
+		if leading >= chimpLeading[i] {
+			return uint64(i)
+		}
+	}
+	return 0
+}
+
+func (enc *Float) chimpEncoding(values []float64) error {
+	prev := math.Float64bits(values[0])
+	_ = enc.bw.WriteBits(prev, 64)
+
+	storedLeading := -1
+	for _, v := range values[1:] {
+		cur := math.Float64bits(v)
+		xor := cur ^ prev
+		prev = cur
+
+		if xor == 0 {
+			_ = enc.bw.WriteBits(chimpFlagZero, 2)
+			storedLeading = -1
+			continue
+		}
+
+		code := chimpLeadingCode(bits.LeadingZeros64(xor))
+		leading := chimpLeading[code]
+		trailing := bits.TrailingZeros64(xor)
+		if trailing > chimpTrailingThreshold {
+			sigbits := 64 - leading - trailing
+			_ = enc.bw.WriteBits(chimpFlagTrailing, 2)
+			_ = enc.bw.WriteBits(code, 3)
+			_ = enc.bw.WriteBits(uint64(sigbits), 6)
+			_ = enc.bw.WriteBits(xor>>uint(trailing), sigbits)
+			storedLeading = -1
+		} else if leading == storedLeading {
+			_ = enc.bw.WriteBits(chimpFlagSameLead, 2)
+			_ = enc.bw.WriteBits(xor, 64-leading)
+		} else {
+			storedLeading = leading
+			_ = enc.bw.WriteBits(chimpFlagNewLead, 2)
+			_ = enc.bw.WriteBits(code, 3)
+			_ = enc.bw.WriteBits(xor, 64-leading)
+		}
+	}
+
+	return enc.bw.Flush(bitstream.Zero)
+}
+
+func (enc *Float) chimpDecoding(out []float64) error {
+	prev, err := enc.br.ReadBits(64)
+	if err != nil {
+		return err
+	}
+	out[0] = math.Float64frombits(prev)
+
+	storedLeading := 0
+	for i := 1; i < len(out); i++ {
+		flag, err := enc.br.ReadBits(2)
+		if err != nil {
+			return err
+		}
+
+		var xor uint64
+		switch flag {
+		case chimpFlagZero:
+		case chimpFlagTrailing:
+			code, err := enc.br.ReadBits(3)
+			if err != nil {
+				return err
+			}
+			sigbits, err := enc.br.ReadBits(6)
+			if err != nil {
+				return err
+			}
+			trailing := 64 - chimpLeading[code] - int(sigbits)
+			if sigbits == 0 || trailing < 0 {
+				return fmt.Errorf("invalid chimp encoded data, significant bits = %v", sigbits)
+			}
+			xor, err = enc.br.ReadBits(int(sigbits))
+			if err != nil {
+				return err
+			}
+			xor <<= uint(trailing)
+		case chimpFlagNewLead:
+			code, err := enc.br.ReadBits(3)
+			if err != nil {
+				return err
+			}
+			storedLeading = chimpLeading[code]
+			fallthrough
+		default:
+			xor, err = enc.br.ReadBits(64 - storedLeading)
+			if err != nil {
+				return err
+			}
+		}
+
+		prev ^= xor
+		out[i] = math.Float64frombits(prev)
+	}
+
+	return nil
+}
@@ -19,6 +19,7 @@ package immutable
 import (
 	"fmt"
 	"io"
+	"sync/atomic"
 
 	"github.com/openGemini/openGemini/engine/immutable/readcache"
 	"github.com/openGemini/openGemini/lib/bufferpool"
@@ -28,7 +29,7 @@ import (
 )
 
 var mmapEn = !config.Is32BitPtr
-var readCacheEn = false
+var readCacheEn int32 = 0
 
 func EnableMmapRead(en bool) {
 	mmapEn = en
@@ -36,13 +37,17 @@ func EnableMmapRead(en bool) {
 
 func EnableReadCache(readCacheLimit int) {
 	if readCacheLimit > 0 {
-		readCacheEn = true
 		readcache.SetCacheLimitSize(readCacheLimit)
+		atomic.StoreInt32(&readCacheEn, 1)
 	} else {
-		readCacheEn = false
+		atomic.StoreInt32(&readCacheEn, 0)
 	}
 }
 
+func readCacheEnabled() bool {
+	return atomic.LoadInt32(&readCacheEn) == 1
+}
+
 type DiskFileReader interface {
 	Name() string
 	ReadAt(off int64, size uint32, dst *[]byte) ([]byte, error)
@@ -67,7 +72,7 @@ func NewDiskFileReader(f fileops.File) *diskFileReader {
 
 	fileSize := fi.Size()
 	r := &diskFileReader{fd: f, fileSize: fileSize}
-	if mmapEn {
+	if mmapEn && !fileops.IsEncrypted(f) {
 		r.mmapData, err = fileops.Mmap(f.Fd(), int(fileSize))
 		if err != nil {
 			err = errMapFail(fName, err)
@@ -114,6 +119,9 @@ func (r *diskFileReader) ReadAt(off int64, size uint32, dstPtr *[]byte) ([]byte,
 		return rb, nil
 	}
 
+	if dstPtr == nil {
+		dstPtr = new([]byte)
+	}
 	*dstPtr = bufferpool.Resize(*dstPtr, int(size))
 	dst := *dstPtr
 
@@ -163,7 +171,7 @@ func (r *diskFileReader) Rename(newName string) error {
 		return err
 	}
 
-	if mmapEn {
+	if mmapEn && !fileops.IsEncrypted(r.fd) {
 		r.mmapData, err = fileops.Mmap(r.fd.Fd(), int(r.fileSize))
 		if err != nil {
 			err = errMapFail(newName, err)
@@ -182,7 +190,7 @@ func (r *diskFileReader) Close() error {
 			return err
 		}
 	}
-	if readCacheEn {
+	if readCacheEnabled() {
 		cacheIns := readcache.GetReadCacheIns()
 		cacheIns.Remove(r.Name())
 	}
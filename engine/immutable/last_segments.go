@@ -0,0 +1,70 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"github.com/openGemini/openGemini/lib/record"
+)
+
+// ReadLastSegments calls fn with the segments of each series in the file, from the
+// last segment backwards, until every column of the series has got a value.
+// The record passed to fn is reused.
+func ReadLastSegments(f TSSPFile, fn func(sid uint64, rec *record.Record)) error {
+	decs := NewReadContext(true)
+	defer decs.Release()
+
+	var cms []ChunkMeta
+	var schema record.Schemas
+	var found []bool
+	n := int(f.FileStat().metaIndexItemNum)
+	for i := 0; i < n; i++ {
+		m, err := f.MetaIndexAt(i)
+		if err != nil {
+			return err
+		}
+		cms, err = f.ReadChunkMetaData(i, m, cms[:0])
+		if err != nil {
+			return err
+		}
+
+		for j := range cms {
+			cm := &cms[j]
+			schema = unmarshalBlockHeader(cm, schema[:0])
+			missing := len(schema) - 1
+			found = append(found[:0], make([]bool, missing)...)
+			rec := record.NewRecordBuilder(schema)
+			for seg := cm.segmentCount() - 1; seg >= 0 && missing > 0; seg-- {
+				rec.ResetForReuse()
+				dst, err := f.ReadAt(cm, seg, rec, decs)
+				if err != nil {
+					return err
+				}
+				if dst == nil || dst.RowNums() == 0 {
+					continue
+				}
+				fn(cm.sid, dst)
+				for c := range found {
+					if !found[c] && dst.ColVals[c].NilCount < dst.ColVals[c].Len {
+						found[c] = true
+						missing--
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
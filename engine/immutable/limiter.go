@@ -48,6 +48,7 @@ func SetSnapshotLimit(bytesPerSec int64, burstLimit int64) {
 		atomic.StoreInt32(&snapshotNoLimit, 1)
 		return
 	}
+	atomic.StoreInt32(&snapshotNoLimit, 0)
 	if burstLimit < bytesPerSec {
 		burstLimit = bytesPerSec
 	}
@@ -0,0 +1,360 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"strings"
+
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// integers beyond this can not be converted to float64 exactly, merged pre-aggs may be rounded
+const maxExactFloatInt = 1 << 53
+
+// preAggFilter checks a field condition against the pre-aggregated min/max of the columns in a chunk,
+// or in a segment of the chunk when the chunk keeps the min/max of its segments, see appendSegmentAggs.
+// mayMatch returning false means no row of the chunk, or of the segment, satisfies the condition and
+// it can be skipped without decoding. Anything that can not be proved is treated as a possible match.
+type preAggFilter struct {
+	cm       *ChunkMeta
+	builders *PreAggBuilders
+	// the fields that FilterByField evaluates, other references are left unknown
+	fields []string
+	rows   int64
+
+	// the segment checked, -1 for the whole chunk
+	seg     int
+	segAggs map[string][]preAggColumnStat
+}
+
+type preAggColumnStat struct {
+	ty    int
+	count int64
+	nulls bool
+	minI  int64
+	maxI  int64
+	minF  float64
+	maxF  float64
+	minB  bool
+	maxB  bool
+	minS  string
+	maxS  string
+	// maxS is a prefix of the max
+	maxTruncated bool
+}
+
+func newPreAggFilter(cm *ChunkMeta, builders *PreAggBuilders, schema record.Schemas, fieldsIdx []int) *preAggFilter {
+	f := &preAggFilter{
+		cm:       cm,
+		builders: builders,
+		fields:   make([]string, 0, len(fieldsIdx)),
+		seg:      -1,
+	}
+	for _, idx := range fieldsIdx {
+		f.fields = append(f.fields, schema[idx].Name)
+	}
+	f.rows = int64(cm.Rows(builders.timeBuilder))
+	return f
+}
+
+func (f *preAggFilter) isFilterField(name string) bool {
+	for i := range f.fields {
+		if f.fields[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// columnStat returns the statistics of the column, ok is false if they can not be used
+func (f *preAggFilter) columnStat(ref *influxql.VarRef) (*preAggColumnStat, bool) {
+	if ref.Val == record.TimeField || !f.isFilterField(ref.Val) {
+		return nil, false
+	}
+
+	var ty int
+	switch ref.Type {
+	case influxql.Integer:
+		ty = influx.Field_Type_Int
+	case influxql.Float:
+		ty = influx.Field_Type_Float
+	case influxql.Boolean:
+		ty = influx.Field_Type_Boolean
+	case influxql.String:
+		ty = influx.Field_Type_String
+	default:
+		return nil, false
+	}
+
+	field := &record.Field{Name: ref.Val, Type: ty}
+	idx := f.cm.columnIndex(field)
+	if idx < 0 {
+		if f.hasColumn(ref.Val) {
+			// the column is stored with another type
+			return nil, false
+		}
+		// the field is missing in the chunk, all values are null
+		return &preAggColumnStat{ty: ty, nulls: f.rows > 0}, true
+	}
+	if f.seg >= 0 || ty == influx.Field_Type_String {
+		return f.segmentStat(field, idx)
+	}
+
+	ab := f.builders.aggBuilder(field)
+	ab.reset()
+	if _, err := ab.unmarshal(f.cm.colMeta[idx].preAgg); err != nil {
+		return nil, false
+	}
+
+	stat := &preAggColumnStat{ty: ty, count: ab.count()}
+	stat.nulls = stat.count < f.rows
+	if stat.count == 0 {
+		return stat, true
+	}
+	min, _ := ab.min()
+	max, _ := ab.max()
+	switch ty {
+	case influx.Field_Type_Int:
+		stat.minI, stat.maxI = min.(int64), max.(int64)
+		if stat.minI < -maxExactFloatInt || stat.maxI > maxExactFloatInt {
+			return nil, false
+		}
+	case influx.Field_Type_Float:
+		stat.minF, stat.maxF = min.(float64), max.(float64)
+	case influx.Field_Type_Boolean:
+		stat.minB, stat.maxB = min.(bool), max.(bool)
+	}
+	return stat, true
+}
+
+// segmentStat returns the statistics of the column in the segment checked. The statistics of a string
+// column in the chunk are the ones of its segments, the segment is set by comparisonMayMatch.
+func (f *preAggFilter) segmentStat(field *record.Field, idx int) (*preAggColumnStat, bool) {
+	stats, ok := f.segAggs[field.Name]
+	if !ok {
+		ab := f.builders.aggBuilder(field)
+		preAgg := f.cm.colMeta[idx].preAgg
+		if len(preAgg) >= ab.size() {
+			stats, _ = unmarshalSegmentAggs(preAgg[ab.size():], field.Type, f.cm.segmentCount())
+		}
+		if f.segAggs == nil {
+			f.segAggs = make(map[string][]preAggColumnStat)
+		}
+		f.segAggs[field.Name] = stats
+	}
+	if f.seg < 0 || f.seg >= len(stats) {
+		return nil, false
+	}
+	stat := &stats[f.seg]
+	if stat.ty == influx.Field_Type_Int && stat.count > 0 && (stat.minI < -maxExactFloatInt || stat.maxI > maxExactFloatInt) {
+		return nil, false
+	}
+	return stat, true
+}
+
+// segmentMayMatch checks the condition against the min/max of the segment seg
+func (f *preAggFilter) segmentMayMatch(expr influxql.Expr, seg int) bool {
+	f.seg = seg
+	defer func() {
+		f.seg = -1
+	}()
+	return f.mayMatch(expr)
+}
+
+func (f *preAggFilter) hasColumn(name string) bool {
+	for i := range f.cm.colMeta {
+		if f.cm.colMeta[i].name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *preAggFilter) mayMatch(expr influxql.Expr) bool {
+	switch e := expr.(type) {
+	case *influxql.ParenExpr:
+		return f.mayMatch(e.Expr)
+	case *influxql.BinaryExpr:
+		switch e.Op {
+		case influxql.AND:
+			return f.mayMatch(e.LHS) && f.mayMatch(e.RHS)
+		case influxql.OR:
+			return f.mayMatch(e.LHS) || f.mayMatch(e.RHS)
+		case influxql.EQ, influxql.NEQ, influxql.LT, influxql.LTE, influxql.GT, influxql.GTE:
+			return f.comparisonMayMatch(e)
+		}
+	}
+	return true
+}
+
+// swapOp is the operator after swapping the operands
+var swapOp = map[influxql.Token]influxql.Token{
+	influxql.EQ:  influxql.EQ,
+	influxql.NEQ: influxql.NEQ,
+	influxql.LT:  influxql.GT,
+	influxql.LTE: influxql.GTE,
+	influxql.GT:  influxql.LT,
+	influxql.GTE: influxql.LTE,
+}
+
+func (f *preAggFilter) comparisonMayMatch(e *influxql.BinaryExpr) bool {
+	op := e.Op
+	ref, ok := e.LHS.(*influxql.VarRef)
+	lit := e.RHS
+	if !ok {
+		if ref, ok = e.RHS.(*influxql.VarRef); !ok {
+			return true
+		}
+		lit, op = e.LHS, swapOp[op]
+	}
+
+	if ref.Type == influxql.String && f.seg < 0 && f.isFilterField(ref.Val) {
+		// a string column matches in the chunk if it matches in one of its segments
+		for f.seg = 0; f.seg < f.cm.segmentCount(); f.seg++ {
+			stat, ok := f.columnStat(ref)
+			if !ok || stat.mayMatch(op, lit) {
+				f.seg = -1
+				return true
+			}
+		}
+		f.seg = -1
+		return false
+	}
+
+	stat, ok := f.columnStat(ref)
+	if !ok {
+		return true
+	}
+	return stat.mayMatch(op, lit)
+}
+
+func (s *preAggColumnStat) mayMatch(op influxql.Token, lit influxql.Expr) bool {
+	switch v := lit.(type) {
+	case *influxql.IntegerLiteral:
+		if s.ty == influx.Field_Type_Int {
+			return s.count > 0 && intRangeMayMatch(op, s.minI, s.maxI, v.Val)
+		}
+		return s.floatMayMatch(op, float64(v.Val))
+	case *influxql.NumberLiteral:
+		return s.floatMayMatch(op, v.Val)
+	case *influxql.BooleanLiteral:
+		return s.boolMayMatch(op, v.Val)
+	case *influxql.StringLiteral:
+		return s.stringMayMatch(op, v.Val)
+	}
+	return true
+}
+
+// stringMayMatch checks EQ and NEQ, null never satisfies a string comparison
+func (s *preAggColumnStat) stringMayMatch(op influxql.Token, v string) bool {
+	if s.ty != influx.Field_Type_String || (op != influxql.EQ && op != influxql.NEQ) {
+		return true
+	}
+	if s.count == 0 {
+		return false
+	}
+	if op == influxql.NEQ {
+		return s.maxTruncated || s.minS != v || s.maxS != v
+	}
+	if v < s.minS {
+		return false
+	}
+	if s.maxTruncated {
+		// all the strings greater than the prefix and not starting with it are greater than the max
+		return v <= s.maxS || strings.HasPrefix(v, s.maxS)
+	}
+	return v <= s.maxS
+}
+
+// floatMayMatch compares the column with a float, the same as the condition evaluation does
+func (s *preAggColumnStat) floatMayMatch(op influxql.Token, v float64) bool {
+	// null never satisfies a number comparison
+	if s.count == 0 {
+		return false
+	}
+
+	switch s.ty {
+	case influx.Field_Type_Int:
+		return floatRangeMayMatch(op, float64(s.minI), float64(s.maxI), v)
+	case influx.Field_Type_Float:
+		// NaN is not in the range, and NaN != v is always true
+		if op == influxql.NEQ {
+			return true
+		}
+		return floatRangeMayMatch(op, s.minF, s.maxF, v)
+	}
+	return true
+}
+
+// boolMayMatch checks EQ and NEQ, null is evaluated as false when compared with a boolean
+func (s *preAggColumnStat) boolMayMatch(op influxql.Token, v bool) bool {
+	if s.ty != influx.Field_Type_Boolean || (op != influxql.EQ && op != influxql.NEQ) {
+		return true
+	}
+
+	hasFalse, hasTrue := s.nulls, false
+	if s.count > 0 {
+		hasFalse = hasFalse || !s.minB
+		hasTrue = s.maxB
+	}
+	return boolSetMayMatch(op, hasFalse, hasTrue, v)
+}
+
+func boolSetMayMatch(op influxql.Token, hasFalse, hasTrue, v bool) bool {
+	if op == influxql.EQ {
+		return (v && hasTrue) || (!v && hasFalse)
+	}
+	return (v && hasFalse) || (!v && hasTrue)
+}
+
+func intRangeMayMatch(op influxql.Token, min, max, v int64) bool {
+	switch op {
+	case influxql.EQ:
+		return min <= v && v <= max
+	case influxql.NEQ:
+		return min != v || max != v
+	case influxql.LT:
+		return min < v
+	case influxql.LTE:
+		return min <= v
+	case influxql.GT:
+		return max > v
+	case influxql.GTE:
+		return max >= v
+	}
+	return true
+}
+
+func floatRangeMayMatch(op influxql.Token, min, max, v float64) bool {
+	switch op {
+	case influxql.EQ:
+		return min <= v && v <= max
+	case influxql.NEQ:
+		return min != v || max != v
+	case influxql.LT:
+		return min < v
+	case influxql.LTE:
+		return min <= v
+	case influxql.GT:
+		return max > v
+	case influxql.GTE:
+		return max >= v
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePreAggFilterFile(t *testing.T, dir string, conf *Config) (*MmsTables, []uint64, *MinMax) {
+	tier := uint64(meta.Hot)
+	store := NewTableStore(dir, &tier, false, conf)
+
+	var idMinMax, tmMinMax MinMax
+	ids, data := genMemTableData(1, 2, 100, &idMinMax, &tmMinMax)
+	fileName := NewTSSPFileName(1, 0, 0, 0, true)
+	msb := AllocMsBuilder(dir, "mst", conf, 10, fileName, 0, store.Sequencer(), 2)
+	for _, id := range ids {
+		require.NoError(t, msb.WriteData(id, data[id]))
+	}
+	store.AddTable(msb, true, false)
+	return store, ids, &tmMinMax
+}
+
+func TestPreAggFilter_MayMatch(t *testing.T) {
+	dir := t.TempDir()
+	store, _, _ := writePreAggFilterFile(t, dir, NewConfig())
+	defer store.Close()
+
+	fs := store.tableFiles("mst", true)
+	require.Equal(t, 1, fs.Len())
+	fi, err := NewFileInspector(fs.Files()[0].Path())
+	require.NoError(t, err)
+	defer fi.Close()
+
+	var cm *ChunkMeta
+	require.NoError(t, fi.WalkChunkMetas(func(_ int, m *ChunkMeta) error {
+		if cm == nil {
+			cm = m
+		}
+		return nil
+	}))
+	require.NotNil(t, cm)
+
+	schema := record.Schemas{
+		{Name: "field1_int64", Type: influx.Field_Type_Int},
+		{Name: "field2_float", Type: influx.Field_Type_Float},
+		{Name: "field3_string", Type: influx.Field_Type_String},
+		{Name: "field4_bool", Type: influx.Field_Type_Boolean},
+		{Name: "missing", Type: influx.Field_Type_Float},
+		{Name: "time", Type: influx.Field_Type_Int},
+	}
+	f := newPreAggFilter(cm, newPreAggBuilders(), schema, []int{0, 1, 2, 3, 4})
+
+	// field1_int64 is in [0, 108], field2_float is in [0, 120], field3_string is test_N with N in
+	// field1_int64, field4_bool has both values and nulls
+	cases := []struct {
+		cond  string
+		match bool
+	}{
+		{"field1_int64::integer > 1000", false},
+		{"field1_int64::integer < 1000", true},
+		{"field1_int64::integer = -1", false},
+		{"field1_int64::integer != -1", true},
+		{"1000 < field1_int64::integer", false},
+		{"field1_int64::integer > 999.5", false},
+		{"field1_int64::integer > 1000 OR field2_float::float < 1000", true},
+		{"field1_int64::integer > 1000 AND field2_float::float < 1000", false},
+		{"(field2_float::float > 100000)", false},
+		{"field2_float::float != 1.5", true},
+		{"field4_bool::boolean = false", true},
+		{"field4_bool::boolean != true", true},
+		{"field3_string::string = 'test'", false},
+		{"field3_string::string = 'test_50'", true},
+		{"'zzz' = field3_string::string", false},
+		{"field3_string::string != 'test_50'", true},
+		{"field3_string::string =~ /zzz/", true},
+		{"field3_string::string = 'test' OR field1_int64::integer > 1000", false},
+		{"missing::float > 1", false},
+		{"missing::float != 1", false},
+		{"missing::boolean = false", true},
+		{"missing::boolean = true", false},
+		{"host::tag = 'a'", true},
+		{"time > 1", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, f.mayMatch(influxql.MustParseExpr(c.cond)), c.cond)
+	}
+
+	// fields not evaluated by the condition filter are never used
+	f = newPreAggFilter(cm, newPreAggBuilders(), schema, []int{0})
+	assert.True(t, f.mayMatch(influxql.MustParseExpr("field2_float::float > 100000")))
+}
+
+func TestLocation_SkipByPreAgg(t *testing.T) {
+	dir := t.TempDir()
+	conf := NewConfig()
+	conf.SetMaxRowsPerSegment(10)
+	store, ids, tm := writePreAggFilterFile(t, dir, conf)
+	defer store.Close()
+
+	fs := store.tableFiles("mst", true)
+	require.Equal(t, 1, fs.Len())
+	f := fs.Files()[0]
+
+	schema := record.Schemas{
+		{Name: "field1_int64", Type: influx.Field_Type_Int},
+		{Name: "time", Type: influx.Field_Type_Int},
+	}
+	read := func(cond string, tr record.TimeRange) (int, int, *ReadContext) {
+		decs := NewReadContext(true)
+		decs.SetTr(tr)
+		loc := NewLocation(f, decs)
+		ok, err := loc.Contains(ids[0], tr)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		opt := NewFilterOpts(influxql.MustParseExpr(cond), map[string]interface{}{}, []int{0}, nil, nil)
+		segs := int(loc.meta.segCount)
+		var rows int
+		for {
+			dst := record.NewRecordBuilder(schema)
+			rec, err := loc.ReadData(opt, dst)
+			require.NoError(t, err)
+			if rec == nil {
+				break
+			}
+			rows += rec.RowNums()
+		}
+		return rows, segs, decs
+	}
+
+	_, segs, decs := read("field1_int64::integer > 1000", record.MinMaxTimeRange)
+	require.True(t, segs > 1)
+	assert.Equal(t, int64(1), decs.SkippedChunks())
+	assert.Equal(t, int64(segs), decs.SkippedSegments())
+	decs.Release()
+
+	// only the segments overlapping the time range are read
+	tr := record.TimeRange{Min: int64(tm.min) + 35, Max: int64(tm.min) + 44}
+	rows, _, decs := read("field1_int64::integer >= 0", tr)
+	assert.True(t, rows > 0)
+	assert.Equal(t, int64(0), decs.SkippedChunks())
+	assert.True(t, decs.SkippedSegments() > 0)
+	decs.Release()
+
+	// field1_int64 starts in [0, 9] and increases by 1, the 100 rows are in segments of 16 rows and
+	// the first 52 rows are at most 60
+	rows, segs, decs = read("field1_int64::integer > 60", record.MinMaxTimeRange)
+	assert.True(t, rows > 0)
+	assert.Equal(t, int64(0), decs.SkippedChunks())
+	assert.True(t, decs.SkippedSegments() >= 3)
+	assert.True(t, decs.SkippedSegments() < int64(segs))
+	decs.Release()
+}
+
+func TestSegmentAggs_Unmarshal(t *testing.T) {
+	long := strings.Repeat("z", maxSegAggString+10)
+	segs := make([]record.ColVal, 3)
+	segs[0].AppendString("b")
+	segs[0].AppendStringNull()
+	segs[0].AppendString("a")
+	segs[1].AppendStringNull()
+	segs[2].AppendString("c")
+	segs[2].AppendString(long)
+
+	src := appendSegmentAggs([]byte{1, 2}, influx.Field_Type_String, segs)
+	_, ok := unmarshalSegmentAggs(src[2:], influx.Field_Type_String, 2)
+	require.False(t, ok)
+	stats, ok := unmarshalSegmentAggs(src[2:], influx.Field_Type_String, 3)
+	require.True(t, ok)
+
+	assert.Equal(t, "a", stats[0].minS)
+	assert.Equal(t, "b", stats[0].maxS)
+	assert.True(t, stats[0].nulls)
+	assert.Equal(t, int64(0), stats[1].count)
+	assert.Equal(t, "c", stats[2].minS)
+	assert.Equal(t, long[:maxSegAggString], stats[2].maxS)
+	assert.True(t, stats[2].maxTruncated)
+	assert.True(t, stats[2].mayMatch(influxql.EQ, &influxql.StringLiteral{Val: long}))
+	assert.False(t, stats[0].mayMatch(influxql.EQ, &influxql.StringLiteral{Val: "c"}))
+
+	// a truncated trailer is ignored
+	_, ok = unmarshalSegmentAggs(src[2:len(src)-1], influx.Field_Type_String, 3)
+	require.False(t, ok)
+
+	ints := make([]record.ColVal, 1)
+	ints[0].AppendInteger(5)
+	ints[0].AppendInteger(-3)
+	stats, ok = unmarshalSegmentAggs(appendSegmentAggs(nil, influx.Field_Type_Int, ints), influx.Field_Type_Int, 1)
+	require.True(t, ok)
+	assert.Equal(t, int64(-3), stats[0].minI)
+	assert.Equal(t, int64(5), stats[0].maxI)
+}
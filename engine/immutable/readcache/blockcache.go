@@ -73,6 +73,15 @@ func newBlockCache(sizeLimit int64) *blockCache {
 	return c
 }
 
+// resize changes the size limit of every block.
+func (c *blockCache) resize(sizeLimit int64) {
+	blockLimit := sizeLimit / int64(c.blockSize)
+	for i := 0; i < c.blockSize; i++ {
+		c.blocks[i].setLimitSize(blockLimit)
+	}
+	atomic.StoreInt64(&c.blockLimit, blockLimit)
+}
+
 // add a value to the cache. Returns true if an eviction occurred.
 func (c *blockCache) add(key string, value []byte, size int64) (evict bool) {
 	block := c.getBlockCache(key)
@@ -43,6 +43,8 @@ type mapLruCache interface {
 	refreshOldBuffer()
 
 	getUseSize() int64
+
+	setLimitSize(size int64)
 }
 
 // lruCache Cache is a thread-safe fixed size LRU cache.
@@ -214,6 +216,22 @@ func (L *lruCache) getUseSize() int64 {
 	return L.currBuffer.byteSize + L.oldBuffer.byteSize
 }
 
+// setLimitSize changes the size limit of the cache, the oldest pages are dropped
+// if they do not fit in the new limit.
+func (L *lruCache) setLimitSize(size int64) {
+	L.lock.Lock()
+	defer L.lock.Unlock()
+
+	L.limitSize = size
+	L.bufferSize = size / 2
+	if L.currBuffer.byteSize >= L.bufferSize {
+		L.refreshBuffer()
+	}
+	if L.oldBuffer.byteSize >= L.bufferSize {
+		L.oldBuffer.clearBuffer()
+	}
+}
+
 func (L *lruCache) refreshOldBuffer() {
 	L.lock.Lock()
 	L.refreshBuffer()
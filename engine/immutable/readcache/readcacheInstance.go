@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/openGemini/openGemini/lib/logger"
 	"go.uber.org/zap"
@@ -37,11 +38,17 @@ type ReadCacheInstance struct {
 	closed         chan int
 }
 
+// SetCacheLimitSize sets the total size of the read cache, the instance already created is resized.
 func SetCacheLimitSize(size int) {
+	mu.Lock()
+	defer mu.Unlock()
 	totalLimitSize = int64(size)
+	if readCacheInstance != nil {
+		readCacheInstance.Resize(totalLimitSize)
+	}
 }
 
-// GetReadCacheIns Get a single instance of readCache, if you want to change totalLimitSize, please use Resize method.
+// GetReadCacheIns Get a single instance of readCache, if you want to change totalLimitSize, please use SetCacheLimitSize.
 func GetReadCacheIns() *ReadCacheInstance {
 	if readCacheInstance == nil {
 		mu.Lock()
@@ -58,15 +65,10 @@ func (c *ReadCacheInstance) CreatCacheKey(filePath string, offset int64) string
 }
 
 func newCacheInstance(totalLimitSize int64, tempFactor float64) *ReadCacheInstance {
-	if totalLimitSize < cacheSizeMin {
-		totalLimitSize = cacheSizeMin
-	}
 	if tempFactor > 0.05 {
 		tempFactor = 0.05
 	}
-
-	tempSize := int64(float64(totalLimitSize) * tempFactor) // for compact buffer and direct index in memory struct
-	totalLimitSize = totalLimitSize - tempSize
+	totalLimitSize = cacheLimitSize(totalLimitSize, tempFactor)
 	cache := newBlockCache(totalLimitSize)
 	return &ReadCacheInstance{
 		cache:          cache,
@@ -76,6 +78,26 @@ func newCacheInstance(totalLimitSize int64, tempFactor float64) *ReadCacheInstan
 	}
 }
 
+// cacheLimitSize returns the size left for the cache pages
+func cacheLimitSize(totalLimitSize int64, tempFactor float64) int64 {
+	if totalLimitSize < cacheSizeMin {
+		totalLimitSize = cacheSizeMin
+	}
+	tempSize := int64(float64(totalLimitSize) * tempFactor) // for compact buffer and direct index in memory struct
+	return totalLimitSize - tempSize
+}
+
+// Resize changes the total size of the cache, the pages are evicted if the cache is shrunk.
+func (c *ReadCacheInstance) Resize(totalLimitSize int64) {
+	totalLimitSize = cacheLimitSize(totalLimitSize, c.tempFactor)
+	if totalLimitSize == atomic.LoadInt64(&c.totalLimitSize) {
+		return
+	}
+	logger.GetLogger().Info("resize read cache", zap.Int64("totalLimit", totalLimitSize))
+	c.cache.resize(totalLimitSize)
+	atomic.StoreInt64(&c.totalLimitSize, totalLimitSize)
+}
+
 func (c *ReadCacheInstance) Close() {
 	close(c.closed)
 }
@@ -422,3 +422,24 @@ func checkGetResultOrNil(t *testing.T, cacheIns *ReadCacheInstance, key string,
 	}
 	return page
 }
+
+// TestReadCacheResize test the pages not fitting in the new size are evicted
+func TestReadCacheResize(t *testing.T) {
+	cacheIns := newCacheInstance(cacheSizeMin, 0)
+	key := cacheIns.CreatCacheKey(path, int64(offset))
+	cacheIns.AddPage(key, data, size)
+
+	cacheIns.Resize(2 * cacheSizeMin)
+	if cacheIns.totalLimitSize != 2*cacheSizeMin || !cacheIns.Contains(key) {
+		t.Fatalf("page is evicted when the cache is extended")
+	}
+
+	block := cacheIns.cache.getBlockCache(key).(*lruCache)
+	block.setLimitSize(size)
+	if cacheIns.Contains(key) {
+		t.Fatalf("page is not evicted when the cache is shrunk")
+	}
+	if block.bufferSize != size/2 {
+		t.Fatalf("buffer size is %d, expect %d", block.bufferSize, size/2)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/openGemini/openGemini/lib/bufferpool"
 	"github.com/openGemini/openGemini/lib/numberenc"
 	"github.com/openGemini/openGemini/lib/record"
+	stats "github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 	"github.com/openGemini/openGemini/open_src/influx/influxql"
 	"github.com/openGemini/openGemini/open_src/influx/query"
 	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
@@ -712,11 +713,18 @@ func decodeColumnData(ref *record.Field, data []byte, col *record.ColVal, decs *
 	return appendColumnData(dataType, nilBitmap, bitmapOffset, encData, nilCount, col, decs)
 }
 
+var readStat = stats.NewReadStatistics()
+
 type Location struct {
 	decs   *ReadContext
 	r      TSSPFile
 	meta   *ChunkMeta
 	segPos int
+	// whether the chunk has been checked against the field condition by pre-aggregations
+	filtered bool
+	// checks the segments of the chunk against the field condition
+	agg  *preAggFilter
+	dict *dictFilter
 }
 
 func NewLocation(r TSSPFile, decs *ReadContext) *Location {
@@ -946,6 +954,8 @@ func (l *Location) readChunkMeta(id uint64, tr record.TimeRange) error {
 	}
 
 	l.meta = meta
+	l.filtered = false
+	l.agg = nil
 	if !l.decs.Ascending {
 		l.segPos = int(meta.segCount) - 1
 	}
@@ -1034,16 +1044,85 @@ func (l *Location) ReadData(filterOpts *FilterOptions, dst *record.Record) (*rec
 	return l.readData(filterOpts, dst)
 }
 
+// skipByPreAgg skips the rest of the chunk if no row satisfies the field condition according to
+// the pre-aggregated min/max of the columns
+func (l *Location) skipByPreAgg(filterOpts *FilterOptions, dst *record.Record) {
+	if l.filtered {
+		return
+	}
+	l.agg = nil
+	if l.isPreAggRead() || filterOpts == nil || filterOpts.cond == nil || len(filterOpts.fieldsIdx) == 0 {
+		return
+	}
+	l.filtered = true
+
+	if l.decs.preAggBuilders == nil {
+		l.decs.preAggBuilders = newPreAggBuilders()
+	}
+	f := newPreAggFilter(l.meta, l.decs.preAggBuilders, dst.Schema, filterOpts.fieldsIdx)
+	if f.mayMatch(filterOpts.cond) {
+		l.agg = f
+		return
+	}
+
+	var segs int
+	if l.decs.Ascending {
+		segs = int(l.meta.segCount) - l.segPos
+		l.segPos = int(l.meta.segCount)
+	} else {
+		segs = l.segPos + 1
+		l.segPos = -1
+	}
+	l.decs.skippedChunks++
+	l.decs.skippedSegments += int64(segs)
+	readStat.AddSkippedChunks(1)
+	readStat.AddSkippedSegments(int64(segs))
+}
+
+// segmentMayMatch checks the field condition against the min/max of the current segment, then the
+// equality conditions on string fields against its dictionaries
+func (l *Location) segmentMayMatch(filterOpts *FilterOptions, dst *record.Record) bool {
+	if l.isPreAggRead() || filterOpts == nil || filterOpts.cond == nil || len(filterOpts.fieldsIdx) == 0 {
+		return true
+	}
+	if l.agg != nil && !l.agg.segmentMayMatch(filterOpts.cond, l.segPos) {
+		return false
+	}
+	if l.dict == nil || l.dict.opts != filterOpts {
+		l.dict = newDictFilter(filterOpts, dst.Schema)
+	}
+	return l.dict.segmentMayMatch(l.r, l.meta, l.segPos)
+}
+
+// segmentOverlaps checks the time range of the current segment
+func (l *Location) segmentOverlaps() bool {
+	if l.isPreAggRead() || l.segPos >= len(l.meta.timeRange) {
+		return true
+	}
+	sr := &l.meta.timeRange[l.segPos]
+	return l.decs.tr.Overlaps(sr.minTime(), sr.maxTime())
+}
+
 func (l *Location) readData(filterOpts *FilterOptions, dst *record.Record) (*record.Record, error) {
 	var rec *record.Record
 	var err error
+	if l.next() {
+		l.skipByPreAgg(filterOpts, dst)
+	}
 	for rec == nil && l.next() {
 		if !l.decs.tr.Overlaps(l.meta.MinMaxTime()) {
 			l.nextSegment()
 			continue
 		}
 
-		rec, err = l.r.ReadAt(l.meta, l.segPos, dst, l.decs)
+		if !l.segmentOverlaps() || !l.segmentMayMatch(filterOpts, dst) {
+			l.decs.skippedSegments++
+			readStat.AddSkippedSegments(1)
+			l.nextSegment()
+			continue
+		}
+
+		rec, err = l.readSegment(dst)
 		if err != nil {
 			return nil, err
 		}
@@ -1070,6 +1149,32 @@ func (l *Location) readData(filterOpts *FilterOptions, dst *record.Record) (*rec
 	return rec, nil
 }
 
+// readSegment reads the current segment, only the rows selected by the dictionaries are kept
+// and expanded to strings
+func (l *Location) readSegment(dst *record.Record) (*record.Record, error) {
+	var mask []bool
+	if l.dict != nil && !l.isPreAggRead() {
+		mask = l.dict.mask
+	}
+	if mask == nil {
+		return l.r.ReadAt(l.meta, l.segPos, dst, l.decs)
+	}
+
+	l.decs.coderCtx.rowMask = mask
+	rec, err := l.r.ReadAt(l.meta, l.segPos, dst, l.decs)
+	l.decs.coderCtx.rowMask = nil
+	if err != nil || rec == nil {
+		return rec, err
+	}
+	if rec.RowNums() != len(mask) {
+		return nil, fmt.Errorf("segment %d of %s has %d rows, the dictionaries have %d", l.segPos,
+			l.r.Path(), rec.RowNums(), len(mask))
+	}
+	skipped := len(mask) - countRows(mask)
+	l.decs.skippedRows += int64(skipped)
+	return filterRows(rec, mask, l.decs.Ascending), nil
+}
+
 func (l *Location) readMeta(filterOpts *FilterOptions, dst *record.Record) (*record.Record, error) {
 	if l.decs.preAggBuilders == nil {
 		l.decs.preAggBuilders = newPreAggBuilders()
@@ -1080,6 +1185,8 @@ func (l *Location) readMeta(filterOpts *FilterOptions, dst *record.Record) (*rec
 
 func (l *Location) ResetMeta() {
 	l.segPos = 0
+	l.filtered = false
+	l.agg = nil
 	if l.GetChunkMeta() != nil {
 		l.GetChunkMeta().Reset()
 	}
@@ -1284,6 +1391,12 @@ type ReadContext struct {
 	origData        []byte
 
 	readBuf []byte
+
+	// chunks and segments skipped without decoding
+	skippedChunks   int64
+	skippedSegments int64
+	// rows dropped by the dictionaries before they are expanded
+	skippedRows int64
 }
 
 func NewReadContext(ascending bool) *ReadContext {
@@ -1323,6 +1436,22 @@ func (d *ReadContext) Set(ascending bool, tr record.TimeRange, onlyFirstOrLast b
 
 func (d *ReadContext) Reset() {}
 
+// SkippedChunks returns the number of chunks skipped by the pre-aggregations
+func (d *ReadContext) SkippedChunks() int64 {
+	return d.skippedChunks
+}
+
+// SkippedSegments returns the number of segments skipped by the pre-aggregations or the time range
+func (d *ReadContext) SkippedSegments() int64 {
+	return d.skippedSegments
+}
+
+// SkippedRows returns the number of rows dropped by the dictionaries of string fields before they
+// are expanded
+func (d *ReadContext) SkippedRows() int64 {
+	return d.skippedRows
+}
+
 func (d *ReadContext) Release() {
 	if d.coderCtx != nil {
 		d.coderCtx.Release()
@@ -0,0 +1,225 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/openGemini/openGemini/lib/numberenc"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// The pre-aggregation of a column in a chunk may be followed by the min/max of the values of each
+// segment. The readers of the older versions only read the pre-aggregation of the chunk and ignore them:
+//
+//	pre-aggregation of the chunk | segAggMagic | segment count (uvarint) | segment 0 | segment 1 ...
+//
+// A segment is its flags then, if it has values, its min and max: 8 bytes each for the numbers, a byte
+// each for the booleans and a uvarint length and the bytes for the strings. A long string is truncated
+// to maxSegAggString bytes, the max is flagged as truncated.
+const (
+	segAggMagic = 0xA5
+
+	segAggValues       = 1 << 0
+	segAggNulls        = 1 << 1
+	segAggMaxTruncated = 1 << 2
+
+	maxSegAggString = 64
+	// the segments of the chunks with more segments are not kept, the pre-aggregation length is an uint16
+	maxSegAggSize = 16 * 1024
+)
+
+// appendSegmentAggs appends the min/max of the segments of a column to its pre-aggregation
+func appendSegmentAggs(dst []byte, ty int, segCols []record.ColVal) []byte {
+	start := len(dst)
+	dst = append(dst, segAggMagic)
+	dst = appendUvarint(dst, uint64(len(segCols)))
+	for i := range segCols {
+		dst = appendSegmentAgg(dst, ty, &segCols[i])
+		if len(dst)-start > maxSegAggSize {
+			return dst[:start]
+		}
+	}
+	return dst
+}
+
+func appendSegmentAgg(dst []byte, ty int, col *record.ColVal) []byte {
+	var flags byte
+	if col.NullN() > 0 {
+		flags |= segAggNulls
+	}
+	if col.NullN() == col.Len {
+		return append(dst, flags)
+	}
+	flags |= segAggValues
+
+	switch ty {
+	case influx.Field_Type_Int:
+		values := col.IntegerValues()
+		min, max := values[0], values[0]
+		for _, v := range values[1:] {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		dst = append(dst, flags)
+		dst = numberenc.MarshalInt64Append(dst, min)
+		dst = numberenc.MarshalInt64Append(dst, max)
+	case influx.Field_Type_Float:
+		// NaN is out of any range
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, v := range col.FloatValues() {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		dst = append(dst, flags)
+		dst = numberenc.MarshalUint64Append(dst, math.Float64bits(min))
+		dst = numberenc.MarshalUint64Append(dst, math.Float64bits(max))
+	case influx.Field_Type_Boolean:
+		min, max := true, false
+		for _, v := range col.BooleanValues() {
+			min = min && v
+			max = max || v
+		}
+		dst = append(dst, flags, boolByte(min), boolByte(max))
+	case influx.Field_Type_String:
+		var min, max string
+		found := false
+		for i := 0; i < col.Len; i++ {
+			v, isNil := col.StringValueUnsafe(i)
+			if isNil {
+				continue
+			}
+			if !found || v < min {
+				min = v
+			}
+			if !found || v > max {
+				max = v
+			}
+			found = true
+		}
+		if len(min) > maxSegAggString {
+			min = min[:maxSegAggString]
+		}
+		if len(max) > maxSegAggString {
+			max = max[:maxSegAggString]
+			flags |= segAggMaxTruncated
+		}
+		dst = append(dst, flags)
+		dst = appendUvarint(dst, uint64(len(min)))
+		dst = append(dst, min...)
+		dst = appendUvarint(dst, uint64(len(max)))
+		dst = append(dst, max...)
+	default:
+		// no min/max for the other types
+		dst = append(dst, flags&^segAggValues)
+	}
+	return dst
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func appendUvarint(dst []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(dst, buf[:n]...)
+}
+
+// unmarshalSegmentAggs returns the statistics of the segments following the pre-aggregation of a
+// column, ok is false if the column has none or they are not the ones of segs segments.
+func unmarshalSegmentAggs(src []byte, ty int, segs int) ([]preAggColumnStat, bool) {
+	if len(src) == 0 || src[0] != segAggMagic {
+		return nil, false
+	}
+	src = src[1:]
+	n, l := binary.Uvarint(src)
+	if l <= 0 || int(n) != segs {
+		return nil, false
+	}
+	src = src[l:]
+
+	stats := make([]preAggColumnStat, segs)
+	for i := range stats {
+		if len(src) == 0 {
+			return nil, false
+		}
+		s := &stats[i]
+		flags := src[0]
+		src = src[1:]
+		s.ty = ty
+		s.nulls = flags&segAggNulls != 0
+		if flags&segAggValues == 0 {
+			continue
+		}
+		s.count = 1
+		s.maxTruncated = flags&segAggMaxTruncated != 0
+
+		switch ty {
+		case influx.Field_Type_Int, influx.Field_Type_Float:
+			if len(src) < 16 {
+				return nil, false
+			}
+			if ty == influx.Field_Type_Int {
+				s.minI, s.maxI = numberenc.UnmarshalInt64(src), numberenc.UnmarshalInt64(src[8:])
+			} else {
+				s.minF = math.Float64frombits(numberenc.UnmarshalUint64(src))
+				s.maxF = math.Float64frombits(numberenc.UnmarshalUint64(src[8:]))
+			}
+			src = src[16:]
+		case influx.Field_Type_Boolean:
+			if len(src) < 2 {
+				return nil, false
+			}
+			s.minB, s.maxB = src[0] == 1, src[1] == 1
+			src = src[2:]
+		case influx.Field_Type_String:
+			var ok bool
+			if s.minS, src, ok = unmarshalSegAggString(src); !ok {
+				return nil, false
+			}
+			if s.maxS, src, ok = unmarshalSegAggString(src); !ok {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return stats, true
+}
+
+func unmarshalSegAggString(src []byte) (string, []byte, bool) {
+	n, l := binary.Uvarint(src)
+	if l <= 0 || uint64(len(src)-l) < n {
+		return "", nil, false
+	}
+	src = src[l:]
+	return string(src[:n]), src[n:], true
+}
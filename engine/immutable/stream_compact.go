@@ -519,6 +519,7 @@ func (c *StreamIterators) Close() {
 	c.schemaMap.Reset()
 	c.fileSize = 0
 	c.colBuilder.resetPreAgg()
+	c.colBuilder.coder.floatStats = nil
 	putStreamIterators(c)
 }
 
@@ -711,6 +712,8 @@ func (m *MmsTables) streamCompactToLevel(group FilesInfo, full bool) error {
 	}
 
 	compItrs.WithLog(lcLog)
+	floatStats := &FloatCodecStats{}
+	compItrs.colBuilder.coder.floatStats = floatStats
 	oldFilesSize := compItrs.estimateSize
 	newFiles, err := compItrs.compact(group.oldFiles, group.toLevel, true)
 	if err != nil {
@@ -733,6 +736,7 @@ func (m *MmsTables) streamCompactToLevel(group FilesInfo, full bool) error {
 		compactStatItem.CompactedFileCount = int64(len(newFiles))
 		compactStatItem.OriginalFileSize = int64(oldFilesSize)
 		compactStatItem.CompactedFileSize = sumFilesSize(newFiles)
+		compactStatItem.Codecs = floatStats.statItems(compactStatItem.Codecs)
 	}
 	return nil
 }
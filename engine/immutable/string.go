@@ -28,6 +28,8 @@ const (
 	stringUncompressed     = 0
 	stringCompressedSnappy = 1
 	StringCompressedZstd   = 2
+	// stringCompressedDict is a dictionary of the distinct values and the bit packed code of each row
+	stringCompressedDict = 3
 
 	minCompReta = 0.85
 )
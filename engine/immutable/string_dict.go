@@ -0,0 +1,225 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/openGemini/openGemini/lib/numberenc"
+)
+
+const (
+	// stringDictMaxSize is the most distinct values of a dictionary encoded block, the codes fit in one byte
+	stringDictMaxSize = 256
+	// every dictionary value must repeat this many times on average for the block to be dictionary encoded
+	stringDictMinRepeat = 2
+)
+
+// stringDictBuilder dictionary encodes a string block whose distinct values are few.
+// The encoded block is:
+//
+//	type | rows uint32 | count uint32 | count * (length uint32 | value) | code width | bit packed codes
+type stringDictBuilder struct {
+	index  map[string]uint32
+	values [][]byte
+	codes  []uint32
+}
+
+func newStringDictBuilder() *stringDictBuilder {
+	return &stringDictBuilder{index: make(map[string]uint32, stringDictMaxSize)}
+}
+
+func (b *stringDictBuilder) reset() {
+	for k := range b.index {
+		delete(b.index, k)
+	}
+	b.values = b.values[:0]
+	b.codes = b.codes[:0]
+}
+
+// build returns false if the values are too many distinct to be dictionary encoded
+func (b *stringDictBuilder) build(in []byte, offset []uint32) bool {
+	b.reset()
+
+	limit := len(offset) / stringDictMinRepeat
+	if limit > stringDictMaxSize {
+		limit = stringDictMaxSize
+	}
+
+	for i := range offset {
+		end := uint32(len(in))
+		if i < len(offset)-1 {
+			end = offset[i+1]
+		}
+		v := in[offset[i]:end]
+
+		code, ok := b.index[string(v)]
+		if !ok {
+			if len(b.values) >= limit {
+				return false
+			}
+			code = uint32(len(b.values))
+			b.index[string(v)] = code
+			b.values = append(b.values, v)
+		}
+		b.codes = append(b.codes, code)
+	}
+	return true
+}
+
+func (b *stringDictBuilder) marshal(out []byte) []byte {
+	out = append(out, byte(stringCompressedDict<<4))
+	out = numberenc.MarshalUint32Append(out, uint32(len(b.codes)))
+	out = numberenc.MarshalUint32Append(out, uint32(len(b.values)))
+	for _, v := range b.values {
+		out = numberenc.MarshalUint32Append(out, uint32(len(v)))
+		out = append(out, v...)
+	}
+
+	width := bits.Len32(uint32(len(b.values) - 1))
+	out = append(out, byte(width))
+	if width == 0 {
+		return out
+	}
+
+	pos := len(out)
+	n := (len(b.codes)*width + 7) / 8
+	for i := 0; i < n; i++ {
+		out = append(out, 0)
+	}
+	for i, code := range b.codes {
+		bit := i * width
+		idx, shift := pos+bit/8, uint(bit%8)
+		out[idx] |= byte(code << shift)
+		if int(shift)+width > 8 {
+			out[idx+1] |= byte(code >> (8 - shift))
+		}
+	}
+	return out
+}
+
+// stringDict is a decoded dictionary block, the values and codes refer to the encoded data.
+// Every value of the dictionary is the value of some row, so equality with a literal can be
+// evaluated on the dictionary without expanding the rows to strings.
+type stringDict struct {
+	rows   int
+	width  int
+	values [][]byte
+	codes  []byte
+}
+
+func isStringDictBlock(in []byte) bool {
+	return len(in) > 0 && int(in[0]>>4) == stringCompressedDict
+}
+
+func (d *stringDict) unmarshal(in []byte) error {
+	if len(in) < 9 {
+		return fmt.Errorf("too small data for string dictionary, %v", len(in))
+	}
+	in = in[1:]
+	d.rows = int(numberenc.UnmarshalUint32(in))
+	count := int(numberenc.UnmarshalUint32(in[4:]))
+	in = in[8:]
+	if count == 0 || count > stringDictMaxSize {
+		return fmt.Errorf("invalid string dictionary size %v", count)
+	}
+
+	d.values = d.values[:0]
+	for i := 0; i < count; i++ {
+		if len(in) < 4 {
+			return fmt.Errorf("too small data for string dictionary value length, %v", len(in))
+		}
+		l := int(numberenc.UnmarshalUint32(in))
+		in = in[4:]
+		if len(in) < l {
+			return fmt.Errorf("too small data for string dictionary value, %v < %v", len(in), l)
+		}
+		d.values = append(d.values, in[:l])
+		in = in[l:]
+	}
+
+	if len(in) < 1 {
+		return fmt.Errorf("too small data for string dictionary code width")
+	}
+	d.width, in = int(in[0]), in[1:]
+	if d.width > 8 || count > 1<<d.width {
+		return fmt.Errorf("invalid string dictionary code width %v for %v values", d.width, count)
+	}
+
+	n := (d.rows*d.width + 7) / 8
+	if len(in) < n {
+		return fmt.Errorf("too small data for string dictionary codes, %v < %v", len(in), n)
+	}
+	d.codes = in[:n]
+	return nil
+}
+
+func (d *stringDict) code(i int) int {
+	if d.width == 0 {
+		return 0
+	}
+	bit := i * d.width
+	idx, shift := bit/8, uint(bit%8)
+	v := uint16(d.codes[idx])
+	if int(shift)+d.width > 8 {
+		v |= uint16(d.codes[idx+1]) << 8
+	}
+	return int(v>>shift) & (1<<d.width - 1)
+}
+
+// lookup returns the code of the value, or -1 if no row of the block has it
+func (d *stringDict) lookup(v string) int {
+	for i := range d.values {
+		if string(d.values[i]) == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// expand appends the values of the rows to out, the offsets start from the first appended value
+func (d *stringDict) expand(out []byte, offset []uint32) ([]byte, []uint32, error) {
+	start := len(out)
+	for i := 0; i < d.rows; i++ {
+		c := d.code(i)
+		if c >= len(d.values) {
+			return nil, nil, fmt.Errorf("invalid string dictionary code %v of %v values", c, len(d.values))
+		}
+		offset = append(offset, uint32(len(out)-start))
+		out = append(out, d.values[c]...)
+	}
+	return out, offset, nil
+}
+
+// expandSelected appends the values of the rows selected by the mask to out, the other rows are
+// appended empty so that the rows still line up with the other columns of the segment
+func (d *stringDict) expandSelected(out []byte, offset []uint32, mask []bool) ([]byte, []uint32, error) {
+	start := len(out)
+	for i := 0; i < d.rows; i++ {
+		offset = append(offset, uint32(len(out)-start))
+		if !mask[i] {
+			continue
+		}
+		c := d.code(i)
+		if c >= len(d.values) {
+			return nil, nil, fmt.Errorf("invalid string dictionary code %v of %v values", c, len(d.values))
+		}
+		out = append(out, d.values[c]...)
+	}
+	return out, offset, nil
+}
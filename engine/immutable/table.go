@@ -209,6 +209,7 @@ func (t *Trailer) copyTo(tr *Trailer) {
 	tr.indexSize = t.indexSize
 	tr.metaIndexSize = t.metaIndexSize
 	tr.bloomSize = t.bloomSize
+	tr.idTimeSize = t.idTimeSize
 	tr.idCount = t.idCount
 	tr.minId, tr.maxId = t.minId, t.maxId
 	tr.minTime, tr.maxTime = t.minTime, t.maxTime
@@ -394,6 +395,7 @@ func (b *ColumnBuilder) encIntegerColumn(timeCols []record.ColVal, segCols []rec
 	}
 
 	b.colMeta.preAgg = b.intPreAggBuilder.marshal(b.colMeta.preAgg[:0])
+	b.colMeta.preAgg = appendSegmentAggs(b.colMeta.preAgg, influx.Field_Type_Int, segCols)
 
 	return err
 }
@@ -438,6 +440,7 @@ func (b *ColumnBuilder) encFloatColumn(timeCols []record.ColVal, segCols []recor
 	}
 
 	b.colMeta.preAgg = b.floatPreAggBuilder.marshal(b.colMeta.preAgg[:0])
+	b.colMeta.preAgg = appendSegmentAggs(b.colMeta.preAgg, influx.Field_Type_Float, segCols)
 
 	return err
 }
@@ -482,6 +485,7 @@ func (b *ColumnBuilder) encStringColumn(timeCols []record.ColVal, segCols []reco
 	}
 
 	b.colMeta.preAgg = b.stringPreAggBuilder.marshal(b.colMeta.preAgg[:0])
+	b.colMeta.preAgg = appendSegmentAggs(b.colMeta.preAgg, influx.Field_Type_String, segCols)
 	return err
 }
 
@@ -527,6 +531,7 @@ func (b *ColumnBuilder) encBooleanColumn(timeCols []record.ColVal, segCols []rec
 	}
 
 	b.colMeta.preAgg = b.boolPreAggBuilder.marshal(b.colMeta.preAgg[:0])
+	b.colMeta.preAgg = appendSegmentAggs(b.colMeta.preAgg, influx.Field_Type_Boolean, segCols)
 	return err
 }
 
@@ -862,6 +867,20 @@ func (b *MsBuilder) WithLog(log *Log.Logger) {
 	}
 }
 
+// WithFloatCodecStats makes the builder account the float blocks it encodes per codec
+func (b *MsBuilder) WithFloatCodecStats(stats *FloatCodecStats) {
+	if b.chunkBuilder != nil && b.chunkBuilder.colBuilder != nil {
+		b.chunkBuilder.colBuilder.coder.floatStats = stats
+	}
+}
+
+func (b *MsBuilder) floatCodecStats() *FloatCodecStats {
+	if b.chunkBuilder != nil && b.chunkBuilder.colBuilder != nil {
+		return b.chunkBuilder.colBuilder.coder.floatStats
+	}
+	return nil
+}
+
 func (b *MsBuilder) Reset() {
 	b.reset()
 
@@ -989,6 +1008,7 @@ func (b *MsBuilder) WriteRecord(id uint64, data *record.Record, nextFile func(fn
 			builder := AllocMsBuilder(msb.Path, msb.Name(), msb.Conf, n, msb.FileName, msb.tier, msb.sequencer, recs[i].Len())
 			builder.Files = append(builder.Files, msb.Files...)
 			builder.WithLog(msb.log)
+			builder.WithFloatCodecStats(msb.floatCodecStats())
 			PutMsBuilder(msb)
 			msb = builder
 		}
@@ -456,7 +456,7 @@ func (r *TSSPFileReader) ReadMetaBlock(metaIdx int, id uint64, offset int64, siz
 		return nil, err
 	}
 
-	if readCacheEn {
+	if readCacheEnabled() {
 		rb, err = r.GetTSSPFileBytes(offset, size, dst)
 	} else {
 		rb, err = r.r.ReadAt(offset, size, dst)
@@ -474,7 +474,7 @@ func (r *TSSPFileReader) ReadDataBlock(offset int64, size uint32, dst *[]byte) (
 		return r.inMemBlock.ReadDataBlock(offset, size, dst)
 	}
 
-	if readCacheEn {
+	if readCacheEnabled() {
 		rb, err = r.GetTSSPFileBytes(offset, size, dst)
 	} else {
 		rb, err = r.r.ReadAt(offset, size, dst)
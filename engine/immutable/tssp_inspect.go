@@ -0,0 +1,342 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"fmt"
+
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// FileSummary is the trailer of a tssp file
+type FileSummary struct {
+	Name        string
+	Measurement string
+	Version     uint64
+	FileSize    int64
+
+	DataOffset    int64
+	DataSize      int64
+	IndexSize     int64
+	MetaIndexSize int64
+	BloomSize     int64
+	IdTimeSize    int64
+
+	SeriesCount    int64
+	MinSeriesID    uint64
+	MaxSeriesID    uint64
+	MinTime        int64
+	MaxTime        int64
+	MetaIndexCount int64
+	BloomM         uint64
+	BloomK         uint64
+}
+
+// MetaIndexInfo describes a block of chunk metas
+type MetaIndexInfo struct {
+	// the series id of the first chunk meta in the block
+	SeriesID   uint64
+	MinTime    int64
+	MaxTime    int64
+	Offset     int64
+	Size       uint32
+	ChunkCount uint32
+}
+
+// PreAggInfo is the pre-aggregation of a column in a chunk
+type PreAggInfo struct {
+	Count   int64
+	Min     interface{}
+	MinTime int64
+	Max     interface{}
+	MaxTime int64
+	Sum     interface{}
+}
+
+// ColumnInfo describes a column of a chunk
+type ColumnInfo struct {
+	Name   string
+	Type   int
+	PreAgg PreAggInfo
+}
+
+// ChunkMetaInfo describes all the data of a series in a tssp file
+type ChunkMetaInfo struct {
+	SeriesID uint64
+	Offset   int64
+	Size     uint32
+	MinTime  int64
+	MaxTime  int64
+	Rows     int64
+	Segments []record.TimeRange
+	Columns  []ColumnInfo
+}
+
+// VerifyResult is the result of FileInspector.Verify
+type VerifyResult struct {
+	Series   int
+	Segments int
+	Rows     int64
+	Errors   []error
+}
+
+// FileInspector reads the structure of a tssp file for offline troubleshooting.
+// It never modifies the file.
+type FileInspector struct {
+	name   string
+	reader *TSSPFileReader
+	decs   *ReadContext
+}
+
+func NewFileInspector(name string) (*FileInspector, error) {
+	// NewTSSPFileReader removes files that are too small, which is unexpected for an inspection
+	fi, err := fileops.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < minTableSize() {
+		return nil, fmt.Errorf("invalid file(%v) size:%v", name, fi.Size())
+	}
+
+	r, err := NewTSSPFileReader(name)
+	if err != nil {
+		return nil, err
+	}
+	r.inMemBlock = emptyMemReader
+	if err = r.LoadIndex(); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+
+	return &FileInspector{
+		name:   name,
+		reader: r,
+		decs:   NewReadContext(true),
+	}, nil
+}
+
+func (fi *FileInspector) Close() error {
+	fi.decs.Release()
+	return fi.reader.Close()
+}
+
+func (fi *FileInspector) Summary() *FileSummary {
+	tr := &fi.reader.trailer
+	return &FileSummary{
+		Name:           fi.name,
+		Measurement:    string(tr.name),
+		Version:        fi.reader.version,
+		FileSize:       fi.reader.fileSize,
+		DataOffset:     tr.dataOffset,
+		DataSize:       tr.dataSize,
+		IndexSize:      tr.indexSize,
+		MetaIndexSize:  tr.metaIndexSize,
+		BloomSize:      tr.bloomSize,
+		IdTimeSize:     tr.idTimeSize,
+		SeriesCount:    tr.idCount,
+		MinSeriesID:    tr.minId,
+		MaxSeriesID:    tr.maxId,
+		MinTime:        tr.minTime,
+		MaxTime:        tr.maxTime,
+		MetaIndexCount: tr.metaIndexItemNum,
+		BloomM:         tr.bloomM,
+		BloomK:         tr.bloomK,
+	}
+}
+
+func (fi *FileInspector) MetaIndexes() []MetaIndexInfo {
+	items := fi.reader.metaIndexItems
+	infos := make([]MetaIndexInfo, 0, len(items))
+	for i := range items {
+		m := &items[i]
+		infos = append(infos, MetaIndexInfo{
+			SeriesID:   m.id,
+			MinTime:    m.minTime,
+			MaxTime:    m.maxTime,
+			Offset:     m.offset,
+			Size:       m.size,
+			ChunkCount: m.count,
+		})
+	}
+	return infos
+}
+
+// BloomContains reports whether the bloom filter of the file may contain the series
+func (fi *FileInspector) BloomContains(sid uint64) bool {
+	return fi.reader.ContainsId(sid)
+}
+
+// WalkChunkMetas calls fn for each chunk meta of the file in series id order
+func (fi *FileInspector) WalkChunkMetas(fn func(metaIdx int, cm *ChunkMeta) error) error {
+	var cms []ChunkMeta
+	var err error
+	for i := range fi.reader.metaIndexItems {
+		cms, err = fi.reader.ReadChunkMetaData(i, &fi.reader.metaIndexItems[i], cms[:0])
+		if err != nil {
+			return err
+		}
+		for j := range cms {
+			if err = fn(i, &cms[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ChunkMetaInfo decodes the chunk meta, including the pre-aggregation of each column
+func (fi *FileInspector) ChunkMetaInfo(cm *ChunkMeta) (*ChunkMetaInfo, error) {
+	info := &ChunkMetaInfo{
+		SeriesID: cm.sid,
+		Offset:   cm.offset,
+		Size:     cm.size,
+		Segments: make([]record.TimeRange, 0, cm.segmentCount()),
+		Columns:  make([]ColumnInfo, 0, len(cm.colMeta)),
+	}
+	info.MinTime, info.MaxTime = cm.MinMaxTime()
+	for i := 0; i < cm.segmentCount(); i++ {
+		sr := &cm.timeRange[i]
+		info.Segments = append(info.Segments, record.TimeRange{Min: sr.minTime(), Max: sr.maxTime()})
+	}
+
+	for i := range cm.colMeta {
+		col := &cm.colMeta[i]
+		ref := record.Field{Name: col.name, Type: int(col.ty)}
+		ci := ColumnInfo{Name: col.name, Type: ref.Type}
+
+		ab := fi.decs.preAggBuilders.aggBuilder(&ref)
+		ab.reset()
+		if _, err := ab.unmarshal(col.preAgg); err != nil {
+			return nil, fmt.Errorf("unmarshal pre-agg of column %s failed, sid: %d, %v", col.name, cm.sid, err)
+		}
+		ci.PreAgg.Count = ab.count()
+		if ref.Name != record.TimeField && ref.Type != influx.Field_Type_String {
+			ci.PreAgg.Min, ci.PreAgg.MinTime = ab.min()
+			ci.PreAgg.Max, ci.PreAgg.MaxTime = ab.max()
+			ci.PreAgg.Sum = ab.sum()
+		}
+		if ref.Name == record.TimeField {
+			info.Rows = ci.PreAgg.Count
+		}
+		info.Columns = append(info.Columns, ci)
+	}
+	return info, nil
+}
+
+// ReadChunk decodes each segment of the chunk and calls fn with the records.
+// The record is reused between segments.
+func (fi *FileInspector) ReadChunk(cm *ChunkMeta, fn func(segment int, rec *record.Record) error) error {
+	schema := unmarshalBlockHeader(cm, nil)
+	rec := record.NewRecordBuilder(schema)
+	for i := 0; i < cm.segmentCount(); i++ {
+		rec.ResetForReuse()
+		dst, err := fi.reader.ReadData(cm, i, rec, fi.decs)
+		if err != nil {
+			return fmt.Errorf("read segment %d of series %d failed, %v", i, cm.sid, err)
+		}
+		if dst == nil {
+			continue
+		}
+		if err = fn(i, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSeries decodes all data of the series, the file is searched by the meta index
+func (fi *FileInspector) ReadSeries(sid uint64, fn func(rec *record.Record) error) error {
+	idx, m, err := fi.reader.MetaIndex(sid, record.MinMaxTimeRange)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return nil
+	}
+
+	cm, err := fi.reader.ChunkMeta(sid, m.offset, m.size, m.count, idx, nil)
+	if err != nil || cm == nil {
+		return err
+	}
+	return fi.ReadChunk(cm, func(_ int, rec *record.Record) error {
+		return fn(rec)
+	})
+}
+
+// Verify checks the consistency of the meta data and decodes every segment of the file.
+// Problems are collected in the result instead of stopping the verification.
+func (fi *FileInspector) Verify() *VerifyResult {
+	res := &VerifyResult{}
+	tr := &fi.reader.trailer
+	addErr := func(format string, a ...interface{}) {
+		res.Errors = append(res.Errors, fmt.Errorf(format, a...))
+	}
+
+	var lastSid uint64
+	err := fi.WalkChunkMetas(func(metaIdx int, cm *ChunkMeta) error {
+		res.Series++
+		if res.Series > 1 && cm.sid <= lastSid {
+			addErr("series %d is not in order, previous: %d", cm.sid, lastSid)
+		}
+		lastSid = cm.sid
+		if !tr.ContainsId(cm.sid) {
+			addErr("series %d is out of the range [%d, %d] of the trailer", cm.sid, tr.minId, tr.maxId)
+		}
+		if !fi.reader.bloom.Contains(record.Uint64ToBytes(cm.sid)) {
+			addErr("series %d is missing in the bloom filter", cm.sid)
+		}
+
+		min, max := cm.MinMaxTime()
+		m := &fi.reader.metaIndexItems[metaIdx]
+		if min < m.minTime || max > m.maxTime {
+			addErr("time range [%d, %d] of series %d is out of the meta index %d [%d, %d]",
+				min, max, cm.sid, metaIdx, m.minTime, m.maxTime)
+		}
+
+		info, err := fi.ChunkMetaInfo(cm)
+		if err != nil {
+			res.Errors = append(res.Errors, err)
+			return nil
+		}
+
+		var rows int64
+		err = fi.ReadChunk(cm, func(segment int, rec *record.Record) error {
+			res.Segments++
+			rows += int64(rec.RowNums())
+			return nil
+		})
+		if err != nil {
+			res.Errors = append(res.Errors, err)
+			return nil
+		}
+		res.Rows += rows
+		if rows != info.Rows {
+			addErr("series %d has %d rows, but the pre-agg count is %d", cm.sid, rows, info.Rows)
+		}
+		return nil
+	})
+	if err != nil {
+		res.Errors = append(res.Errors, err)
+	}
+
+	if int64(res.Series) != tr.idCount {
+		addErr("%d series are read, but the trailer has %d", res.Series, tr.idCount)
+	}
+	return res
+}
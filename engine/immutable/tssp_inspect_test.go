@@ -0,0 +1,107 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileInspector(t *testing.T) {
+	dir := t.TempDir()
+	conf := NewConfig()
+	tier := uint64(meta.Hot)
+	store := NewTableStore(dir, &tier, false, conf)
+	defer store.Close()
+
+	var idMinMax, tmMinMax MinMax
+	ids, data := genMemTableData(1, 10, 100, &idMinMax, &tmMinMax)
+	fileName := NewTSSPFileName(1, 0, 0, 0, true)
+	msb := AllocMsBuilder(dir, "mst", conf, 10, fileName, 0, store.Sequencer(), 2)
+	for _, id := range ids {
+		require.NoError(t, msb.WriteData(id, data[id]))
+	}
+	store.AddTable(msb, true, false)
+
+	fs := store.tableFiles("mst", true)
+	require.Equal(t, 1, fs.Len())
+
+	fi, err := NewFileInspector(fs.Files()[0].Path())
+	require.NoError(t, err)
+	defer fi.Close()
+
+	summary := fi.Summary()
+	assert.Equal(t, "mst", summary.Measurement)
+	assert.Equal(t, int64(10), summary.SeriesCount)
+	assert.Equal(t, idMinMax.min, summary.MinSeriesID)
+	assert.Equal(t, idMinMax.max, summary.MaxSeriesID)
+	assert.Equal(t, int64(tmMinMax.min), summary.MinTime)
+	assert.Equal(t, int64(tmMinMax.max), summary.MaxTime)
+	assert.Equal(t, int(summary.MetaIndexCount), len(fi.MetaIndexes()))
+	assert.True(t, fi.BloomContains(ids[0]))
+
+	var infos []*ChunkMetaInfo
+	err = fi.WalkChunkMetas(func(_ int, cm *ChunkMeta) error {
+		info, err := fi.ChunkMetaInfo(cm)
+		if err != nil {
+			return err
+		}
+		infos = append(infos, info)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(ids), len(infos))
+	for i, info := range infos {
+		assert.Equal(t, ids[i], info.SeriesID)
+		assert.Equal(t, int64(100), info.Rows)
+		assert.Equal(t, 5, len(info.Columns))
+		assert.Equal(t, "field1_int64", info.Columns[0].Name)
+		// every 21th integer is null
+		assert.Equal(t, int64(96), info.Columns[0].PreAgg.Count)
+	}
+
+	res := fi.Verify()
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, 10, res.Series)
+	assert.Equal(t, int64(1000), res.Rows)
+
+	var rows int
+	err = fi.ReadSeries(ids[3], func(rec *record.Record) error {
+		rows += rec.RowNums()
+		assert.Equal(t, data[ids[3]].Times()[:rec.RowNums()], rec.Times()[:rec.RowNums()])
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100, rows)
+
+	rows = 0
+	err = fi.ReadSeries(1000, func(rec *record.Record) error {
+		rows += rec.RowNums()
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, rows)
+}
+
+func TestFileInspector_InvalidFile(t *testing.T) {
+	_, err := NewFileInspector(t.TempDir() + "/not_exists.tssp")
+	assert.Error(t, err)
+}
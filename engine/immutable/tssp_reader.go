@@ -861,6 +861,7 @@ type TablesStore interface {
 	FreeAllMemReader()
 	ReplaceFiles(name string, oldFiles, newFiles []TSSPFile, isOrder bool, log *Log.Logger) error
 	GetFilesRef(measurement string, isOrder bool) []TSSPFile
+	Measurements() []string
 	GetFilesRefByAscending(measurement string, isOrder bool, ascending bool, tr record.TimeRange) []TSSPFile
 	NextSequence() uint64
 	Sequencer() *Sequencer
@@ -1466,6 +1467,24 @@ func (m *MmsTables) GetFilesRef(measurement string, isOrder bool) []TSSPFile {
 	return nil
 }
 
+// Measurements returns the names of the measurements which have files.
+func (m *MmsTables) Measurements() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.Order)+len(m.OutOfOrder))
+	for name := range m.Order {
+		names = append(names, name)
+	}
+	for name := range m.OutOfOrder {
+		if _, ok := m.Order[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 /*
 	Get files by time range and ascending.
 	For example, we have files which time range are [1,3],[1,4],[2,5], [3,7]
@@ -22,7 +22,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
-	"github.com/VictoriaMetrics/VictoriaMetrics/lib/mergeset"
+	"github.com/openGemini/openGemini/open_src/vm/mergeset"
 	"github.com/openGemini/openGemini/open_src/vm/uint64set"
 )
 
@@ -174,6 +174,7 @@ func (brm *BasicRowMerger) FlushPendingTSIDs(dstData []byte, dstItems []mergeset
 	}
 	// Use sort.Sort instead of sort.Slice in order to reduce memory allocations.
 	sort.Sort(&brm.pendingTSIDs)
+	brm.pendingTSIDs = removeDuplicateTSIDs(brm.pendingTSIDs)
 
 	// marshal pendingTSIDs
 	dstDataLen := len(dstData)
@@ -189,6 +190,21 @@ func (brm *BasicRowMerger) FlushPendingTSIDs(dstData []byte, dstItems []mergeset
 	return dstData, dstItems
 }
 
+// removeDuplicateTSIDs removes the duplicates of the sorted tsids, the same tag row may be
+// written again for a series when its index is rebuilt.
+func removeDuplicateTSIDs(sortedTSIDs uint64Sorter) uint64Sorter {
+	if len(sortedTSIDs) < 2 {
+		return sortedTSIDs
+	}
+	dst := sortedTSIDs[:1]
+	for _, tsid := range sortedTSIDs[1:] {
+		if tsid != dst[len(dst)-1] {
+			dst = append(dst, tsid)
+		}
+	}
+	return dst
+}
+
 func (brm *BasicRowMerger) AppendTSIDs(tsids []uint64) {
 	brm.pendingTSIDs = append(brm.pendingTSIDs, tsids...)
 }
@@ -0,0 +1,374 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsi
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+var cardinalityConf atomic.Value
+
+// SetCardinalityLimits sets the series and tag value limits checked when new series are created.
+func SetCardinalityLimits(conf *config.Store) {
+	c := *conf
+	cardinalityConf.Store(&c)
+}
+
+func getCardinalityLimit(db string) config.CardinalityLimit {
+	conf, ok := cardinalityConf.Load().(*config.Store)
+	if !ok {
+		return config.CardinalityLimit{Database: db}
+	}
+	return conf.CardinalityLimit(db)
+}
+
+// IsCardinalityLimitError returns true if err is caused by series or tag value limits.
+func IsCardinalityLimitError(err error) bool {
+	return errno.Equal(err, errno.SeriesLimitExceeded) || errno.Equal(err, errno.TagValuesLimitExceeded)
+}
+
+// cardinalityLimiters holds the limiter of every database with an open index on this node.
+var cardinalityLimiters = struct {
+	mu  sync.Mutex
+	dbs map[string]*cardinalityLimiter
+}{dbs: make(map[string]*cardinalityLimiter)}
+
+// cardinalityLimiter counts the series and tag values of one database over all the indexes of the
+// database on this node, i.e. over its pts and index time ranges. A series written in several index
+// time ranges is counted once, and is no longer counted once it is deleted from all of them.
+// Counters are loaded lazily from the indexes the first time they are needed, and are only
+// maintained while limits are enabled. Series and tag values created before a counter is
+// loaded are only counted once they are flushed to the index.
+type cardinalityLimiter struct {
+	mu sync.Mutex
+	db string
+	// the indexes of the database, true if the series of the index are in series
+	indexes map[*MergeSetIndex]bool
+
+	// the hash of the index key of every series, without the measurement version,
+	// and the number of the indexes the series is in
+	series    map[uint64]int32
+	mstSeries map[string]int64 // measurement -> series
+
+	tagValues map[string]*tagValueCounter // composite tag key -> values of the database
+	newTags   []int
+}
+
+type tagValueCounter struct {
+	n int64
+	// values added since the counter was loaded, which may not be searchable yet
+	added map[string]struct{}
+}
+
+// openCardinalityLimiter adds an opened index to the limiter of its database.
+func openCardinalityLimiter(idx *MergeSetIndex) *cardinalityLimiter {
+	cardinalityLimiters.mu.Lock()
+	defer cardinalityLimiters.mu.Unlock()
+	l, ok := cardinalityLimiters.dbs[idx.db]
+	if !ok {
+		l = &cardinalityLimiter{
+			db:        idx.db,
+			indexes:   make(map[*MergeSetIndex]bool),
+			series:    make(map[uint64]int32),
+			mstSeries: make(map[string]int64),
+			tagValues: make(map[string]*tagValueCounter),
+		}
+		cardinalityLimiters.dbs[idx.db] = l
+	}
+
+	l.mu.Lock()
+	l.indexes[idx] = false
+	// the values of the index are not in the loaded tag value counters
+	l.tagValues = make(map[string]*tagValueCounter)
+	l.mu.Unlock()
+	return l
+}
+
+// closeIndex removes an index before it is closed, the counters of the database no longer include it.
+func (l *cardinalityLimiter) closeIndex(idx *MergeSetIndex) {
+	cardinalityLimiters.mu.Lock()
+	defer cardinalityLimiters.mu.Unlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.indexes, idx)
+	l.tagValues = make(map[string]*tagValueCounter)
+	if len(l.indexes) == 0 && cardinalityLimiters.dbs[l.db] == l {
+		delete(cardinalityLimiters.dbs, l.db)
+		statistics.NewCardinalityStat().RemoveDatabase(l.db)
+		return
+	}
+
+	// the series of the closed index are unknown, they are loaded again from the other indexes
+	l.series = make(map[uint64]int32)
+	l.mstSeries = make(map[string]int64)
+	for other := range l.indexes {
+		l.indexes[other] = false
+	}
+}
+
+// check returns an error if creating the series vkey of the measurement vname would exceed the limits.
+// The caller must hold l.mu and call add after the series is created.
+func (l *cardinalityLimiter) check(limit *config.CardinalityLimit, vkey, vname []byte, tags []influx.Tag) error {
+	if limit.MaxSeries > 0 || limit.MaxSeriesPerMeasurement > 0 {
+		if err := l.loadSeries(); err != nil {
+			return err
+		}
+	}
+
+	// a series of the other indexes of the database is not a new one
+	if l.series[seriesHash(vkey)] == 0 {
+		if limit.MaxSeries > 0 {
+			series := int64(len(l.series))
+			statistics.NewCardinalityStat().SetSeries(l.db, series, int64(limit.MaxSeries))
+			if series >= int64(limit.MaxSeries) {
+				statistics.NewCardinalityStat().AddRejectedSeries(l.db, 1)
+				return errno.NewError(errno.SeriesLimitExceeded, l.db, measurementName(vname), series, limit.MaxSeries)
+			}
+		}
+
+		if limit.MaxSeriesPerMeasurement > 0 {
+			n := l.mstSeries[measurementName(vname)]
+			if n >= int64(limit.MaxSeriesPerMeasurement) {
+				statistics.NewCardinalityStat().AddRejectedSeries(l.db, 1)
+				return errno.NewError(errno.SeriesLimitExceeded, l.db, measurementName(vname), n, limit.MaxSeriesPerMeasurement)
+			}
+		}
+	}
+
+	l.newTags = l.newTags[:0]
+	if limit.MaxValuesPerTag <= 0 {
+		return nil
+	}
+	for i := range tags {
+		c, isNew, err := l.loadTagValue(vname, &tags[i])
+		if err != nil {
+			return err
+		}
+		if !isNew {
+			continue
+		}
+		if c.n >= int64(limit.MaxValuesPerTag) {
+			statistics.NewCardinalityStat().AddRejectedTagValues(l.db, 1)
+			return errno.NewError(errno.TagValuesLimitExceeded, l.db, measurementName(vname), tags[i].Key, c.n, limit.MaxValuesPerTag)
+		}
+		l.newTags = append(l.newTags, i)
+	}
+	return nil
+}
+
+// add counts the series vkey created in the index idx after a successful check.
+func (l *cardinalityLimiter) add(idx *MergeSetIndex, limit *config.CardinalityLimit, vkey, vname []byte, tags []influx.Tag) {
+	if l.indexes[idx] {
+		l.countSeries(vkey, 1)
+		statistics.NewCardinalityStat().SetSeries(l.db, int64(len(l.series)), int64(limit.MaxSeries))
+	}
+
+	compositeKey := kbPool.Get()
+	defer kbPool.Put(compositeKey)
+	for _, i := range l.newTags {
+		compositeKey.B = marshalCompositeTagKey(compositeKey.B[:0], vname, []byte(tags[i].Key))
+		tc := l.tagValues[string(compositeKey.B)]
+		tc.n++
+		tc.added[tags[i].Value] = struct{}{}
+	}
+	l.newTags = l.newTags[:0]
+}
+
+// remove stops counting the series tsids of the index idx, before they are marked deleted.
+func (l *cardinalityLimiter) remove(idx *MergeSetIndex, tsids []uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.indexes[idx] {
+		return
+	}
+
+	is := idx.getIndexSearch()
+	defer func() {
+		idx.putIndexSearch(is)
+	}()
+	vkey := kbPool.Get()
+	defer kbPool.Put(vkey)
+	deleted := idx.getDeletedTSIDs()
+	flushed := false
+	var err error
+	for _, tsid := range tsids {
+		if deleted.Has(tsid) {
+			continue
+		}
+		vkey.B, err = is.searchSeriesKey(vkey.B[:0], tsid)
+		if err == io.EOF && !flushed {
+			// the series is not flushed yet, deleting series is rare enough to flush the index
+			idx.putIndexSearch(is)
+			idx.tb.DebugFlush()
+			is = idx.getIndexSearch()
+			flushed = true
+			vkey.B, err = is.searchSeriesKey(vkey.B[:0], tsid)
+		}
+		if err != nil {
+			continue
+		}
+		l.countSeries(vkey.B, -1)
+	}
+	if limit := getCardinalityLimit(l.db); limit.MaxSeries > 0 {
+		statistics.NewCardinalityStat().SetSeries(l.db, int64(len(l.series)), int64(limit.MaxSeries))
+	}
+}
+
+// countSeries adds delta to the number of the indexes holding the series vkey
+func (l *cardinalityLimiter) countSeries(vkey []byte, delta int32) {
+	if len(vkey) < 2 {
+		return
+	}
+	h := seriesHash(vkey)
+	n := l.series[h]
+	if n+delta > 0 {
+		l.series[h] = n + delta
+		if n == 0 {
+			l.mstSeries[seriesMeasurement(vkey)]++
+		}
+		return
+	}
+	if n > 0 {
+		delete(l.series, h)
+		l.mstSeries[seriesMeasurement(vkey)]--
+	}
+}
+
+// loadSeries counts the series of the indexes not loaded yet
+func (l *cardinalityLimiter) loadSeries() error {
+	for idx, loaded := range l.indexes {
+		if loaded {
+			continue
+		}
+		err := idx.walkSeriesKeys(func(vkey []byte) {
+			l.countSeries(vkey, 1)
+		})
+		if err != nil {
+			return err
+		}
+		l.indexes[idx] = true
+	}
+	return nil
+}
+
+// seriesHash returns the hash of the series key with the measurement version vkey, without the version,
+// so that a series is the same one in all the indexes of the database.
+func seriesHash(vkey []byte) uint64 {
+	return meta.HashID(vkey[:len(vkey)-2])
+}
+
+func seriesMeasurement(vkey []byte) string {
+	name, _, err := influx.MeasurementName(vkey[:len(vkey)-2])
+	if err != nil {
+		return ""
+	}
+	return string(name)
+}
+
+// loadTagValue returns the counter of the values of a tag key in the database,
+// and whether the value of tag is new to all the indexes of the database.
+func (l *cardinalityLimiter) loadTagValue(vname []byte, tag *influx.Tag) (*tagValueCounter, bool, error) {
+	compositeKey := kbPool.Get()
+	defer kbPool.Put(compositeKey)
+	compositeKey.B = marshalCompositeTagKey(compositeKey.B[:0], vname, []byte(tag.Key))
+
+	c, ok := l.tagValues[string(compositeKey.B)]
+	if !ok {
+		values := make(map[string]struct{})
+		for idx := range l.indexes {
+			is := idx.getIndexSearch()
+			vs, err := is.searchTagValuesBySingleKey(vname, []byte(tag.Key), nil)
+			idx.putIndexSearch(is)
+			if err != nil {
+				return nil, false, err
+			}
+			for v := range vs {
+				values[v] = struct{}{}
+			}
+		}
+		c = &tagValueCounter{n: int64(len(values)), added: make(map[string]struct{})}
+		l.tagValues[string(compositeKey.B)] = c
+	}
+
+	if _, ok := c.added[tag.Value]; ok {
+		return c, false, nil
+	}
+	for idx := range l.indexes {
+		is := idx.getIndexSearch()
+		exists := is.hasTagValue(compositeKey.B, []byte(tag.Value))
+		idx.putIndexSearch(is)
+		if exists {
+			return c, false, nil
+		}
+	}
+	return c, true, nil
+}
+
+func measurementName(vname []byte) string {
+	if len(vname) < 2 {
+		return string(vname)
+	}
+	// trim the measurement version
+	return string(vname[:len(vname)-2])
+}
+
+// walkSeriesKeys calls f with the key of every series in the index, the deleted series excluded.
+func (idx *MergeSetIndex) walkSeriesKeys(f func(vkey []byte)) error {
+	is := idx.getIndexSearch()
+	defer idx.putIndexSearch(is)
+	ts := &is.ts
+	kb := &is.kb
+
+	deleted := idx.getDeletedTSIDs()
+	kb.B = append(kb.B[:0], nsPrefixKeyToTSID)
+	ts.Seek(kb.B)
+	for ts.NextItem() {
+		if !bytes.HasPrefix(ts.Item, kb.B) {
+			break
+		}
+		// the series key, the separator and the tsid
+		item := ts.Item[len(kb.B):]
+		if len(item) < 1+8 {
+			continue
+		}
+		if deleted.Has(encoding.UnmarshalUint64(item[len(item)-8:])) {
+			continue
+		}
+		f(item[:len(item)-9])
+	}
+	return ts.Error()
+}
+
+func (is *indexSearch) hasTagValue(compositeKey, value []byte) bool {
+	ts := &is.ts
+	kb := &is.kb
+	kb.B = append(kb.B[:0], nsPrefixTagToTSIDs)
+	kb.B = marshalTagValue(kb.B, compositeKey)
+	kb.B = marshalTagValue(kb.B, value)
+	ts.Seek(kb.B)
+	return ts.NextItem() && bytes.HasPrefix(ts.Item, kb.B)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/open_src/github.com/savsgio/dictpool"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/assert"
+)
+
+func genCardinalityRows(keys ...string) *dictpool.Dict {
+	mmPoints := &dictpool.Dict{}
+	for _, key := range keys {
+		pt := influx.Row{}
+		strs := strings.Split(key, ",")
+		pt.Name = strs[0]
+		pt.Tags = make(influx.PointTags, len(strs)-1)
+		for i, str := range strs[1:] {
+			kv := strings.Split(str, "=")
+			pt.Tags[i].Key = kv[0]
+			pt.Tags[i].Value = kv[1]
+		}
+		sort.Sort(&pt.Tags)
+		pt.Timestamp = time.Now().UnixNano()
+		pt.UnmarshalIndexKeys(nil)
+		pt.ShardKey = pt.IndexKey
+
+		if !mmPoints.Has(pt.Name) {
+			mmPoints.Set(pt.Name, &[]influx.Row{})
+		}
+		rows := mmPoints.Get(pt.Name).(*[]influx.Row)
+		*rows = append(*rows, pt)
+	}
+	return mmPoints
+}
+
+func countRowsWithSeries(mmPoints *dictpool.Dict) int {
+	n := 0
+	for i := range mmPoints.D {
+		rows := mmPoints.D[i].Value.(*[]influx.Row)
+		for j := range *rows {
+			if (*rows)[j].SeriesId != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func getCardinalityIndexAndBuilder(db string, id uint64) (Index, *IndexBuilder) {
+	opts := new(Options).
+		Ident(&meta.IndexIdentifier{OwnerDb: db, Index: &meta.IndexDescriptor{IndexID: id}}).
+		Path(testIndexPath + "index-" + fmt.Sprintf("%d", time.Now().UnixNano())).
+		IndexType(MergeSet).
+		EndTime(time.Now().Add(time.Hour)).
+		Duration(time.Hour)
+
+	indexBuilder := NewIndexBuilder(opts)
+	indexBuilder.Relations = make(map[uint32]*IndexRelation)
+	primaryIndex, err := NewIndex(opts)
+	if err != nil {
+		panic(err)
+	}
+	primaryIndex.SetIndexBuilder(indexBuilder)
+	indexRelation, _ := NewIndexRelation(opts, primaryIndex, indexBuilder)
+	indexBuilder.Relations[uint32(MergeSet)] = indexRelation
+	if err = indexBuilder.Open(); err != nil {
+		panic(err)
+	}
+	return primaryIndex, indexBuilder
+}
+
+func TestCardinalityLimit(t *testing.T) {
+	unlimited := config.NewStore()
+	defer SetCardinalityLimits(&unlimited)
+	conf := config.NewStore()
+
+	// the limits are checked against both indexes of the database
+	idx, idxBuilder := getCardinalityIndexAndBuilder("db_limit", 1)
+	defer clear(idx)
+	idx2, idxBuilder2 := getCardinalityIndexAndBuilder("db_limit", 2)
+	defer idx2.Close()
+
+	f := func(expErrno errno.Errno, expSeries int, keys ...string) {
+		mmPoints := genCardinalityRows(keys...)
+		err := idxBuilder.CreateIndexIfNotExists(mmPoints)
+		if expErrno == 0 {
+			assert.NoError(t, err)
+		} else {
+			assert.True(t, errno.Equal(err, expErrno), fmt.Sprintf("unexpected error: %v", err))
+		}
+		assert.Equal(t, expSeries, countRowsWithSeries(mmPoints))
+	}
+
+	// existing series are counted after limits are enabled
+	f(0, 1, "mn-1,tk1=v1")
+	mmPoints := genCardinalityRows("mn-1,tk1=v2")
+	assert.NoError(t, idxBuilder2.CreateIndexIfNotExists(mmPoints))
+	idx.(*MergeSetIndex).DebugFlush()
+	idx2.(*MergeSetIndex).DebugFlush()
+
+	t.Run("series per measurement", func(t *testing.T) {
+		conf.MaxSeriesPerMeasurement = 3
+		SetCardinalityLimits(&conf)
+		// only one of v3 and v4 is created
+		f(errno.SeriesLimitExceeded, 2, "mn-1,tk1=v1", "mn-1,tk1=v3", "mn-1,tk1=v4")
+		// written series are not limited
+		f(0, 1, "mn-1,tk1=v1")
+	})
+
+	t.Run("values per tag", func(t *testing.T) {
+		conf.MaxSeriesPerMeasurement = 0
+		conf.MaxValuesPerTag = 2
+		SetCardinalityLimits(&conf)
+		f(0, 2, "mn-2,tk1=v1,tk2=a", "mn-2,tk1=v1,tk2=b")
+		f(errno.TagValuesLimitExceeded, 1, "mn-2,tk1=v1,tk2=a", "mn-2,tk1=v1,tk2=c")
+		// the values of the other index are counted too
+		mmPoints := genCardinalityRows("mn-2,tk1=v2,tk2=b", "mn-2,tk1=v2,tk2=c")
+		assert.True(t, errno.Equal(idxBuilder2.CreateIndexIfNotExists(mmPoints), errno.TagValuesLimitExceeded))
+		assert.Equal(t, 1, countRowsWithSeries(mmPoints))
+	})
+
+	t.Run("series per database", func(t *testing.T) {
+		// 6 series are written to the indexes of the database
+		idx.(*MergeSetIndex).DebugFlush()
+		idx2.(*MergeSetIndex).DebugFlush()
+		conf.MaxValuesPerTag = 0
+		conf.MaxSeriesPerDatabase = 7
+		SetCardinalityLimits(&conf)
+		f(0, 1, "mn-3,tk1=v1")
+		f(errno.SeriesLimitExceeded, 0, "mn-3,tk1=v2")
+
+		// a series of the other index is counted once
+		mmPoints := genCardinalityRows("mn-3,tk1=v1")
+		assert.NoError(t, idxBuilder2.CreateIndexIfNotExists(mmPoints))
+		assert.Equal(t, 1, countRowsWithSeries(mmPoints))
+		idx2.(*MergeSetIndex).DebugFlush()
+		f(errno.SeriesLimitExceeded, 0, "mn-3,tk1=v2")
+	})
+
+	t.Run("per database override", func(t *testing.T) {
+		conf.CardinalityLimits = []config.CardinalityLimit{{Database: "db_limit", MaxSeries: 10}}
+		SetCardinalityLimits(&conf)
+		f(0, 1, "mn-3,tk1=v2")
+	})
+
+	t.Run("deleted series", func(t *testing.T) {
+		conf.CardinalityLimits = []config.CardinalityLimit{{Database: "db_limit", MaxSeries: 8}}
+		SetCardinalityLimits(&conf)
+		f(errno.SeriesLimitExceeded, 0, "mn-4,tk1=v1")
+
+		// a series is counted until it is deleted from all the indexes
+		mmPoints := genCardinalityRows("mn-3,tk1=v1")
+		assert.NoError(t, idxBuilder2.CreateIndexIfNotExists(mmPoints))
+		rows := mmPoints.Get("mn-3").(*[]influx.Row)
+		assert.NoError(t, idx2.(*MergeSetIndex).deleteTSIDs([]uint64{(*rows)[0].SeriesId}))
+		f(errno.SeriesLimitExceeded, 0, "mn-4,tk1=v1")
+
+		mmPoints = genCardinalityRows("mn-3,tk1=v2")
+		assert.NoError(t, idxBuilder.CreateIndexIfNotExists(mmPoints))
+		rows = mmPoints.Get("mn-3").(*[]influx.Row)
+		assert.NoError(t, idx.(*MergeSetIndex).deleteTSIDs([]uint64{(*rows)[0].SeriesId}))
+		f(0, 1, "mn-4,tk1=v1")
+	})
+}
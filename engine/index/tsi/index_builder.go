@@ -269,6 +269,7 @@ func (iBuilder *IndexBuilder) CreateIndexIfNotExists(mmRows *dictpool.Dict) erro
 		version, loaded := iBuilder.loadOrStore(stringinterner.InternSafe(mmRows.D[mmIdx].Key))
 		if !loaded {
 			if err := iBuilder.saveVersion([]byte(mmRows.D[mmIdx].Key), version); err != nil {
+				putIndexRows(iRows)
 				return err
 			}
 		}
@@ -286,24 +287,37 @@ func (iBuilder *IndexBuilder) CreateIndexIfNotExists(mmRows *dictpool.Dict) erro
 			} else {
 				*iRows = append(*iRows, indexRow{})
 			}
-			iRow := (*iRows)[len(*iRows)-1]
+			iRow := &(*iRows)[len(*iRows)-1]
 			iRow.Row = row
 			iRow.Wg = &wg
-
-			wg.Add(1)
-			idx := primaryIndex.(*MergeSetIndex)
-			idx.WriteRow(&iRow)
+			iRow.Err = nil
 		}
 	}
+
+	// The rows are sent after iRows stops growing, so that the pointers stay valid
+	// and the errors are set on the rows checked below.
+	idx := primaryIndex.(*MergeSetIndex)
+	wg.Add(len(*iRows))
+	for i := range *iRows {
+		idx.WriteRow(&(*iRows)[i])
+	}
 	// Wait all rows in the batch finished.
 	wg.Wait()
 
-	// Check Err.
+	// Check Err. Rows rejected by cardinality limits keep a zero series id,
+	// the others are still indexed and the limit error is returned at last.
+	var limitErr error
 	for _, row := range *iRows {
-		if row.Err != nil {
+		if row.Err == nil {
+			continue
+		}
+		if !IsCardinalityLimitError(row.Err) {
 			putIndexRows(iRows)
 			return row.Err
 		}
+		if limitErr == nil {
+			limitErr = row.Err
+		}
 	}
 	putIndexRows(iRows)
 
@@ -312,13 +326,16 @@ func (iBuilder *IndexBuilder) CreateIndexIfNotExists(mmRows *dictpool.Dict) erro
 		rows, _ := mmRows.D[mmIdx].Value.(*[]influx.Row)
 		for rowIdx := range *rows {
 			row := &(*rows)[rowIdx]
+			if row.SeriesId == 0 {
+				continue
+			}
 			if err := iBuilder.createSecondaryIndex(row, primaryIndex); err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	return limitErr
 }
 
 func (iBuilder *IndexBuilder) CreateIndexIfPrimaryKeyExists(mmRows *dictpool.Dict, openIndexOption bool) error {
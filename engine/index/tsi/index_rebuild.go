@@ -0,0 +1,152 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
+)
+
+// SeriesIDs adds the tsid of every series in the index to dst, the deleted series excluded.
+func (idx *MergeSetIndex) SeriesIDs(dst *uint64set.Set) error {
+	is := idx.getIndexSearch()
+	defer idx.putIndexSearch(is)
+	ts := &is.ts
+	kb := &is.kb
+
+	deleted := idx.getDeletedTSIDs()
+	kb.B = append(kb.B[:0], nsPrefixTSIDToKey)
+	ts.Seek(kb.B)
+	for ts.NextItem() {
+		if !bytes.HasPrefix(ts.Item, kb.B) {
+			break
+		}
+		tsid := encoding.UnmarshalUint64(ts.Item[len(kb.B):])
+		if !deleted.Has(tsid) {
+			dst.Add(tsid)
+		}
+	}
+	return ts.Error()
+}
+
+// RebuildIndexes writes the series key -> tsid and tag -> tsid entries of the series again,
+// based on the tsid -> series key entries. It returns the number of the rebuilt series and
+// the number of the series whose key is lost, which can not be rebuilt. An error is returned
+// if the keys of all the series are lost, such an index can not be regenerated.
+func (idx *MergeSetIndex) RebuildIndexes(tsids []uint64) (int, int, error) {
+	is := idx.getIndexSearch()
+	defer idx.putIndexSearch(is)
+
+	vkey := kbPool.Get()
+	defer kbPool.Put(vkey)
+	vname := kbPool.Get()
+	defer kbPool.Put(vname)
+	var tags influx.PointTags
+
+	var rebuilt, missing int
+	var err error
+	for _, tsid := range tsids {
+		vkey.B, err = is.searchSeriesKey(vkey.B[:0], tsid)
+		if err == io.EOF || (err == nil && len(vkey.B) < 3) {
+			missing++
+			continue
+		}
+		if err != nil {
+			return rebuilt, missing, err
+		}
+
+		// the key is made up of the index key and the version of the measurement
+		indexKey := vkey.B[:len(vkey.B)-2]
+		name, _, err := influx.MeasurementName(indexKey)
+		if err != nil {
+			missing++
+			continue
+		}
+		if _, err = influx.IndexKeyToTags(indexKey, false, &tags); err != nil {
+			missing++
+			continue
+		}
+		vname.B = append(append(vname.B[:0], name...), vkey.B[len(vkey.B)-2:]...)
+
+		if err = idx.addSeriesItems(vkey.B, vname.B, tags, tsid); err != nil {
+			return rebuilt, missing, err
+		}
+		rebuilt++
+	}
+
+	invalidateTagCache()
+	if rebuilt == 0 && missing > 0 {
+		return rebuilt, missing, fmt.Errorf("the keys of all the %d series are lost, the index can not be rebuilt", missing)
+	}
+	return rebuilt, missing, nil
+}
+
+// BeginWrite is called by a writer before the series of the rows are looked up, and EndWrite
+// after the rows are in the mem table, so that the series are not deleted as orphans meanwhile.
+func (idx *MergeSetIndex) BeginWrite() {
+	idx.writeMu.RLock()
+}
+
+func (idx *MergeSetIndex) EndWrite() {
+	idx.writeMu.RUnlock()
+}
+
+// DeleteOrphanSeries marks the series deleted, a series written again later gets a new tsid.
+// inUse is called with the writes blocked, and the series among its argument that it adds to
+// dst, those written since the orphans were found, are kept. It returns the number of the
+// deleted series.
+func (idx *MergeSetIndex) DeleteOrphanSeries(tsids []uint64, inUse func(tsids []uint64, dst *uint64set.Set) error) (int, error) {
+	if len(tsids) == 0 {
+		return 0, nil
+	}
+	idx.writeMu.Lock()
+	defer idx.writeMu.Unlock()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if inUse != nil {
+		used := &uint64set.Set{}
+		if err := inUse(tsids, used); err != nil {
+			return 0, err
+		}
+		if used.Len() > 0 {
+			kept := tsids[:0:0]
+			for _, tsid := range tsids {
+				if !used.Has(tsid) {
+					kept = append(kept, tsid)
+				}
+			}
+			tsids = kept
+		}
+	}
+	if len(tsids) == 0 {
+		return 0, nil
+	}
+	if err := idx.deleteTSIDs(tsids); err != nil {
+		return 0, err
+	}
+
+	// the cached series key -> tsid entries of the deleted series must not be used any more
+	idx.cache.SeriesKeyToTSIDCache.Reset()
+	invalidateTagCache()
+	return len(tsids), nil
+}
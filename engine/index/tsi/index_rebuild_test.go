@@ -0,0 +1,156 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsi
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/influxdata/influxdb/pkg/testing/assert"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
+	"github.com/stretchr/testify/require"
+)
+
+var rebuildSeriesKeys = []string{
+	"mn-1,tk1=value1,tk2=value2,tk3=value3",
+	"mn-1,tk1=value1,tk2=value22,tk3=value3",
+	"mn-1,tk1=value11,tk2=value2,tk3=value33",
+	"mn-1,tk1=value11,tk2=value22,tk3=value3",
+	"mn-1,tk1=value11,tk2=value22,tk3=value33",
+}
+
+func searchSortedSeries(t *testing.T, idx Index) []string {
+	dst, err := idx.SearchSeries(nil, []byte("mn-1"), nil, defaultTR)
+	require.NoError(t, err)
+	keys := make([]string, 0, len(dst))
+	for _, key := range dst {
+		keys = append(keys, string(key))
+		influx.PutBytesBuffer(key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestRebuildIndexes(t *testing.T) {
+	idx, _ := getTestIndexAndBuilder()
+	defer clear(idx)
+	CreateIndexByPts(idx)
+	mergeSetIdx := idx.(*MergeSetIndex)
+
+	tsids := &uint64set.Set{}
+	require.NoError(t, mergeSetIdx.SeriesIDs(tsids))
+	assert.Equal(t, tsids.Len(), 5)
+
+	// an index which only keeps the tsid -> series key entries
+	lost, builder := getTestIndexAndBuilder()
+	defer lost.Close()
+	lostIdx := lost.(*MergeSetIndex)
+	builder.storeVersion("mn-1", minVersion)
+
+	ii := idxItemsPool.Get()
+	is := mergeSetIdx.getIndexSearch()
+	for _, tsid := range tsids.AppendTo(nil) {
+		seriesKey, err := is.searchSeriesKey(nil, tsid)
+		require.NoError(t, err)
+		ii.B = append(ii.B, nsPrefixTSIDToKey)
+		ii.B = encoding.MarshalUint64(ii.B, tsid)
+		ii.B = append(ii.B, seriesKey...)
+		ii.Next()
+	}
+	mergeSetIdx.putIndexSearch(is)
+	require.NoError(t, lostIdx.tb.AddItems(ii.Items))
+	idxItemsPool.Put(ii)
+	lostIdx.DebugFlush()
+	assert.Equal(t, len(searchSortedSeries(t, lost)), 0)
+
+	// an index without any of the series keys can not be rebuilt
+	rebuilt, missing, err := lostIdx.RebuildIndexes([]uint64{GenerateUUID(), GenerateUUID()})
+	require.Error(t, err)
+	assert.Equal(t, rebuilt, 0)
+	assert.Equal(t, missing, 2)
+
+	rebuilt, missing, err = lostIdx.RebuildIndexes(append(tsids.AppendTo(nil), GenerateUUID()))
+	require.NoError(t, err)
+	assert.Equal(t, rebuilt, 5)
+	assert.Equal(t, missing, 1)
+	lostIdx.DebugFlush()
+	assert.Equal(t, searchSortedSeries(t, lost), rebuildSeriesKeys)
+
+	for _, key := range rebuildSeriesKeys {
+		row := influx.Row{Name: "mn-1", Tags: parseTestTags(key)}
+		row.UnmarshalIndexKeys(nil)
+		tsid, err := lostIdx.GetSeriesIdBySeriesKey(row.IndexKey, []byte("mn-1"))
+		require.NoError(t, err)
+		assert.Equal(t, tsids.Has(tsid), true)
+	}
+}
+
+func TestDeleteOrphanSeries(t *testing.T) {
+	idx, _ := getTestIndexAndBuilder()
+	defer clear(idx)
+	CreateIndexByPts(idx)
+	mergeSetIdx := idx.(*MergeSetIndex)
+
+	row := influx.Row{Name: "mn-1", Tags: parseTestTags(rebuildSeriesKeys[0])}
+	row.UnmarshalIndexKeys(nil)
+	orphan, err := mergeSetIdx.GetSeriesIdBySeriesKey(row.IndexKey, []byte("mn-1"))
+	require.NoError(t, err)
+
+	// the series written since the orphans were found is kept
+	deleted, err := mergeSetIdx.DeleteOrphanSeries([]uint64{orphan}, func(tsids []uint64, dst *uint64set.Set) error {
+		dst.Add(orphan)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, deleted, 0)
+	assert.Equal(t, searchSortedSeries(t, idx), rebuildSeriesKeys)
+
+	deleted, err = mergeSetIdx.DeleteOrphanSeries([]uint64{orphan}, func(tsids []uint64, dst *uint64set.Set) error {
+		dst.Add(orphan + 1)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, deleted, 1)
+	assert.Equal(t, searchSortedSeries(t, idx), rebuildSeriesKeys[1:])
+
+	tsids := &uint64set.Set{}
+	require.NoError(t, mergeSetIdx.SeriesIDs(tsids))
+	assert.Equal(t, tsids.Len(), 4)
+	assert.Equal(t, tsids.Has(orphan), false)
+
+	// the series written again gets a new tsid
+	CreateIndexByPts(idx, rebuildSeriesKeys[0])
+	tsid, err := mergeSetIdx.GetSeriesIdBySeriesKey(row.IndexKey, []byte("mn-1"))
+	require.NoError(t, err)
+	assert.Equal(t, tsid != 0 && tsid != orphan, true)
+	assert.Equal(t, searchSortedSeries(t, idx), rebuildSeriesKeys)
+}
+
+func parseTestTags(key string) influx.PointTags {
+	strs := strings.Split(key, ",")
+	tags := make(influx.PointTags, len(strs)-1)
+	for i, str := range strs[1:] {
+		kv := strings.Split(str, "=")
+		tags[i].Key = kv[0]
+		tags[i].Value = kv[1]
+	}
+	sort.Sort(&tags)
+	return tags
+}
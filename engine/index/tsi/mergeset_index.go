@@ -30,7 +30,6 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
-	"github.com/VictoriaMetrics/VictoriaMetrics/lib/mergeset"
 	"github.com/openGemini/openGemini/engine/hybridqp"
 	"github.com/openGemini/openGemini/engine/index/mergeindex"
 	"github.com/openGemini/openGemini/lib/logger"
@@ -42,6 +41,7 @@ import (
 	"github.com/openGemini/openGemini/open_src/influx/influxql"
 	"github.com/openGemini/openGemini/open_src/influx/meta"
 	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/openGemini/openGemini/open_src/vm/mergeset"
 	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
 	"github.com/openGemini/openGemini/open_src/vm/uint64set"
 	"go.uber.org/zap"
@@ -98,14 +98,22 @@ type MergeSetIndex struct {
 	deletedTSIDsLock sync.Mutex
 
 	mu sync.RWMutex
+	// writeMu is held shared by the writers from the lookup of the series until their rows
+	// are in the mem table, and exclusively when the orphan series are deleted.
+	writeMu sync.RWMutex
 
 	indexBuilder *IndexBuilder
+	db           string
+	limiter      *cardinalityLimiter
 }
 
 func NewMergeSetIndex(opts *Options) (*MergeSetIndex, error) {
 	ms := &MergeSetIndex{
 		path: opts.path,
 	}
+	if opts.ident != nil {
+		ms.db = opts.ident.OwnerDb
+	}
 
 	return ms, nil
 }
@@ -136,6 +144,7 @@ func (idx *MergeSetIndex) Open() error {
 	}
 
 	idx.run()
+	idx.limiter = openCardinalityLimiter(idx)
 
 	return nil
 }
@@ -298,7 +307,21 @@ func (idx *MergeSetIndex) createIndexesIfNotExists(vkey, vname []byte, tags []in
 		}
 	}(&tsid)
 
+	limit := getCardinalityLimit(idx.db)
+	if !limit.Enabled() {
+		tsid, err = idx.createIndexes(vkey, vname, tags)
+		return tsid, err
+	}
+
+	idx.limiter.mu.Lock()
+	defer idx.limiter.mu.Unlock()
+	if err = idx.limiter.check(&limit, vkey, vname, tags); err != nil {
+		return 0, err
+	}
 	tsid, err = idx.createIndexes(vkey, vname, tags)
+	if err == nil {
+		idx.limiter.add(idx, &limit, vkey, vname, tags)
+	}
 	return tsid, err
 }
 
@@ -306,7 +329,13 @@ var idxItemsPool mergeindex.IndexItemsPool
 
 func (idx *MergeSetIndex) createIndexes(seriesKey []byte, name []byte, tags []influx.Tag) (uint64, error) {
 	tsid := GenerateUUID()
+	if err := idx.addSeriesItems(seriesKey, name, tags, tsid); err != nil {
+		return 0, err
+	}
+	return tsid, nil
+}
 
+func (idx *MergeSetIndex) addSeriesItems(seriesKey []byte, name []byte, tags []influx.Tag, tsid uint64) error {
 	ii := idxItemsPool.Get()
 	defer idxItemsPool.Put(ii)
 
@@ -344,11 +373,7 @@ func (idx *MergeSetIndex) createIndexes(seriesKey []byte, name []byte, tags []in
 
 	kbPool.Put(compositeKey)
 
-	if err := idx.tb.AddItems(ii.Items); err != nil {
-		return 0, err
-	}
-
-	return tsid, nil
+	return idx.tb.AddItems(ii.Items)
 }
 
 func (idx *MergeSetIndex) SeriesCardinality(name []byte, condition influxql.Expr, tr TimeRange) (uint64, error) {
@@ -768,6 +793,7 @@ func (idx *MergeSetIndex) dumpSeriesByTsids(tw *tabwriter.Writer, tsids []uint64
 }
 
 func (idx *MergeSetIndex) Close() error {
+	idx.limiter.closeIndex(idx)
 	idx.tb.MustClose()
 
 	if err := idx.cache.close(); err != nil {
@@ -845,6 +871,8 @@ func (idx *MergeSetIndex) deleteTSIDs(tsids []uint64) error {
 	ii := idxItemsPool.Get()
 	defer idxItemsPool.Put(ii)
 
+	idx.limiter.remove(idx, tsids)
+
 	// Lock to protect concurrent delete safety
 	idx.deletedTSIDsLock.Lock()
 	curDeleted := idx.deletedTSIDs.Load().(*uint64set.Set)
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openGemini/openGemini/engine/index/tsi"
+	stat "github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
+	"go.uber.org/zap"
+)
+
+var indexGCStat = stat.NewIndexGCStatistics()
+
+// indexGroupKey identifies an index and the shards sharing it.
+type indexGroupKey struct {
+	db      string
+	pt      uint32
+	indexID uint64
+}
+
+// indexGroup is an index referenced together with the shards written through it.
+type indexGroup struct {
+	key    indexGroupKey
+	dbPT   *DBPTInfo
+	idx    *tsi.MergeSetIndex
+	shards []Shard
+}
+
+func (g *indexGroup) release() {
+	for _, sh := range g.shards {
+		sh.UnRef()
+	}
+	g.dbPT.unref()
+}
+
+// seriesIDs returns the ids of the series having data in any shard of the group, and the
+// number of the snapshots of each shard taken before.
+func (g *indexGroup) seriesIDs() (*uint64set.Set, []uint64, error) {
+	sids := &uint64set.Set{}
+	seqs := make([]uint64, len(g.shards))
+	for i, sh := range g.shards {
+		seqs[i] = sh.SnapshotSeq()
+		if err := sh.SeriesIDs(sids); err != nil {
+			return nil, nil, fmt.Errorf("shard %d: %s", sh.GetID(), err)
+		}
+	}
+	return sids, seqs, nil
+}
+
+// writtenSince adds the ids among tsids of the series written to the group after the snapshots
+// seqs, and updates seqs to the snapshots seen. They are in the mem tables, or in the files too
+// if a snapshot was taken since.
+func (g *indexGroup) writtenSince(seqs []uint64, tsids []uint64, dst *uint64set.Set) error {
+	for i, sh := range g.shards {
+		seq := sh.MemSeriesIDs(tsids, dst)
+		if seq == seqs[i] {
+			continue
+		}
+		seqs[i] = seq
+		if err := sh.SeriesIDs(dst); err != nil {
+			return fmt.Errorf("shard %d: %s", sh.GetID(), err)
+		}
+	}
+	return nil
+}
+
+// deleteOrphans deletes the series of the group, except the ones written after seriesIDs
+// returned seqs. It returns the number of the deleted series.
+func (g *indexGroup) deleteOrphans(tsids *uint64set.Set, seqs []uint64) (int, error) {
+	// the series written since the scan are looked up without blocking the writes, and only
+	// the remaining orphans are looked up again in the mem tables with the writes blocked
+	used := &uint64set.Set{}
+	if err := g.writtenSince(seqs, tsids.AppendTo(nil), used); err != nil {
+		return 0, err
+	}
+	orphans := tsids.Clone()
+	orphans.Subtract(used)
+	return g.idx.DeleteOrphanSeries(orphans.AppendTo(nil), func(candidates []uint64, dst *uint64set.Set) error {
+		for i, sh := range g.shards {
+			if sh.MemSeriesIDs(candidates, dst) != seqs[i] {
+				// a snapshot was taken since, the series are kept until the next round
+				dst.AddMulti(candidates)
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+func (e *Engine) acquireIndexGroup(key indexGroupKey) (*indexGroup, error) {
+	e.mu.RLock()
+	if err := e.checkAndAddRefPTNoLock(key.db, key.pt); err != nil {
+		e.mu.RUnlock()
+		return nil, err
+	}
+	dbPT := e.DBPartitions[key.db][key.pt]
+	e.mu.RUnlock()
+
+	dbPT.mu.RLock()
+	iBuild, ok := dbPT.indexBuilder[key.indexID]
+	if !ok {
+		dbPT.mu.RUnlock()
+		dbPT.unref()
+		return nil, ErrIndexNotFound
+	}
+	idx, ok := iBuild.GetPrimaryIndex().(*tsi.MergeSetIndex)
+	if !ok {
+		dbPT.mu.RUnlock()
+		dbPT.unref()
+		return nil, fmt.Errorf("index %d isn't a mergeset index", key.indexID)
+	}
+	g := &indexGroup{key: key, dbPT: dbPT, idx: idx}
+	for _, sh := range dbPT.shards {
+		if sh.GetIndexBuild().GetIndexID() == key.indexID {
+			sh.Ref()
+			g.shards = append(g.shards, sh)
+		}
+	}
+	dbPT.mu.RUnlock()
+	return g, nil
+}
+
+// GCIndexes removes the series without data left in any shard from all the indexes. A series
+// is removed only if it was found orphan by the previous round already, so that the series
+// created but not written yet are kept.
+func (e *Engine) GCIndexes() {
+	keys := e.indexGroupKeys()
+
+	e.gcMu.Lock()
+	defer e.gcMu.Unlock()
+	candidates := make(map[indexGroupKey]*uint64set.Set, len(keys))
+	for _, key := range keys {
+		if e.closed.Closed() {
+			return
+		}
+		orphans, err := e.gcIndex(key, e.gcCandidates[key])
+		if err != nil {
+			indexGCStat.AddErrors(1)
+			e.log.Error("gc index failed", zap.String("db", key.db), zap.Uint32("pt", key.pt),
+				zap.Uint64("indexID", key.indexID), zap.Error(err))
+			continue
+		}
+		if orphans.Len() > 0 {
+			candidates[key] = orphans
+		}
+	}
+	// the candidates of the dropped indexes are forgotten
+	e.gcCandidates = candidates
+	indexGCStat.SetPendingIndexGroups(int64(len(candidates)))
+}
+
+// gcIndex deletes the orphan series of the index group found in prev too, and returns the
+// orphan series left for the next round.
+func (e *Engine) gcIndex(key indexGroupKey, prev *uint64set.Set) (*uint64set.Set, error) {
+	g, err := e.acquireIndexGroup(key)
+	if err != nil {
+		return nil, err
+	}
+	defer g.release()
+	indexGCStat.AddGCTotal(1)
+
+	// the index is read before the data, so a series created meanwhile is not an orphan
+	orphans := &uint64set.Set{}
+	if err = g.idx.SeriesIDs(orphans); err != nil {
+		return nil, err
+	}
+	indexGCStat.AddScannedSeries(int64(orphans.Len()))
+	sids, seqs, err := g.seriesIDs()
+	if err != nil {
+		return nil, err
+	}
+	orphans.Subtract(sids)
+	indexGCStat.AddOrphanSeries(int64(orphans.Len()))
+	if prev == nil || orphans.Len() == 0 {
+		return orphans, nil
+	}
+
+	// the series written since the scan are kept, the writes are blocked while checking them
+	confirmed := orphans.Clone()
+	confirmed.Intersect(prev)
+	deleted, err := g.deleteOrphans(confirmed, seqs)
+	if err != nil {
+		return nil, err
+	}
+	indexGCStat.AddDeletedSeries(int64(deleted))
+	e.log.Info("gc index done", zap.String("db", key.db), zap.Uint32("pt", key.pt),
+		zap.Uint64("indexID", key.indexID), zap.Int("deleted", deleted))
+
+	orphans.Subtract(confirmed)
+	return orphans, nil
+}
+
+func (e *Engine) indexGroupKeys() []indexGroupKey {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var keys []indexGroupKey
+	for db, pts := range e.DBPartitions {
+		for pt, dbPT := range pts {
+			dbPT.mu.RLock()
+			for indexID := range dbPT.indexBuilder {
+				keys = append(keys, indexGroupKey{db: db, pt: pt, indexID: indexID})
+			}
+			dbPT.mu.RUnlock()
+		}
+	}
+	return keys
+}
+
+func (e *Engine) indexGCLoop(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer func() {
+		t.Stop()
+		e.gcWg.Done()
+	}()
+
+	for {
+		select {
+		case <-e.closed.Signal():
+			return
+		case <-t.C:
+			e.GCIndexes()
+		}
+	}
+}
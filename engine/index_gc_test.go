@@ -0,0 +1,184 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/engine/index/tsi"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
+	"github.com/stretchr/testify/require"
+)
+
+// addShardOfIndex adds a shard sharing the index 659 with the shard 1 of initEngine1.
+func addShardOfIndex(t *testing.T, eng *Engine, shardID uint64) {
+	dbPTInfo := eng.DBPartitions["db0"][0]
+	shardIdent := &meta.ShardIdentifier{ShardID: shardID, ShardGroupID: shardID, Policy: "rp0", OwnerDb: "db0", OwnerPt: 0}
+	shardDuration := &meta.DurationDescriptor{Tier: meta.Hot, TierDuration: time.Hour}
+	tr := &meta.TimeRangeInfo{StartTime: mustParseTime(time.RFC3339Nano, "1999-01-01T01:00:00Z"),
+		EndTime: mustParseTime(time.RFC3339Nano, "2000-01-01T01:00:00Z")}
+	sh := NewShard(filepath.Join(eng.dataPath, "2"), filepath.Join(eng.walPath, "2"), shardIdent,
+		dbPTInfo.indexBuilder[659], shardDuration, tr, defaultEngineOption)
+	require.NoError(t, sh.Open())
+	dbPTInfo.shards[shardID] = sh
+}
+
+func indexSeriesCount(t *testing.T, idx *tsi.MergeSetIndex) int {
+	ids := &uint64set.Set{}
+	require.NoError(t, idx.SeriesIDs(ids))
+	return ids.Len()
+}
+
+func TestEngine_GCIndexes(t *testing.T) {
+	eng, err := initEngine1(t.TempDir())
+	require.NoError(t, err)
+	defer eng.Close()
+	addShardOfIndex(t, eng, 2)
+
+	tm := time.Now().Truncate(time.Second)
+	rows, _, _ := GenDataRecord([]string{"cpu"}, 10, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows("db0", "rp0", 0, 1, rows, nil))
+	rows, _, _ = GenDataRecord([]string{"expired"}, 5, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows("db0", "rp0", 0, 2, rows, nil))
+	eng.ForceFlush()
+
+	// the series only in the mem table are not orphan
+	rows, _, _ = GenDataRecord([]string{"mem"}, 3, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows("db0", "rp0", 0, 1, rows, nil))
+	require.NoError(t, eng.DeleteShard("db0", 0, 2))
+
+	idx := eng.DBPartitions["db0"][0].indexBuilder[659].GetPrimaryIndex().(*tsi.MergeSetIndex)
+	idx.DebugFlush()
+	require.Equal(t, 18, indexSeriesCount(t, idx))
+
+	// the orphan series are deleted by the second round only
+	eng.GCIndexes()
+	key := indexGroupKey{db: "db0", pt: 0, indexID: 659}
+	require.Equal(t, 5, eng.gcCandidates[key].Len())
+	idx.DebugFlush()
+	require.Equal(t, 18, indexSeriesCount(t, idx))
+
+	eng.GCIndexes()
+	require.Equal(t, 0, len(eng.gcCandidates))
+	idx.DebugFlush()
+	require.Equal(t, 13, indexSeriesCount(t, idx))
+
+	keys, err := eng.SeriesKeys("db0", []uint32{0}, [][]byte{[]byte("expired")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(keys))
+	keys, err = eng.SeriesKeys("db0", []uint32{0}, [][]byte{[]byte("cpu")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 10, len(keys))
+}
+
+func TestEngine_GCIndexKeepsWrittenSeries(t *testing.T) {
+	eng, err := initEngine1(t.TempDir())
+	require.NoError(t, err)
+	defer eng.Close()
+	addShardOfIndex(t, eng, 2)
+
+	tm := time.Now().Truncate(time.Second)
+	rows, _, _ := GenDataRecord([]string{"cpu"}, 10, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows("db0", "rp0", 0, 1, rows, nil))
+	rows, _, _ = GenDataRecord([]string{"expired"}, 5, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows("db0", "rp0", 0, 2, rows, nil))
+	eng.ForceFlush()
+	require.NoError(t, eng.DeleteShard("db0", 0, 2))
+
+	g, err := eng.acquireIndexGroup(indexGroupKey{db: "db0", pt: 0, indexID: 659})
+	require.NoError(t, err)
+	defer g.release()
+	orphans := &uint64set.Set{}
+	require.NoError(t, g.idx.SeriesIDs(orphans))
+	sids, seqs, err := g.seriesIDs()
+	require.NoError(t, err)
+	orphans.Subtract(sids)
+	require.Equal(t, 5, orphans.Len())
+
+	// the series written after the scan are kept, found in the mem table or in the files
+	rows, _, _ = GenDataRecord([]string{"expired"}, 2, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows("db0", "rp0", 0, 1, rows, nil))
+	deleted, err := g.deleteOrphans(orphans.Clone(), seqs)
+	require.NoError(t, err)
+	require.Equal(t, 3, deleted)
+
+	kept := &uint64set.Set{}
+	require.NoError(t, g.shards[0].SeriesIDs(kept))
+	kept.Intersect(orphans)
+	require.Equal(t, 2, kept.Len())
+	eng.ForceFlush()
+	deleted, err = g.deleteOrphans(kept, seqs)
+	require.NoError(t, err)
+	require.Equal(t, 0, deleted)
+
+	g.idx.DebugFlush()
+	keys, err := eng.SeriesKeys("db0", []uint32{0}, [][]byte{[]byte("expired")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(keys))
+}
+
+func TestEngine_RebuildIndex(t *testing.T) {
+	eng, err := initEngine1(t.TempDir())
+	require.NoError(t, err)
+	defer eng.Close()
+
+	tm := time.Now().Truncate(time.Second)
+	rows, _, _ := GenDataRecord([]string{"cpu"}, 10, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows("db0", "rp0", 0, 1, rows, nil))
+	eng.ForceFlush()
+
+	require.NoError(t, eng.RebuildIndex("db0", 0, 659))
+	require.Equal(t, ErrIndexNotFound, eng.RebuildIndex("db0", 0, 1))
+	require.Equal(t, ErrPTNotFound, eng.RebuildIndex("db1", 0, 659))
+
+	req := &netstorage.SysCtrlRequest{}
+	req.SetMod(RebuildIndex)
+	req.SetParam(map[string]string{"db": "db0", "pt": "0", "indexid": "659"})
+	require.NoError(t, eng.processReq(req))
+	req.SetParam(map[string]string{"db": "db0", "pt": "0"})
+	require.Error(t, eng.processReq(req))
+
+	idx := eng.DBPartitions["db0"][0].indexBuilder[659].GetPrimaryIndex().(*tsi.MergeSetIndex)
+	idx.DebugFlush()
+	keys, err := eng.SeriesKeys("db0", []uint32{0}, [][]byte{[]byte("cpu")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 10, len(keys))
+}
+
+func TestRebuildIndexOffline(t *testing.T) {
+	eng, err := initEngine(t.TempDir())
+	require.NoError(t, err)
+
+	tm := time.Now().Truncate(time.Second)
+	rows, _, _ := GenDataRecord([]string{"cpu"}, 10, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows(defaultDb, defaultRp, defaultPtId, defaultShardId, rows, nil))
+	eng.ForceFlush()
+	rpDir := filepath.Join(eng.DBPartitions[defaultDb][defaultPtId].path, defaultRp)
+	require.NoError(t, eng.Close())
+
+	rebuilt, missing, err := RebuildIndexOffline(rpDir, defaultShardId)
+	require.NoError(t, err)
+	require.Equal(t, 10, rebuilt)
+	require.Equal(t, 0, missing)
+
+	_, _, err = RebuildIndexOffline(rpDir, defaultShardId+1)
+	require.Equal(t, ErrIndexNotFound, err)
+}
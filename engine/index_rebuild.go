@@ -0,0 +1,176 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/engine/index/tsi"
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
+	"go.uber.org/zap"
+)
+
+const tsspFileSuffix = ".tssp"
+
+// RebuildIndex writes the index entries of the series found in the shards of the index group
+// again, from the series keys kept in the index. The series whose key is lost are counted as
+// missing, their data can not be found by the series key any more.
+func (e *Engine) RebuildIndex(db string, ptId uint32, indexID uint64) error {
+	e.log.Info("start rebuild index...", zap.String("db", db), zap.Uint32("pt", ptId), zap.Uint64("indexID", indexID))
+	start := time.Now()
+	indexGCStat.AddRebuildTotal(1)
+
+	g, err := e.acquireIndexGroup(indexGroupKey{db: db, pt: ptId, indexID: indexID})
+	if err != nil {
+		indexGCStat.AddErrors(1)
+		return err
+	}
+	defer g.release()
+
+	sids, _, err := g.seriesIDs()
+	if err != nil {
+		indexGCStat.AddErrors(1)
+		return err
+	}
+	rebuilt, missing, err := g.idx.RebuildIndexes(sids.AppendTo(nil))
+	indexGCStat.AddRebuiltSeries(int64(rebuilt))
+	indexGCStat.AddMissingSeries(int64(missing))
+	if err != nil {
+		indexGCStat.AddErrors(1)
+		return err
+	}
+
+	e.log.Info("rebuild index done", zap.String("db", db), zap.Uint64("indexID", indexID),
+		zap.Int("rebuilt", rebuilt), zap.Int("missing", missing), zap.Duration("time used", time.Since(start)))
+	return nil
+}
+
+// RebuildIndexOffline rebuilds the index indexID of the retention policy directory rpDir, from
+// the series of the tssp files of its shards. The store must be stopped, and its mem tables
+// flushed before, the series only in the wal get new index entries once replayed.
+func RebuildIndexOffline(rpDir string, indexID uint64) (rebuilt, missing int, err error) {
+	iBuild, err := openIndexOffline(rpDir, indexID)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if e := iBuild.Close(); err == nil {
+			err = e
+		}
+	}()
+	idx, ok := iBuild.GetPrimaryIndex().(*tsi.MergeSetIndex)
+	if !ok {
+		return 0, 0, fmt.Errorf("index %d isn't a mergeset index", indexID)
+	}
+
+	sids := &uint64set.Set{}
+	if err = loadShardSeriesIDs(rpDir, indexID, sids); err != nil {
+		return 0, 0, err
+	}
+	return idx.RebuildIndexes(sids.AppendTo(nil))
+}
+
+func openIndexOffline(rpDir string, indexID uint64) (*tsi.IndexBuilder, error) {
+	indexPath := path.Join(rpDir, IndexFileDirectory)
+	dirs, err := fileops.ReadDir(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dirs {
+		id, tr, err := parseIndexDir(d.Name())
+		if err != nil || id != indexID || !d.IsDir() {
+			continue
+		}
+
+		opts := new(tsi.Options).
+			Path(path.Join(indexPath, d.Name())).
+			IndexType(tsi.MergeSet).
+			EndTime(tr.EndTime)
+		iBuild := tsi.NewIndexBuilder(opts)
+		iBuild.Relations = make(map[uint32]*tsi.IndexRelation)
+		primaryIndex, err := tsi.NewIndex(opts)
+		if err != nil {
+			return nil, err
+		}
+		primaryIndex.SetIndexBuilder(iBuild)
+		relation, err := tsi.NewIndexRelation(opts, primaryIndex, iBuild)
+		if err != nil {
+			return nil, err
+		}
+		iBuild.Relations[uint32(tsi.MergeSet)] = relation
+		if err = iBuild.Open(); err != nil {
+			return nil, err
+		}
+		return iBuild, nil
+	}
+	return nil, ErrIndexNotFound
+}
+
+// loadShardSeriesIDs adds the series of the tssp files of the shards of the index to dst.
+func loadShardSeriesIDs(rpDir string, indexID uint64, dst *uint64set.Set) error {
+	dirs, err := fileops.ReadDir(rpDir)
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		if !d.IsDir() || d.Name() == IndexFileDirectory {
+			continue
+		}
+		_, id, _, err := parseShardDir(d.Name())
+		if err != nil || id != indexID {
+			continue
+		}
+		tsspDir := path.Join(rpDir, d.Name(), immutable.TsspDirName)
+		err = filepath.Walk(tsspDir, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(name, tsspFileSuffix) {
+				return nil
+			}
+			return loadFileSeriesIDs(name, dst)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadFileSeriesIDs(name string, dst *uint64set.Set) error {
+	fi, err := immutable.NewFileInspector(name)
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+	defer func() {
+		_ = fi.Close()
+	}()
+	return fi.WalkChunkMetas(func(_ int, cm *immutable.ChunkMeta) error {
+		dst.Add(cm.GetSid())
+		return nil
+	})
+}
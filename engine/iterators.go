@@ -53,6 +53,9 @@ const (
 	unorderRowCount  = "unorder_row_count"
 	unorderDuration  = "unorder_duration"
 	aggIterCount     = "agg_iter"
+
+	skippedChunkCount   = "skipped_chunk_count"
+	skippedSegmentCount = "skipped_segment_count"
 )
 
 const (
@@ -245,11 +248,13 @@ func (s *shard) initGroupCursors(querySchema *executor.QuerySchema, parallelism
 			if c.ctx.schema.Len() <= 1 {
 				return nil, fmt.Errorf("no field selected")
 			}
+			c.ctx.lastValues = s.lastValuesFor(querySchema, c.ctx)
 		} else {
 			c.ctx.schema = schema
 			c.ctx.filterFieldsIdx = filterFieldsIdx
 			c.ctx.filterTags = filterTags
 			c.ctx.auxTags = auxTags
+			c.ctx.lastValues = cursors[0].(*groupCursor).ctx.lastValues
 		}
 
 		// init map
@@ -459,6 +464,9 @@ type idKeyCursorContext struct {
 	seriesPool      *record.RecordPool
 	tmsMergePool    *record.RecordPool
 	querySchema     *executor.QuerySchema
+
+	// lastValues answers the query instead of the mem tables and the files, if not nil
+	lastValues *mstLastValues
 }
 
 func (i *idKeyCursorContext) hasAuxTags() bool {
@@ -630,6 +638,8 @@ func (c *groupCursor) StartSpan(span *tracing.Span) {
 		c.span.CreateCounter(unorderDuration, "ns")
 		c.span.CreateCounter(tsmIterCount, "")
 		c.span.CreateCounter(tsmIterDuration, "ns")
+		c.span.CreateCounter(skippedChunkCount, "")
+		c.span.CreateCounter(skippedSegmentCount, "")
 		for _, cursor := range c.tagSetCursors {
 			cursor.StartSpan(c.span)
 		}
@@ -638,6 +648,8 @@ func (c *groupCursor) StartSpan(span *tracing.Span) {
 
 func (c *groupCursor) EndSpan() {
 	if c.span != nil {
+		c.span.Count(skippedChunkCount, c.ctx.decs.SkippedChunks())
+		c.span.Count(skippedSegmentCount, c.ctx.decs.SkippedSegments())
 		c.span.Finish()
 	}
 }
@@ -1377,8 +1389,17 @@ func (s *shard) newSeriesCursor(ctx *idKeyCursorContext, span *tracing.Span, sch
 		tm = time.Now()
 	}
 
-	// get record from mem table which match the select cond
-	memTableRecord := s.GetValuesInMutableAndSnapshot(schema.Options().OptionsName(), sid, ctx.tr, ctx.schema, schema.Options().IsAscending())
+	var memTableRecord *record.Record
+	var tsmCursor *tsmMergeCursor
+	cached := false
+	if ctx.lastValues != nil {
+		// the latest values of the series are read from the last value cache, as if they were in the mem table
+		memTableRecord, cached = ctx.lastValues.values(sid, ctx.tr, ctx.schema, schema.Options().IsAscending())
+	}
+	if !cached {
+		// get record from mem table which match the select cond
+		memTableRecord = s.GetValuesInMutableAndSnapshot(schema.Options().OptionsName(), sid, ctx.tr, ctx.schema, schema.Options().IsAscending())
+	}
 
 	memTableRecord = immutable.FilterByField(memTableRecord, ctx.m, filter, ctx.filterFieldsIdx, ctx.filterTags, ptTags)
 
@@ -1389,12 +1410,12 @@ func (s *shard) newSeriesCursor(ctx *idKeyCursorContext, span *tracing.Span, sch
 		}
 	}
 
-	// create tsm cursor
-	var tsmCursor *tsmMergeCursor
-	tsmCursor, err = NewTsmMergeCursor(ctx, sid, filter, ptTags, span)
-
-	if err != nil {
-		return nil, err
+	if !cached {
+		// create tsm cursor
+		tsmCursor, err = NewTsmMergeCursor(ctx, sid, filter, ptTags, span)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// only if tsm or mem table have data, we will create series cursor
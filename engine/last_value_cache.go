@@ -0,0 +1,517 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/immutable"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/github.com/savsgio/dictpool"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"go.uber.org/zap"
+)
+
+// lastValueCacheSize is the memory used by the last value caches of all the shards
+var lastValueCacheSize int64
+
+const (
+	lastValueOverhead  = 64
+	lastSeriesOverhead = 48
+)
+
+// lastValueCache keeps the latest value of each field of the series in a shard.
+// It holds every series written to the shard, so a series missing in the cache has
+// no data. A measurement exceeding the memory limit is no longer cached, until the
+// memory used by all the caches falls back under the limit.
+type lastValueCache struct {
+	maxSize int64
+	msts    map[string]struct{} // measurements cached, empty for all
+
+	mu     sync.RWMutex
+	tables map[string]*mstLastValues
+}
+
+func newLastValueCache(options netstorage.EngineOptions) *lastValueCache {
+	if !options.LastValueCacheEnabled {
+		return nil
+	}
+	c := &lastValueCache{
+		maxSize: options.LastValueCacheMaxSize,
+		msts:    make(map[string]struct{}, len(options.LastValueCacheMeasurements)),
+		tables:  make(map[string]*mstLastValues),
+	}
+	for _, name := range options.LastValueCacheMeasurements {
+		c.msts[name] = struct{}{}
+	}
+	return c
+}
+
+func (c *lastValueCache) enabled(name string) bool {
+	if len(c.msts) == 0 {
+		return true
+	}
+	_, ok := c.msts[name]
+	return ok
+}
+
+// table returns the values of the measurement, nil if it is not cached.
+func (c *lastValueCache) table(name string) *mstLastValues {
+	c.mu.RLock()
+	t := c.tables[name]
+	c.mu.RUnlock()
+	if t != nil || !c.enabled(name) {
+		return t
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t = c.tables[name]; t == nil {
+		t = &mstLastValues{name: name, maxSize: c.maxSize, series: make(map[uint64][]lastValue)}
+		c.tables[name] = t
+	}
+	return t
+}
+
+// measurement returns the values of the measurement to answer a query, nil if it is
+// not cached or has exceeded the memory limit.
+func (c *lastValueCache) measurement(name string) *mstLastValues {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t := c.tables[name]
+	if t == nil || !t.isReady() {
+		return nil
+	}
+	return t
+}
+
+// update keeps the values of the rows written to the shard.
+func (c *lastValueCache) update(mmPoints *dictpool.Dict) {
+	if c == nil {
+		return
+	}
+	for _, mapp := range mmPoints.D {
+		rows, ok := mapp.Value.(*[]influx.Row)
+		if !ok {
+			continue
+		}
+		if t := c.table(mapp.Key); t != nil {
+			t.updateRows(*rows)
+		}
+	}
+}
+
+// warmUp loads the latest values from the files of the shard.
+func (c *lastValueCache) warmUp(store immutable.TablesStore) {
+	if c == nil {
+		return
+	}
+	for _, name := range store.Measurements() {
+		if t := c.table(name); t != nil {
+			t.warmUp(store)
+		}
+	}
+}
+
+// reenable caches again the measurements disabled over the memory limit, if their
+// last size fits in the limit now. The caller blocks the writes to the shard, so the
+// values written later are kept and the older ones are loaded by warmUpReenabled
+// once they are in the files. The tables are not used by queries until then.
+func (c *lastValueCache) reenable() []*mstLastValues {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var tables []*mstLastValues
+	for _, t := range c.tables {
+		if t.reenable() {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// warmUpReenabled loads the values of the re-enabled tables from the files of the shard.
+func (c *lastValueCache) warmUpReenabled(store immutable.TablesStore, tables []*mstLastValues) {
+	for _, t := range tables {
+		t.warmUp(store)
+		t.endWarmUp()
+	}
+}
+
+func (c *lastValueCache) dropMeasurement(name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	t := c.tables[name]
+	delete(c.tables, name)
+	c.mu.Unlock()
+	if t != nil {
+		t.disable()
+	}
+}
+
+// close releases the memory of the cache.
+func (c *lastValueCache) close() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, t := range c.tables {
+		t.disable()
+		delete(c.tables, name)
+	}
+}
+
+type lastValue struct {
+	name string
+	typ  int
+	time int64
+	num  float64
+	str  string
+}
+
+func (v *lastValue) size() int64 {
+	return lastValueOverhead + int64(len(v.name)+len(v.str))
+}
+
+// mstLastValues holds the latest values of the series of a measurement.
+type mstLastValues struct {
+	name    string
+	maxSize int64
+
+	mu       sync.RWMutex
+	disabled bool
+	warming  bool  // re-enabled, the values in the files are not loaded yet
+	lastSize int64 // size when disabled over the memory limit
+	size     int64
+	series   map[uint64][]lastValue
+}
+
+func (t *mstLastValues) isDisabled() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.disabled
+}
+
+// isReady returns true if the table holds the values of all the series.
+func (t *mstLastValues) isReady() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return !t.disabled && !t.warming
+}
+
+func (t *mstLastValues) reenable() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.disabled || t.lastSize == 0 || atomic.LoadInt64(&lastValueCacheSize)+t.lastSize > t.maxSize {
+		return false
+	}
+	t.disabled, t.warming = false, true
+	t.lastSize = 0
+	t.series = make(map[uint64][]lastValue)
+	return true
+}
+
+func (t *mstLastValues) endWarmUp() {
+	t.mu.Lock()
+	t.warming = false
+	t.mu.Unlock()
+}
+
+// warmUp loads the latest values from the files of the measurement.
+func (t *mstLastValues) warmUp(store immutable.TablesStore) {
+	for _, isOrder := range []bool{true, false} {
+		files := store.GetFilesRef(t.name, isOrder)
+		sort.Slice(files, func(i, j int) bool {
+			_, si := files[i].LevelAndSequence()
+			_, sj := files[j].LevelAndSequence()
+			return si < sj
+		})
+		for _, f := range files {
+			if !t.isDisabled() {
+				err := immutable.ReadLastSegments(f, t.updateRecord)
+				if err != nil {
+					log.Error("warm up last value cache failed", zap.String("file", f.Path()), zap.Error(err))
+					t.disable()
+				}
+			}
+			f.Unref()
+		}
+	}
+}
+
+func (t *mstLastValues) disable() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.disableLocked()
+}
+
+func (t *mstLastValues) disableLocked() {
+	if t.disabled {
+		return
+	}
+	t.disabled, t.warming = true, false
+	t.series = nil
+	atomic.AddInt64(&lastValueCacheSize, -t.size)
+	t.size = 0
+}
+
+// grow accounts the memory of new values, the measurement is disabled if the limit is exceeded.
+func (t *mstLastValues) grow(delta int64) bool {
+	if delta == 0 {
+		return true
+	}
+	t.size += delta
+	if atomic.AddInt64(&lastValueCacheSize, delta) > t.maxSize {
+		log.Warn("last value cache exceeds the memory limit, measurement is no longer cached",
+			zap.String("measurement", t.name), zap.Int64("limit", t.maxSize))
+		size := t.size
+		t.disableLocked()
+		t.lastSize = size
+		return false
+	}
+	return true
+}
+
+// set replaces the value of the field if it is not older, it returns the memory delta.
+// The values read from the files while warming up do not replace the written ones of the same time.
+func (t *mstLastValues) set(sid uint64, typ int, name string, tm int64, num float64, str string, fromFile bool) int64 {
+	if typ != influx.Field_Type_String {
+		str = ""
+	}
+	values, ok := t.series[sid]
+	var delta int64
+	if !ok {
+		delta += lastSeriesOverhead
+	}
+	for i := range values {
+		v := &values[i]
+		if v.name != name {
+			continue
+		}
+		if tm < v.time || (tm == v.time && fromFile && t.warming) {
+			return delta
+		}
+		delta += int64(len(str) - len(v.str))
+		v.typ, v.time, v.num, v.str = typ, tm, num, copyString(str)
+		return delta
+	}
+	v := lastValue{name: copyString(name), typ: typ, time: tm, num: num, str: copyString(str)}
+	t.series[sid] = append(values, v)
+	return delta + v.size()
+}
+
+func copyString(s string) string {
+	if s == "" {
+		return ""
+	}
+	return string(append([]byte(nil), s...))
+}
+
+func (t *mstLastValues) updateRows(rows []influx.Row) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.disabled {
+		return
+	}
+	var delta int64
+	for i := range rows {
+		r := &rows[i]
+		if r.SeriesId == 0 {
+			continue
+		}
+		for j := range r.Fields {
+			f := &r.Fields[j]
+			typ := int(f.Type)
+			if typ == influx.Field_Type_UInt {
+				typ = influx.Field_Type_Int
+			}
+			delta += t.set(r.SeriesId, typ, f.Key, r.Timestamp, f.NumValue, f.StrValue, false)
+		}
+	}
+	t.grow(delta)
+}
+
+// updateRecord keeps the last value of each column of the record read from a file.
+func (t *mstLastValues) updateRecord(sid uint64, rec *record.Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.disabled {
+		return
+	}
+	var delta int64
+	for c := 0; c < rec.Schema.Len()-1; c++ {
+		col := rec.Column(c)
+		for i := rec.RowNums() - 1; i >= 0; i-- {
+			if col.IsNil(i) {
+				continue
+			}
+			field := &rec.Schema[c]
+			var num float64
+			var str string
+			switch field.Type {
+			case influx.Field_Type_Int:
+				v, _ := col.IntegerValue(i)
+				num = float64(v)
+			case influx.Field_Type_Float:
+				num, _ = col.FloatValue(i)
+			case influx.Field_Type_Boolean:
+				if v, _ := col.BooleanValue(i); v {
+					num = 1
+				}
+			case influx.Field_Type_String:
+				str, _ = col.StringValueUnsafe(i)
+			}
+			delta += t.set(sid, field.Type, field.Name, rec.Time(i), num, str, true)
+			break
+		}
+	}
+	t.grow(delta)
+}
+
+// values returns the latest values of the fields in the schema within the time range,
+// one row per distinct time. It returns false if a value is newer than the time range,
+// the cache can not tell the last value in the range then.
+func (t *mstLastValues) values(sid uint64, tr record.TimeRange, schema record.Schemas, ascending bool) (*record.Record, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.disabled || t.warming {
+		return nil, false
+	}
+	values, ok := t.series[sid]
+	if !ok {
+		return nil, true
+	}
+
+	selected := make([]*lastValue, schema.Len()-1)
+	times := make([]int64, 0, len(selected))
+	for i := range selected {
+		for j := range values {
+			v := &values[j]
+			if v.name != schema[i].Name {
+				continue
+			}
+			if v.time > tr.Max || v.typ != schema[i].Type {
+				return nil, false
+			}
+			if v.time >= tr.Min {
+				selected[i] = v
+				times = append(times, v.time)
+			}
+			break
+		}
+	}
+	if len(times) == 0 {
+		return nil, true
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		if ascending {
+			return times[i] < times[j]
+		}
+		return times[i] > times[j]
+	})
+	n := 0
+	for i := range times {
+		if i == 0 || times[i] != times[n-1] {
+			times[n] = times[i]
+			n++
+		}
+	}
+	times = times[:n]
+
+	rec := record.NewRecordBuilder(schema)
+	for i, v := range selected {
+		col := rec.Column(i)
+		for _, tm := range times {
+			if v == nil || v.time != tm {
+				appendNull(col, schema[i].Type)
+				continue
+			}
+			switch v.typ {
+			case influx.Field_Type_Int:
+				col.AppendInteger(int64(v.num))
+			case influx.Field_Type_Float:
+				col.AppendFloat(v.num)
+			case influx.Field_Type_Boolean:
+				col.AppendBoolean(v.num != 0)
+			case influx.Field_Type_String:
+				col.AppendString(v.str)
+			}
+		}
+	}
+	rec.AppendTime(times...)
+	return rec, true
+}
+
+func appendNull(col *record.ColVal, typ int) {
+	switch typ {
+	case influx.Field_Type_Int:
+		col.AppendIntegerNull()
+	case influx.Field_Type_Float:
+		col.AppendFloatNull()
+	case influx.Field_Type_Boolean:
+		col.AppendBooleanNull()
+	case influx.Field_Type_String:
+		col.AppendStringNull()
+	}
+}
+
+// lastValuesFor returns the last value cache of the measurement if it answers the query,
+// which only selects last() of the fields without an interval or a field condition.
+func (s *shard) lastValuesFor(querySchema *executor.QuerySchema, ctx *idKeyCursorContext) *mstLastValues {
+	if s.lastValues == nil || (executor.GetEnableFileCursor() && querySchema.HasInSeriesAgg()) {
+		return nil
+	}
+	// descending cursors keep the last row of the scan order, which is not the latest value
+	if !querySchema.Options().IsAscending() {
+		return nil
+	}
+	if !hasCall(querySchema) || hasInterval(querySchema) || hasFieldCondition(ctx) || ctx.hasAuxTags() {
+		return nil
+	}
+
+	refs := make(map[string]struct{}, len(querySchema.OrigCalls()))
+	for _, call := range querySchema.OrigCalls() {
+		if call.Name != "last" || len(call.Args) != 1 {
+			return nil
+		}
+		ref, ok := call.Args[0].(*influxql.VarRef)
+		if !ok {
+			return nil
+		}
+		refs[ref.Val] = struct{}{}
+	}
+	for i := 0; i < ctx.schema.Len()-1; i++ {
+		if _, ok := refs[ctx.schema[i].Name]; !ok {
+			return nil
+		}
+	}
+	return s.lastValues.measurement(querySchema.Options().OptionsName())
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/engine/comm"
+	"github.com/openGemini/openGemini/engine/index/tsi"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/record"
+	"github.com/openGemini/openGemini/open_src/github.com/savsgio/dictpool"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+func createLastValueShard(t *testing.T, dir string) *shard {
+	opt := defaultEngineOption
+	defaultEngineOption.LastValueCacheEnabled = true
+	defaultEngineOption.LastValueCacheMaxSize = 64 * 1024 * 1024
+	defer func() {
+		defaultEngineOption = opt
+	}()
+	sh, err := createShard(defaultDb, defaultRp, defaultPtId, dir)
+	require.NoError(t, err)
+	require.NotNil(t, sh.lastValues)
+	return sh
+}
+
+// expectLastValues returns the latest value of each field of each series, keyed by index key and field
+func expectLastValues(rows []influx.Row) map[string]map[string]influx.Field {
+	times := make(map[string]map[string]int64)
+	values := make(map[string]map[string]influx.Field)
+	for i := range rows {
+		key := string(rows[i].IndexKey)
+		if values[key] == nil {
+			values[key] = make(map[string]influx.Field)
+			times[key] = make(map[string]int64)
+		}
+		for _, f := range rows[i].Fields {
+			if tm, ok := times[key][f.Key]; ok && tm > rows[i].Timestamp {
+				continue
+			}
+			times[key][f.Key] = rows[i].Timestamp
+			values[key][f.Key] = f
+		}
+	}
+	return values
+}
+
+func checkLastValues(t *testing.T, sh *shard, rows []influx.Row) {
+	idx := sh.indexBuilder.GetPrimaryIndex().(*tsi.MergeSetIndex)
+	table := sh.lastValues.measurement(defaultMeasurementName)
+	require.NotNil(t, table)
+	schema := record.Schemas{
+		{Name: "field1_string", Type: influx.Field_Type_String},
+		{Name: "field2_int", Type: influx.Field_Type_Int},
+		{Name: "field3_bool", Type: influx.Field_Type_Boolean},
+		{Name: "field4_float", Type: influx.Field_Type_Float},
+		{Name: record.TimeField, Type: influx.Field_Type_Int},
+	}
+
+	for key, fields := range expectLastValues(rows) {
+		sid, err := idx.GetSeriesIdBySeriesKey([]byte(key), []byte(defaultMeasurementName))
+		require.NoError(t, err)
+		rec, ok := table.values(sid, record.TimeRange{Min: math.MinInt64, Max: math.MaxInt64}, schema, true)
+		require.True(t, ok)
+		require.NotNil(t, rec)
+		for i := 0; i < schema.Len()-1; i++ {
+			expect, ok := fields[schema[i].Name]
+			col := rec.Column(i)
+			if !ok {
+				require.Equal(t, col.Len, col.NilCount)
+				continue
+			}
+			found := false
+			for j := 0; j < rec.RowNums(); j++ {
+				if col.IsNil(j) {
+					continue
+				}
+				found = true
+				switch schema[i].Type {
+				case influx.Field_Type_String:
+					v, _ := col.StringValueSafe(j)
+					require.Equal(t, expect.StrValue, v)
+				case influx.Field_Type_Int:
+					v, _ := col.IntegerValue(j)
+					require.Equal(t, int64(expect.NumValue), v)
+				case influx.Field_Type_Boolean:
+					v, _ := col.BooleanValue(j)
+					require.Equal(t, expect.NumValue != 0, v)
+				case influx.Field_Type_Float:
+					v, _ := col.FloatValue(j)
+					require.Equal(t, expect.NumValue, v)
+				}
+			}
+			require.True(t, found)
+		}
+	}
+}
+
+func TestLastValueCache_WriteAndWarmUp(t *testing.T) {
+	dir := t.TempDir()
+	sh := createLastValueShard(t, dir)
+
+	now := time.Now().Truncate(time.Second)
+	rows, _, _ := GenDataRecord([]string{defaultMeasurementName}, 10, 5, time.Second, now, false, true, true)
+	require.NoError(t, writeData(sh, rows, true))
+	// out of order rows older than the cached values
+	old, _, _ := GenDataRecord([]string{defaultMeasurementName}, 10, 2, time.Second, now.Add(-time.Hour), false, true, true)
+	for i := range old {
+		for j := range old[i].Fields {
+			old[i].Fields[j].NumValue += 1000
+			old[i].Fields[j].StrValue += "-old"
+		}
+	}
+	require.NoError(t, writeData(sh, old, false))
+	// newer rows still in the mem table and the wal
+	newer, _, _ := GenDataRecord([]string{defaultMeasurementName}, 5, 1, time.Second, now.Add(time.Minute), false, true, true)
+	require.NoError(t, writeData(sh, newer, false))
+
+	all := append(append(append([]influx.Row{}, rows...), old...), newer...)
+	checkLastValues(t, sh, all)
+	require.True(t, lastValueCacheSize > 0)
+
+	// warm up from the files and the wal
+	require.NoError(t, closeShard(sh))
+	require.Equal(t, int64(0), lastValueCacheSize)
+	sh = createLastValueShard(t, dir)
+	checkLastValues(t, sh, all)
+
+	sh.lastValues.dropMeasurement(defaultMeasurementName)
+	require.Nil(t, sh.lastValues.measurement(defaultMeasurementName))
+	require.NoError(t, closeShard(sh))
+	require.Equal(t, int64(0), lastValueCacheSize)
+}
+
+func TestLastValueCache_MemoryLimit(t *testing.T) {
+	c := newLastValueCache(netstorage.EngineOptions{
+		LastValueCacheEnabled:      true,
+		LastValueCacheMeasurements: []string{"cpu"},
+		LastValueCacheMaxSize:      1024,
+	})
+	rows, _, _ := GenDataRecord([]string{"cpu", "mem"}, 4, 1, time.Second, time.Now(), false, true, true)
+	for i := range rows {
+		rows[i].SeriesId = uint64(i + 1)
+	}
+	update := func() {
+		var mmPoints dictpool.Dict
+		for i := range rows {
+			r := []influx.Row{rows[i]}
+			if v := mmPoints.Get(rows[i].Name); v != nil {
+				r = append(*(v.(*[]influx.Row)), rows[i])
+			}
+			mmPoints.Set(rows[i].Name, &r)
+		}
+		c.update(&mmPoints)
+	}
+
+	update()
+	require.NotNil(t, c.measurement("cpu"))
+	require.Nil(t, c.measurement("mem"))
+	require.True(t, lastValueCacheSize > 0)
+
+	c.maxSize = 0
+	c.table("cpu").maxSize = 0
+	rows[0].SeriesId = 100
+	update()
+	require.Nil(t, c.measurement("cpu"))
+	require.Equal(t, int64(0), lastValueCacheSize)
+	c.close()
+}
+
+func TestLastValueCache_Reenable(t *testing.T) {
+	dir := t.TempDir()
+	sh := createLastValueShard(t, dir)
+	defer func() {
+		require.NoError(t, closeShard(sh))
+		require.Equal(t, int64(0), lastValueCacheSize)
+	}()
+
+	now := time.Now().Truncate(time.Second)
+	rows, _, _ := GenDataRecord([]string{defaultMeasurementName}, 10, 5, time.Second, now, false, true, true)
+	require.NoError(t, writeData(sh, rows, true))
+	table := sh.lastValues.measurement(defaultMeasurementName)
+	require.NotNil(t, table)
+
+	// exceeds the memory limit
+	maxSize := table.maxSize
+	table.maxSize = 0
+	newer, _, _ := GenDataRecord([]string{defaultMeasurementName}, 10, 1, time.Second, now.Add(time.Minute), false, true, true)
+	require.NoError(t, writeData(sh, newer, true))
+	require.Nil(t, sh.lastValues.measurement(defaultMeasurementName))
+	require.Equal(t, int64(0), lastValueCacheSize)
+
+	// still over the limit at the next snapshot
+	sh.ForceFlush()
+	require.Nil(t, sh.lastValues.measurement(defaultMeasurementName))
+
+	// the memory falls back under the limit, the values are loaded from the files
+	table.maxSize = maxSize
+	latest, _, _ := GenDataRecord([]string{defaultMeasurementName}, 5, 1, time.Second, now.Add(2*time.Minute), false, true, true)
+	require.NoError(t, writeData(sh, latest, true))
+	all := append(append(append([]influx.Row{}, rows...), newer...), latest...)
+	checkLastValues(t, sh, all)
+	require.True(t, lastValueCacheSize > 0)
+}
+
+func queryLast(t *testing.T, sh *shard, field influxql.VarRef, start, end int64, ascending bool) map[string]string {
+	var opt query.ProcessorOptions
+	opt.Name = defaultMeasurementName
+	opt.Ascending = ascending
+	opt.MaxParallel = 1
+	opt.ChunkSize = defaultChunkSize
+	opt.StartTime = start
+	opt.EndTime = end
+	calls := genCall([]influxql.VarRef{field}, "last")
+	schema := genAggQuerySchema(nil, calls, &opt)
+
+	cursors, err := sh.CreateCursor(context.Background(), schema)
+	require.NoError(t, err)
+	var callOps []*comm.CallOption
+	for i := range calls {
+		callOps = append(callOps, &comm.CallOption{Call: &calls[i], Ref: &field})
+	}
+	comm.KeyCursors(cursors).SetOps(callOps)
+
+	result := make(map[string]string)
+	for _, cur := range cursors {
+		for {
+			rec, info, err := cur.Next()
+			require.NoError(t, err)
+			if rec == nil {
+				break
+			}
+			var b strings.Builder
+			for i := 0; i < rec.RowNums(); i++ {
+				b.WriteString(fmt.Sprintf("%d:%v;", rec.Time(i), recordValue(rec, 0, i)))
+			}
+			result[string(info.GetSeriesKey())] += b.String()
+		}
+		require.NoError(t, cur.Close())
+	}
+	return result
+}
+
+func recordValue(rec *record.Record, col, row int) interface{} {
+	cv := rec.Column(col)
+	switch rec.Schema[col].Type {
+	case influx.Field_Type_Int:
+		v, isNil := cv.IntegerValue(row)
+		return fmt.Sprint(v, isNil)
+	case influx.Field_Type_Float:
+		v, isNil := cv.FloatValue(row)
+		return fmt.Sprint(v, isNil)
+	case influx.Field_Type_Boolean:
+		v, isNil := cv.BooleanValue(row)
+		return fmt.Sprint(v, isNil)
+	default:
+		v, isNil := cv.StringValueSafe(row)
+		return fmt.Sprint(v, isNil)
+	}
+}
+
+func TestLastValueCache_Query(t *testing.T) {
+	dir := t.TempDir()
+	sh := createLastValueShard(t, dir)
+	defer func() {
+		require.NoError(t, closeShard(sh))
+	}()
+
+	now := time.Now().Truncate(time.Second)
+	rows, minTime, maxTime := GenDataRecord([]string{defaultMeasurementName}, 10, 5, time.Second, now, false, true, true)
+	require.NoError(t, writeData(sh, rows, true))
+	newer, _, newerMax := GenDataRecord([]string{defaultMeasurementName}, 5, 1, time.Second, now.Add(time.Minute), false, true, true)
+	require.NoError(t, writeData(sh, newer, false))
+
+	ranges := [][2]int64{
+		{math.MinInt64, math.MaxInt64},
+		{maxTime, math.MaxInt64},
+		{newerMax + 1, math.MaxInt64},
+		// older than the cached values, answered from the files
+		{minTime, maxTime},
+	}
+	cache := sh.lastValues
+	for _, field := range createFieldAux(nil) {
+		for _, tr := range ranges {
+			for _, ascending := range []bool{true, false} {
+				sh.lastValues = cache
+				cached := queryLast(t, sh, field, tr[0], tr[1], ascending)
+				sh.lastValues = nil
+				expect := queryLast(t, sh, field, tr[0], tr[1], ascending)
+				if !reflect.DeepEqual(expect, cached) {
+					t.Errorf("field %s, range %v, asc %v", field.Val, tr, ascending)
+				}
+			}
+		}
+	}
+	sh.lastValues = cache
+
+	// the cache is only used by ascending last() without an interval
+	var opt query.ProcessorOptions
+	opt.Name = defaultMeasurementName
+	opt.Ascending = true
+	fields := createFieldAux([]string{"field2_int"})
+	ctx := &idKeyCursorContext{}
+	schema := genAggQuerySchema(nil, genCall(fields, "last"), &opt)
+	require.NoError(t, newCursorSchema(ctx, schema))
+	require.NotNil(t, sh.lastValuesFor(schema, ctx))
+	schema = genAggQuerySchema(nil, genCall(fields, "first"), &opt)
+	require.Nil(t, sh.lastValuesFor(schema, ctx))
+	opt.Ascending = false
+	schema = genAggQuerySchema(nil, genCall(fields, "last"), &opt)
+	require.Nil(t, sh.lastValuesFor(schema, ctx))
+	opt.Ascending = true
+	opt.Interval.Duration = time.Minute
+	schema = genAggQuerySchema(nil, genCall(fields, "last"), &opt)
+	require.Nil(t, sh.lastValuesFor(schema, ctx))
+}
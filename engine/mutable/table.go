@@ -34,6 +34,7 @@ import (
 	"github.com/openGemini/openGemini/lib/stringinterner"
 	"github.com/openGemini/openGemini/open_src/github.com/savsgio/dictpool"
 	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
 	"go.uber.org/zap"
 )
 
@@ -196,6 +197,34 @@ func (t *MemTable) GetSids(msName string, sids []uint64) []uint64 {
 	return sids
 }
 
+// SeriesIDs adds the ids of the series written to the table of all the measurements to dst.
+func (t *MemTable) SeriesIDs(dst *uint64set.Set) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, msInfo := range t.msInfoMap {
+		msInfo.mu.RLock()
+		for sid := range msInfo.sidMap {
+			dst.Add(sid)
+		}
+		msInfo.mu.RUnlock()
+	}
+}
+
+// SeriesIDsIn adds the ids among tsids of the series written to the table to dst.
+func (t *MemTable) SeriesIDsIn(tsids []uint64, dst *uint64set.Set) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, msInfo := range t.msInfoMap {
+		msInfo.mu.RLock()
+		for _, sid := range tsids {
+			if _, ok := msInfo.sidMap[sid]; ok {
+				dst.Add(sid)
+			}
+		}
+		msInfo.mu.RUnlock()
+	}
+}
+
 type SortAuxPool struct {
 	cache chan *record.SortAux
 	pool  *sync.Pool
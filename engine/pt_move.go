@@ -0,0 +1,338 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/fileops"
+	"github.com/openGemini/openGemini/lib/netstorage"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"go.uber.org/zap"
+)
+
+// ptFileChunkSize is the size of the chunks a moving pt is copied by
+const ptFileChunkSize = 4 * 1024 * 1024
+
+// movingDirectory holds the files of a pt being copied from another node,
+// it is out of the data and wal directories so the partly copied pt is never loaded
+const movingDirectory = ".moving"
+
+// dbPtKey identifies a db pt which is offloaded from this node
+type dbPtKey struct {
+	db string
+	pt uint32
+}
+
+func (e *Engine) ptDataPath(db string, pt uint32) string {
+	return path.Join(e.dataPath, DataDirectory, db, strconv.Itoa(int(pt)))
+}
+
+func (e *Engine) ptWalPath(db string, pt uint32) string {
+	return path.Join(e.walPath, WalDirectory, db, strconv.Itoa(int(pt)))
+}
+
+// isOffloaded returns true if the pt is moving or moved to another node, the writes
+// to it must fail instead of creating an empty pt. The caller must hold e.mu.
+func (e *Engine) isOffloaded(db string, pt uint32) bool {
+	_, ok := e.offloadedPts[dbPtKey{db: db, pt: pt}]
+	return ok
+}
+
+// PreOffloadDBPT closes the pt and keeps its files, so they can be copied to the node the pt moves to.
+// The pt rejects the reads and writes until it is loaded again.
+func (e *Engine) PreOffloadDBPT(db string, ptId uint32) error {
+	key := dbPtKey{db: db, pt: ptId}
+	e.mu.Lock()
+	dbPTInfo, ok := e.DBPartitions[db][ptId]
+	e.offloadedPts[key] = struct{}{}
+	e.mu.Unlock()
+	if !ok {
+		// the pt is not written yet or is already closed
+		return nil
+	}
+
+	done := make(chan bool, 1)
+	if ok := dbPTInfo.markOffload(done); !ok {
+		select {
+		case <-done:
+		case <-time.After(15 * time.Second):
+			e.log.Warn("offload dbPt timeout", zap.String("db", db), zap.Uint32("pt id", ptId))
+			dbPTInfo.unMarkOffload()
+			e.mu.Lock()
+			delete(e.offloadedPts, key)
+			e.mu.Unlock()
+			return meta2.ErrConflictWithIo
+		}
+	}
+
+	e.mu.Lock()
+	e.dropDBPTInfo(db, ptId)
+	e.mu.Unlock()
+
+	if err := dbPTInfo.closeDBPt(); err != nil {
+		e.log.Error("close db pt failed", zap.String("db", db), zap.Uint32("pt", ptId), zap.Error(err))
+		return err
+	}
+	e.log.Info("db pt is pre offloaded", zap.String("db", db), zap.Uint32("pt", ptId))
+	return nil
+}
+
+// LoadDBPT opens the pt from the files on the disk, it serves a pt copied from
+// another node or a pt whose move is rolled back.
+func (e *Engine) LoadDBPT(db string, ptId uint32, durationInfos map[uint64]*meta2.ShardDurationInfo) error {
+	e.mu.Lock()
+	delete(e.offloadedPts, dbPtKey{db: db, pt: ptId})
+	exist := e.isDBPtExist(db, ptId)
+	e.mu.Unlock()
+	if exist {
+		return nil
+	}
+
+	rps, err := fileops.ReadDir(e.ptDataPath(db, ptId))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, rp := range rps {
+		if !rp.IsDir() {
+			continue
+		}
+		if err = e.loadDbRp(db, ptId, rp.Name(), durationInfos); err != nil {
+			return err
+		}
+	}
+	e.createDBPTIfNotExist(db, ptId)
+	e.log.Info("db pt is loaded", zap.String("db", db), zap.Uint32("pt", ptId))
+	return nil
+}
+
+// OffloadDBPT deletes the files of a pre offloaded pt after they are copied to another node
+func (e *Engine) OffloadDBPT(db string, ptId uint32) error {
+	e.mu.RLock()
+	exist := e.isDBPtExist(db, ptId)
+	e.mu.RUnlock()
+	if exist {
+		return fmt.Errorf("db %s pt %d is not pre offloaded", db, ptId)
+	}
+	return deleteDataAndWalPath(e.ptDataPath(db, ptId), e.ptWalPath(db, ptId))
+}
+
+// checkPreOffloaded makes sure the files of the pt are not changed while they are copied
+func (e *Engine) checkPreOffloaded(db string, ptId uint32) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if !e.isOffloaded(db, ptId) || e.isDBPtExist(db, ptId) {
+		return fmt.Errorf("db %s pt %d is not pre offloaded", db, ptId)
+	}
+	return nil
+}
+
+// DBPTFiles lists the directories and the files of a pre offloaded pt. The names are
+// relative to the pt and prefixed by the data or the wal directory, the directories end with a slash.
+func (e *Engine) DBPTFiles(db string, ptId uint32) ([]string, error) {
+	if err := e.checkPreOffloaded(db, ptId); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	roots := map[string]string{DataDirectory: e.ptDataPath(db, ptId), WalDirectory: e.ptWalPath(db, ptId)}
+	for dir, root := range roots {
+		if err := listFiles(root, dir, &files); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func listFiles(dir, name string, files *[]string) error {
+	fis, err := fileops.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	*files = append(*files, name+"/")
+	for _, fi := range fis {
+		if fi.IsDir() {
+			if err = listFiles(path.Join(dir, fi.Name()), path.Join(name, fi.Name()), files); err != nil {
+				return err
+			}
+			continue
+		}
+		*files = append(*files, path.Join(name, fi.Name()))
+	}
+	return nil
+}
+
+// splitPtFile splits a name listed by DBPTFiles into the data or the wal directory and the relative path
+func splitPtFile(name string) (string, string, error) {
+	name = path.Clean(name)
+	dir, rel := name, ""
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		dir, rel = name[:i], name[i+1:]
+	}
+	if (dir != DataDirectory && dir != WalDirectory) || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", "", fmt.Errorf("invalid pt file %s", name)
+	}
+	return dir, rel, nil
+}
+
+// ptFilePath maps a name listed by DBPTFiles to the path under the data root or the wal root of a pt
+func ptFilePath(dataRoot, walRoot, name string) (string, error) {
+	dir, rel, err := splitPtFile(name)
+	if err != nil {
+		return "", err
+	}
+	if dir == DataDirectory {
+		return path.Join(dataRoot, rel), nil
+	}
+	return path.Join(walRoot, rel), nil
+}
+
+// ReadDBPTFile reads at most size bytes of a file of a pre offloaded pt from offset, less bytes are
+// returned only at the end of the file. The size is capped by ptFileChunkSize, so the files are
+// streamed by chunks whatever the request asks for.
+func (e *Engine) ReadDBPTFile(db string, ptId uint32, name string, offset int64, size int) ([]byte, error) {
+	if size <= 0 || size > ptFileChunkSize {
+		size = ptFileChunkSize
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid offset %d of pt file %s", offset, name)
+	}
+	if err := e.checkPreOffloaded(db, ptId); err != nil {
+		return nil, err
+	}
+	p, err := ptFilePath(e.ptDataPath(db, ptId), e.ptWalPath(db, ptId), name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := fileops.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if remain := fi.Size() - offset; remain < int64(size) {
+		size = 0
+		if remain > 0 {
+			size = int(remain)
+		}
+	}
+
+	buf := make([]byte, size)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// ReceiveDBPT copies the files of a pt from another node. The files are written to a
+// moving directory first and renamed to the pt directories once all of them are copied.
+func (e *Engine) ReceiveDBPT(db string, ptId uint32, files []string, read netstorage.DBPTFileReader) error {
+	e.mu.RLock()
+	exist := e.isDBPtExist(db, ptId)
+	e.mu.RUnlock()
+	if exist {
+		return fmt.Errorf("db %s pt %d is already loaded", db, ptId)
+	}
+
+	pt := strconv.Itoa(int(ptId))
+	lock := fileops.FileLockOption("")
+	movingData := path.Join(e.dataPath, movingDirectory, db, pt)
+	movingWal := path.Join(e.walPath, movingDirectory, db, pt)
+	if err := deleteDataAndWalPath(movingData, movingWal); err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		p, err := ptFilePath(movingData, movingWal, name)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(name, "/") {
+			err = fileops.MkdirAll(p, 0750, lock)
+		} else {
+			err = receiveFile(p, name, read)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := deleteDataAndWalPath(e.ptDataPath(db, ptId), e.ptWalPath(db, ptId)); err != nil {
+		return err
+	}
+	for _, dir := range [][2]string{{movingData, e.ptDataPath(db, ptId)}, {movingWal, e.ptWalPath(db, ptId)}} {
+		if _, err := fileops.Stat(dir[0]); os.IsNotExist(err) {
+			continue
+		}
+		if err := fileops.MkdirAll(path.Dir(dir[1]), 0750, lock); err != nil {
+			return err
+		}
+		if err := fileops.RenameFile(dir[0], dir[1], lock); err != nil {
+			return err
+		}
+	}
+	e.log.Info("db pt is received", zap.String("db", db), zap.Uint32("pt", ptId), zap.Int("files", len(files)))
+	return nil
+}
+
+func receiveFile(p, name string, read netstorage.DBPTFileReader) error {
+	lock := fileops.FileLockOption("")
+	if err := fileops.MkdirAll(path.Dir(p), 0750, lock); err != nil {
+		return err
+	}
+	f, err := fileops.Create(p, lock)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	for {
+		b, err := read(name, offset, ptFileChunkSize)
+		if err == nil {
+			_, err = f.Write(b)
+		}
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		offset += int64(len(b))
+		if len(b) < ptFileChunkSize {
+			break
+		}
+	}
+
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
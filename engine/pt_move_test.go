@@ -0,0 +1,127 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/logger"
+	"github.com/openGemini/openGemini/lib/interruptsignal"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func initMoveEngine(t *testing.T, dir string, createShard bool) *Engine {
+	dataPath := filepath.Join(dir, dPath)
+	eng := &Engine{
+		closed:       interruptsignal.NewInterruptSignal(),
+		dataPath:     dataPath + "/data",
+		walPath:      dataPath + "/wal",
+		DBPartitions: make(map[string]map[uint32]*DBPTInfo, 64),
+		offloadedPts: make(map[dbPtKey]struct{}),
+		droppingDB:   make(map[string]string),
+		droppingRP:   make(map[string]string),
+		droppingMst:  make(map[string]string),
+	}
+	eng.log = logger.New(os.Stderr)
+	eng.engOpt.ShardMutableSizeLimit = 30 * 1024 * 1024
+	eng.engOpt.NodeMutableSizeLimit = 1e9
+	eng.engOpt.MaxWriteHangTime = time.Second
+	eng.loadCtx = getLoadCtx()
+	if createShard {
+		eng.CreateDBPT(defaultDb, defaultPtId)
+		require.NoError(t, eng.CreateShard(defaultDb, defaultRp, defaultPtId, defaultShardId, getTimeRangeInfo()))
+	}
+	return eng
+}
+
+func TestEngine_MoveDBPT(t *testing.T) {
+	src := initMoveEngine(t, t.TempDir(), true)
+	defer src.Close()
+	dst := initMoveEngine(t, t.TempDir(), false)
+	defer dst.Close()
+
+	tm := mustParseTime(time.RFC3339Nano, "1999-06-01T01:00:00Z")
+	rows, _, _ := GenDataRecord([]string{"mst"}, 10, 20, time.Second, tm, false, true, false)
+	require.NoError(t, src.WriteRows(defaultDb, defaultRp, defaultPtId, defaultShardId, rows, nil))
+	src.ForceFlush()
+
+	// the files are listed only after the pt is closed
+	_, err := src.DBPTFiles(defaultDb, defaultPtId)
+	require.Error(t, err)
+	require.NoError(t, src.PreOffloadDBPT(defaultDb, defaultPtId))
+	require.Equal(t, meta2.ErrDBPTClose, src.WriteRows(defaultDb, defaultRp, defaultPtId, defaultShardId, rows, nil))
+
+	files, err := src.DBPTFiles(defaultDb, defaultPtId)
+	require.NoError(t, err)
+	require.Contains(t, files, "data/")
+	require.Contains(t, files, "wal/")
+	_, err = src.ReadDBPTFile(defaultDb, defaultPtId, "data/../../x", 0, 10)
+	require.Error(t, err)
+	_, err = src.ReadDBPTFile(defaultDb, defaultPtId, "foo/x", 0, 10)
+	require.Error(t, err)
+	// the size of the request is capped, the files are read by chunks
+	for _, name := range files {
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+		b, err := src.ReadDBPTFile(defaultDb, defaultPtId, name, 0, 1<<40)
+		require.NoError(t, err)
+		require.True(t, len(b) <= ptFileChunkSize)
+		b, err = src.ReadDBPTFile(defaultDb, defaultPtId, name, 1<<40, 10)
+		require.NoError(t, err)
+		require.Empty(t, b)
+	}
+
+	read := func(name string, offset int64, size int) ([]byte, error) {
+		return src.ReadDBPTFile(defaultDb, defaultPtId, name, offset, size)
+	}
+	require.NoError(t, dst.ReceiveDBPT(defaultDb, defaultPtId, files, read))
+	require.NoError(t, src.OffloadDBPT(defaultDb, defaultPtId))
+	_, err = os.Stat(src.ptDataPath(defaultDb, defaultPtId))
+	require.True(t, os.IsNotExist(err))
+
+	durations := map[uint64]*meta2.ShardDurationInfo{defaultShardId: getShardDurationInfo(defaultShardId)}
+	require.NoError(t, dst.LoadDBPT(defaultDb, defaultPtId, durations))
+	require.Equal(t, 1, len(dst.DBPartitions[defaultDb][defaultPtId].shards))
+	require.Error(t, dst.ReceiveDBPT(defaultDb, defaultPtId, files, read))
+
+	keys, err := dst.SeriesKeys(defaultDb, []uint32{defaultPtId}, [][]byte{[]byte("mst")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 10, len(keys))
+}
+
+func TestEngine_RollbackPreOffloadDBPT(t *testing.T) {
+	eng := initMoveEngine(t, t.TempDir(), true)
+	defer eng.Close()
+
+	tm := mustParseTime(time.RFC3339Nano, "1999-06-01T01:00:00Z")
+	rows, _, _ := GenDataRecord([]string{"mst"}, 5, 20, time.Second, tm, false, true, false)
+	require.NoError(t, eng.WriteRows(defaultDb, defaultRp, defaultPtId, defaultShardId, rows, nil))
+	eng.ForceFlush()
+
+	require.NoError(t, eng.PreOffloadDBPT(defaultDb, defaultPtId))
+
+	durations := map[uint64]*meta2.ShardDurationInfo{defaultShardId: getShardDurationInfo(defaultShardId)}
+	require.NoError(t, eng.LoadDBPT(defaultDb, defaultPtId, durations))
+	require.Error(t, eng.OffloadDBPT(defaultDb, defaultPtId))
+	require.NoError(t, eng.WriteRows(defaultDb, defaultRp, defaultPtId, defaultShardId, rows, nil))
+}
@@ -48,6 +48,7 @@ import (
 	"github.com/openGemini/openGemini/open_src/influx/meta"
 	"github.com/openGemini/openGemini/open_src/influx/query"
 	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/open_src/vm/uint64set"
 	"github.com/pingcap/failpoint"
 	"go.uber.org/zap"
 )
@@ -108,6 +109,12 @@ type Shard interface {
 	Statistics(buffer []byte) ([]byte, error)
 
 	NewShardKeyIdx(shardType, dataPath string) error
+
+	SeriesIDs(dst *uint64set.Set) error
+
+	MemSeriesIDs(tsids []uint64, dst *uint64set.Set) uint64
+
+	SnapshotSeq() uint64
 }
 
 type shard struct {
@@ -124,6 +131,7 @@ type shard struct {
 	snapshotLock sync.RWMutex
 	activeTbl    *mutable.MemTable
 	snapshotTbl  *mutable.MemTable
+	snapshotSeq  uint64
 	snapshotWg   sync.WaitGroup
 	immTables    immutable.TablesStore
 	indexBuilder *tsi.IndexBuilder
@@ -148,6 +156,9 @@ type shard struct {
 	forceChan   chan struct{}
 	defaultTags map[string]string
 	fileStat    *statistics.FileStatistics
+
+	// lastValues is nil if the last value cache is disabled
+	lastValues *lastValueCache
 }
 
 type nodeMemBucket struct {
@@ -203,7 +214,7 @@ func NewShard(dataPath string, walPath string, ident *meta.ShardIdentifier, inde
 		walPath:           walPath,
 		tsspPath:          tsspPath,
 		ident:             ident,
-		wal:               NewWAL(walPath, options.WalSyncInterval, options.WalEnabled, options.WalReplayParallel, getWalPartitionNum()),
+		wal:               NewWAL(walPath, options.WalSyncInterval, options.WalEnabled, options.WalReplayParallel, options.WalReplayQuarantine, getWalPartitionNum()),
 		activeTbl:         mutable.NewMemTable(mutable.NewConfig(), dataPath),
 		indexBuilder:      indexBuilder,
 		maxTime:           0,
@@ -218,7 +229,8 @@ func NewShard(dataPath string, walPath string, ident *meta.ShardIdentifier, inde
 			"database":        db,
 			"retentionPolicy": rp,
 		},
-		fileStat: statistics.NewFileStatistics(),
+		fileStat:   statistics.NewFileStatistics(),
+		lastValues: newLastValueCache(options),
 	}
 	s.log = logger.NewLogger(errno.ModuleShard)
 	s.SetMutableSizeLimit(options.ShardMutableSizeLimit)
@@ -301,6 +313,71 @@ func (s *shard) GetValuesInMutableAndSnapshot(msName string, id uint64, tr recor
 	return &mergeRecord
 }
 
+// SeriesIDs adds the ids of the series having data in the shard to dst. The mem tables
+// are read before the files, so the series flushed meanwhile are found in the files.
+func (s *shard) SeriesIDs(dst *uint64set.Set) error {
+	activeTbl, snapshotTbl, _ := s.refMemTables()
+	activeTbl.SeriesIDs(dst)
+	activeTbl.UnRef()
+	if snapshotTbl != nil {
+		snapshotTbl.SeriesIDs(dst)
+		snapshotTbl.UnRef()
+	}
+
+	for _, mst := range s.immTables.Measurements() {
+		for _, isOrder := range []bool{true, false} {
+			files := s.immTables.GetFilesRef(mst, isOrder)
+			for _, f := range files {
+				p := immutable.GetIDTimePairs(mst)
+				err := f.LoadIdTimes(p)
+				if err == nil {
+					for _, id := range p.Ids {
+						dst.Add(id)
+					}
+				}
+				immutable.PutIDTimePairs(p)
+				if err != nil {
+					immutable.UnrefFiles(files...)
+					return err
+				}
+			}
+			immutable.UnrefFiles(files...)
+		}
+	}
+	return nil
+}
+
+// MemSeriesIDs adds the ids among tsids of the series in the mem tables to dst, and returns the
+// number of the snapshots taken so far. A series written since is in the mem tables unless
+// another snapshot is taken.
+func (s *shard) MemSeriesIDs(tsids []uint64, dst *uint64set.Set) uint64 {
+	activeTbl, snapshotTbl, seq := s.refMemTables()
+	activeTbl.SeriesIDsIn(tsids, dst)
+	activeTbl.UnRef()
+	if snapshotTbl != nil {
+		snapshotTbl.SeriesIDsIn(tsids, dst)
+		snapshotTbl.UnRef()
+	}
+	return seq
+}
+
+func (s *shard) refMemTables() (*mutable.MemTable, *mutable.MemTable, uint64) {
+	s.snapshotLock.RLock()
+	defer s.snapshotLock.RUnlock()
+	s.activeTbl.Ref()
+	if s.snapshotTbl != nil {
+		s.snapshotTbl.Ref()
+	}
+	return s.activeTbl, s.snapshotTbl, s.snapshotSeq
+}
+
+// SnapshotSeq returns the number of the snapshots of the mem table taken so far.
+func (s *shard) SnapshotSeq() uint64 {
+	s.snapshotLock.RLock()
+	defer s.snapshotLock.RUnlock()
+	return s.snapshotSeq
+}
+
 func (s *shard) WriteRows(rows []influx.Row, binaryRows []byte) error {
 	if atomic.LoadInt32(&s.cacheClosed) > 0 {
 		return ErrShardClosed
@@ -312,6 +389,10 @@ func (s *shard) WriteRows(rows []influx.Row, binaryRows []byte) error {
 	atomic.StoreUint64(&s.lastWriteTime, fasttime.UnixTimestamp())
 
 	if err := s.writeRowsToTable(rows, binaryRows); err != nil {
+		if _, ok := err.(netstorage.PartialWriteError); ok {
+			atomic.AddInt64(&statistics.PerfStat.WriteRowsBatch, 1)
+			return err
+		}
 		log.Error("write buffer failed", zap.Error(err))
 		atomic.AddInt64(&statistics.PerfStat.WriteReqErrors, 1)
 		return err
@@ -405,6 +486,47 @@ func (mw *mstWriteCtx) Reset() {
 	mw.mstMap.Reset()
 }
 
+// dropRowsWithoutSeries removes the rows whose series were rejected when creating the index,
+// and returns the number of dropped rows.
+func (mw *mstWriteCtx) dropRowsWithoutSeries() int {
+	dropped := 0
+	var empty []string
+	for _, mapp := range mw.mstMap.D {
+		rows, ok := mapp.Value.(*[]influx.Row)
+		if !ok {
+			panic("can't map mmPoints")
+		}
+		n := 0
+		for i := range *rows {
+			if (*rows)[i].SeriesId == 0 {
+				dropped++
+				continue
+			}
+			(*rows)[n] = (*rows)[i]
+			n++
+		}
+		*rows = (*rows)[:n]
+		if n == 0 {
+			empty = append(empty, mapp.Key)
+		}
+	}
+
+	for _, name := range empty {
+		rows, _ := mw.mstMap.Get(name).(*[]influx.Row)
+		mw.putRowsPool(*rows)
+		mw.mstMap.Del(name)
+	}
+	return dropped
+}
+
+func (mw *mstWriteCtx) marshalRows(dst []byte) ([]byte, error) {
+	var rows []influx.Row
+	for _, mapp := range mw.mstMap.D {
+		rows = append(rows, *(mapp.Value.(*[]influx.Row))...)
+	}
+	return influx.FastMarshalMultiRows(dst, rows)
+}
+
 var mstWriteCtxPool sync.Pool
 
 func getMstWriteCtx() *mstWriteCtx {
@@ -499,11 +621,21 @@ func (s *shard) writeRowsToTable(rows influx.Rows, binaryRows []byte) error {
 	atomic.AddInt64(&statistics.PerfStat.WriteSortIndexDurationNs, time.Since(start).Nanoseconds())
 
 	var writeIndexRequired bool
+	var partialErr error
+	var dropped int
 	start = time.Now()
 
 	tm := int64(math.MinInt64)
 	primaryIndex := s.indexBuilder.GetPrimaryIndex()
 	mergetIndex := primaryIndex.(*tsi.MergeSetIndex)
+	// the series looked up are not deleted by the gc of the index until they are in the mem table
+	mergetIndex.BeginWrite()
+	writing := true
+	defer func() {
+		if writing {
+			mergetIndex.EndWrite()
+		}
+	}()
 	for i := 0; i < len(rows); i++ {
 		if s.closed.Closed() {
 			return ErrShardClosed
@@ -552,7 +684,20 @@ func (s *shard) writeRowsToTable(rows influx.Rows, binaryRows []byte) error {
 		failpoint.Inject("SlowDownCreateIndex", nil)
 
 		if err = s.indexBuilder.CreateIndexIfNotExists(mmPoints); err != nil {
-			return err
+			if !tsi.IsCardinalityLimitError(err) {
+				return err
+			}
+			// Rows of the rejected series are dropped, the others are still written.
+			dropped = mw.dropRowsWithoutSeries()
+			partialErr = netstorage.PartialWriteError{Reason: err, Dropped: dropped}
+			if len(mmPoints.D) == 0 {
+				return partialErr
+			}
+			if binaryRows != nil {
+				if binaryRows, err = mw.marshalRows(nil); err != nil {
+					return err
+				}
+			}
 		}
 	} else {
 		if err = s.indexBuilder.CreateIndexIfPrimaryKeyExists(mmPoints, false); err != nil {
@@ -578,6 +723,9 @@ func (s *shard) writeRowsToTable(rows influx.Rows, binaryRows []byte) error {
 		return err
 	}
 	s.activeTbl.AddMemSize(curSize)
+	mergetIndex.EndWrite()
+	writing = false
+	s.lastValues.update(mmPoints)
 	atomic.AddInt64(&statistics.PerfStat.WriteRowsDurationNs, time.Since(start).Nanoseconds())
 
 	start = time.Now()
@@ -591,8 +739,8 @@ func (s *shard) writeRowsToTable(rows influx.Rows, binaryRows []byte) error {
 	}
 	atomic.AddInt64(&statistics.PerfStat.WriteWalDurationNs, time.Since(start).Nanoseconds())
 	s.snapshotLock.RUnlock()
-	s.addRowCounts(int64(len(rows)))
-	return nil
+	s.addRowCounts(int64(len(rows) - dropped))
+	return partialErr
 }
 
 func (s *shard) enableForceFlush() {
@@ -742,12 +890,14 @@ func (s *shard) writeSnapshot() {
 	}
 
 	s.snapshotTbl = s.activeTbl
+	s.snapshotSeq++
 	curSize := s.snapshotTbl.GetMemSize()
 	statistics.MutableStat.AddMutableSize(s.tsspPath, -curSize)
 
 	s.activeTbl = mutable.GetMemTable(s.tsspPath)
 	s.activeTbl.SetIdx(s.skIdx)
 	s.activeTbl.GetConf().SetShardMutableSizeLimit(s.mutableSizeLimit)
+	reenabled := s.lastValues.reenable()
 	s.snapshotLock.Unlock()
 
 	start := time.Now()
@@ -755,6 +905,8 @@ func (s *shard) writeSnapshot() {
 
 	s.commitSnapshot(s.snapshotTbl)
 	nodeMutableLimit.freeResource(curSize)
+	// the rows written before the switch are in the files now
+	s.lastValues.warmUpReenabled(s.immTables, reenabled)
 
 	err = s.wal.Remove(walFiles)
 	if err != nil {
@@ -843,6 +995,8 @@ func (s *shard) Close() error {
 	s.snapshotLock.Unlock()
 	nodeMutableLimit.freeResource(curMemSize)
 
+	s.lastValues.close()
+
 	log.Info("close immutables")
 	if err := s.immTables.Close(); err != nil {
 		log.Error("close table store fail", zap.Uint64("id", s.ident.ShardID), zap.Error(err))
@@ -877,7 +1031,13 @@ func (s *shard) writeWalBuffer(binary []byte) error {
 		return nil
 	}
 
-	return s.writeRowsToTable(rows, nil)
+	err = s.writeRowsToTable(rows, nil)
+	if _, ok := err.(netstorage.PartialWriteError); ok {
+		// the cardinality limits may be lowered after the rows were written
+		logger.GetLogger().Warn("drop rows in wal", zap.Uint64("shard", s.ident.ShardID), zap.Error(err))
+		return nil
+	}
+	return err
 }
 
 func (s *shard) replayWal() error {
@@ -919,6 +1079,12 @@ func (s *shard) Open() error {
 	s.setMaxTime(maxTime)
 	logger.GetLogger().Info("open immutable done", zap.Uint64("id", s.ident.ShardID), zap.Duration("time used", time.Since(start)))
 
+	if s.lastValues != nil {
+		lStart := time.Now()
+		s.lastValues.warmUp(s.immTables)
+		logger.GetLogger().Info("warm up last value cache done", zap.Uint64("id", s.ident.ShardID), zap.Duration("time used", time.Since(lStart)))
+	}
+
 	// replay wal files
 	wStart := time.Now()
 	err = s.replayWal()
@@ -1007,6 +1173,7 @@ func (s *shard) DropMeasurement(ctx context.Context, name string) error {
 
 	// flush measurement data in mem
 	s.ForceFlush()
+	s.lastValues.dropMeasurement(name)
 
 	// drop measurement from immutable
 	return s.immTables.DropMeasurement(ctx, name)
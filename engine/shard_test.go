@@ -38,6 +38,7 @@ import (
 	"github.com/openGemini/openGemini/lib/bufferpool"
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/netstorage"
 	"github.com/openGemini/openGemini/lib/rand"
 	"github.com/openGemini/openGemini/lib/record"
 	"github.com/openGemini/openGemini/open_src/influx/influxql"
@@ -1968,6 +1969,51 @@ func TestDropMeasurement(t *testing.T) {
 	}
 }
 
+func TestWriteRows_CardinalityLimit(t *testing.T) {
+	testDir := t.TempDir()
+	msNames := []string{"cpu", "cpu1"}
+
+	unlimited := config.NewStore()
+	defer tsi.SetCardinalityLimits(&unlimited)
+	conf := config.NewStore()
+	conf.MaxSeriesPerMeasurement = 4
+	tsi.SetCardinalityLimits(&conf)
+
+	sh, err := createShard(defaultDb, defaultRp, defaultPtId, testDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tm := time.Now().Truncate(time.Second)
+	// 10 series of each measurement
+	rows, _, _ := GenDataRecord(msNames, 20, 20, time.Second, tm, false, true, false)
+	err = writeData(sh, rows, true)
+	werr, ok := err.(netstorage.PartialWriteError)
+	if !ok {
+		t.Fatalf("expect partial write error, got: %v", err)
+	}
+	// 6 series of each measurement are rejected
+	if werr.Dropped != 2*6*20 || !tsi.IsCardinalityLimitError(werr.Reason) {
+		t.Fatalf("unexpected partial write error: %v", werr)
+	}
+
+	for _, name := range msNames {
+		idx := sh.indexBuilder.GetPrimaryIndex().(*tsi.MergeSetIndex)
+		idx.DebugFlush()
+		n, err := idx.SeriesCardinality([]byte(name), nil, tsi.TimeRange{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 4 {
+			t.Fatalf("series cardinality of %s, exp: 4, got: %d", name, n)
+		}
+	}
+
+	if err = closeShard(sh); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestEngine_DropMeasurement(t *testing.T) {
 	dir := t.TempDir()
 	eng, err := initEngine1(dir)
@@ -33,6 +33,8 @@ import (
  curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=compen&switchon=true&allshards=true&shid=4'
  curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=merge&switchon=true&allshards=true&shid=4'
  curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=snapshot&duration=30m'
+ curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=rebuild_index&db=db0&pt=1&indexid=3'
+ curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=index_gc'
 */
 
 const (
@@ -42,6 +44,8 @@ const (
 	snapshot     = "snapshot"
 	Failpoint    = "failpoint"
 	Readonly     = "readonly"
+	RebuildIndex = "rebuild_index"
+	IndexGC      = "index_gc"
 )
 
 var (
@@ -120,6 +124,12 @@ func (e *Engine) processReq(req *netstorage.SysCtrlRequest) error {
 		return nil
 	case Readonly:
 		return e.handleReadonly(req)
+	case RebuildIndex:
+		return e.handleRebuildIndex(req)
+	case IndexGC:
+		e.GCIndexes()
+		log.Info("index gc done")
+		return nil
 	default:
 		return fmt.Errorf("unknown sys cmd %v", req.Mod())
 	}
@@ -171,6 +181,24 @@ func (e *Engine) handleReadonly(req *netstorage.SysCtrlRequest) error {
 	return nil
 }
 
+func (e *Engine) handleRebuildIndex(req *netstorage.SysCtrlRequest) error {
+	db, ok := req.Param()["db"]
+	if !ok {
+		return fmt.Errorf("no db in parameter")
+	}
+	pt, err := intValue(req.Param(), "pt")
+	if err != nil {
+		log.Error("get pt from param fail", zap.Error(err))
+		return err
+	}
+	indexID, err := intValue(req.Param(), "indexid")
+	if err != nil {
+		log.Error("get index id from param fail", zap.Error(err))
+		return err
+	}
+	return e.RebuildIndex(db, uint32(pt), uint64(indexID))
+}
+
 func intValue(param map[string]string, key string) (int64, error) {
 	str, ok := param[key]
 	if !ok {
@@ -18,6 +18,7 @@ package engine
 import (
 	"encoding/binary"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
 	"path/filepath"
@@ -35,6 +36,7 @@ import (
 	"github.com/openGemini/openGemini/lib/logger"
 	"github.com/openGemini/openGemini/lib/util"
 	"github.com/pingcap/failpoint"
+	"go.uber.org/zap"
 )
 
 const (
@@ -43,16 +45,31 @@ const (
 	WalRecordHeadSize = 1 + 4
 	WalCompBufSize    = 256 * 1024
 	WalCompMaxBufSize = 2 * 1024 * 1024
+
+	// WalRecordCRCHeadSize is the header size of records with a checksum: type, length and crc32 of the header and body
+	WalRecordCRCHeadSize = WalRecordHeadSize + 4
+	// WalFileHeaderSize is the size of the version marker at the start of wal files with checksummed records
+	WalFileHeaderSize = 8
+	// WalCorruptDirName is the directory under the wal path that keeps the quarantined corrupt records
+	WalCorruptDirName = "corrupt"
 )
 
 type WalRecordType byte
 
 const (
+	// WriteWALRecord is the record without checksum written by old versions, it is only replayed
 	WriteWALRecord WalRecordType = 0x01
+	// WriteWALRecordWithCRC is the record with a crc32 checksum of its type, length and compressed body
+	WriteWALRecordWithCRC WalRecordType = 0x02
 )
 
+// walFileHeader starts the files that hold WriteWALRecordWithCRC records. Files written by old versions have
+// no header and hold WriteWALRecord records only, their first byte is never 0xFF.
+var walFileHeader = [WalFileHeaderSize]byte{0xFF, 'o', 'g', 'w', 'a', 'l', 0x00, byte(WriteWALRecordWithCRC)}
+
 var (
 	walCompBufPool = bufferpool.NewByteBufferPool(WalCompBufSize)
+	walCRCTable    = crc32.MakeTable(crc32.Castagnoli)
 )
 
 type WAL struct {
@@ -64,15 +81,18 @@ type WAL struct {
 	logWriter      []LogWriter
 	walEnabled     bool
 	replayParallel bool
+	// copy corrupt records and truncated tails to WalCorruptDirName before they are skipped
+	quarantine bool
 }
 
-func NewWAL(path string, walSyncInterval time.Duration, walEnabled, replayParallel bool, partitionNum int) *WAL {
+func NewWAL(path string, walSyncInterval time.Duration, walEnabled, replayParallel, quarantine bool, partitionNum int) *WAL {
 	wal := &WAL{
 		logPath:        path,
 		partitionNum:   partitionNum,
 		logWriter:      make([]LogWriter, partitionNum),
 		walEnabled:     walEnabled,
 		replayParallel: replayParallel,
+		quarantine:     quarantine,
 		log:            logger.NewLogger(errno.ModuleWal),
 	}
 
@@ -97,7 +117,7 @@ func (l *WAL) writeBinary(binaryData []byte) error {
 	// prepare for compress memory
 	compBuf := walCompBufPool.Get()
 	maxEncodeLen := snappy.MaxEncodedLen(len(binaryData))
-	compBuf = bufferpool.Resize(compBuf, WalRecordHeadSize+maxEncodeLen)
+	compBuf = bufferpool.Resize(compBuf, WalRecordCRCHeadSize+maxEncodeLen)
 	defer func() {
 		if len(compBuf) <= WalCompMaxBufSize {
 			walCompBufPool.Put(compBuf)
@@ -105,12 +125,13 @@ func (l *WAL) writeBinary(binaryData []byte) error {
 	}()
 
 	// compress data
-	compData := snappy.Encode(compBuf[WalRecordHeadSize:], binaryData)
+	compData := snappy.Encode(compBuf[WalRecordCRCHeadSize:], binaryData)
 
 	// encode record header
-	compBuf[0] = byte(WriteWALRecord)
+	compBuf[0] = byte(WriteWALRecordWithCRC)
 	binary.BigEndian.PutUint32(compBuf[1:WalRecordHeadSize], uint32(len(compData)))
-	compBuf = compBuf[:WalRecordHeadSize+len(compData)]
+	binary.BigEndian.PutUint32(compBuf[WalRecordHeadSize:WalRecordCRCHeadSize], walRecordChecksum(compBuf[:WalRecordHeadSize], compData))
+	compBuf = compBuf[:WalRecordCRCHeadSize+len(compData)]
 
 	// write data, switch to new file if needed
 	l.mu.RLock()
@@ -183,51 +204,143 @@ func (l *WAL) Remove(files []string) error {
 	return nil
 }
 
-func (l *WAL) replayPhysicRecord(fd fileops.File, offset, fileSize int64, recordCompBuff []byte,
-	callBack func(binary []byte) error) (int64, []byte, error) {
+// walRecordChecksum is the crc32 of the record type, length and compressed body
+func walRecordChecksum(head, body []byte) uint32 {
+	return crc32.Update(crc32.Checksum(head, walCRCTable), walCRCTable, body)
+}
+
+// walReplayFile is the state of a wal file being replayed
+type walReplayFile struct {
+	fd       fileops.File
+	name     string
+	size     int64
+	offset   int64
+	compBuff []byte
+	// the only record type of the file, WriteWALRecord if the file has no walFileHeader
+	recordType WalRecordType
+	// the number of corrupt tails skipped
+	corrupt int
+}
+
+// replayPhysicRecord replays the record at f.offset. A record that is truncated, has an unexpected type,
+// fails the checksum or can not be decompressed ends the replay of the file at the last good offset,
+// the rest of the file is skipped and truncated.
+func (l *WAL) replayPhysicRecord(f *walReplayFile, callBack func(binary []byte) error) error {
 	// check file is all replayed
-	if offset >= fileSize {
-		return offset, recordCompBuff, io.EOF
+	if f.offset >= f.size {
+		return io.EOF
 	}
 
 	// read record header
-	var recordHeader [WalRecordHeadSize]byte
-	n, err := fd.ReadAt(recordHeader[:], offset)
-	if err != nil {
-		l.log.Warn(errno.NewError(errno.ReadWalFileFailed, fd.Name(), offset, "record header").Error())
-		return offset, recordCompBuff, io.EOF
+	var recordHeader [WalRecordCRCHeadSize]byte
+	headSize := int64(WalRecordHeadSize)
+	if f.size-f.offset < headSize {
+		l.skipTail(f, errno.NewError(errno.WalFileTruncated, f.name, f.offset, f.size))
+		return io.EOF
+	}
+	n, err := f.fd.ReadAt(recordHeader[:1], f.offset)
+	if err != nil || n != 1 {
+		l.log.Warn(errno.NewError(errno.ReadWalFileFailed, f.name, f.offset, "record header").Error())
+		return io.EOF
+	}
+
+	if WalRecordType(recordHeader[0]) != f.recordType {
+		l.skipTail(f, errno.NewError(errno.WalRecordHeaderCorrupted, f.name, f.offset))
+		return io.EOF
+	}
+	if f.recordType == WriteWALRecordWithCRC {
+		headSize = WalRecordCRCHeadSize
 	}
-	if n != WalRecordHeadSize {
-		l.log.Warn(errno.NewError(errno.WalRecordHeaderCorrupted, fd.Name(), offset).Error())
-		return offset, recordCompBuff, io.EOF
+	if f.size-f.offset < headSize {
+		l.skipTail(f, errno.NewError(errno.WalFileTruncated, f.name, f.offset, f.size))
+		return io.EOF
+	}
+	n, err = f.fd.ReadAt(recordHeader[:headSize], f.offset)
+	if err != nil || int64(n) != headSize {
+		l.log.Warn(errno.NewError(errno.ReadWalFileFailed, f.name, f.offset, "record header").Error())
+		return io.EOF
 	}
-	offset += int64(len(recordHeader))
 
 	// prepare record memory
-	compBinaryLen := binary.BigEndian.Uint32(recordHeader[1:WalRecordHeadSize])
-	recordCompBuff = bufferpool.Resize(recordCompBuff, int(compBinaryLen))
+	compBinaryLen := int64(binary.BigEndian.Uint32(recordHeader[1:WalRecordHeadSize]))
+	if f.size-f.offset-headSize < compBinaryLen {
+		l.skipTail(f, errno.NewError(errno.WalFileTruncated, f.name, f.offset, f.size))
+		return io.EOF
+	}
+	f.compBuff = bufferpool.Resize(f.compBuff, int(compBinaryLen))
 
 	// read record body
-	var recordBuff []byte
-	n, err = fd.ReadAt(recordCompBuff, offset)
-	if err == nil || err == io.EOF {
-		offset += int64(n)
-		var innerErr error
-		recordBuff, innerErr = snappy.Decode(recordBuff, recordCompBuff)
-		if innerErr != nil {
-			l.log.Warn(errno.NewError(errno.DecompressWalRecordFailed, fd.Name(), offset, innerErr.Error()).Error())
-			return offset, recordCompBuff, io.EOF
-		}
+	n, err = f.fd.ReadAt(f.compBuff, f.offset+headSize)
+	if (err != nil && err != io.EOF) || int64(n) != compBinaryLen {
+		l.log.Warn(errno.NewError(errno.ReadWalFileFailed, f.name, f.offset, "record body").Error())
+		return io.EOF
+	}
 
-		innerErr = callBack(recordBuff)
-		if innerErr == nil {
-			return offset, recordCompBuff, err
+	if headSize == WalRecordCRCHeadSize {
+		exp := binary.BigEndian.Uint32(recordHeader[WalRecordHeadSize:WalRecordCRCHeadSize])
+		if got := walRecordChecksum(recordHeader[:WalRecordHeadSize], f.compBuff); got != exp {
+			l.skipTail(f, errno.NewError(errno.WalRecordChecksumMismatch, f.name, f.offset, exp, got))
+			return io.EOF
 		}
+	}
+
+	recordBuff, err := snappy.Decode(nil, f.compBuff)
+	if err != nil {
+		l.skipTail(f, errno.NewError(errno.DecompressWalRecordFailed, f.name, f.offset, err.Error()))
+		return io.EOF
+	}
+	f.offset += headSize + compBinaryLen
+
+	return callBack(recordBuff)
+}
+
+// skipTail skips the rest of the file from f.offset, the last good offset. The tail is copied to the
+// quarantine directory if required and cut off, so that records appended later are not hidden behind it.
+func (l *WAL) skipTail(f *walReplayFile, reason error) {
+	f.corrupt++
+	l.log.Error("skip the tail of wal file", zap.Error(reason), zap.Int64("skipped", f.size-f.offset))
+	l.quarantineRange(f, f.offset, f.size-f.offset)
+	if err := f.fd.Truncate(f.offset); err != nil {
+		l.log.Error("truncate wal file failed", zap.String("file", f.name), zap.Int64("offset", f.offset), zap.Error(err))
+	}
+	f.size = f.offset
+}
+
+func (l *WAL) quarantineRange(f *walReplayFile, offset, size int64) {
+	if !l.quarantine || size <= 0 {
+		return
+	}
 
-		return offset, recordCompBuff, innerErr
+	lock := fileops.FileLockOption("")
+	dir := filepath.Join(l.logPath, WalCorruptDirName)
+	if err := fileops.MkdirAll(dir, 0750, lock); err != nil {
+		l.log.Error("create wal quarantine dir failed", zap.String("dir", dir), zap.Error(err))
+		return
+	}
+
+	// eg, corrupt/0_1.wal.1024, the partition, the file and the offset of the record
+	partition := filepath.Base(filepath.Dir(f.name))
+	name := filepath.Join(dir, fmt.Sprintf("%s_%s.%d", partition, filepath.Base(f.name), offset))
+	buf := make([]byte, size)
+	if n, err := f.fd.ReadAt(buf, offset); err != nil && err != io.EOF {
+		l.log.Error("read corrupt wal record failed", zap.String("file", f.name), zap.Error(err))
+		return
+	} else {
+		buf = buf[:n]
+	}
+
+	pri := fileops.FilePriorityOption(fileops.IO_PRIORITY_NORMAL)
+	fd, err := fileops.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640, lock, pri)
+	if err != nil {
+		l.log.Error("create wal quarantine file failed", zap.String("file", name), zap.Error(err))
+		return
+	}
+	defer util.MustClose(fd)
+	if _, err = fd.Write(buf); err != nil {
+		l.log.Error("write wal quarantine file failed", zap.String("file", name), zap.Error(err))
+		return
 	}
-	l.log.Warn(errno.NewError(errno.ReadWalFileFailed, fd.Name(), offset, "record body").Error())
-	return offset, recordCompBuff, io.EOF
+	l.log.Warn("corrupt wal record is quarantined", zap.String("file", name), zap.Int("size", len(buf)))
 }
 
 func (l *WAL) replayWalFile(walFileName string, callBack func(binary []byte) error) error {
@@ -239,10 +352,11 @@ func (l *WAL) replayWalFile(walFileName string, callBack func(binary []byte) err
 	})
 	lock := fileops.FileLockOption("")
 	pri := fileops.FilePriorityOption(fileops.IO_PRIORITY_NORMAL)
-	fd, err := fileops.OpenFile(walFileName, os.O_RDONLY, 0640, lock, pri)
+	fd, err := fileops.OpenFile(walFileName, os.O_RDWR, 0640, lock, pri)
 	if err != nil {
 		return err
 	}
+	defer util.MustClose(fd)
 
 	stat, err := fd.Stat()
 	if err != nil {
@@ -254,18 +368,31 @@ func (l *WAL) replayWalFile(walFileName string, callBack func(binary []byte) err
 		return nil
 	}
 
-	recordCompBuff := walCompBufPool.Get()
+	f := &walReplayFile{
+		fd:         fd,
+		name:       walFileName,
+		size:       fileSize,
+		compBuff:   walCompBufPool.Get(),
+		recordType: WriteWALRecord,
+	}
+	var header [WalFileHeaderSize]byte
+	if n, err := fd.ReadAt(header[:], 0); err == nil && n == WalFileHeaderSize && header == walFileHeader {
+		f.offset = WalFileHeaderSize
+		f.recordType = WriteWALRecordWithCRC
+	}
 	defer func() {
-		if len(recordCompBuff) <= WalCompMaxBufSize {
-			walCompBufPool.Put(recordCompBuff)
+		if len(f.compBuff) <= WalCompMaxBufSize {
+			walCompBufPool.Put(f.compBuff)
+		}
+		if f.corrupt > 0 {
+			l.log.Warn("wal file replayed with the corrupt tail truncated", zap.String("file", walFileName),
+				zap.Int64("size", f.size), zap.Bool("quarantine", l.quarantine))
 		}
 	}()
 
-	offset := int64(0)
 	for {
-		offset, recordCompBuff, err = l.replayPhysicRecord(fd, offset, fileSize, recordCompBuff, callBack)
+		err = l.replayPhysicRecord(f, callBack)
 		if err != nil {
-			util.MustClose(fd)
 			if err == io.EOF {
 				return nil
 			}
@@ -391,6 +518,12 @@ func (l *WAL) Replay(callBack func(binary []byte) error) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	for i := 0; i < len(dirs); i++ {
+		if dirs[i].Name() == WalCorruptDirName {
+			dirs = append(dirs[:i], dirs[i+1:]...)
+			break
+		}
+	}
 
 	// replay wal files
 	var mu = sync.Mutex{}
@@ -472,9 +605,14 @@ func (w *LogWriter) trySwitchFile(logPath string) error {
 			return err
 		}
 
+		if _, err = fd.Write(walFileHeader[:]); err != nil {
+			util.MustClose(fd)
+			return err
+		}
+
 		w.fileNames = append(w.fileNames, fileName)
 		w.currentFd = fd
-		w.currentFileSize = 0
+		w.currentFileSize = WalFileHeaderSize
 	}
 
 	return nil
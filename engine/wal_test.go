@@ -16,10 +16,17 @@ limitations under the License.
 package engine
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWalReplayParallel(t *testing.T) {
@@ -183,3 +190,144 @@ func TestWalReplaySerial(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func encodeWalRecord(typ WalRecordType, data []byte) []byte {
+	comp := snappy.Encode(nil, data)
+	head := WalRecordHeadSize
+	if typ == WriteWALRecordWithCRC {
+		head = WalRecordCRCHeadSize
+	}
+	buf := make([]byte, head, head+len(comp))
+	buf[0] = byte(typ)
+	binary.BigEndian.PutUint32(buf[1:WalRecordHeadSize], uint32(len(comp)))
+	if typ == WriteWALRecordWithCRC {
+		binary.BigEndian.PutUint32(buf[WalRecordHeadSize:], walRecordChecksum(buf[:WalRecordHeadSize], comp))
+	}
+	return append(buf, comp...)
+}
+
+func writeWalFile(t *testing.T, fileName string, records ...[]byte) {
+	if err := os.WriteFile(fileName, bytes.Join(records, nil), 0640); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func requireWalFileSize(t *testing.T, fileName string, size int) {
+	stat, err := os.Stat(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, int64(size), stat.Size())
+}
+
+func replayWalRecords(t *testing.T, wal *WAL, fileName string) []string {
+	var got []string
+	err := wal.replayWalFile(fileName, func(binary []byte) error {
+		got = append(got, string(binary))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestWalReplayCorruptRecords(t *testing.T) {
+	walPath := t.TempDir()
+	partDir := filepath.Join(walPath, "0")
+	if err := os.MkdirAll(partDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(partDir, "1.wal")
+
+	header := walFileHeader[:]
+	rec1 := encodeWalRecord(WriteWALRecordWithCRC, []byte("record-1"))
+	rec2 := encodeWalRecord(WriteWALRecordWithCRC, []byte("record-2"))
+	rec3 := encodeWalRecord(WriteWALRecordWithCRC, []byte("record-3"))
+	legacy1 := encodeWalRecord(WriteWALRecord, []byte("legacy-1"))
+	legacy2 := encodeWalRecord(WriteWALRecord, []byte("legacy-2"))
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		bad := append([]byte{}, rec2...)
+		bad[len(bad)-1] ^= 0xff
+		writeWalFile(t, fileName, header, rec1, bad, rec3)
+
+		wal := NewWAL(walPath, time.Second, true, false, false, 1)
+		got := replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"record-1"}, got)
+		requireWalFileSize(t, fileName, len(header)+len(rec1))
+	})
+
+	t.Run("checksum covers the header", func(t *testing.T) {
+		bad := append([]byte{}, rec2...)
+		bad[1] ^= 0x01
+		writeWalFile(t, fileName, header, rec1, bad, rec3, rec3)
+
+		wal := NewWAL(walPath, time.Second, true, false, false, 1)
+		got := replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"record-1"}, got)
+		requireWalFileSize(t, fileName, len(header)+len(rec1))
+	})
+
+	t.Run("legacy records", func(t *testing.T) {
+		wal := NewWAL(walPath, time.Second, true, false, false, 1)
+		writeWalFile(t, fileName, legacy1, legacy2)
+		got := replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"legacy-1", "legacy-2"}, got)
+
+		// legacy records are not accepted in a file with the version header
+		writeWalFile(t, fileName, header, rec1, legacy1, rec3)
+		got = replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"record-1"}, got)
+		requireWalFileSize(t, fileName, len(header)+len(rec1))
+
+		// and records with checksum are not accepted in a legacy file
+		writeWalFile(t, fileName, legacy1, rec1, legacy2)
+		got = replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"legacy-1"}, got)
+		requireWalFileSize(t, fileName, len(legacy1))
+	})
+
+	t.Run("truncated tail", func(t *testing.T) {
+		writeWalFile(t, fileName, header, rec1, rec2, rec3[:len(rec3)-2])
+
+		wal := NewWAL(walPath, time.Second, true, false, false, 1)
+		got := replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"record-1", "record-2"}, got)
+		requireWalFileSize(t, fileName, len(header)+len(rec1)+len(rec2))
+
+		// only part of the header is written
+		writeWalFile(t, fileName, header, rec1, rec3[:WalRecordHeadSize+1])
+		got = replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"record-1"}, got)
+		requireWalFileSize(t, fileName, len(header)+len(rec1))
+	})
+
+	t.Run("quarantine", func(t *testing.T) {
+		bad := append([]byte{}, rec2...)
+		bad[len(bad)-1] ^= 0xff
+		writeWalFile(t, fileName, header, rec1, bad, rec3)
+
+		wal := NewWAL(walPath, time.Second, true, false, true, 1)
+		got := replayWalRecords(t, wal, fileName)
+		require.Equal(t, []string{"record-1"}, got)
+
+		corruptDir := filepath.Join(walPath, WalCorruptDirName)
+		tailFile := filepath.Join(corruptDir, fmt.Sprintf("0_1.wal.%d", len(header)+len(rec1)))
+		buf, err := os.ReadFile(tailFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		require.Equal(t, bytes.Join([][]byte{bad, rec3}, nil), buf)
+		requireWalFileSize(t, fileName, len(header)+len(rec1))
+
+		// the quarantine directory is not a wal partition
+		files, err := wal.Replay(func(binary []byte) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		require.Equal(t, []string{fileName}, files)
+	})
+}
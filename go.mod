@@ -7,7 +7,7 @@ require (
 	github.com/RoaringBitmap/roaring v0.9.1
 	github.com/VictoriaMetrics/VictoriaMetrics v1.67.0
 	github.com/VictoriaMetrics/fastcache v1.7.0
-	github.com/apache/arrow/go/arrow v0.0.0-20200923215132-ac86123a3f01
+	github.com/apache/arrow/go/arrow v0.0.0-20201229220542-30ce2eb5d4dc
 	github.com/armon/go-metrics v0.3.10
 	github.com/c-bata/go-prompt v0.2.2
 	github.com/cockroachdb/pebble v0.0.0-20211013210608-e95e73745ce8
@@ -25,6 +25,7 @@ require (
 	github.com/klauspost/compress v1.13.6
 	github.com/mitchellh/cli v1.1.0
 	github.com/mitchellh/copystructure v1.2.0
+	github.com/pierrec/lz4/v4 v4.1.17
 	github.com/pingcap/failpoint v0.0.0-20200702092429-9f69995143ce
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.12.1
@@ -44,6 +45,7 @@ require (
 	golang.org/x/text v0.3.7
 	golang.org/x/time v0.0.0-20210723032227-1f47c861a9ac
 	golang.org/x/tools v0.1.12 // indirect
+	google.golang.org/grpc v1.42.0
 	google.golang.org/protobuf v1.27.1
 	gopkg.in/natefinch/lumberjack.v2 v2.0.1-0.20190411184413-94d9e492cc53
 )
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/lib/logger"
+	"go.uber.org/zap"
+)
+
+// Types of the audit events.
+const (
+	Login       = "login"
+	Statement   = "statement"
+	MetaCommand = "meta_command"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Event is a security relevant action recorded by the audit log.
+type Event struct {
+	Time time.Time
+	Type string
+
+	// Name of the user who did the action, empty if it is unknown.
+	User string
+
+	// Remote address of the client.
+	Source string
+
+	Database string
+
+	// Object affected by the action, such as a database, a retention policy or a role.
+	Object string
+
+	// Statement text, or command type of the meta commands.
+	Statement string
+
+	// Error of the action, nil if it succeeded.
+	Err error
+}
+
+// Result returns whether the action succeeded.
+func (e *Event) Result() string {
+	if e.Err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+// Sink receives the audit events in addition to the audit log file.
+type Sink interface {
+	WriteEvent(e *Event)
+}
+
+// auditLog is the audit log of the process. In the single binary, ts-meta and
+// ts-sql share it, it is closed when both are closed.
+type auditLog struct {
+	mu     sync.RWMutex
+	refs   int
+	logger *zap.Logger
+	closer io.Closer
+	sinks  []Sink
+}
+
+var std auditLog
+
+// Open starts writing the audit events into audit.log of the logging path.
+func Open(conf config.Audit, logConf config.Logger) {
+	if !conf.Enabled {
+		return
+	}
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.refs++
+	if std.logger == nil {
+		std.logger, std.closer = logger.NewFileLogger(logConf, "audit")
+	}
+}
+
+// Close stops the audit log once every Open has been closed.
+func Close() {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	if std.refs == 0 {
+		return
+	}
+	std.refs--
+	if std.refs > 0 {
+		return
+	}
+	_ = std.logger.Sync()
+	_ = std.closer.Close()
+	std.logger, std.closer, std.sinks = nil, nil, nil
+}
+
+// Enabled returns true if the audit log is open.
+func Enabled() bool {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	return std.logger != nil
+}
+
+// AddSink registers a sink receiving the events of the open audit log.
+func AddSink(s Sink) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	if std.logger != nil {
+		std.sinks = append(std.sinks, s)
+	}
+}
+
+// RemoveSink unregisters a sink.
+func RemoveSink(s Sink) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	for i := range std.sinks {
+		if std.sinks[i] == s {
+			std.sinks = append(std.sinks[:i], std.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Log records an event, it does nothing if the audit log is not open.
+func Log(e *Event) {
+	std.mu.RLock()
+	defer std.mu.RUnlock()
+	if std.logger == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	fields := []zap.Field{
+		zap.String("type", e.Type),
+		zap.String("user", e.User),
+		zap.String("source", e.Source),
+		zap.String("db", e.Database),
+		zap.String("object", e.Object),
+		zap.String("statement", e.Statement),
+		zap.String("result", e.Result()),
+	}
+	if e.Err != nil {
+		fields = append(fields, zap.String("error", e.Err.Error()))
+	}
+	if ce := std.logger.Check(zap.InfoLevel, "audit"); ce != nil {
+		ce.Time = e.Time
+		ce.Write(fields...)
+	}
+	for _, s := range std.sinks {
+		s.WriteEvent(e)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/audit"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSink struct {
+	events []*audit.Event
+}
+
+func (s *mockSink) WriteEvent(e *audit.Event) {
+	s.events = append(s.events, e)
+}
+
+func TestAuditLog(t *testing.T) {
+	dir := t.TempDir()
+	logConf := config.NewLogger(config.AppSql)
+	logConf.Path = dir
+	conf := config.NewAudit()
+
+	// disabled audit log drops the events
+	audit.Open(conf, logConf)
+	require.False(t, audit.Enabled())
+	audit.Log(&audit.Event{Type: audit.Login, User: "nobody"})
+
+	conf.Enabled = true
+	audit.Open(conf, logConf)
+	audit.Open(conf, logConf)
+	require.True(t, audit.Enabled())
+
+	sink := &mockSink{}
+	audit.AddSink(sink)
+	audit.Log(&audit.Event{Type: audit.Login, User: "admin", Source: "127.0.0.1:8086"})
+	audit.Log(&audit.Event{Type: audit.Statement, User: "bob", Database: "db0",
+		Statement: "DROP DATABASE db0", Err: errors.New("authorization failed")})
+	audit.RemoveSink(sink)
+	audit.Log(&audit.Event{Type: audit.MetaCommand, Statement: "DropDatabaseCommand", Object: "db0"})
+
+	require.Len(t, sink.events, 2)
+	require.Equal(t, audit.ResultSuccess, sink.events[0].Result())
+	require.Equal(t, audit.ResultFailure, sink.events[1].Result())
+	require.False(t, sink.events[0].Time.IsZero())
+
+	// the audit log stays open until both users have closed it
+	audit.Close()
+	require.True(t, audit.Enabled())
+	audit.Close()
+	require.False(t, audit.Enabled())
+	audit.Close()
+
+	f, err := os.Open(filepath.Join(dir, "audit.log"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+	require.Equal(t, "login", lines[0]["type"])
+	require.Equal(t, "admin", lines[0]["user"])
+	require.Equal(t, "success", lines[0]["result"])
+	require.Equal(t, "DROP DATABASE db0", lines[1]["statement"])
+	require.Equal(t, "failure", lines[1]["result"])
+	require.Equal(t, "authorization failed", lines[1]["error"])
+	require.Equal(t, "meta_command", lines[2]["type"])
+	require.Equal(t, "db0", lines[2]["object"])
+}
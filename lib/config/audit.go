@@ -0,0 +1,66 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+)
+
+const (
+	DefaultAuditDatabase     = "_audit"
+	DefaultAuditBatchSize    = 100
+	DefaultAuditBatchTimeout = time.Second
+)
+
+// Audit represents the configuration of the security audit log. The events are
+// written into audit.log of the logging path, rotated as the application logs.
+type Audit struct {
+	Enabled bool `toml:"enabled"`
+
+	// WriteDatabase writes the events into Database too, only ts-sql writes them.
+	WriteDatabase bool          `toml:"write-database"`
+	Database      string        `toml:"database"`
+	BatchSize     int           `toml:"batch-size"`
+	BatchTimeout  toml.Duration `toml:"batch-timeout"`
+}
+
+func NewAudit() Audit {
+	return Audit{
+		Database:     DefaultAuditDatabase,
+		BatchSize:    DefaultAuditBatchSize,
+		BatchTimeout: toml.Duration(DefaultAuditBatchTimeout),
+	}
+}
+
+func (c Audit) Validate() error {
+	if !c.Enabled || !c.WriteDatabase {
+		return nil
+	}
+	if c.Database == "" {
+		return errors.New("audit database can not be empty")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("audit batch-size must be positive")
+	}
+	if c.BatchTimeout <= 0 {
+		return errors.New("audit batch-timeout must be positive")
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	itoml "github.com/influxdata/influxdb/toml"
 	"github.com/openGemini/openGemini/lib/config"
 	"github.com/stretchr/testify/assert"
 )
@@ -91,6 +92,67 @@ func TestTSMonitor(t *testing.T) {
 	assert.Empty(t, conf.GetCommon())
 }
 
+func TestTSMonitor_Alert(t *testing.T) {
+	txt := `
+[alert]
+  enabled = true
+  evaluation-interval = "30s"
+  webhook-urls = ["http://127.0.0.1:9000/hook"]
+  [[alert.rule]]
+    name = "high_cpu"
+    type = "threshold"
+    query = "SELECT last(CpuUsage) FROM system GROUP BY host"
+    operator = ">"
+    threshold = 90.0
+    for = "2m"
+    labels = {severity = "critical"}
+  [[alert.rule]]
+    name = "no_metric"
+    type = "absence"
+    query = "SELECT count(CpuUsage) FROM system WHERE time > now() - 5m"
+  [[alert.silence]]
+    rule = "high_cpu"
+    tags = {host = "node1"}
+    starts-at = 2022-01-01T00:00:00Z
+    ends-at = 2022-01-02T00:00:00Z
+`
+	configFile := t.TempDir() + "/monitor.conf"
+	_ = os.WriteFile(configFile, []byte(txt), 0600)
+
+	conf := config.NewTSMonitor()
+	if !assert.NoError(t, config.Parse(conf, configFile)) {
+		return
+	}
+	assert.NoError(t, conf.Validate())
+
+	alert := conf.AlertConfig
+	assert.Equal(t, 30*time.Second, time.Duration(alert.EvaluationInterval))
+	assert.Equal(t, config.DefaultMonitorDatabase, alert.Database)
+	assert.Equal(t, 2, len(alert.Rules))
+	assert.Equal(t, 2*time.Minute, time.Duration(alert.Rules[0].For))
+	assert.Equal(t, "critical", alert.Rules[0].Labels["severity"])
+	assert.Equal(t, config.AlertRuleAbsence, alert.Rules[1].Type)
+	assert.Equal(t, "node1", alert.Silences[0].Tags["host"])
+
+	alert.Rules[1].Name = "high_cpu"
+	assert.EqualError(t, alert.Validate(), `duplicate alert rule name "high_cpu"`)
+	alert.Rules[1].Name = "no_metric"
+
+	alert.Rules[0].Operator = "=~"
+	assert.EqualError(t, alert.Validate(), `alert rule "high_cpu": unknown operator "=~"`)
+	alert.Rules[0].Operator = ">"
+
+	alert.Rules[0].Type = "unknown"
+	assert.EqualError(t, alert.Validate(), `alert rule "high_cpu": unknown type "unknown"`)
+	alert.Rules[0].Type = config.AlertRuleThreshold
+
+	alert.Silences[0].EndsAt = alert.Silences[0].StartsAt.Add(-time.Hour)
+	assert.EqualError(t, alert.Validate(), `alert silence for rule "high_cpu" ends before it starts`)
+
+	alert.Enabled = false
+	assert.NoError(t, alert.Validate())
+}
+
 func TestMonitor(t *testing.T) {
 	conf := config.NewMonitor(config.AppMonitor)
 	assert.NoError(t, conf.Validate())
@@ -169,6 +231,121 @@ func TestTSStore(t *testing.T) {
 	assert.NoError(t, conf.Data.ValidateEngine(eng))
 }
 
+func TestStore_CardinalityLimit(t *testing.T) {
+	conf := config.NewTSStore()
+	conf.Data.IngesterAddress = "127.0.0.1:8800"
+	conf.Data.SelectAddress = "127.0.0.1:8801"
+	conf.Data.DataDir = "/opt/gemini"
+	conf.Data.MetaDir = "/opt/gemini/meta"
+	conf.Data.WALDir = "/opt/gemini/wal"
+	assert.NoError(t, conf.Validate())
+	assert.False(t, conf.Data.CardinalityLimit("db0").Enabled())
+
+	conf.Data.MaxSeriesPerDatabase = -1
+	assert.EqualError(t, conf.Validate(), "data max-series-per-database must be greater than 0. got: -1")
+	conf.Data.MaxSeriesPerDatabase = 1000
+	conf.Data.MaxValuesPerTag = 10
+
+	conf.Data.CardinalityLimits = []config.CardinalityLimit{
+		{Database: "db1", MaxSeries: 10},
+		{Database: "db1", MaxSeries: 20},
+	}
+	assert.EqualError(t, conf.Validate(), "data cardinality-limit duplicate database: db1")
+	conf.Data.CardinalityLimits[1].Database = ""
+	assert.EqualError(t, conf.Validate(), "data cardinality-limit database must not be empty")
+	conf.Data.CardinalityLimits[1].Database = "db2"
+	conf.Data.CardinalityLimits[1].MaxValuesPerTag = -5
+	assert.EqualError(t, conf.Validate(), "data cardinality-limit max-values-per-tag must be greater than 0. got: -5")
+	conf.Data.CardinalityLimits[1].MaxValuesPerTag = 0
+	assert.NoError(t, conf.Validate())
+
+	l := conf.Data.CardinalityLimit("db0")
+	assert.True(t, l.Enabled())
+	assert.Equal(t, 1000, l.MaxSeries)
+	assert.Equal(t, 10, l.MaxValuesPerTag)
+
+	l = conf.Data.CardinalityLimit("db1")
+	assert.Equal(t, 10, l.MaxSeries)
+	assert.Equal(t, 0, l.MaxValuesPerTag)
+}
+
+func TestConfig_ParseCardinality(t *testing.T) {
+	txt := `
+[data]
+  store-data-dir = "/opt/gemini/data"
+  max-series-per-database = 1000
+  max-values-per-tag = 10
+  [[data.cardinality-limit]]
+    database = "db1"
+    max-series = 10
+`
+	configFile := t.TempDir() + "/gemini.conf"
+	_ = os.WriteFile(configFile, []byte(txt), 0600)
+
+	// ts-sql reads the limits of the [data] section shared with ts-store
+	sql := config.NewTSSql()
+	if !assert.NoError(t, config.Parse(sql, configFile)) {
+		return
+	}
+	store := config.NewTSStore()
+	if !assert.NoError(t, config.Parse(store, configFile)) {
+		return
+	}
+	assert.Equal(t, store.Data.Cardinality, sql.Cardinality)
+	assert.Equal(t, 1000, sql.Cardinality.CardinalityLimit("db0").MaxSeries)
+	assert.Equal(t, 10, sql.Cardinality.CardinalityLimit("db1").MaxSeries)
+}
+
+func TestStore_WalReplayCorruptPolicy(t *testing.T) {
+	conf := config.NewTSStore()
+	conf.Data.IngesterAddress = "127.0.0.1:8800"
+	conf.Data.SelectAddress = "127.0.0.1:8801"
+	conf.Data.DataDir = "/opt/gemini"
+	conf.Data.MetaDir = "/opt/gemini/meta"
+	conf.Data.WALDir = "/opt/gemini/wal"
+	assert.NoError(t, conf.Validate())
+	assert.False(t, conf.Data.WalQuarantineEnabled())
+
+	conf.Data.WalReplayCorruptPolicy = "drop"
+	assert.EqualError(t, conf.Validate(), "data wal-replay-corrupt-policy must be skip or quarantine. got: drop")
+
+	conf.Data.WalReplayCorruptPolicy = config.WalCorruptPolicyQuarantine
+	assert.NoError(t, conf.Validate())
+	assert.True(t, conf.Data.WalQuarantineEnabled())
+}
+
+func TestStore_FloatCompression(t *testing.T) {
+	conf := config.NewTSStore()
+	conf.Data.IngesterAddress = "127.0.0.1:8800"
+	conf.Data.SelectAddress = "127.0.0.1:8801"
+	conf.Data.DataDir = "/opt/gemini"
+	conf.Data.MetaDir = "/opt/gemini/meta"
+	conf.Data.WALDir = "/opt/gemini/wal"
+	assert.NoError(t, conf.Validate())
+
+	conf.Data.FloatCompression = "zstd"
+	assert.EqualError(t, conf.Validate(), "data float-compression must be adaptive, gorilla, chimp or alp. got: zstd")
+
+	conf.Data.FloatCompression = config.FloatCompressionChimp
+	assert.NoError(t, conf.Validate())
+}
+
+func TestStore_IndexGCInterval(t *testing.T) {
+	conf := config.NewTSStore()
+	conf.Data.IngesterAddress = "127.0.0.1:8800"
+	conf.Data.SelectAddress = "127.0.0.1:8801"
+	conf.Data.DataDir = "/opt/gemini"
+	conf.Data.MetaDir = "/opt/gemini/meta"
+	conf.Data.WALDir = "/opt/gemini/wal"
+	assert.NoError(t, conf.Validate())
+
+	conf.Data.IndexGCInterval = itoml.Duration(-time.Hour)
+	assert.EqualError(t, conf.Validate(), "data index-gc-interval must be greater than 0. got: -3600000000000")
+
+	conf.Data.IndexGCInterval = itoml.Duration(time.Hour)
+	assert.NoError(t, conf.Validate())
+}
+
 func TestGossip_BuildSerf(t *testing.T) {
 	conf := config.NewGossip()
 	conf.LogEnabled = true
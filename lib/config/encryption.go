@@ -0,0 +1,45 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+)
+
+// Encryption represents the configuration of the encryption at rest of the
+// TSSP files, WAL and the index of ts-store.
+type Encryption struct {
+	Enabled bool `toml:"enabled"`
+
+	// KeyFile holds the keys by id, one "<id> <hex key>" per line.
+	KeyFile string `toml:"key-file"`
+
+	// ActiveKey is the id of the key encrypting the new files, 0 selects the
+	// highest id of the key file.
+	ActiveKey uint32 `toml:"active-key"`
+}
+
+func NewEncryption() Encryption {
+	return Encryption{}
+}
+
+func (c Encryption) Validate() error {
+	if c.Enabled && c.KeyFile == "" {
+		return errors.New("encryption key-file can not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+)
+
+const (
+	DefaultGraphiteBindAddress  = ":2003"
+	DefaultGraphiteDatabase     = "graphite"
+	DefaultGraphiteProtocol     = "tcp"
+	DefaultGraphiteSeparator    = "."
+	DefaultGraphiteBatchSize    = 5000
+	DefaultGraphiteBatchTimeout = time.Second
+
+	DefaultOpenTSDBBindAddress  = ":4242"
+	DefaultOpenTSDBDatabase     = "opentsdb"
+	DefaultOpenTSDBBatchSize    = 1000
+	DefaultOpenTSDBBatchTimeout = time.Second
+)
+
+// Graphite represents the configuration of the graphite plaintext listener.
+type Graphite struct {
+	Enabled         bool          `toml:"enabled"`
+	BindAddress     string        `toml:"bind-address"`
+	Database        string        `toml:"database"`
+	RetentionPolicy string        `toml:"retention-policy"`
+	Protocol        string        `toml:"protocol"`
+	BatchSize       int           `toml:"batch-size"`
+	BatchTimeout    toml.Duration `toml:"batch-timeout"`
+	UDPReadBuffer   int           `toml:"udp-read-buffer"`
+	Separator       string        `toml:"separator"`
+
+	// Templates map the dotted names to measurement, tags and field,
+	// such as "cpu.* .host.measurement.field*"
+	Templates []string `toml:"templates"`
+	// Tags are added to every point, such as "region=us-west"
+	Tags []string `toml:"tags"`
+}
+
+func NewGraphite() Graphite {
+	return Graphite{
+		BindAddress:  DefaultGraphiteBindAddress,
+		Database:     DefaultGraphiteDatabase,
+		Protocol:     DefaultGraphiteProtocol,
+		BatchSize:    DefaultGraphiteBatchSize,
+		BatchTimeout: toml.Duration(DefaultGraphiteBatchTimeout),
+		Separator:    DefaultGraphiteSeparator,
+	}
+}
+
+func (c Graphite) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Database == "" {
+		return errors.New("graphite database can not be empty")
+	}
+	if c.Protocol != "tcp" && c.Protocol != "udp" {
+		return fmt.Errorf("graphite protocol must be tcp or udp, got %q", c.Protocol)
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("graphite batch-size must be positive")
+	}
+	if c.BatchTimeout <= 0 {
+		return errors.New("graphite batch-timeout must be positive")
+	}
+	for _, tag := range c.Tags {
+		if _, _, err := ParseGraphiteTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseGraphiteTag parses a default tag in the form of key=value
+func ParseGraphiteTag(tag string) (string, string, error) {
+	kv := strings.SplitN(tag, "=", 2)
+	if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+		return "", "", fmt.Errorf("graphite tag %q must be in the form of key=value", tag)
+	}
+	return kv[0], kv[1], nil
+}
+
+// OpenTSDB represents the configuration of the opentsdb listener,
+// which serves both the telnet put command and the http /api/put endpoint on the same port.
+type OpenTSDB struct {
+	Enabled         bool          `toml:"enabled"`
+	BindAddress     string        `toml:"bind-address"`
+	Database        string        `toml:"database"`
+	RetentionPolicy string        `toml:"retention-policy"`
+	BatchSize       int           `toml:"batch-size"`
+	BatchTimeout    toml.Duration `toml:"batch-timeout"`
+	LogPointErrors  bool          `toml:"log-point-errors"`
+}
+
+func NewOpenTSDB() OpenTSDB {
+	return OpenTSDB{
+		BindAddress:    DefaultOpenTSDBBindAddress,
+		Database:       DefaultOpenTSDBDatabase,
+		BatchSize:      DefaultOpenTSDBBatchSize,
+		BatchTimeout:   toml.Duration(DefaultOpenTSDBBatchTimeout),
+		LogPointErrors: true,
+	}
+}
+
+func (c OpenTSDB) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Database == "" {
+		return errors.New("opentsdb database can not be empty")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("opentsdb batch-size must be positive")
+	}
+	if c.BatchTimeout <= 0 {
+		return errors.New("opentsdb batch-timeout must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerConfig(t *testing.T) {
+	confStr := `
+	[graphite]
+		enabled = true
+		bind-address = ":2004"
+		protocol = "udp"
+		templates = ["cpu.* .host.measurement.field*"]
+		tags = ["region=east"]
+	[opentsdb]
+		enabled = true
+		database = "tsdb"
+	`
+	c := NewTSSql()
+	_, err := toml.Decode(confStr, c)
+	require.NoError(t, err)
+	require.NoError(t, c.Graphite.Validate())
+	require.NoError(t, c.OpenTSDB.Validate())
+	require.Equal(t, ":2004", c.Graphite.BindAddress)
+	require.Equal(t, DefaultGraphiteBatchSize, c.Graphite.BatchSize)
+	require.Equal(t, "tsdb", c.OpenTSDB.Database)
+	require.Equal(t, DefaultOpenTSDBBindAddress, c.OpenTSDB.BindAddress)
+
+	c.Graphite.Protocol = "http"
+	require.Error(t, c.Graphite.Validate())
+	c.Graphite.Protocol = "tcp"
+	c.Graphite.Tags = []string{"region"}
+	require.Error(t, c.Graphite.Validate())
+	c.Graphite.Tags = nil
+	c.Graphite.BatchSize = 0
+	require.Error(t, c.Graphite.Validate())
+
+	c.OpenTSDB.BatchTimeout = 0
+	require.Error(t, c.OpenTSDB.Validate())
+	c.OpenTSDB.Enabled = false
+	require.NoError(t, c.OpenTSDB.Validate())
+}
+
+func TestResultCacheConfig(t *testing.T) {
+	confStr := `
+	[result-cache]
+		enabled = true
+		max-memory-size = "64m"
+	`
+	c := NewTSSql()
+	_, err := toml.Decode(confStr, c)
+	require.NoError(t, err)
+	require.NoError(t, c.ResultCache.Validate())
+	require.Equal(t, 64*1024*1024, int(c.ResultCache.MaxMemorySize))
+	require.Equal(t, DefaultResultCacheRecentInterval, time.Duration(c.ResultCache.RecentInterval))
+	require.Equal(t, DefaultResultCacheMaxAge, time.Duration(c.ResultCache.MaxAge))
+
+	c.ResultCache.MaxAge = -1
+	require.Error(t, c.ResultCache.Validate())
+	c.ResultCache.MaxAge = 0
+	require.NoError(t, c.ResultCache.Validate())
+
+	c.ResultCache.MaxMemorySize = 0
+	require.Error(t, c.ResultCache.Validate())
+	c.ResultCache.Enabled = false
+	require.NoError(t, c.ResultCache.Validate())
+}
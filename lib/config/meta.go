@@ -42,6 +42,7 @@ const (
 	DefaultVersion              = 0
 	DefaultSplitRowThreshold    = 1000
 	DefaultImbalanceFactor      = 0.3
+	DefaultPtBalanceInterval    = time.Minute
 	DefaultRaftStore            = "boltdb"
 	DefaultHostname             = "localhost"
 	DefaultSuspicionMult        = 4
@@ -56,6 +57,7 @@ type TSMeta struct {
 	Monitor Monitor `toml:"monitor"`
 	Gossip  *Gossip `toml:"gossip"`
 	Spdy    Spdy    `toml:"spdy"`
+	Audit   Audit   `toml:"audit"`
 
 	// TLS provides configuration options for all https endpoints.
 	TLS tlsconfig.Config `toml:"tls"`
@@ -69,6 +71,7 @@ func NewTSMeta() *TSMeta {
 	c.Monitor = NewMonitor(AppMeta)
 	c.Gossip = NewGossip()
 	c.TLS = tlsconfig.NewConfig()
+	c.Audit = NewAudit()
 
 	return c
 }
@@ -83,6 +86,7 @@ func (c *TSMeta) Validate() error {
 		c.Logging,
 		c.Gossip,
 		c.Spdy,
+		c.Audit,
 	}
 
 	for _, item := range items {
@@ -142,6 +146,11 @@ type Meta struct {
 	CommitTimeout      toml.Duration `toml:"commit-timeout"`
 	LeaseDuration      toml.Duration `toml:"lease-duration"`
 	Logging            Logger        `toml:"logging"`
+
+	// PtBalanceEnabled moves pts from the most loaded data node to the least loaded one.
+	// Pts of decommissioning data nodes are always moved out.
+	PtBalanceEnabled  bool          `toml:"pt-balance-enabled"`
+	PtBalanceInterval toml.Duration `toml:"pt-balance-interval"`
 }
 
 // NewMeta builds a new configuration with default values.
@@ -162,6 +171,7 @@ func NewMeta() *Meta {
 		Version:                 DefaultVersion,
 		SplitRowThreshold:       DefaultSplitRowThreshold,
 		ImbalanceFactor:         DefaultImbalanceFactor,
+		PtBalanceInterval:       toml.Duration(DefaultPtBalanceInterval),
 		BatchApplyCh:            true,
 		RaftStore:               DefaultRaftStore,
 		RemoteHostname:          DefaultHostname,
@@ -189,6 +199,10 @@ func (c *Meta) Validate() error {
 		return fmt.Errorf("meta split-row-threshold must be greater than 0. got: %d", c.SplitRowThreshold)
 	}
 
+	if c.PtBalanceInterval <= 0 {
+		return fmt.Errorf("meta pt-balance-interval must be greater than 0. got: %s", c.PtBalanceInterval)
+	}
+
 	return nil
 }
 
@@ -18,6 +18,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/influxdata/influxdb/toml"
@@ -65,6 +66,7 @@ type TSMonitor struct {
 	MonitorConfig MonitorMain   `toml:"monitor"`
 	QueryConfig   MonitorQuery  `toml:"query"`
 	ReportConfig  MonitorReport `toml:"report"`
+	AlertConfig   MonitorAlert  `toml:"alert"`
 	Logging       Logger        `toml:"logging"`
 }
 
@@ -74,13 +76,14 @@ func NewTSMonitor() *TSMonitor {
 	c.MonitorConfig = newMonitor()
 	c.ReportConfig = newMonitorReport()
 	c.QueryConfig = newMonitorQuery()
+	c.AlertConfig = newMonitorAlert()
 	c.Logging = NewLogger(AppMonitor)
 	return c
 }
 
 // Validate returns an error if the config is invalid.
 func (c *TSMonitor) Validate() error {
-	return nil
+	return c.AlertConfig.Validate()
 }
 
 // ApplyEnvOverrides apply the environment configuration on top of the config.
@@ -147,6 +150,117 @@ func newMonitorReport() MonitorReport {
 	}
 }
 
+const (
+	DefaultAlertEvaluationInterval = time.Minute
+
+	// AlertRuleThreshold fires when the latest value of a series crosses the threshold.
+	AlertRuleThreshold = "threshold"
+	// AlertRuleAbsence fires when the query returns no data at all.
+	AlertRuleAbsence = "absence"
+	// AlertRuleRate fires when the per-second change of a series crosses the threshold.
+	AlertRuleRate = "rate"
+)
+
+var alertOperators = map[string]struct{}{
+	">": {}, ">=": {}, "<": {}, "<=": {}, "==": {}, "!=": {},
+}
+
+// MonitorAlert represents the configuration of the ts-monitor alerting rules engine.
+type MonitorAlert struct {
+	Enabled            bool          `toml:"enabled"`
+	HttpEndpoint       string        `toml:"http-endpoint"`
+	Database           string        `toml:"database"`
+	EvaluationInterval toml.Duration `toml:"evaluation-interval"`
+	WebhookURLs        []string      `toml:"webhook-urls"`
+	FilePath           string        `toml:"file-path"`
+
+	Rules    []AlertRule    `toml:"rule"`
+	Silences []AlertSilence `toml:"silence"`
+}
+
+func newMonitorAlert() MonitorAlert {
+	return MonitorAlert{
+		Enabled:            false,
+		HttpEndpoint:       DefaultMonitorAddress,
+		Database:           DefaultMonitorDatabase,
+		EvaluationInterval: toml.Duration(DefaultAlertEvaluationInterval),
+	}
+}
+
+// Validate returns an error if the alert config is invalid.
+func (c MonitorAlert) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.EvaluationInterval <= 0 {
+		return errors.New("alert evaluation-interval must be positive")
+	}
+	names := make(map[string]struct{}, len(c.Rules))
+	for i := range c.Rules {
+		if err := c.Rules[i].Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[c.Rules[i].Name]; ok {
+			return fmt.Errorf("duplicate alert rule name %q", c.Rules[i].Name)
+		}
+		names[c.Rules[i].Name] = struct{}{}
+	}
+	for i := range c.Silences {
+		s := &c.Silences[i]
+		if !s.EndsAt.IsZero() && s.EndsAt.Before(s.StartsAt) {
+			return fmt.Errorf("alert silence for rule %q ends before it starts", s.Rule)
+		}
+	}
+	return nil
+}
+
+// AlertRule is a single alerting rule evaluated periodically by ts-monitor.
+// The query must return one value column per series, the last row of each series is evaluated.
+type AlertRule struct {
+	Name        string            `toml:"name"`
+	Type        string            `toml:"type"`
+	Database    string            `toml:"database"`
+	Query       string            `toml:"query"`
+	Operator    string            `toml:"operator"`
+	Threshold   float64           `toml:"threshold"`
+	For         toml.Duration     `toml:"for"`
+	Labels      map[string]string `toml:"labels"`
+	Description string            `toml:"description"`
+}
+
+func (r *AlertRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("alert rule name must not be empty")
+	}
+	if r.Query == "" {
+		return fmt.Errorf("alert rule %q: query must not be empty", r.Name)
+	}
+	if r.For < 0 {
+		return fmt.Errorf("alert rule %q: for must not be negative", r.Name)
+	}
+	switch r.Type {
+	case AlertRuleAbsence:
+		return nil
+	case AlertRuleThreshold, AlertRuleRate:
+	default:
+		return fmt.Errorf("alert rule %q: unknown type %q", r.Name, r.Type)
+	}
+	if _, ok := alertOperators[r.Operator]; !ok {
+		return fmt.Errorf("alert rule %q: unknown operator %q", r.Name, r.Operator)
+	}
+	return nil
+}
+
+// AlertSilence mutes notifications of a rule, optionally restricted to series having all the given tags.
+// A zero EndsAt means the silence never expires.
+type AlertSilence struct {
+	Rule     string            `toml:"rule"`
+	Tags     map[string]string `toml:"tags"`
+	StartsAt time.Time         `toml:"starts-at"`
+	EndsAt   time.Time         `toml:"ends-at"`
+	Comment  string            `toml:"comment"`
+}
+
 // Monitor represents the configuration for the monitor service.
 type Monitor struct {
 	app           App
@@ -0,0 +1,223 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// ReloadHandler applies the settings of a module which can be changed while
+// the server is running. Keys are the toml paths of the settings, like
+// "data.compact-throughput". Apply is called with the new configuration
+// whenever one of them changes, it applies all of them at once.
+type ReloadHandler struct {
+	Module string
+	Keys   []string
+	Apply  func(conf Config) error
+}
+
+// ReloadResult reports the settings changed by a reload.
+type ReloadResult struct {
+	// Applied are the changed settings in effect now.
+	Applied []string `json:"applied"`
+
+	// RestartRequired are the changed settings which only take effect after a restart.
+	RestartRequired []string `json:"restart_required"`
+}
+
+// Reloader re-reads the configuration file of a running server and applies
+// the changed settings through the registered handlers.
+type Reloader struct {
+	// Prepare fills in the values the server derives at startup, like the
+	// limits computed from the resources of the node. It is called on every
+	// configuration read, before it is compared with the running one.
+	Prepare func(conf Config)
+
+	mu       sync.Mutex
+	path     string
+	running  Config
+	newConf  func() Config
+	handlers []ReloadHandler
+}
+
+// NewReloader returns a reloader of the configuration file path. running is
+// the configuration the server started with, the applied settings are
+// copied into it. newConf returns a configuration with the default values.
+func NewReloader(path string, running Config, newConf func() Config) *Reloader {
+	return &Reloader{
+		path:    path,
+		running: running,
+		newConf: newConf,
+	}
+}
+
+// Register adds the handler of a module.
+func (r *Reloader) Register(h ReloadHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, h)
+}
+
+// Reload reads the configuration file and applies the changed settings. An
+// invalid configuration is rejected as a whole. The modules are applied one
+// after another, if one of them fails the result holds the settings applied
+// before it.
+func (r *Reloader) Reload() (*ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conf, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+
+	running := flattenConfig(r.running)
+	loaded := flattenConfig(conf)
+	changed := changedKeys(running, loaded)
+
+	result := &ReloadResult{}
+	pending := make([][]string, len(r.handlers))
+	for _, key := range changed {
+		i := r.handlerOf(key)
+		if i < 0 {
+			result.RestartRequired = append(result.RestartRequired, key)
+			continue
+		}
+		pending[i] = append(pending[i], key)
+	}
+
+	for i, keys := range pending {
+		if len(keys) == 0 {
+			continue
+		}
+		if err := r.handlers[i].Apply(conf); err != nil {
+			return result, fmt.Errorf("reload %s: %v", r.handlers[i].Module, err)
+		}
+		for _, key := range keys {
+			if dst, ok := running[key]; ok && dst.CanSet() {
+				dst.Set(loaded[key])
+			}
+		}
+		result.Applied = append(result.Applied, keys...)
+	}
+	sort.Strings(result.Applied)
+	return result, nil
+}
+
+func (r *Reloader) read() (Config, error) {
+	if r.path == "" {
+		return nil, errors.New("the server is started without a configuration file")
+	}
+	conf := r.newConf()
+	if err := Parse(conf, r.path); err != nil {
+		return nil, fmt.Errorf("parse config: %s", err)
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	if common := conf.GetCommon(); common != nil {
+		common.Corrector()
+	}
+	if r.Prepare != nil {
+		r.Prepare(conf)
+	}
+	return conf, nil
+}
+
+func (r *Reloader) handlerOf(key string) int {
+	for i := range r.handlers {
+		for _, k := range r.handlers[i].Keys {
+			if k == key {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func changedKeys(running, loaded map[string]reflect.Value) []string {
+	var keys []string
+	for key, v := range loaded {
+		old, ok := running[key]
+		if !ok || !reflect.DeepEqual(old.Interface(), v.Interface()) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range running {
+		if _, ok := loaded[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flattenConfig maps the toml path of every setting of conf to its value.
+func flattenConfig(conf Config) map[string]reflect.Value {
+	values := make(map[string]reflect.Value)
+	flattenValue("", reflect.ValueOf(conf), values)
+	return values
+}
+
+func flattenValue(path string, v reflect.Value, dst map[string]reflect.Value) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() || isLeafType(v.Type()) {
+			dst[path] = v
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || isLeafType(v.Type()) {
+		dst[path] = v
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		flattenValue(name, v.Field(i), dst)
+	}
+}
+
+// isLeafType returns true for the types decoded from a single toml value.
+func isLeafType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() != reflect.Struct || reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const reloadConf = `
+[data]
+  store-ingest-addr = "127.0.0.1:8400"
+  store-select-addr = "127.0.0.1:8401"
+  store-data-dir = "/tmp/openGemini/data"
+  store-meta-dir = "/tmp/openGemini/meta"
+  store-wal-dir = "/tmp/openGemini/wal"
+  compact-throughput = "%s"
+  read-cache-limit = %d
+[logging]
+  level = "%s"
+[http]
+  bind-address = "%s"
+  slow-query-time = "%s"
+`
+
+func newReloadStore() config.Config {
+	return config.NewTSStore()
+}
+
+func writeReloadConf(t *testing.T, file string, args ...interface{}) {
+	require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(reloadConf, args...)), 0600))
+}
+
+func loadRunningStore(t *testing.T, file string) *config.TSStore {
+	conf := config.NewTSStore()
+	require.NoError(t, config.Parse(conf, file))
+	require.NoError(t, conf.Validate())
+	conf.Common.Corrector()
+	conf.Data.Corrector(conf.Common.CPUNum, conf.Common.MemorySize)
+	return conf
+}
+
+func TestReloader_Reload(t *testing.T) {
+	file := t.TempDir() + "/store.conf"
+	writeReloadConf(t, file, "48m", 0, "info", "127.0.0.1:8086", "10s")
+	running := loadRunningStore(t, file)
+
+	var dataApplied, loggingApplied int
+	var readCache int
+	r := config.NewReloader(file, running, newReloadStore)
+	r.Prepare = func(c config.Config) {
+		conf := c.(*config.TSStore)
+		conf.Data.Corrector(conf.Common.CPUNum, conf.Common.MemorySize)
+	}
+	r.Register(config.ReloadHandler{
+		Module: "data",
+		Keys:   []string{"data.compact-throughput", "data.read-cache-limit"},
+		Apply: func(c config.Config) error {
+			dataApplied++
+			readCache = c.(*config.TSStore).Data.ReadCacheLimit
+			return nil
+		},
+	})
+	r.Register(config.ReloadHandler{
+		Module: "logging",
+		Keys:   []string{"logging.level"},
+		Apply: func(c config.Config) error {
+			loggingApplied++
+			return nil
+		},
+	})
+
+	// nothing changed
+	res, err := r.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, res.Applied)
+	assert.Empty(t, res.RestartRequired)
+	assert.Equal(t, 0, dataApplied+loggingApplied)
+
+	writeReloadConf(t, file, "64m", 1024, "debug", "127.0.0.1:8087", "10s")
+	res, err = r.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data.compact-throughput", "data.read-cache-limit", "logging.level"}, res.Applied)
+	assert.Equal(t, []string{"http.bind-address"}, res.RestartRequired)
+	assert.Equal(t, 1, dataApplied)
+	assert.Equal(t, 1, loggingApplied)
+	assert.Equal(t, 1024, readCache)
+	assert.Equal(t, zapcore.DebugLevel, running.Logging.Level)
+	assert.Equal(t, 1024, running.Data.ReadCacheLimit)
+	assert.Equal(t, "127.0.0.1:8086", running.HTTPD.BindAddress)
+
+	// the applied settings are not applied again
+	res, err = r.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, res.Applied)
+	assert.Equal(t, []string{"http.bind-address"}, res.RestartRequired)
+	assert.Equal(t, 1, dataApplied)
+}
+
+func TestReloader_ReloadFailed(t *testing.T) {
+	file := t.TempDir() + "/store.conf"
+	writeReloadConf(t, file, "48m", 0, "info", "127.0.0.1:8086", "10s")
+	running := loadRunningStore(t, file)
+
+	r := config.NewReloader(file, running, newReloadStore)
+	r.Register(config.ReloadHandler{
+		Module: "http",
+		Keys:   []string{"http.slow-query-time"},
+		Apply: func(c config.Config) error {
+			return errors.New("mock error")
+		},
+	})
+	r.Register(config.ReloadHandler{
+		Module: "logging",
+		Keys:   []string{"logging.level"},
+		Apply: func(c config.Config) error {
+			return nil
+		},
+	})
+
+	writeReloadConf(t, file, "48m", 0, "info", "127.0.0.1:8086", "20s")
+	_, err := r.Reload()
+	assert.EqualError(t, err, "reload http: mock error")
+	assert.Equal(t, 10*time.Second, time.Duration(running.HTTPD.SlowQueryTime))
+
+	require.NoError(t, os.WriteFile(file, []byte("[logging]\n  level = 1\n  max-size = \"x\""), 0600))
+	_, err = r.Reload()
+	assert.Error(t, err)
+
+	_, err = config.NewReloader("", running, newReloadStore).Reload()
+	assert.Error(t, err)
+}
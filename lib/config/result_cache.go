@@ -0,0 +1,72 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+)
+
+const (
+	DefaultResultCacheMaxMemorySize  = 256 * 1024 * 1024
+	DefaultResultCacheRecentInterval = time.Minute
+	DefaultResultCacheMaxAge         = 10 * time.Minute
+)
+
+// ResultCache represents the configuration of the cache of the GROUP BY time()
+// query results on ts-sql.
+type ResultCache struct {
+	Enabled       bool      `toml:"enabled"`
+	MaxMemorySize toml.Size `toml:"max-memory-size"`
+
+	// RecentInterval is the age below which the buckets are still written to,
+	// they are always fetched from the stores and never cached.
+	RecentInterval toml.Duration `toml:"recent-interval"`
+
+	// MaxAge is the time after which an entry is dropped, 0 keeps the entries
+	// until they are evicted or invalidated. The writes, deletes and drops sent
+	// to the other ts-sql nodes are only seen by their own caches, so with
+	// several ts-sql nodes a cached result may be stale for up to MaxAge, 10m
+	// by default.
+	MaxAge toml.Duration `toml:"max-age"`
+}
+
+func NewResultCache() ResultCache {
+	return ResultCache{
+		MaxMemorySize:  DefaultResultCacheMaxMemorySize,
+		RecentInterval: toml.Duration(DefaultResultCacheRecentInterval),
+		MaxAge:         toml.Duration(DefaultResultCacheMaxAge),
+	}
+}
+
+func (c ResultCache) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxMemorySize <= 0 {
+		return errors.New("result-cache max-memory-size must be positive")
+	}
+	if c.RecentInterval < 0 {
+		return errors.New("result-cache recent-interval can not be negative")
+	}
+	if c.MaxAge < 0 {
+		return errors.New("result-cache max-age can not be negative")
+	}
+	return nil
+}
@@ -47,6 +47,11 @@ type Spdy struct {
 	TLSClientPrivateKey   string `toml:"tls-client-private-key"`
 	TLSCARoot             string `toml:"tls-ca-root"`
 	TLSServerName         string `toml:"tls-server-name"`
+
+	// Compression is the block compression requested for response frames
+	// when opening a session. The remote node may refuse it, in which case
+	// the session falls back to uncompressed frames.
+	Compression string `toml:"compression"`
 }
 
 const (
@@ -67,6 +72,13 @@ const (
 	DefaultConnPoolSize            = 4
 )
 
+const (
+	SpdyCompressionNone   = "none"
+	SpdyCompressionSnappy = "snappy"
+	SpdyCompressionZstd   = "zstd"
+	SpdyCompressionLz4    = "lz4"
+)
+
 func NewSpdy() Spdy {
 	return Spdy{
 		RecvWindowSize:            DefaultRecvWindowSize,
@@ -77,6 +89,7 @@ func NewSpdy() Spdy {
 		TCPDialTimeout:            DefaultTCPDialTimeout,
 		TLSEnable:                 false,
 		ConnPoolSize:              DefaultConnPoolSize,
+		Compression:               SpdyCompressionNone,
 	}
 }
 
@@ -121,6 +134,12 @@ func (c *Spdy) NewClientTLSConfig() (*tls.Config, error) {
 }
 
 func (c Spdy) Validate() error {
+	switch c.Compression {
+	case "", SpdyCompressionNone, SpdyCompressionSnappy, SpdyCompressionZstd, SpdyCompressionLz4:
+	default:
+		return errno.NewError(errno.UnknownCompression, c.Compression)
+	}
+
 	if !c.TLSEnable {
 		return nil
 	}
@@ -155,6 +174,9 @@ func FormatSpdy(cfg *Spdy) {
 	cfg.SessionSelectTimeout = limitDuration(cfg.SessionSelectTimeout, MinSessionSelectTimeout, DefaultSessionSelectTimeout)
 	cfg.TCPDialTimeout = limitDuration(cfg.TCPDialTimeout, MinTCPDialTimeout, DefaultTCPDialTimeout)
 	cfg.ConnPoolSize = formatInt(cfg.ConnPoolSize, MinConnPoolSize, DefaultConnPoolSize)
+	if cfg.Compression == "" {
+		cfg.Compression = SpdyCompressionNone
+	}
 	if cfg.TLSCertificate == "" {
 		cfg.TLSEnable = false
 	}
@@ -45,6 +45,22 @@ func TestSpdy(t *testing.T) {
 	assert.Equal(t, time.Duration(conf.TCPDialTimeout), conf.GetTCPDialTimeout())
 }
 
+func TestSpdyCompression(t *testing.T) {
+	conf := config.NewSpdy()
+	conf.Compression = ""
+	config.FormatSpdy(&conf)
+	assert.Equal(t, config.SpdyCompressionNone, conf.Compression)
+	assert.NoError(t, conf.Validate())
+
+	for _, name := range []string{config.SpdyCompressionSnappy, config.SpdyCompressionZstd, config.SpdyCompressionLz4} {
+		conf.Compression = name
+		assert.NoError(t, conf.Validate())
+	}
+
+	conf.Compression = "lzo"
+	assert.EqualError(t, conf.Validate(), errno.NewError(errno.UnknownCompression, "lzo").Error())
+}
+
 func TestSpdyTLSConfig(t *testing.T) {
 	var err error
 	if !assert.NoError(t, execCommand([]string{
@@ -65,6 +65,15 @@ type TSSql struct {
 	// TLS provides configuration options for all https endpoints.
 	TLS      tlsconfig.Config `toml:"tls"`
 	Analysis Castor           `toml:"castor"`
+
+	Graphite Graphite `toml:"graphite"`
+	OpenTSDB OpenTSDB `toml:"opentsdb"`
+
+	Audit       Audit       `toml:"audit"`
+	ResultCache ResultCache `toml:"result-cache"`
+
+	// Cardinality is read from the [data] section shared with ts-store
+	Cardinality Cardinality `toml:"data"`
 }
 
 // NewTSSql returns an instance of Config with reasonable defaults.
@@ -76,6 +85,10 @@ func NewTSSql() *TSSql {
 	c.Logging = NewLogger(AppSql)
 	c.HTTP = httpdConfig.NewConfig()
 	c.Analysis = NewCastor()
+	c.Graphite = NewGraphite()
+	c.OpenTSDB = NewOpenTSDB()
+	c.Audit = NewAudit()
+	c.ResultCache = NewResultCache()
 	return c
 }
 
@@ -90,6 +103,11 @@ func (c *TSSql) Validate() error {
 		c.HTTP,
 		c.Spdy,
 		c.Analysis,
+		c.Graphite,
+		c.OpenTSDB,
+		c.Audit,
+		c.ResultCache,
+		c.Cardinality,
 	}
 
 	for _, item := range items {
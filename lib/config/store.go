@@ -52,6 +52,19 @@ const (
 	DefaultSnapshotThroughputBurst = 64 * MB
 	DefaultMaxWriteHangTime        = 15 * time.Second
 	DefaultWALSyncInterval         = 100 * time.Millisecond
+	DefaultLastValueCacheMaxSize   = 256 * MB
+
+	// WalCorruptPolicySkip skips corrupt wal records during replay
+	WalCorruptPolicySkip = "skip"
+	// WalCorruptPolicyQuarantine copies corrupt wal records to the corrupt directory and then skips them
+	WalCorruptPolicyQuarantine = "quarantine"
+
+	// FloatCompressionAdaptive picks the smallest float codec for each block
+	FloatCompressionAdaptive = "adaptive"
+	// FloatCompressionGorilla keeps the float blocks readable by the versions without the other codecs
+	FloatCompressionGorilla = "gorilla"
+	FloatCompressionChimp   = "chimp"
+	FloatCompressionALP     = "alp"
 )
 
 // TSStore represents the configuration format for the influxd binary.
@@ -69,8 +82,9 @@ type TSStore struct {
 	HierarchicalStore retention.Config `toml:"hierarchical-storage"`
 
 	// TLS provides configuration options for all https endpoints.
-	TLS      tlsconfig.Config `toml:"tls"`
-	Analysis Castor           `toml:"castor"`
+	TLS        tlsconfig.Config `toml:"tls"`
+	Analysis   Castor           `toml:"castor"`
+	Encryption Encryption       `toml:"encryption"`
 }
 
 // NewTSStore returns an instance of Config with reasonable defaults.
@@ -90,6 +104,7 @@ func NewTSStore() *TSStore {
 	c.Gossip = NewGossip()
 
 	c.Analysis = NewCastor()
+	c.Encryption = NewEncryption()
 	return c
 }
 
@@ -109,6 +124,7 @@ func (c *TSStore) Validate() error {
 		c.Logging,
 		c.Spdy,
 		c.Analysis,
+		c.Encryption,
 	}
 
 	for _, item := range items {
@@ -161,6 +177,9 @@ type Store struct {
 	SnapshotThroughputBurst      toml.Size     `toml:"snapshot-throughput-burst"`
 	CompactionMethod             int           `toml:"compaction-method"` // 0:auto, 1: streaming, 2: non-streaming
 
+	// FloatCompression is the codec of the float blocks written by flush and compaction
+	FloatCompression string `toml:"float-compression"`
+
 	// Configs for snapshot
 	WriteColdDuration     toml.Duration `toml:"write-cold-duration"`
 	ShardMutableSizeLimit toml.Size     `toml:"shard-mutable-size-limit"`
@@ -178,6 +197,66 @@ type Store struct {
 
 	ReadCacheLimit       int `toml:"read-cache-limit"`
 	WriteConcurrentLimit int `toml:"write-concurrent-limit"`
+
+	// WalReplayCorruptPolicy is how corrupt wal records are handled during replay, skip or quarantine
+	WalReplayCorruptPolicy string `toml:"wal-replay-corrupt-policy"`
+
+	Cardinality
+
+	// The last value cache keeps the latest value of each field of the series, to answer
+	// last() queries without reading the files. Empty LastValueCacheMeasurements caches all
+	// the measurements. LastValueCacheMaxSize limits the memory of all the shards of the node.
+	LastValueCacheEnabled      bool      `toml:"last-value-cache-enabled"`
+	LastValueCacheMeasurements []string  `toml:"last-value-cache-measurements"`
+	LastValueCacheMaxSize      toml.Size `toml:"last-value-cache-max-size"`
+
+	// SortKey is the tag keys the tssp2 engine sorts the rows of its column files by,
+	// the equality conditions on a prefix of them skip the granules of the files.
+	SortKey []string `toml:"sort-key"`
+
+	// IndexGCInterval is how often the series without data left in any shard are removed
+	// from the index, 0 disables the index garbage collection.
+	IndexGCInterval toml.Duration `toml:"index-gc-interval"`
+}
+
+// Cardinality is the cardinality limits of the [data] section. They are read by ts-sql too,
+// to drop the points of a write request which can not fit in the stores before they are sent.
+type Cardinality struct {
+	// Cardinality limits of a database on a node, checked against the sum of all the pts and
+	// index time ranges of the database. 0 means unlimited. Databases listed in CardinalityLimits
+	// override the defaults.
+	MaxSeriesPerDatabase    int                `toml:"max-series-per-database"`
+	MaxSeriesPerMeasurement int                `toml:"max-series-per-measurement"`
+	MaxValuesPerTag         int                `toml:"max-values-per-tag"`
+	CardinalityLimits       []CardinalityLimit `toml:"cardinality-limit"`
+}
+
+// CardinalityLimit overrides the cardinality limits of one database.
+type CardinalityLimit struct {
+	Database                string `toml:"database"`
+	MaxSeries               int    `toml:"max-series"`
+	MaxSeriesPerMeasurement int    `toml:"max-series-per-measurement"`
+	MaxValuesPerTag         int    `toml:"max-values-per-tag"`
+}
+
+// Enabled returns true if any of the limits is set.
+func (l CardinalityLimit) Enabled() bool {
+	return l.MaxSeries > 0 || l.MaxSeriesPerMeasurement > 0 || l.MaxValuesPerTag > 0
+}
+
+// CardinalityLimit returns the cardinality limits applied to the database db.
+func (c *Cardinality) CardinalityLimit(db string) CardinalityLimit {
+	for i := range c.CardinalityLimits {
+		if c.CardinalityLimits[i].Database == db {
+			return c.CardinalityLimits[i]
+		}
+	}
+	return CardinalityLimit{
+		Database:                db,
+		MaxSeries:               c.MaxSeriesPerDatabase,
+		MaxSeriesPerMeasurement: c.MaxSeriesPerMeasurement,
+		MaxValuesPerTag:         c.MaxValuesPerTag,
+	}
 }
 
 // NewStore returns the default configuration for tsdb.
@@ -200,8 +279,10 @@ func NewStore() Store {
 		WalSyncInterval:              toml.Duration(DefaultWALSyncInterval),
 		WalEnabled:                   true,
 		WalReplayParallel:            false,
+		WalReplayCorruptPolicy:       WalCorruptPolicySkip,
 		CompactRecovery:              true,
 		CompactionMethod:             0,
+		LastValueCacheMaxSize:        toml.Size(DefaultLastValueCacheMaxSize),
 	}
 }
 
@@ -254,13 +335,64 @@ func (c Store) Validate() error {
 		{"data imm-table-max-memory-percentage", int64(c.ImmTableMaxMemoryPercentage), false},
 		{"data write-cold-duration", int64(c.WriteColdDuration), false},
 		{"data max-write-hang-time", int64(c.MaxWriteHangTime), false},
+		{"data index-gc-interval", int64(c.IndexGCInterval), true},
+	}
+	if c.LastValueCacheEnabled {
+		ivItems = append(ivItems, intValidatorItem{"data last-value-cache-max-size", int64(c.LastValueCacheMaxSize), false})
 	}
 	iv := intValidator{0, math.MaxInt64}
 	if err := iv.Validate(ivItems); err != nil {
 		return err
 	}
 
-	return nil
+	switch c.WalReplayCorruptPolicy {
+	case "", WalCorruptPolicySkip, WalCorruptPolicyQuarantine:
+	default:
+		return fmt.Errorf("data wal-replay-corrupt-policy must be %s or %s. got: %s",
+			WalCorruptPolicySkip, WalCorruptPolicyQuarantine, c.WalReplayCorruptPolicy)
+	}
+
+	switch c.FloatCompression {
+	case "", FloatCompressionAdaptive, FloatCompressionGorilla, FloatCompressionChimp, FloatCompressionALP:
+	default:
+		return fmt.Errorf("data float-compression must be %s, %s, %s or %s. got: %s", FloatCompressionAdaptive,
+			FloatCompressionGorilla, FloatCompressionChimp, FloatCompressionALP, c.FloatCompression)
+	}
+
+	return c.Cardinality.Validate()
+}
+
+// WalQuarantineEnabled returns whether corrupt wal records are copied aside before they are skipped
+func (c *Store) WalQuarantineEnabled() bool {
+	return c.WalReplayCorruptPolicy == WalCorruptPolicyQuarantine
+}
+
+// Validate returns an error if any limit is negative or a database is listed twice.
+func (c Cardinality) Validate() error {
+	ivItems := []intValidatorItem{
+		{"data max-series-per-database", int64(c.MaxSeriesPerDatabase), true},
+		{"data max-series-per-measurement", int64(c.MaxSeriesPerMeasurement), true},
+		{"data max-values-per-tag", int64(c.MaxValuesPerTag), true},
+	}
+
+	dbs := make(map[string]struct{}, len(c.CardinalityLimits))
+	for _, l := range c.CardinalityLimits {
+		if l.Database == "" {
+			return fmt.Errorf("data cardinality-limit database must not be empty")
+		}
+		if _, ok := dbs[l.Database]; ok {
+			return fmt.Errorf("data cardinality-limit duplicate database: %s", l.Database)
+		}
+		dbs[l.Database] = struct{}{}
+
+		ivItems = append(ivItems,
+			intValidatorItem{"data cardinality-limit max-series", int64(l.MaxSeries), true},
+			intValidatorItem{"data cardinality-limit max-series-per-measurement", int64(l.MaxSeriesPerMeasurement), true},
+			intValidatorItem{"data cardinality-limit max-values-per-tag", int64(l.MaxValuesPerTag), true},
+		)
+	}
+
+	return intValidator{0, math.MaxInt64}.Validate(ivItems)
 }
 
 func (c Store) ValidateEngine(engines []string) error {
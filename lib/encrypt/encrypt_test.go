@@ -0,0 +1,315 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encrypt_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/encrypt"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeys = `
+# id key
+1 000102030405060708090a0b0c0d0e0f000102030405060708090a0b0c0d0e0f
+2 0f0e0d0c0b0a09080706050403020100
+`
+
+func newKeyring(t *testing.T) *encrypt.Keyring {
+	k, err := encrypt.ParseKeyring([]byte(testKeys))
+	require.NoError(t, err)
+	return k
+}
+
+func createFile(t *testing.T, name string) *os.File {
+	f, err := os.OpenFile(filepath.Join(t.TempDir(), name), os.O_CREATE|os.O_RDWR, 0600)
+	require.NoError(t, err)
+	return f
+}
+
+func TestParseKeyring(t *testing.T) {
+	k := newKeyring(t)
+	require.Equal(t, uint32(2), k.Active())
+	require.NoError(t, k.SetActive(1))
+	require.Equal(t, uint32(1), k.Active())
+	require.Equal(t, encrypt.ErrKeyNotFound, k.SetActive(3))
+
+	for _, keys := range []string{
+		"",
+		"1",
+		"x 000102030405060708090a0b0c0d0e0f",
+		"1 zz",
+		"1 0001",
+		"1 000102030405060708090a0b0c0d0e0f\n1 000102030405060708090a0b0c0d0e0f",
+	} {
+		_, err := encrypt.ParseKeyring([]byte(keys))
+		require.Error(t, err, keys)
+	}
+
+	file := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, ioutil.WriteFile(file, []byte(testKeys), 0600))
+	k, err := encrypt.LoadKeyring(file)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), k.Active())
+}
+
+func TestFile_RandomWrites(t *testing.T) {
+	k := newKeyring(t)
+	base := createFile(t, "data")
+	f, err := k.Create(base)
+	require.NoError(t, err)
+
+	rnd := rand.New(rand.NewSource(1))
+	var expect []byte
+	for i := 0; i < 200; i++ {
+		b := make([]byte, rnd.Intn(3*encrypt.BlockSize))
+		rnd.Read(b)
+		off := int64(0)
+		if len(expect) > 0 {
+			off = rnd.Int63n(int64(len(expect)) + 100)
+		}
+		n, err := f.WriteAt(b, off)
+		require.NoError(t, err)
+		require.Equal(t, len(b), n)
+
+		if end := int(off) + len(b); end > len(expect) {
+			expect = append(expect, make([]byte, end-len(expect))...)
+		}
+		copy(expect[off:], b)
+	}
+	require.Equal(t, int64(len(expect)), f.Size())
+
+	// the plaintext is not stored
+	raw, err := ioutil.ReadFile(base.Name())
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(raw, expect[:64]))
+
+	require.NoError(t, f.Close())
+	base, err = os.Open(base.Name())
+	require.NoError(t, err)
+	f, err = k.Open(base)
+	require.NoError(t, err)
+	defer f.Close()
+
+	fi, err := f.Stat()
+	require.NoError(t, err)
+	require.Equal(t, int64(len(expect)), fi.Size())
+	got, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, expect, got)
+
+	b := make([]byte, 100)
+	n, err := f.ReadAt(b, int64(len(expect)-50))
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, expect[len(expect)-50:], b[:n])
+}
+
+func TestFile_AppendAndTruncate(t *testing.T) {
+	k := newKeyring(t)
+	f, err := k.Create(createFile(t, "wal"))
+	require.NoError(t, err)
+	defer f.Close()
+	f.SetAppend()
+
+	var expect []byte
+	for i := 0; i < 1000; i++ {
+		line := []byte("cpu,host=server01 value=1\n")
+		_, err = f.Write(line)
+		require.NoError(t, err)
+		expect = append(expect, line...)
+	}
+
+	for _, size := range []int64{int64(len(expect)) - 1, 3*encrypt.BlockSize + 10, 2 * encrypt.BlockSize, 5} {
+		require.NoError(t, f.Truncate(size))
+		expect = expect[:size]
+		got := make([]byte, size)
+		_, err = f.ReadAt(got, 0)
+		require.NoError(t, err)
+		require.Equal(t, expect, got)
+	}
+
+	// extending the file fills zeros
+	require.NoError(t, f.Truncate(encrypt.BlockSize+1))
+	got := make([]byte, encrypt.BlockSize+1)
+	_, err = f.ReadAt(got, 0)
+	require.NoError(t, err)
+	require.Equal(t, append(expect, make([]byte, encrypt.BlockSize-4)...), got)
+
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	b := make([]byte, 5)
+	_, err = io.ReadFull(f, b)
+	require.NoError(t, err)
+	require.Equal(t, expect, b)
+}
+
+func TestFile_KeyRotation(t *testing.T) {
+	k := newKeyring(t)
+	require.NoError(t, k.SetActive(1))
+	base := createFile(t, "old")
+	f, err := k.Create(base)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("written by key 1"))
+	require.NoError(t, err)
+
+	// the rotated keyring still reads the old files
+	k = newKeyring(t)
+	f, err = k.Open(base)
+	require.NoError(t, err)
+	b := make([]byte, 16)
+	_, err = f.ReadAt(b, 0)
+	require.NoError(t, err)
+	require.Equal(t, "written by key 1", string(b))
+
+	// but not without the old key
+	k = encrypt.NewKeyring()
+	require.NoError(t, k.AddKey(2, bytes.Repeat([]byte{1}, 32)))
+	_, err = k.Open(base)
+	require.ErrorIs(t, err, encrypt.ErrKeyNotFound)
+}
+
+func TestFile_Tampered(t *testing.T) {
+	k := newKeyring(t)
+	base := createFile(t, "data")
+	f, err := k.Create(base)
+	require.NoError(t, err)
+	_, err = f.Write(bytes.Repeat([]byte("a"), 2*encrypt.BlockSize+100))
+	require.NoError(t, err)
+
+	fi, err := base.Stat()
+	require.NoError(t, err)
+	_, err = base.WriteAt([]byte{0xff}, fi.Size()-100)
+	require.NoError(t, err)
+	f, err = k.Open(base)
+	require.NoError(t, err)
+	_, err = f.ReadAt(make([]byte, 10), encrypt.BlockSize)
+	require.Error(t, err)
+}
+
+// tornFile crashes in the middle of the write of the block or the tail
+type tornFile struct {
+	*os.File
+	writes int
+	garble bool
+}
+
+func (f *tornFile) WriteAt(p []byte, off int64) (int, error) {
+	if f.writes--; f.writes >= 0 {
+		return f.File.WriteAt(p, off)
+	}
+	if f.garble {
+		b := append([]byte{}, p...)
+		for i := len(b) / 2; i < len(b); i++ {
+			b[i] = ^b[i]
+		}
+		_, _ = f.File.WriteAt(b, off)
+	} else {
+		_, _ = f.File.WriteAt(p[:len(p)/2], off)
+	}
+	return 0, io.ErrShortWrite
+}
+
+func TestFile_TornWrite(t *testing.T) {
+	k := newKeyring(t)
+	synced := bytes.Repeat([]byte("synced record\n"), 100)
+
+	check := func(base *tornFile, expect []byte) {
+		require.NoError(t, base.File.Close())
+		of, err := os.OpenFile(base.Name(), os.O_RDWR, 0600)
+		require.NoError(t, err)
+		f, err := k.Open(of)
+		require.NoError(t, err)
+		defer f.Close()
+		require.Equal(t, int64(len(expect)), f.Size())
+		got, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		require.Equal(t, expect, got)
+
+		// the file is appended after the recovered data
+		f.SetAppend()
+		_, err = f.Write([]byte("next"))
+		require.NoError(t, err)
+		got = make([]byte, len(expect)+4)
+		_, err = f.ReadAt(got, 0)
+		require.NoError(t, err)
+		require.Equal(t, append(append([]byte{}, expect...), "next"...), got)
+	}
+
+	for _, garble := range []bool{false, true} {
+		// the tail holding the synced records is not overwritten by the next append
+		base := &tornFile{File: createFile(t, "tail"), writes: 1 << 30, garble: garble}
+		f, err := k.Create(base)
+		require.NoError(t, err)
+		f.SetAppend()
+		_, err = f.Write(synced)
+		require.NoError(t, err)
+		require.NoError(t, f.Sync())
+		base.writes = 0
+		_, err = f.Write([]byte("lost record\n"))
+		require.Error(t, err)
+		check(base, synced)
+
+		// the block completed after the tail was synced is torn
+		base = &tornFile{File: createFile(t, "block"), writes: 1 << 30, garble: garble}
+		f, err = k.Create(base)
+		require.NoError(t, err)
+		f.SetAppend()
+		_, err = f.Write(synced)
+		require.NoError(t, err)
+		require.NoError(t, f.Sync())
+		base.writes = 0
+		_, err = f.Write(bytes.Repeat([]byte("x"), 2*encrypt.BlockSize))
+		require.Error(t, err)
+		check(base, synced)
+
+		// the tail written after a full block is torn, the block is kept
+		base = &tornFile{File: createFile(t, "next"), writes: 1 << 30, garble: garble}
+		f, err = k.Create(base)
+		require.NoError(t, err)
+		f.SetAppend()
+		_, err = f.Write(bytes.Repeat([]byte("y"), encrypt.BlockSize+10))
+		require.NoError(t, err)
+		require.NoError(t, f.Sync())
+		base.writes = 1
+		_, err = f.Write(bytes.Repeat([]byte("z"), encrypt.BlockSize))
+		require.Error(t, err)
+		expect := append(bytes.Repeat([]byte("y"), encrypt.BlockSize+10), bytes.Repeat([]byte("z"), encrypt.BlockSize-10)...)
+		check(base, expect)
+	}
+}
+
+func TestOpen_NotEncrypted(t *testing.T) {
+	k := newKeyring(t)
+	base := createFile(t, "plain")
+	_, err := k.Open(base)
+	require.Equal(t, encrypt.ErrNotEncrypted, err)
+	require.False(t, encrypt.IsEncrypted(base))
+
+	_, err = base.Write(bytes.Repeat([]byte("plain text"), 10))
+	require.NoError(t, err)
+	_, err = k.Open(base)
+	require.Equal(t, encrypt.ErrNotEncrypted, err)
+
+	_, err = k.Create(createFile(t, "enc"))
+	require.NoError(t, err)
+}
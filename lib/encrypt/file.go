@@ -0,0 +1,562 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encrypt
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// The encrypted file starts with a header and two tail slots, followed by the
+// blocks of BlockSize bytes of plaintext. Every block is sealed by AES-GCM with
+// its own random nonce, the file id and the block index are authenticated, so
+// blocks can not be moved within or between files:
+//
+//	header: magic(8) | key id(4) | block size(4) | file id(16)
+//	tail:   block index(8) | sequence(8) | length(4) | nonce(12) | ciphertext(length) | tag(16)
+//	block:  nonce(12) | ciphertext(BlockSize) | tag(16)
+//
+// Only the full blocks are written at their place, the appended blocks are
+// written once. The last partial block is written into a tail slot instead,
+// and after a Sync into the other slot, so that a torn write never destroys the
+// synced tail. The tail with the largest sequence is read when the file is opened.
+const (
+	BlockSize = 4096
+
+	headerSize    = 32
+	fileIDSize    = 16
+	nonceSize     = 12
+	tagSize       = 16
+	blockOverhead = nonceSize + tagSize
+	physBlockSize = BlockSize + blockOverhead
+	tailMetaSize  = 20
+	tailSlotSize  = tailMetaSize + physBlockSize
+	dataOffset    = headerSize + 2*tailSlotSize
+)
+
+var magic = []byte("OGENC\x00\x00\x02")
+
+var errNegativeOffset = errors.New("negative offset")
+
+var physBlockPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, tailSlotSize)
+		return &b
+	},
+}
+
+// BaseFile stores the encrypted data of a File.
+type BaseFile interface {
+	io.ReaderAt
+	io.WriterAt
+	io.Closer
+	Name() string
+	Truncate(size int64) error
+	Stat() (os.FileInfo, error)
+	Sync() error
+}
+
+// File encrypts the data written into the base file and decrypts the data
+// read from it. Offsets and sizes are the ones of the plaintext.
+type File struct {
+	base   BaseFile
+	aead   cipher.AEAD
+	fileID [fileIDSize]byte
+
+	mu     sync.RWMutex
+	size   int64
+	pos    int64
+	append bool
+
+	// plaintext of the last partial block, the block size/BlockSize
+	tail       []byte
+	tailSeq    uint64
+	tailSlot   int // slot of the last written tail, -1 if none
+	syncedSlot int // slot of the tail written before the last Sync, -1 if none
+	// the blocks after the last valid one are left by a torn write
+	trimmed bool
+}
+
+// Create writes the header of a new encrypted file into the empty base file.
+func (k *Keyring) Create(base BaseFile) (*File, error) {
+	aead, err := k.key(k.active)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{base: base, aead: aead, tailSlot: -1, syncedSlot: -1, trimmed: true}
+	if _, err = rand.Read(f.fileID[:]); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	binary.BigEndian.PutUint32(header[8:], k.active)
+	binary.BigEndian.PutUint32(header[12:], BlockSize)
+	copy(header[16:], f.fileID[:])
+	if err = base.Truncate(0); err != nil {
+		return nil, err
+	}
+	if _, err = base.WriteAt(header, 0); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Open reads the header of an encrypted file, it returns ErrNotEncrypted if
+// the base file has no header.
+func (k *Keyring) Open(base BaseFile) (*File, error) {
+	header := make([]byte, headerSize)
+	if n, _ := base.ReadAt(header, 0); n < headerSize || !bytes.Equal(header[:len(magic)], magic) {
+		return nil, ErrNotEncrypted
+	}
+	if bs := binary.BigEndian.Uint32(header[12:]); bs != BlockSize {
+		return nil, fmt.Errorf("unsupported encryption block size %d", bs)
+	}
+	aead, err := k.key(binary.BigEndian.Uint32(header[8:]))
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := base.Stat()
+	if err != nil {
+		return nil, err
+	}
+	f := &File{base: base, aead: aead, tailSlot: -1, syncedSlot: -1}
+	copy(f.fileID[:], header[16:])
+	f.load(fi.Size())
+	return f, nil
+}
+
+// IsEncrypted returns true if r starts with the header of an encrypted file.
+func IsEncrypted(r io.ReaderAt) bool {
+	b := make([]byte, len(magic))
+	n, _ := r.ReadAt(b, 0)
+	return n == len(magic) && bytes.Equal(b, magic)
+}
+
+type tailVersion struct {
+	slot  int
+	idx   int64
+	seq   uint64
+	plain []byte
+}
+
+// load finds the size and the tail of the file. A block written after the
+// tail of its data was synced may be torn, the tail is read instead of it.
+func (f *File) load(physSize int64) {
+	var full int64
+	if n := physSize - dataOffset; n > 0 {
+		full = n / physBlockSize
+	}
+
+	var tails []tailVersion
+	for slot := 0; slot < 2; slot++ {
+		if t, ok := f.readTail(slot); ok {
+			tails = append(tails, t)
+			if t.seq > f.tailSeq {
+				f.tailSeq = t.seq
+			}
+		}
+	}
+	if len(tails) == 2 && tails[0].idx > tails[1].idx {
+		tails[0], tails[1] = tails[1], tails[0]
+	}
+	for _, t := range tails {
+		f.size = full * BlockSize
+		if t.idx < full {
+			if _, err := f.readBlock(t.idx, nil); err != nil {
+				full = t.idx
+			}
+		}
+	}
+
+	f.size = full * BlockSize
+	var tail *tailVersion
+	for i := range tails {
+		if tails[i].idx == full && (tail == nil || tails[i].seq > tail.seq) {
+			tail = &tails[i]
+		}
+	}
+	if tail != nil {
+		f.tail = tail.plain
+		f.size += int64(len(tail.plain))
+		f.tailSlot, f.syncedSlot = tail.slot, tail.slot
+	}
+	f.trimmed = physSize <= dataOffset+full*physBlockSize
+}
+
+func (f *File) readTail(slot int) (tailVersion, bool) {
+	t := tailVersion{slot: slot}
+	buf := physBlockPool.Get().(*[]byte)
+	defer physBlockPool.Put(buf)
+	n, _ := f.base.ReadAt(*buf, tailOffset(slot))
+	if n < tailMetaSize+blockOverhead {
+		return t, false
+	}
+	t.idx = int64(binary.BigEndian.Uint64(*buf))
+	t.seq = binary.BigEndian.Uint64((*buf)[8:])
+	length := int(binary.BigEndian.Uint32((*buf)[16:]))
+	if t.idx < 0 || t.seq == 0 || length >= BlockSize || n < tailMetaSize+length+blockOverhead {
+		return t, false
+	}
+
+	phys := (*buf)[tailMetaSize : tailMetaSize+length+blockOverhead]
+	var err error
+	t.plain, err = f.aead.Open(nil, phys[:nonceSize], phys[nonceSize:], f.tailAdditionalData(t.idx, t.seq))
+	return t, err == nil
+}
+
+func blockOffset(idx int64) int64 {
+	return dataOffset + idx*physBlockSize
+}
+
+func tailOffset(slot int) int64 {
+	return headerSize + int64(slot)*tailSlotSize
+}
+
+// SetAppend makes Write always append at the end of the file, as O_APPEND.
+func (f *File) SetAppend() {
+	f.mu.Lock()
+	f.append = true
+	f.mu.Unlock()
+}
+
+func (f *File) additionalData(idx int64) []byte {
+	ad := make([]byte, fileIDSize+8)
+	copy(ad, f.fileID[:])
+	binary.BigEndian.PutUint64(ad[fileIDSize:], uint64(idx))
+	return ad
+}
+
+func (f *File) tailAdditionalData(idx int64, seq uint64) []byte {
+	ad := make([]byte, fileIDSize+16)
+	copy(ad, f.fileID[:])
+	binary.BigEndian.PutUint64(ad[fileIDSize:], uint64(idx))
+	binary.BigEndian.PutUint64(ad[fileIDSize+8:], seq)
+	return ad
+}
+
+// readBlock appends the plaintext of block idx to dst.
+func (f *File) readBlock(idx int64, dst []byte) ([]byte, error) {
+	if idx == f.size/BlockSize {
+		return append(dst, f.tail...), nil
+	}
+
+	buf := physBlockPool.Get().(*[]byte)
+	defer physBlockPool.Put(buf)
+	phys := (*buf)[:physBlockSize]
+	if _, err := f.base.ReadAt(phys, blockOffset(idx)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	dst, err := f.aead.Open(dst, phys[:nonceSize], phys[nonceSize:], f.additionalData(idx))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt block %d of %s: %v", idx, f.base.Name(), err)
+	}
+	return dst, nil
+}
+
+// writeBlock writes the full block idx at its place.
+func (f *File) writeBlock(idx int64, plain []byte) error {
+	buf := physBlockPool.Get().(*[]byte)
+	defer physBlockPool.Put(buf)
+	phys := (*buf)[:nonceSize]
+	if _, err := rand.Read(phys); err != nil {
+		return err
+	}
+	phys = f.aead.Seal(phys, phys[:nonceSize], plain, f.additionalData(idx))
+	_, err := f.base.WriteAt(phys, blockOffset(idx))
+	return err
+}
+
+// writeTail writes the tail into the slot which doesn't hold the synced tail.
+func (f *File) writeTail() error {
+	slot := 0
+	if f.syncedSlot >= 0 {
+		slot = 1 - f.syncedSlot
+	}
+	idx := f.size / BlockSize
+
+	buf := physBlockPool.Get().(*[]byte)
+	defer physBlockPool.Put(buf)
+	phys := (*buf)[:tailMetaSize+nonceSize]
+	binary.BigEndian.PutUint64(phys, uint64(idx))
+	binary.BigEndian.PutUint64(phys[8:], f.tailSeq+1)
+	binary.BigEndian.PutUint32(phys[16:], uint32(len(f.tail)))
+	nonce := phys[tailMetaSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	phys = f.aead.Seal(phys, nonce, f.tail, f.tailAdditionalData(idx, f.tailSeq+1))
+	if _, err := f.base.WriteAt(phys, tailOffset(slot)); err != nil {
+		return err
+	}
+	f.tailSeq++
+	f.tailSlot = slot
+	return nil
+}
+
+// trim removes the torn blocks after the last valid block before the file is changed.
+func (f *File) trim() error {
+	if f.trimmed {
+		return nil
+	}
+	if err := f.base.Truncate(blockOffset(f.size / BlockSize)); err != nil {
+		return err
+	}
+	f.trimmed = true
+	return nil
+}
+
+func (f *File) readAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errNegativeOffset
+	}
+	n := 0
+	block := make([]byte, 0, BlockSize)
+	for n < len(p) && off < f.size {
+		idx := off / BlockSize
+		var err error
+		block, err = f.readBlock(idx, block[:0])
+		if err != nil {
+			return n, err
+		}
+		c := copy(p[n:], block[off-idx*BlockSize:])
+		n += c
+		off += int64(c)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *File) writeAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errNegativeOffset
+	}
+	if err := f.trim(); err != nil {
+		return 0, err
+	}
+	if off > f.size {
+		if err := f.fillZero(off); err != nil {
+			return 0, err
+		}
+	}
+
+	n := 0
+	tailChanged := false
+	block := make([]byte, 0, BlockSize)
+	for n < len(p) {
+		idx := off / BlockSize
+		start := int(off - idx*BlockSize)
+		c := BlockSize - start
+		if c > len(p)-n {
+			c = len(p) - n
+		}
+
+		if idx < f.size/BlockSize {
+			// a full block is overwritten in place
+			block = block[:0]
+			if c < BlockSize {
+				var err error
+				if block, err = f.readBlock(idx, block); err != nil {
+					return n, err
+				}
+			}
+			block = block[:BlockSize]
+			copy(block[start:], p[n:n+c])
+			if err := f.writeBlock(idx, block); err != nil {
+				return n, err
+			}
+		} else {
+			if len(f.tail) < start+c {
+				f.tail = append(f.tail, make([]byte, start+c-len(f.tail))...)
+				f.size = idx*BlockSize + int64(len(f.tail))
+			}
+			copy(f.tail[start:], p[n:n+c])
+			tailChanged = true
+			if len(f.tail) == BlockSize {
+				if err := f.writeBlock(idx, f.tail); err != nil {
+					return n, err
+				}
+				f.tail = f.tail[:0]
+			}
+		}
+		n += c
+		off += int64(c)
+	}
+	if tailChanged {
+		if err := f.writeTail(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (f *File) fillZero(end int64) error {
+	zero := make([]byte, BlockSize)
+	for f.size < end {
+		n := end - f.size
+		if n > BlockSize {
+			n = BlockSize
+		}
+		if _, err := f.writeAt(zero[:n], f.size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.readAt(p, off)
+}
+
+func (f *File) WriteAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.writeAt(p, off)
+}
+
+func (f *File) Read(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pos >= f.size && len(p) > 0 {
+		return 0, io.EOF
+	}
+	n, err := f.readAt(p, f.pos)
+	f.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.append {
+		f.pos = f.size
+	}
+	n, err := f.writeAt(p, f.pos)
+	f.pos += int64(n)
+	return n, err
+}
+
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errNegativeOffset
+	}
+	f.pos = offset
+	return offset, nil
+}
+
+func (f *File) Truncate(size int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if size < 0 {
+		return errNegativeOffset
+	}
+	if size >= f.size {
+		return f.fillZero(size)
+	}
+	if err := f.trim(); err != nil {
+		return err
+	}
+
+	idx := size / BlockSize
+	var keep []byte
+	if rem := size - idx*BlockSize; rem > 0 {
+		block, err := f.readBlock(idx, nil)
+		if err != nil {
+			return err
+		}
+		keep = block[:rem]
+	}
+	if err := f.base.Truncate(blockOffset(idx)); err != nil {
+		return err
+	}
+	f.size = size
+	f.tail = append(f.tail[:0], keep...)
+	return f.writeTail()
+}
+
+// Size returns the plaintext size.
+func (f *File) Size() int64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.size
+}
+
+func (f *File) Stat() (os.FileInfo, error) {
+	fi, err := f.base.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return fileInfo{FileInfo: fi, size: f.Size()}, nil
+}
+
+func (f *File) Name() string {
+	return f.base.Name()
+}
+
+// Sync makes the written data durable, the tail written later goes to the other slot.
+func (f *File) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.base.Sync(); err != nil {
+		return err
+	}
+	f.syncedSlot = f.tailSlot
+	return nil
+}
+
+func (f *File) Close() error {
+	return f.base.Close()
+}
+
+// fileInfo reports the plaintext size of an encrypted file.
+type fileInfo struct {
+	os.FileInfo
+	size int64
+}
+
+func (fi fileInfo) Size() int64 {
+	return fi.size
+}
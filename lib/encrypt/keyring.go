@@ -0,0 +1,161 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encrypt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNotEncrypted = errors.New("file is not encrypted")
+	ErrKeyNotFound  = errors.New("encryption key not found")
+)
+
+// Keyring holds the data encryption keys by id. New files are encrypted by
+// the active key, the other keys are kept to read the files written before a
+// key rotation.
+type Keyring struct {
+	keys   map[uint32]cipher.AEAD
+	active uint32
+}
+
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[uint32]cipher.AEAD)}
+}
+
+// AddKey adds an AES-128, AES-192 or AES-256 key. The key with the highest id
+// becomes the active key.
+func (k *Keyring) AddKey(id uint32, key []byte) error {
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("duplicate encryption key %d", id)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("encryption key %d: %v", id, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	k.keys[id] = aead
+	if len(k.keys) == 1 || id > k.active {
+		k.active = id
+	}
+	return nil
+}
+
+// SetActive selects the key used to encrypt the new files.
+func (k *Keyring) SetActive(id uint32) error {
+	if _, ok := k.keys[id]; !ok {
+		return ErrKeyNotFound
+	}
+	k.active = id
+	return nil
+}
+
+func (k *Keyring) Active() uint32 {
+	return k.active
+}
+
+func (k *Keyring) key(id uint32) (cipher.AEAD, error) {
+	aead, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrKeyNotFound, id)
+	}
+	return aead, nil
+}
+
+// ParseKeyring parses a key file. Each line holds a key id and the hex encoded
+// key, empty lines and lines starting with '#' are ignored:
+//
+//	# id key
+//	1 6368616e676520746869732070617373776f726420746f206120736563726574
+//
+// A key is rotated by appending a line with a higher id.
+func ParseKeyring(data []byte) (*Keyring, error) {
+	k := NewKeyring()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		items := strings.Fields(text)
+		if len(items) != 2 {
+			return nil, fmt.Errorf("key file line %d: expected \"<id> <hex key>\"", line)
+		}
+		id, err := strconv.ParseUint(items[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("key file line %d: invalid key id %q", line, items[0])
+		}
+		key, err := hex.DecodeString(items[1])
+		if err != nil {
+			return nil, fmt.Errorf("key file line %d: invalid hex key", line)
+		}
+		if err = k.AddKey(uint32(id), key); err != nil {
+			return nil, fmt.Errorf("key file line %d: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(k.keys) == 0 {
+		return nil, errors.New("key file has no key")
+	}
+	return k, nil
+}
+
+// LoadKeyring reads the key file, see ParseKeyring for the format.
+func LoadKeyring(file string) (*Keyring, error) {
+	data, err := ioutil.ReadFile(path.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyring(data)
+}
+
+var defaultKeyring struct {
+	mu      sync.RWMutex
+	keyring *Keyring
+}
+
+// SetDefaultKeyring sets the keyring of the process, nil disables the
+// encryption of the new files.
+func SetDefaultKeyring(k *Keyring) {
+	defaultKeyring.mu.Lock()
+	defaultKeyring.keyring = k
+	defaultKeyring.mu.Unlock()
+}
+
+// DefaultKeyring returns the keyring of the process, nil if the encryption is
+// disabled.
+func DefaultKeyring() *Keyring {
+	defaultKeyring.mu.RLock()
+	defer defaultKeyring.mu.RUnlock()
+	return defaultKeyring.keyring
+}
@@ -71,6 +71,7 @@ const (
 	BadListen             = 1023
 	FailedConvertToCodec  = 1024
 	OpenSessionTimeout    = 1025
+	UnknownCompression    = 1026
 	RemoteError           = 1206
 	DataACKTimeout        = 1027
 	InvalidTLSConfig      = 1208
@@ -93,6 +94,7 @@ const (
 	BucketLacks                  = 1113
 	CreatePipelineExecutorFail   = 1114
 	LogicalPlainBuildFailInShard = 1115
+	InvalidSketch                = 1116
 )
 
 // store engine error codes
@@ -124,6 +126,8 @@ const (
 	WalRecordHeaderCorrupted           = 2125
 	WalRecordUnmarshalFailed           = 2126
 	CompactPanicFail                   = 2127
+	WalRecordChecksumMismatch          = 2128
+	WalFileTruncated                   = 2129
 )
 
 // merge out of order
@@ -161,6 +165,8 @@ const (
 	EventNotFound            = 4017
 	PtChanged                = 4018
 	OpIdIsInvalid            = 4019
+	DataNodeNotDrained       = 4020
+	NoAvailableDataNode      = 4021
 )
 
 // meta-client process
@@ -192,6 +198,8 @@ const (
 	WritePointOutOfRP          = 5013
 	WritePointShardKeyTooLarge = 5014
 	EngineClosed               = 5015
+	SeriesLimitExceeded        = 5016
+	TagValuesLimitExceeded     = 5017
 )
 
 // index
@@ -75,6 +75,9 @@ var messageMap = map[Errno]*Message{
 	DuplicateField:     newWarnMessage("duplicate field: %s", ModuleWrite),
 	EngineClosed:       newWarnMessage("engine is closed", ModuleWrite),
 
+	SeriesLimitExceeded:    newWarnMessage("max series limit exceeded: db=%s, measurement=%s, %d/%d", ModuleWrite),
+	TagValuesLimitExceeded: newWarnMessage("max values per tag limit exceeded: db=%s, measurement=%s, tag=%s, %d/%d", ModuleWrite),
+
 	// network module error codes
 	NoConnectionAvailable: newFatalMessage("no connections available, node: %v, %v", ModuleNetwork),
 	NoNodeAvailable:       newFatalMessage("no node available, node: %v", ModuleNetwork),
@@ -100,6 +103,7 @@ var messageMap = map[Errno]*Message{
 	BadListen:             newNoticeMessage("bad practice to listen on %s", ModuleNetwork),
 	FailedConvertToCodec:  newWarnMessage("failed to convert to Codec, give type: %s", ModuleNetwork),
 	OpenSessionTimeout:    newWarnMessage("failed to open session: timeout", ModuleNetwork),
+	UnknownCompression:    newWarnMessage("unknown compression algorithm: %v", ModuleNetwork),
 	SessionSelectTimeout:  newWarnMessage("select timeout in %d seconds", ModuleNetwork),
 	RemoteError:           newWarnMessage("remote error: %v", ModuleNetwork),
 	DataACKTimeout:        newWarnMessage("wait data ack signal timeout", ModuleNetwork),
@@ -119,6 +123,7 @@ var messageMap = map[Errno]*Message{
 	UnsupportedDataType:        newWarnMessage("unsupported (%s) iterator type: (%s)", ModuleQueryEngine),
 	LogicalPlanBuildFail:       newWarnMessage("logical plan build failed: %s", ModuleQueryEngine),
 	CreatePipelineExecutorFail: newWarnMessage("create pipeline executor raise panic: %s", ModuleQueryEngine),
+	InvalidSketch:              newWarnMessage("invalid serialized %s sketch", ModuleQueryEngine),
 
 	// store engine error codes
 	CreateIndexFailPointRowType:        newFatalMessage("create index failed due to rows are not belong to type PointRow", ModuleIndex),
@@ -146,10 +151,12 @@ var messageMap = map[Errno]*Message{
 	CompactPanicFail:                   newFatalMessage("compact fail", ModuleTssp),
 
 	// wal error codes
-	ReadWalFileFailed:         newWarnMessage("read wal file failed", ModuleWal),
-	DecompressWalRecordFailed: newWarnMessage("decompress wal record failed", ModuleWal),
-	WalRecordHeaderCorrupted:  newWarnMessage("wal record header is corrupt", ModuleWal),
-	WalRecordUnmarshalFailed:  newWarnMessage("wal record unmarshal failed", ModuleWal),
+	ReadWalFileFailed:         newWarnMessage("read wal file failed, file: %s, offset: %d, %s", ModuleWal),
+	DecompressWalRecordFailed: newWarnMessage("decompress wal record failed, file: %s, offset: %d, %s", ModuleWal),
+	WalRecordHeaderCorrupted:  newWarnMessage("wal record header is corrupt, file: %s, offset: %d", ModuleWal),
+	WalRecordUnmarshalFailed:  newWarnMessage("wal record unmarshal failed, %s, shard: %d, %v", ModuleWal),
+	WalRecordChecksumMismatch: newWarnMessage("wal record checksum mismatch, file: %s, offset: %d, exp: %d, got: %d", ModuleWal),
+	WalFileTruncated:          newWarnMessage("wal file is truncated, file: %s, offset: %d, size: %d", ModuleWal),
 
 	// merge out of order
 	SeriesIdIsZero:     newFatalMessage("invalid record, series id is 0. file: %s", ModuleMerge),
@@ -182,6 +189,8 @@ var messageMap = map[Errno]*Message{
 	EventNotFound:            newWarnMessage("event is not found", ModuleHA),
 	PtChanged:                newWarnMessage("pt is changed", ModuleHA),
 	OpIdIsInvalid:            newWarnMessage("event op id is invalid", ModuleHA),
+	DataNodeNotDrained:       newWarnMessage("dataNode(id=%d) still owns %d pts, decommission it first", ModuleHA),
+	NoAvailableDataNode:      newWarnMessage("no alive dataNode is available", ModuleHA),
 
 	// http error codes
 	HttpUnauthorized:          newWarnMessage("authorization failed", ModuleHTTP),
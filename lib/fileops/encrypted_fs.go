@@ -0,0 +1,147 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fileops
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/openGemini/openGemini/lib/encrypt"
+	"github.com/openGemini/openGemini/lib/util"
+)
+
+// encryptedFile is a file encrypted by the default keyring of the process.
+type encryptedFile struct {
+	*encrypt.File
+	of *file
+}
+
+func (f *encryptedFile) SyncUpdateLength() error {
+	return f.Sync()
+}
+
+func (f *encryptedFile) Fd() uintptr {
+	return f.of.Fd()
+}
+
+// IsEncrypted returns true if the file content is encrypted, such a file can
+// not be mapped into the memory.
+func IsEncrypted(f File) bool {
+	_, ok := f.(*encryptedFile)
+	return ok
+}
+
+// openEncrypted opens a file when the keyring is configured. The new files are
+// encrypted, the existing files are opened as they were written, so the files
+// written before the encryption was enabled stay readable.
+func openEncrypted(keyring *encrypt.Keyring, name string, flag int, perm os.FileMode) (File, error) {
+	name = path.Clean(name)
+	writable := flag&(os.O_WRONLY|os.O_RDWR) != 0
+	encFlag := flag
+	if writable {
+		// the tail and the blocks are read before they are rewritten
+		encFlag = flag&^(os.O_WRONLY|os.O_APPEND) | os.O_RDWR
+	}
+
+	of, err := os.OpenFile(name, encFlag, perm) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	f := &file{of: of}
+	fi, err := of.Stat()
+	if err != nil {
+		util.MustClose(f)
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return f, nil
+	}
+
+	var ef *encrypt.File
+	if fi.Size() == 0 && writable {
+		ef, err = keyring.Create(f)
+	} else {
+		ef, err = keyring.Open(f)
+	}
+	if err == encrypt.ErrNotEncrypted {
+		if encFlag == flag {
+			return f, nil
+		}
+		util.MustClose(f)
+		of, err = os.OpenFile(name, flag, perm) // #nosec
+		if err != nil {
+			return nil, err
+		}
+		return &file{of: of}, nil
+	}
+	if err != nil {
+		util.MustClose(f)
+		return nil, err
+	}
+
+	if flag&os.O_APPEND != 0 {
+		ef.SetAppend()
+	}
+	return &encryptedFile{File: ef, of: f}, nil
+}
+
+func statEncrypted(keyring *encrypt.Keyring, name string) (os.FileInfo, error) {
+	fi, err := os.Stat(name)
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() == 0 {
+		return fi, err
+	}
+	f, err := openEncrypted(keyring, name, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer util.MustClose(f)
+	return f.Stat()
+}
+
+func readEncrypted(keyring *encrypt.Keyring, name string) ([]byte, error) {
+	f, err := openEncrypted(keyring, name, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer util.MustClose(f)
+	return ioutil.ReadAll(f)
+}
+
+func writeEncrypted(keyring *encrypt.Keyring, name string, data []byte, perm os.FileMode) error {
+	f, err := openEncrypted(keyring, name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+func truncateEncrypted(keyring *encrypt.Keyring, name string, size int64) error {
+	f, err := openEncrypted(keyring, name, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	err = f.Truncate(size)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
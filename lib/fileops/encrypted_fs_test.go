@@ -0,0 +1,96 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fileops
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/encrypt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedFS(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.wal")
+	require.NoError(t, WriteFile(plain, []byte("written before encryption\n"), 0640))
+
+	keyring, err := encrypt.ParseKeyring([]byte("1 000102030405060708090a0b0c0d0e0f"))
+	require.NoError(t, err)
+	encrypt.SetDefaultKeyring(keyring)
+	defer encrypt.SetDefaultKeyring(nil)
+
+	// the plaintext files stay readable and appendable
+	f, err := OpenFile(plain, os.O_WRONLY|os.O_APPEND, 0640)
+	require.NoError(t, err)
+	require.False(t, IsEncrypted(f))
+	_, err = f.Write([]byte("appended\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	data, err := ReadFile(plain)
+	require.NoError(t, err)
+	require.Equal(t, "written before encryption\nappended\n", string(data))
+
+	content := strings.Repeat("cpu,host=server01 value=1\n", 1000)
+	name := filepath.Join(dir, "00000001-0000-00000000.tssp")
+	f, err = Create(name)
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(f))
+	_, err = f.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	raw, err := ioutil.ReadFile(name)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "server01")
+
+	fi, err := Stat(name)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(content)), fi.Size())
+
+	f, err = Open(name)
+	require.NoError(t, err)
+	b := make([]byte, 9)
+	_, err = f.ReadAt(b, 26*500+4)
+	require.NoError(t, err)
+	require.Equal(t, "host=serv", string(b))
+	require.NoError(t, f.Close())
+
+	require.NoError(t, Truncate(name, 26))
+	data, err = ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, content[:26], string(data))
+
+	copied := filepath.Join(dir, "copied.tssp")
+	_, err = CopyFile(name, copied)
+	require.NoError(t, err)
+	raw, err = ioutil.ReadFile(copied)
+	require.NoError(t, err)
+	require.True(t, encrypt.IsEncrypted(strings.NewReader(string(raw))))
+	data, err = ReadFile(copied)
+	require.NoError(t, err)
+	require.Equal(t, content[:26], string(data))
+
+	// directories are opened as usual
+	f, err = Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, f.Sync())
+	require.NoError(t, f.Close())
+}
@@ -29,6 +29,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/openGemini/openGemini/lib/encrypt"
 	"github.com/openGemini/openGemini/lib/logger"
 	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 	"github.com/openGemini/openGemini/lib/sysinfo"
@@ -57,6 +58,15 @@ func (f *file) Write(b []byte) (int, error) {
 	return res, err
 }
 
+func (f *file) WriteAt(b []byte, off int64) (int, error) {
+	begin := time.Now()
+	atomic.AddInt64(&statistics.IOStat.IOWriteTotalCount, 1)
+	atomic.AddInt64(&statistics.IOStat.IOWriteTotalBytes, int64(len(b)))
+	res, err := f.of.WriteAt(b, off)
+	opsStatEnd(begin.UnixNano(), opsTypeWrite, int64(res))
+	return res, err
+}
+
 func (f *file) ReadAt(b []byte, off int64) (int, error) {
 	begin := time.Now()
 	atomic.AddInt64(&statistics.IOStat.IOReadTotalCount, 1)
@@ -124,11 +134,17 @@ func NewFS() VFS {
 }
 
 func (vfs) Open(name string, _ ...FSOption) (File, error) {
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		return openEncrypted(keyring, name, os.O_RDONLY, 0)
+	}
 	f, err := os.Open(path.Clean(name))
 	return &file{of: f}, err
 }
 
 func (vfs) OpenFile(name string, flag int, perm os.FileMode, _ ...FSOption) (File, error) {
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		return openEncrypted(keyring, name, flag, perm)
+	}
 	fd, err := os.OpenFile(path.Clean(name), flag, perm) // #nosec
 	if err != nil {
 		return nil, err
@@ -138,6 +154,9 @@ func (vfs) OpenFile(name string, flag int, perm os.FileMode, _ ...FSOption) (Fil
 }
 
 func (vfs) Create(name string, _ ...FSOption) (File, error) {
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		return openEncrypted(keyring, name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	}
 	f, err := os.Create(path.Clean(name))
 	return &file{of: f}, err
 }
@@ -172,14 +191,23 @@ func (vfs) RenameFile(oldPath, newPath string, _ ...FSOption) error {
 }
 
 func (vfs) Stat(name string) (os.FileInfo, error) {
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		return statEncrypted(keyring, name)
+	}
 	return os.Stat(name)
 }
 
 func (vfs) WriteFile(filename string, data []byte, perm os.FileMode, _ ...FSOption) error {
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		return writeEncrypted(keyring, filename, data, perm)
+	}
 	return ioutil.WriteFile(filename, data, perm)
 }
 
 func (vfs) ReadFile(filename string, _ ...FSOption) ([]byte, error) {
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		return readEncrypted(keyring, filename)
+	}
 	return ioutil.ReadFile(path.Clean(filename))
 }
 
@@ -188,6 +216,9 @@ func (vfs) CreateTime(name string) (*time.Time, error) {
 }
 
 func (vfs) Truncate(name string, size int64, _ ...FSOption) error {
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		return truncateEncrypted(keyring, name, size)
+	}
 	return os.Truncate(name, size)
 }
 
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+const datatypeAnnotation = "#datatype"
+
+// csvDecoder decodes one record per line. The columns are typed by a #datatype annotation
+// before the header, the same as the csv written by the influx 2.x client:
+//
+//	#datatype measurement,tag,double,dateTime:RFC3339
+//	m,host,usage,time
+//	cpu,server01,2.5,2020-01-01T00:00:00Z
+//
+// or by the mapping. The other lines starting with # are comments.
+type csvDecoder struct {
+	lr      *lineReader
+	opt     Options
+	columns []column
+}
+
+func newCSVDecoder(lr *lineReader, opt Options) (*csvDecoder, error) {
+	d := &csvDecoder{lr: lr, opt: opt}
+
+	var datatypes []string
+	var header []string
+	for header == nil {
+		s, _, err := lr.next()
+		if err == io.EOF {
+			return nil, ErrMissingHeader
+		} else if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(s, datatypeAnnotation) {
+			datatypes, err = parseCSVLine(strings.TrimLeft(s[len(datatypeAnnotation):], " \t"))
+		} else if !strings.HasPrefix(s, "#") {
+			header, err = parseCSVLine(s)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.initColumns(header, datatypes); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *csvDecoder) initColumns(header, datatypes []string) error {
+	if datatypes == nil && d.opt.Mapping == nil {
+		return ErrMissingColumns
+	}
+	if datatypes != nil && len(datatypes) != len(header) {
+		return fmt.Errorf("#datatype has %d columns but the header has %d", len(datatypes), len(header))
+	}
+
+	hasMeasurement := d.opt.Mapping != nil && d.opt.Mapping.Measurement != ""
+	d.columns = make([]column, len(header))
+	for i, name := range header {
+		if datatypes != nil {
+			c, err := datatypeColumn(name, datatypes[i])
+			if err != nil {
+				return fmt.Errorf("column %q: %v", name, err)
+			}
+			d.columns[i] = c
+		} else {
+			d.columns[i] = d.opt.Mapping.column(name)
+		}
+
+		if d.columns[i].kind == columnMeasurement {
+			hasMeasurement = true
+		}
+	}
+	if !hasMeasurement {
+		return ErrMissingMeasurement
+	}
+	return nil
+}
+
+func (d *csvDecoder) Decode(row *influx.Row) (int, error) {
+	s, line, err := d.lr.next()
+	for err == nil && strings.HasPrefix(s, "#") {
+		s, line, err = d.lr.next()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	resetRow(row)
+	record, err := parseCSVLine(s)
+	if err != nil {
+		return line, &LineError{Line: line, Err: err}
+	}
+	if len(record) != len(d.columns) {
+		return line, &LineError{Line: line, Err: fmt.Errorf("expect %d columns, got %d", len(d.columns), len(record))}
+	}
+	for i := range record {
+		if err := setValue(row, &d.columns[i], record[i], &d.opt); err != nil {
+			return line, &LineError{Line: line, Err: err}
+		}
+	}
+
+	var measurement string
+	if d.opt.Mapping != nil {
+		measurement = d.opt.Mapping.Measurement
+	}
+	if err := finishRow(row, measurement, &d.opt); err != nil {
+		return line, &LineError{Line: line, Err: err}
+	}
+	return line, nil
+}
+
+func parseCSVLine(s string) ([]string, error) {
+	r := csv.NewReader(strings.NewReader(s))
+	r.FieldsPerRecord = -1
+	return r.Read()
+}
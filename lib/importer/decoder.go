@@ -0,0 +1,154 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var (
+	ErrUnknownFormat      = errors.New("unknown import format, expect csv or json")
+	ErrMissingMeasurement = errors.New("no measurement column and no measurement in the mapping")
+	ErrMissingColumns     = errors.New("csv needs a #datatype annotation or a mapping of its columns")
+	ErrMissingHeader      = errors.New("csv has no header")
+)
+
+// LineError is a line that can not be converted. The decoding goes on with the next line.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+type Options struct {
+	Format  string
+	Mapping *Mapping
+	// TsMultiplier converts the numeric timestamps to nanoseconds
+	TsMultiplier int64
+	// LineOffset is added to the line numbers, it is the number of lines sent before
+	LineOffset int
+	// Now is the timestamp of the rows without time
+	Now int64
+}
+
+// Decoder converts the lines of the imported data into rows.
+type Decoder interface {
+	// Decode converts the next line into row and returns the line number. It returns io.EOF after
+	// the last line and a *LineError when the line can not be converted.
+	Decode(row *influx.Row) (int, error)
+}
+
+// NewDecoder returns the decoder of the format, the header of a csv is read at once.
+func NewDecoder(r io.Reader, opt Options) (Decoder, error) {
+	if opt.TsMultiplier <= 0 {
+		opt.TsMultiplier = 1
+	}
+	lr := &lineReader{r: bufio.NewReaderSize(r, 64*1024), offset: opt.LineOffset}
+	switch opt.Format {
+	case FormatCSV:
+		return newCSVDecoder(lr, opt)
+	case FormatJSON:
+		return newJSONDecoder(lr, opt), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// lineReader returns the non-empty lines and their numbers
+type lineReader struct {
+	r      *bufio.Reader
+	line   int
+	offset int
+}
+
+func (lr *lineReader) next() (string, int, error) {
+	for {
+		s, err := lr.r.ReadString('\n')
+		if err != nil && (err != io.EOF || s == "") {
+			return "", 0, err
+		}
+		lr.line++
+		s = strings.TrimRight(s, "\r\n")
+		if strings.TrimSpace(s) != "" {
+			return s, lr.line + lr.offset, nil
+		}
+	}
+}
+
+// resetRow keeps the memory of the tags and fields of a reused row
+func resetRow(row *influx.Row) {
+	row.Name = ""
+	row.Tags = row.Tags[:0]
+	row.Fields = row.Fields[:0]
+	row.ShardKey = row.ShardKey[:0]
+	row.Timestamp = influx.NoTimestamp
+	row.IndexKey = nil
+	row.SeriesId = 0
+	row.IndexOptions = nil
+}
+
+// setValue sets the value of the column to the row, empty values are skipped
+func setValue(row *influx.Row, c *column, v string, opt *Options) error {
+	if v == "" {
+		return nil
+	}
+	switch c.kind {
+	case columnMeasurement:
+		row.Name = v
+	case columnTag:
+		row.Tags = append(row.Tags, influx.Tag{Key: c.name, Value: v})
+	case columnTime:
+		ts, err := parseTime(v, c.timeLayout, opt.TsMultiplier)
+		if err != nil {
+			return err
+		}
+		row.Timestamp = ts
+	case columnField:
+		f, err := parseField(c.name, v, c.fieldType)
+		if err != nil {
+			return err
+		}
+		row.Fields = append(row.Fields, f)
+	}
+	return nil
+}
+
+// finishRow fills the defaults of the row and checks it the way the line protocol does
+func finishRow(row *influx.Row, measurement string, opt *Options) error {
+	if row.Name == "" {
+		row.Name = measurement
+	}
+	if row.Timestamp == influx.NoTimestamp {
+		row.Timestamp = opt.Now
+	}
+	sort.Sort(&row.Tags)
+	return row.CheckValid()
+}
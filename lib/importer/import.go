@@ -0,0 +1,106 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"io"
+
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+const (
+	DefaultBatchSize = 5000
+
+	// MaxReportedErrors limits the line errors kept in a result, the others are only counted
+	MaxReportedErrors = 1000
+)
+
+type ReportedError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// Result is the progress of an import.
+type Result struct {
+	// Lines is the number of lines decoded
+	Lines    int `json:"lines"`
+	Written  int `json:"written"`
+	Rejected int `json:"rejected"`
+	// Committed is the last line such that it and every line before it is either written or rejected,
+	// an interrupted import resumes after it
+	Committed int             `json:"committed"`
+	Errors    []ReportedError `json:"errors,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func (r *Result) reject(e *LineError) {
+	r.Rejected++
+	if len(r.Errors) < MaxReportedErrors {
+		r.Errors = append(r.Errors, ReportedError{Line: e.Line, Error: e.Err.Error()})
+	}
+}
+
+// Import decodes every line and writes the rows in batches of batchSize. The lines which can
+// not be converted are reported in the result, the import stops at the first failed write.
+func Import(dec Decoder, batchSize int, write func([]influx.Row) error) (*Result, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	res := &Result{}
+	rows := make([]influx.Row, batchSize)
+	n, last := 0, 0
+
+	flush := func() error {
+		if n > 0 {
+			if err := write(rows[:n]); err != nil {
+				return err
+			}
+			res.Written += n
+			n = 0
+		}
+		res.Committed = last
+		return nil
+	}
+
+	for {
+		line, err := dec.Decode(&rows[n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			e, ok := err.(*LineError)
+			if !ok {
+				if ferr := flush(); ferr != nil {
+					return res, ferr
+				}
+				return res, err
+			}
+			res.reject(e)
+		} else {
+			n++
+		}
+		res.Lines++
+		last = line
+
+		if n == batchSize {
+			if err := flush(); err != nil {
+				return res, err
+			}
+		}
+	}
+	return res, flush()
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/importer"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNow = int64(1e18)
+
+// decodeAll copies the rows, the memory of a batch is reused by the next one
+func decodeAll(t *testing.T, data string, opt importer.Options) []influx.Row {
+	opt.Now = testNow
+	dec, err := importer.NewDecoder(strings.NewReader(data), opt)
+	require.NoError(t, err)
+
+	var rows []influx.Row
+	_, err = importer.Import(dec, 1, func(batch []influx.Row) error {
+		for i := range batch {
+			r := batch[i]
+			r.Tags = append(influx.PointTags{}, r.Tags...)
+			r.Fields = append(influx.Fields{}, r.Fields...)
+			rows = append(rows, r)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return rows
+}
+
+func TestCSVDecoder_Datatype(t *testing.T) {
+	data := `#datatype measurement,tag,tag,double,long,boolean,string,ignored,dateTime:RFC3339
+m,host,region,usage,count,up,note,skip,time
+cpu,server01,east,2.5,3,true,ok,x,2020-01-01T00:00:00Z
+# comment
+cpu,server02,,1,,false,,,2020-01-01T00:00:01Z
+`
+	rows := decodeAll(t, data, importer.Options{Format: importer.FormatCSV})
+	require.Equal(t, 2, len(rows))
+
+	r := rows[0]
+	assert.Equal(t, "cpu", r.Name)
+	assert.Equal(t, influx.PointTags{{Key: "host", Value: "server01"}, {Key: "region", Value: "east"}}, r.Tags)
+	assert.Equal(t, influx.Fields{
+		{Key: "usage", NumValue: 2.5, Type: influx.Field_Type_Float},
+		{Key: "count", NumValue: 3, Type: influx.Field_Type_Int},
+		{Key: "up", NumValue: 1, Type: influx.Field_Type_Boolean},
+		{Key: "note", StrValue: "ok", Type: influx.Field_Type_String},
+	}, r.Fields)
+	assert.Equal(t, int64(1577836800000000000), r.Timestamp)
+
+	r = rows[1]
+	assert.Equal(t, influx.PointTags{{Key: "host", Value: "server02"}}, r.Tags)
+	assert.Equal(t, 2, len(r.Fields))
+	assert.Equal(t, int64(1577836801000000000), r.Timestamp)
+}
+
+func TestCSVDecoder_Mapping(t *testing.T) {
+	m, err := importer.ParseMapping([]byte(`{"measurement":"cpu","time_column":"ts","tags":["host"],"fields":{"usage":"float","n":"integer"}}`))
+	require.NoError(t, err)
+
+	data := "host,usage,n,other,ts\nserver01,2.5,3,x,1577836800\nserver02,bad,1,y,1577836801\nserver03,1,1,z\nserver04,1,,,\n"
+	opt := importer.Options{Format: importer.FormatCSV, Mapping: m, TsMultiplier: 1e9, Now: testNow}
+	dec, err := importer.NewDecoder(strings.NewReader(data), opt)
+	require.NoError(t, err)
+
+	var rows []influx.Row
+	res, err := importer.Import(dec, 10, func(batch []influx.Row) error {
+		rows = append(rows, batch...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, res.Lines)
+	assert.Equal(t, 2, res.Written)
+	assert.Equal(t, 2, res.Rejected)
+	assert.Equal(t, 5, res.Committed)
+	assert.Equal(t, []int{3, 4}, []int{res.Errors[0].Line, res.Errors[1].Line})
+
+	require.Equal(t, 2, len(rows))
+	assert.Equal(t, "cpu", rows[0].Name)
+	assert.Equal(t, int64(1577836800000000000), rows[0].Timestamp)
+	assert.Equal(t, 2, len(rows[0].Fields))
+	assert.Equal(t, testNow, rows[1].Timestamp)
+}
+
+func TestCSVDecoder_Errors(t *testing.T) {
+	_, err := importer.NewDecoder(strings.NewReader("a,b\n1,2\n"), importer.Options{Format: importer.FormatCSV})
+	assert.Equal(t, importer.ErrMissingColumns, err)
+
+	_, err = importer.NewDecoder(strings.NewReader("#datatype tag,double\na,b\n"), importer.Options{Format: importer.FormatCSV})
+	assert.Equal(t, importer.ErrMissingMeasurement, err)
+
+	_, err = importer.NewDecoder(strings.NewReader("#datatype measurement,uint\na,b\n"), importer.Options{Format: importer.FormatCSV})
+	assert.Error(t, err)
+
+	_, err = importer.NewDecoder(strings.NewReader("#datatype measurement\n"), importer.Options{Format: importer.FormatCSV})
+	assert.Equal(t, importer.ErrMissingHeader, err)
+
+	_, err = importer.NewDecoder(strings.NewReader(""), importer.Options{Format: "xml"})
+	assert.Equal(t, importer.ErrUnknownFormat, err)
+
+	_, err = importer.ParseMapping([]byte(`{"fields":{"a":"uint"}}`))
+	assert.Error(t, err)
+	_, err = importer.ParseMapping([]byte(`{"field":{}}`))
+	assert.Error(t, err)
+}
+
+func TestJSONDecoder(t *testing.T) {
+	data := `{"measurement":"cpu","tags":{"host":"a"},"fields":{"usage":2.5,"up":true,"note":"x","n":3},"time":1577836800}
+{"measurement":"cpu","fields":{"usage":1},"time":"2020-01-01T00:00:00Z"}
+{"measurement":"cpu","fields":{}}
+not json
+`
+	m, err := importer.ParseMapping([]byte(`{"fields":{"n":"integer"}}`))
+	require.NoError(t, err)
+	opt := importer.Options{Format: importer.FormatJSON, Mapping: m, TsMultiplier: 1e9, Now: testNow, LineOffset: 10}
+	dec, err := importer.NewDecoder(strings.NewReader(data), opt)
+	require.NoError(t, err)
+
+	var rows []influx.Row
+	res, err := importer.Import(dec, 10, func(batch []influx.Row) error {
+		rows = append(rows, batch...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Written)
+	assert.Equal(t, []int{13, 14}, []int{res.Errors[0].Line, res.Errors[1].Line})
+	assert.Equal(t, 14, res.Committed)
+
+	require.Equal(t, 2, len(rows))
+	assert.Equal(t, influx.PointTags{{Key: "host", Value: "a"}}, rows[0].Tags)
+	assert.Equal(t, int64(1577836800000000000), rows[0].Timestamp)
+	types := make(map[string]int32)
+	for _, f := range rows[0].Fields {
+		types[f.Key] = f.Type
+	}
+	assert.Equal(t, map[string]int32{
+		"usage": influx.Field_Type_Float,
+		"up":    influx.Field_Type_Boolean,
+		"note":  influx.Field_Type_String,
+		"n":     influx.Field_Type_Int,
+	}, types)
+	assert.Equal(t, int64(1577836800000000000), rows[1].Timestamp)
+}
+
+func TestJSONDecoder_Mapping(t *testing.T) {
+	m, err := importer.ParseMapping([]byte(`{"measurement_column":"m","time_column":"ts","time_format":"rfc3339","tags":["host"]}`))
+	require.NoError(t, err)
+
+	data := `{"m":"cpu","host":"a","usage":2.5,"ts":"2020-01-01T00:00:00Z"}
+{"host":"b","usage":1}
+{"m":"cpu","usage":[1]}
+`
+	opt := importer.Options{Format: importer.FormatJSON, Mapping: m, Now: testNow}
+	dec, err := importer.NewDecoder(strings.NewReader(data), opt)
+	require.NoError(t, err)
+
+	var rows []influx.Row
+	res, err := importer.Import(dec, 10, func(batch []influx.Row) error {
+		rows = append(rows, batch...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Written)
+	assert.Equal(t, 2, res.Rejected)
+
+	require.Equal(t, 1, len(rows))
+	assert.Equal(t, "cpu", rows[0].Name)
+	assert.Equal(t, influx.PointTags{{Key: "host", Value: "a"}}, rows[0].Tags)
+	assert.Equal(t, influx.Fields{{Key: "usage", NumValue: 2.5, Type: influx.Field_Type_Float}}, rows[0].Fields)
+	assert.Equal(t, int64(1577836800000000000), rows[0].Timestamp)
+}
+
+func TestImport_WriteError(t *testing.T) {
+	data := "#datatype measurement,double\nm,v\ncpu,1\ncpu,2\ncpu,3\n"
+	dec, err := importer.NewDecoder(strings.NewReader(data), importer.Options{Format: importer.FormatCSV})
+	require.NoError(t, err)
+
+	writes := 0
+	res, err := importer.Import(dec, 2, func(batch []influx.Row) error {
+		writes++
+		if writes == 2 {
+			return errors.New("write failed")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "write failed")
+	assert.Equal(t, 2, res.Written)
+	assert.Equal(t, 4, res.Committed)
+}
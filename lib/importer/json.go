@@ -0,0 +1,193 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+var errMissingMapping = errors.New("an object without fields needs a mapping")
+
+// jsonDecoder decodes one object per line. An object holding the fields is converted as is:
+//
+//	{"measurement":"cpu","tags":{"host":"server01"},"fields":{"usage":2.5},"time":1577836800000000000}
+//
+// the keys of the other objects are mapped to the columns by the mapping.
+type jsonDecoder struct {
+	lr      *lineReader
+	opt     Options
+	columns map[string]column
+}
+
+func newJSONDecoder(lr *lineReader, opt Options) *jsonDecoder {
+	return &jsonDecoder{lr: lr, opt: opt, columns: make(map[string]column)}
+}
+
+func (d *jsonDecoder) Decode(row *influx.Row) (int, error) {
+	s, line, err := d.lr.next()
+	if err != nil {
+		return 0, err
+	}
+
+	resetRow(row)
+	obj := make(map[string]interface{})
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return line, &LineError{Line: line, Err: err}
+	}
+
+	if fields, ok := obj["fields"].(map[string]interface{}); ok {
+		err = d.decodeObject(row, obj, fields)
+	} else if d.opt.Mapping == nil {
+		err = errMissingMapping
+	} else {
+		err = d.decodeMapped(row, obj)
+	}
+	if err == nil {
+		var measurement string
+		if d.opt.Mapping != nil {
+			measurement = d.opt.Mapping.Measurement
+		}
+		err = finishRow(row, measurement, &d.opt)
+	}
+	if err != nil {
+		return line, &LineError{Line: line, Err: err}
+	}
+	return line, nil
+}
+
+func (d *jsonDecoder) decodeObject(row *influx.Row, obj, fields map[string]interface{}) error {
+	if v, ok := obj["measurement"]; ok {
+		name, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("measurement must be a string")
+		}
+		row.Name = name
+	}
+
+	if v, ok := obj["tags"]; ok {
+		tags, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("tags must be an object")
+		}
+		for k, tv := range tags {
+			s, err := jsonString(k, tv)
+			if err != nil {
+				return err
+			}
+			if s != "" {
+				row.Tags = append(row.Tags, influx.Tag{Key: k, Value: s})
+			}
+		}
+	}
+
+	for k, fv := range fields {
+		s, err := jsonString(k, fv)
+		if err != nil {
+			return err
+		}
+		if s == "" {
+			continue
+		}
+		typ := jsonFieldType(fv)
+		if d.opt.Mapping != nil {
+			if name, ok := d.opt.Mapping.Fields[k]; ok {
+				typ, _ = fieldType(name)
+			}
+		}
+		f, err := parseField(k, s, typ)
+		if err != nil {
+			return err
+		}
+		row.Fields = append(row.Fields, f)
+	}
+
+	if v, ok := obj["time"]; ok && v != nil {
+		layout := time.RFC3339Nano
+		if d.opt.Mapping != nil && d.opt.Mapping.TimeFormat != "" {
+			layout = timeLayout(d.opt.Mapping.TimeFormat)
+		}
+		if _, ok := v.(json.Number); ok {
+			layout = ""
+		}
+		s, err := jsonString("time", v)
+		if err != nil {
+			return err
+		}
+		row.Timestamp, err = parseTime(s, layout, d.opt.TsMultiplier)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *jsonDecoder) decodeMapped(row *influx.Row, obj map[string]interface{}) error {
+	for k, v := range obj {
+		c, ok := d.columns[k]
+		if !ok {
+			c = d.opt.Mapping.column(k)
+			d.columns[k] = c
+		}
+		if c.kind == columnIgnore {
+			continue
+		}
+		s, err := jsonString(k, v)
+		if err != nil {
+			return err
+		}
+		if c.kind == columnField && c.fieldType == influx.Field_Type_Unknown {
+			c.fieldType = jsonFieldType(v)
+		}
+		if err := setValue(row, &c, s, &d.opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func jsonString(key string, v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("unsupported value of %q", key)
+}
+
+func jsonFieldType(v interface{}) int32 {
+	switch v.(type) {
+	case json.Number:
+		return influx.Field_Type_Float
+	case bool:
+		return influx.Field_Type_Boolean
+	}
+	return influx.Field_Type_String
+}
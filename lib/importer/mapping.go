@@ -0,0 +1,216 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+const (
+	TimeFormatNumber  = "number"
+	TimeFormatRFC3339 = "rfc3339"
+)
+
+// Mapping tells which columns of the imported data are the measurement, the tags, the fields and the time.
+// When no field is listed, every column which is not mapped otherwise is a field of an inferred type.
+type Mapping struct {
+	// Measurement is the measurement of the rows without a measurement column
+	Measurement       string `json:"measurement,omitempty"`
+	MeasurementColumn string `json:"measurement_column,omitempty"`
+	TimeColumn        string `json:"time_column,omitempty"`
+	// TimeFormat is number (in the precision of the request), rfc3339 or a Go time layout
+	TimeFormat string   `json:"time_format,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	// Fields maps the field columns to their types: float, integer, string or boolean
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ParseMapping decodes a mapping from its JSON form.
+func ParseMapping(b []byte) (*Mapping, error) {
+	m := &Mapping{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("invalid mapping: %v", err)
+	}
+	for name, typ := range m.Fields {
+		if _, err := fieldType(typ); err != nil {
+			return nil, fmt.Errorf("invalid mapping of field %q: %v", name, err)
+		}
+	}
+	return m, nil
+}
+
+type columnKind uint8
+
+const (
+	columnIgnore columnKind = iota
+	columnMeasurement
+	columnTag
+	columnField
+	columnTime
+)
+
+type column struct {
+	name string
+	kind columnKind
+	// influx.Field_Type_Unknown means the type is inferred from the value
+	fieldType int32
+	// the layout of a time column, empty for a number
+	timeLayout string
+}
+
+func (m *Mapping) column(name string) column {
+	c := column{name: name}
+	switch {
+	case name == m.MeasurementColumn:
+		c.kind = columnMeasurement
+	case name == m.TimeColumn:
+		c.kind = columnTime
+		c.timeLayout = timeLayout(m.TimeFormat)
+	case containsString(m.Tags, name):
+		c.kind = columnTag
+	case len(m.Fields) == 0:
+		c.kind = columnField
+	default:
+		if typ, ok := m.Fields[name]; ok {
+			c.kind = columnField
+			c.fieldType, _ = fieldType(typ)
+		}
+	}
+	return c
+}
+
+func containsString(s []string, v string) bool {
+	for i := range s {
+		if s[i] == v {
+			return true
+		}
+	}
+	return false
+}
+
+func timeLayout(format string) string {
+	switch strings.ToLower(format) {
+	case "", TimeFormatNumber:
+		return ""
+	case TimeFormatRFC3339, "rfc3339nano":
+		return time.RFC3339Nano
+	}
+	return format
+}
+
+// fieldType accepts both the names of the mapping and the types of the csv annotations
+func fieldType(name string) (int32, error) {
+	switch name {
+	case "float", "double":
+		return influx.Field_Type_Float, nil
+	case "integer", "long":
+		return influx.Field_Type_Int, nil
+	case "string":
+		return influx.Field_Type_String, nil
+	case "boolean", "bool":
+		return influx.Field_Type_Boolean, nil
+	case "", "field":
+		return influx.Field_Type_Unknown, nil
+	}
+	return influx.Field_Type_Unknown, fmt.Errorf("unsupported field type %q", name)
+}
+
+// datatypeColumn converts a type of the csv #datatype annotation to a column
+func datatypeColumn(name, datatype string) (column, error) {
+	c := column{name: name}
+	switch {
+	case datatype == "measurement":
+		c.kind = columnMeasurement
+	case datatype == "tag":
+		c.kind = columnTag
+	case datatype == "ignore" || datatype == "ignored":
+		c.kind = columnIgnore
+	case datatype == "dateTime" || strings.HasPrefix(datatype, "dateTime:"):
+		c.kind = columnTime
+		c.timeLayout = timeLayout(strings.TrimPrefix(strings.TrimPrefix(datatype, "dateTime"), ":"))
+	default:
+		typ, err := fieldType(datatype)
+		if err != nil {
+			return c, err
+		}
+		c.kind = columnField
+		c.fieldType = typ
+	}
+	return c, nil
+}
+
+func parseField(key, s string, typ int32) (influx.Field, error) {
+	f := influx.Field{Key: key, Type: typ}
+	var err error
+	switch typ {
+	case influx.Field_Type_Float:
+		f.NumValue, err = strconv.ParseFloat(s, 64)
+	case influx.Field_Type_Int:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 64)
+		f.NumValue = float64(n)
+	case influx.Field_Type_String:
+		f.StrValue = s
+	case influx.Field_Type_Boolean:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		f.NumValue = boolValue(b)
+	default:
+		if v, e := strconv.ParseFloat(s, 64); e == nil {
+			f.Type, f.NumValue = influx.Field_Type_Float, v
+		} else if b, e := strconv.ParseBool(s); e == nil {
+			f.Type, f.NumValue = influx.Field_Type_Boolean, boolValue(b)
+		} else {
+			f.Type, f.StrValue = influx.Field_Type_String, s
+		}
+	}
+	if err != nil {
+		return f, fmt.Errorf("invalid %s value of field %q: %q", influx.FieldTypeString(typ), key, s)
+	}
+	return f, nil
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func parseTime(s, layout string, multiplier int64) (int64, error) {
+	if layout == "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		return n * multiplier, nil
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %v", s, err)
+	}
+	return t.UnixNano(), nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvstorage
+
+import (
+	"os"
+
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/openGemini/openGemini/lib/encrypt"
+	"github.com/openGemini/openGemini/lib/util"
+)
+
+// encryptedFS encrypts the new files of pebble by the keyring. The files
+// written before the encryption was enabled are read as they are.
+type encryptedFS struct {
+	vfs.FS
+	keyring *encrypt.Keyring
+}
+
+func newEncryptedFS(keyring *encrypt.Keyring) vfs.FS {
+	return &encryptedFS{FS: vfs.Default, keyring: keyring}
+}
+
+func (fs *encryptedFS) Create(name string) (vfs.File, error) {
+	f, err := fs.FS.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	ef, err := fs.keyring.Create(f.(*os.File))
+	if err != nil {
+		util.MustClose(f)
+		return nil, err
+	}
+	return ef, nil
+}
+
+func (fs *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, error) {
+	f, err := fs.FS.Open(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	of, ok := f.(*os.File)
+	if !ok {
+		return f, nil
+	}
+	ef, err := fs.keyring.Open(of)
+	if err == encrypt.ErrNotEncrypted {
+		return f, nil
+	}
+	if err != nil {
+		util.MustClose(f)
+		return nil, err
+	}
+	return ef, nil
+}
+
+// ReuseForWrite creates a new file instead of reusing the old one, because the
+// encrypted blocks can not be overwritten in place.
+func (fs *encryptedFS) ReuseForWrite(oldname, newname string) (vfs.File, error) {
+	if err := fs.FS.Remove(oldname); err != nil {
+		return nil, err
+	}
+	return fs.Create(newname)
+}
+
+func (fs *encryptedFS) Stat(name string) (os.FileInfo, error) {
+	fi, err := fs.FS.Stat(name)
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() == 0 {
+		return fi, err
+	}
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer util.MustClose(f)
+	return f.Stat()
+}
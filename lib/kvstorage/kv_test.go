@@ -17,13 +17,17 @@ limitations under the License.
 package kvstorage_test
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/influxdata/influxdb/pkg/testing/assert"
+	"github.com/openGemini/openGemini/lib/encrypt"
 	kv "github.com/openGemini/openGemini/lib/kvstorage"
 )
 
@@ -197,3 +201,56 @@ func BenchmarkPebbleDB_Get(b *testing.B) {
 	kbPool.Put(v)
 	os.RemoveAll(tmp)
 }
+
+func TestEncryptedPebble(t *testing.T) {
+	keyring, err := encrypt.ParseKeyring([]byte("1 000102030405060708090a0b0c0d0e0f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt.SetDefaultKeyring(keyring)
+	defer encrypt.SetDefaultKeyring(nil)
+
+	path := t.TempDir()
+	open := func() kv.KVStorage {
+		s, err := kv.NewStorage(&kv.Config{KVType: kv.PEBBLEDB, Path: path, Pebble: &kv.PebbleOptions{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	s := open()
+	for i := 0; i < 100; i++ {
+		if err := s.Set([]byte(fmt.Sprintf("key-%03d", i)), []byte("secret-value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fi := range files {
+		data, err := ioutil.ReadFile(filepath.Join(path, fi.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret-value") {
+			t.Fatalf("%s is not encrypted", fi.Name())
+		}
+	}
+
+	s = open()
+	defer s.Close()
+	v, err := s.Get(nil, []byte("key-042"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(v), "secret-value")
+}
@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/pebble"
+	"github.com/openGemini/openGemini/lib/encrypt"
 	"github.com/openGemini/openGemini/lib/util"
 )
 
@@ -68,13 +69,17 @@ func genPebbleOptions(opts *PebbleOptions) *pebble.Options {
 		opts.MemTableSize = DefaultMemTableSize
 	}
 
-	return &pebble.Options{
+	pebbleOpts := &pebble.Options{
 		Cache:                       pebble.NewCache(opts.CacheSize),
 		MemTableSize:                opts.MemTableSize,
 		MemTableStopWritesThreshold: opts.MemTableNumber,
 		MaxConcurrentCompactions:    opts.MaxConcurrentCompactions,
 		LBaseMaxBytes:               opts.L0MaxBytes,
 	}
+	if keyring := encrypt.DefaultKeyring(); keyring != nil {
+		pebbleOpts.FS = newEncryptedFS(keyring)
+	}
+	return pebbleOpts
 }
 
 func NewPebbleDB(path string, opts *PebbleOptions) *PebbleDB {
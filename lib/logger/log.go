@@ -18,6 +18,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 
@@ -32,7 +33,7 @@ import (
 var logger *zap.Logger
 var hooks []*lumberjack.Logger
 var initHandler func(*zap.Logger)
-var level zapcore.Level
+var level = zap.NewAtomicLevel()
 
 func init() {
 	InitLogger(config.NewLogger(config.AppSingle))
@@ -43,7 +44,7 @@ func SetInitLoggerHandler(handler func(*zap.Logger)) {
 }
 
 func InitLogger(conf config.Logger) {
-	level = conf.Level
+	level.SetLevel(conf.Level)
 	logger = getLogger(conf)
 	if initHandler != nil {
 		initHandler(logger)
@@ -51,6 +52,11 @@ func InitLogger(conf config.Logger) {
 	util.SetLogger(logger)
 }
 
+// SetLevel changes the level of the application logs, it takes effect immediately.
+func SetLevel(l zapcore.Level) {
+	level.SetLevel(l)
+}
+
 func GetLogger() *zap.Logger {
 	return logger
 }
@@ -69,7 +75,6 @@ func CloseLogger() {
 
 func getLogger(conf config.Logger) *zap.Logger {
 	maxSize := rewriteMaxSize(conf.MaxSize)
-	level := rewriteLevel(conf.Level)
 	hookNormal := newHook(conf, maxSize, conf.GetFileName())
 	hookError := newHook(conf, maxSize, makeErrFileName(conf.GetFileName()))
 	hooks = append(hooks, hookNormal, hookError)
@@ -77,7 +82,7 @@ func getLogger(conf config.Logger) *zap.Logger {
 	encoder := newEncoder()
 
 	levelNormal := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= level
+		return lvl >= rewriteLevel(level.Level())
 	})
 	levelError := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 		return lvl >= zapcore.ErrorLevel
@@ -90,6 +95,14 @@ func getLogger(conf config.Logger) *zap.Logger {
 	return zap.New(core, zap.AddCaller(), zap.Development())
 }
 
+// NewFileLogger returns a logger writing into name.log of the logging path. The
+// file is rotated as the application logs, it is closed by the returned closer.
+func NewFileLogger(conf config.Logger, name string) (*zap.Logger, io.Closer) {
+	hook := newHook(conf, rewriteMaxSize(conf.MaxSize), filepath.Join(conf.Path, name+".log"))
+	core := zapcore.NewCore(newEncoder(), zapcore.AddSync(hook), zap.InfoLevel)
+	return zap.New(core), hook
+}
+
 func rewriteMaxSize(size toml.Size) int {
 	maxSize := int(size)
 	if maxSize < 1024*1024 {
@@ -429,7 +429,7 @@ func (l *Logger) Warn(msg string, fields ...zap.Field) {
 }
 
 func (l *Logger) Debug(msg string, fields ...zap.Field) {
-	if level > zapcore.DebugLevel {
+	if level.Level() > zapcore.DebugLevel {
 		return
 	}
 	l.logger.Debug(msg, fields...)
@@ -13,6 +13,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -69,6 +70,9 @@ const (
 
 	maxDbOrRpName = 256
 
+	// the number of random bytes of an API token secret
+	tokenSecretBytes = 32
+
 	// ShardGroupDeletedExpiration is the amount of time before a shard group info will be removed from cached
 	// data after it has been marked deleted (2 weeks).
 	ShardGroupDeletedExpiration = -2 * 7 * 24 * time.Hour
@@ -128,6 +132,7 @@ type MetaClient interface {
 	DataNode(id uint64) (*meta2.DataNode, error)
 	DataNodes() ([]meta2.DataNode, error)
 	DeleteDataNode(id uint64) error
+	DecommissionDataNode(id uint64) error
 	DeleteMetaNode(id uint64) error
 	DropShard(id uint64) error
 	DropDatabase(name string) error
@@ -138,6 +143,14 @@ type MetaClient interface {
 	RetentionPolicy(database, name string) (rpi *meta2.RetentionPolicyInfo, err error)
 	SetAdminPrivilege(username string, admin bool) error
 	SetPrivilege(username, database string, p originql.Privilege) error
+	CreateRole(name string) error
+	DropRole(name string) error
+	Roles() []meta2.RoleInfo
+	SetRolePrivilege(role string, p meta2.RolePrivilege, revoke bool) error
+	SetUserRole(username, role string, revoke bool) error
+	CreateToken(name, user string, scopes map[string]originql.Privilege, duration time.Duration) (string, error)
+	DropToken(name string) error
+	Tokens() []meta2.TokenInfo
 	ShardsByTimeRange(sources influxql.Sources, tmin, tmax time.Time) (a []meta2.ShardInfo, err error)
 	ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta2.ShardGroupInfo, err error)
 	TruncateShardGroups(t time.Time) error
@@ -247,6 +260,9 @@ type Client struct {
 	muAuthData   sync.RWMutex
 	authFailRcds map[string]authFailCache
 	authSuccRcds map[string]time.Time
+
+	// names of the tokens whose last used time is being updated
+	tokensInUse sync.Map
 }
 
 type authRcd struct {
@@ -470,6 +486,16 @@ func (c *Client) DeleteDataNode(id uint64) error {
 	return c.retryUntilExec(proto2.Command_DeleteDataNodeCommand, proto2.E_DeleteDataNodeCommand_Command, cmd)
 }
 
+// DecommissionDataNode marks a data node as decommissioning, its pts are moved to other nodes and
+// the node is deleted after it owns no pts.
+func (c *Client) DecommissionDataNode(id uint64) error {
+	cmd := &proto2.DecommissionDataNodeCommand{
+		ID: proto.Uint64(id),
+	}
+
+	return c.retryUntilExec(proto2.Command_DecommissionDataNodeCommand, proto2.E_DecommissionDataNodeCommand_Command, cmd)
+}
+
 // MetaNodes returns the meta nodes' info.
 func (c *Client) MetaNodes() ([]meta2.NodeInfo, error) {
 	c.mu.RLock()
@@ -1338,6 +1364,156 @@ func (c *Client) SetPrivilege(username, database string, p originql.Privilege) e
 	)
 }
 
+// CreateRole creates a role without any privilege.
+func (c *Client) CreateRole(name string) error {
+	return c.retryUntilExec(proto2.Command_CreateRoleCommand, proto2.E_CreateRoleCommand_Command,
+		&proto2.CreateRoleCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+// DropRole removes the role with the given name and revokes it from all users.
+func (c *Client) DropRole(name string) error {
+	return c.retryUntilExec(proto2.Command_DropRoleCommand, proto2.E_DropRoleCommand_Command,
+		&proto2.DropRoleCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+// Roles returns the roles in the cluster.
+func (c *Client) Roles() []meta2.RoleInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cacheData.CloneRoles()
+}
+
+// SetRolePrivilege grants a privilege to the given role, or revokes it if revoke is true.
+func (c *Client) SetRolePrivilege(role string, p meta2.RolePrivilege, revoke bool) error {
+	return c.retryUntilExec(proto2.Command_SetRolePrivilegeCommand, proto2.E_SetRolePrivilegeCommand_Command,
+		&proto2.SetRolePrivilegeCommand{
+			Role: proto.String(role),
+			Privilege: &proto2.RolePrivilege{
+				Database:        proto.String(p.Database),
+				RetentionPolicy: proto.String(p.RetentionPolicy),
+				Measurement:     proto.String(p.Measurement),
+				Regex:           proto.Bool(p.Regex),
+				Privilege:       proto.Int32(int32(p.Privilege)),
+			},
+			Revoke: proto.Bool(revoke),
+		},
+	)
+}
+
+// SetUserRole grants the role to the given user, or revokes it if revoke is true.
+func (c *Client) SetUserRole(username, role string, revoke bool) error {
+	return c.retryUntilExec(proto2.Command_SetUserRoleCommand, proto2.E_SetUserRoleCommand_Command,
+		&proto2.SetUserRoleCommand{
+			Username: proto.String(username),
+			Role:     proto.String(role),
+			Revoke:   proto.Bool(revoke),
+		},
+	)
+}
+
+// CreateToken creates an API token of user and returns its secret. Only the
+// hash of the secret is stored, so it cannot be retrieved later. A zero
+// duration creates a token which never expires.
+func (c *Client) CreateToken(name, user string, scopes map[string]originql.Privilege, duration time.Duration) (string, error) {
+	b := make([]byte, tokenSecretBytes)
+	if _, err := io.ReadFull(crand.Reader, b); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(b)
+
+	ti := meta2.TokenInfo{
+		Name:      name,
+		User:      user,
+		Hash:      meta2.HashToken(secret),
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+	}
+	if duration > 0 {
+		ti.ExpiresAt = ti.CreatedAt.Add(duration)
+	}
+	err := c.retryUntilExec(proto2.Command_CreateTokenCommand, proto2.E_CreateTokenCommand_Command,
+		&proto2.CreateTokenCommand{
+			Token: ti.Marshal(),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// DropToken revokes the API token with the given name.
+func (c *Client) DropToken(name string) error {
+	return c.retryUntilExec(proto2.Command_DropTokenCommand, proto2.E_DropTokenCommand_Command,
+		&proto2.DropTokenCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+// Tokens returns the API tokens in the cluster.
+func (c *Client) Tokens() []meta2.TokenInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cacheData.CloneTokens()
+}
+
+// AuthenticateToken returns the user authenticated by the API token secret,
+// restricted to the scopes of the token.
+func (c *Client) AuthenticateToken(secret string) (meta2.User, error) {
+	hash := meta2.HashToken(secret)
+	c.mu.RLock()
+	ti := c.cacheData.TokenByHash(hash)
+	var token meta2.TokenInfo
+	var userInfo *meta2.UserInfo
+	if ti != nil {
+		token = *ti
+		userInfo = c.cacheData.GetUser(ti.User)
+	}
+	c.mu.RUnlock()
+
+	if ti == nil {
+		return nil, meta2.ErrAuthenticate
+	}
+	if userInfo == nil {
+		return nil, meta2.ErrUserNotFound
+	}
+	now := time.Now().UTC()
+	if token.Expired(now) {
+		return nil, meta2.ErrTokenExpired
+	}
+	if now.Sub(token.LastUsed) >= meta2.TokenLastUsedInterval {
+		c.touchToken(token.Name, now)
+	}
+	return &meta2.TokenUser{UserInfo: userInfo, Token: token}, nil
+}
+
+// touchToken records the last used time of a token in the background.
+// Concurrent requests authenticated by the same token only update it once.
+func (c *Client) touchToken(name string, now time.Time) {
+	if _, loaded := c.tokensInUse.LoadOrStore(name, struct{}{}); loaded {
+		return
+	}
+	go func() {
+		defer c.tokensInUse.Delete(name)
+		err := c.retryUntilExec(proto2.Command_UpdateTokenLastUsedCommand, proto2.E_UpdateTokenLastUsedCommand_Command,
+			&proto2.UpdateTokenLastUsedCommand{
+				Name:     proto.String(name),
+				LastUsed: proto.Int64(now.UnixNano()),
+			},
+		)
+		if err != nil {
+			c.logger.Warn("update token last used time failed", zap.String("token", name), zap.Error(err))
+		}
+	}()
+}
+
 // SetAdminPrivilege sets or unsets admin privilege to the given username.
 func (c *Client) SetAdminPrivilege(username string, admin bool) error {
 	return c.retryUntilExec(proto2.Command_SetAdminPrivilegeCommand, proto2.E_SetAdminPrivilegeCommand_Command,
@@ -202,7 +202,7 @@ func TestClient_BasicFunctions(t *testing.T) {
 			ClusterID:    1,
 			DataNodes: []meta2.DataNode{
 				meta2.DataNode{
-					meta2.NodeInfo{
+					NodeInfo: meta2.NodeInfo{
 						ID:      1,
 						Host:    "127.0.0.1:8090",
 						TCPHost: "127.0.0.1:8091",
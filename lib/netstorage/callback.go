@@ -21,6 +21,7 @@ import (
 
 	"github.com/openGemini/openGemini/engine/executor"
 	"github.com/openGemini/openGemini/engine/executor/spdy/transport"
+	"github.com/openGemini/openGemini/lib/errno"
 )
 
 type DDLCallback struct {
@@ -86,8 +87,14 @@ func (c *WritePointsCallback) GetCodec() transport.Codec {
 }
 
 func (c *WritePointsCallback) Error() error {
-	if c.data.Code == 0 {
+	switch c.data.Code {
+	case WritePointsOK:
 		return nil
+	case WritePointsPartial:
+		return PartialWriteError{
+			Reason:  errno.NewRemote(c.data.Message, errno.Errno(c.data.Errno)),
+			Dropped: int(c.data.Dropped),
+		}
 	}
 
 	return errors.New(c.data.Message)
@@ -57,6 +57,9 @@ func GetNewEngineFunction(entType string) NewEngineFun {
 	return nil
 }
 
+// DBPTFileReader reads at most size bytes of a file of a moving pt from offset
+type DBPTFileReader func(name string, offset int64, size int) ([]byte, error)
+
 type Engine interface {
 	Open(ptIds []uint32, durationInfos map[uint64]*meta.ShardDurationInfo) error
 	Close() error
@@ -98,6 +101,13 @@ type Engine interface {
 
 	UpdateShardDurationInfo(info *meta.ShardDurationInfo) error
 
+	PreOffloadDBPT(db string, ptId uint32) error
+	LoadDBPT(db string, ptId uint32, durationInfos map[uint64]*meta.ShardDurationInfo) error
+	OffloadDBPT(db string, ptId uint32) error
+	DBPTFiles(db string, ptId uint32) ([]string, error)
+	ReadDBPTFile(db string, ptId uint32, name string, offset int64, size int) ([]byte, error)
+	ReceiveDBPT(db string, ptId uint32, files []string, read DBPTFileReader) error
+
 	SysCtrl(req *SysCtrlRequest) error
 	Statistics(buffer []byte) ([]byte, error)
 }
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/pkg/limiter"
+	"github.com/openGemini/openGemini/lib/config"
 )
 
 const (
@@ -58,6 +59,8 @@ type EngineOptions struct {
 	WalEnabled        bool
 	WalSyncInterval   time.Duration
 	WalReplayParallel bool
+	// WalReplayQuarantine copies corrupt wal records aside before they are skipped
+	WalReplayQuarantine bool
 
 	// Immutable config
 	ReadCacheLimit   int
@@ -65,6 +68,18 @@ type EngineOptions struct {
 	CacheMetaBlock   bool
 	EnableMmapRead   bool
 	CompactionMethod int // 0:auto, 1:stream, 2: non-stream
+	FloatCompression string
+
+	// LastValueCache keeps the latest value of each field of the series for the last() queries
+	LastValueCacheEnabled      bool
+	LastValueCacheMeasurements []string
+	LastValueCacheMaxSize      int64
+
+	// SortKey is the tag keys the rows of the tssp2 column files are sorted by
+	SortKey []string
+
+	// IndexGCInterval is the interval of the orphan series garbage collection, 0 disables it
+	IndexGCInterval time.Duration
 }
 
 func NewEngineOptions() EngineOptions {
@@ -72,3 +87,37 @@ func NewEngineOptions() EngineOptions {
 		Version: tsspVersion,
 	}
 }
+
+// NewEngineOptionsWithConfig creates the engine options from the [data] section of the store configuration.
+func NewEngineOptionsWithConfig(conf *config.Store) EngineOptions {
+	opt := NewEngineOptions()
+	opt.ImmTableMaxMemoryPercentage = conf.ImmTableMaxMemoryPercentage
+	opt.WriteColdDuration = time.Duration(conf.WriteColdDuration)
+	opt.ShardMutableSizeLimit = int64(conf.ShardMutableSizeLimit)
+	opt.NodeMutableSizeLimit = int64(conf.NodeMutableSizeLimit)
+	opt.MaxWriteHangTime = time.Duration(conf.MaxWriteHangTime)
+	opt.CompactThroughput = int64(conf.CompactThroughput)
+	opt.CompactThroughputBurst = int64(conf.CompactThroughputBurst)
+	opt.CompactRecovery = conf.CompactRecovery
+	opt.SnapshotThroughput = int64(conf.SnapshotThroughput)
+	opt.SnapshotThroughputBurst = int64(conf.SnapshotThroughputBurst)
+	opt.MaxConcurrentCompactions = conf.MaxConcurrentCompactions
+	opt.MaxFullCompactions = conf.MaxFullCompactions
+	opt.FullCompactColdDuration = time.Duration(conf.CompactFullWriteColdDuration)
+	opt.CacheDataBlock = conf.CacheDataBlock
+	opt.CacheMetaBlock = conf.CacheMetaBlock
+	opt.EnableMmapRead = conf.EnableMmapRead
+	opt.ReadCacheLimit = conf.ReadCacheLimit
+	opt.WalSyncInterval = time.Duration(conf.WalSyncInterval)
+	opt.WalEnabled = conf.WalEnabled
+	opt.WalReplayParallel = conf.WalReplayParallel
+	opt.WalReplayQuarantine = conf.WalQuarantineEnabled()
+	opt.CompactionMethod = conf.CompactionMethod
+	opt.FloatCompression = conf.FloatCompression
+	opt.LastValueCacheEnabled = conf.LastValueCacheEnabled
+	opt.LastValueCacheMeasurements = conf.LastValueCacheMeasurements
+	opt.LastValueCacheMaxSize = int64(conf.LastValueCacheMaxSize)
+	opt.SortKey = conf.SortKey
+	opt.IndexGCInterval = time.Duration(conf.IndexGCInterval)
+	return opt
+}
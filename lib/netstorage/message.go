@@ -241,9 +241,19 @@ func (r *WritePointsRequest) Size() int {
 	return len(r.points)
 }
 
+const (
+	WritePointsOK      uint8 = 0
+	WritePointsFailed  uint8 = 1
+	WritePointsPartial uint8 = 2
+)
+
 type WritePointsResponse struct {
 	Code    uint8
 	Message string
+
+	// The errno and the number of dropped rows of a partial write
+	Errno   uint16
+	Dropped uint32
 }
 
 func NewWritePointsResponse(code uint8, message string) *WritePointsResponse {
@@ -255,6 +265,10 @@ func NewWritePointsResponse(code uint8, message string) *WritePointsResponse {
 
 func (r *WritePointsResponse) Marshal(buf []byte) ([]byte, error) {
 	buf = append(buf, r.Code)
+	if r.Code == WritePointsPartial {
+		buf = codec.AppendUint16(buf, r.Errno)
+		buf = codec.AppendUint32(buf, r.Dropped)
+	}
 	buf = append(buf, r.Message...)
 	return buf, nil
 }
@@ -265,7 +279,17 @@ func (r *WritePointsResponse) Unmarshal(buf []byte) error {
 	}
 
 	r.Code = buf[0]
-	r.Message = string(buf[1:])
+	buf = buf[1:]
+	if r.Code == WritePointsPartial {
+		if len(buf) < 6 {
+			return errno.NewError(errno.ShortBufferSize, 6, len(buf))
+		}
+		dec := codec.NewBinaryDecoder(buf)
+		r.Errno = dec.Uint16()
+		r.Dropped = dec.Uint32()
+		buf = buf[6:]
+	}
+	r.Message = string(buf)
 	return nil
 }
 
@@ -274,5 +298,8 @@ func (r *WritePointsResponse) Instance() transport.Codec {
 }
 
 func (r *WritePointsResponse) Size() int {
+	if r.Code == WritePointsPartial {
+		return 7 + len(r.Message)
+	}
 	return 1 + len(r.Message)
 }
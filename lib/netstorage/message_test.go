@@ -216,6 +216,29 @@ func TestWritePointsResponse(t *testing.T) {
 	assert.Equal(t, req, other.(*netstorage.WritePointsResponse))
 }
 
+func TestWritePointsResponse_Partial(t *testing.T) {
+	req := netstorage.NewWritePointsResponse(netstorage.WritePointsPartial, "max series limit exceeded")
+	req.Errno = uint16(errno.SeriesLimitExceeded)
+	req.Dropped = 10
+
+	other, ok := assertCodec(t, req, true, true)
+	if !ok {
+		return
+	}
+	assert.Equal(t, req, other.(*netstorage.WritePointsResponse))
+
+	cb := &netstorage.WritePointsCallback{}
+	assert.NoError(t, cb.Handle(other))
+	werr, ok := cb.Error().(netstorage.PartialWriteError)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, 10, werr.Dropped)
+	assert.True(t, errno.Equal(werr.Reason, errno.SeriesLimitExceeded))
+
+	assert.NotNil(t, (&netstorage.WritePointsResponse{}).Unmarshal([]byte{netstorage.WritePointsPartial, 0}))
+}
+
 func TestInvalidDDLMessage(t *testing.T) {
 	msg := &netstorage.DDLMessage{}
 	err := msg.Unmarshal(nil)
@@ -50,6 +50,12 @@ const (
 
 	CreateDataBaseRequestMessage
 	CreateDatabaseResponseMessage
+
+	MigratePtRequestMessage
+	MigratePtResponseMessage
+
+	PtFilesRequestMessage
+	PtFilesResponseMessage
 )
 
 func NewMessage(typ uint8) codec.BinaryCodec {
@@ -86,6 +92,14 @@ func NewMessage(typ uint8) codec.BinaryCodec {
 		return &CreateDataBaseRequest{}
 	case CreateDatabaseResponseMessage:
 		return &CreateDataBaseResponse{}
+	case MigratePtRequestMessage:
+		return &MigratePtRequest{}
+	case MigratePtResponseMessage:
+		return &MigratePtResponse{}
+	case PtFilesRequestMessage:
+		return &PtFilesRequest{}
+	case PtFilesResponseMessage:
+		return &PtFilesResponse{}
 	default:
 		return nil
 	}
@@ -107,6 +121,10 @@ func GetResponseMessageType(typ uint8) uint8 {
 		return GetShardSplitPointsResponseMessage
 	case DeleteRequestMessage:
 		return DeleteResponseMessage
+	case MigratePtRequestMessage:
+		return MigratePtResponseMessage
+	case PtFilesRequestMessage:
+		return PtFilesResponseMessage
 	default:
 		return UnknownMessage
 	}
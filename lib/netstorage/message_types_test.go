@@ -39,6 +39,8 @@ func TestMessageTypes(t *testing.T) {
 		store.ShowTagValuesCardinalityRequestMessage: {&store.ShowTagValuesCardinalityRequest{}, &store.ShowTagValuesCardinalityResponse{}},
 		store.GetShardSplitPointsRequestMessage:      {&store.GetShardSplitPointsRequest{}, &store.GetShardSplitPointsResponse{}},
 		store.DeleteRequestMessage:                   {&store.DeleteRequest{}, &store.DeleteResponse{}},
+		store.MigratePtRequestMessage:                {&store.MigratePtRequest{}, &store.MigratePtResponse{}},
+		store.PtFilesRequestMessage:                  {&store.PtFilesRequest{}, &store.PtFilesResponse{}},
 	}
 
 	for typ, items := range data {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/openGemini/openGemini/lib/codec"
 	internal2 "github.com/openGemini/openGemini/lib/netstorage/data"
 	"github.com/openGemini/openGemini/open_src/influx/meta"
 )
@@ -366,3 +367,150 @@ func (r *CreateDataBaseResponse) Error() error {
 	}
 	return fmt.Errorf("%s", *r.Err)
 }
+
+type MigratePtType uint8
+
+// the steps of moving a db pt from the src node to the dst node
+const (
+	MigratePtPreOffload         MigratePtType = iota + 1 // src stops serving the pt and keeps its files
+	MigratePtRollbackPreOffload                          // src serves the pt again
+	MigratePtPreAssign                                   // dst copies the files of the pt from src
+	MigratePtOffload                                     // src deletes the files of the pt
+	MigratePtAssign                                      // dst opens the copied pt
+)
+
+// MigratePtRequest asks a store node to execute one step of moving a db pt
+type MigratePtRequest struct {
+	Type MigratePtType
+	Db   string
+	PtId uint32
+	OpId uint64
+
+	// the node which owned the pt before the move, dst pulls the files from it
+	SrcNodeId uint64
+
+	// the shards of the pt, dst opens them on assign
+	Durations []*meta.ShardDurationInfo
+}
+
+func (r *MigratePtRequest) MarshalBinary() ([]byte, error) {
+	buf := codec.AppendUint8(nil, uint8(r.Type))
+	buf = codec.AppendString(buf, r.Db)
+	buf = codec.AppendUint32(buf, r.PtId)
+	buf = codec.AppendUint64(buf, r.OpId)
+	buf = codec.AppendUint64(buf, r.SrcNodeId)
+	buf = codec.AppendUint32(buf, uint32(len(r.Durations)))
+	for _, d := range r.Durations {
+		b, err := d.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf = codec.AppendBytes(buf, b)
+	}
+	return buf, nil
+}
+
+func (r *MigratePtRequest) UnmarshalBinary(buf []byte) error {
+	dec := codec.NewBinaryDecoder(buf)
+	r.Type = MigratePtType(dec.Uint8())
+	r.Db = dec.String()
+	r.PtId = dec.Uint32()
+	r.OpId = dec.Uint64()
+	r.SrcNodeId = dec.Uint64()
+	n := int(dec.Uint32())
+	r.Durations = make([]*meta.ShardDurationInfo, 0, n)
+	for i := 0; i < n; i++ {
+		d := &meta.ShardDurationInfo{}
+		if err := d.UnmarshalBinary(dec.Bytes()); err != nil {
+			return err
+		}
+		r.Durations = append(r.Durations, d)
+	}
+	return nil
+}
+
+// DurationInfos returns the shard durations of the request keyed by the shard id
+func (r *MigratePtRequest) DurationInfos() map[uint64]*meta.ShardDurationInfo {
+	infos := make(map[uint64]*meta.ShardDurationInfo, len(r.Durations))
+	for _, d := range r.Durations {
+		infos[d.Ident.ShardID] = d
+	}
+	return infos
+}
+
+type MigratePtResponse struct {
+	Err string
+}
+
+func (r *MigratePtResponse) MarshalBinary() ([]byte, error) {
+	return codec.AppendString(nil, r.Err), nil
+}
+
+func (r *MigratePtResponse) UnmarshalBinary(buf []byte) error {
+	r.Err = codec.NewBinaryDecoder(buf).String()
+	return nil
+}
+
+func (r *MigratePtResponse) Error() error {
+	if r.Err == "" {
+		return nil
+	}
+	return errors.New(r.Err)
+}
+
+// PtFilesRequest lists the files of an offloaded db pt if Name is empty,
+// otherwise it reads Size bytes of the file Name from Offset
+type PtFilesRequest struct {
+	Db     string
+	PtId   uint32
+	Name   string
+	Offset int64
+	Size   uint32
+}
+
+func (r *PtFilesRequest) MarshalBinary() ([]byte, error) {
+	buf := codec.AppendString(nil, r.Db)
+	buf = codec.AppendUint32(buf, r.PtId)
+	buf = codec.AppendString(buf, r.Name)
+	buf = codec.AppendInt64(buf, r.Offset)
+	buf = codec.AppendUint32(buf, r.Size)
+	return buf, nil
+}
+
+func (r *PtFilesRequest) UnmarshalBinary(buf []byte) error {
+	dec := codec.NewBinaryDecoder(buf)
+	r.Db = dec.String()
+	r.PtId = dec.Uint32()
+	r.Name = dec.String()
+	r.Offset = dec.Int64()
+	r.Size = dec.Uint32()
+	return nil
+}
+
+type PtFilesResponse struct {
+	Files []string
+	Data  []byte
+	Err   string
+}
+
+func (r *PtFilesResponse) MarshalBinary() ([]byte, error) {
+	buf := codec.AppendStringSlice(nil, r.Files)
+	buf = codec.AppendBytes(buf, r.Data)
+	buf = codec.AppendString(buf, r.Err)
+	return buf, nil
+}
+
+func (r *PtFilesResponse) UnmarshalBinary(buf []byte) error {
+	dec := codec.NewBinaryDecoder(buf)
+	r.Files = dec.StringSlice()
+	r.Data = dec.Bytes()
+	r.Err = dec.String()
+	return nil
+}
+
+func (r *PtFilesResponse) Error() error {
+	if r.Err == "" {
+		return nil
+	}
+	return errors.New(r.Err)
+}
@@ -64,6 +64,9 @@ type Storage interface {
 	DeleteDatabase(node *meta2.DataNode, database string, pt uint32) error
 	DeleteRetentionPolicy(node *meta2.DataNode, db string, rp string, pt uint32) error
 	DeleteMeasurement(node *meta2.DataNode, db string, rp string, name string, shardIds []uint64) error
+
+	MigratePt(node *meta2.DataNode, req *MigratePtRequest) error
+	PtFiles(node *meta2.DataNode, req *PtFilesRequest) (*PtFilesResponse, error)
 }
 
 type NetStorage struct {
@@ -154,6 +157,34 @@ func (s *NetStorage) DeleteDatabase(node *meta2.DataNode, database string, pt ui
 	return s.HandleDeleteReq(node, deleteReq)
 }
 
+func (s *NetStorage) MigratePt(node *meta2.DataNode, req *MigratePtRequest) error {
+	v, err := s.ddlRequestWithNode(node, MigratePtRequestMessage, req)
+	if err != nil {
+		return err
+	}
+
+	resp, ok := v.(*MigratePtResponse)
+	if !ok {
+		return executor.NewInvalidTypeError("*netstorage.MigratePtResponse", v)
+	}
+
+	return resp.Error()
+}
+
+func (s *NetStorage) PtFiles(node *meta2.DataNode, req *PtFilesRequest) (*PtFilesResponse, error) {
+	v, err := s.ddlRequestWithNode(node, PtFilesRequestMessage, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := v.(*PtFilesResponse)
+	if !ok {
+		return nil, executor.NewInvalidTypeError("*netstorage.PtFilesResponse", v)
+	}
+
+	return resp, resp.Error()
+}
+
 func (s *NetStorage) WriteRows(nodeID uint64, database string, rpName string, pt uint32, shard uint64, rows *[]influx.Row, timeout time.Duration) error {
 	if len(*rows) == 0 {
 		return nil
@@ -0,0 +1,291 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultcache
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Entry holds the result rows of a GROUP BY time() query for the complete
+// buckets in [Start, End). The entries in the cache are never modified.
+type Entry struct {
+	Database string
+
+	// Measurements read by the query, empty if they are matched by a regular
+	// expression.
+	Measurements []string
+
+	Start, End int64
+	Rows       models.Rows
+
+	// Created is the time the oldest bucket of the entry was queried, an
+	// entry extended by later buckets keeps it. Zero means now.
+	Created time.Time
+
+	key  string
+	size int64
+}
+
+// Slice returns a copy of the rows in [start, end).
+func (e *Entry) Slice(start, end int64) models.Rows {
+	return sliceRows(e.Rows, start, end)
+}
+
+func (e *Entry) matches(db, measurement string, min, max int64) bool {
+	if e.Database != db || max < e.Start || min >= e.End {
+		return false
+	}
+	if measurement == "" || len(e.Measurements) == 0 {
+		return true
+	}
+	for _, m := range e.Measurements {
+		if m == measurement {
+			return true
+		}
+	}
+	return false
+}
+
+// Cache is a LRU cache of the query results bounded by the memory size. The
+// entries older than the max age are dropped, as the writes through other
+// ts-sql nodes do not invalidate them.
+type Cache struct {
+	maxSize int64
+	maxAge  time.Duration
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List // the most recently used entry is at the front
+	entries map[string]*list.Element
+
+	// recent invalidations, checked by Put for the queries running meanwhile
+	gen           uint64
+	invalidations [maxInvalidations]invalidation
+
+	n      int64
+	hits   int64
+	misses int64
+}
+
+const maxInvalidations = 1024
+
+type invalidation struct {
+	db, measurement string
+	min, max        int64
+}
+
+// NewCache returns a cache of maxSize bytes, the entries never expire if maxAge is 0.
+func NewCache(maxSize int64, maxAge time.Duration) *Cache {
+	return &Cache{
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry of the query key, nil if it is not cached.
+func (c *Cache) Get(key string) *Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if ok && c.expired(elem.Value.(*Entry)) {
+		c.remove(elem)
+		ok = false
+	}
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil
+	}
+	atomic.AddInt64(&c.hits, 1)
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*Entry)
+}
+
+// Generation returns the current generation of the cache, it is captured
+// before the query of an entry runs and passed to Put.
+func (c *Cache) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// Put caches the rows of e in [e.Start, e.End) and evicts the least recently
+// used entries beyond the memory limit. The rows are copied. The entry is
+// dropped if an invalidation after the generation gen matches it.
+func (c *Cache) Put(key string, e *Entry, gen uint64) {
+	entry := &Entry{
+		Database:     e.Database,
+		Measurements: e.Measurements,
+		Start:        e.Start,
+		End:          e.End,
+		Rows:         sliceRows(e.Rows, e.Start, e.End),
+		Created:      e.Created,
+		key:          key,
+	}
+	if entry.Created.IsZero() {
+		entry.Created = time.Now()
+	}
+	entry.size = entrySize(entry)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	if entry.size > c.maxSize || c.expired(entry) || c.invalidatedSince(entry, gen) {
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.size += entry.size
+	atomic.AddInt64(&c.n, 1)
+	for c.size > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) expired(e *Entry) bool {
+	return c.maxAge > 0 && time.Since(e.Created) > c.maxAge
+}
+
+func (c *Cache) invalidatedSince(e *Entry, gen uint64) bool {
+	if c.gen-gen > maxInvalidations {
+		return true
+	}
+	for g := gen; g < c.gen; g++ {
+		inv := &c.invalidations[g%maxInvalidations]
+		if e.matches(inv.db, inv.measurement, inv.min, inv.max) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*Entry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+	atomic.AddInt64(&c.n, -1)
+}
+
+// Invalidate drops the entries of the database which read the measurement and
+// cache a bucket in [min, max]. An empty measurement matches all of them.
+func (c *Cache) Invalidate(db, measurement string, min, max int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidations[c.gen%maxInvalidations] = invalidation{db: db, measurement: measurement, min: min, max: max}
+	c.gen++
+	if len(c.entries) == 0 {
+		return
+	}
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*Entry).matches(db, measurement, min, max) {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+// InvalidateDatabase drops all the entries of the database.
+func (c *Cache) InvalidateDatabase(db string) {
+	c.Invalidate(db, "", models.MinNanoTime, models.MaxNanoTime)
+}
+
+// Len returns the number of cached entries.
+func (c *Cache) Len() int {
+	return int(atomic.LoadInt64(&c.n))
+}
+
+// Size returns the estimated memory size of the cached entries.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Stats returns the number of cache hits and misses.
+func (c *Cache) Stats() (hits, misses int64) {
+	return atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses)
+}
+
+// RowTime returns the time of a result row value, the first column.
+func RowTime(values []interface{}) int64 {
+	switch t := values[0].(type) {
+	case time.Time:
+		return t.UnixNano()
+	case int64:
+		return t
+	default:
+		return models.MinNanoTime
+	}
+}
+
+// sliceRows copies the values in [start, end) of every row, the values are
+// sorted by time.
+func sliceRows(rows models.Rows, start, end int64) models.Rows {
+	dst := make(models.Rows, 0, len(rows))
+	for _, row := range rows {
+		lo := sort.Search(len(row.Values), func(i int) bool { return RowTime(row.Values[i]) >= start })
+		hi := sort.Search(len(row.Values), func(i int) bool { return RowTime(row.Values[i]) >= end })
+		if lo >= hi {
+			continue
+		}
+		values := make([][]interface{}, 0, hi-lo)
+		for _, v := range row.Values[lo:hi] {
+			values = append(values, append([]interface{}(nil), v...))
+		}
+		dst = append(dst, &models.Row{
+			Name:    row.Name,
+			Tags:    row.Tags,
+			Columns: row.Columns,
+			Values:  values,
+		})
+	}
+	return dst
+}
+
+const (
+	rowOverhead   = 128
+	valueOverhead = 16
+)
+
+// entrySize estimates the memory used by the entry.
+func entrySize(e *Entry) int64 {
+	size := int64(len(e.key))
+	for _, row := range e.Rows {
+		size += rowOverhead + int64(len(row.Name))
+		for k, v := range row.Tags {
+			size += int64(len(k) + len(v))
+		}
+		for _, values := range row.Values {
+			size += 24 + int64(len(values))*valueOverhead
+			for _, v := range values {
+				if s, ok := v.(string); ok {
+					size += int64(len(s))
+				}
+			}
+		}
+	}
+	return size
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultcache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/openGemini/openGemini/lib/resultcache"
+	"github.com/stretchr/testify/require"
+)
+
+func buildRows(name string, start, end, step int64) models.Rows {
+	row := &models.Row{Name: name, Columns: []string{"time", "mean"}}
+	for t := start; t < end; t += step {
+		row.Values = append(row.Values, []interface{}{time.Unix(0, t).UTC(), float64(t)})
+	}
+	return models.Rows{row}
+}
+
+func TestCache_PutGet(t *testing.T) {
+	c := resultcache.NewCache(1<<20, 0)
+	require.Nil(t, c.Get("q"))
+
+	rows := buildRows("cpu", 0, 100, 10)
+	c.Put("q", &resultcache.Entry{Database: "db0", Measurements: []string{"cpu"}, Start: 20, End: 60, Rows: rows}, c.Generation())
+	require.Equal(t, 1, c.Len())
+
+	e := c.Get("q")
+	require.NotNil(t, e)
+	require.Equal(t, 4, len(e.Rows[0].Values))
+	require.Equal(t, int64(20), resultcache.RowTime(e.Rows[0].Values[0]))
+
+	// the slice is a copy
+	s := e.Slice(30, 50)
+	require.Equal(t, 2, len(s[0].Values))
+	s[0].Values[0][1] = "modified"
+	require.Equal(t, float64(30), e.Rows[0].Values[1][1])
+	rows[0].Values[2][1] = "modified"
+	require.Equal(t, float64(20), e.Rows[0].Values[0][1])
+
+	require.Equal(t, 0, len(e.Slice(60, 100)))
+	hits, misses := c.Stats()
+	require.Equal(t, int64(1), hits)
+	require.Equal(t, int64(1), misses)
+}
+
+func TestCache_Evict(t *testing.T) {
+	c := resultcache.NewCache(800, 0)
+	for _, key := range []string{"a", "b", "c"} {
+		c.Put(key, &resultcache.Entry{Database: "db0", Start: 0, End: 40, Rows: buildRows("cpu", 0, 40, 10)}, c.Generation())
+	}
+	require.Nil(t, c.Get("a"))
+	require.NotNil(t, c.Get("b"))
+	require.NotNil(t, c.Get("c"))
+	require.True(t, c.Size() <= 800)
+
+	// b is the most recently used
+	c.Get("b")
+	c.Put("d", &resultcache.Entry{Database: "db0", Start: 0, End: 40, Rows: buildRows("cpu", 0, 40, 10)}, c.Generation())
+	require.Nil(t, c.Get("c"))
+	require.NotNil(t, c.Get("b"))
+
+	// larger than the cache
+	c.Put("e", &resultcache.Entry{Database: "db0", Start: 0, End: 1000, Rows: buildRows("cpu", 0, 1000, 1)}, c.Generation())
+	require.Nil(t, c.Get("e"))
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	c := resultcache.NewCache(1<<20, 0)
+	put := func(key, db string, msts []string) {
+		c.Put(key, &resultcache.Entry{Database: db, Measurements: msts, Start: 0, End: 100, Rows: buildRows("cpu", 0, 100, 10)}, c.Generation())
+	}
+	put("cpu", "db0", []string{"cpu"})
+	put("mem", "db0", []string{"mem"})
+	put("regex", "db0", nil)
+	put("db1", "db1", []string{"cpu"})
+
+	// the write is after the cached buckets
+	c.Invalidate("db0", "cpu", 100, 200)
+	require.Equal(t, 4, c.Len())
+
+	c.Invalidate("db0", "cpu", 50, 200)
+	require.Nil(t, c.Get("cpu"))
+	require.Nil(t, c.Get("regex"))
+	require.NotNil(t, c.Get("mem"))
+	require.NotNil(t, c.Get("db1"))
+
+	c.InvalidateDatabase("db0")
+	require.Nil(t, c.Get("mem"))
+	require.Equal(t, 1, c.Len())
+}
+
+func TestCache_InvalidateWhileQuerying(t *testing.T) {
+	c := resultcache.NewCache(1<<20, 0)
+	entry := &resultcache.Entry{Database: "db0", Measurements: []string{"cpu"}, Start: 0, End: 100, Rows: buildRows("cpu", 0, 100, 10)}
+
+	gen := c.Generation()
+	c.Invalidate("db0", "cpu", 200, 300)
+	c.Put("q", entry, gen)
+	require.NotNil(t, c.Get("q"))
+
+	gen = c.Generation()
+	c.Invalidate("db0", "cpu", 50, 50)
+	c.Put("q", entry, gen)
+	require.Nil(t, c.Get("q"))
+
+	gen = c.Generation()
+	for i := 0; i < 2000; i++ {
+		c.Invalidate("db1", "cpu", 0, 100)
+	}
+	c.Put("q", entry, gen)
+	require.Nil(t, c.Get("q"))
+}
+
+func TestCache_MaxAge(t *testing.T) {
+	c := resultcache.NewCache(1<<20, time.Hour)
+	entry := &resultcache.Entry{Database: "db0", Measurements: []string{"cpu"}, Start: 0, End: 100, Rows: buildRows("cpu", 0, 100, 10)}
+	c.Put("q", entry, c.Generation())
+	require.NotNil(t, c.Get("q"))
+
+	// an entry extended from an expired one keeps its creation time
+	entry.Created = time.Now().Add(-2 * time.Hour)
+	c.Put("q", entry, c.Generation())
+	require.Nil(t, c.Get("q"))
+	require.Equal(t, 0, c.Len())
+
+	entry.Created = time.Now().Add(-time.Minute)
+	c.Put("q", entry, c.Generation())
+	e := c.Get("q")
+	require.NotNil(t, e)
+	require.Equal(t, entry.Created, e.Created)
+}
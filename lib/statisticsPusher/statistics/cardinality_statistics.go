@@ -0,0 +1,128 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statistics
+
+import (
+	"sync"
+)
+
+const (
+	cardinalityStatisticsName = "cardinality"
+
+	StatCardinalityDatabase      = "database"
+	StatCardinalitySeries        = "series"
+	StatCardinalitySeriesLimit   = "seriesLimit"
+	StatCardinalityUsagePercent  = "usagePercent"
+	StatCardinalityRejectSeries  = "rejectedSeries"
+	StatCardinalityRejectTagVals = "rejectedTagValues"
+)
+
+var cardinalityStat = NewCardinalityStatistics()
+
+// CardinalityStatistics keeps the series usage of every database with cardinality limits,
+// the series are summed over all the indexes of the database on the node like the limits.
+type CardinalityStatistics struct {
+	mu   sync.Mutex
+	tags map[string]string
+	dbs  map[string]*dbCardinality
+}
+
+type dbCardinality struct {
+	series            int64
+	seriesLimit       int64
+	rejectedSeries    int64
+	rejectedTagValues int64
+}
+
+func NewCardinalityStatistics() *CardinalityStatistics {
+	return &CardinalityStatistics{
+		tags: make(map[string]string),
+		dbs:  make(map[string]*dbCardinality),
+	}
+}
+
+func NewCardinalityStat() *CardinalityStatistics {
+	return cardinalityStat
+}
+
+// Init only sets the global tags, indexes opened before may have reported their usage.
+func (s *CardinalityStatistics) Init(tags map[string]string) {
+	s.mu.Lock()
+	AllocTagMap(s.tags, tags)
+	s.mu.Unlock()
+}
+
+func (s *CardinalityStatistics) db(name string) *dbCardinality {
+	stat, ok := s.dbs[name]
+	if !ok {
+		stat = &dbCardinality{}
+		s.dbs[name] = stat
+	}
+	return stat
+}
+
+func (s *CardinalityStatistics) SetSeries(db string, series, limit int64) {
+	s.mu.Lock()
+	stat := s.db(db)
+	stat.series, stat.seriesLimit = series, limit
+	s.mu.Unlock()
+}
+
+func (s *CardinalityStatistics) RemoveDatabase(db string) {
+	s.mu.Lock()
+	delete(s.dbs, db)
+	s.mu.Unlock()
+}
+
+func (s *CardinalityStatistics) AddRejectedSeries(db string, n int64) {
+	s.mu.Lock()
+	s.db(db).rejectedSeries += n
+	s.mu.Unlock()
+}
+
+func (s *CardinalityStatistics) AddRejectedTagValues(db string, n int64) {
+	s.mu.Lock()
+	s.db(db).rejectedTagValues += n
+	s.mu.Unlock()
+}
+
+func (s *CardinalityStatistics) Collect(buffer []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, stat := range s.dbs {
+		series, limit := stat.series, stat.seriesLimit
+		var usage float64
+		if limit > 0 {
+			usage = float64(series) * 100 / float64(limit)
+		}
+
+		tagMap := make(map[string]string, len(s.tags)+1)
+		AllocTagMap(tagMap, s.tags)
+		tagMap[StatCardinalityDatabase] = name
+		valueMap := map[string]interface{}{
+			StatCardinalitySeries:        series,
+			StatCardinalitySeriesLimit:   limit,
+			StatCardinalityUsagePercent:  usage,
+			StatCardinalityRejectSeries:  stat.rejectedSeries,
+			StatCardinalityRejectTagVals: stat.rejectedTagValues,
+		}
+		buffer = AddPointToBuffer(cardinalityStatisticsName, tagMap, valueMap, buffer)
+	}
+
+	return buffer, nil
+}
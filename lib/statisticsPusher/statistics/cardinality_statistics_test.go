@@ -0,0 +1,55 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statistics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+)
+
+func TestCardinalityStatistics(t *testing.T) {
+	tags := map[string]string{
+		"hostname": "127.0.0.1:8400",
+		"app":      "ts-store",
+	}
+	stat := statistics.NewCardinalityStatistics()
+	stat.Init(tags)
+	stat.SetSeries("db0", 300, 1000)
+	stat.SetSeries("db0", 500, 1000)
+	stat.SetSeries("db1", 100, 1000)
+	stat.RemoveDatabase("db1")
+	stat.AddRejectedSeries("db0", 2)
+	stat.AddRejectedTagValues("db0", 1)
+	statistics.NewTimestamp().Init(time.Second)
+
+	buf, _ := stat.Collect(nil)
+
+	tags["database"] = "db0"
+	fields := map[string]interface{}{
+		"series":            int64(500),
+		"seriesLimit":       int64(1000),
+		"usagePercent":      float64(50),
+		"rejectedSeries":    int64(2),
+		"rejectedTagValues": int64(1),
+	}
+
+	if err := compareBuffer("cardinality", tags, fields, buf); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
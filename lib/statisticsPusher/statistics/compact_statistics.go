@@ -43,6 +43,17 @@ type CompactStatItem struct {
 	// File information after compact
 	CompactedFileCount int64
 	CompactedFileSize  int64
+
+	// Blocks written by each column codec
+	Codecs []CodecStatItem
+}
+
+// CodecStatItem is the size of the column blocks a codec encoded during a compaction
+type CodecStatItem struct {
+	Codec        string
+	Blocks       int64
+	OriginalSize int64
+	EncodedSize  int64
 }
 
 func NewCompactStatItem(mst string, shardID uint64) *CompactStatItem {
@@ -95,4 +106,25 @@ func (s *CompactStatistics) PushCompaction(item *CompactStatItem) {
 	tags["measurement"] = item.mst
 	tags["shard_id"] = strconv.FormatUint(item.shardID, 10)
 	s.buf = AddPointToBuffer("compact", tags, data, s.buf)
+	s.pushCodecs(item, tags)
+}
+
+func (s *CompactStatistics) pushCodecs(item *CompactStatItem, tags map[string]string) {
+	defer delete(tags, "codec")
+
+	for i := range item.Codecs {
+		codec := &item.Codecs[i]
+		if codec.OriginalSize == 0 {
+			continue
+		}
+
+		data := map[string]interface{}{
+			"Blocks":       codec.Blocks,
+			"OriginalSize": codec.OriginalSize,
+			"EncodedSize":  codec.EncodedSize,
+			"Ratio":        float64(codec.EncodedSize) / float64(codec.OriginalSize),
+		}
+		tags["codec"] = codec.Codec
+		s.buf = AddPointToBuffer("compact_codec", tags, data, s.buf)
+	}
 }
@@ -93,3 +93,43 @@ func TestPushCompaction(t *testing.T) {
 	assert.NoError(t, compareBuffer("compact", tags, fields, buf))
 	compareRowIndex = 0
 }
+
+func TestPushCompactionCodecs(t *testing.T) {
+	stat := statistics.NewCompactStatistics()
+	tags := map[string]string{"hostname": "127.0.0.1:8866"}
+	stat.Init(tags)
+	statistics.NewTimestamp().Init(time.Second)
+
+	item := statistics.NewCompactStatItem("cpu", 101)
+	item.OriginalFileCount = 2
+	item.OriginalFileSize = 1000
+	item.CompactedFileCount = 1
+	item.CompactedFileSize = 800
+	item.Level = 2
+	item.Codecs = []statistics.CodecStatItem{
+		{Codec: "gorilla", Blocks: 1, OriginalSize: 800, EncodedSize: 600},
+		{Codec: "alp", Blocks: 2, OriginalSize: 1600, EncodedSize: 400},
+	}
+	stat.PushCompaction(item)
+
+	buf, err := stat.Collect(nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tags["level"] = "2"
+	tags["action"] = "level"
+	tags["measurement"] = "cpu"
+	tags["shard_id"] = "101"
+	tags["codec"] = "alp"
+	fields := map[string]interface{}{
+		"Blocks":       int64(2),
+		"OriginalSize": int64(1600),
+		"EncodedSize":  int64(400),
+		"Ratio":        0.25,
+	}
+
+	compareRowIndex = 3
+	assert.NoError(t, compareBuffer("compact_codec", tags, fields, buf))
+	compareRowIndex = 0
+}
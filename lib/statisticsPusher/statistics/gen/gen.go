@@ -24,3 +24,9 @@ package gen
 
 //go:generate tmpl -data=@meta.data -o=../meta_statistics.gen.go statistics.tmpl
 //go:generate tmpl -data=@meta.data -o=../meta_statistics.gen_test.go statistics_test.tmpl
+
+//go:generate tmpl -data=@read.data -o=../read_statistics.gen.go statistics.tmpl
+//go:generate tmpl -data=@read.data -o=../read_statistics.gen_test.go statistics_test.tmpl
+
+//go:generate tmpl -data=@index_gc.data -o=../index_gc_statistics.gen.go statistics.tmpl
+//go:generate tmpl -data=@index_gc.data -o=../index_gc_statistics.gen_test.go statistics_test.tmpl
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statistics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+)
+
+func TestIndexGC(t *testing.T) {
+	stat := statistics.NewIndexGCStatistics()
+	tags := map[string]string{"hostname": "127.0.0.1:8866", "mst": "index_gc"}
+	stat.Init(tags)
+	stat.AddRebuildTotal(1)
+	stat.AddRebuiltSeries(10)
+	stat.AddMissingSeries(2)
+	stat.AddGCTotal(3)
+	stat.AddScannedSeries(100)
+	stat.AddOrphanSeries(7)
+	stat.AddDeletedSeries(5)
+	stat.AddErrors(1)
+	stat.SetPendingIndexGroups(4)
+
+	fields := map[string]interface{}{
+		"RebuildTotal":       int64(1),
+		"RebuiltSeries":      int64(10),
+		"MissingSeries":      int64(2),
+		"GCTotal":            int64(3),
+		"ScannedSeries":      int64(100),
+		"OrphanSeries":       int64(7),
+		"DeletedSeries":      int64(5),
+		"Errors":             int64(1),
+		"PendingIndexGroups": int64(4),
+	}
+	statistics.NewTimestamp().Init(time.Second)
+	buf, err := stat.Collect(nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := compareBuffer("index_gc", tags, fields, buf); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statistics
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	statListenerPointsReceived     = "pointsRx"           // Number of points received.
+	statListenerBytesReceived      = "bytesRx"            // Number of bytes received.
+	statListenerPointsParseFail    = "pointsParseFail"    // Number of points that failed to be parsed.
+	statListenerPointsWritten      = "pointsWritten"      // Number of points written.
+	statListenerPointsWriteFail    = "pointsWriteFail"    // Number of points that failed to be written.
+	statListenerBatchesWritten     = "batchesWritten"     // Number of batches written.
+	statListenerBatchesWriteFail   = "batchesWriteFail"   // Number of batches that failed to be written.
+	statListenerActiveConnections  = "activeConnections"  // Number of currently active connections.
+	statListenerHandledConnections = "handledConnections" // Number of connections handled.
+)
+
+// ListenerStatistics is the statistics of a protocol listener, such as graphite and opentsdb
+type ListenerStatistics struct {
+	PointsReceived     int64
+	BytesReceived      int64
+	PointsParseFail    int64
+	PointsWritten      int64
+	PointsWriteFail    int64
+	BatchesWritten     int64
+	BatchesWriteFail   int64
+	ActiveConnections  int64
+	HandledConnections int64
+
+	tags map[string]string
+}
+
+var ListenerTagMap map[string]string
+var ListenerStatisticsName = "listener"
+
+var listenerStats = struct {
+	mu    sync.RWMutex
+	items []*ListenerStatistics
+}{}
+
+func InitListenerStatistics(tags map[string]string) {
+	ListenerTagMap = tags
+}
+
+// NewListenerStatistics registers the statistics of a listener, it is collected until it is unregistered
+func NewListenerStatistics(protocol, bindAddress string) *ListenerStatistics {
+	s := &ListenerStatistics{
+		tags: map[string]string{"protocol": protocol, "bind": bindAddress},
+	}
+
+	listenerStats.mu.Lock()
+	listenerStats.items = append(listenerStats.items, s)
+	listenerStats.mu.Unlock()
+	return s
+}
+
+func UnregisterListenerStatistics(s *ListenerStatistics) {
+	listenerStats.mu.Lock()
+	defer listenerStats.mu.Unlock()
+	for i, item := range listenerStats.items {
+		if item == s {
+			listenerStats.items = append(listenerStats.items[:i], listenerStats.items[i+1:]...)
+			return
+		}
+	}
+}
+
+func CollectListenerStatistics(buffer []byte) ([]byte, error) {
+	listenerStats.mu.RLock()
+	defer listenerStats.mu.RUnlock()
+
+	for _, s := range listenerStats.items {
+		tags := make(map[string]string, len(ListenerTagMap)+len(s.tags))
+		for k, v := range ListenerTagMap {
+			tags[k] = v
+		}
+		for k, v := range s.tags {
+			tags[k] = v
+		}
+
+		valueMap := map[string]interface{}{
+			statListenerPointsReceived:     atomic.LoadInt64(&s.PointsReceived),
+			statListenerBytesReceived:      atomic.LoadInt64(&s.BytesReceived),
+			statListenerPointsParseFail:    atomic.LoadInt64(&s.PointsParseFail),
+			statListenerPointsWritten:      atomic.LoadInt64(&s.PointsWritten),
+			statListenerPointsWriteFail:    atomic.LoadInt64(&s.PointsWriteFail),
+			statListenerBatchesWritten:     atomic.LoadInt64(&s.BatchesWritten),
+			statListenerBatchesWriteFail:   atomic.LoadInt64(&s.BatchesWriteFail),
+			statListenerActiveConnections:  atomic.LoadInt64(&s.ActiveConnections),
+			statListenerHandledConnections: atomic.LoadInt64(&s.HandledConnections),
+		}
+		buffer = AddPointToBuffer(ListenerStatisticsName, tags, valueMap, buffer)
+	}
+	return buffer, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statistics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListenerStatistics(t *testing.T) {
+	ts := NewTimestamp()
+	ts.Init(time.Second)
+	InitListenerStatistics(map[string]string{"hostname": "127.0.0.1"})
+
+	s := NewListenerStatistics("graphite", ":2003")
+	s.PointsReceived = 3
+	s.PointsWritten = 2
+	s.PointsParseFail = 1
+
+	got, _ := CollectListenerStatistics(nil)
+	exp := fmt.Sprintf("listener,bind=:2003,hostname=127.0.0.1,protocol=graphite activeConnections=0,batchesWriteFail=0,"+
+		"batchesWritten=0,bytesRx=0,handledConnections=0,pointsParseFail=1,pointsRx=3,pointsWriteFail=0,pointsWritten=2 %s",
+		string(ts.Bytes()))
+	if sortPoint(string(got)) != sortPoint(exp) {
+		t.Fatalf("listener statistics failed. \nexp: \n%s \ngot: \n%s \n", exp, got)
+	}
+
+	UnregisterListenerStatistics(s)
+	got, _ = CollectListenerStatistics(nil)
+	if len(got) != 0 {
+		t.Fatalf("expect no statistics after unregister, got %s", got)
+	}
+}
+
+// sortPoint sorts the tags and the fields of a point
+func sortPoint(point string) string {
+	parts := strings.Split(strings.TrimSpace(point), " ")
+	for i := 0; i < 2 && i < len(parts); i++ {
+		items := strings.Split(parts[i], ",")
+		sort.Strings(items)
+		parts[i] = strings.Join(items, ",")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statistics
+
+import (
+	"sync/atomic"
+)
+
+type ReadStatistics struct {
+	itemSkippedChunks   int64
+	itemSkippedSegments int64
+
+	tags map[string]string
+}
+
+var instanceReadStatistics = &ReadStatistics{}
+
+func NewReadStatistics() *ReadStatistics {
+	return instanceReadStatistics
+}
+
+func (s *ReadStatistics) Init(tags map[string]string) {
+	s.tags = make(map[string]string)
+	for k, v := range tags {
+		s.tags[k] = v
+	}
+}
+
+func (s *ReadStatistics) Collect(buffer []byte) ([]byte, error) {
+	data := map[string]interface{}{
+		"SkippedChunks":   s.itemSkippedChunks,
+		"SkippedSegments": s.itemSkippedSegments,
+	}
+
+	buffer = AddPointToBuffer("read", s.tags, data, buffer)
+
+	return buffer, nil
+}
+
+func (s *ReadStatistics) AddSkippedChunks(i int64) {
+	atomic.AddInt64(&s.itemSkippedChunks, i)
+}
+
+func (s *ReadStatistics) AddSkippedSegments(i int64) {
+	atomic.AddInt64(&s.itemSkippedSegments, i)
+}
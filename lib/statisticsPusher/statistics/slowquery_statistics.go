@@ -18,6 +18,7 @@ package statistics
 
 import (
 	"sync/atomic"
+	"time"
 )
 
 const (
@@ -50,6 +51,21 @@ var SlowQueryStatisticsName = "slow_queries"
 var SqlSlowQueryStatisticsName = "sql_slow_queries"
 var SlowQueries chan *SQLSlowQueryStatistics
 
+// slowQueryThreshold is the duration in nanoseconds above which a query is reported as slow
+var slowQueryThreshold = int64(10 * time.Second)
+
+// SetSlowQueryThreshold changes the duration above which a query is reported as slow,
+// the threshold is not changed if d is not positive.
+func SetSlowQueryThreshold(d time.Duration) {
+	if d > 0 {
+		atomic.StoreInt64(&slowQueryThreshold, int64(d))
+	}
+}
+
+func SlowQueryThreshold() time.Duration {
+	return time.Duration(atomic.LoadInt64(&slowQueryThreshold))
+}
+
 func NewSqlSlowQueryStatistics() *SQLSlowQueryStatistics {
 	SlowQueries = make(chan *SQLSlowQueryStatistics, 256)
 	return &SQLSlowQueryStatistics{}
@@ -51,6 +51,8 @@ const (
 	SuccessCreateSessionTotal
 	FailedCreateSessionTotal
 	ClosedSessionTotal
+	RawBytesTotal
+	CompressedBytesTotal
 	ItemEnd
 )
 
@@ -69,6 +71,8 @@ var items = [ItemEnd]string{
 	SuccessCreateSessionTotal: "successCreateSessionTotal",
 	FailedCreateSessionTotal:  "failedCreateSessionTotal",
 	ClosedSessionTotal:        "closedSessionTotal",
+	RawBytesTotal:             "rawBytesTotal",
+	CompressedBytesTotal:      "compressedBytesTotal",
 }
 
 type SpdyItem uint8
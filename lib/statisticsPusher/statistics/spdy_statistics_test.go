@@ -48,14 +48,18 @@ func TestSpdyStatistics(t *testing.T) {
 	job2.SetItem(FailedConnTotal)
 	job2.SetValue(20)
 	ss.Add(job2)
+	ss.Add(job.Clone().SetAddr("127.0.0.1:8402").SetItem(RawBytesTotal).SetValue(4096))
+	ss.Add(job.Clone().SetAddr("127.0.0.1:8403").SetItem(CompressedBytesTotal).SetValue(1024))
 
 	time.Sleep(time.Second)
 
 	got := sortBuf(string(ss.CollectBuf()), "\n", true)
 	exp := fmt.Sprintf(`spdy,link=sql2meta,remote_addr=127.0.0.1:8092 connTotal=1 %s
 spdy,link=meta2meta,remote_addr=127.0.0.1:8091 closedConnTotal=1 %s
-spdy,link=sql2store,remote_addr=127.0.0.1:8401 failedConnTotal=20 %s`,
-		string(ts.Bytes()), string(ts.Bytes()), string(ts.Bytes()))
+spdy,link=sql2store,remote_addr=127.0.0.1:8401 failedConnTotal=20 %s
+spdy,link=sql2store,remote_addr=127.0.0.1:8402 rawBytesTotal=4096 %s
+spdy,link=sql2store,remote_addr=127.0.0.1:8403 compressedBytesTotal=1024 %s`,
+		string(ts.Bytes()), string(ts.Bytes()), string(ts.Bytes()), string(ts.Bytes()), string(ts.Bytes()))
 
 	exp = sortBuf(exp, "\n", true)
 
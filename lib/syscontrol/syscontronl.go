@@ -50,6 +50,8 @@ curl -i -XPOST 'https://127.0.0.1:8086/debug/ctrl?mod=snapshot&flushduration=5m'
 curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=compen&switchon=true&allshards=true&shid=4'
 curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=merge&switchon=true&allshards=true&shid=4'
 curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=snapshot&duration=30m'
+curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=rebuild_index&db=db0&pt=1&indexid=3'
+curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=index_gc'
 
 curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=readonly&switchon=true&allnodes=y'
 curl -i -XPOST 'http://127.0.0.1:8086/debug/ctrl?mod=readonly&switchon=true&host=127.0.0.1'
@@ -76,6 +78,8 @@ const (
 	Failpoint           = "failpoint"
 	Readonly            = "readonly"
 	LogRows             = "log_rows"
+	RebuildIndex        = "rebuild_index"
+	IndexGC             = "index_gc"
 )
 
 var (
@@ -128,7 +132,7 @@ func SetLogRowsRuleSwitch(switchon bool, rules string) error {
 
 func ProcessRequest(req netstorage.SysCtrlRequest, resp *strings.Builder) (err error) {
 	switch req.Mod() {
-	case DataFlush, compactionEn, compmerge, snapshot, Failpoint:
+	case DataFlush, compactionEn, compmerge, snapshot, Failpoint, RebuildIndex, IndexGC:
 		// store SysCtrl cmd
 		dataNodes, err := SysCtrl.MetaClient.DataNodes()
 		if err != nil {
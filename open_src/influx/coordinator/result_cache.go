@@ -0,0 +1,491 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coordinator
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/resultcache"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	query2 "github.com/openGemini/openGemini/open_src/influx/query"
+	"go.uber.org/zap"
+)
+
+// cacheableCalls are the aggregates computed from the points of a single
+// bucket, so the buckets of a result can be cached and merged.
+var cacheableCalls = map[string]struct{}{
+	"count":      {},
+	"sum":        {},
+	"mean":       {},
+	"median":     {},
+	"mode":       {},
+	"min":        {},
+	"max":        {},
+	"first":      {},
+	"last":       {},
+	"spread":     {},
+	"stddev":     {},
+	"percentile": {},
+}
+
+// resultCachePlan splits the time range of a GROUP BY time() query into the
+// buckets read from the result cache and the ones queried from the stores.
+type resultCachePlan struct {
+	key          string
+	stmt         *influxql.SelectStatement
+	database     string
+	measurements []string
+
+	interval int64
+	offset   int64
+
+	// time range of the query, [min, max)
+	min, max int64
+
+	// first bucket fully covered by the time range
+	first int64
+
+	// end of the buckets old enough to be cached
+	stableEnd int64
+}
+
+func newResultCachePlan(stmt *influxql.SelectStatement, now time.Time, recent time.Duration) *resultCachePlan {
+	if stmt.Target != nil || stmt.IsRawQuery || stmt.Location != nil ||
+		stmt.Limit > 0 || stmt.Offset > 0 || stmt.SLimit > 0 || stmt.SOffset > 0 ||
+		!stmt.TimeAscending() || stmt.Fill == influxql.PreviousFill || stmt.Fill == influxql.LinearFill {
+		return nil
+	}
+	interval, err := stmt.GroupByInterval()
+	if err != nil || interval <= 0 {
+		return nil
+	}
+	offset, err := stmt.GroupByOffset()
+	if err != nil {
+		return nil
+	}
+
+	p := &resultCachePlan{stmt: stmt, interval: int64(interval), offset: int64(offset)}
+	regex := false
+	for _, source := range stmt.Sources {
+		m, ok := source.(*influxql.Measurement)
+		if !ok || m.Database == "" || m.SystemIterator != "" || (p.database != "" && p.database != m.Database) {
+			return nil
+		}
+		p.database = m.Database
+		if m.Regex != nil {
+			regex = true
+		} else {
+			p.measurements = append(p.measurements, m.Name)
+		}
+	}
+	if regex {
+		p.measurements = nil
+	}
+	if !cacheableFields(stmt.Fields) {
+		return nil
+	}
+
+	_, tr, err := influxql.ConditionExpr(stmt.Condition, &influxql.NowValuer{Now: now})
+	if err != nil || tr.Min.IsZero() {
+		return nil
+	}
+	p.min = tr.Min.UnixNano()
+	p.max = now.UnixNano() + 1
+	if !tr.Max.IsZero() {
+		p.max = tr.Max.UnixNano() + 1
+	}
+	if p.min >= p.max {
+		return nil
+	}
+
+	p.first = p.bucketStart(p.min)
+	if p.first < p.min {
+		p.first += p.interval
+	}
+	p.stableEnd = p.bucketStart(now.Add(-recent).UnixNano())
+	if end := p.bucketStart(p.max); end < p.stableEnd {
+		p.stableEnd = end
+	}
+	key := stmt.Clone()
+	key.Condition = withoutTimeCondition(key.Condition)
+	p.key = key.String()
+	return p
+}
+
+func cacheableFields(fields influxql.Fields) bool {
+	calls := 0
+	ok := true
+	for _, f := range fields {
+		influxql.WalkFunc(f.Expr, func(n influxql.Node) {
+			if call, isCall := n.(*influxql.Call); isCall {
+				calls++
+				if _, found := cacheableCalls[call.Name]; !found {
+					ok = false
+				}
+			}
+		})
+	}
+	return ok && calls > 0
+}
+
+func (p *resultCachePlan) bucketStart(t int64) int64 {
+	mod := (t - p.offset) % p.interval
+	if mod < 0 {
+		mod += p.interval
+	}
+	return t - mod
+}
+
+// statement returns the query restricted to the time range [start, end).
+func (p *resultCachePlan) statement(start, end int64) *influxql.SelectStatement {
+	stmt := p.stmt.Clone()
+	timeRange := &influxql.BinaryExpr{
+		Op:  influxql.AND,
+		LHS: &influxql.BinaryExpr{Op: influxql.GTE, LHS: &influxql.VarRef{Val: "time"}, RHS: &influxql.TimeLiteral{Val: time.Unix(0, start).UTC()}},
+		RHS: &influxql.BinaryExpr{Op: influxql.LT, LHS: &influxql.VarRef{Val: "time"}, RHS: &influxql.TimeLiteral{Val: time.Unix(0, end).UTC()}},
+	}
+	if cond := withoutTimeCondition(stmt.Condition); cond != nil {
+		stmt.Condition = &influxql.BinaryExpr{Op: influxql.AND, LHS: &influxql.ParenExpr{Expr: cond}, RHS: timeRange}
+	} else {
+		stmt.Condition = timeRange
+	}
+	return stmt
+}
+
+// withoutTimeCondition removes the time comparisons from the condition.
+func withoutTimeCondition(cond influxql.Expr) influxql.Expr {
+	if cond == nil {
+		return nil
+	}
+	isTime := func(e influxql.Expr) bool {
+		ref, ok := e.(*influxql.VarRef)
+		return ok && ref.Val == "time"
+	}
+	expr := influxql.RewriteExpr(influxql.CloneExpr(cond), func(e influxql.Expr) influxql.Expr {
+		if b, ok := e.(*influxql.BinaryExpr); ok && (isTime(b.LHS) || isTime(b.RHS)) {
+			return &influxql.BooleanLiteral{Val: true}
+		}
+		return e
+	})
+	expr = influxql.Reduce(expr, nil)
+	if lit, ok := expr.(*influxql.BooleanLiteral); ok && lit.Val {
+		return nil
+	}
+	return expr
+}
+
+func (p *resultCachePlan) entry(rows models.Rows, end int64, created time.Time) *resultcache.Entry {
+	return &resultcache.Entry{
+		Database:     p.database,
+		Measurements: p.measurements,
+		Start:        p.first,
+		End:          end,
+		Rows:         rows,
+		Created:      created,
+	}
+}
+
+// executeCachedSelect answers a GROUP BY time() query from the cached buckets,
+// only the buckets missing from the cache are queried from the stores.
+func (e *StatementExecutor) executeCachedSelect(p *resultCachePlan, ctx *query2.ExecutionContext) error {
+	rows, err := e.cachedSelectRows(p, ctx)
+	if err != nil {
+		return err
+	}
+	if rows == nil {
+		rows = make(models.Rows, 0)
+	}
+	return ctx.Send(&query.Result{Series: rows})
+}
+
+func (e *StatementExecutor) cachedSelectRows(p *resultCachePlan, ctx *query2.ExecutionContext) (models.Rows, error) {
+	// the cache is shared by all users, the privileges are checked before the
+	// cached rows are returned as the stores may not be queried at all
+	if err := query2.AuthorizeMeasurements(p.stmt, ctx.Authorizer); err != nil {
+		return nil, err
+	}
+
+	// the invalidations while the stores are queried discard the new entry
+	gen := e.ResultCache.Generation()
+
+	if entry := e.ResultCache.Get(p.key); entry != nil && entry.Start <= p.first {
+		cachedEnd := entry.End
+		if end := p.bucketStart(p.max); end < cachedEnd {
+			cachedEnd = end
+		}
+		if cachedEnd > p.first {
+			rows, ok, err := e.mergeCachedRows(p, ctx, entry, cachedEnd)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if p.stableEnd > entry.End {
+					e.ResultCache.Put(p.key, p.entry(rows, p.stableEnd, entry.Created), gen)
+				}
+				return rows, nil
+			}
+		}
+	}
+
+	rows, err := e.selectRows(p.statement(p.min, p.max), ctx)
+	if err != nil {
+		return nil, err
+	}
+	if p.stableEnd > p.first {
+		e.ResultCache.Put(p.key, p.entry(rows, p.stableEnd, time.Time{}), gen)
+	}
+	return rows, nil
+}
+
+// mergeCachedRows queries the partial first bucket and the buckets after the
+// cached ones, and merges them with the cached buckets. It returns false if the
+// series differ, so the buckets filled by the query would be missing.
+func (e *StatementExecutor) mergeCachedRows(p *resultCachePlan, ctx *query2.ExecutionContext, entry *resultcache.Entry, cachedEnd int64) (models.Rows, bool, error) {
+	parts := make([]models.Rows, 0, 3)
+	if p.min < p.first {
+		head, err := e.selectRows(p.statement(p.min, p.first), ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		parts = append(parts, head)
+	}
+	parts = append(parts, entry.Slice(p.first, cachedEnd))
+	if cachedEnd < p.max {
+		tail, err := e.selectRows(p.statement(cachedEnd, p.max), ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		parts = append(parts, tail)
+	}
+	rows, ok := mergeRows(p.stmt.Fill != influxql.NoFill, parts...)
+	return rows, ok, nil
+}
+
+// mergeRows merges the rows of consecutive time ranges by series. If strict,
+// every part must hold the same series.
+func mergeRows(strict bool, parts ...models.Rows) (models.Rows, bool) {
+	var merged models.Rows
+	index := make(map[string]*models.Row)
+	seen := make([]map[string]struct{}, len(parts))
+	for i, part := range parts {
+		seen[i] = make(map[string]struct{}, len(part))
+		for _, row := range part {
+			key := row.Name + string(models.NewTags(row.Tags).HashKey())
+			seen[i][key] = struct{}{}
+			dst, ok := index[key]
+			if !ok {
+				dst = &models.Row{Name: row.Name, Tags: row.Tags, Columns: row.Columns}
+				index[key] = dst
+				merged = append(merged, dst)
+			} else if !equalColumns(dst.Columns, row.Columns) {
+				return nil, false
+			}
+			dst.Values = append(dst.Values, row.Values...)
+		}
+	}
+	if strict {
+		for i := range seen {
+			if len(seen[i]) != len(index) {
+				return nil, false
+			}
+		}
+	}
+	return merged, true
+}
+
+func equalColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// selectRows executes the select statement and collects the result rows.
+func (e *StatementExecutor) selectRows(stmt *influxql.SelectStatement, ctx *query2.ExecutionContext) (models.Rows, error) {
+	opt := ctx.ExecutionOptions
+	opt.RowsChan = make(chan query2.RowsChan)
+	pipelineExecutor, err := e.retryCreatePipelineExecutor(ctx, stmt, opt)
+	if err == influxql.ErrDeclareEmptyCollection {
+		err = nil
+		pipelineExecutor = nil
+	}
+	if err != nil || pipelineExecutor == nil {
+		close(opt.RowsChan)
+		return nil, err
+	}
+
+	ec := make(chan error, 1)
+	go func() {
+		err := pipelineExecutor.ExecuteExecutor(context.Background())
+		if err != nil && errno.Equal(err, errno.BucketLacks) {
+			close(opt.RowsChan)
+		}
+		ec <- err
+	}()
+
+	var rows models.Rows
+	for {
+		select {
+		case rc, ok := <-opt.RowsChan:
+			if !ok {
+				if err := <-ec; err != nil {
+					e.StmtExecLogger.Error("PipelineExecutor execute failed", zap.Error(err))
+					return nil, err
+				}
+				return rows, nil
+			}
+			rows = append(rows, rc.Rows...)
+		case <-ctx.Done():
+			e.StmtExecLogger.Info("aborted by user", zap.String("stmt", stmt.String()))
+			pipelineExecutor.Abort()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// invalidateResultCache drops the cached results of the data removed by the
+// statement.
+func (e *StatementExecutor) invalidateResultCache(stmt influxql.Statement, ctx *query2.ExecutionContext) {
+	if e.ResultCache == nil {
+		return
+	}
+	switch stmt := stmt.(type) {
+	case *influxql.DropDatabaseStatement:
+		e.ResultCache.InvalidateDatabase(stmt.Name)
+	case *influxql.DropRetentionPolicyStatement:
+		e.ResultCache.InvalidateDatabase(stmt.Database)
+	case *influxql.DropMeasurementStatement:
+		e.ResultCache.Invalidate(ctx.Database, stmt.Name, models.MinNanoTime, models.MaxNanoTime)
+	case *influxql.DeleteSeriesStatement:
+		e.invalidateSources(stmt.Sources, ctx.Database)
+	case *influxql.DropSeriesStatement:
+		e.invalidateSources(stmt.Sources, ctx.Database)
+	}
+}
+
+// invalidateSources drops the cached results of the measurements, all the
+// results of the database if a measurement is a regex or there are no sources.
+func (e *StatementExecutor) invalidateSources(sources influxql.Sources, database string) {
+	if len(sources) == 0 {
+		e.ResultCache.InvalidateDatabase(database)
+		return
+	}
+	for _, source := range sources {
+		m, ok := source.(*influxql.Measurement)
+		if !ok {
+			continue
+		}
+		db := m.Database
+		if db == "" {
+			db = database
+		}
+		if m.Regex != nil {
+			e.ResultCache.InvalidateDatabase(db)
+			continue
+		}
+		e.ResultCache.Invalidate(db, m.Name, models.MinNanoTime, models.MaxNanoTime)
+	}
+}
+
+// ResultCacheRetention drops the cached results of the shard groups removed by
+// the retention policies or by DROP SHARD, ts-sql only sees them go in the meta data.
+type ResultCacheRetention struct {
+	Cache      *resultcache.Cache
+	MetaClient interface {
+		Databases() map[string]*meta2.DatabaseInfo
+		WaitForDataChanged() chan struct{}
+	}
+
+	groups  map[uint64]shardGroupRange
+	closing chan struct{}
+	done    chan struct{}
+}
+
+type shardGroupRange struct {
+	db         string
+	start, end int64
+}
+
+// Open starts watching the shard groups of the meta data.
+func (r *ResultCacheRetention) Open() {
+	r.groups = r.shardGroups()
+	r.closing = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+// Close stops watching the meta data.
+func (r *ResultCacheRetention) Close() error {
+	if r.closing == nil {
+		return nil
+	}
+	close(r.closing)
+	<-r.done
+	r.closing = nil
+	return nil
+}
+
+func (r *ResultCacheRetention) run() {
+	defer close(r.done)
+	for {
+		// the channel is taken before the meta data is read, so no change is missed
+		changed := r.MetaClient.WaitForDataChanged()
+		r.check()
+		select {
+		case <-changed:
+		case <-r.closing:
+			return
+		}
+	}
+}
+
+// check invalidates the time range of the shard groups deleted since the last check.
+func (r *ResultCacheRetention) check() {
+	groups := r.shardGroups()
+	for id, g := range r.groups {
+		if _, ok := groups[id]; !ok {
+			r.Cache.Invalidate(g.db, "", g.start, g.end)
+		}
+	}
+	r.groups = groups
+}
+
+func (r *ResultCacheRetention) shardGroups() map[uint64]shardGroupRange {
+	groups := make(map[uint64]shardGroupRange)
+	for _, dbi := range r.MetaClient.Databases() {
+		for _, rpi := range dbi.RetentionPolicies {
+			for i := range rpi.ShardGroups {
+				sg := &rpi.ShardGroups[i]
+				if sg.Deleted() {
+					continue
+				}
+				groups[sg.ID] = shardGroupRange{db: dbi.Name, start: sg.StartTime.UnixNano(), end: sg.EndTime.UnixNano()}
+			}
+		}
+	}
+	return groups
+}
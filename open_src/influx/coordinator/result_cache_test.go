@@ -0,0 +1,151 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	originql "github.com/influxdata/influxql"
+	"github.com/openGemini/openGemini/lib/resultcache"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	query2 "github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/stretchr/testify/require"
+)
+
+// readAuthorizer allows reading the measurements of db0.rp0 in the set
+type readAuthorizer map[string]bool
+
+func (a readAuthorizer) AuthorizeDatabase(originql.Privilege, string) bool { return false }
+
+func (a readAuthorizer) AuthorizeSeriesRead(string, []byte, models.Tags) bool { return true }
+
+func (a readAuthorizer) AuthorizeSeriesWrite(string, []byte, models.Tags) bool { return true }
+
+func (a readAuthorizer) AuthorizeMeasurement(p originql.Privilege, db, rp, mst string) bool {
+	return p == originql.ReadPrivilege && db == "db0" && rp == "rp0" && a[mst]
+}
+
+func TestCachedSelectRows_Authorize(t *testing.T) {
+	stmt, err := influxql.ParseStatement(`SELECT count(v) FROM db0.rp0.cpu WHERE time >= '2023-01-01T00:00:00Z' AND time < '2023-01-01T00:10:00Z' GROUP BY time(1m)`)
+	require.NoError(t, err)
+	p := newResultCachePlan(stmt.(*influxql.SelectStatement), time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), time.Minute)
+	require.NotNil(t, p)
+
+	// the range is fully cached by the query of another user, the stores are not queried
+	row := &models.Row{Name: "cpu", Columns: []string{"time", "count"}}
+	for ts := p.first; ts < p.stableEnd; ts += p.interval {
+		row.Values = append(row.Values, []interface{}{time.Unix(0, ts).UTC(), int64(1)})
+	}
+	e := &StatementExecutor{ResultCache: resultcache.NewCache(1<<20, 0)}
+	e.ResultCache.Put(p.key, p.entry(models.Rows{row}, p.stableEnd, time.Time{}), e.ResultCache.Generation())
+
+	ctx := &query2.ExecutionContext{Context: context.Background()}
+	ctx.Authorizer = readAuthorizer{"cpu": true}
+	rows, err := e.cachedSelectRows(p, ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rows))
+	require.Equal(t, 10, len(rows[0].Values))
+
+	ctx.Authorizer = readAuthorizer{"mem": true}
+	rows, err = e.cachedSelectRows(p, ctx)
+	require.EqualError(t, err, "not authorized to read measurement db0.rp0.cpu")
+	require.Nil(t, rows)
+}
+
+func putTestEntry(c *resultcache.Cache, key, db, mst string, start, end int64) {
+	c.Put(key, &resultcache.Entry{Database: db, Measurements: []string{mst}, Start: start, End: end}, c.Generation())
+}
+
+func TestInvalidateResultCache_Series(t *testing.T) {
+	e := &StatementExecutor{ResultCache: resultcache.NewCache(1<<20, 0)}
+	ctx := &query2.ExecutionContext{Context: context.Background()}
+	ctx.Database = "db0"
+	putTestEntry(e.ResultCache, "cpu", "db0", "cpu", 0, 100)
+	putTestEntry(e.ResultCache, "mem", "db0", "mem", 0, 100)
+	putTestEntry(e.ResultCache, "disk", "db1", "disk", 0, 100)
+
+	stmt, err := influxql.ParseStatement(`DROP SERIES FROM cpu WHERE host = 'a'`)
+	require.NoError(t, err)
+	e.invalidateResultCache(stmt, ctx)
+	require.Nil(t, e.ResultCache.Get("cpu"))
+	require.NotNil(t, e.ResultCache.Get("mem"))
+
+	stmt, err = influxql.ParseStatement(`DELETE FROM /.*/`)
+	require.NoError(t, err)
+	e.invalidateResultCache(stmt, ctx)
+	require.Nil(t, e.ResultCache.Get("mem"))
+	require.NotNil(t, e.ResultCache.Get("disk"))
+}
+
+type retentionMetaClient struct {
+	mu      sync.Mutex
+	dbs     map[string]*meta2.DatabaseInfo
+	changed chan struct{}
+}
+
+func (c *retentionMetaClient) Databases() map[string]*meta2.DatabaseInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dbs
+}
+
+func (c *retentionMetaClient) WaitForDataChanged() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.changed
+}
+
+// setDatabases replaces the meta data and notifies the waiters
+func (c *retentionMetaClient) setDatabases(dbs map[string]*meta2.DatabaseInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dbs = dbs
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func retentionDatabases(groups ...meta2.ShardGroupInfo) map[string]*meta2.DatabaseInfo {
+	return map[string]*meta2.DatabaseInfo{"db0": {
+		Name:              "db0",
+		RetentionPolicies: map[string]*meta2.RetentionPolicyInfo{"rp0": {Name: "rp0", ShardGroups: groups}},
+	}}
+}
+
+func TestResultCacheRetention(t *testing.T) {
+	sg1 := meta2.ShardGroupInfo{ID: 1, StartTime: time.Unix(0, 0), EndTime: time.Unix(0, 100)}
+	sg2 := meta2.ShardGroupInfo{ID: 2, StartTime: time.Unix(0, 100), EndTime: time.Unix(0, 200)}
+	mc := &retentionMetaClient{dbs: retentionDatabases(sg1, sg2), changed: make(chan struct{})}
+	r := &ResultCacheRetention{Cache: resultcache.NewCache(1<<20, 0), MetaClient: mc}
+	putTestEntry(r.Cache, "old", "db0", "cpu", 0, 50)
+	putTestEntry(r.Cache, "new", "db0", "cpu", 150, 200)
+	r.Open()
+	defer r.Close()
+
+	// the entries of the expired shard group are dropped
+	mc.setDatabases(retentionDatabases(sg2))
+	require.Eventually(t, func() bool { return r.Cache.Get("old") == nil }, 5*time.Second, 10*time.Millisecond)
+	require.NotNil(t, r.Cache.Get("new"))
+
+	sg2.DeletedAt = time.Now()
+	mc.setDatabases(retentionDatabases(sg2))
+	require.Eventually(t, func() bool { return r.Cache.Get("new") == nil }, 5*time.Second, 10*time.Millisecond)
+}
@@ -35,10 +35,12 @@ import (
 	originql "github.com/influxdata/influxql"
 	"github.com/openGemini/openGemini/coordinator"
 	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/lib/audit"
 	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/lib/logger"
 	meta "github.com/openGemini/openGemini/lib/metaclient"
 	"github.com/openGemini/openGemini/lib/netstorage"
+	"github.com/openGemini/openGemini/lib/resultcache"
 	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
 	"github.com/openGemini/openGemini/lib/syscontrol"
 	"github.com/openGemini/openGemini/lib/tracing"
@@ -80,6 +82,11 @@ type StatementExecutor struct {
 	RetentionPolicyLimit    int
 	MaxQueryParallel        int
 
+	// ResultCache caches the complete buckets of GROUP BY time() queries, nil if disabled.
+	// The buckets in the recent interval are never cached.
+	ResultCache               *resultcache.Cache
+	ResultCacheRecentInterval time.Duration
+
 	StmtExecLogger *logger.Logger
 }
 
@@ -88,7 +95,7 @@ func (e *StatementExecutor) Close() error {
 }
 
 // ExecuteStatement executes the given statement with the given execution context.
-func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query2.ExecutionContext) error {
+func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query2.ExecutionContext) (err error) {
 	e.MaxQueryParallel = int(atomic.LoadInt32(&syscontrol.QueryParallel))
 	// Select statements are handled separately so that they can be streamed.
 	if stmt, ok := stmt.(*influxql.SelectStatement); ok {
@@ -100,10 +107,15 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 		return nil
 	}
 
+	if isAuditStatement(stmt) {
+		defer func() {
+			auditStatement(stmt, ctx, err)
+		}()
+	}
+
 	e.StmtExecLogger.Info("start execute statement", zap.Any("stmt", stmt))
 	var rows models.Rows
 	var messages []*query.Message
-	var err error
 	switch stmt := stmt.(type) {
 	case *influxql.AlterRetentionPolicyStatement:
 		if ctx.ReadOnly {
@@ -176,6 +188,57 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropUserStatement(stmt)
+	case *influxql.DecommissionNodeStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDecommissionNodeStatement(stmt)
+	case *influxql.CreateRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.CreateRole(stmt.Name)
+	case *influxql.DropRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.DropRole(stmt.Name)
+	case *influxql.GrantRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.SetUserRole(stmt.User, stmt.Role, false)
+	case *influxql.RevokeRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.SetUserRole(stmt.User, stmt.Role, true)
+	case *influxql.GrantToRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeSetRolePrivilege(stmt.Role, stmt.Privilege, stmt.On, false)
+	case *influxql.RevokeFromRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeSetRolePrivilege(stmt.Role, stmt.Privilege, stmt.On, true)
+	case *influxql.ShowRolesStatement:
+		rows, err = e.executeShowRolesStatement(stmt)
+	case *influxql.CreateTokenStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		rows, err = e.executeCreateTokenStatement(stmt)
+	case *influxql.RevokeTokenStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.DropToken(stmt.Name)
+	case *influxql.ShowTokensStatement:
+		rows, err = e.executeShowTokensStatement(stmt)
+	case *influxql.ShowGrantsForRoleStatement:
+		rows, err = e.executeShowGrantsForRoleStatement(stmt)
 	case *influxql.ExplainStatement:
 		rows, err = e.retryExecuteStatement(stmt, ctx)
 	case *influxql.GrantStatement:
@@ -277,6 +340,7 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 	if err != nil {
 		return err
 	}
+	e.invalidateResultCache(stmt, ctx)
 
 	return ctx.Send(&query.Result{
 		Series:   rows,
@@ -284,6 +348,47 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 	})
 }
 
+// isAuditStatement returns true for the DDL and DCL statements, which are
+// recorded by the audit log.
+func isAuditStatement(stmt influxql.Statement) bool {
+	switch stmt.(type) {
+	case *influxql.AlterRetentionPolicyStatement, *influxql.AlterShardKeyStatement,
+		*influxql.CreateContinuousQueryStatement, *influxql.CreateDatabaseStatement,
+		*influxql.CreateMeasurementStatement, *influxql.CreateRetentionPolicyStatement,
+		*influxql.CreateSubscriptionStatement, *influxql.CreateUserStatement,
+		*influxql.DeleteSeriesStatement, *influxql.DropContinuousQueryStatement,
+		*influxql.DropDatabaseStatement, *influxql.DropMeasurementStatement,
+		*influxql.DropRetentionPolicyStatement, *influxql.DropSeriesStatement,
+		*influxql.DropShardStatement, *influxql.DropSubscriptionStatement,
+		*influxql.DropUserStatement, *influxql.DecommissionNodeStatement,
+		*influxql.GrantStatement, *influxql.GrantAdminStatement,
+		*influxql.RevokeStatement, *influxql.RevokeAdminStatement,
+		*influxql.SetPasswordUserStatement,
+		*influxql.CreateRoleStatement, *influxql.DropRoleStatement,
+		*influxql.GrantRoleStatement, *influxql.RevokeRoleStatement,
+		*influxql.GrantToRoleStatement, *influxql.RevokeFromRoleStatement,
+		*influxql.CreateTokenStatement, *influxql.RevokeTokenStatement:
+		return true
+	default:
+		return false
+	}
+}
+
+func auditStatement(stmt influxql.Statement, ctx *query2.ExecutionContext, err error) {
+	db := ctx.Database
+	if s, ok := stmt.(influxql.HasDefaultDatabase); ok && s.DefaultDatabase() != "" {
+		db = s.DefaultDatabase()
+	}
+	audit.Log(&audit.Event{
+		Type:      audit.Statement,
+		User:      ctx.UserID,
+		Source:    ctx.RemoteAddr,
+		Database:  db,
+		Statement: stmt.String(),
+		Err:       err,
+	})
+}
+
 func (e *StatementExecutor) retryExecuteStatement(stmt influxql.Statement, ctx *query2.ExecutionContext) (models.Rows, error) {
 	startTime := time.Now()
 	var retryNum uint32 = 0
@@ -592,6 +697,10 @@ func (e *StatementExecutor) executeDropUserStatement(q *influxql.DropUserStateme
 	return e.MetaClient.DropUser(q.Name)
 }
 
+func (e *StatementExecutor) executeDecommissionNodeStatement(q *influxql.DecommissionNodeStatement) error {
+	return e.MetaClient.DecommissionDataNode(q.NodeID)
+}
+
 func (e *StatementExecutor) executeExplainStatement(q *influxql.ExplainStatement, ctx *query2.ExecutionContext) (models.Rows, error) {
 	panic("impl me")
 }
@@ -607,7 +716,10 @@ func (e *StatementExecutor) executeExplainAnalyzeStatement(q *influxql.ExplainSt
 
 	pipSpan := span.StartSpan("create_pipeline_executor").StartPP()
 	ectx.ExecutionOptions.RowsChan = make(chan query2.RowsChan)
-	pipelineExecutor, err := e.createPipelineExecutor(ctx, stmt, ectx.ExecutionOptions)
+	// the rows are only counted, the plan is returned as a normal result
+	opt := ectx.ExecutionOptions
+	opt.ChunkWriter = nil
+	pipelineExecutor, err := e.createPipelineExecutor(ctx, stmt, opt)
 	pipSpan.Finish()
 
 	if err != nil {
@@ -690,6 +802,18 @@ func (e *StatementExecutor) executeRevokeStatement(stmt *originql.RevokeStatemen
 	return e.MetaClient.SetPrivilege(stmt.User, stmt.On, priv)
 }
 
+func (e *StatementExecutor) executeSetRolePrivilege(role string, p influxql.Privilege, on *influxql.Measurement, revoke bool) error {
+	mst, regex := on.Name, false
+	if on.Regex != nil {
+		mst, regex = on.Regex.Val.String(), true
+	}
+	rp, err := meta2.NewRolePrivilege(on.Database, on.RetentionPolicy, mst, regex, originql.Privilege(p))
+	if err != nil {
+		return err
+	}
+	return e.MetaClient.SetRolePrivilege(role, rp, revoke)
+}
+
 func (e *StatementExecutor) executeRevokeAdminStatement(stmt *influxql.RevokeAdminStatement) error {
 	return e.MetaClient.SetAdminPrivilege(stmt.User, false)
 }
@@ -729,9 +853,14 @@ func (e *StatementExecutor) retryCreatePipelineExecutor(ctx context.Context, stm
 
 func (e *StatementExecutor) executeSelectStatement(stmt *influxql.SelectStatement, ctx *query2.ExecutionContext) error {
 	start := time.Now()
-	ctx.ExecutionOptions.RowsChan = make(chan query2.RowsChan)
 	// omit Time field for stmt
 	stmt.OmitTime = true
+	if e.ResultCache != nil && !ctx.Chunked && ctx.ChunkWriter == nil {
+		if p := newResultCachePlan(stmt, start, e.ResultCacheRecentInterval); p != nil {
+			return e.executeCachedSelect(p, ctx)
+		}
+	}
+	ctx.ExecutionOptions.RowsChan = make(chan query2.RowsChan)
 	pipelineExecutor, err := e.retryCreatePipelineExecutor(ctx, stmt, ctx.ExecutionOptions)
 	if err == influxql.ErrDeclareEmptyCollection {
 		// skip empty collection err and return empty result set
@@ -834,6 +963,7 @@ func (e *StatementExecutor) createPipelineExecutor(ctx context.Context, stmt *in
 		ChunkedSize:             opt.ChunkSize,
 		QueryLimitEn:            opt.QueryLimitEn,
 		RowsChan:                opt.RowsChan,
+		ChunkWriter:             opt.ChunkWriter,
 		ChunkSize:               opt.InnerChunkSize,
 		Traceid:                 opt.Traceid,
 		AbortChan:               opt.AbortCh,
@@ -1382,6 +1512,82 @@ func (e *StatementExecutor) executeShowUsersStatement(q *influxql.ShowUsersState
 	return []*models.Row{row}, nil
 }
 
+func (e *StatementExecutor) executeShowRolesStatement(q *influxql.ShowRolesStatement) (models.Rows, error) {
+	users := e.MetaClient.Users()
+	row := &models.Row{Columns: []string{"role", "users"}}
+	for _, ri := range e.MetaClient.Roles() {
+		var members []string
+		for i := range users {
+			if users[i].HasRole(ri.Name) {
+				members = append(members, users[i].Name)
+			}
+		}
+		row.Values = append(row.Values, []interface{}{ri.Name, strings.Join(members, ",")})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowGrantsForRoleStatement(q *influxql.ShowGrantsForRoleStatement) (models.Rows, error) {
+	for _, ri := range e.MetaClient.Roles() {
+		if ri.Name != q.Name {
+			continue
+		}
+		row := &models.Row{Columns: []string{"database", "retention_policy", "measurement", "privilege"}}
+		for _, p := range ri.Privileges {
+			mst := p.Measurement
+			if p.Regex {
+				mst = "/" + mst + "/"
+			}
+			row.Values = append(row.Values, []interface{}{p.Database, p.RetentionPolicy, mst, p.Privilege.String()})
+		}
+		return []*models.Row{row}, nil
+	}
+	return nil, meta2.ErrRoleNotFound
+}
+
+// executeCreateTokenStatement creates the token and returns its secret, which
+// is only available in this result.
+func (e *StatementExecutor) executeCreateTokenStatement(q *influxql.CreateTokenStatement) (models.Rows, error) {
+	var scopes map[string]originql.Privilege
+	if len(q.Scopes) > 0 {
+		scopes = make(map[string]originql.Privilege, len(q.Scopes))
+	}
+	for _, scope := range q.Scopes {
+		scopes[scope.Database] |= originql.Privilege(scope.Privilege)
+	}
+	secret, err := e.MetaClient.CreateToken(q.Name, q.User, scopes, q.Duration)
+	if err != nil {
+		return nil, err
+	}
+	row := &models.Row{Columns: []string{"name", "token"}}
+	row.Values = append(row.Values, []interface{}{q.Name, secret})
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowTokensStatement(q *influxql.ShowTokensStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{"name", "user", "scopes", "created", "expires", "last_used"}}
+	for _, ti := range e.MetaClient.Tokens() {
+		if q.User != "" && ti.User != q.User {
+			continue
+		}
+		scopes := make([]string, 0, len(ti.Scopes))
+		for db, p := range ti.Scopes {
+			scopes = append(scopes, p.String()+" ON "+db)
+		}
+		sort.Strings(scopes)
+		row.Values = append(row.Values, []interface{}{ti.Name, ti.User, strings.Join(scopes, ","),
+			formatTokenTime(ti.CreatedAt), formatTokenTime(ti.ExpiresAt), formatTokenTime(ti.LastUsed)})
+	}
+	return []*models.Row{row}, nil
+}
+
+func formatTokenTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func (e *StatementExecutor) Statistics(buffer []byte) ([]byte, error) {
 	// Statistics() period is 10
 	// do db stats period 1 minute
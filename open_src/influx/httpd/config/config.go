@@ -32,6 +32,12 @@ const (
 	DefaultMaxRowNum = 1000000
 
 	DefaultBlockSize = 64 * 1024
+
+	// DefaultFlightBindAddress is the default address the Arrow Flight service binds to.
+	DefaultFlightBindAddress = ":8087"
+
+	// DefaultFlightTokenTTL is how long a token issued by the Arrow Flight handshake stays valid.
+	DefaultFlightTokenTTL = time.Hour
 )
 
 // Config represents a configuration for a HTTP service.
@@ -75,6 +81,10 @@ type Config struct {
 	QueryMemoryLimitEnabled bool           `toml:"query-memory-limit-enabled"`
 	ChunkReaderParallel     int            `toml:"chunk-reader-parallel"`
 	ReadBlockSize           toml.Size      `toml:"read-block-size"`
+	FlightEnabled           bool           `toml:"flight-enabled"`
+	FlightBindAddress       string         `toml:"flight-bind-address"`
+	FlightTokenTTL          toml.Duration  `toml:"flight-token-ttl"`
+	FlightInsecureAuth      bool           `toml:"flight-insecure-auth-enabled"`
 }
 
 // NewHttpConfig returns a new Config with default settings.
@@ -100,6 +110,8 @@ func NewConfig() Config {
 		QueryMemoryLimitEnabled: true,
 		ChunkReaderParallel:     cpu.GetCpuNum(),
 		ReadBlockSize:           toml.Size(DefaultBlockSize),
+		FlightBindAddress:       DefaultFlightBindAddress,
+		FlightTokenTTL:          toml.Duration(DefaultFlightTokenTTL),
 	}
 }
 
@@ -129,6 +141,21 @@ func (c Config) Validate() error {
 	if c.MaxBodySize < 0 {
 		return errors.New("http max-body-size can not be negative")
 	}
+	if c.FlightEnabled && c.FlightBindAddress == "" {
+		return errors.New("http flight-bind-address must be specified")
+	}
+	if c.FlightTokenTTL < 0 {
+		return errors.New("http flight-token-ttl can not be negative")
+	}
+	return c.ValidateFlightTLS()
+}
+
+// ValidateFlightTLS refuses to send the credentials of the Arrow Flight service over plaintext,
+// unless flight-insecure-auth-enabled is set.
+func (c Config) ValidateFlightTLS() error {
+	if c.FlightEnabled && c.AuthEnabled && !c.HTTPSEnabled && !c.FlightInsecureAuth {
+		return errors.New("http flight-enabled with auth-enabled requires https-enabled or flight-insecure-auth-enabled")
+	}
 	return nil
 }
 
@@ -0,0 +1,535 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/flight"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/influxdb/models"
+	"github.com/openGemini/openGemini/app"
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/index/tsi"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	"github.com/openGemini/openGemini/open_src/influx/httpd/config"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	query2 "github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/openGemini/openGemini/yacc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpccreds "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	errFlightTokenInvalid = errors.New("invalid token")
+	errFlightTokenExpired = errors.New("token expired")
+	errFlightTokenRevoked = errors.New("token revoked")
+	errFlightSchemaChange = errors.New("results with different measurements or columns can not share a flight stream")
+)
+
+// flightQuery is the ticket of a DoGet call and the command of a flight descriptor.
+type flightQuery struct {
+	Database        string `json:"db"`
+	RetentionPolicy string `json:"rp"`
+	Query           string `json:"q"`
+}
+
+// FlightService serves InfluxQL queries over Arrow Flight. The query is sent as the ticket
+// of a DoGet call and the results stream back as Arrow record batches, one per series.
+// Users, privileges and query limits are shared with the HTTP handler.
+type FlightService struct {
+	addr    string
+	conf    config.Config
+	server  flight.Server
+	auth    *flightAuthHandler
+	closing chan struct{}
+	Handler *Handler
+	Logger  *zap.Logger
+}
+
+// NewFlightService returns a new instance of FlightService serving the queries through h.
+func NewFlightService(c config.Config, h *Handler) *FlightService {
+	return &FlightService{
+		addr:    c.FlightBindAddress,
+		conf:    c,
+		auth:    newFlightAuthHandler(h, time.Duration(c.FlightTokenTTL)),
+		Handler: h,
+		Logger:  logger.GetLogger().With(zap.String("service", "flight")),
+	}
+}
+
+// Open starts the service. It serves over TLS with the https certificate when https is enabled.
+func (s *FlightService) Open() error {
+	if err := s.conf.ValidateFlightTLS(); err != nil {
+		return err
+	}
+
+	var opts []grpc.ServerOption
+	if s.conf.HTTPSEnabled {
+		key := s.conf.HTTPSPrivateKey
+		if key == "" {
+			key = s.conf.HTTPSCertificate
+		}
+		cert, err := tls.LoadX509KeyPair(s.conf.HTTPSCertificate, key)
+		if err != nil {
+			return err
+		}
+		tlsConfig := new(tls.Config)
+		if s.conf.TLS != nil {
+			tlsConfig = s.conf.TLS.Clone()
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		opts = append(opts, grpc.Creds(grpccreds.NewTLS(tlsConfig)))
+	}
+
+	// the handshake is only required when the authentication is enabled
+	var auth flight.ServerAuthHandler
+	if s.Handler.Config.AuthEnabled {
+		auth = s.auth
+	}
+	s.server = flight.NewFlightServer(auth, opts...)
+	if err := s.server.Init(s.addr); err != nil {
+		return err
+	}
+	s.server.RegisterFlightService(&flight.FlightServiceService{
+		GetFlightInfo: s.getFlightInfo,
+		DoGet:         s.doGet,
+	})
+	s.Logger.Info("Listening on Arrow Flight", zap.Stringer("addr", s.server.Addr()), zap.Bool("https", s.conf.HTTPSEnabled))
+
+	s.closing = make(chan struct{})
+	go s.auth.sweep(s.closing)
+
+	go func() {
+		if err := s.server.Serve(); err != nil {
+			s.Logger.Error("flight server stopped", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Close stops the service after the running queries return.
+func (s *FlightService) Close() error {
+	if s.server != nil {
+		s.server.Shutdown()
+	}
+	if s.closing != nil {
+		close(s.closing)
+		s.closing = nil
+	}
+	return nil
+}
+
+// Addr returns the address the service is listening on.
+func (s *FlightService) Addr() net.Addr {
+	return s.server.Addr()
+}
+
+// getFlightInfo validates the query of a command descriptor and returns the endpoint to read it from.
+// The schema depends on the data, so it is only known once the query runs.
+func (s *FlightService) getFlightInfo(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	if desc.GetType() != flight.FlightDescriptor_CMD {
+		return nil, status.Error(codes.InvalidArgument, "only command descriptors are supported")
+	}
+	user, _ := flight.AuthFromContext(ctx).(meta2.User)
+	err := s.prepareQuery(desc.Cmd, user, func(*flightQuery, *influxql.Query) error {
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &flight.FlightInfo{
+		FlightDescriptor: desc,
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: desc.Cmd}}},
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+func (s *FlightService) doGet(tkt *flight.Ticket, stream flight.FlightService_DoGetServer) error {
+	atomic.AddInt64(&statistics.HandlerStat.QueryRequests, 1)
+	atomic.AddInt64(&statistics.HandlerStat.ActiveQueryRequests, 1)
+	start := time.Now()
+	defer func() {
+		atomic.AddInt64(&statistics.HandlerStat.ActiveQueryRequests, -1)
+		atomic.AddInt64(&statistics.HandlerStat.QueryRequestDuration, time.Since(start).Nanoseconds())
+	}()
+
+	ctx := stream.Context()
+	user, _ := flight.AuthFromContext(ctx).(meta2.User)
+	return s.prepareQuery(tkt.GetTicket(), user, func(fq *flightQuery, q *influxql.Query) error {
+		release, err := s.Handler.queryThrottler.Acquire(ctx)
+		if err != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		defer release()
+
+		traceId := tsi.GenerateUUID()
+		fields := []zap.Field{zap.String("db", fq.Database), zap.Uint64("trace_id", traceId)}
+		if user != nil {
+			fields = append(fields, zap.String("userID", user.ID()))
+		}
+		s.Logger.Info(app.HideQueryPassword(fq.Query), fields...)
+
+		w := newFlightRecordWriter(stream)
+		opts := query2.ExecutionOptions{
+			Database:        fq.Database,
+			RetentionPolicy: fq.RetentionPolicy,
+			ChunkSize:       DefaultChunkSize,
+			ReadOnly:        true,
+			InnerChunkSize:  DefaultInnerChunkSize,
+			Quiet:           true,
+			Traceid:         traceId,
+			Authorizer:      s.Handler.fineAuthorizer(user),
+			ChunkWriter:     w,
+		}
+
+		// Make sure if the client goes away we signal the query to abort
+		closing := make(chan struct{})
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
+			close(closing)
+		}()
+		opts.AbortCh = closing
+
+		if err := s.runQuery(q, opts, closing, w); err != nil {
+			s.Logger.Error("query error! flight query", zap.Error(err), zap.Uint64("trace_id", traceId))
+			return status.Error(codes.Internal, err.Error())
+		}
+		return nil
+	})
+}
+
+// runQuery writes the rows of the statements which are not returned as chunks
+// and drains the results even after the first error.
+func (s *FlightService) runQuery(q *influxql.Query, opts query2.ExecutionOptions, closing chan struct{}, w *flightRecordWriter) error {
+	var err error
+	results := s.Handler.QueryExecutor.ExecuteQuery(q, opts, closing, nil)
+	for r := range results {
+		if r == nil || err != nil {
+			continue
+		}
+		if r.Err != nil {
+			err = r.Err
+			continue
+		}
+		for _, row := range r.Series {
+			if err = w.WriteRow(row); err != nil {
+				break
+			}
+		}
+	}
+
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// prepareQuery decodes, parses and authorizes the query, then calls fn while the parsed query is valid.
+func (s *FlightService) prepareQuery(b []byte, user meta2.User, fn func(*flightQuery, *influxql.Query) error) error {
+	fq := &flightQuery{}
+	if err := json.Unmarshal(b, fq); err != nil {
+		return status.Error(codes.InvalidArgument, "error decoding ticket: "+err.Error())
+	}
+	if strings.TrimSpace(fq.Query) == "" {
+		return status.Error(codes.InvalidArgument, `missing required field "q"`)
+	}
+
+	p := influxql.NewParser(strings.NewReader(fq.Query))
+	defer p.Release()
+	YyParser := yacc.NewYyParser(p.GetScanner())
+	YyParser.ParseTokens()
+	q, err := YyParser.GetQuery()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "error parsing query: "+err.Error())
+	}
+	// every statement of a flight stream must share the schema, so only one is accepted
+	if len(q.Statements) != 1 {
+		return status.Errorf(codes.InvalidArgument, "a ticket must hold exactly one statement, got %d", len(q.Statements))
+	}
+
+	if s.Handler.Config.AuthEnabled {
+		if err := s.Handler.QueryAuthorizer.AuthorizeQuery(user, q, fq.Database); err != nil {
+			if err, ok := err.(meta2.ErrAuthorize); ok {
+				s.Logger.Info("Unauthorized request",
+					zap.String("user", err.User),
+					zap.Stringer("query", err.Query),
+					zap.String("database", err.Database))
+			}
+			return status.Error(codes.PermissionDenied, "error authorizing query: "+err.Error())
+		}
+	}
+	return fn(fq, q)
+}
+
+// flightRecordWriter writes the results of a query to a DoGet stream. The chunks of a
+// select are written by the query pipeline and the other rows once the statement returns.
+type flightRecordWriter struct {
+	mu     sync.Mutex
+	stream flight.FlightService_DoGetServer
+	mem    memory.Allocator
+	w      *ipc.FlightDataWriter
+	schema *arrow.Schema
+}
+
+func newFlightRecordWriter(stream flight.FlightService_DoGetServer) *flightRecordWriter {
+	return &flightRecordWriter{
+		stream: stream,
+		mem:    memory.NewGoAllocator(),
+	}
+}
+
+// WriteChunk writes one record batch for each series of the chunk.
+func (w *flightRecordWriter) WriteChunk(chunk interface{}) error {
+	c, ok := chunk.(executor.Chunk)
+	if !ok {
+		return fmt.Errorf("unsupported chunk type %T", chunk)
+	}
+	recs, err := executor.ChunkToArrowRecords(c, w.mem)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, rec := range recs {
+		if err := w.write(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteRow writes the row as one record batch.
+func (w *flightRecordWriter) WriteRow(row *models.Row) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rec, err := executor.RowToArrowRecord(row, w.mem, w.schema)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+	return w.write(rec)
+}
+
+func (w *flightRecordWriter) write(rec array.Record) error {
+	if w.w == nil {
+		w.schema = rec.Schema()
+		w.w = ipc.NewFlightDataWriter(w.stream, ipc.WithSchema(w.schema), ipc.WithAllocator(w.mem))
+	} else if !flightSchemaEqual(w.schema, rec.Schema()) {
+		return errFlightSchemaChange
+	}
+	return w.w.Write(rec)
+}
+
+// Close ends the stream. A query without any result ends it without a schema.
+func (w *flightRecordWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.w == nil {
+		return nil
+	}
+	return w.w.Close()
+}
+
+// flightSchemaEqual also compares the measurements, which are only sent with the first schema of the stream.
+func flightSchemaEqual(a, b *arrow.Schema) bool {
+	if !a.Equal(b) {
+		return false
+	}
+	ma, mb := a.Metadata(), b.Metadata()
+	return flightMeasurement(ma) == flightMeasurement(mb)
+}
+
+func flightMeasurement(md arrow.Metadata) string {
+	if i := md.FindKey(executor.ArrowMeasurementKey); i >= 0 {
+		return md.Values()[i]
+	}
+	return ""
+}
+
+// flightToken keeps the user a token was issued to, the user is looked up again on every call
+// so that a dropped user, a changed password or a revoked privilege takes effect at once.
+type flightToken struct {
+	username string
+	hash     string
+	expire   time.Time
+}
+
+// flightAuthHandler authenticates the flight handshake with the user and password of
+// a BasicAuth payload and issues the token sent by the following calls.
+type flightAuthHandler struct {
+	mu     sync.Mutex
+	h      *Handler
+	ttl    time.Duration
+	tokens map[string]flightToken
+}
+
+func newFlightAuthHandler(h *Handler, ttl time.Duration) *flightAuthHandler {
+	if ttl <= 0 {
+		ttl = config.DefaultFlightTokenTTL
+	}
+	return &flightAuthHandler{
+		h:      h,
+		ttl:    ttl,
+		tokens: make(map[string]flightToken),
+	}
+}
+
+// Authenticate implements flight.ServerAuthHandler.
+func (a *flightAuthHandler) Authenticate(conn flight.AuthConn) error {
+	payload, err := conn.Read()
+	if err != nil {
+		return err
+	}
+
+	var user meta2.User
+	// no users in system, the same as the HTTP handler
+	if a.h.MetaClient.AdminUserExists() {
+		creds := &flight.BasicAuth{}
+		if err := proto.Unmarshal(payload, creds); err != nil {
+			return status.Error(codes.InvalidArgument, "error decoding handshake: "+err.Error())
+		}
+		if creds.Username == "" {
+			atomic.AddInt64(&statistics.HandlerStat.AuthenticationFailures, 1)
+			return status.Error(codes.Unauthenticated, "username required")
+		}
+		user, err = a.h.MetaClient.Authenticate(creds.Username, creds.Password)
+		if err != nil {
+			atomic.AddInt64(&statistics.HandlerStat.AuthenticationFailures, 1)
+			errMsg := "authorization failed"
+			if err == meta2.ErrUserLocked {
+				errMsg = err.Error()
+			}
+			return status.Error(codes.Unauthenticated, errMsg)
+		}
+	}
+
+	token, err := a.issue(user)
+	if err != nil {
+		return err
+	}
+	return conn.Send([]byte(token))
+}
+
+// IsValid implements flight.ServerAuthHandler, it returns the current state of the user the token was issued to.
+// The token is revoked if the user is dropped or the password is changed.
+func (a *flightAuthHandler) IsValid(token string) (interface{}, error) {
+	if !a.h.MetaClient.AdminUserExists() {
+		return nil, nil
+	}
+
+	a.mu.Lock()
+	t, ok := a.tokens[token]
+	if ok && time.Now().After(t.expire) {
+		delete(a.tokens, token)
+		a.mu.Unlock()
+		return nil, errFlightTokenExpired
+	}
+	a.mu.Unlock()
+	if !ok {
+		return nil, errFlightTokenInvalid
+	}
+
+	user, err := a.h.MetaClient.User(t.username)
+	if err != nil || user == nil || userHash(user) != t.hash {
+		a.revoke(token)
+		return nil, errFlightTokenRevoked
+	}
+	return user, nil
+}
+
+func (a *flightAuthHandler) revoke(token string) {
+	a.mu.Lock()
+	delete(a.tokens, token)
+	a.mu.Unlock()
+}
+
+// sweep drops the expired tokens every ttl until closing is closed
+func (a *flightAuthHandler) sweep(closing chan struct{}) {
+	ticker := time.NewTicker(a.ttl)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case now := <-ticker.C:
+			a.mu.Lock()
+			for k, t := range a.tokens {
+				if now.After(t.expire) {
+					delete(a.tokens, k)
+				}
+			}
+			a.mu.Unlock()
+		}
+	}
+}
+
+func userHash(user meta2.User) string {
+	if u, ok := user.(*meta2.UserInfo); ok {
+		return u.Hash
+	}
+	return ""
+}
+
+func (a *flightAuthHandler) issue(user meta2.User) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	t := flightToken{expire: time.Now().Add(a.ttl)}
+	if user != nil {
+		t.username = user.ID()
+		t.hash = userHash(user)
+	}
+	a.mu.Lock()
+	a.tokens[token] = t
+	a.mu.Unlock()
+	return token, nil
+}
@@ -0,0 +1,432 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/arrow/flight"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/openGemini/openGemini/engine/executor"
+	"github.com/openGemini/openGemini/engine/hybridqp"
+	"github.com/openGemini/openGemini/open_src/influx/httpd/config"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	query2 "github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpccreds "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type flightStatementExecutor struct{}
+
+func (e *flightStatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query2.ExecutionContext) error {
+	rows := models.Rows{
+		{Name: "cpu", Tags: map[string]string{"host": "a"}, Columns: []string{"time", "value"}, Values: [][]interface{}{{int64(1), 1.5}, {int64(2), 2.5}}},
+		{Name: "cpu", Tags: map[string]string{"host": "b"}, Columns: []string{"time", "value"}, Values: [][]interface{}{{int64(1), 3.5}}},
+	}
+	if strings.Contains(stmt.String(), "mem") {
+		rows = append(rows, &models.Row{Name: "mem", Columns: []string{"time", "used"}, Values: [][]interface{}{{int64(1), int64(10)}}})
+	}
+	return ctx.Send(&query.Result{Series: rows})
+}
+
+func (e *flightStatementExecutor) Statistics(buffer []byte) ([]byte, error) {
+	return buffer, nil
+}
+
+type flightMetaClient struct {
+	mu    sync.Mutex
+	users map[string]*meta2.UserInfo
+}
+
+func newFlightMetaClient() *flightMetaClient {
+	return &flightMetaClient{users: map[string]*meta2.UserInfo{
+		"admin": {Name: "admin", Hash: "pwd", Admin: true},
+	}}
+}
+
+func (c *flightMetaClient) setUser(u *meta2.UserInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if u.Hash == "" {
+		delete(c.users, u.Name)
+		return
+	}
+	c.users[u.Name] = u
+}
+
+func (c *flightMetaClient) Database(name string) (*meta2.DatabaseInfo, error) {
+	return nil, nil
+}
+
+func (c *flightMetaClient) User(username string) (meta2.User, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u, ok := c.users[username]
+	if !ok {
+		return nil, meta2.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (c *flightMetaClient) AdminUserExists() bool {
+	return true
+}
+
+func (c *flightMetaClient) DataNodes() ([]meta2.DataNode, error) {
+	return nil, nil
+}
+
+func (c *flightMetaClient) ShowShards() models.Rows {
+	return nil
+}
+
+func (c *flightMetaClient) Authenticate(username, password string) (meta2.User, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u, ok := c.users[username]
+	if !ok || u.Hash != password {
+		return nil, meta2.ErrAuthenticate
+	}
+	return u, nil
+}
+
+func (c *flightMetaClient) AuthenticateToken(secret string) (meta2.User, error) {
+	return nil, meta2.ErrAuthenticate
+}
+
+type flightQueryAuthorizer struct{}
+
+func (a *flightQueryAuthorizer) AuthorizeQuery(u meta2.User, q *influxql.Query, database string) error {
+	if u == nil {
+		return errors.New("no user")
+	}
+	return nil
+}
+
+type flightClientAuth struct {
+	username, password string
+	token              string
+}
+
+func (a *flightClientAuth) Authenticate(ctx context.Context, conn flight.AuthConn) error {
+	b, err := proto.Marshal(&flight.BasicAuth{Username: a.username, Password: a.password})
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(b); err != nil {
+		return err
+	}
+	token, err := conn.Read()
+	if err != nil {
+		return err
+	}
+	a.token = string(token)
+	return nil
+}
+
+func (a *flightClientAuth) GetToken(ctx context.Context) (string, error) {
+	return a.token, nil
+}
+
+func newFlightConfig(authEnabled bool) config.Config {
+	c := config.NewConfig()
+	c.AuthEnabled = authEnabled
+	c.FlightEnabled = true
+	c.FlightBindAddress = "127.0.0.1:0"
+	return c
+}
+
+func newFlightService(c config.Config) *FlightService {
+	h := NewHandler(c)
+	h.QueryExecutor = query2.NewExecutor()
+	h.QueryExecutor.StatementExecutor = &flightStatementExecutor{}
+	h.MetaClient = newFlightMetaClient()
+	h.QueryAuthorizer = &flightQueryAuthorizer{}
+	return NewFlightService(c, h)
+}
+
+func openFlightService(t *testing.T, c config.Config) *FlightService {
+	s := newFlightService(c)
+	require.NoError(t, s.Open())
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+	return s
+}
+
+func flightTicket(t *testing.T, q string) *flight.Ticket {
+	b, err := json.Marshal(&flightQuery{Database: "db0", Query: q})
+	require.NoError(t, err)
+	return &flight.Ticket{Ticket: b}
+}
+
+func readFlightRecords(t *testing.T, client flight.Client, ctx context.Context, tkt *flight.Ticket) ([]int64, error) {
+	stream, err := client.DoGet(ctx, tkt)
+	require.NoError(t, err)
+	r, err := ipc.NewFlightDataReader(stream)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	defer r.Release()
+
+	var rows []int64
+	for r.Next() {
+		rows = append(rows, r.Record().NumRows())
+	}
+	return rows, grpcError(r.Err())
+}
+
+// grpcError unwraps the status error of the stream from the error of the ipc reader
+func grpcError(err error) error {
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Err()
+	}
+	return err
+}
+
+func TestFlightService_DoGet(t *testing.T) {
+	s := openFlightService(t, newFlightConfig(false))
+	client, err := flight.NewFlightClient(s.Addr().String(), nil, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	stream, err := client.DoGet(ctx, flightTicket(t, "SELECT value FROM cpu GROUP BY host"))
+	require.NoError(t, err)
+	r, err := ipc.NewFlightDataReader(stream)
+	require.NoError(t, err)
+	defer r.Release()
+
+	assert.Equal(t, []string{"host", "time", "value"}, []string{r.Schema().Field(0).Name, r.Schema().Field(1).Name, r.Schema().Field(2).Name})
+	assert.Equal(t, "cpu", flightMeasurement(r.Schema().Metadata()))
+	var rows []int64
+	for r.Next() {
+		rows = append(rows, r.Record().NumRows())
+	}
+	require.NoError(t, r.Err())
+	assert.Equal(t, []int64{2, 1}, rows)
+
+	info, err := client.GetFlightInfo(ctx, &flight.FlightDescriptor{
+		Type: flight.FlightDescriptor_CMD,
+		Cmd:  flightTicket(t, "SELECT value FROM cpu").Ticket,
+	})
+	require.NoError(t, err)
+	require.Len(t, info.Endpoint, 1)
+	rows, err = readFlightRecords(t, client, ctx, info.Endpoint[0].Ticket)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, rows)
+
+	_, err = client.GetFlightInfo(ctx, &flight.FlightDescriptor{
+		Type: flight.FlightDescriptor_CMD,
+		Cmd:  flightTicket(t, "SELECT value FROM cpu; SELECT value FROM mem").Ticket,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = readFlightRecords(t, client, ctx, flightTicket(t, "SELECT FROM"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// writeFlightCertificate writes a self-signed certificate of 127.0.0.1 and its key into dir
+func writeFlightCertificate(t *testing.T, dir string) (string, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	pemData = append(pemData, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})...)
+	path := filepath.Join(dir, "flight.pem")
+	require.NoError(t, os.WriteFile(path, pemData, 0600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return path, pool
+}
+
+func TestFlightService_TLS(t *testing.T) {
+	// the credentials are not sent over plaintext unless it is allowed
+	c := newFlightConfig(true)
+	require.Error(t, c.Validate())
+	require.Error(t, newFlightService(c).Open())
+	c.FlightInsecureAuth = true
+	require.NoError(t, c.Validate())
+	openFlightService(t, c)
+
+	cert, pool := writeFlightCertificate(t, t.TempDir())
+	c = newFlightConfig(false)
+	c.HTTPSEnabled = true
+	c.HTTPSCertificate = cert
+	s := openFlightService(t, c)
+	ctx := context.Background()
+
+	// a plaintext client can not query the service
+	client, err := flight.NewFlightClient(s.Addr().String(), nil, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer client.Close()
+	stream, err := client.DoGet(ctx, flightTicket(t, "SELECT value FROM cpu"))
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	client, err = flight.NewFlightClient(s.Addr().String(), nil,
+		grpc.WithTransportCredentials(grpccreds.NewTLS(&tls.Config{RootCAs: pool})))
+	require.NoError(t, err)
+	defer client.Close()
+	rows, err := readFlightRecords(t, client, ctx, flightTicket(t, "SELECT value FROM cpu"))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, rows)
+}
+
+func TestFlightService_Auth(t *testing.T) {
+	cert, pool := writeFlightCertificate(t, t.TempDir())
+	c := newFlightConfig(true)
+	c.HTTPSEnabled = true
+	c.HTTPSCertificate = cert
+	s := openFlightService(t, c)
+	ctx := context.Background()
+
+	auth := &flightClientAuth{username: "admin", password: "wrong"}
+	client, err := flight.NewFlightClient(s.Addr().String(), auth,
+		grpc.WithTransportCredentials(grpccreds.NewTLS(&tls.Config{RootCAs: pool})))
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = client.Authenticate(ctx)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = readFlightRecords(t, client, ctx, flightTicket(t, "SELECT value FROM cpu"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	auth.password = "pwd"
+	require.NoError(t, client.Authenticate(ctx))
+	require.NotEmpty(t, auth.token)
+	rows, err := readFlightRecords(t, client, ctx, flightTicket(t, "SELECT value FROM cpu"))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, rows)
+
+	// the token is revoked when the password is changed
+	metaClient := s.Handler.MetaClient.(*flightMetaClient)
+	metaClient.setUser(&meta2.UserInfo{Name: "admin", Hash: "pwd2", Admin: true})
+	_, err = readFlightRecords(t, client, ctx, flightTicket(t, "SELECT value FROM cpu"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	auth.password = "pwd2"
+	require.NoError(t, client.Authenticate(ctx))
+	_, err = readFlightRecords(t, client, ctx, flightTicket(t, "SELECT value FROM cpu"))
+	require.NoError(t, err)
+
+	// and when the user is dropped
+	metaClient.setUser(&meta2.UserInfo{Name: "admin"})
+	_, err = readFlightRecords(t, client, ctx, flightTicket(t, "SELECT value FROM cpu"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestFlightAuthHandler_Sweep(t *testing.T) {
+	a := newFlightAuthHandler(NewHandler(config.NewConfig()), 10*time.Millisecond)
+	_, err := a.issue(&meta2.UserInfo{Name: "admin", Hash: "pwd"})
+	require.NoError(t, err)
+
+	closing := make(chan struct{})
+	go a.sweep(closing)
+	defer close(closing)
+	require.Eventually(t, func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return len(a.tokens) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestFlightService_SchemaChange(t *testing.T) {
+	s := openFlightService(t, newFlightConfig(false))
+	client, err := flight.NewFlightClient(s.Addr().String(), nil, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = readFlightRecords(t, client, context.Background(), flightTicket(t, "SELECT * FROM cpu, mem"))
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, err.Error(), errFlightSchemaChange.Error())
+}
+
+type flightDoGetStream struct {
+	grpc.ServerStream
+	data []*flight.FlightData
+}
+
+func (s *flightDoGetStream) Send(data *flight.FlightData) error {
+	s.data = append(s.data, data)
+	return nil
+}
+
+func TestFlightRecordWriter_WriteChunk(t *testing.T) {
+	row := hybridqp.NewRowDataTypeImpl(influxql.VarRef{Val: "value", Type: influxql.Float})
+	ch := executor.NewChunkBuilder(row).NewChunk("cpu")
+	ch.AppendTime(1, 2, 3)
+	ch.AppendTagsAndIndexes([]executor.ChunkTags{*executor.ParseChunkTags("host=a"), *executor.ParseChunkTags("host=b")}, []int{0, 2})
+	ch.AppendIntervalIndex(0, 2)
+	ch.Column(0).AppendFloatValues(1.5, 2.5, 3.5)
+	ch.Column(0).AppendNilsV2(true, true, true)
+
+	stream := &flightDoGetStream{}
+	w := newFlightRecordWriter(stream)
+	require.NoError(t, w.WriteChunk(ch))
+	require.NoError(t, w.Close())
+	// the schema, then one record batch for each series
+	assert.Equal(t, 3, len(stream.data))
+
+	assert.Error(t, w.WriteChunk(struct{}{}))
+}
@@ -1,17 +1,26 @@
 package httpd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb/services/httpd"
+	originql "github.com/influxdata/influxql"
+	"github.com/openGemini/openGemini/lib/audit"
+	config2 "github.com/openGemini/openGemini/lib/config"
 	"github.com/openGemini/openGemini/open_src/github.com/bmizerany/pat"
 	"github.com/openGemini/openGemini/open_src/influx/httpd/config"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var addr = "127.0.0.2:8901"
@@ -59,3 +68,259 @@ func TestDebugCtrl(t *testing.T) {
 		t.Fatalf("invalid response data. exp: %s; got: %s", exp, string(body))
 	}
 }
+
+type writeAuthMetaClient struct {
+	flightMetaClient
+	data *meta2.Data
+}
+
+func (c *writeAuthMetaClient) Database(name string) (*meta2.DatabaseInfo, error) {
+	return c.data.GetDatabase(name)
+}
+
+type denyWriteAuthorizer struct{}
+
+func (a denyWriteAuthorizer) AuthorizeWrite(username, database string) error {
+	return meta2.ErrAuthorize{User: username, Database: database, Message: "write"}
+}
+
+func TestServeWrite_MeasurementPrivileges(t *testing.T) {
+	data := &meta2.Data{Databases: map[string]*meta2.DatabaseInfo{
+		"db0": {
+			Name:                   "db0",
+			DefaultRetentionPolicy: "autogen",
+			RetentionPolicies:      map[string]*meta2.RetentionPolicyInfo{"autogen": {Name: "autogen"}, "rp1": {Name: "rp1"}},
+		},
+	}}
+	require.NoError(t, data.CreateUser("u1", "hash", false, false))
+	require.NoError(t, data.CreateRole("writer"))
+	p, err := meta2.NewRolePrivilege("db0", "autogen", "^cpu", true, originql.WritePrivilege)
+	require.NoError(t, err)
+	require.NoError(t, data.SetRolePrivilege("writer", p, false))
+	require.NoError(t, data.SetUserRole("u1", "writer", false))
+	user := data.GetUser("u1")
+
+	influx.StartUnmarshalWorkers()
+	defer influx.StopUnmarshalWorkers()
+
+	c := config.NewConfig()
+	c.AuthEnabled = true
+	h := NewHandler(c)
+	h.MetaClient = &writeAuthMetaClient{data: data}
+	h.WriteAuthorizer = denyWriteAuthorizer{}
+	pw := &importPointsWriter{}
+	h.PointsWriter = pw
+
+	write := func(query, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.serveWrite(w, httptest.NewRequest(http.MethodPost, "/write?"+query, strings.NewReader(body)), user)
+		return w
+	}
+
+	w := write("db=db0", "cpu_load,host=a value=1 1\ncpu,host=a value=2 2\n")
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, 2, len(pw.rows))
+
+	w = write("db=db0", "cpu,host=a value=1 1\nmem,host=a value=2 2\n")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `write to measurement \"mem\"`)
+
+	w = write("db=db0&rp=rp1", "cpu,host=a value=1 1\n")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	require.NoError(t, data.SetUserRole("u1", "writer", true))
+	w = write("db=db0", "cpu,host=a value=1 1\n")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, 2, len(pw.rows))
+}
+
+type tokenMetaClient struct {
+	writeAuthMetaClient
+}
+
+func (c *tokenMetaClient) AuthenticateToken(secret string) (meta2.User, error) {
+	ti := c.data.TokenByHash(meta2.HashToken(secret))
+	if ti == nil {
+		return nil, meta2.ErrAuthenticate
+	}
+	if ti.Expired(time.Now()) {
+		return nil, meta2.ErrTokenExpired
+	}
+	return &meta2.TokenUser{UserInfo: c.data.GetUser(ti.User), Token: *ti}, nil
+}
+
+type allowWriteAuthorizer struct{}
+
+func (a allowWriteAuthorizer) AuthorizeWrite(username, database string) error {
+	return nil
+}
+
+func TestAuthenticate_APIToken(t *testing.T) {
+	data := &meta2.Data{Databases: map[string]*meta2.DatabaseInfo{
+		"db0": {Name: "db0", DefaultRetentionPolicy: "autogen"},
+		"db1": {Name: "db1", DefaultRetentionPolicy: "autogen"},
+	}}
+	require.NoError(t, data.CreateUser("u1", "hash", false, false))
+	require.NoError(t, data.SetPrivilege("u1", "db0", originql.AllPrivileges))
+	require.NoError(t, data.SetPrivilege("u1", "db1", originql.AllPrivileges))
+	require.NoError(t, data.CreateToken(meta2.TokenInfo{
+		Name:   "collector",
+		User:   "u1",
+		Hash:   meta2.HashToken("secret0"),
+		Scopes: map[string]originql.Privilege{"db0": originql.WritePrivilege},
+	}))
+	require.NoError(t, data.CreateToken(meta2.TokenInfo{
+		Name:      "old",
+		User:      "u1",
+		Hash:      meta2.HashToken("secret1"),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+
+	influx.StartUnmarshalWorkers()
+	defer influx.StopUnmarshalWorkers()
+
+	c := config.NewConfig()
+	c.AuthEnabled = true
+	h := NewHandler(c)
+	h.MetaClient = &tokenMetaClient{writeAuthMetaClient{data: data}}
+	h.WriteAuthorizer = allowWriteAuthorizer{}
+	pw := &importPointsWriter{}
+	h.PointsWriter = pw
+	handler := authenticate(h.serveWrite, h, true)
+
+	write := func(db, auth string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/write?db="+db, strings.NewReader("cpu,host=a value=1 1\n"))
+		r.Header.Set("Authorization", auth)
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := write("db0", "Token secret0")
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, 1, len(pw.rows))
+
+	// the user may write db1, but the token is scoped to db0
+	w = write("db1", "Token secret0")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = write("db0", "Token secret1")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), meta2.ErrTokenExpired.Error())
+
+	w = write("db0", "Token unknown")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	require.NoError(t, data.DropToken("collector"))
+	w = write("db0", "Token secret0")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, 1, len(pw.rows))
+}
+
+type auditSink struct {
+	events []*audit.Event
+}
+
+func (s *auditSink) WriteEvent(e *audit.Event) {
+	s.events = append(s.events, e)
+}
+
+func TestAuthenticate_Audit(t *testing.T) {
+	data := &meta2.Data{Databases: map[string]*meta2.DatabaseInfo{
+		"db0": {Name: "db0", DefaultRetentionPolicy: "autogen"},
+	}}
+	require.NoError(t, data.CreateUser("u1", "hash", false, false))
+	require.NoError(t, data.SetPrivilege("u1", "db0", originql.AllPrivileges))
+	require.NoError(t, data.CreateToken(meta2.TokenInfo{Name: "t1", User: "u1", Hash: meta2.HashToken("secret0")}))
+
+	logConf := config2.NewLogger(config2.AppSql)
+	logConf.Path = t.TempDir()
+	auditConf := config2.NewAudit()
+	auditConf.Enabled = true
+	audit.Open(auditConf, logConf)
+	defer audit.Close()
+	sink := &auditSink{}
+	audit.AddSink(sink)
+	defer audit.RemoveSink(sink)
+
+	influx.StartUnmarshalWorkers()
+	defer influx.StopUnmarshalWorkers()
+
+	c := config.NewConfig()
+	c.AuthEnabled = true
+	h := NewHandler(c)
+	h.MetaClient = &tokenMetaClient{writeAuthMetaClient{data: data}}
+	h.WriteAuthorizer = allowWriteAuthorizer{}
+	h.PointsWriter = &importPointsWriter{}
+	handler := authenticate(h.serveWrite, h, true)
+
+	for _, auth := range []string{"Token secret0", "Token unknown"} {
+		r := httptest.NewRequest(http.MethodPost, "/write?db=db0", strings.NewReader("cpu,host=a value=1 1\n"))
+		r.Header.Set("Authorization", auth)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	require.Len(t, sink.events, 2)
+	assert.Equal(t, audit.Login, sink.events[0].Type)
+	assert.Equal(t, "u1", sink.events[0].User)
+	assert.Equal(t, audit.ResultSuccess, sink.events[0].Result())
+	assert.NotEmpty(t, sink.events[0].Source)
+	assert.Equal(t, audit.ResultFailure, sink.events[1].Result())
+}
+
+func TestThrottler_SetLimits(t *testing.T) {
+	th := NewThrottler(1, 0, 0)
+	release, err := th.Acquire(context.Background())
+	require.NoError(t, err)
+
+	th.SetLimits(1, 0, 0, 10*time.Millisecond)
+	_, err = th.Acquire(context.Background())
+	require.NoError(t, err, "the slot taken before the change is not counted")
+
+	_, err = th.Acquire(context.Background())
+	assert.Equal(t, errThrottledTimeout, err)
+	release()
+
+	th.SetLimits(0, 0, 0, 0)
+	for i := 0; i < 3; i++ {
+		_, err = th.Acquire(context.Background())
+		require.NoError(t, err)
+	}
+}
+
+type mockConfigReloader struct {
+	err error
+}
+
+func (r *mockConfigReloader) Reload() (*config2.ReloadResult, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &config2.ReloadResult{
+		Applied:         []string{"logging.level"},
+		RestartRequired: []string{"http.bind-address"},
+	}, nil
+}
+
+func TestServeConfigReload(t *testing.T) {
+	h := NewHandler(config.NewConfig())
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.serveConfigReload(w, httptest.NewRequest(http.MethodPost, "/config/reload", nil), nil)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotImplemented, serve().Code)
+
+	h.ConfigReloader = &mockConfigReloader{}
+	w := serve()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"applied":["logging.level"],"restart_required":["http.bind-address"]}`, w.Body.String())
+
+	h.ConfigReloader = &mockConfigReloader{err: fmt.Errorf("parse config: invalid")}
+	assert.Equal(t, http.StatusInternalServerError, serve().Code)
+
+	h.Config.AuthEnabled = true
+	assert.Equal(t, http.StatusForbidden, serve().Code)
+}
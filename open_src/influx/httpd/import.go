@@ -0,0 +1,192 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpd
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	originql "github.com/influxdata/influxql"
+	"github.com/openGemini/openGemini/lib/importer"
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"go.uber.org/zap"
+)
+
+// importFormat returns the format parameter, or the format of the content type
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return importer.FormatCSV
+	case "application/json", "application/x-ndjson", "application/jsonl":
+		return importer.FormatJSON
+	}
+	return ""
+}
+
+// serveImport writes the rows of a CSV or JSON lines body. The lines which can not be converted
+// are reported in the response, the rows of the other lines are written.
+func (h *Handler) serveImport(w http.ResponseWriter, r *http.Request, user meta2.User) {
+	atomic.AddInt64(&statistics.HandlerStat.WriteRequests, 1)
+	atomic.AddInt64(&statistics.HandlerStat.ActiveWriteRequests, 1)
+	atomic.AddInt64(&statistics.HandlerStat.WriteRequestBytesIn, r.ContentLength)
+	defer func(start time.Time) {
+		d := time.Since(start).Nanoseconds()
+		atomic.AddInt64(&statistics.HandlerStat.ActiveWriteRequests, -1)
+		atomic.AddInt64(&statistics.HandlerStat.WriteRequestDuration, d)
+	}(time.Now())
+	h.requestTracker.Add(r, user)
+
+	q := r.URL.Query()
+	database := q.Get("db")
+	if database == "" {
+		h.httpError(w, "database is required", http.StatusBadRequest)
+		atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+		return
+	}
+
+	dbi, err := h.MetaClient.Database(database)
+	if err != nil {
+		h.httpError(w, fmt.Sprintf("database not found: %q", database), http.StatusNotFound)
+		atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+		return
+	}
+
+	var rowAuthorizer meta2.User
+	if h.Config.AuthEnabled {
+		if user == nil {
+			h.httpError(w, fmt.Sprintf("user is required to write to database %q", database), http.StatusForbidden)
+			atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+			return
+		}
+
+		if err := h.authorizeWrite(user, database); err != nil {
+			if !user.AuthorizeAnyMeasurement(originql.WritePrivilege, database) {
+				h.httpError(w, fmt.Sprintf("%q user is not authorized to write to database %q", user.ID(), database), http.StatusForbidden)
+				atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+				return
+			}
+			// the user may only write some measurements, every row is checked
+			rowAuthorizer = user
+		}
+	}
+
+	if h.Config.MaxBodySize > 0 && r.ContentLength > int64(h.Config.MaxBodySize) {
+		h.httpError(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+		return
+	}
+
+	opt := importer.Options{
+		Format:       importFormat(r),
+		TsMultiplier: precisionMultiplier(q.Get("precision")),
+		Now:          time.Now().UnixNano(),
+	}
+	if s := q.Get("line_offset"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			h.httpError(w, fmt.Sprintf("invalid line_offset: %q", s), http.StatusBadRequest)
+			atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+			return
+		}
+		opt.LineOffset = n
+	}
+	if s := q.Get("mapping"); s != "" {
+		m, err := importer.ParseMapping([]byte(s))
+		if err != nil {
+			h.httpError(w, err.Error(), http.StatusBadRequest)
+			atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+			return
+		}
+		opt.Mapping = m
+	}
+
+	body := r.Body
+	if h.Config.MaxBodySize > 0 {
+		body = truncateReader(body, int64(h.Config.MaxBodySize))
+	}
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		b, err := GetGzipReader(body)
+		if err != nil {
+			h.httpError(w, err.Error(), http.StatusBadRequest)
+			atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+			return
+		}
+		defer PutGzipReader(b)
+		body = b
+	}
+
+	dec, err := importer.NewDecoder(body, opt)
+	if err != nil {
+		h.httpError(w, "error decoding import: "+err.Error(), http.StatusBadRequest)
+		atomic.AddInt64(&statistics.HandlerStat.Write400ErrRequests, 1)
+		return
+	}
+
+	rp := q.Get("rp")
+	authRP := rp
+	if authRP == "" && rowAuthorizer != nil {
+		authRP = dbi.DefaultRetentionPolicy
+	}
+	res, err := importer.Import(dec, importer.DefaultBatchSize, func(rows []influx.Row) error {
+		if rowAuthorizer != nil {
+			if err := authorizeRowsWrite(rowAuthorizer, database, authRP, rows); err != nil {
+				return err
+			}
+		}
+		if err := h.PointsWriter.WritePointRows(database, rp, rows); err != nil {
+			return err
+		}
+		atomic.AddInt64(&statistics.HandlerStat.PointsWrittenOK, int64(len(rows)))
+		return nil
+	})
+
+	code := http.StatusOK
+	if err != nil {
+		res.Error = err.Error()
+		switch {
+		case err == errTruncated:
+			code = http.StatusRequestEntityTooLarge
+		case influxdb.IsClientError(err):
+			code = http.StatusBadRequest
+		case influxdb.IsAuthorizationError(err):
+			code = http.StatusForbidden
+		default:
+			code = http.StatusInternalServerError
+		}
+		h.Logger.Error("import error", zap.Error(err), zap.String("db", database), zap.Int("committed", res.Committed))
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		h.httpError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	h.writeHeader(w, code)
+	_, _ = w.Write(b)
+}
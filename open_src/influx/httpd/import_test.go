@@ -0,0 +1,157 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	originql "github.com/influxdata/influxql"
+	"github.com/openGemini/openGemini/lib/importer"
+	"github.com/openGemini/openGemini/open_src/influx/httpd/config"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type importPointsWriter struct {
+	rows []influx.Row
+	err  error
+}
+
+func (w *importPointsWriter) WritePointRows(database, retentionPolicy string, rows []influx.Row) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.rows = append(w.rows, rows...)
+	return nil
+}
+
+func serveTestImport(t *testing.T, pw *importPointsWriter, params url.Values, contentType, body string) (*httptest.ResponseRecorder, *importer.Result) {
+	h := NewHandler(config.NewConfig())
+	h.MetaClient = &flightMetaClient{}
+	h.PointsWriter = pw
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/import?"+params.Encode(), strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	res := &importer.Result{}
+	if w.Header().Get("Content-Type") == "application/json" {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	}
+	return w, res
+}
+
+func TestServeImport(t *testing.T) {
+	pw := &importPointsWriter{}
+	params := url.Values{
+		"db":          []string{"db0"},
+		"precision":   []string{"s"},
+		"line_offset": []string{"100"},
+		"mapping":     []string{`{"measurement":"cpu","time_column":"time","tags":["host"],"fields":{"usage":"float"}}`},
+	}
+	w, res := serveTestImport(t, pw, params, "text/csv", "host,usage,time\na,1.5,10\nb,x,11\n")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, res.Written)
+	assert.Equal(t, 1, res.Rejected)
+	assert.Equal(t, 103, res.Committed)
+	assert.Equal(t, []importer.ReportedError{{Line: 103, Error: `invalid float value of field "usage": "x"`}}, res.Errors)
+
+	require.Equal(t, 1, len(pw.rows))
+	assert.Equal(t, "cpu", pw.rows[0].Name)
+	assert.Equal(t, int64(10e9), pw.rows[0].Timestamp)
+
+	w, res = serveTestImport(t, pw, url.Values{"db": []string{"db0"}}, "application/x-ndjson", `{"measurement":"mem","fields":{"used":1}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, res.Written)
+	assert.Equal(t, "mem", pw.rows[1].Name)
+}
+
+func TestServeImport_Errors(t *testing.T) {
+	pw := &importPointsWriter{}
+	w, _ := serveTestImport(t, pw, url.Values{}, "text/csv", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = serveTestImport(t, pw, url.Values{"db": []string{"db0"}}, "text/plain", "a\n")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = serveTestImport(t, pw, url.Values{"db": []string{"db0"}, "mapping": []string{"{"}}, "text/csv", "a\n")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	pw.err = errors.New("shard unavailable")
+	w, res := serveTestImport(t, pw, url.Values{"db": []string{"db0"}}, "text/csv", "#datatype measurement,double\nm,v\ncpu,1\n")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "shard unavailable", res.Error)
+	assert.Equal(t, 0, res.Committed)
+}
+
+func TestServeImport_MeasurementPrivileges(t *testing.T) {
+	data := &meta2.Data{Databases: map[string]*meta2.DatabaseInfo{
+		"db0": {
+			Name:                   "db0",
+			DefaultRetentionPolicy: "autogen",
+			RetentionPolicies:      map[string]*meta2.RetentionPolicyInfo{"autogen": {Name: "autogen"}},
+		},
+	}}
+	require.NoError(t, data.CreateUser("u1", "hash", false, false))
+	require.NoError(t, data.CreateRole("writer"))
+	p, err := meta2.NewRolePrivilege("db0", "autogen", "^cpu", true, originql.WritePrivilege)
+	require.NoError(t, err)
+	require.NoError(t, data.SetRolePrivilege("writer", p, false))
+	require.NoError(t, data.SetUserRole("u1", "writer", false))
+	user := data.GetUser("u1")
+
+	c := config.NewConfig()
+	c.AuthEnabled = true
+	h := NewHandler(c)
+	h.MetaClient = &writeAuthMetaClient{data: data}
+	h.WriteAuthorizer = denyWriteAuthorizer{}
+	pw := &importPointsWriter{}
+	h.PointsWriter = pw
+
+	write := func(body string) (*httptest.ResponseRecorder, *importer.Result) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/import?db=db0", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		h.serveImport(w, req, user)
+		res := &importer.Result{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		return w, res
+	}
+
+	w, res := write(`{"measurement":"cpu_load","fields":{"v":1}}` + "\n" + `{"measurement":"cpu","fields":{"v":2}}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 2, res.Written)
+	assert.Equal(t, 2, len(pw.rows))
+
+	w, res = write(`{"measurement":"cpu","fields":{"v":1}}` + "\n" + `{"measurement":"mem","fields":{"v":2}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, res.Error, `write to measurement "mem"`)
+	assert.Equal(t, 2, len(pw.rows))
+
+	require.NoError(t, data.SetUserRole("u1", "writer", true))
+	w = httptest.NewRecorder()
+	h.serveImport(w, httptest.NewRequest(http.MethodPost, "/api/v1/import?db=db0", strings.NewReader("")), user)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
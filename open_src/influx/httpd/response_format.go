@@ -0,0 +1,423 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpd
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/openGemini/openGemini/engine/executor"
+)
+
+const (
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+	contentTypeArrow  = "application/vnd.apache.arrow.stream"
+
+	// errorTrailer carries the error of a response that can not embed it
+	errorTrailer = "X-InfluxDB-Error"
+)
+
+type responseFormat int
+
+const (
+	formatJSON responseFormat = iota
+	formatCSV
+	formatNDJSON
+	formatArrow
+)
+
+var acceptFormats = map[string]responseFormat{
+	"application/json":      formatJSON,
+	"application/x-msgpack": formatJSON,
+	"application/*":         formatJSON,
+	"*/*":                   formatJSON,
+	"text/csv":              formatCSV,
+	"application/csv":       formatCSV,
+	contentTypeNDJSON:       formatNDJSON,
+	contentTypeArrow:        formatArrow,
+}
+
+type acceptItem struct {
+	mime string
+	q    float64
+}
+
+// negotiateFormat chooses the response format by the Accept header, the default is the influxdb formats
+func negotiateFormat(r *http.Request) responseFormat {
+	var items []acceptItem
+	for _, header := range r.Header["Accept"] {
+		for _, part := range strings.Split(header, ",") {
+			params := strings.Split(part, ";")
+			item := acceptItem{mime: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+			for _, p := range params[1:] {
+				p = strings.TrimSpace(p)
+				if strings.HasPrefix(p, "q=") {
+					if q, err := strconv.ParseFloat(p[2:], 64); err == nil {
+						item.q = q
+					}
+				}
+			}
+			if item.mime != "" && item.q > 0 {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	for _, item := range items {
+		if f, ok := acceptFormats[item.mime]; ok {
+			return f
+		}
+	}
+	return formatJSON
+}
+
+// resultEncoder writes the results of a query one by one without buffering them
+type resultEncoder interface {
+	ContentType() string
+	Encode(r *query.Result) error
+	Close() error
+}
+
+func newResultEncoder(format responseFormat, w *countingWriter) resultEncoder {
+	switch format {
+	case formatCSV:
+		return newCSVEncoder(w)
+	case formatNDJSON:
+		return &ndjsonEncoder{w: w}
+	case formatArrow:
+		return &arrowEncoder{w: w, cw: executor.NewArrowChunkWriter(w)}
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written, the arrow chunks are written by the query pipeline
+type countingWriter struct {
+	w http.ResponseWriter
+	n int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	atomic.AddInt64(&w.n, int64(n))
+	return n, err
+}
+
+func (w *countingWriter) Flush() {
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *countingWriter) Bytes() int64 {
+	return atomic.LoadInt64(&w.n)
+}
+
+// csvEncoder writes the same columns as the influxdb csv format: name, tags and the columns of the rows.
+// The header is written again when the statement or the columns change.
+type csvEncoder struct {
+	w           *countingWriter
+	csv         *csv.Writer
+	statementID int
+	columns     []string
+	record      []string
+}
+
+func newCSVEncoder(w *countingWriter) *csvEncoder {
+	return &csvEncoder{w: w, csv: csv.NewWriter(w), statementID: -1}
+}
+
+func (e *csvEncoder) ContentType() string {
+	return contentTypeCSV
+}
+
+func (e *csvEncoder) Encode(r *query.Result) error {
+	if r.Err != nil {
+		if err := e.writeHeader([]string{"error"}); err != nil {
+			return err
+		}
+		if err := e.csv.Write([]string{r.Err.Error()}); err != nil {
+			return err
+		}
+		e.columns = nil
+		return e.flush()
+	}
+
+	for _, row := range r.Series {
+		if r.StatementID != e.statementID || !stringsEqual(e.columns, row.Columns) {
+			header := append([]string{"name", "tags"}, row.Columns...)
+			if err := e.writeHeader(header); err != nil {
+				return err
+			}
+			e.statementID = r.StatementID
+			e.columns = row.Columns
+			e.record = make([]string, len(header))
+		}
+
+		e.record[0] = row.Name
+		e.record[1] = ""
+		if len(row.Tags) > 0 {
+			if key := models.NewTags(row.Tags).HashKey(); len(key) > 0 {
+				e.record[1] = string(key[1:])
+			}
+		}
+		for _, values := range row.Values {
+			for i := range e.record[2:] {
+				e.record[i+2] = ""
+				if i < len(values) {
+					e.record[i+2] = csvValue(values[i])
+				}
+			}
+			if err := e.csv.Write(e.record); err != nil {
+				return err
+			}
+		}
+	}
+	return e.flush()
+}
+
+// writeHeader separates the tables by an empty line
+func (e *csvEncoder) writeHeader(header []string) error {
+	if e.statementID >= 0 || e.columns != nil {
+		if err := e.flush(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, "\n"); err != nil {
+			return err
+		}
+	}
+	return e.csv.Write(header)
+}
+
+func (e *csvEncoder) flush() error {
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+func (e *csvEncoder) Close() error {
+	return e.flush()
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return strconv.FormatInt(v.UnixNano(), 10)
+	}
+	return ""
+}
+
+// ndjsonEncoder writes one JSON object per row, keyed by the column names.
+// The measurement, the tags and the statement id use the reserved keys _measurement, _tags and _statement_id.
+type ndjsonEncoder struct {
+	w   *countingWriter
+	buf []byte
+}
+
+func (e *ndjsonEncoder) ContentType() string {
+	return contentTypeNDJSON
+}
+
+func (e *ndjsonEncoder) Encode(r *query.Result) error {
+	if r.Err != nil {
+		e.buf = append(e.buf[:0], `{"_statement_id":`...)
+		e.buf = strconv.AppendInt(e.buf, int64(r.StatementID), 10)
+		e.buf = append(e.buf, `,"error":`...)
+		e.buf = appendJSONString(e.buf, r.Err.Error())
+		e.buf = append(e.buf, "}\n"...)
+		_, err := e.w.Write(e.buf)
+		return err
+	}
+
+	for _, row := range r.Series {
+		prefix := ndjsonRowPrefix(r.StatementID, row)
+		for _, values := range row.Values {
+			e.buf = append(e.buf[:0], prefix...)
+			for i, col := range row.Columns {
+				if i >= len(values) || values[i] == nil {
+					continue
+				}
+				e.buf = append(e.buf, ',')
+				e.buf = appendJSONString(e.buf, col)
+				e.buf = append(e.buf, ':')
+				e.buf = appendJSONValue(e.buf, values[i])
+			}
+			e.buf = append(e.buf, "}\n"...)
+			if _, err := e.w.Write(e.buf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+func ndjsonRowPrefix(statementID int, row *models.Row) []byte {
+	b := append([]byte(nil), `{"_statement_id":`...)
+	b = strconv.AppendInt(b, int64(statementID), 10)
+	if row.Name != "" {
+		b = append(b, `,"_measurement":`...)
+		b = appendJSONString(b, row.Name)
+	}
+	if len(row.Tags) > 0 {
+		keys := make([]string, 0, len(row.Tags))
+		for k := range row.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = append(b, `,"_tags":{`...)
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, k)
+			b = append(b, ':')
+			b = appendJSONString(b, row.Tags[k])
+		}
+		b = append(b, '}')
+	}
+	return b
+}
+
+func appendJSONValue(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case float64:
+		return appendJSONFloat(b, v)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case uint64:
+		return strconv.AppendUint(b, v, 10)
+	case string:
+		return appendJSONString(b, v)
+	case bool:
+		return strconv.AppendBool(b, v)
+	case time.Time:
+		b = append(b, '"')
+		b = v.AppendFormat(b, time.RFC3339Nano)
+		return append(b, '"')
+	}
+	return append(b, "null"...)
+}
+
+// appendJSONFloat formats the float like encoding/json, NaN and Inf are written as null
+func appendJSONFloat(b []byte, v float64) []byte {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return append(b, "null"...)
+	}
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	return strconv.AppendFloat(b, v, format, -1, 64)
+}
+
+const hexDigits = "0123456789abcdef"
+
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', c)
+		case c == '\n':
+			b = append(b, '\\', 'n')
+		case c == '\r':
+			b = append(b, '\\', 'r')
+		case c == '\t':
+			b = append(b, '\\', 't')
+		case c < 0x20:
+			b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			b = append(b, c)
+		}
+	}
+	return append(b, '"')
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrowEncoder writes the chunks of select statements directly, other results are converted from rows.
+// An IPC stream can not embed errors, the first error is returned in the X-InfluxDB-Error trailer.
+type arrowEncoder struct {
+	w   *countingWriter
+	cw  *executor.ArrowChunkWriter
+	err error
+}
+
+func (e *arrowEncoder) ContentType() string {
+	return contentTypeArrow
+}
+
+func (e *arrowEncoder) Encode(r *query.Result) error {
+	if r.Err != nil {
+		if e.err == nil {
+			e.err = r.Err
+		}
+		return nil
+	}
+	for _, row := range r.Series {
+		if err := e.cw.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *arrowEncoder) Close() error {
+	if err := e.cw.Close(); err != nil {
+		return err
+	}
+	if e.err != nil {
+		e.w.w.Header().Set(errorTrailer, e.err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpd
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/openGemini/openGemini/lib/errno"
+	"github.com/openGemini/openGemini/lib/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	cases := []struct {
+		accept string
+		format responseFormat
+	}{
+		{"", formatJSON},
+		{"application/json", formatJSON},
+		{"text/csv", formatCSV},
+		{"application/csv", formatCSV},
+		{"application/x-ndjson", formatNDJSON},
+		{"application/vnd.apache.arrow.stream", formatArrow},
+		{"application/json, text/csv", formatJSON},
+		{"application/json;q=0.5, application/x-ndjson", formatNDJSON},
+		{"text/html, application/vnd.apache.arrow.stream;q=0.9", formatArrow},
+		{"text/csv;q=0, */*", formatJSON},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/query", nil)
+		if c.accept != "" {
+			r.Header.Set("Accept", c.accept)
+		}
+		assert.Equal(t, c.format, negotiateFormat(r), c.accept)
+	}
+}
+
+func testResults() []*query.Result {
+	tm := time.Unix(0, 1000).UTC()
+	return []*query.Result{
+		{
+			StatementID: 0,
+			Series: models.Rows{
+				{Name: "cpu", Tags: map[string]string{"host": "a"}, Columns: []string{"time", "value", "ok"},
+					Values: [][]interface{}{{tm, 1.5, true}, {tm, nil, false}}},
+				{Name: "cpu", Tags: map[string]string{"host": "b"}, Columns: []string{"time", "value", "ok"},
+					Values: [][]interface{}{{tm, 2.0, nil}}},
+			},
+		},
+		{
+			StatementID: 1,
+			Series: models.Rows{
+				{Name: "databases", Columns: []string{"name"}, Values: [][]interface{}{{"db0"}}},
+			},
+		},
+		{StatementID: 2, Err: errors.New("measurement not found")},
+	}
+}
+
+func streamTestResults(t *testing.T, accept string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/query", nil)
+	r.Header.Set("Accept", accept)
+
+	cw := &countingWriter{w: rec}
+	enc := newResultEncoder(negotiateFormat(r), cw)
+	require.NotNil(t, enc)
+
+	results := make(chan *query.Result, 4)
+	for _, res := range testResults() {
+		results <- res
+	}
+	close(results)
+
+	h := &Handler{Logger: logger.NewLogger(errno.ModuleHTTP)}
+	h.streamResults(results, enc, cw, "")
+	assert.Equal(t, int64(rec.Body.Len()), cw.Bytes())
+	return rec
+}
+
+func TestStreamResults_CSV(t *testing.T) {
+	rec := streamTestResults(t, "text/csv")
+	exp := "name,tags,time,value,ok\n" +
+		"cpu,host=a,1000,1.5,true\n" +
+		"cpu,host=a,1000,,false\n" +
+		"cpu,host=b,1000,2,\n" +
+		"\n" +
+		"name,tags,name\n" +
+		"databases,,db0\n" +
+		"\n" +
+		"error\n" +
+		"measurement not found\n"
+	assert.Equal(t, exp, rec.Body.String())
+}
+
+func TestStreamResults_NDJSON(t *testing.T) {
+	rec := streamTestResults(t, "application/x-ndjson")
+	exp := `{"_statement_id":0,"_measurement":"cpu","_tags":{"host":"a"},"time":"1970-01-01T00:00:00.000001Z","value":1.5,"ok":true}` + "\n" +
+		`{"_statement_id":0,"_measurement":"cpu","_tags":{"host":"a"},"time":"1970-01-01T00:00:00.000001Z","ok":false}` + "\n" +
+		`{"_statement_id":0,"_measurement":"cpu","_tags":{"host":"b"},"time":"1970-01-01T00:00:00.000001Z","value":2}` + "\n" +
+		`{"_statement_id":1,"_measurement":"databases","name":"db0"}` + "\n" +
+		`{"_statement_id":2,"error":"measurement not found"}` + "\n"
+	assert.Equal(t, exp, rec.Body.String())
+}
+
+func TestStreamResults_Arrow(t *testing.T) {
+	rec := streamTestResults(t, "application/vnd.apache.arrow.stream")
+	assert.Equal(t, "measurement not found", rec.Header().Get(errorTrailer))
+
+	body := bytes.NewReader(rec.Body.Bytes())
+	var values []float64
+	for _, name := range []string{"cpu", "databases"} {
+		rd, err := ipc.NewReader(body)
+		require.NoError(t, err)
+		md := rd.Schema().Metadata()
+		assert.Equal(t, name, md.Values()[md.FindKey("measurement")])
+		for rd.Next() {
+			if name == "cpu" {
+				values = append(values, rd.Record().Column(2).(*array.Float64).Value(0))
+			}
+		}
+		rd.Release()
+	}
+	assert.Equal(t, []float64{1.5, 2}, values)
+}
+
+func TestAppendJSONString(t *testing.T) {
+	assert.Equal(t, `"a\"b\\c\n\u0001"`, string(appendJSONString(nil, "a\"b\\c\n\x01")))
+	assert.Equal(t, `null`, string(appendJSONValue(nil, math.NaN())))
+	assert.Equal(t, `1e+21`, string(appendJSONValue(nil, 1e21)))
+}
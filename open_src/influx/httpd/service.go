@@ -38,6 +38,9 @@ type Service struct {
 
 	Handler *Handler
 
+	// Flight serves the queries over Arrow Flight, it is nil unless flight-enabled is set
+	Flight *FlightService
+
 	Logger    *zap.Logger
 	whiteList string
 }
@@ -68,6 +71,9 @@ func NewService(c config.Config) *Service {
 	if c.UnixSocketGroup != nil {
 		s.unixSocketGroup = int(*c.UnixSocketGroup)
 	}
+	if c.FlightEnabled {
+		s.Flight = NewFlightService(c, s.Handler)
+	}
 	return s
 }
 
@@ -185,11 +191,20 @@ func (s *Service) Open() error {
 	for _, ln := range s.Ln {
 		go s.serveTCP(ln)
 	}
+
+	if s.Flight != nil {
+		return s.Flight.Open()
+	}
 	return nil
 }
 
 // Close closes the underlying listener.
 func (s *Service) Close() error {
+	if s.Flight != nil {
+		if err := s.Flight.Close(); err != nil {
+			return err
+		}
+	}
 	s.Handler.Close()
 
 	for _, ln := range s.Ln {
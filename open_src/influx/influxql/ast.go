@@ -265,6 +265,18 @@ func (*DropMeasurementStatement) node()            {}
 func (*DropRetentionPolicyStatement) node()        {}
 func (*DropSeriesStatement) node()                 {}
 func (*DropShardStatement) node()                  {}
+func (*DecommissionNodeStatement) node()           {}
+func (*CreateRoleStatement) node()                 {}
+func (*DropRoleStatement) node()                   {}
+func (*GrantRoleStatement) node()                  {}
+func (*RevokeRoleStatement) node()                 {}
+func (*GrantToRoleStatement) node()                {}
+func (*RevokeFromRoleStatement) node()             {}
+func (*ShowRolesStatement) node()                  {}
+func (*ShowGrantsForRoleStatement) node()          {}
+func (*CreateTokenStatement) node()                {}
+func (*RevokeTokenStatement) node()                {}
+func (*ShowTokensStatement) node()                 {}
 func (*DropSubscriptionStatement) node()           {}
 func (*DropUserStatement) node()                   {}
 func (*ExplainStatement) node()                    {}
@@ -423,6 +435,18 @@ func (*ShowShardGroupsStatement) stmt()            {}
 func (*ShowShardsStatement) stmt()                 {}
 func (*ShowStatsStatement) stmt()                  {}
 func (*DropShardStatement) stmt()                  {}
+func (*DecommissionNodeStatement) stmt()           {}
+func (*CreateRoleStatement) stmt()                 {}
+func (*DropRoleStatement) stmt()                   {}
+func (*GrantRoleStatement) stmt()                  {}
+func (*RevokeRoleStatement) stmt()                 {}
+func (*GrantToRoleStatement) stmt()                {}
+func (*RevokeFromRoleStatement) stmt()             {}
+func (*ShowRolesStatement) stmt()                  {}
+func (*ShowGrantsForRoleStatement) stmt()          {}
+func (*CreateTokenStatement) stmt()                {}
+func (*RevokeTokenStatement) stmt()                {}
+func (*ShowTokensStatement) stmt()                 {}
 func (*ShowSubscriptionsStatement) stmt()          {}
 func (*ShowDiagnosticsStatement) stmt()            {}
 func (*ShowTagKeyCardinalityStatement) stmt()      {}
@@ -1072,6 +1096,289 @@ func (s *RevokeAdminStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
 }
 
+// CreateRoleStatement represents a command for creating a new role.
+type CreateRoleStatement struct {
+	// Name of the role to be created.
+	Name string
+}
+
+// String returns a string representation of the create role statement.
+func (s *CreateRoleStatement) String() string {
+	return "CREATE ROLE " + QuoteIdent(s.Name)
+}
+
+// RequiredPrivileges returns the privilege required to execute a CreateRoleStatement.
+func (s *CreateRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// DropRoleStatement represents a command for dropping a role.
+type DropRoleStatement struct {
+	// Name of the role to drop.
+	Name string
+}
+
+// String returns a string representation of the drop role statement.
+func (s *DropRoleStatement) String() string {
+	return "DROP ROLE " + QuoteIdent(s.Name)
+}
+
+// RequiredPrivileges returns the privilege required to execute a DropRoleStatement.
+func (s *DropRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// GrantRoleStatement represents a command for granting a role to a user.
+type GrantRoleStatement struct {
+	// The role to grant.
+	Role string
+
+	// Who to grant the role to.
+	User string
+}
+
+// String returns a string representation of the grant role statement.
+func (s *GrantRoleStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("GRANT ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	_, _ = buf.WriteString(" TO ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a GrantRoleStatement.
+func (s *GrantRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// RevokeRoleStatement represents a command for revoking a role from a user.
+type RevokeRoleStatement struct {
+	// The role to revoke.
+	Role string
+
+	// Who to revoke the role from.
+	User string
+}
+
+// String returns a string representation of the revoke role statement.
+func (s *RevokeRoleStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("REVOKE ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	_, _ = buf.WriteString(" FROM ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeRoleStatement.
+func (s *RevokeRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// GrantToRoleStatement represents a command for granting a privilege to a role.
+// The privilege covers a database, a retention policy, a single measurement or
+// the measurements matching a regex.
+type GrantToRoleStatement struct {
+	// The privilege to be granted.
+	Privilege Privilege
+
+	// Scope of the privilege. Database is required, RetentionPolicy, Name
+	// and Regex are optional.
+	On *Measurement
+
+	// Role to grant the privilege to.
+	Role string
+}
+
+// String returns a string representation of the grant to role statement.
+func (s *GrantToRoleStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("GRANT ")
+	_, _ = buf.WriteString(s.Privilege.String())
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(privilegeScopeString(s.On))
+	_, _ = buf.WriteString(" TO ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a GrantToRoleStatement.
+func (s *GrantToRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *GrantToRoleStatement) DefaultDatabase() string {
+	return s.On.Database
+}
+
+// RevokeFromRoleStatement represents a command to revoke a privilege from a role.
+type RevokeFromRoleStatement struct {
+	// The privilege to be revoked.
+	Privilege Privilege
+
+	// Scope of the privilege, see GrantToRoleStatement.
+	On *Measurement
+
+	// Role to revoke the privilege from.
+	Role string
+}
+
+// String returns a string representation of the revoke from role statement.
+func (s *RevokeFromRoleStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("REVOKE ")
+	_, _ = buf.WriteString(s.Privilege.String())
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(privilegeScopeString(s.On))
+	_, _ = buf.WriteString(" FROM ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeFromRoleStatement.
+func (s *RevokeFromRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *RevokeFromRoleStatement) DefaultDatabase() string {
+	return s.On.Database
+}
+
+func privilegeScopeString(m *Measurement) string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString(QuoteIdent(m.Database))
+	if m.RetentionPolicy != "" {
+		_, _ = buf.WriteString(" RETENTION POLICY ")
+		_, _ = buf.WriteString(QuoteIdent(m.RetentionPolicy))
+	}
+	if m.Regex != nil {
+		_, _ = buf.WriteString(" MEASUREMENT ")
+		_, _ = buf.WriteString(m.Regex.String())
+	} else if m.Name != "" {
+		_, _ = buf.WriteString(" MEASUREMENT ")
+		_, _ = buf.WriteString(QuoteIdent(m.Name))
+	}
+	return buf.String()
+}
+
+// ShowRolesStatement represents a command for listing roles.
+type ShowRolesStatement struct{}
+
+// String returns a string representation of the show roles command.
+func (s *ShowRolesStatement) String() string {
+	return "SHOW ROLES"
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowRolesStatement.
+func (s *ShowRolesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// ShowGrantsForRoleStatement represents a command for listing role privileges.
+type ShowGrantsForRoleStatement struct {
+	// Name of the role to display privileges.
+	Name string
+}
+
+// String returns a string representation of the show grants for role.
+func (s *ShowGrantsForRoleStatement) String() string {
+	return "SHOW GRANTS FOR ROLE " + QuoteIdent(s.Name)
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowGrantsForRoleStatement.
+func (s *ShowGrantsForRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// TokenScope is a privilege on a database usable through an API token.
+type TokenScope struct {
+	Database  string
+	Privilege Privilege
+}
+
+// CreateTokenStatement represents a command for creating an API token.
+type CreateTokenStatement struct {
+	// Name of the token to be created.
+	Name string
+
+	// User authenticated by the token.
+	User string
+
+	// Privileges usable through the token. No scope means all the
+	// database privileges of the user.
+	Scopes []TokenScope
+
+	// Validity of the token, zero means it never expires.
+	Duration time.Duration
+}
+
+// String returns a string representation of the create token statement.
+func (s *CreateTokenStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("CREATE TOKEN ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" FOR ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	for i, scope := range s.Scopes {
+		if i == 0 {
+			_, _ = buf.WriteString(" WITH ")
+		} else {
+			_, _ = buf.WriteString(", ")
+		}
+		_, _ = buf.WriteString(scope.Privilege.String())
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(scope.Database))
+	}
+	if s.Duration > 0 {
+		_, _ = buf.WriteString(" DURATION ")
+		_, _ = buf.WriteString(FormatDuration(s.Duration))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a CreateTokenStatement.
+func (s *CreateTokenStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// RevokeTokenStatement represents a command for revoking an API token.
+type RevokeTokenStatement struct {
+	// Name of the token to revoke.
+	Name string
+}
+
+// String returns a string representation of the revoke token statement.
+func (s *RevokeTokenStatement) String() string {
+	return "REVOKE TOKEN " + QuoteIdent(s.Name)
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeTokenStatement.
+func (s *RevokeTokenStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
+// ShowTokensStatement represents a command for listing API tokens.
+type ShowTokensStatement struct {
+	// Only list the tokens of this user if set.
+	User string
+}
+
+// String returns a string representation of the show tokens command.
+func (s *ShowTokensStatement) String() string {
+	if s.User == "" {
+		return "SHOW TOKENS"
+	}
+	return "SHOW TOKENS FOR " + QuoteIdent(s.User)
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowTokensStatement.
+func (s *ShowTokensStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: false, Privilege: AllPrivileges}}, nil
+}
+
 // CreateRetentionPolicyStatement represents a command to create a retention policy.
 type CreateRetentionPolicyStatement struct {
 	// Name of policy to create.
@@ -1687,7 +1994,7 @@ func (s *SelectStatement) RewriteFields(m FieldMapper, batchEn bool) (*SelectSta
 
 				// Add additional types for certain functions.
 				switch call.Name {
-				case "count", "first", "last", "distinct", "elapsed", "mode", "sample", "absent":
+				case "count", "first", "last", "distinct", "elapsed", "mode", "sample", "absent", "approx_count_distinct":
 					supportedTypes[String] = struct{}{}
 					fallthrough
 				case "min", "max":
@@ -2746,6 +3053,27 @@ func (s *DropShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: true, Privilege: AllPrivileges}}, nil
 }
 
+// DecommissionNodeStatement represents a command for moving all pts out of a data node
+// and removing the node from the cluster.
+type DecommissionNodeStatement struct {
+	// ID of the data node to be decommissioned.
+	NodeID uint64
+}
+
+// String returns a string representation of the decommission node statement.
+func (s *DecommissionNodeStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("DECOMMISSION NODE ")
+	buf.WriteString(strconv.FormatUint(s.NodeID, 10))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// DecommissionNodeStatement.
+func (s *DecommissionNodeStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Rwuser: true, Privilege: AllPrivileges}}, nil
+}
+
 // ShowSeriesCardinalityStatement represents a command for listing series cardinality.
 type ShowSeriesCardinalityStatement struct {
 	// Database to query. If blank, use the default database.
@@ -96,40 +96,46 @@ const INDEXTYPE = 57426
 const INDEXLIST = 57427
 const QUERY = 57428
 const PARTITION = 57429
-const DESC = 57430
-const ASC = 57431
-const COMMA = 57432
-const SEMICOLON = 57433
-const LPAREN = 57434
-const RPAREN = 57435
-const REGEX = 57436
-const EQ = 57437
-const NEQ = 57438
-const LT = 57439
-const LTE = 57440
-const GT = 57441
-const GTE = 57442
-const DOT = 57443
-const DOUBLECOLON = 57444
-const NEQREGEX = 57445
-const EQREGEX = 57446
-const IDENT = 57447
-const INTEGER = 57448
-const DURATIONVAL = 57449
-const STRING = 57450
-const NUMBER = 57451
-const HINT = 57452
-const AND = 57453
-const OR = 57454
-const ADD = 57455
-const SUB = 57456
-const BITWISE_OR = 57457
-const BITWISE_XOR = 57458
-const MUL = 57459
-const DIV = 57460
-const MOD = 57461
-const BITWISE_AND = 57462
-const UMINUS = 57463
+const DECOMMISSION = 57430
+const NODE = 57431
+const ROLE = 57432
+const ROLES = 57433
+const TOKEN = 57434
+const TOKENS = 57435
+const DESC = 57436
+const ASC = 57437
+const COMMA = 57438
+const SEMICOLON = 57439
+const LPAREN = 57440
+const RPAREN = 57441
+const REGEX = 57442
+const EQ = 57443
+const NEQ = 57444
+const LT = 57445
+const LTE = 57446
+const GT = 57447
+const GTE = 57448
+const DOT = 57449
+const DOUBLECOLON = 57450
+const NEQREGEX = 57451
+const EQREGEX = 57452
+const IDENT = 57453
+const INTEGER = 57454
+const DURATIONVAL = 57455
+const STRING = 57456
+const NUMBER = 57457
+const HINT = 57458
+const AND = 57459
+const OR = 57460
+const ADD = 57461
+const SUB = 57462
+const BITWISE_OR = 57463
+const BITWISE_XOR = 57464
+const MUL = 57465
+const DIV = 57466
+const MOD = 57467
+const BITWISE_AND = 57468
+const UMINUS = 57469
 
 // Token is a lexical token of the InfluxQL language.
 type Token int
@@ -427,6 +433,12 @@ var tokens = [...]string{
 	REPLICANUM:    "REPLICANUM",
 	INDEXTYPE:     "INDEXTYPE",
 	INDEXLIST:     "INDEXLIST",
+	DECOMMISSION:  "DECOMMISSION",
+	NODE:          "NODE",
+	ROLE:          "ROLE",
+	ROLES:         "ROLES",
+	TOKEN:         "TOKEN",
+	TOKENS:        "TOKENS",
 }
 
 var keywords map[string]int
@@ -127,7 +127,13 @@ func (u *UserInfo) AuthorizeQuery(database string, query *influxql.Query) error
 			if db == "" {
 				db = database
 			}
-			if !u.AuthorizeDatabase(originql.Privilege(p.Privilege), db) {
+			authorize := u.AuthorizeDatabase
+			if _, ok := stmt.(*influxql.SelectStatement); ok {
+				// measurement privileges granted by roles are checked
+				// when the statement is compiled.
+				authorize = u.AuthorizeAnyMeasurement
+			}
+			if !authorize(originql.Privilege(p.Privilege), db) {
 				return &ErrAuthorize{
 					Query:    query,
 					User:     u.Name,
@@ -155,3 +161,8 @@ func (e ErrAuthorize) Error() string {
 	}
 	return fmt.Sprintf("%s not authorized to execute %s", e.User, e.Message)
 }
+
+// AuthorizationFailed marks the error as an authorization error.
+func (e ErrAuthorize) AuthorizationFailed() bool {
+	return true
+}
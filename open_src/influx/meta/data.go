@@ -88,6 +88,8 @@ type Data struct {
 
 	Databases     map[string]*DatabaseInfo
 	Users         []UserInfo
+	Roles         []RoleInfo
+	Tokens        []TokenInfo
 	MigrateEvents map[string]*MigrateEventInfo
 
 	// adminUserExists provides a constant time mechanism for determining
@@ -625,10 +627,49 @@ func (data *Data) SetDataNode(nodeID uint64, host, tcpHost string) error {
 	return nil
 }
 
+// DeleteDataNode removes a data node, the node must not own any pt any more.
 func (data *Data) DeleteDataNode(id uint64) error {
+	idx := -1
+	for i := range data.DataNodes {
+		if data.DataNodes[i].ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return ErrNodeNotFound
+	}
+
+	if ptNum := data.ptNumOfNode(id); ptNum > 0 {
+		return errno.NewError(errno.DataNodeNotDrained, id, ptNum)
+	}
+	data.DataNodes = append(data.DataNodes[:idx], data.DataNodes[idx+1:]...)
+	return nil
+}
+
+// DecommissionDataNode marks a data node as decommissioning,
+// the balancer moves all of its pts to other alive nodes before it can be deleted.
+func (data *Data) DecommissionDataNode(id uint64) error {
+	dn := data.DataNode(id)
+	if dn == nil {
+		return ErrNodeNotFound
+	}
+	dn.Decommissioning = true
 	return nil
 }
 
+func (data *Data) ptNumOfNode(id uint64) int {
+	n := 0
+	for db := range data.PtView {
+		for i := range data.PtView[db] {
+			if data.PtView[db][i].Owner.NodeID == id {
+				n++
+			}
+		}
+	}
+	return n
+}
+
 // CreateMetaNode will add a new meta node to the metastore
 func (data *Data) CreateMetaNode(httpAddr, rpcAddr, tcpAddr string) error {
 	// Ensure a node with the same host doesn't already exist.
@@ -930,6 +971,17 @@ func (data *Data) DropDatabase(name string) {
 	for i := range data.Users {
 		delete(data.Users[i].Privileges, name)
 	}
+	for i := range data.Roles {
+		ri := &data.Roles[i]
+		privileges := ri.Privileges[:0]
+		for _, p := range ri.Privileges {
+			if p.Database != name {
+				privileges = append(privileges, p)
+			}
+		}
+		ri.Privileges = privileges
+	}
+	data.resolveUserRoles()
 
 	if data.PtView != nil {
 		delete(data.PtView, name)
@@ -1698,6 +1750,7 @@ func (data *Data) DropUser(name string) error {
 	for i := range data.Users {
 		if data.Users[i].Name == name {
 			data.Users = append(data.Users[:i], data.Users[i+1:]...)
+			data.dropUserTokens(name)
 			return nil
 		}
 	}
@@ -1793,6 +1846,193 @@ func (data *Data) UserPrivilege(name, database string) (*originql.Privilege, err
 	return originql.NewPrivilege(originql.NoPrivileges), nil
 }
 
+// Role returns a role by name.
+func (data *Data) Role(name string) *RoleInfo {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			return &data.Roles[i]
+		}
+	}
+	return nil
+}
+
+// CreateRole creates a new role without any privilege.
+func (data *Data) CreateRole(name string) error {
+	if name == "" {
+		return ErrRoleNameRequired
+	} else if data.Role(name) != nil {
+		return ErrRoleExists
+	}
+	data.Roles = append(data.Roles, RoleInfo{Name: name})
+	return nil
+}
+
+// DropRole removes a role and revokes it from all users.
+func (data *Data) DropRole(name string) error {
+	for i := range data.Roles {
+		if data.Roles[i].Name != name {
+			continue
+		}
+		data.Roles = append(data.Roles[:i], data.Roles[i+1:]...)
+		for j := range data.Users {
+			data.Users[j].removeRole(name)
+		}
+		data.resolveUserRoles()
+		return nil
+	}
+	return ErrRoleNotFound
+}
+
+// SetRolePrivilege grants a privilege to a role, or revokes it if revoke is true.
+func (data *Data) SetRolePrivilege(role string, p RolePrivilege, revoke bool) error {
+	ri := data.Role(role)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	if !revoke {
+		dbi, err := data.GetDatabase(p.Database)
+		if err != nil {
+			return err
+		}
+		if p.RetentionPolicy != "" && dbi.RetentionPolicy(p.RetentionPolicy) == nil {
+			return ErrRetentionPolicyNotFound(p.RetentionPolicy)
+		}
+	}
+
+	ri.setPrivilege(p, revoke)
+	data.resolveUserRoles()
+	return nil
+}
+
+// SetUserRole grants a role to a user, or revokes it if revoke is true.
+func (data *Data) SetUserRole(username, role string, revoke bool) error {
+	ui := data.GetUser(username)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+	if data.Role(role) == nil {
+		return ErrRoleNotFound
+	}
+
+	if revoke {
+		ui.removeRole(role)
+	} else if !ui.HasRole(role) {
+		ui.Roles = append(ui.Roles, role)
+	}
+	data.resolveUserRoles()
+	return nil
+}
+
+// CloneRoles returns a copy of the role infos.
+func (data *Data) CloneRoles() []RoleInfo {
+	if len(data.Roles) == 0 {
+		return nil
+	}
+	roles := make([]RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		roles[i] = data.Roles[i].clone()
+	}
+	return roles
+}
+
+// Token returns a token by name.
+func (data *Data) Token(name string) *TokenInfo {
+	for i := range data.Tokens {
+		if data.Tokens[i].Name == name {
+			return &data.Tokens[i]
+		}
+	}
+	return nil
+}
+
+// TokenByHash returns the token whose secret has the given hash.
+func (data *Data) TokenByHash(hash string) *TokenInfo {
+	for i := range data.Tokens {
+		if data.Tokens[i].Hash == hash {
+			return &data.Tokens[i]
+		}
+	}
+	return nil
+}
+
+// CreateToken stores a new API token of an existing user.
+func (data *Data) CreateToken(ti TokenInfo) error {
+	if ti.Name == "" {
+		return ErrTokenNameRequired
+	} else if data.Token(ti.Name) != nil {
+		return ErrTokenExists
+	} else if data.GetUser(ti.User) == nil {
+		return ErrUserNotFound
+	}
+	for db := range ti.Scopes {
+		if _, err := data.GetDatabase(db); err != nil {
+			return err
+		}
+	}
+	data.Tokens = append(data.Tokens, ti.clone())
+	return nil
+}
+
+// DropToken removes a token, revoking it immediately.
+func (data *Data) DropToken(name string) error {
+	for i := range data.Tokens {
+		if data.Tokens[i].Name == name {
+			data.Tokens = append(data.Tokens[:i], data.Tokens[i+1:]...)
+			return nil
+		}
+	}
+	return ErrTokenNotFound
+}
+
+// UpdateTokenLastUsed records the last time a token authenticated a request.
+func (data *Data) UpdateTokenLastUsed(name string, lastUsed time.Time) error {
+	ti := data.Token(name)
+	if ti == nil {
+		return ErrTokenNotFound
+	}
+	if lastUsed.After(ti.LastUsed) {
+		ti.LastUsed = lastUsed
+	}
+	return nil
+}
+
+func (data *Data) dropUserTokens(user string) {
+	tokens := data.Tokens[:0]
+	for i := range data.Tokens {
+		if data.Tokens[i].User != user {
+			tokens = append(tokens, data.Tokens[i])
+		}
+	}
+	data.Tokens = tokens
+}
+
+// CloneTokens returns a copy of the token infos.
+func (data *Data) CloneTokens() []TokenInfo {
+	if len(data.Tokens) == 0 {
+		return nil
+	}
+	tokens := make([]TokenInfo, len(data.Tokens))
+	for i := range data.Tokens {
+		tokens[i] = data.Tokens[i].clone()
+	}
+	return tokens
+}
+
+// resolveUserRoles refreshes the privileges users hold through their roles.
+// It must be called after any change of the roles or of the user roles.
+func (data *Data) resolveUserRoles() {
+	for i := range data.Users {
+		ui := &data.Users[i]
+		ui.rolePrivileges = nil
+		for _, name := range ui.Roles {
+			if ri := data.Role(name); ri != nil {
+				ui.rolePrivileges = append(ui.rolePrivileges, ri.Privileges...)
+			}
+		}
+	}
+}
+
 // Clone returns a copy of data with a new version.
 func (data *Data) Clone() *Data {
 	other := *data
@@ -1803,6 +2043,8 @@ func (data *Data) Clone() *Data {
 
 	other.Databases = data.CloneDatabases()
 	other.Users = data.CloneUsers()
+	other.Roles = data.CloneRoles()
+	other.Tokens = data.CloneTokens()
 	other.PtView = data.CloneDBPtView()
 	other.MigrateEvents = data.CloneMigrateEvents()
 	return &other
@@ -1862,6 +2104,16 @@ func (data *Data) Marshal() *proto2.Data {
 		pb.Users[i] = data.Users[i].marshal()
 	}
 
+	pb.Roles = make([]*proto2.RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		pb.Roles[i] = data.Roles[i].marshal()
+	}
+
+	pb.Tokens = make([]*proto2.TokenInfo, len(data.Tokens))
+	for i := range data.Tokens {
+		pb.Tokens[i] = data.Tokens[i].Marshal()
+	}
+
 	pb.MigrateEvents = make([]*proto2.MigrateEventInfo, len(data.MigrateEvents))
 	i = 0
 	for eventStr := range data.MigrateEvents {
@@ -1917,6 +2169,23 @@ func (data *Data) Unmarshal(pb *proto2.Data) {
 		data.Users[i].unmarshal(x)
 	}
 
+	data.Roles = nil
+	if len(pb.GetRoles()) > 0 {
+		data.Roles = make([]RoleInfo, len(pb.GetRoles()))
+	}
+	for i, x := range pb.GetRoles() {
+		data.Roles[i].unmarshal(x)
+	}
+	data.resolveUserRoles()
+
+	data.Tokens = nil
+	if len(pb.GetTokens()) > 0 {
+		data.Tokens = make([]TokenInfo, len(pb.GetTokens()))
+	}
+	for i, x := range pb.GetTokens() {
+		data.Tokens[i].Unmarshal(x)
+	}
+
 	data.MigrateEvents = make(map[string]*MigrateEventInfo, len(pb.GetMigrateEvents()))
 	for _, me := range pb.GetMigrateEvents() {
 		mei := &MigrateEventInfo{}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/influxdata/influxdb/logger"
+	originql "github.com/influxdata/influxql"
 	"github.com/openGemini/openGemini/lib/errno"
 	"github.com/openGemini/openGemini/open_src/influx/influxql"
 	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
@@ -410,6 +411,148 @@ func TestData_UserCmd(t *testing.T) {
 	require.EqualError(t, data.DropUser(""), ErrUserNotFound.Error())
 }
 
+func TestData_RoleCmd(t *testing.T) {
+	data := &Data{Databases: map[string]*DatabaseInfo{
+		"db0": {
+			Name:                   "db0",
+			DefaultRetentionPolicy: "rp0",
+			RetentionPolicies:      map[string]*RetentionPolicyInfo{"rp0": {Name: "rp0"}, "rp1": {Name: "rp1"}},
+		},
+	}}
+	require.NoError(t, data.CreateUser("user1", "xxxxhashxxxx", false, false))
+	require.EqualError(t, data.CreateRole(""), ErrRoleNameRequired.Error())
+	require.NoError(t, data.CreateRole("reader"))
+	require.EqualError(t, data.CreateRole("reader"), ErrRoleExists.Error())
+
+	readCPU, err := NewRolePrivilege("db0", "rp0", "^cpu", true, originql.ReadPrivilege)
+	require.NoError(t, err)
+	writeMem, err := NewRolePrivilege("db0", "", "mem", false, originql.AllPrivileges)
+	require.NoError(t, err)
+	_, err = NewRolePrivilege("db0", "", "[", true, originql.ReadPrivilege)
+	require.Error(t, err)
+
+	require.EqualError(t, data.SetRolePrivilege("none", readCPU, false), ErrRoleNotFound.Error())
+	noRP, _ := NewRolePrivilege("db0", "rp2", "", false, originql.ReadPrivilege)
+	require.EqualError(t, data.SetRolePrivilege("reader", noRP, false), ErrRetentionPolicyNotFound("rp2").Error())
+	require.NoError(t, data.SetRolePrivilege("reader", readCPU, false))
+	require.NoError(t, data.SetRolePrivilege("reader", writeMem, false))
+	require.EqualError(t, data.SetUserRole("user1", "none", false), ErrRoleNotFound.Error())
+	require.EqualError(t, data.SetUserRole("none", "reader", false), ErrUserNotFound.Error())
+	require.NoError(t, data.SetUserRole("user1", "reader", false))
+	require.NoError(t, data.SetUserRole("user1", "reader", false))
+
+	check := func(u *UserInfo) {
+		require.Equal(t, []string{"reader"}, u.Roles)
+		assert2.True(t, u.AuthorizeMeasurement(originql.ReadPrivilege, "db0", "rp0", "cpu_load"))
+		assert2.False(t, u.AuthorizeMeasurement(originql.WritePrivilege, "db0", "rp0", "cpu_load"))
+		assert2.False(t, u.AuthorizeMeasurement(originql.ReadPrivilege, "db0", "rp1", "cpu_load"))
+		assert2.False(t, u.AuthorizeMeasurement(originql.ReadPrivilege, "db0", "rp0", ""))
+		assert2.True(t, u.AuthorizeMeasurement(originql.WritePrivilege, "db0", "rp1", "mem"))
+		assert2.False(t, u.AuthorizeDatabase(originql.ReadPrivilege, "db0"))
+		assert2.True(t, u.AuthorizeAnyMeasurement(originql.ReadPrivilege, "db0"))
+		assert2.False(t, u.AuthorizeAnyMeasurement(originql.ReadPrivilege, "db1"))
+	}
+	check(data.GetUser("user1"))
+	check(&data.Clone().Users[0])
+
+	buf, err := data.MarshalBinary()
+	require.NoError(t, err)
+	other := &Data{}
+	require.NoError(t, other.UnmarshalBinary(buf))
+	check(other.GetUser("user1"))
+	require.Equal(t, data.Roles[0].Privileges[0].Measurement, other.Roles[0].Privileges[0].Measurement)
+
+	// revoking write from all privileges keeps read
+	writeMem.Privilege = originql.WritePrivilege
+	require.NoError(t, data.SetRolePrivilege("reader", writeMem, true))
+	u := data.GetUser("user1")
+	assert2.True(t, u.AuthorizeMeasurement(originql.ReadPrivilege, "db0", "rp1", "mem"))
+	assert2.False(t, u.AuthorizeMeasurement(originql.WritePrivilege, "db0", "rp1", "mem"))
+
+	// a role privilege on the whole database counts as a database privilege
+	allDB, _ := NewRolePrivilege("db0", "", "", false, originql.ReadPrivilege)
+	require.NoError(t, data.SetRolePrivilege("reader", allDB, false))
+	assert2.True(t, data.GetUser("user1").AuthorizeDatabase(originql.ReadPrivilege, "db0"))
+
+	data.DropDatabase("db0")
+	require.Equal(t, 0, len(data.Roles[0].Privileges))
+	assert2.False(t, data.GetUser("user1").AuthorizeAnyMeasurement(originql.ReadPrivilege, "db0"))
+
+	require.NoError(t, data.DropRole("reader"))
+	require.EqualError(t, data.DropRole("reader"), ErrRoleNotFound.Error())
+	require.Equal(t, 0, len(data.GetUser("user1").Roles))
+}
+
+func TestData_TokenCmd(t *testing.T) {
+	data := &Data{Databases: map[string]*DatabaseInfo{
+		"db0": {Name: "db0"},
+		"db1": {Name: "db1"},
+	}}
+	require.NoError(t, data.CreateUser("user1", "xxxxhashxxxx", false, false))
+	require.NoError(t, data.SetPrivilege("user1", "db0", originql.AllPrivileges))
+	require.NoError(t, data.SetPrivilege("user1", "db1", originql.ReadPrivilege))
+
+	now := time.Now().UTC().Round(0)
+	token := TokenInfo{
+		Name:      "t1",
+		User:      "user1",
+		Hash:      HashToken("secret"),
+		Scopes:    map[string]originql.Privilege{"db0": originql.ReadPrivilege, "db1": originql.AllPrivileges},
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+	require.EqualError(t, data.CreateToken(TokenInfo{User: "user1"}), ErrTokenNameRequired.Error())
+	require.EqualError(t, data.CreateToken(TokenInfo{Name: "t0", User: "none"}), ErrUserNotFound.Error())
+	require.EqualError(t, data.CreateToken(TokenInfo{Name: "t0", User: "user1",
+		Scopes: map[string]originql.Privilege{"db2": originql.ReadPrivilege}}), errno.NewError(errno.DatabaseNotFound, "db2").Error())
+	require.NoError(t, data.CreateToken(token))
+	require.EqualError(t, data.CreateToken(token), ErrTokenExists.Error())
+
+	require.Nil(t, data.TokenByHash(HashToken("other")))
+	ti := data.TokenByHash(HashToken("secret"))
+	require.NotNil(t, ti)
+	assert2.False(t, ti.Expired(now))
+	assert2.True(t, ti.Expired(now.Add(time.Hour)))
+
+	// the token privileges are the intersection of the scopes and the user privileges
+	u := &TokenUser{UserInfo: data.GetUser("user1"), Token: *ti}
+	assert2.True(t, u.AuthorizeDatabase(originql.ReadPrivilege, "db0"))
+	assert2.False(t, u.AuthorizeDatabase(originql.WritePrivilege, "db0"))
+	assert2.True(t, u.AuthorizeDatabase(originql.ReadPrivilege, "db1"))
+	assert2.False(t, u.AuthorizeDatabase(originql.WritePrivilege, "db1"))
+	assert2.False(t, u.AuthorizeAnyMeasurement(originql.WritePrivilege, "db0"))
+	assert2.False(t, u.AuthorizeUnrestricted())
+
+	q, err := influxql.ParseQuery("SELECT * FROM cpu; DROP DATABASE db0")
+	require.NoError(t, err)
+	require.NoError(t, u.AuthorizeQuery("db0", &influxql.Query{Statements: q.Statements[:1]}))
+	require.Error(t, u.AuthorizeQuery("db0", &influxql.Query{Statements: q.Statements[1:]}))
+
+	// a token of an admin never allows admin statements
+	require.NoError(t, data.CreateUser("admin", "xxxxhashxxxx", true, false))
+	admin := &TokenUser{UserInfo: data.GetUser("admin"), Token: TokenInfo{Name: "t2"}}
+	assert2.True(t, admin.AuthorizeDatabase(originql.WritePrivilege, "db1"))
+	require.NoError(t, admin.AuthorizeQuery("db0", &influxql.Query{Statements: q.Statements[:1]}))
+	require.Error(t, admin.AuthorizeQuery("db0", &influxql.Query{Statements: q.Statements[1:]}))
+
+	require.NoError(t, data.UpdateTokenLastUsed("t1", now))
+	require.EqualError(t, data.UpdateTokenLastUsed("none", now), ErrTokenNotFound.Error())
+
+	buf, err := data.MarshalBinary()
+	require.NoError(t, err)
+	other := &Data{}
+	require.NoError(t, other.UnmarshalBinary(buf))
+	require.Equal(t, data.Tokens, other.Tokens)
+	require.Equal(t, data.Tokens, data.Clone().Tokens)
+
+	require.NoError(t, data.DropToken("t1"))
+	require.EqualError(t, data.DropToken("t1"), ErrTokenNotFound.Error())
+
+	// dropping a user revokes its tokens
+	require.NoError(t, other.DropUser("user1"))
+	require.Equal(t, 0, len(other.Tokens))
+}
+
 func TestData_UpdateRetentionPolicy(t *testing.T) {
 	data := initData()
 	database := "alterDb"
@@ -1166,6 +1309,36 @@ func TestData_CreateMigrateEvent(t *testing.T) {
 	assert2.Equal(t, uint64(1), data.MigrateEvents[dbPt1.String()].opId)
 }
 
+func TestData_DecommissionAndDeleteDataNode(t *testing.T) {
+	data := &Data{PtNumPerNode: 1}
+	DataLogger = logger.New(os.Stderr)
+	for _, host := range []string{"127.0.0.1", "127.0.0.2"} {
+		if err, _ := data.CreateDataNode(host+":8400", host+":8401"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := data.CreateDatabase("db0", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	assert2.Equal(t, ErrNodeNotFound, data.DecommissionDataNode(3))
+	assert2.NoError(t, data.DecommissionDataNode(2))
+	assert2.True(t, data.DataNode(2).Decommissioning)
+
+	clone := &Data{}
+	clone.Unmarshal(data.Marshal())
+	assert2.True(t, clone.DataNode(2).Decommissioning)
+	assert2.False(t, clone.DataNode(1).Decommissioning)
+
+	err := data.DeleteDataNode(2)
+	assert2.True(t, errno.Equal(err, errno.DataNodeNotDrained))
+
+	data.updatePtStatus("db0", 1, 1, Online)
+	assert2.NoError(t, data.DeleteDataNode(2))
+	assert2.Nil(t, data.DataNode(2))
+	assert2.Equal(t, ErrNodeNotFound, data.DeleteDataNode(2))
+}
+
 func PrintMemUsage() {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
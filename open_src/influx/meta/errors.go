@@ -196,6 +196,27 @@ var (
 	// ErrUsernameRequired is returned when creating a GetUser without a username.
 	ErrUsernameRequired = errors.New("username required")
 
+	// ErrRoleExists is returned when creating an already existing role.
+	ErrRoleExists = errors.New("role already exists")
+
+	// ErrRoleNotFound is returned when mutating a role that doesn't exist.
+	ErrRoleNotFound = errors.New("role not found")
+
+	// ErrRoleNameRequired is returned when creating a role without a name.
+	ErrRoleNameRequired = errors.New("role name required")
+
+	// ErrTokenExists is returned when creating an already existing token.
+	ErrTokenExists = errors.New("token already exists")
+
+	// ErrTokenNotFound is returned when mutating a token that doesn't exist.
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrTokenNameRequired is returned when creating a token without a name.
+	ErrTokenNameRequired = errors.New("token name required")
+
+	// ErrTokenExpired is returned when authenticating with an expired token.
+	ErrTokenExpired = errors.New("token expired")
+
 	// ErrAuthenticate is returned when authentication fails.
 	ErrAuthenticate = errors.New("authentication failed")
 
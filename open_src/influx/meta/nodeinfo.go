@@ -66,6 +66,7 @@ func (ni *NodeInfo) unmarshal(pb *proto2.NodeInfo) {
 
 type DataNode struct {
 	NodeInfo
+	Decommissioning bool // pts are being moved out before the node is deleted
 }
 
 func (n *DataNode) MarshalBinary() ([]byte, error) {
@@ -84,10 +85,14 @@ func (n *DataNode) UnmarshalBinary(buf []byte) error {
 func (n *DataNode) marshal() *proto2.DataNode {
 	pb := &proto2.DataNode{}
 	pb.Ni = n.NodeInfo.marshal()
+	if n.Decommissioning {
+		pb.Decommissioning = proto.Bool(true)
+	}
 	return pb
 }
 func (n *DataNode) unmarshal(pb *proto2.DataNode) {
 	n.NodeInfo.unmarshal(pb.GetNi())
+	n.Decommissioning = pb.GetDecommissioning()
 }
 
 // NodeInfos is a slice of NodeInfo used for sorting
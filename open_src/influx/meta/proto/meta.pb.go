@@ -67,6 +67,14 @@ const (
 	Command_UpdateEventCommand               Command_Type = 66
 	Command_UpdatePtInfoCommand              Command_Type = 67
 	Command_RemoveEventCommand               Command_Type = 68
+	Command_DecommissionDataNodeCommand      Command_Type = 69
+	Command_CreateRoleCommand                Command_Type = 70
+	Command_DropRoleCommand                  Command_Type = 71
+	Command_SetRolePrivilegeCommand          Command_Type = 72
+	Command_SetUserRoleCommand               Command_Type = 73
+	Command_CreateTokenCommand               Command_Type = 74
+	Command_DropTokenCommand                 Command_Type = 75
+	Command_UpdateTokenLastUsedCommand       Command_Type = 76
 )
 
 var Command_Type_name = map[int32]string{
@@ -113,6 +121,14 @@ var Command_Type_name = map[int32]string{
 	66: "UpdateEventCommand",
 	67: "UpdatePtInfoCommand",
 	68: "RemoveEventCommand",
+	69: "DecommissionDataNodeCommand",
+	70: "CreateRoleCommand",
+	71: "DropRoleCommand",
+	72: "SetRolePrivilegeCommand",
+	73: "SetUserRoleCommand",
+	74: "CreateTokenCommand",
+	75: "DropTokenCommand",
+	76: "UpdateTokenLastUsedCommand",
 }
 
 var Command_Type_value = map[string]int32{
@@ -159,6 +175,14 @@ var Command_Type_value = map[string]int32{
 	"UpdateEventCommand":               66,
 	"UpdatePtInfoCommand":              67,
 	"RemoveEventCommand":               68,
+	"DecommissionDataNodeCommand":      69,
+	"CreateRoleCommand":                70,
+	"DropRoleCommand":                  71,
+	"SetRolePrivilegeCommand":          72,
+	"SetUserRoleCommand":               73,
+	"CreateTokenCommand":               74,
+	"DropTokenCommand":                 75,
+	"UpdateTokenLastUsedCommand":       76,
 }
 
 func (x Command_Type) Enum() *Command_Type {
@@ -181,7 +205,7 @@ func (x *Command_Type) UnmarshalJSON(data []byte) error {
 }
 
 func (Command_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{19, 0}
+	return fileDescriptor_4aed0c02de55ead8, []int{22, 0}
 }
 
 type Data struct {
@@ -204,6 +228,8 @@ type Data struct {
 	MaxEventOpId         *uint64              `protobuf:"varint,19,opt,name=MaxEventOpId" json:"MaxEventOpId,omitempty"`
 	TakeOverEnabled      *bool                `protobuf:"varint,20,opt,name=TakeOverEnabled" json:"TakeOverEnabled,omitempty"`
 	MigrateEvents        []*MigrateEventInfo  `protobuf:"bytes,21,rep,name=MigrateEvents" json:"MigrateEvents,omitempty"`
+	Roles                []*RoleInfo          `protobuf:"bytes,22,rep,name=Roles" json:"Roles,omitempty"`
+	Tokens               []*TokenInfo         `protobuf:"bytes,23,rep,name=Tokens" json:"Tokens,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -366,6 +392,20 @@ func (m *Data) GetMigrateEvents() []*MigrateEventInfo {
 	return nil
 }
 
+func (m *Data) GetRoles() []*RoleInfo {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *Data) GetTokens() []*TokenInfo {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
 type PtOwner struct {
 	NodeID               *uint64  `protobuf:"varint,1,req,name=NodeID" json:"NodeID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -584,6 +624,7 @@ func (m *NodeInfo) GetGossipAddr() string {
 
 type DataNode struct {
 	Ni                   *NodeInfo `protobuf:"bytes,1,req,name=Ni" json:"Ni,omitempty"`
+	Decommissioning      *bool     `protobuf:"varint,2,opt,name=Decommissioning" json:"Decommissioning,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
 	XXX_unrecognized     []byte    `json:"-"`
 	XXX_sizecache        int32     `json:"-"`
@@ -620,6 +661,13 @@ func (m *DataNode) GetNi() *NodeInfo {
 	return nil
 }
 
+func (m *DataNode) GetDecommissioning() bool {
+	if m != nil && m.Decommissioning != nil {
+		return *m.Decommissioning
+	}
+	return false
+}
+
 type DatabaseInfo struct {
 	Name                   *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	DefaultRetentionPolicy *string                `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
@@ -1257,6 +1305,7 @@ type UserInfo struct {
 	Admin                *bool            `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
 	RwUser               *bool            `protobuf:"varint,4,opt,name=RwUser" json:"RwUser,omitempty"`
 	Privileges           []*UserPrivilege `protobuf:"bytes,5,rep,name=Privileges" json:"Privileges,omitempty"`
+	Roles                []string         `protobuf:"bytes,6,rep,name=Roles" json:"Roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -1321,6 +1370,13 @@ func (m *UserInfo) GetPrivileges() []*UserPrivilege {
 	return nil
 }
 
+func (m *UserInfo) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
 type UserPrivilege struct {
 	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Privilege            *int32   `protobuf:"varint,2,req,name=Privilege" json:"Privilege,omitempty"`
@@ -1367,6 +1423,208 @@ func (m *UserPrivilege) GetPrivilege() int32 {
 	return 0
 }
 
+type RoleInfo struct {
+	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Privileges           []*RolePrivilege `protobuf:"bytes,2,rep,name=Privileges" json:"Privileges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RoleInfo) Reset()         { *m = RoleInfo{} }
+func (m *RoleInfo) String() string { return proto.CompactTextString(m) }
+func (*RoleInfo) ProtoMessage()    {}
+func (*RoleInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{17}
+}
+func (m *RoleInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleInfo.Unmarshal(m, b)
+}
+func (m *RoleInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleInfo.Marshal(b, m, deterministic)
+}
+func (m *RoleInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleInfo.Merge(m, src)
+}
+func (m *RoleInfo) XXX_Size() int {
+	return xxx_messageInfo_RoleInfo.Size(m)
+}
+func (m *RoleInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleInfo proto.InternalMessageInfo
+
+func (m *RoleInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *RoleInfo) GetPrivileges() []*RolePrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
+type RolePrivilege struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy      *string  `protobuf:"bytes,2,opt,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Measurement          *string  `protobuf:"bytes,3,opt,name=Measurement" json:"Measurement,omitempty"`
+	Regex                *bool    `protobuf:"varint,4,opt,name=Regex" json:"Regex,omitempty"`
+	Privilege            *int32   `protobuf:"varint,5,req,name=Privilege" json:"Privilege,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RolePrivilege) Reset()         { *m = RolePrivilege{} }
+func (m *RolePrivilege) String() string { return proto.CompactTextString(m) }
+func (*RolePrivilege) ProtoMessage()    {}
+func (*RolePrivilege) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{18}
+}
+func (m *RolePrivilege) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RolePrivilege.Unmarshal(m, b)
+}
+func (m *RolePrivilege) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RolePrivilege.Marshal(b, m, deterministic)
+}
+func (m *RolePrivilege) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RolePrivilege.Merge(m, src)
+}
+func (m *RolePrivilege) XXX_Size() int {
+	return xxx_messageInfo_RolePrivilege.Size(m)
+}
+func (m *RolePrivilege) XXX_DiscardUnknown() {
+	xxx_messageInfo_RolePrivilege.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RolePrivilege proto.InternalMessageInfo
+
+func (m *RolePrivilege) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *RolePrivilege) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *RolePrivilege) GetMeasurement() string {
+	if m != nil && m.Measurement != nil {
+		return *m.Measurement
+	}
+	return ""
+}
+
+func (m *RolePrivilege) GetRegex() bool {
+	if m != nil && m.Regex != nil {
+		return *m.Regex
+	}
+	return false
+}
+
+func (m *RolePrivilege) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
+type TokenInfo struct {
+	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	User                 *string          `protobuf:"bytes,2,req,name=User" json:"User,omitempty"`
+	Hash                 *string          `protobuf:"bytes,3,req,name=Hash" json:"Hash,omitempty"`
+	Scopes               []*UserPrivilege `protobuf:"bytes,4,rep,name=Scopes" json:"Scopes,omitempty"`
+	CreatedAt            *int64           `protobuf:"varint,5,req,name=CreatedAt" json:"CreatedAt,omitempty"`
+	ExpiresAt            *int64           `protobuf:"varint,6,opt,name=ExpiresAt" json:"ExpiresAt,omitempty"`
+	LastUsed             *int64           `protobuf:"varint,7,opt,name=LastUsed" json:"LastUsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *TokenInfo) Reset()         { *m = TokenInfo{} }
+func (m *TokenInfo) String() string { return proto.CompactTextString(m) }
+func (*TokenInfo) ProtoMessage()    {}
+func (*TokenInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{19}
+}
+func (m *TokenInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenInfo.Unmarshal(m, b)
+}
+func (m *TokenInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenInfo.Marshal(b, m, deterministic)
+}
+func (m *TokenInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenInfo.Merge(m, src)
+}
+func (m *TokenInfo) XXX_Size() int {
+	return xxx_messageInfo_TokenInfo.Size(m)
+}
+func (m *TokenInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenInfo proto.InternalMessageInfo
+
+func (m *TokenInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetHash() string {
+	if m != nil && m.Hash != nil {
+		return *m.Hash
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetScopes() []*UserPrivilege {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *TokenInfo) GetCreatedAt() int64 {
+	if m != nil && m.CreatedAt != nil {
+		return *m.CreatedAt
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetExpiresAt() int64 {
+	if m != nil && m.ExpiresAt != nil {
+		return *m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetLastUsed() int64 {
+	if m != nil && m.LastUsed != nil {
+		return *m.LastUsed
+	}
+	return 0
+}
+
 type IndexRelation struct {
 	Rid                  *uint32      `protobuf:"varint,1,req,name=Rid" json:"Rid,omitempty"`
 	Oid                  *uint32      `protobuf:"varint,2,req,name=Oid" json:"Oid,omitempty"`
@@ -1381,7 +1639,7 @@ func (m *IndexRelation) Reset()         { *m = IndexRelation{} }
 func (m *IndexRelation) String() string { return proto.CompactTextString(m) }
 func (*IndexRelation) ProtoMessage()    {}
 func (*IndexRelation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{17}
+	return fileDescriptor_4aed0c02de55ead8, []int{20}
 }
 func (m *IndexRelation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IndexRelation.Unmarshal(m, b)
@@ -1440,7 +1698,7 @@ func (m *IndexList) Reset()         { *m = IndexList{} }
 func (m *IndexList) String() string { return proto.CompactTextString(m) }
 func (*IndexList) ProtoMessage()    {}
 func (*IndexList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{18}
+	return fileDescriptor_4aed0c02de55ead8, []int{21}
 }
 func (m *IndexList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IndexList.Unmarshal(m, b)
@@ -1479,7 +1737,7 @@ func (m *Command) Reset()         { *m = Command{} }
 func (m *Command) String() string { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()    {}
 func (*Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{19}
+	return fileDescriptor_4aed0c02de55ead8, []int{22}
 }
 
 var extRange_Command = []proto.ExtensionRange{
@@ -1529,7 +1787,7 @@ func (m *CreateDatabaseCommand) Reset()         { *m = CreateDatabaseCommand{} }
 func (m *CreateDatabaseCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateDatabaseCommand) ProtoMessage()    {}
 func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{20}
+	return fileDescriptor_4aed0c02de55ead8, []int{23}
 }
 func (m *CreateDatabaseCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateDatabaseCommand.Unmarshal(m, b)
@@ -1597,7 +1855,7 @@ func (m *DropDatabaseCommand) Reset()         { *m = DropDatabaseCommand{} }
 func (m *DropDatabaseCommand) String() string { return proto.CompactTextString(m) }
 func (*DropDatabaseCommand) ProtoMessage()    {}
 func (*DropDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{21}
+	return fileDescriptor_4aed0c02de55ead8, []int{24}
 }
 func (m *DropDatabaseCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropDatabaseCommand.Unmarshal(m, b)
@@ -1646,7 +1904,7 @@ func (m *CreateRetentionPolicyCommand) Reset()         { *m = CreateRetentionPol
 func (m *CreateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateRetentionPolicyCommand) ProtoMessage()    {}
 func (*CreateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{22}
+	return fileDescriptor_4aed0c02de55ead8, []int{25}
 }
 func (m *CreateRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateRetentionPolicyCommand.Unmarshal(m, b)
@@ -1708,7 +1966,7 @@ func (m *DropRetentionPolicyCommand) Reset()         { *m = DropRetentionPolicyC
 func (m *DropRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*DropRetentionPolicyCommand) ProtoMessage()    {}
 func (*DropRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{23}
+	return fileDescriptor_4aed0c02de55ead8, []int{26}
 }
 func (m *DropRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropRetentionPolicyCommand.Unmarshal(m, b)
@@ -1763,7 +2021,7 @@ func (m *SetDefaultRetentionPolicyCommand) Reset()         { *m = SetDefaultRete
 func (m *SetDefaultRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDefaultRetentionPolicyCommand) ProtoMessage()    {}
 func (*SetDefaultRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{24}
+	return fileDescriptor_4aed0c02de55ead8, []int{27}
 }
 func (m *SetDefaultRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetDefaultRetentionPolicyCommand.Unmarshal(m, b)
@@ -1826,7 +2084,7 @@ func (m *UpdateRetentionPolicyCommand) Reset()         { *m = UpdateRetentionPol
 func (m *UpdateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateRetentionPolicyCommand) ProtoMessage()    {}
 func (*UpdateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{25}
+	return fileDescriptor_4aed0c02de55ead8, []int{28}
 }
 func (m *UpdateRetentionPolicyCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateRetentionPolicyCommand.Unmarshal(m, b)
@@ -1939,7 +2197,7 @@ func (m *CreateShardGroupCommand) Reset()         { *m = CreateShardGroupCommand
 func (m *CreateShardGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateShardGroupCommand) ProtoMessage()    {}
 func (*CreateShardGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{26}
+	return fileDescriptor_4aed0c02de55ead8, []int{29}
 }
 func (m *CreateShardGroupCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateShardGroupCommand.Unmarshal(m, b)
@@ -2009,7 +2267,7 @@ func (m *DeleteShardGroupCommand) Reset()         { *m = DeleteShardGroupCommand
 func (m *DeleteShardGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteShardGroupCommand) ProtoMessage()    {}
 func (*DeleteShardGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{27}
+	return fileDescriptor_4aed0c02de55ead8, []int{30}
 }
 func (m *DeleteShardGroupCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteShardGroupCommand.Unmarshal(m, b)
@@ -2073,7 +2331,7 @@ func (m *CreateUserCommand) Reset()         { *m = CreateUserCommand{} }
 func (m *CreateUserCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateUserCommand) ProtoMessage()    {}
 func (*CreateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{28}
+	return fileDescriptor_4aed0c02de55ead8, []int{31}
 }
 func (m *CreateUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateUserCommand.Unmarshal(m, b)
@@ -2141,7 +2399,7 @@ func (m *DropUserCommand) Reset()         { *m = DropUserCommand{} }
 func (m *DropUserCommand) String() string { return proto.CompactTextString(m) }
 func (*DropUserCommand) ProtoMessage()    {}
 func (*DropUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{29}
+	return fileDescriptor_4aed0c02de55ead8, []int{32}
 }
 func (m *DropUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropUserCommand.Unmarshal(m, b)
@@ -2189,7 +2447,7 @@ func (m *UpdateUserCommand) Reset()         { *m = UpdateUserCommand{} }
 func (m *UpdateUserCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateUserCommand) ProtoMessage()    {}
 func (*UpdateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{30}
+	return fileDescriptor_4aed0c02de55ead8, []int{33}
 }
 func (m *UpdateUserCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateUserCommand.Unmarshal(m, b)
@@ -2245,7 +2503,7 @@ func (m *SetPrivilegeCommand) Reset()         { *m = SetPrivilegeCommand{} }
 func (m *SetPrivilegeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetPrivilegeCommand) ProtoMessage()    {}
 func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{31}
+	return fileDescriptor_4aed0c02de55ead8, []int{34}
 }
 func (m *SetPrivilegeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetPrivilegeCommand.Unmarshal(m, b)
@@ -2306,7 +2564,7 @@ func (m *SetDataCommand) Reset()         { *m = SetDataCommand{} }
 func (m *SetDataCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDataCommand) ProtoMessage()    {}
 func (*SetDataCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{32}
+	return fileDescriptor_4aed0c02de55ead8, []int{35}
 }
 func (m *SetDataCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetDataCommand.Unmarshal(m, b)
@@ -2354,7 +2612,7 @@ func (m *SetAdminPrivilegeCommand) Reset()         { *m = SetAdminPrivilegeComma
 func (m *SetAdminPrivilegeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetAdminPrivilegeCommand) ProtoMessage()    {}
 func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{33}
+	return fileDescriptor_4aed0c02de55ead8, []int{36}
 }
 func (m *SetAdminPrivilegeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetAdminPrivilegeCommand.Unmarshal(m, b)
@@ -2412,7 +2670,7 @@ func (m *CreateSubscriptionCommand) Reset()         { *m = CreateSubscriptionCom
 func (m *CreateSubscriptionCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateSubscriptionCommand) ProtoMessage()    {}
 func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{34}
+	return fileDescriptor_4aed0c02de55ead8, []int{37}
 }
 func (m *CreateSubscriptionCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateSubscriptionCommand.Unmarshal(m, b)
@@ -2489,7 +2747,7 @@ func (m *DropSubscriptionCommand) Reset()         { *m = DropSubscriptionCommand
 func (m *DropSubscriptionCommand) String() string { return proto.CompactTextString(m) }
 func (*DropSubscriptionCommand) ProtoMessage()    {}
 func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{35}
+	return fileDescriptor_4aed0c02de55ead8, []int{38}
 }
 func (m *DropSubscriptionCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropSubscriptionCommand.Unmarshal(m, b)
@@ -2553,7 +2811,7 @@ func (m *CreateMetaNodeCommand) Reset()         { *m = CreateMetaNodeCommand{} }
 func (m *CreateMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateMetaNodeCommand) ProtoMessage()    {}
 func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{36}
+	return fileDescriptor_4aed0c02de55ead8, []int{39}
 }
 func (m *CreateMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateMetaNodeCommand.Unmarshal(m, b)
@@ -2622,7 +2880,7 @@ func (m *CreateDataNodeCommand) Reset()         { *m = CreateDataNodeCommand{} }
 func (m *CreateDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateDataNodeCommand) ProtoMessage()    {}
 func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{37}
+	return fileDescriptor_4aed0c02de55ead8, []int{40}
 }
 func (m *CreateDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateDataNodeCommand.Unmarshal(m, b)
@@ -2678,7 +2936,7 @@ func (m *DataNodeEvent) Reset()         { *m = DataNodeEvent{} }
 func (m *DataNodeEvent) String() string { return proto.CompactTextString(m) }
 func (*DataNodeEvent) ProtoMessage()    {}
 func (*DataNodeEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{38}
+	return fileDescriptor_4aed0c02de55ead8, []int{41}
 }
 func (m *DataNodeEvent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DataNodeEvent.Unmarshal(m, b)
@@ -2730,7 +2988,7 @@ func (m *DeleteMetaNodeCommand) Reset()         { *m = DeleteMetaNodeCommand{} }
 func (m *DeleteMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteMetaNodeCommand) ProtoMessage()    {}
 func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{39}
+	return fileDescriptor_4aed0c02de55ead8, []int{42}
 }
 func (m *DeleteMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteMetaNodeCommand.Unmarshal(m, b)
@@ -2777,7 +3035,7 @@ func (m *DeleteDataNodeCommand) Reset()         { *m = DeleteDataNodeCommand{} }
 func (m *DeleteDataNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteDataNodeCommand) ProtoMessage()    {}
 func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{40}
+	return fileDescriptor_4aed0c02de55ead8, []int{43}
 }
 func (m *DeleteDataNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteDataNodeCommand.Unmarshal(m, b)
@@ -2826,7 +3084,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{41}
+	return fileDescriptor_4aed0c02de55ead8, []int{44}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -2883,7 +3141,7 @@ func (m *SetMetaNodeCommand) Reset()         { *m = SetMetaNodeCommand{} }
 func (m *SetMetaNodeCommand) String() string { return proto.CompactTextString(m) }
 func (*SetMetaNodeCommand) ProtoMessage()    {}
 func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{42}
+	return fileDescriptor_4aed0c02de55ead8, []int{45}
 }
 func (m *SetMetaNodeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetMetaNodeCommand.Unmarshal(m, b)
@@ -2951,7 +3209,7 @@ func (m *DropShardCommand) Reset()         { *m = DropShardCommand{} }
 func (m *DropShardCommand) String() string { return proto.CompactTextString(m) }
 func (*DropShardCommand) ProtoMessage()    {}
 func (*DropShardCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{43}
+	return fileDescriptor_4aed0c02de55ead8, []int{46}
 }
 func (m *DropShardCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropShardCommand.Unmarshal(m, b)
@@ -2998,7 +3256,7 @@ func (m *MarkDatabaseDeleteCommand) Reset()         { *m = MarkDatabaseDeleteCom
 func (m *MarkDatabaseDeleteCommand) String() string { return proto.CompactTextString(m) }
 func (*MarkDatabaseDeleteCommand) ProtoMessage()    {}
 func (*MarkDatabaseDeleteCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{44}
+	return fileDescriptor_4aed0c02de55ead8, []int{47}
 }
 func (m *MarkDatabaseDeleteCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MarkDatabaseDeleteCommand.Unmarshal(m, b)
@@ -3048,7 +3306,7 @@ func (m *UpdateShardOwnerCommand) Reset()         { *m = UpdateShardOwnerCommand
 func (m *UpdateShardOwnerCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateShardOwnerCommand) ProtoMessage()    {}
 func (*UpdateShardOwnerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{45}
+	return fileDescriptor_4aed0c02de55ead8, []int{48}
 }
 func (m *UpdateShardOwnerCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateShardOwnerCommand.Unmarshal(m, b)
@@ -3117,7 +3375,7 @@ func (m *MarkRetentionPolicyDeleteCommand) Reset()         { *m = MarkRetentionP
 func (m *MarkRetentionPolicyDeleteCommand) String() string { return proto.CompactTextString(m) }
 func (*MarkRetentionPolicyDeleteCommand) ProtoMessage()    {}
 func (*MarkRetentionPolicyDeleteCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{46}
+	return fileDescriptor_4aed0c02de55ead8, []int{49}
 }
 func (m *MarkRetentionPolicyDeleteCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MarkRetentionPolicyDeleteCommand.Unmarshal(m, b)
@@ -3175,7 +3433,7 @@ func (m *CreateMeasurementCommand) Reset()         { *m = CreateMeasurementComma
 func (m *CreateMeasurementCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateMeasurementCommand) ProtoMessage()    {}
 func (*CreateMeasurementCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{47}
+	return fileDescriptor_4aed0c02de55ead8, []int{50}
 }
 func (m *CreateMeasurementCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateMeasurementCommand.Unmarshal(m, b)
@@ -3253,7 +3511,7 @@ func (m *AlterShardKeyCmd) Reset()         { *m = AlterShardKeyCmd{} }
 func (m *AlterShardKeyCmd) String() string { return proto.CompactTextString(m) }
 func (*AlterShardKeyCmd) ProtoMessage()    {}
 func (*AlterShardKeyCmd) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{48}
+	return fileDescriptor_4aed0c02de55ead8, []int{51}
 }
 func (m *AlterShardKeyCmd) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AlterShardKeyCmd.Unmarshal(m, b)
@@ -3325,7 +3583,7 @@ func (m *UpdateDbPtStatusCommand) Reset()         { *m = UpdateDbPtStatusCommand
 func (m *UpdateDbPtStatusCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateDbPtStatusCommand) ProtoMessage()    {}
 func (*UpdateDbPtStatusCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{49}
+	return fileDescriptor_4aed0c02de55ead8, []int{52}
 }
 func (m *UpdateDbPtStatusCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateDbPtStatusCommand.Unmarshal(m, b)
@@ -3404,7 +3662,7 @@ func (m *ReShardingCommand) Reset()         { *m = ReShardingCommand{} }
 func (m *ReShardingCommand) String() string { return proto.CompactTextString(m) }
 func (*ReShardingCommand) ProtoMessage()    {}
 func (*ReShardingCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{50}
+	return fileDescriptor_4aed0c02de55ead8, []int{53}
 }
 func (m *ReShardingCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ReShardingCommand.Unmarshal(m, b)
@@ -3482,7 +3740,7 @@ func (m *UpdateSchemaCommand) Reset()         { *m = UpdateSchemaCommand{} }
 func (m *UpdateSchemaCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateSchemaCommand) ProtoMessage()    {}
 func (*UpdateSchemaCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{51}
+	return fileDescriptor_4aed0c02de55ead8, []int{54}
 }
 func (m *UpdateSchemaCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateSchemaCommand.Unmarshal(m, b)
@@ -3551,7 +3809,7 @@ func (m *FieldSchema) Reset()         { *m = FieldSchema{} }
 func (m *FieldSchema) String() string { return proto.CompactTextString(m) }
 func (*FieldSchema) ProtoMessage()    {}
 func (*FieldSchema) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{52}
+	return fileDescriptor_4aed0c02de55ead8, []int{55}
 }
 func (m *FieldSchema) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FieldSchema.Unmarshal(m, b)
@@ -3597,7 +3855,7 @@ func (m *IndexInfo) Reset()         { *m = IndexInfo{} }
 func (m *IndexInfo) String() string { return proto.CompactTextString(m) }
 func (*IndexInfo) ProtoMessage()    {}
 func (*IndexInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{53}
+	return fileDescriptor_4aed0c02de55ead8, []int{56}
 }
 func (m *IndexInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IndexInfo.Unmarshal(m, b)
@@ -3646,7 +3904,7 @@ func (m *IndexGroupInfo) Reset()         { *m = IndexGroupInfo{} }
 func (m *IndexGroupInfo) String() string { return proto.CompactTextString(m) }
 func (*IndexGroupInfo) ProtoMessage()    {}
 func (*IndexGroupInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{54}
+	return fileDescriptor_4aed0c02de55ead8, []int{57}
 }
 func (m *IndexGroupInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IndexGroupInfo.Unmarshal(m, b)
@@ -3715,7 +3973,7 @@ func (m *ShardStatus) Reset()         { *m = ShardStatus{} }
 func (m *ShardStatus) String() string { return proto.CompactTextString(m) }
 func (*ShardStatus) ProtoMessage()    {}
 func (*ShardStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{55}
+	return fileDescriptor_4aed0c02de55ead8, []int{58}
 }
 func (m *ShardStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardStatus.Unmarshal(m, b)
@@ -3775,7 +4033,7 @@ func (m *RpShardStatus) Reset()         { *m = RpShardStatus{} }
 func (m *RpShardStatus) String() string { return proto.CompactTextString(m) }
 func (*RpShardStatus) ProtoMessage()    {}
 func (*RpShardStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{56}
+	return fileDescriptor_4aed0c02de55ead8, []int{59}
 }
 func (m *RpShardStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RpShardStatus.Unmarshal(m, b)
@@ -3822,7 +4080,7 @@ func (m *DBPtStatus) Reset()         { *m = DBPtStatus{} }
 func (m *DBPtStatus) String() string { return proto.CompactTextString(m) }
 func (*DBPtStatus) ProtoMessage()    {}
 func (*DBPtStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{57}
+	return fileDescriptor_4aed0c02de55ead8, []int{60}
 }
 func (m *DBPtStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DBPtStatus.Unmarshal(m, b)
@@ -3874,7 +4132,7 @@ func (m *ReportShardsLoadCommand) Reset()         { *m = ReportShardsLoadCommand
 func (m *ReportShardsLoadCommand) String() string { return proto.CompactTextString(m) }
 func (*ReportShardsLoadCommand) ProtoMessage()    {}
 func (*ReportShardsLoadCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{58}
+	return fileDescriptor_4aed0c02de55ead8, []int{61}
 }
 func (m *ReportShardsLoadCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ReportShardsLoadCommand.Unmarshal(m, b)
@@ -3921,7 +4179,7 @@ func (m *PruneGroupsCommand) Reset()         { *m = PruneGroupsCommand{} }
 func (m *PruneGroupsCommand) String() string { return proto.CompactTextString(m) }
 func (*PruneGroupsCommand) ProtoMessage()    {}
 func (*PruneGroupsCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{59}
+	return fileDescriptor_4aed0c02de55ead8, []int{62}
 }
 func (m *PruneGroupsCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PruneGroupsCommand.Unmarshal(m, b)
@@ -3970,7 +4228,7 @@ func (m *MarkMeasurementDeleteCommand) Reset()         { *m = MarkMeasurementDel
 func (m *MarkMeasurementDeleteCommand) String() string { return proto.CompactTextString(m) }
 func (*MarkMeasurementDeleteCommand) ProtoMessage()    {}
 func (*MarkMeasurementDeleteCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{60}
+	return fileDescriptor_4aed0c02de55ead8, []int{63}
 }
 func (m *MarkMeasurementDeleteCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MarkMeasurementDeleteCommand.Unmarshal(m, b)
@@ -4033,7 +4291,7 @@ func (m *DropMeasurementCommand) Reset()         { *m = DropMeasurementCommand{}
 func (m *DropMeasurementCommand) String() string { return proto.CompactTextString(m) }
 func (*DropMeasurementCommand) ProtoMessage()    {}
 func (*DropMeasurementCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{61}
+	return fileDescriptor_4aed0c02de55ead8, []int{64}
 }
 func (m *DropMeasurementCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DropMeasurementCommand.Unmarshal(m, b)
@@ -4098,7 +4356,7 @@ func (m *NodeStartInfo) Reset()         { *m = NodeStartInfo{} }
 func (m *NodeStartInfo) String() string { return proto.CompactTextString(m) }
 func (*NodeStartInfo) ProtoMessage()    {}
 func (*NodeStartInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{62}
+	return fileDescriptor_4aed0c02de55ead8, []int{65}
 }
 func (m *NodeStartInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeStartInfo.Unmarshal(m, b)
@@ -4166,7 +4424,7 @@ func (m *TimeRangeCommand) Reset()         { *m = TimeRangeCommand{} }
 func (m *TimeRangeCommand) String() string { return proto.CompactTextString(m) }
 func (*TimeRangeCommand) ProtoMessage()    {}
 func (*TimeRangeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{63}
+	return fileDescriptor_4aed0c02de55ead8, []int{66}
 }
 func (m *TimeRangeCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TimeRangeCommand.Unmarshal(m, b)
@@ -4228,7 +4486,7 @@ func (m *ShardDurationCommand) Reset()         { *m = ShardDurationCommand{} }
 func (m *ShardDurationCommand) String() string { return proto.CompactTextString(m) }
 func (*ShardDurationCommand) ProtoMessage()    {}
 func (*ShardDurationCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{64}
+	return fileDescriptor_4aed0c02de55ead8, []int{67}
 }
 func (m *ShardDurationCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardDurationCommand.Unmarshal(m, b)
@@ -4284,7 +4542,7 @@ func (m *DurationDescriptor) Reset()         { *m = DurationDescriptor{} }
 func (m *DurationDescriptor) String() string { return proto.CompactTextString(m) }
 func (*DurationDescriptor) ProtoMessage()    {}
 func (*DurationDescriptor) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{65}
+	return fileDescriptor_4aed0c02de55ead8, []int{68}
 }
 func (m *DurationDescriptor) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DurationDescriptor.Unmarshal(m, b)
@@ -4341,7 +4599,7 @@ func (m *ShardIdentifier) Reset()         { *m = ShardIdentifier{} }
 func (m *ShardIdentifier) String() string { return proto.CompactTextString(m) }
 func (*ShardIdentifier) ProtoMessage()    {}
 func (*ShardIdentifier) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{66}
+	return fileDescriptor_4aed0c02de55ead8, []int{69}
 }
 func (m *ShardIdentifier) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardIdentifier.Unmarshal(m, b)
@@ -4415,7 +4673,7 @@ func (m *TimeRangeInfo) Reset()         { *m = TimeRangeInfo{} }
 func (m *TimeRangeInfo) String() string { return proto.CompactTextString(m) }
 func (*TimeRangeInfo) ProtoMessage()    {}
 func (*TimeRangeInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{67}
+	return fileDescriptor_4aed0c02de55ead8, []int{70}
 }
 func (m *TimeRangeInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TimeRangeInfo.Unmarshal(m, b)
@@ -4462,7 +4720,7 @@ func (m *IndexDescriptor) Reset()         { *m = IndexDescriptor{} }
 func (m *IndexDescriptor) String() string { return proto.CompactTextString(m) }
 func (*IndexDescriptor) ProtoMessage()    {}
 func (*IndexDescriptor) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{68}
+	return fileDescriptor_4aed0c02de55ead8, []int{71}
 }
 func (m *IndexDescriptor) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IndexDescriptor.Unmarshal(m, b)
@@ -4515,7 +4773,7 @@ func (m *ShardDurationInfo) Reset()         { *m = ShardDurationInfo{} }
 func (m *ShardDurationInfo) String() string { return proto.CompactTextString(m) }
 func (*ShardDurationInfo) ProtoMessage()    {}
 func (*ShardDurationInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{69}
+	return fileDescriptor_4aed0c02de55ead8, []int{72}
 }
 func (m *ShardDurationInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardDurationInfo.Unmarshal(m, b)
@@ -4563,7 +4821,7 @@ func (m *ShardTimeRangeInfo) Reset()         { *m = ShardTimeRangeInfo{} }
 func (m *ShardTimeRangeInfo) String() string { return proto.CompactTextString(m) }
 func (*ShardTimeRangeInfo) ProtoMessage()    {}
 func (*ShardTimeRangeInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{70}
+	return fileDescriptor_4aed0c02de55ead8, []int{73}
 }
 func (m *ShardTimeRangeInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardTimeRangeInfo.Unmarshal(m, b)
@@ -4623,7 +4881,7 @@ func (m *ShardDurationResponse) Reset()         { *m = ShardDurationResponse{} }
 func (m *ShardDurationResponse) String() string { return proto.CompactTextString(m) }
 func (*ShardDurationResponse) ProtoMessage()    {}
 func (*ShardDurationResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{71}
+	return fileDescriptor_4aed0c02de55ead8, []int{74}
 }
 func (m *ShardDurationResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardDurationResponse.Unmarshal(m, b)
@@ -4670,7 +4928,7 @@ func (m *DeleteIndexGroupCommand) Reset()         { *m = DeleteIndexGroupCommand
 func (m *DeleteIndexGroupCommand) String() string { return proto.CompactTextString(m) }
 func (*DeleteIndexGroupCommand) ProtoMessage()    {}
 func (*DeleteIndexGroupCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{72}
+	return fileDescriptor_4aed0c02de55ead8, []int{75}
 }
 func (m *DeleteIndexGroupCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteIndexGroupCommand.Unmarshal(m, b)
@@ -4734,7 +4992,7 @@ func (m *UpdateShardInfoTierCommand) Reset()         { *m = UpdateShardInfoTierC
 func (m *UpdateShardInfoTierCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateShardInfoTierCommand) ProtoMessage()    {}
 func (*UpdateShardInfoTierCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{73}
+	return fileDescriptor_4aed0c02de55ead8, []int{76}
 }
 func (m *UpdateShardInfoTierCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateShardInfoTierCommand.Unmarshal(m, b)
@@ -4803,7 +5061,7 @@ func (m *CardinalityInfo) Reset()         { *m = CardinalityInfo{} }
 func (m *CardinalityInfo) String() string { return proto.CompactTextString(m) }
 func (*CardinalityInfo) ProtoMessage()    {}
 func (*CardinalityInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{74}
+	return fileDescriptor_4aed0c02de55ead8, []int{77}
 }
 func (m *CardinalityInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CardinalityInfo.Unmarshal(m, b)
@@ -4849,7 +5107,7 @@ func (m *MeasurementCardinalityInfo) Reset()         { *m = MeasurementCardinali
 func (m *MeasurementCardinalityInfo) String() string { return proto.CompactTextString(m) }
 func (*MeasurementCardinalityInfo) ProtoMessage()    {}
 func (*MeasurementCardinalityInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{75}
+	return fileDescriptor_4aed0c02de55ead8, []int{78}
 }
 func (m *MeasurementCardinalityInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MeasurementCardinalityInfo.Unmarshal(m, b)
@@ -4895,7 +5153,7 @@ func (m *CardinalityResponse) Reset()         { *m = CardinalityResponse{} }
 func (m *CardinalityResponse) String() string { return proto.CompactTextString(m) }
 func (*CardinalityResponse) ProtoMessage()    {}
 func (*CardinalityResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{76}
+	return fileDescriptor_4aed0c02de55ead8, []int{79}
 }
 func (m *CardinalityResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CardinalityResponse.Unmarshal(m, b)
@@ -4943,7 +5201,7 @@ func (m *UpdateNodeStatusCommand) Reset()         { *m = UpdateNodeStatusCommand
 func (m *UpdateNodeStatusCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateNodeStatusCommand) ProtoMessage()    {}
 func (*UpdateNodeStatusCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{77}
+	return fileDescriptor_4aed0c02de55ead8, []int{80}
 }
 func (m *UpdateNodeStatusCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateNodeStatusCommand.Unmarshal(m, b)
@@ -5013,7 +5271,7 @@ func (m *DbPt) Reset()         { *m = DbPt{} }
 func (m *DbPt) String() string { return proto.CompactTextString(m) }
 func (*DbPt) ProtoMessage()    {}
 func (*DbPt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{78}
+	return fileDescriptor_4aed0c02de55ead8, []int{81}
 }
 func (m *DbPt) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DbPt.Unmarshal(m, b)
@@ -5072,7 +5330,7 @@ func (m *MigrateEventInfo) Reset()         { *m = MigrateEventInfo{} }
 func (m *MigrateEventInfo) String() string { return proto.CompactTextString(m) }
 func (*MigrateEventInfo) ProtoMessage()    {}
 func (*MigrateEventInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{79}
+	return fileDescriptor_4aed0c02de55ead8, []int{82}
 }
 func (m *MigrateEventInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MigrateEventInfo.Unmarshal(m, b)
@@ -5159,7 +5417,7 @@ func (m *CreateEventCommand) Reset()         { *m = CreateEventCommand{} }
 func (m *CreateEventCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateEventCommand) ProtoMessage()    {}
 func (*CreateEventCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{80}
+	return fileDescriptor_4aed0c02de55ead8, []int{83}
 }
 func (m *CreateEventCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateEventCommand.Unmarshal(m, b)
@@ -5206,7 +5464,7 @@ func (m *UpdateEventCommand) Reset()         { *m = UpdateEventCommand{} }
 func (m *UpdateEventCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateEventCommand) ProtoMessage()    {}
 func (*UpdateEventCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{81}
+	return fileDescriptor_4aed0c02de55ead8, []int{84}
 }
 func (m *UpdateEventCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateEventCommand.Unmarshal(m, b)
@@ -5256,7 +5514,7 @@ func (m *UpdatePtInfoCommand) Reset()         { *m = UpdatePtInfoCommand{} }
 func (m *UpdatePtInfoCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdatePtInfoCommand) ProtoMessage()    {}
 func (*UpdatePtInfoCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{82}
+	return fileDescriptor_4aed0c02de55ead8, []int{85}
 }
 func (m *UpdatePtInfoCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdatePtInfoCommand.Unmarshal(m, b)
@@ -5324,7 +5582,7 @@ func (m *RemoveEventCommand) Reset()         { *m = RemoveEventCommand{} }
 func (m *RemoveEventCommand) String() string { return proto.CompactTextString(m) }
 func (*RemoveEventCommand) ProtoMessage()    {}
 func (*RemoveEventCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4aed0c02de55ead8, []int{83}
+	return fileDescriptor_4aed0c02de55ead8, []int{86}
 }
 func (m *RemoveEventCommand) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoveEventCommand.Unmarshal(m, b)
@@ -5360,6 +5618,422 @@ var E_RemoveEventCommand_Command = &proto.ExtensionDesc{
 	Filename:      "open_src/influx/meta/proto/meta.proto",
 }
 
+type DecommissionDataNodeCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DecommissionDataNodeCommand) Reset()         { *m = DecommissionDataNodeCommand{} }
+func (m *DecommissionDataNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*DecommissionDataNodeCommand) ProtoMessage()    {}
+func (*DecommissionDataNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{87}
+}
+func (m *DecommissionDataNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecommissionDataNodeCommand.Unmarshal(m, b)
+}
+func (m *DecommissionDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecommissionDataNodeCommand.Marshal(b, m, deterministic)
+}
+func (m *DecommissionDataNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecommissionDataNodeCommand.Merge(m, src)
+}
+func (m *DecommissionDataNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_DecommissionDataNodeCommand.Size(m)
+}
+func (m *DecommissionDataNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecommissionDataNodeCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DecommissionDataNodeCommand proto.InternalMessageInfo
+
+func (m *DecommissionDataNodeCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+var E_DecommissionDataNodeCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DecommissionDataNodeCommand)(nil),
+	Field:         169,
+	Name:          "proto.DecommissionDataNodeCommand.command",
+	Tag:           "bytes,169,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
+type CreateRoleCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateRoleCommand) Reset()         { *m = CreateRoleCommand{} }
+func (m *CreateRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateRoleCommand) ProtoMessage()    {}
+func (*CreateRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{88}
+}
+func (m *CreateRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRoleCommand.Unmarshal(m, b)
+}
+func (m *CreateRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRoleCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRoleCommand.Merge(m, src)
+}
+func (m *CreateRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateRoleCommand.Size(m)
+}
+func (m *CreateRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRoleCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateRoleCommand proto.InternalMessageInfo
+
+func (m *CreateRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_CreateRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateRoleCommand)(nil),
+	Field:         170,
+	Name:          "proto.CreateRoleCommand.command",
+	Tag:           "bytes,170,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
+type DropRoleCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DropRoleCommand) Reset()         { *m = DropRoleCommand{} }
+func (m *DropRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*DropRoleCommand) ProtoMessage()    {}
+func (*DropRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{89}
+}
+func (m *DropRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropRoleCommand.Unmarshal(m, b)
+}
+func (m *DropRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropRoleCommand.Marshal(b, m, deterministic)
+}
+func (m *DropRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropRoleCommand.Merge(m, src)
+}
+func (m *DropRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_DropRoleCommand.Size(m)
+}
+func (m *DropRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropRoleCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropRoleCommand proto.InternalMessageInfo
+
+func (m *DropRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_DropRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DropRoleCommand)(nil),
+	Field:         171,
+	Name:          "proto.DropRoleCommand.command",
+	Tag:           "bytes,171,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
+type SetRolePrivilegeCommand struct {
+	Role                 *string        `protobuf:"bytes,1,req,name=Role" json:"Role,omitempty"`
+	Privilege            *RolePrivilege `protobuf:"bytes,2,req,name=Privilege" json:"Privilege,omitempty"`
+	Revoke               *bool          `protobuf:"varint,3,opt,name=Revoke" json:"Revoke,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SetRolePrivilegeCommand) Reset()         { *m = SetRolePrivilegeCommand{} }
+func (m *SetRolePrivilegeCommand) String() string { return proto.CompactTextString(m) }
+func (*SetRolePrivilegeCommand) ProtoMessage()    {}
+func (*SetRolePrivilegeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{90}
+}
+func (m *SetRolePrivilegeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Unmarshal(m, b)
+}
+func (m *SetRolePrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Marshal(b, m, deterministic)
+}
+func (m *SetRolePrivilegeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRolePrivilegeCommand.Merge(m, src)
+}
+func (m *SetRolePrivilegeCommand) XXX_Size() int {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Size(m)
+}
+func (m *SetRolePrivilegeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRolePrivilegeCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRolePrivilegeCommand proto.InternalMessageInfo
+
+func (m *SetRolePrivilegeCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
+	}
+	return ""
+}
+
+func (m *SetRolePrivilegeCommand) GetPrivilege() *RolePrivilege {
+	if m != nil {
+		return m.Privilege
+	}
+	return nil
+}
+
+func (m *SetRolePrivilegeCommand) GetRevoke() bool {
+	if m != nil && m.Revoke != nil {
+		return *m.Revoke
+	}
+	return false
+}
+
+var E_SetRolePrivilegeCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetRolePrivilegeCommand)(nil),
+	Field:         172,
+	Name:          "proto.SetRolePrivilegeCommand.command",
+	Tag:           "bytes,172,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
+type SetUserRoleCommand struct {
+	Username             *string  `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
+	Role                 *string  `protobuf:"bytes,2,req,name=Role" json:"Role,omitempty"`
+	Revoke               *bool    `protobuf:"varint,3,opt,name=Revoke" json:"Revoke,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetUserRoleCommand) Reset()         { *m = SetUserRoleCommand{} }
+func (m *SetUserRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*SetUserRoleCommand) ProtoMessage()    {}
+func (*SetUserRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{91}
+}
+func (m *SetUserRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetUserRoleCommand.Unmarshal(m, b)
+}
+func (m *SetUserRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetUserRoleCommand.Marshal(b, m, deterministic)
+}
+func (m *SetUserRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetUserRoleCommand.Merge(m, src)
+}
+func (m *SetUserRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_SetUserRoleCommand.Size(m)
+}
+func (m *SetUserRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetUserRoleCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetUserRoleCommand proto.InternalMessageInfo
+
+func (m *SetUserRoleCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
+	}
+	return ""
+}
+
+func (m *SetUserRoleCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
+	}
+	return ""
+}
+
+func (m *SetUserRoleCommand) GetRevoke() bool {
+	if m != nil && m.Revoke != nil {
+		return *m.Revoke
+	}
+	return false
+}
+
+var E_SetUserRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetUserRoleCommand)(nil),
+	Field:         173,
+	Name:          "proto.SetUserRoleCommand.command",
+	Tag:           "bytes,173,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
+type CreateTokenCommand struct {
+	Token                *TokenInfo `protobuf:"bytes,1,req,name=Token" json:"Token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CreateTokenCommand) Reset()         { *m = CreateTokenCommand{} }
+func (m *CreateTokenCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateTokenCommand) ProtoMessage()    {}
+func (*CreateTokenCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{92}
+}
+func (m *CreateTokenCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTokenCommand.Unmarshal(m, b)
+}
+func (m *CreateTokenCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTokenCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateTokenCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTokenCommand.Merge(m, src)
+}
+func (m *CreateTokenCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateTokenCommand.Size(m)
+}
+func (m *CreateTokenCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTokenCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTokenCommand proto.InternalMessageInfo
+
+func (m *CreateTokenCommand) GetToken() *TokenInfo {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+var E_CreateTokenCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateTokenCommand)(nil),
+	Field:         174,
+	Name:          "proto.CreateTokenCommand.command",
+	Tag:           "bytes,174,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
+type DropTokenCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DropTokenCommand) Reset()         { *m = DropTokenCommand{} }
+func (m *DropTokenCommand) String() string { return proto.CompactTextString(m) }
+func (*DropTokenCommand) ProtoMessage()    {}
+func (*DropTokenCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{93}
+}
+func (m *DropTokenCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropTokenCommand.Unmarshal(m, b)
+}
+func (m *DropTokenCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropTokenCommand.Marshal(b, m, deterministic)
+}
+func (m *DropTokenCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropTokenCommand.Merge(m, src)
+}
+func (m *DropTokenCommand) XXX_Size() int {
+	return xxx_messageInfo_DropTokenCommand.Size(m)
+}
+func (m *DropTokenCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropTokenCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropTokenCommand proto.InternalMessageInfo
+
+func (m *DropTokenCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_DropTokenCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DropTokenCommand)(nil),
+	Field:         175,
+	Name:          "proto.DropTokenCommand.command",
+	Tag:           "bytes,175,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
+type UpdateTokenLastUsedCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	LastUsed             *int64   `protobuf:"varint,2,req,name=LastUsed" json:"LastUsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTokenLastUsedCommand) Reset()         { *m = UpdateTokenLastUsedCommand{} }
+func (m *UpdateTokenLastUsedCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateTokenLastUsedCommand) ProtoMessage()    {}
+func (*UpdateTokenLastUsedCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4aed0c02de55ead8, []int{94}
+}
+func (m *UpdateTokenLastUsedCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTokenLastUsedCommand.Unmarshal(m, b)
+}
+func (m *UpdateTokenLastUsedCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTokenLastUsedCommand.Marshal(b, m, deterministic)
+}
+func (m *UpdateTokenLastUsedCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTokenLastUsedCommand.Merge(m, src)
+}
+func (m *UpdateTokenLastUsedCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateTokenLastUsedCommand.Size(m)
+}
+func (m *UpdateTokenLastUsedCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTokenLastUsedCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTokenLastUsedCommand proto.InternalMessageInfo
+
+func (m *UpdateTokenLastUsedCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *UpdateTokenLastUsedCommand) GetLastUsed() int64 {
+	if m != nil && m.LastUsed != nil {
+		return *m.LastUsed
+	}
+	return 0
+}
+
+var E_UpdateTokenLastUsedCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*UpdateTokenLastUsedCommand)(nil),
+	Field:         176,
+	Name:          "proto.UpdateTokenLastUsedCommand.command",
+	Tag:           "bytes,176,opt,name=command",
+	Filename:      "open_src/influx/meta/proto/meta.proto",
+}
+
 func init() {
 	proto.RegisterEnum("proto.Command_Type", Command_Type_name, Command_Type_value)
 	proto.RegisterType((*Data)(nil), "proto.Data")
@@ -5381,6 +6055,9 @@ func init() {
 	proto.RegisterType((*ShardOwner)(nil), "proto.ShardOwner")
 	proto.RegisterType((*UserInfo)(nil), "proto.UserInfo")
 	proto.RegisterType((*UserPrivilege)(nil), "proto.UserPrivilege")
+	proto.RegisterType((*RoleInfo)(nil), "proto.RoleInfo")
+	proto.RegisterType((*RolePrivilege)(nil), "proto.RolePrivilege")
+	proto.RegisterType((*TokenInfo)(nil), "proto.TokenInfo")
 	proto.RegisterType((*IndexRelation)(nil), "proto.IndexRelation")
 	proto.RegisterType((*IndexList)(nil), "proto.IndexList")
 	proto.RegisterType((*Command)(nil), "proto.Command")
@@ -5492,6 +6169,22 @@ func init() {
 	proto.RegisterType((*UpdatePtInfoCommand)(nil), "proto.UpdatePtInfoCommand")
 	proto.RegisterExtension(E_RemoveEventCommand_Command)
 	proto.RegisterType((*RemoveEventCommand)(nil), "proto.RemoveEventCommand")
+	proto.RegisterExtension(E_DecommissionDataNodeCommand_Command)
+	proto.RegisterType((*DecommissionDataNodeCommand)(nil), "proto.DecommissionDataNodeCommand")
+	proto.RegisterExtension(E_CreateRoleCommand_Command)
+	proto.RegisterType((*CreateRoleCommand)(nil), "proto.CreateRoleCommand")
+	proto.RegisterExtension(E_DropRoleCommand_Command)
+	proto.RegisterType((*DropRoleCommand)(nil), "proto.DropRoleCommand")
+	proto.RegisterExtension(E_SetRolePrivilegeCommand_Command)
+	proto.RegisterType((*SetRolePrivilegeCommand)(nil), "proto.SetRolePrivilegeCommand")
+	proto.RegisterExtension(E_SetUserRoleCommand_Command)
+	proto.RegisterType((*SetUserRoleCommand)(nil), "proto.SetUserRoleCommand")
+	proto.RegisterExtension(E_CreateTokenCommand_Command)
+	proto.RegisterType((*CreateTokenCommand)(nil), "proto.CreateTokenCommand")
+	proto.RegisterExtension(E_DropTokenCommand_Command)
+	proto.RegisterType((*DropTokenCommand)(nil), "proto.DropTokenCommand")
+	proto.RegisterExtension(E_UpdateTokenLastUsedCommand_Command)
+	proto.RegisterType((*UpdateTokenLastUsedCommand)(nil), "proto.UpdateTokenLastUsedCommand")
 }
 
 func init() {
@@ -5499,252 +6192,278 @@ func init() {
 }
 
 var fileDescriptor_4aed0c02de55ead8 = []byte{
-	// 3947 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3b, 0x5d, 0x8f, 0x5c, 0xc7,
-	0x52, 0xea, 0x33, 0x33, 0xbb, 0x33, 0xbd, 0x9e, 0xdd, 0x75, 0xdb, 0x5e, 0x1f, 0x6f, 0xd6, 0xf6,
-	0xf8, 0x5c, 0x47, 0x59, 0x5d, 0xb8, 0x6b, 0x32, 0xba, 0xd7, 0xc9, 0x35, 0x37, 0xf7, 0x5e, 0xef,
-	0x8e, 0x63, 0x4f, 0xec, 0x5d, 0x0f, 0x67, 0x37, 0x44, 0x02, 0x09, 0x72, 0x76, 0xa7, 0x6d, 0x4f,
-	0xbc, 0xf3, 0xc1, 0x39, 0x67, 0xec, 0x75, 0x14, 0x14, 0x87, 0x48, 0xf0, 0x80, 0x78, 0x88, 0x50,
-	0x12, 0x82, 0x14, 0x3e, 0x42, 0x12, 0xe0, 0x01, 0x41, 0x9e, 0x00, 0xf1, 0x21, 0x11, 0x78, 0x40,
-	0xbc, 0xf2, 0x0a, 0xfc, 0x02, 0x90, 0x78, 0x8b, 0x78, 0x43, 0x55, 0xdd, 0x7d, 0xba, 0xfb, 0x7c,
-	0xed, 0xda, 0x92, 0xf3, 0x34, 0xd3, 0x55, 0xd5, 0xdd, 0x55, 0xd5, 0xd5, 0x55, 0x5d, 0xd5, 0x7d,
-	0xe8, 0xf3, 0xe3, 0x09, 0x1f, 0xfd, 0x6a, 0x14, 0xee, 0x5d, 0x1a, 0x8c, 0xee, 0xec, 0x4f, 0x0f,
-	0x2e, 0x0d, 0x79, 0x1c, 0x5c, 0x9a, 0x84, 0xe3, 0x78, 0x8c, 0x7f, 0xd7, 0xf0, 0x2f, 0xab, 0xe1,
-	0x8f, 0xf7, 0xd5, 0x0c, 0xad, 0x76, 0x82, 0x38, 0x60, 0x8c, 0x56, 0x77, 0x78, 0x38, 0x74, 0x49,
-	0xcb, 0x59, 0xad, 0xfa, 0xf8, 0x9f, 0x9d, 0xa4, 0xb5, 0xee, 0xa8, 0xcf, 0x0f, 0x5c, 0x07, 0x81,
-	0xa2, 0xc1, 0x56, 0x68, 0x63, 0x63, 0x7f, 0x1a, 0xc5, 0x3c, 0xec, 0x76, 0xdc, 0x0a, 0x62, 0x34,
-	0x80, 0x3d, 0x4f, 0x6b, 0x5b, 0xe3, 0x3e, 0x8f, 0xdc, 0x6a, 0xab, 0xb2, 0x3a, 0xd7, 0x5e, 0x10,
-	0xd3, 0xad, 0x01, 0xac, 0x3b, 0xba, 0x33, 0xf6, 0x05, 0x96, 0xbd, 0x48, 0x1b, 0x30, 0xed, 0x6e,
-	0x10, 0xf1, 0xc8, 0xad, 0x21, 0xe9, 0x09, 0x49, 0xaa, 0xe0, 0x48, 0xae, 0xa9, 0x60, 0xe4, 0xd7,
-	0x23, 0x1e, 0x46, 0xee, 0x8c, 0x35, 0x32, 0xc0, 0xc4, 0xc8, 0x88, 0x05, 0xf6, 0x36, 0x83, 0x03,
-	0x9c, 0xaf, 0xe3, 0xce, 0x0a, 0xf6, 0x12, 0x00, 0x5b, 0xa5, 0x0b, 0x9b, 0xc1, 0xc1, 0xf6, 0xbd,
-	0x20, 0xec, 0x5f, 0x0f, 0xc7, 0xd3, 0x49, 0xb7, 0xe3, 0xd6, 0x91, 0x26, 0x0d, 0x66, 0xe7, 0x28,
-	0x55, 0xa0, 0x6e, 0xc7, 0x6d, 0x20, 0x91, 0x01, 0x61, 0xdf, 0x13, 0x12, 0x08, 0x61, 0xa9, 0xc5,
-	0x92, 0x82, 0xfb, 0x9a, 0x02, 0xc8, 0x37, 0xb9, 0x22, 0x9f, 0xcb, 0xd7, 0x8d, 0xa6, 0x60, 0x1e,
-	0x3d, 0x26, 0x75, 0xda, 0x8b, 0xb7, 0xa6, 0x43, 0x77, 0xbe, 0xe5, 0xac, 0x36, 0x7d, 0x0b, 0xc6,
-	0x2e, 0xd1, 0x99, 0x5e, 0xfc, 0x8b, 0x03, 0xfe, 0xd0, 0x5d, 0xc0, 0xf1, 0x4e, 0x1b, 0xd3, 0xaf,
-	0x09, 0xcc, 0xb5, 0x51, 0x1c, 0x3e, 0xf2, 0x25, 0x19, 0x0c, 0x8a, 0x3d, 0x7b, 0x3c, 0x84, 0x59,
-	0xdc, 0xc5, 0x16, 0x81, 0x41, 0x4d, 0x98, 0x54, 0x10, 0xae, 0xb4, 0x52, 0xd0, 0xf1, 0x44, 0x41,
-	0x26, 0x58, 0x2a, 0x08, 0x41, 0xdd, 0x8e, 0xcb, 0x12, 0x05, 0x49, 0x08, 0xcc, 0xb6, 0x19, 0x1c,
-	0x5c, 0x7b, 0xc0, 0x47, 0xf1, 0xed, 0x49, 0xb7, 0xef, 0x9e, 0x68, 0x91, 0xd5, 0xaa, 0x6f, 0xc1,
-	0x60, 0xb6, 0x9d, 0xe0, 0x3e, 0xbf, 0xfd, 0x80, 0x87, 0xd7, 0x46, 0xc1, 0xee, 0x3e, 0xef, 0xbb,
-	0x27, 0x5b, 0x64, 0xb5, 0xee, 0xa7, 0xc1, 0xec, 0x15, 0xda, 0xdc, 0x1c, 0xdc, 0x0d, 0x83, 0x98,
-	0x63, 0xef, 0xc8, 0x3d, 0x65, 0xc9, 0x6c, 0xe2, 0x50, 0x97, 0x36, 0xf5, 0xf2, 0x6b, 0x74, 0xce,
-	0xd0, 0x08, 0x5b, 0xa4, 0x95, 0xfb, 0xfc, 0x91, 0x4b, 0x5a, 0x64, 0xb5, 0xe1, 0xc3, 0x5f, 0xb0,
-	0xae, 0x07, 0xc1, 0xfe, 0x94, 0xbb, 0x4e, 0x8b, 0x98, 0x4b, 0xb9, 0xde, 0x13, 0xe3, 0x09, 0xec,
-	0x15, 0xe7, 0x65, 0xe2, 0x5d, 0xa0, 0xb3, 0xbd, 0xf8, 0xf6, 0xc3, 0x11, 0x0f, 0xd9, 0x12, 0x9d,
-	0x91, 0x96, 0x26, 0xf6, 0x8d, 0x6c, 0x79, 0xbf, 0x04, 0x4b, 0x03, 0xfd, 0xd8, 0x45, 0x5a, 0x43,
-	0x52, 0x24, 0x98, 0x6b, 0xcf, 0xcb, 0x71, 0xe5, 0x00, 0x7e, 0x2d, 0x19, 0x67, 0x3b, 0x0e, 0xe2,
-	0x69, 0x84, 0x5b, 0xad, 0xe9, 0xcb, 0x16, 0xec, 0xca, 0x5e, 0xdc, 0xed, 0xe3, 0x36, 0x6b, 0xfa,
-	0xf8, 0xdf, 0xfb, 0x1e, 0xad, 0x2b, 0xae, 0xd8, 0x05, 0x5a, 0xed, 0xec, 0xf6, 0x62, 0x97, 0xa0,
-	0x32, 0x9a, 0xc9, 0xe0, 0xc8, 0x32, 0xa2, 0xbc, 0xbf, 0x22, 0xb4, 0xae, 0x2c, 0x8c, 0xcd, 0x53,
-	0x27, 0xe1, 0xd5, 0xe9, 0x76, 0x60, 0xfc, 0x1b, 0xe3, 0x28, 0xc6, 0x59, 0x1b, 0x3e, 0xfe, 0x67,
-	0x2e, 0x9d, 0xf5, 0x7b, 0x1b, 0x57, 0xfb, 0xfd, 0xd0, 0xad, 0xa1, 0x7e, 0x54, 0x13, 0x30, 0x3b,
-	0x1b, 0x3d, 0xec, 0x50, 0x11, 0x18, 0xd9, 0x34, 0xf8, 0xaf, 0xb6, 0x9c, 0xd5, 0x4a, 0xc2, 0xff,
-	0x49, 0x5a, 0xbb, 0xb5, 0x33, 0x18, 0x72, 0x77, 0x46, 0x78, 0x10, 0x6c, 0x80, 0xe5, 0x5c, 0x1f,
-	0x47, 0xd1, 0x60, 0x82, 0x93, 0xcc, 0xe2, 0xdc, 0x06, 0xc4, 0xfb, 0x19, 0x5a, 0x57, 0x1b, 0x87,
-	0x9d, 0xa7, 0xce, 0xd6, 0x40, 0x2a, 0x2f, 0xb3, 0x61, 0x9c, 0xad, 0x81, 0xf7, 0x0d, 0xa1, 0xc7,
-	0x4c, 0x97, 0x01, 0x32, 0x6d, 0x05, 0x43, 0x8e, 0x7d, 0x1a, 0x3e, 0xfe, 0x67, 0x97, 0xe9, 0x52,
-	0x87, 0xdf, 0x09, 0xa6, 0xfb, 0xb1, 0xcf, 0x63, 0x3e, 0x8a, 0x07, 0xe3, 0x51, 0x6f, 0xbc, 0x3f,
-	0xd8, 0x7b, 0x24, 0x25, 0x2f, 0xc0, 0xb2, 0x1b, 0xf4, 0xb8, 0x0d, 0x1a, 0xf0, 0xc8, 0xad, 0xa0,
-	0xb2, 0x97, 0x25, 0x33, 0xa9, 0x2e, 0xc8, 0x57, 0xb6, 0x13, 0x6b, 0xd1, 0xb9, 0xcd, 0x20, 0xbc,
-	0xdf, 0xe1, 0xfb, 0x3c, 0xe6, 0x7d, 0xd4, 0x6c, 0xdd, 0x37, 0x41, 0xec, 0x12, 0xad, 0xa3, 0x6f,
-	0xb9, 0xc9, 0x1f, 0xb9, 0x33, 0x68, 0x84, 0xca, 0x23, 0x2a, 0x30, 0x8e, 0x9d, 0x10, 0x79, 0x1f,
-	0x10, 0x7a, 0x22, 0x35, 0xfb, 0xf6, 0x84, 0xef, 0x19, 0x0a, 0x20, 0x89, 0x02, 0x96, 0x69, 0xbd,
-	0x33, 0x0d, 0x03, 0xa0, 0x44, 0x0b, 0xaf, 0xf8, 0x49, 0x9b, 0xad, 0x51, 0xa6, 0x3d, 0x5f, 0x42,
-	0x55, 0x41, 0xaa, 0x1c, 0x0c, 0x8c, 0xe5, 0xf3, 0xc9, 0xfe, 0x60, 0x2f, 0xd8, 0x72, 0xab, 0xe8,
-	0x42, 0x92, 0xb6, 0xf7, 0x97, 0x0e, 0x5d, 0xd8, 0xe4, 0x41, 0x34, 0x0d, 0xf9, 0x50, 0x6e, 0xc5,
-	0xdc, 0x05, 0x79, 0x91, 0x36, 0x94, 0x1c, 0x60, 0xf3, 0x95, 0x22, 0x69, 0x35, 0x15, 0xbb, 0x42,
-	0x67, 0xb6, 0xf7, 0xee, 0xf1, 0x61, 0x20, 0x17, 0xc0, 0x53, 0x5b, 0xdf, 0x9e, 0x6e, 0x4d, 0x10,
-	0x49, 0xcf, 0x27, 0x1a, 0x69, 0xed, 0x57, 0xb3, 0xda, 0xff, 0x11, 0x9d, 0x1f, 0x80, 0xe3, 0xf2,
-	0xf9, 0x3e, 0x4a, 0xa9, 0xa2, 0xd2, 0x49, 0x39, 0x4b, 0xd7, 0x44, 0xfa, 0x29, 0xda, 0xe5, 0x1f,
-	0xd2, 0x39, 0x63, 0xda, 0x1c, 0xf7, 0x72, 0xd2, 0x74, 0x2f, 0x35, 0xd3, 0x9b, 0x7c, 0x54, 0xcd,
-	0xac, 0x62, 0xa1, 0xd6, 0xec, 0x55, 0x74, 0x8e, 0xb4, 0x8a, 0xce, 0x91, 0x56, 0xd1, 0x31, 0x57,
-	0x91, 0x5d, 0xa1, 0xc7, 0x0c, 0xad, 0x2a, 0x55, 0x2c, 0xe5, 0x2b, 0xdc, 0xb7, 0x68, 0xd9, 0x4b,
-	0x74, 0x4e, 0xcf, 0xa6, 0x82, 0xf5, 0x29, 0x73, 0x6d, 0x45, 0x00, 0x81, 0x9e, 0x26, 0x25, 0x78,
-	0xf8, 0xed, 0xe9, 0x6e, 0xb4, 0x17, 0x0e, 0x26, 0x62, 0x01, 0x66, 0x2d, 0x0f, 0x6f, 0xe2, 0x84,
-	0x87, 0xb7, 0xa8, 0xd3, 0x4b, 0x5c, 0xcf, 0x2e, 0x71, 0x8b, 0xce, 0xdd, 0x18, 0xc7, 0x89, 0x6a,
-	0x1a, 0xa8, 0x1a, 0x13, 0x04, 0x21, 0xeb, 0x8d, 0x20, 0x1c, 0x26, 0x24, 0x14, 0x49, 0x2c, 0x18,
-	0xe8, 0x59, 0x87, 0xc1, 0x84, 0x72, 0x4e, 0xe8, 0x39, 0x8b, 0x01, 0x7d, 0x68, 0x68, 0xe4, 0x1e,
-	0xb3, 0xf4, 0x61, 0x04, 0x54, 0xd4, 0x87, 0x41, 0xe9, 0x7d, 0x4d, 0xe8, 0xbc, 0xad, 0xaf, 0x8c,
-	0xfb, 0x5e, 0xa1, 0x8d, 0xed, 0x38, 0x08, 0x63, 0x74, 0xb1, 0xc2, 0x20, 0x34, 0x00, 0xdc, 0xf5,
-	0xb5, 0x51, 0x1f, 0x71, 0xc2, 0x0c, 0x54, 0x13, 0xfa, 0x49, 0xa5, 0x5c, 0x8d, 0xa5, 0xc7, 0xd6,
-	0x00, 0xb6, 0x4a, 0x67, 0x70, 0x5e, 0xb5, 0xee, 0x8b, 0xe6, 0xe2, 0x21, 0x9f, 0x12, 0x0f, 0x1a,
-	0xdd, 0x09, 0xa7, 0xa3, 0xbd, 0x40, 0x8c, 0x34, 0x83, 0x2e, 0xc3, 0x04, 0x79, 0xbf, 0x43, 0xe4,
-	0x46, 0xcf, 0xe5, 0xff, 0x1c, 0xad, 0x63, 0xfc, 0xeb, 0x76, 0x84, 0x13, 0x68, 0xae, 0x3b, 0x2e,
-	0xf1, 0x13, 0x18, 0xec, 0xa3, 0xcd, 0x81, 0x30, 0xe2, 0x86, 0x0f, 0x7f, 0x11, 0x12, 0x1c, 0x20,
-	0xcf, 0x00, 0x09, 0x0e, 0xf0, 0xe0, 0x3a, 0xe0, 0x10, 0xab, 0xc4, 0xc1, 0x75, 0xc0, 0x31, 0x50,
-	0xa9, 0x73, 0x89, 0x08, 0x3c, 0xaa, 0xe9, 0xf9, 0xf4, 0x98, 0xe9, 0x5f, 0x60, 0x17, 0x24, 0x4e,
-	0x17, 0x82, 0x68, 0x43, 0xfb, 0x57, 0x1c, 0xf9, 0xd1, 0x44, 0x6c, 0xd9, 0x86, 0x8f, 0xff, 0x01,
-	0xb6, 0x7d, 0x17, 0xcf, 0xbd, 0x70, 0x98, 0xc1, 0xff, 0xde, 0xaf, 0xd0, 0xc5, 0xb4, 0x71, 0xe6,
-	0xee, 0x5e, 0x46, 0xab, 0x9b, 0x70, 0xec, 0x92, 0xc1, 0x16, 0xfe, 0x83, 0xc5, 0x75, 0x78, 0x14,
-	0x0f, 0x46, 0xd2, 0xe9, 0x54, 0x90, 0x07, 0x0b, 0xe6, 0x5d, 0xa4, 0x14, 0x79, 0x2a, 0x3f, 0x72,
-	0x7c, 0x44, 0x68, 0x5d, 0x9d, 0x85, 0x8b, 0xa6, 0xbf, 0x11, 0x44, 0xf7, 0x92, 0x58, 0x1f, 0x44,
-	0xf7, 0xc0, 0x2d, 0x5d, 0xed, 0x0f, 0xa5, 0x8a, 0xeb, 0xbe, 0x68, 0xc0, 0x14, 0xfe, 0x43, 0x18,
-	0x4b, 0x3a, 0x4a, 0xd9, 0x62, 0xdf, 0xa7, 0xb4, 0x17, 0x0e, 0x1e, 0x0c, 0xf6, 0xf9, 0x5d, 0x9e,
-	0xf6, 0x8f, 0x40, 0x90, 0x20, 0x7d, 0x83, 0xce, 0xeb, 0xd2, 0xa6, 0x85, 0x44, 0x2f, 0x26, 0x03,
-	0xb6, 0x64, 0x30, 0x69, 0x83, 0x65, 0x26, 0x84, 0xc8, 0x69, 0xcd, 0xd7, 0x00, 0xef, 0x7d, 0x42,
-	0x9b, 0x96, 0x23, 0x06, 0x7b, 0xf0, 0x07, 0x7d, 0x1c, 0xa6, 0xe9, 0xc3, 0x5f, 0x80, 0xdc, 0x1e,
-	0xf4, 0xe5, 0x39, 0x0a, 0xfe, 0xc2, 0x98, 0xd8, 0x09, 0x35, 0x22, 0x14, 0xac, 0x01, 0xec, 0xe7,
-	0x28, 0xc5, 0xc6, 0xad, 0x41, 0x14, 0xab, 0xac, 0x65, 0xd1, 0xdc, 0x9e, 0x80, 0xf0, 0x0d, 0x1a,
-	0xef, 0x82, 0x1c, 0x0f, 0x5a, 0x98, 0x23, 0xc1, 0x1f, 0x69, 0x3d, 0xa2, 0xe1, 0xfd, 0x47, 0x83,
-	0xce, 0x6e, 0x8c, 0x87, 0xc3, 0x60, 0xd4, 0x67, 0x2f, 0xd0, 0x6a, 0x0c, 0x66, 0x04, 0x3c, 0xce,
-	0x27, 0x51, 0x4e, 0x62, 0xd7, 0xc0, 0xaa, 0x7c, 0x24, 0xf0, 0x3e, 0x6d, 0x08, 0x83, 0x63, 0x67,
-	0xe8, 0xa9, 0x8d, 0x90, 0x07, 0x31, 0x57, 0x6a, 0x91, 0xc4, 0x8b, 0x15, 0x76, 0x9a, 0x9e, 0xe8,
-	0x84, 0xe3, 0x49, 0x1a, 0x51, 0x65, 0x2d, 0xba, 0x22, 0xfa, 0xa4, 0x62, 0x89, 0xa2, 0xa8, 0xb1,
-	0x73, 0x74, 0x19, 0xba, 0x16, 0xe0, 0x67, 0xd8, 0x45, 0xda, 0xda, 0xe6, 0x71, 0xfe, 0x41, 0x48,
-	0x51, 0xcd, 0xc2, 0x3c, 0xaf, 0x4f, 0xfa, 0xc5, 0xf3, 0xd4, 0xd9, 0x73, 0xf4, 0xb4, 0xe0, 0x44,
-	0x3b, 0x2f, 0x85, 0x6c, 0x00, 0x52, 0x38, 0x9a, 0x2c, 0x92, 0xb2, 0x53, 0xf4, 0xb8, 0xe8, 0x09,
-	0xf6, 0xa2, 0xc0, 0x4d, 0x76, 0x82, 0x2e, 0x00, 0xe3, 0x26, 0x70, 0x1e, 0x68, 0x05, 0x1f, 0x26,
-	0x78, 0x01, 0xf4, 0xb3, 0xcd, 0xe3, 0xc4, 0x62, 0x14, 0x62, 0x91, 0x31, 0x3a, 0x0f, 0xd2, 0x05,
-	0x71, 0xa0, 0x60, 0xc7, 0xd9, 0x0a, 0x75, 0xb7, 0x79, 0x8c, 0x36, 0x9f, 0xe9, 0xc1, 0xd8, 0x59,
-	0x7a, 0x46, 0xca, 0x61, 0x6c, 0x6e, 0x85, 0x3e, 0x85, 0x92, 0x84, 0xe3, 0x49, 0x1e, 0x72, 0x49,
-	0xaf, 0xa0, 0xca, 0xe8, 0x14, 0xca, 0xb5, 0x17, 0xd7, 0x44, 0x9d, 0x01, 0x94, 0x90, 0x29, 0x8d,
-	0x5a, 0x06, 0x94, 0xd0, 0x5b, 0x7a, 0xc0, 0xe7, 0x34, 0x2a, 0xdd, 0x6b, 0x85, 0x2d, 0x51, 0xb6,
-	0xcd, 0xe3, 0x74, 0x97, 0xb3, 0xec, 0x24, 0x5d, 0x44, 0xde, 0x61, 0x0d, 0x14, 0xf4, 0x1c, 0x08,
-	0x8c, 0xe1, 0x52, 0xda, 0x96, 0x18, 0x54, 0xa1, 0xcf, 0x83, 0xc0, 0x82, 0x3b, 0xed, 0x8c, 0x14,
-	0xf2, 0x3b, 0x60, 0x3c, 0xd0, 0x37, 0x65, 0x14, 0xf6, 0x10, 0x2f, 0x80, 0xc2, 0x95, 0x5a, 0x92,
-	0x13, 0x83, 0xc2, 0xbe, 0x08, 0x5c, 0x5d, 0xdd, 0x8f, 0x79, 0xa8, 0x1c, 0xf0, 0xc6, 0xb0, 0xbf,
-	0xd8, 0x86, 0x85, 0xf6, 0xc5, 0x94, 0x83, 0xd1, 0x5d, 0x45, 0xfc, 0x7d, 0x58, 0x68, 0xc9, 0x0d,
-	0x9e, 0xbb, 0x14, 0xe2, 0x07, 0x80, 0xf0, 0xf9, 0x64, 0x1c, 0xc6, 0x22, 0x46, 0x29, 0xc4, 0x65,
-	0x50, 0x46, 0x2f, 0x9c, 0x8e, 0xb8, 0x08, 0xaf, 0x0a, 0xfe, 0x43, 0xb0, 0x68, 0x60, 0xdd, 0x60,
-	0xc9, 0x66, 0xfb, 0x0a, 0x5b, 0xa6, 0x4b, 0xa0, 0xae, 0x1c, 0xa6, 0x7f, 0x1e, 0x98, 0x86, 0x90,
-	0xea, 0x07, 0x23, 0x6d, 0x3b, 0x3f, 0x62, 0x2e, 0x3d, 0x89, 0xd3, 0xab, 0x53, 0x80, 0xc2, 0xbc,
-	0xa2, 0x37, 0x80, 0x0e, 0xf5, 0x0a, 0xf9, 0x63, 0xd8, 0xa2, 0x86, 0x8a, 0xc1, 0x93, 0x43, 0x38,
-	0x53, 0xf8, 0x9f, 0xe8, 0x25, 0x80, 0xe5, 0x14, 0x29, 0x96, 0x42, 0xfe, 0x14, 0xe4, 0x13, 0xca,
-	0xc5, 0x94, 0x57, 0xc1, 0xaf, 0x02, 0x5c, 0x74, 0xb2, 0xe0, 0xeb, 0x5a, 0x83, 0x22, 0x5d, 0x54,
-	0x88, 0x0d, 0xe8, 0xe0, 0xf3, 0xe1, 0xf8, 0x81, 0xdd, 0xa1, 0xf3, 0xdd, 0x7a, 0xbd, 0xbf, 0xf8,
-	0xf8, 0xf1, 0xe3, 0xc7, 0x8e, 0xf7, 0xd8, 0x29, 0xf0, 0x50, 0xb9, 0x81, 0xa7, 0x43, 0x17, 0xb2,
-	0x59, 0x17, 0x39, 0x24, 0x85, 0x4a, 0x77, 0x81, 0xa4, 0x51, 0x9d, 0x4f, 0xa7, 0x43, 0x8c, 0xbf,
-	0x4d, 0xdf, 0x80, 0xb0, 0xe7, 0x69, 0x65, 0xfb, 0xfe, 0x00, 0x23, 0x56, 0x41, 0x2e, 0x01, 0xf8,
-	0xf6, 0xab, 0x74, 0x76, 0x4f, 0xf2, 0x3a, 0x6f, 0xbb, 0x62, 0xf7, 0x2e, 0x76, 0x5d, 0x51, 0xd0,
-	0x3c, 0xf9, 0x7c, 0xd5, 0xd9, 0x1b, 0xe7, 0x3a, 0xe2, 0x3c, 0xf9, 0xdb, 0x9d, 0xe2, 0x29, 0xef,
-	0x59, 0x7a, 0xc8, 0x19, 0x50, 0x4f, 0xf8, 0x3f, 0xa4, 0xdc, 0xc3, 0x97, 0x86, 0xd5, 0xdc, 0x25,
-	0x70, 0x9e, 0x74, 0x09, 0xf0, 0xd8, 0x28, 0xc2, 0x43, 0x4f, 0x9e, 0x18, 0x34, 0xa0, 0xbd, 0x59,
-	0x2c, 0xe6, 0x00, 0xc5, 0xfc, 0x8e, 0xa5, 0xd9, 0x7c, 0x29, 0xb4, 0xbc, 0x9f, 0x90, 0xb2, 0x78,
-	0x55, 0x2a, 0xad, 0x5a, 0x04, 0xc7, 0x58, 0x84, 0x9b, 0xc5, 0xdc, 0xbd, 0x85, 0xdc, 0x5d, 0x30,
-	0x16, 0xe1, 0x30, 0xde, 0xbe, 0x20, 0x87, 0xc7, 0xca, 0x27, 0xe6, 0xf0, 0x17, 0x8a, 0x39, 0xbc,
-	0x8f, 0x1c, 0xbe, 0xa0, 0x8c, 0xfa, 0x90, 0x99, 0x35, 0x9f, 0x7f, 0x5d, 0x29, 0x8f, 0xd6, 0x4f,
-	0xca, 0x23, 0x1c, 0xac, 0xb7, 0xf8, 0x43, 0x79, 0x90, 0xc2, 0x0a, 0x90, 0x6c, 0x5a, 0xa9, 0x69,
-	0x35, 0x55, 0x60, 0x30, 0x53, 0xcd, 0x9a, 0x5d, 0x30, 0x28, 0x48, 0x5b, 0x67, 0x0a, 0x8b, 0x0f,
-	0x98, 0xe6, 0xdd, 0xe7, 0x52, 0x01, 0x58, 0x3c, 0xc2, 0x34, 0x2f, 0x01, 0x65, 0xd3, 0x3c, 0x72,
-	0x78, 0x9a, 0x47, 0x8e, 0x9c, 0xe6, 0x91, 0xfc, 0x34, 0xaf, 0xcc, 0xfa, 0xf7, 0x2d, 0xeb, 0x2f,
-	0x5b, 0x0f, 0xbd, 0x72, 0xff, 0x4e, 0x0a, 0x4f, 0x51, 0xa5, 0x8b, 0xb6, 0x44, 0x67, 0xac, 0xc2,
-	0xd6, 0x8c, 0xde, 0xba, 0x10, 0xa6, 0xa2, 0x38, 0x18, 0x4e, 0x64, 0x36, 0xa8, 0x01, 0x98, 0x47,
-	0xc2, 0x34, 0x98, 0x48, 0x55, 0x45, 0xcd, 0x3c, 0x01, 0xb4, 0x6f, 0x14, 0x8b, 0x36, 0x44, 0xd1,
-	0xce, 0x59, 0x1b, 0x3b, 0xc3, 0xb0, 0x96, 0xea, 0xef, 0x48, 0xe1, 0xf1, 0xef, 0xa9, 0xa4, 0xf2,
-	0x64, 0x36, 0xa7, 0x2a, 0xd5, 0xe2, 0x36, 0xc2, 0x82, 0x95, 0x71, 0x3f, 0xb2, 0xb8, 0x2f, 0x60,
-	0x4c, 0x73, 0xff, 0x17, 0x24, 0xe7, 0x7c, 0xfa, 0x6c, 0x52, 0xad, 0xf6, 0x7a, 0x31, 0xd7, 0xbf,
-	0x86, 0x5c, 0xbb, 0x96, 0xce, 0x0d, 0x86, 0x34, 0xbf, 0x77, 0x33, 0xe7, 0xe6, 0xdc, 0xf0, 0xf4,
-	0xd3, 0xe2, 0xa9, 0x42, 0x9c, 0x6a, 0xc9, 0xf0, 0x8c, 0xb9, 0x13, 0xbd, 0x9b, 0x73, 0x16, 0x3f,
-	0xaa, 0x5e, 0xca, 0x24, 0x8d, 0x2c, 0x49, 0x33, 0x53, 0x68, 0x06, 0xbe, 0x22, 0xb9, 0xc7, 0x7e,
-	0xb0, 0x29, 0xa0, 0x1f, 0x69, 0x3e, 0x92, 0xb6, 0x65, 0x6f, 0x4e, 0x59, 0x16, 0x5a, 0x49, 0x65,
-	0xa1, 0x65, 0xf1, 0x3c, 0xb6, 0xe2, 0x79, 0x0e, 0x4b, 0x9a, 0xe7, 0x30, 0x9d, 0x90, 0xb0, 0xf3,
-	0xe2, 0x2a, 0x4e, 0x16, 0xbb, 0xe7, 0x8c, 0xdb, 0x1c, 0x1f, 0x11, 0xed, 0x9f, 0x14, 0x4f, 0x3c,
-	0xc5, 0x89, 0x4f, 0x19, 0x11, 0x42, 0x0f, 0xac, 0xe7, 0xfc, 0x98, 0x14, 0x67, 0x3c, 0xa5, 0xca,
-	0x4a, 0x8c, 0xd7, 0x31, 0x8c, 0xb7, 0xdd, 0x2d, 0xe6, 0xe7, 0x01, 0xf2, 0x73, 0x5e, 0xf3, 0x93,
-	0x3b, 0xa7, 0xe6, 0xec, 0xff, 0x48, 0x49, 0xb6, 0x55, 0x58, 0x0b, 0x2d, 0x5a, 0xbf, 0xd5, 0xec,
-	0x71, 0x47, 0xd4, 0x90, 0x32, 0x47, 0x1a, 0x55, 0x93, 0xa9, 0x96, 0xd4, 0x64, 0x6a, 0xd9, 0x9a,
-	0x4c, 0xfb, 0xb5, 0x62, 0xd1, 0x1f, 0xa1, 0xe8, 0x2d, 0xdb, 0x27, 0x66, 0x85, 0xd2, 0xb2, 0xff,
-	0x03, 0x29, 0x4c, 0x25, 0x9f, 0x9d, 0xe4, 0x65, 0x7e, 0xf1, 0x6d, 0xdb, 0x2f, 0xe6, 0xb3, 0xa6,
-	0xf9, 0xff, 0x67, 0x52, 0x90, 0xed, 0x02, 0xa7, 0x37, 0x76, 0x76, 0x7a, 0x78, 0xcd, 0x23, 0x4d,
-	0x4a, 0xb5, 0xcd, 0x6b, 0x26, 0xa1, 0xfc, 0xd4, 0x35, 0x13, 0x62, 0x84, 0x78, 0xaa, 0x09, 0xda,
-	0xf0, 0x81, 0x41, 0xe1, 0xe7, 0xf1, 0x7f, 0xd9, 0x81, 0xfe, 0x9d, 0x9c, 0x03, 0x7d, 0x8a, 0x45,
-	0x2d, 0xc5, 0x87, 0xa4, 0x20, 0x31, 0x3f, 0x4c, 0x8a, 0x7c, 0x5e, 0xcb, 0xf8, 0xfa, 0xf5, 0x82,
-	0x44, 0x23, 0x97, 0xaf, 0x37, 0x68, 0x53, 0xe1, 0x30, 0x1f, 0x4b, 0xee, 0xec, 0x80, 0x95, 0x63,
-	0xf2, 0xce, 0x6e, 0x85, 0x36, 0x10, 0x29, 0xeb, 0x95, 0x18, 0xde, 0x13, 0x80, 0xbe, 0x85, 0xab,
-	0x18, 0xb7, 0x70, 0xde, 0xb8, 0xa0, 0xa4, 0x90, 0xae, 0xd2, 0x96, 0x49, 0xf2, 0xae, 0x25, 0x49,
-	0xee, 0x70, 0x5a, 0x92, 0x49, 0x41, 0xa1, 0x22, 0x33, 0xe1, 0xf5, 0xe2, 0x09, 0x1f, 0x93, 0x9c,
-	0x19, 0x0b, 0x75, 0xf7, 0x2a, 0x1c, 0x3c, 0xa3, 0xc9, 0x78, 0x14, 0x71, 0x98, 0xe4, 0xf6, 0x4d,
-	0x9c, 0xa4, 0xee, 0x3b, 0xb7, 0x6f, 0x82, 0x52, 0xae, 0x85, 0xe1, 0x38, 0x94, 0xe5, 0x5d, 0xd1,
-	0xd0, 0x4f, 0x1e, 0x44, 0x81, 0x57, 0x34, 0xbc, 0x7f, 0x24, 0x79, 0x85, 0x94, 0x6f, 0xc5, 0xbc,
-	0x4b, 0x82, 0xcd, 0x7b, 0x42, 0x17, 0x67, 0xb4, 0x93, 0x2d, 0x54, 0xfd, 0x9d, 0x6c, 0xc1, 0x27,
-	0xa3, 0xf5, 0x92, 0x40, 0xfc, 0x1b, 0x62, 0xa6, 0xd3, 0xa6, 0x47, 0x30, 0x86, 0xd2, 0xf3, 0xbc,
-	0x53, 0x52, 0x42, 0xca, 0x3d, 0x7c, 0x94, 0xa4, 0x65, 0xef, 0x13, 0xcb, 0x91, 0x16, 0x8e, 0xab,
-	0x67, 0xff, 0x57, 0x52, 0x58, 0xa2, 0x02, 0xad, 0x8b, 0xa2, 0x8a, 0x28, 0x16, 0x57, 0x7c, 0xd5,
-	0x04, 0x8c, 0xb8, 0x70, 0xe8, 0xcb, 0x9d, 0xa3, 0x9a, 0x70, 0x38, 0xeb, 0xec, 0xca, 0x64, 0x07,
-	0x8f, 0x9d, 0xa2, 0x85, 0x87, 0xb6, 0x09, 0xc2, 0xc5, 0xd2, 0xca, 0x56, 0x59, 0x3c, 0xfc, 0x2d,
-	0x62, 0xf9, 0xd4, 0x02, 0x2e, 0xb5, 0x28, 0x5f, 0x92, 0xc3, 0x0b, 0x6a, 0x4f, 0x9c, 0x61, 0xfa,
-	0xc5, 0xfc, 0xfd, 0x36, 0xb1, 0x52, 0xcc, 0xc3, 0xa6, 0xd6, 0x8c, 0x7e, 0x43, 0x8a, 0x6b, 0x7a,
-	0xa8, 0xc0, 0x75, 0x63, 0xcd, 0x65, 0xcb, 0x50, 0xa0, 0x63, 0x2a, 0x30, 0x61, 0xba, 0x62, 0x44,
-	0xbb, 0xa3, 0xd5, 0x75, 0xd8, 0x45, 0xea, 0x74, 0x7d, 0xcc, 0x2e, 0x8b, 0xee, 0x6c, 0x9d, 0xae,
-	0x5f, 0x16, 0xb6, 0x3f, 0x24, 0xd6, 0x91, 0xa5, 0x48, 0x26, 0x2d, 0xf9, 0x3f, 0x91, 0x6c, 0xbd,
-	0xf2, 0x5b, 0x94, 0xb8, 0x6c, 0xbf, 0x7e, 0x64, 0xef, 0xd7, 0x34, 0x97, 0x5a, 0x86, 0x7f, 0x4b,
-	0x76, 0x4c, 0x67, 0xb7, 0x17, 0x5b, 0x15, 0x45, 0x60, 0x79, 0x27, 0x88, 0xee, 0xeb, 0x8b, 0x26,
-	0xd1, 0x4a, 0x2e, 0xa0, 0xfa, 0xf2, 0x59, 0x98, 0x6c, 0x81, 0x3f, 0xe9, 0xac, 0x4b, 0x41, 0x9c,
-	0xce, 0x3a, 0xb4, 0x7b, 0x3b, 0xf2, 0x6a, 0xd9, 0xe9, 0xed, 0x68, 0x87, 0x5b, 0x33, 0x1c, 0x6e,
-	0xd9, 0x9e, 0xf9, 0x38, 0x6f, 0xcf, 0x64, 0xf8, 0xd4, 0xc2, 0xfc, 0x2f, 0xc9, 0x29, 0x15, 0x1f,
-	0x96, 0x57, 0xe6, 0xae, 0xca, 0x11, 0xf2, 0x4a, 0xcc, 0x99, 0x27, 0xfb, 0x03, 0x71, 0xf7, 0x2a,
-	0xef, 0x50, 0x13, 0x00, 0x6b, 0xc9, 0x5b, 0xf0, 0xf5, 0xf1, 0x74, 0xd4, 0x57, 0x47, 0x48, 0x13,
-	0xd4, 0xde, 0x28, 0x16, 0xfc, 0xf7, 0x88, 0x95, 0xf8, 0x64, 0x64, 0xd2, 0x22, 0xff, 0x37, 0xc9,
-	0x2d, 0x83, 0x3f, 0x95, 0xd0, 0x2d, 0x3a, 0x67, 0x98, 0xbb, 0x5c, 0x48, 0x13, 0xc4, 0x5e, 0xa6,
-	0xcd, 0x57, 0x07, 0x7c, 0xbf, 0xbf, 0x33, 0x16, 0xbb, 0x43, 0xde, 0x96, 0x31, 0xc9, 0x27, 0xe2,
-	0x04, 0x1f, 0xbe, 0x4d, 0xd8, 0xbe, 0x56, 0x2c, 0xec, 0x27, 0xc4, 0xca, 0x99, 0x72, 0xa4, 0xd1,
-	0xe2, 0x76, 0xe9, 0x9c, 0x31, 0x09, 0x2c, 0x01, 0x36, 0x8d, 0xfd, 0xa6, 0x01, 0x09, 0x36, 0x39,
-	0x13, 0xd5, 0x7c, 0x0d, 0xf0, 0x5e, 0x92, 0x97, 0x78, 0xb9, 0xf7, 0xd2, 0xcb, 0xe9, 0x7b, 0x69,
-	0x7d, 0x27, 0xed, 0x7d, 0x46, 0xe8, 0xbc, 0x7d, 0x6d, 0xff, 0x2d, 0x5d, 0xcb, 0x7f, 0x57, 0x5e,
-	0x6a, 0xf3, 0xf4, 0xbd, 0x7c, 0x22, 0x87, 0xaf, 0x08, 0xbc, 0xf7, 0x88, 0xb4, 0x3f, 0xf9, 0x0e,
-	0x2b, 0x89, 0x7e, 0x8a, 0x4d, 0xd5, 0x4c, 0x4a, 0x3f, 0xdb, 0x83, 0xb7, 0xb9, 0xdc, 0xd0, 0x1a,
-	0x80, 0x66, 0xcc, 0xc3, 0x01, 0x8f, 0x36, 0xc6, 0x53, 0x69, 0x13, 0x35, 0xdf, 0x04, 0xc1, 0xc8,
-	0x9b, 0xc1, 0x81, 0xb1, 0x09, 0x54, 0xd3, 0xfb, 0x65, 0xda, 0xf4, 0x27, 0x26, 0x13, 0xda, 0xf0,
-	0x88, 0x65, 0x78, 0x6d, 0x79, 0xbf, 0x0d, 0x64, 0x91, 0xac, 0x4b, 0x33, 0xd3, 0xed, 0x89, 0xfe,
-	0xbe, 0x41, 0xe5, 0xbd, 0x49, 0x69, 0x67, 0x5d, 0x79, 0x02, 0xe9, 0x7a, 0x48, 0xe2, 0x7a, 0xc4,
-	0xb3, 0xb9, 0x8e, 0xbc, 0x04, 0xc6, 0xff, 0x6c, 0x8d, 0xce, 0xfa, 0x13, 0x31, 0x45, 0xc5, 0xba,
-	0xb9, 0xb6, 0x98, 0xf4, 0x15, 0x91, 0xf7, 0xbb, 0x84, 0x9e, 0x36, 0x2f, 0x92, 0x6e, 0x8d, 0x83,
-	0xe4, 0xe8, 0x24, 0x9e, 0xe0, 0xed, 0x00, 0xa1, 0x7c, 0x7a, 0x77, 0xdc, 0x78, 0x2f, 0x28, 0x47,
-	0x4a, 0x48, 0xca, 0x7c, 0xdc, 0xef, 0xdb, 0x3e, 0xae, 0x60, 0x42, 0xbd, 0x03, 0xde, 0xce, 0xbb,
-	0xc4, 0x62, 0xe7, 0xa4, 0x06, 0x11, 0x2a, 0xcf, 0xb8, 0x06, 0xa4, 0xec, 0x10, 0xf9, 0xa9, 0x7d,
-	0x88, 0xcc, 0x0e, 0xae, 0xe7, 0xfe, 0x17, 0x52, 0x7e, 0x53, 0xf6, 0x54, 0x25, 0xbc, 0x43, 0xbd,
-	0x4e, 0x7b, 0xab, 0x98, 0xf9, 0x3f, 0x20, 0x56, 0x69, 0xb5, 0x8c, 0x39, 0x2d, 0xc6, 0xdf, 0x90,
-	0xa2, 0xeb, 0xbc, 0x67, 0x24, 0x40, 0x49, 0xa6, 0xfd, 0x87, 0x42, 0x80, 0xb3, 0xc6, 0xc1, 0xba,
-	0xec, 0xc8, 0xf1, 0xe7, 0x84, 0x36, 0xe5, 0xd5, 0x5f, 0x28, 0xde, 0xd6, 0xad, 0x88, 0x57, 0xc8,
-	0x22, 0x67, 0x11, 0x5b, 0x5b, 0x03, 0x8c, 0xb7, 0x22, 0x66, 0xa8, 0xee, 0x40, 0x28, 0xee, 0xc5,
-	0xdd, 0xbe, 0xd8, 0x09, 0x4d, 0x5f, 0x34, 0xd8, 0x65, 0xda, 0x50, 0xe5, 0x6c, 0xf5, 0x10, 0xc2,
-	0x35, 0xb7, 0xa1, 0x42, 0xca, 0x87, 0xd9, 0x8a, 0x54, 0xa7, 0x97, 0x35, 0x33, 0xbd, 0xfc, 0x9c,
-	0x64, 0x6f, 0x46, 0x9f, 0x4a, 0xc1, 0x86, 0xef, 0xaa, 0x58, 0xbe, 0xab, 0xec, 0x04, 0xf4, 0x47,
-	0xf6, 0x09, 0x28, 0xcd, 0x88, 0x56, 0xe9, 0x6f, 0x92, 0xfc, 0xab, 0x5a, 0x9d, 0x09, 0x12, 0xf3,
-	0xf1, 0xfb, 0x22, 0xad, 0xf4, 0x62, 0x15, 0x14, 0xe0, 0x6f, 0x59, 0x76, 0xfc, 0xc7, 0x82, 0x89,
-	0xe7, 0xf2, 0x94, 0x98, 0x93, 0x1d, 0x33, 0x85, 0xeb, 0x70, 0x51, 0x6c, 0x19, 0x87, 0xa0, 0xb0,
-	0x9d, 0x01, 0x0f, 0x77, 0xd4, 0x03, 0x92, 0xaa, 0x9f, 0xb4, 0xe1, 0x94, 0x02, 0xff, 0x53, 0x2f,
-	0x02, 0x2d, 0x98, 0x75, 0x2d, 0x53, 0xb1, 0x5f, 0x0c, 0x7a, 0x7f, 0x4b, 0xe8, 0x82, 0x4c, 0x82,
-	0xe0, 0xa0, 0x7f, 0x47, 0xbe, 0x9c, 0x2a, 0x08, 0x14, 0xe9, 0x33, 0x91, 0x93, 0x73, 0x26, 0x52,
-	0xa9, 0x54, 0x67, 0x57, 0xee, 0x03, 0xd5, 0x4c, 0x30, 0xbd, 0x58, 0x9e, 0x08, 0x55, 0xd3, 0x58,
-	0xf6, 0x5a, 0xfa, 0xc6, 0x42, 0x5c, 0x41, 0x80, 0xe8, 0x33, 0x22, 0xb8, 0x27, 0x00, 0xef, 0x3a,
-	0x6d, 0x26, 0x6b, 0xaa, 0x36, 0x82, 0x8e, 0xb9, 0xa4, 0x24, 0xe6, 0x3a, 0x56, 0xcc, 0xf5, 0xde,
-	0x27, 0x74, 0x01, 0x97, 0xd6, 0x50, 0xba, 0xf1, 0x7c, 0x8c, 0x58, 0xcf, 0xc7, 0x40, 0x09, 0xd6,
-	0xd3, 0x78, 0xa9, 0x04, 0xeb, 0x5d, 0x7c, 0x5b, 0x5c, 0xb5, 0x20, 0x6b, 0xa8, 0x06, 0x1d, 0x6a,
-	0x2c, 0x96, 0x7d, 0x4d, 0xe6, 0x3d, 0x26, 0xf4, 0x78, 0x66, 0x8f, 0xb1, 0x9f, 0xa5, 0x35, 0x5c,
-	0x1a, 0x97, 0x58, 0x75, 0xf8, 0xd4, 0x9a, 0xf9, 0x82, 0x88, 0xbd, 0x42, 0x8f, 0x99, 0xbd, 0x65,
-	0x20, 0x55, 0x8e, 0x3d, 0x6b, 0x5b, 0xbe, 0x45, 0xee, 0xfd, 0x17, 0x91, 0x37, 0x71, 0xb6, 0x5e,
-	0x2d, 0x69, 0xc8, 0x91, 0xa4, 0x61, 0x97, 0x29, 0x15, 0xc7, 0xa5, 0xe4, 0xe3, 0x11, 0xcd, 0x7c,
-	0x4a, 0xd7, 0xbe, 0x41, 0xc9, 0x7e, 0x4c, 0x9b, 0x96, 0x12, 0xa4, 0xf6, 0x8a, 0x9d, 0x90, 0x4d,
-	0x6e, 0x9b, 0x4c, 0x15, 0xb3, 0x0c, 0xc3, 0x64, 0x86, 0xf4, 0x94, 0x45, 0x9e, 0x54, 0x86, 0xca,
-	0x7d, 0xa8, 0xe5, 0x15, 0x9d, 0x23, 0x7b, 0x45, 0xef, 0xef, 0x49, 0xe1, 0x4b, 0x8f, 0xa7, 0xbd,
-	0xeb, 0xb2, 0x4c, 0xaf, 0x92, 0x35, 0xbd, 0xb2, 0x83, 0xc6, 0x67, 0x24, 0xe7, 0xb2, 0x2b, 0xc3,
-	0x99, 0x55, 0x4b, 0x29, 0x79, 0x8b, 0x52, 0xe2, 0x27, 0xd4, 0x7b, 0x4c, 0xc7, 0x78, 0x8f, 0xf9,
-	0xa4, 0x85, 0x94, 0x5b, 0xc5, 0x72, 0xfc, 0x09, 0xb1, 0x6e, 0xeb, 0x8b, 0x59, 0xb4, 0xee, 0xc1,
-	0x36, 0x30, 0x7f, 0x0a, 0xf6, 0x07, 0xf1, 0xa3, 0xa7, 0xb6, 0xea, 0x16, 0x9d, 0x33, 0x86, 0x91,
-	0xf2, 0x99, 0x20, 0xef, 0x2d, 0xba, 0x6c, 0x46, 0xef, 0xd4, 0x9c, 0x79, 0xa5, 0xfc, 0x97, 0xd3,
-	0x63, 0x9a, 0xef, 0xac, 0x53, 0x03, 0xd8, 0x73, 0xbd, 0x49, 0x4f, 0x18, 0xcd, 0xc4, 0x96, 0x5f,
-	0x82, 0xa8, 0x75, 0x67, 0x1c, 0xc9, 0x63, 0xe9, 0x85, 0xec, 0x93, 0xed, 0xf4, 0xa8, 0x82, 0x1e,
-	0x02, 0xdb, 0xb5, 0x50, 0x15, 0x43, 0xe1, 0xaf, 0xf7, 0x35, 0x29, 0x7c, 0x6d, 0x94, 0xc9, 0x78,
-	0xec, 0xef, 0x57, 0x6a, 0xd6, 0xf7, 0x1f, 0xb1, 0x59, 0x79, 0x8e, 0xb3, 0xdf, 0x7f, 0x54, 0xd3,
-	0xdf, 0x7f, 0x94, 0x99, 0xf1, 0xe7, 0x79, 0x35, 0x81, 0x0c, 0x7f, 0xd6, 0x8d, 0x33, 0x7e, 0x06,
-	0x83, 0x29, 0xc2, 0x6e, 0x92, 0x22, 0xec, 0xb2, 0xb3, 0xd4, 0xe9, 0xc5, 0xd2, 0x37, 0xa5, 0xbe,
-	0x9b, 0x71, 0x7a, 0x31, 0xbb, 0x94, 0xbc, 0x81, 0xae, 0xd8, 0xdf, 0x56, 0xed, 0xf6, 0x62, 0xb1,
-	0xef, 0x23, 0xf5, 0x85, 0x01, 0x36, 0x96, 0xb7, 0x65, 0xc2, 0x15, 0x65, 0xbe, 0x00, 0xa8, 0x8a,
-	0x2f, 0x00, 0xd6, 0xec, 0x0f, 0x8c, 0x8a, 0x7d, 0x88, 0xf1, 0x6d, 0xc0, 0x7f, 0x12, 0xba, 0x98,
-	0xfe, 0xb2, 0x09, 0xb6, 0x1e, 0xc7, 0x46, 0x5f, 0x7e, 0x60, 0xa0, 0x9a, 0xe0, 0xc8, 0xb8, 0x71,
-	0x0b, 0x40, 0x20, 0xe3, 0x4d, 0x00, 0x60, 0x7f, 0xe3, 0x09, 0x7e, 0x4b, 0x84, 0x4f, 0x97, 0xe1,
-	0x3f, 0x3b, 0x4b, 0x2b, 0x93, 0x58, 0x95, 0x9a, 0xe6, 0x0c, 0x19, 0x7d, 0x80, 0xc3, 0x80, 0x7b,
-	0xd3, 0x30, 0x04, 0xdd, 0x72, 0x2c, 0xdb, 0xd4, 0x7c, 0x0d, 0x00, 0x2f, 0x36, 0x09, 0xb9, 0x40,
-	0xce, 0x20, 0x32, 0x69, 0x83, 0xfc, 0x51, 0xb8, 0xe7, 0xce, 0x0a, 0xf9, 0xa3, 0x10, 0xbf, 0x4a,
-	0xe9, 0xf3, 0x28, 0xc6, 0x87, 0xf9, 0x55, 0x1f, 0xff, 0x7b, 0x1f, 0x90, 0xbc, 0x37, 0x6b, 0xec,
-	0x07, 0x52, 0x0e, 0x0c, 0x63, 0x62, 0x77, 0x16, 0x7e, 0xe7, 0xa5, 0x29, 0xcb, 0xb2, 0x9c, 0x2f,
-	0xec, 0x2c, 0x27, 0x3b, 0xa7, 0xb6, 0x18, 0xe0, 0x29, 0xfb, 0x5e, 0xee, 0x19, 0xf0, 0xf4, 0xa5,
-	0xcd, 0x53, 0x76, 0x4e, 0xab, 0xd4, 0x98, 0xf7, 0x56, 0xef, 0x49, 0x8d, 0x7a, 0x85, 0x36, 0x30,
-	0xda, 0xe2, 0xc7, 0x7f, 0xc2, 0x0c, 0x34, 0xc0, 0xfa, 0x86, 0x8b, 0xe8, 0x6f, 0xd0, 0xca, 0x6a,
-	0x37, 0x7f, 0x9a, 0x57, 0xbb, 0xb1, 0x58, 0xd4, 0x32, 0xc4, 0x79, 0xaf, 0x0a, 0x6d, 0x63, 0x76,
-	0x0c, 0x63, 0x2e, 0xd3, 0xdc, 0x9f, 0xd9, 0x9a, 0xcb, 0x0e, 0x9b, 0xcc, 0xfa, 0xff, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0x5a, 0x80, 0x21, 0xa5, 0x0e, 0x3b, 0x00, 0x00,
+	// 4367 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3b, 0x5d, 0x8f, 0x1c, 0xc7,
+	0x71, 0xe8, 0xd9, 0xdd, 0xbb, 0xdd, 0x3e, 0xee, 0xdd, 0xb1, 0xf9, 0x35, 0x3a, 0x51, 0xe4, 0x72,
+	0x2c, 0x45, 0x07, 0xc3, 0x26, 0xa3, 0x83, 0x2d, 0xc9, 0x8a, 0x65, 0x9b, 0xbc, 0x3d, 0x91, 0x2b,
+	0xf2, 0xc8, 0x4d, 0xdf, 0x29, 0x06, 0x12, 0x20, 0xf1, 0xdc, 0x6d, 0x93, 0x5c, 0xdd, 0xed, 0xce,
+	0x66, 0x66, 0x96, 0x3a, 0x0a, 0x0e, 0x4c, 0xcb, 0x40, 0xf2, 0x10, 0xe4, 0x21, 0x08, 0x6c, 0xc7,
+	0x06, 0xf2, 0xa5, 0xd8, 0x4e, 0x1c, 0xcb, 0xb1, 0xf3, 0x94, 0x04, 0xf9, 0x00, 0xe2, 0xf8, 0x21,
+	0x08, 0x90, 0xa7, 0x3c, 0x27, 0xbf, 0x20, 0x06, 0xf2, 0x66, 0xe4, 0x2d, 0xa8, 0xea, 0xee, 0xe9,
+	0xee, 0xf9, 0x3a, 0x92, 0x80, 0xf4, 0xb4, 0xdb, 0x55, 0x35, 0x5d, 0x1f, 0x5d, 0x5d, 0xd5, 0x5d,
+	0xdd, 0x4d, 0x5f, 0x88, 0x66, 0x62, 0xfa, 0x1b, 0x49, 0xbc, 0x7f, 0x65, 0x3c, 0xbd, 0x7b, 0x38,
+	0x3f, 0xba, 0x32, 0x11, 0x69, 0x78, 0x65, 0x16, 0x47, 0x69, 0x84, 0x7f, 0x2f, 0xe3, 0x5f, 0xd6,
+	0xc2, 0x9f, 0xe0, 0xbd, 0x45, 0xda, 0xec, 0x87, 0x69, 0xc8, 0x18, 0x6d, 0xee, 0x8a, 0x78, 0xe2,
+	0x93, 0x9e, 0xb7, 0xde, 0xe4, 0xf8, 0x9f, 0x9d, 0xa6, 0xad, 0xc1, 0x74, 0x24, 0x8e, 0x7c, 0x0f,
+	0x81, 0xb2, 0xc1, 0xce, 0xd3, 0xce, 0xe6, 0xe1, 0x3c, 0x49, 0x45, 0x3c, 0xe8, 0xfb, 0x0d, 0xc4,
+	0x18, 0x00, 0x7b, 0x81, 0xb6, 0x6e, 0x47, 0x23, 0x91, 0xf8, 0xcd, 0x5e, 0x63, 0x7d, 0x69, 0x63,
+	0x45, 0xb2, 0xbb, 0x0c, 0xb0, 0xc1, 0xf4, 0x6e, 0xc4, 0x25, 0x96, 0xbd, 0x44, 0x3b, 0xc0, 0x76,
+	0x2f, 0x4c, 0x44, 0xe2, 0xb7, 0x90, 0xf4, 0x94, 0x22, 0xd5, 0x70, 0x24, 0x37, 0x54, 0xd0, 0xf3,
+	0x5b, 0x89, 0x88, 0x13, 0x7f, 0xc1, 0xe9, 0x19, 0x60, 0xb2, 0x67, 0xc4, 0x82, 0x78, 0xdb, 0xe1,
+	0x11, 0xf2, 0xeb, 0xfb, 0x8b, 0x52, 0xbc, 0x0c, 0xc0, 0xd6, 0xe9, 0xca, 0x76, 0x78, 0xb4, 0x73,
+	0x3f, 0x8c, 0x47, 0xd7, 0xe3, 0x68, 0x3e, 0x1b, 0xf4, 0xfd, 0x36, 0xd2, 0xe4, 0xc1, 0xec, 0x02,
+	0xa5, 0x1a, 0x34, 0xe8, 0xfb, 0x1d, 0x24, 0xb2, 0x20, 0xec, 0x93, 0x52, 0x03, 0xa9, 0x2c, 0x75,
+	0x44, 0xd2, 0x70, 0x6e, 0x28, 0x80, 0x7c, 0x5b, 0x68, 0xf2, 0xa5, 0x72, 0xdb, 0x18, 0x0a, 0x16,
+	0xd0, 0x13, 0xca, 0xa6, 0xc3, 0xf4, 0xf6, 0x7c, 0xe2, 0x2f, 0xf7, 0xbc, 0xf5, 0x2e, 0x77, 0x60,
+	0xec, 0x0a, 0x5d, 0x18, 0xa6, 0xbf, 0x32, 0x16, 0xef, 0xf8, 0x2b, 0xd8, 0xdf, 0x39, 0x8b, 0xfd,
+	0x65, 0x89, 0xd9, 0x9a, 0xa6, 0xf1, 0x43, 0xae, 0xc8, 0xa0, 0x53, 0xfc, 0x72, 0x28, 0x62, 0xe0,
+	0xe2, 0xaf, 0xf6, 0x08, 0x74, 0x6a, 0xc3, 0x94, 0x81, 0x70, 0xa4, 0xb5, 0x81, 0x4e, 0x66, 0x06,
+	0xb2, 0xc1, 0xca, 0x40, 0x08, 0x1a, 0xf4, 0x7d, 0x96, 0x19, 0x48, 0x41, 0x80, 0xdb, 0x76, 0x78,
+	0xb4, 0xf5, 0x40, 0x4c, 0xd3, 0x3b, 0xb3, 0xc1, 0xc8, 0x3f, 0xd5, 0x23, 0xeb, 0x4d, 0xee, 0xc0,
+	0x80, 0xdb, 0x6e, 0x78, 0x20, 0xee, 0x3c, 0x10, 0xf1, 0xd6, 0x34, 0xdc, 0x3b, 0x14, 0x23, 0xff,
+	0x74, 0x8f, 0xac, 0xb7, 0x79, 0x1e, 0xcc, 0x5e, 0xa7, 0xdd, 0xed, 0xf1, 0xbd, 0x38, 0x4c, 0x05,
+	0x7e, 0x9d, 0xf8, 0x67, 0x1c, 0x9d, 0x6d, 0x1c, 0xda, 0xd2, 0xa5, 0x06, 0xe7, 0xe1, 0xd1, 0xa1,
+	0x48, 0xfc, 0xb3, 0x8e, 0xe9, 0x01, 0x26, 0x9d, 0x07, 0xb1, 0x6c, 0x9d, 0x2e, 0xec, 0x46, 0x07,
+	0x62, 0x9a, 0xf8, 0xe7, 0x90, 0x6e, 0x55, 0xd1, 0x21, 0x10, 0x09, 0x15, 0x7e, 0xed, 0x4d, 0xba,
+	0x64, 0x99, 0x98, 0xad, 0xd2, 0xc6, 0x81, 0x78, 0xe8, 0x93, 0x1e, 0x59, 0xef, 0x70, 0xf8, 0x0b,
+	0x1c, 0x1f, 0x84, 0x87, 0x73, 0xe1, 0x7b, 0x3d, 0x62, 0xfb, 0xc6, 0xb5, 0xa1, 0x14, 0x50, 0x62,
+	0x5f, 0xf3, 0x5e, 0x25, 0xc1, 0x25, 0xba, 0x38, 0x4c, 0xef, 0xbc, 0x33, 0x15, 0x31, 0x3b, 0x4b,
+	0x17, 0x94, 0xeb, 0xca, 0x89, 0xa8, 0x5a, 0xc1, 0xaf, 0xd2, 0x05, 0xf9, 0x1d, 0x7b, 0x9e, 0xb6,
+	0x90, 0x14, 0x09, 0x96, 0x36, 0x96, 0x55, 0xbf, 0xaa, 0x03, 0xde, 0xca, 0xfa, 0xd9, 0x49, 0xc3,
+	0x74, 0x9e, 0xe0, 0xdc, 0xed, 0x72, 0xd5, 0x82, 0x69, 0x3e, 0x4c, 0x07, 0x23, 0x9c, 0xb7, 0x5d,
+	0x8e, 0xff, 0x83, 0x4f, 0xd2, 0xb6, 0x96, 0x8a, 0x5d, 0xa2, 0xcd, 0xfe, 0xde, 0x30, 0xf5, 0x09,
+	0xaa, 0xdf, 0xcd, 0x3a, 0x47, 0x91, 0x11, 0x15, 0xfc, 0x98, 0xd0, 0xb6, 0x76, 0x59, 0xb6, 0x4c,
+	0xbd, 0x4c, 0x56, 0x6f, 0xd0, 0x87, 0xfe, 0x6f, 0x44, 0x49, 0x8a, 0x5c, 0x3b, 0x1c, 0xff, 0x33,
+	0x9f, 0x2e, 0xf2, 0xe1, 0xe6, 0xd5, 0xd1, 0x28, 0xf6, 0x5b, 0x68, 0x1f, 0xdd, 0x04, 0xcc, 0xee,
+	0xe6, 0x10, 0x3f, 0x68, 0x48, 0x8c, 0x6a, 0x5a, 0xf2, 0x37, 0x7b, 0xde, 0x7a, 0x23, 0x93, 0xff,
+	0x34, 0x6d, 0xdd, 0xda, 0x1d, 0x4f, 0x84, 0xbf, 0x20, 0x43, 0x12, 0x36, 0xc0, 0x15, 0xaf, 0x47,
+	0x49, 0x32, 0x9e, 0x21, 0x93, 0x45, 0xe4, 0x6d, 0x41, 0x82, 0xb7, 0x68, 0x5b, 0xcf, 0x44, 0x76,
+	0x91, 0x7a, 0xb7, 0xc7, 0xca, 0x78, 0x85, 0x19, 0xe8, 0xdd, 0x1e, 0x83, 0x4f, 0xf6, 0xc5, 0x7e,
+	0x34, 0x99, 0x8c, 0x93, 0x64, 0x1c, 0x4d, 0xc7, 0xd3, 0x7b, 0x38, 0x84, 0x6d, 0x9e, 0x07, 0x07,
+	0x3f, 0x27, 0xf4, 0x84, 0x1d, 0xad, 0x40, 0xfb, 0xdb, 0xe1, 0x44, 0x60, 0xef, 0x1d, 0x8e, 0xff,
+	0xd9, 0xcb, 0xf4, 0x6c, 0x5f, 0xdc, 0x0d, 0xe7, 0x87, 0x29, 0x17, 0xa9, 0x98, 0xa6, 0xe3, 0x68,
+	0x3a, 0x8c, 0x0e, 0xc7, 0xfb, 0x0f, 0x95, 0x8d, 0x2a, 0xb0, 0xec, 0x06, 0x3d, 0xe9, 0x82, 0xc6,
+	0x22, 0xf1, 0x1b, 0x38, 0x2c, 0x6b, 0xda, 0x7b, 0xdd, 0x4f, 0x50, 0x83, 0xe2, 0x47, 0xac, 0x47,
+	0x97, 0xb6, 0xc3, 0xf8, 0xa0, 0x2f, 0x0e, 0x45, 0x2a, 0x46, 0x38, 0x06, 0x6d, 0x6e, 0x83, 0xd8,
+	0x15, 0xda, 0xc6, 0xb0, 0x76, 0x53, 0x3c, 0xf4, 0x17, 0x7a, 0xc4, 0x0a, 0xc6, 0x1a, 0x8c, 0x7d,
+	0x67, 0x44, 0xc1, 0xef, 0x13, 0x7a, 0x2a, 0xc7, 0x7d, 0x67, 0x26, 0xf6, 0x2d, 0x03, 0x90, 0xcc,
+	0x00, 0x6b, 0xb4, 0xdd, 0x9f, 0xc7, 0x21, 0x50, 0xa2, 0x21, 0x1b, 0x3c, 0x6b, 0xb3, 0xcb, 0x94,
+	0x99, 0xa0, 0x9b, 0x51, 0x35, 0x90, 0xaa, 0x04, 0x03, 0x7d, 0x71, 0x31, 0x3b, 0x1c, 0xef, 0x87,
+	0xb7, 0xfd, 0x26, 0x46, 0xaf, 0xac, 0x1d, 0xfc, 0xc8, 0xa3, 0x2b, 0xdb, 0x22, 0x4c, 0xe6, 0xb1,
+	0x98, 0xa8, 0x28, 0x50, 0x3a, 0x20, 0x2f, 0xd1, 0x8e, 0xd6, 0x03, 0x66, 0x47, 0xa3, 0x4a, 0x5b,
+	0x43, 0xc5, 0x5e, 0xa3, 0x0b, 0x3b, 0xfb, 0xf7, 0xc5, 0x24, 0x54, 0x03, 0x10, 0xe8, 0xa8, 0xe3,
+	0xb2, 0xbb, 0x2c, 0x89, 0x54, 0xd0, 0x95, 0x8d, 0xbc, 0xf5, 0x9b, 0x45, 0xeb, 0x7f, 0x96, 0x2e,
+	0x8f, 0x21, 0x66, 0x72, 0x71, 0x88, 0x5a, 0xea, 0x84, 0x78, 0x5a, 0x71, 0x19, 0xd8, 0x48, 0x9e,
+	0xa3, 0x5d, 0xfb, 0x0c, 0x5d, 0xb2, 0xd8, 0x96, 0x04, 0xa2, 0xd3, 0x76, 0x20, 0x6a, 0xd9, 0x71,
+	0xe7, 0x1b, 0xcd, 0xc2, 0x28, 0x56, 0x5a, 0xcd, 0x1d, 0x45, 0xef, 0xb1, 0x46, 0xd1, 0x7b, 0xac,
+	0x51, 0xf4, 0xec, 0x51, 0x64, 0xaf, 0xd1, 0x13, 0x96, 0x55, 0xb5, 0x29, 0xce, 0x96, 0x1b, 0x9c,
+	0x3b, 0xb4, 0xec, 0x15, 0xba, 0x64, 0xb8, 0xe9, 0x75, 0xc2, 0x19, 0x7b, 0x6c, 0x11, 0x83, 0x5f,
+	0xda, 0x94, 0x90, 0x5c, 0x76, 0xe6, 0x7b, 0xc9, 0x7e, 0x3c, 0x9e, 0xc9, 0x01, 0x58, 0x74, 0x92,
+	0x8b, 0x8d, 0x93, 0xc9, 0xc5, 0xa1, 0xce, 0x0f, 0x71, 0xbb, 0x38, 0xc4, 0x3d, 0xba, 0x74, 0x23,
+	0x4a, 0x33, 0xd3, 0x74, 0xd0, 0x34, 0x36, 0x08, 0xb2, 0xe5, 0x17, 0xc3, 0x78, 0x92, 0x91, 0x50,
+	0x24, 0x71, 0x60, 0x60, 0x67, 0x93, 0x81, 0x33, 0xca, 0x25, 0x69, 0xe7, 0x22, 0x06, 0xec, 0x61,
+	0xa0, 0x89, 0x7f, 0xc2, 0xb1, 0x87, 0xc1, 0x48, 0x7b, 0x58, 0x94, 0xc1, 0x4f, 0x08, 0x5d, 0x76,
+	0xed, 0x55, 0x08, 0xf4, 0xe7, 0x69, 0x67, 0x27, 0x0d, 0xe3, 0x14, 0x83, 0xb1, 0x74, 0x08, 0x03,
+	0x80, 0xc0, 0xbe, 0x35, 0x1d, 0x21, 0x4e, 0xba, 0x81, 0x6e, 0xc2, 0x77, 0xca, 0x28, 0x57, 0x53,
+	0x15, 0xdb, 0x0d, 0x00, 0xf2, 0x2f, 0xf2, 0xd5, 0xe3, 0xbe, 0x6a, 0x0f, 0x9e, 0xcc, 0xbf, 0x12,
+	0x0f, 0x16, 0xdd, 0x8d, 0xe7, 0xd3, 0xfd, 0x50, 0xf6, 0xb4, 0x80, 0x21, 0xc3, 0x06, 0x05, 0xbf,
+	0x47, 0x68, 0x27, 0xfb, 0xae, 0x20, 0xff, 0x05, 0xda, 0xc6, 0x4c, 0x39, 0xe8, 0xcb, 0x20, 0xd0,
+	0xbd, 0xe6, 0xf9, 0x84, 0x67, 0x30, 0x98, 0x47, 0xdb, 0x63, 0xe9, 0xc4, 0x1d, 0x0e, 0x7f, 0x11,
+	0x12, 0x1e, 0xf9, 0x4d, 0x05, 0x09, 0x8f, 0x70, 0xcd, 0x3c, 0x16, 0x90, 0xd5, 0xe4, 0x9a, 0x79,
+	0x2c, 0x30, 0xa5, 0xe9, 0x25, 0x91, 0x4c, 0x51, 0xba, 0x19, 0x70, 0x7a, 0xc2, 0x8e, 0x2f, 0x30,
+	0x0b, 0x74, 0x1b, 0xd3, 0x6d, 0xc7, 0xc4, 0x57, 0xec, 0xf9, 0xe1, 0x4c, 0x4e, 0xd9, 0x0e, 0xc7,
+	0xff, 0x00, 0xdb, 0xb9, 0x87, 0x4b, 0x6e, 0x58, 0x47, 0xe1, 0xff, 0xe0, 0xd7, 0xe9, 0x6a, 0xde,
+	0x39, 0x4b, 0x67, 0x2f, 0xa3, 0xcd, 0xed, 0x68, 0x24, 0x07, 0xaa, 0xc3, 0xf1, 0x3f, 0x78, 0x5c,
+	0x5f, 0x24, 0xe9, 0x78, 0xaa, 0x82, 0x4e, 0x03, 0x65, 0x70, 0x60, 0xc1, 0xf3, 0x94, 0xa2, 0x4c,
+	0xf5, 0x8b, 0x93, 0x0f, 0x08, 0x6d, 0xeb, 0x65, 0x78, 0x15, 0xfb, 0x1b, 0x61, 0x72, 0x3f, 0x5b,
+	0x15, 0x84, 0xc9, 0x7d, 0x08, 0x4b, 0x57, 0x47, 0x13, 0x65, 0xe2, 0x36, 0x97, 0x0d, 0x60, 0xc1,
+	0xdf, 0x81, 0xbe, 0x54, 0xa0, 0x54, 0x2d, 0xf6, 0x29, 0x4a, 0x87, 0xf1, 0xf8, 0xc1, 0xf8, 0x50,
+	0xdc, 0x13, 0xf9, 0xf8, 0x08, 0x04, 0x19, 0x92, 0x5b, 0x74, 0xc0, 0x43, 0xae, 0xfa, 0x16, 0x50,
+	0x37, 0xd9, 0x08, 0x06, 0xb4, 0xeb, 0x7c, 0x82, 0xb1, 0x4d, 0xa5, 0x71, 0x25, 0x76, 0xd6, 0x06,
+	0x7f, 0xcd, 0x08, 0x51, 0xfe, 0x16, 0x37, 0x80, 0x60, 0x97, 0xb6, 0xf5, 0x12, 0xb2, 0x54, 0x71,
+	0x57, 0x6c, 0xcf, 0x11, 0x1b, 0x3e, 0x2c, 0x15, 0x3b, 0xf8, 0x01, 0xa1, 0x5d, 0x07, 0x5b, 0x2b,
+	0xe1, 0x3a, 0x5d, 0x29, 0xae, 0x2c, 0xc0, 0x6d, 0xf2, 0x60, 0x8c, 0x53, 0x26, 0x5e, 0xaa, 0x25,
+	0x97, 0x0d, 0x42, 0x83, 0x89, 0x7b, 0xe2, 0x48, 0x59, 0x5f, 0x36, 0x5c, 0x1b, 0xb4, 0xf2, 0x36,
+	0xf8, 0x0f, 0x42, 0x3b, 0xd9, 0xfa, 0xb8, 0x6a, 0xf8, 0x71, 0x48, 0xd5, 0xf0, 0xc3, 0xff, 0xcc,
+	0x25, 0x1a, 0x96, 0x4b, 0x7c, 0x02, 0xd2, 0x6c, 0x34, 0xcb, 0x36, 0x8f, 0xe5, 0x03, 0xac, 0x68,
+	0x70, 0x1f, 0x1a, 0x0b, 0x35, 0xff, 0x5b, 0x32, 0x92, 0x64, 0x00, 0xc0, 0x6e, 0x1d, 0xcd, 0xc6,
+	0xb1, 0x48, 0xb2, 0xe8, 0x60, 0x00, 0x60, 0xcf, 0x5b, 0x61, 0x92, 0xbe, 0x95, 0x88, 0x91, 0xbf,
+	0x88, 0xc8, 0xac, 0x1d, 0x7c, 0x8d, 0xd0, 0xae, 0x93, 0x72, 0x61, 0xe6, 0xf3, 0xf1, 0x08, 0x55,
+	0xea, 0x72, 0xf8, 0x0b, 0x90, 0x3b, 0xe3, 0x91, 0x5a, 0x5b, 0xc3, 0x5f, 0xe0, 0x87, 0x1f, 0xa1,
+	0xf2, 0x72, 0x2a, 0x19, 0x00, 0xfb, 0x45, 0x4a, 0xb1, 0x71, 0x6b, 0x9c, 0xa4, 0x5a, 0xbb, 0x55,
+	0x3b, 0x10, 0x03, 0x82, 0x5b, 0x34, 0xc1, 0x25, 0xda, 0xc9, 0x5a, 0xb8, 0x11, 0x87, 0x3f, 0x2a,
+	0x4e, 0xc8, 0x46, 0xf0, 0xc1, 0x12, 0x5d, 0xdc, 0x8c, 0x26, 0x93, 0x70, 0x3a, 0x62, 0x2f, 0xd2,
+	0x66, 0x0a, 0x01, 0x03, 0x64, 0x5c, 0xce, 0xd6, 0x33, 0x0a, 0x7b, 0x19, 0xe2, 0x07, 0x47, 0x82,
+	0xe0, 0x67, 0x54, 0x86, 0x16, 0xf6, 0x0c, 0x3d, 0x23, 0xad, 0xa5, 0x1d, 0x49, 0x11, 0xaf, 0x36,
+	0xd8, 0x39, 0x7a, 0xaa, 0x1f, 0x47, 0xb3, 0x3c, 0xa2, 0xc9, 0x7a, 0xf4, 0xbc, 0xfc, 0x26, 0xe7,
+	0x59, 0x9a, 0xa2, 0xc5, 0x2e, 0xd0, 0x35, 0xf8, 0xb4, 0x02, 0xbf, 0xc0, 0x9e, 0xa7, 0xbd, 0x1d,
+	0x91, 0x96, 0x2f, 0x79, 0x35, 0xd5, 0x22, 0xf0, 0x79, 0x6b, 0x36, 0xaa, 0xe6, 0xd3, 0x66, 0xcf,
+	0xd2, 0x73, 0x52, 0x12, 0x93, 0xa6, 0x34, 0xb2, 0x03, 0x48, 0x99, 0x52, 0x8a, 0x48, 0xca, 0xce,
+	0xd0, 0x93, 0xf2, 0x4b, 0xf0, 0x2a, 0x0d, 0xee, 0xb2, 0x53, 0x74, 0x05, 0x04, 0xb7, 0x81, 0xcb,
+	0x40, 0x2b, 0xe5, 0xb0, 0xc1, 0x2b, 0x60, 0x9f, 0x1d, 0x91, 0x66, 0x1e, 0xa9, 0x11, 0xab, 0x8c,
+	0xd1, 0x65, 0xd0, 0x2e, 0x4c, 0x43, 0x0d, 0x3b, 0xc9, 0xce, 0x53, 0x7f, 0x47, 0xa4, 0x18, 0xdd,
+	0x0a, 0x5f, 0x30, 0xf6, 0x1c, 0x7d, 0x46, 0xe9, 0x61, 0x85, 0x71, 0x8d, 0x3e, 0x83, 0x9a, 0xc4,
+	0xd1, 0xac, 0x0c, 0x79, 0xd6, 0x8c, 0xa0, 0x2e, 0x1b, 0x68, 0x94, 0xef, 0x0e, 0xae, 0x8d, 0x7a,
+	0x06, 0x50, 0x52, 0xa7, 0x3c, 0x6a, 0x0d, 0x50, 0xd2, 0x6e, 0xf9, 0x0e, 0x9f, 0x35, 0xa8, 0xfc,
+	0x57, 0xe7, 0xd9, 0x59, 0xca, 0x76, 0x44, 0x9a, 0xff, 0xe4, 0x39, 0x76, 0x9a, 0xae, 0xa2, 0xec,
+	0x30, 0x06, 0x1a, 0x7a, 0x01, 0x14, 0xc6, 0x85, 0x91, 0xf2, 0x2d, 0xd9, 0xa9, 0x46, 0x5f, 0x04,
+	0x85, 0xa5, 0x74, 0x26, 0xed, 0x68, 0xe4, 0xc7, 0xc0, 0x79, 0xe0, 0xdb, 0x9c, 0x53, 0xb8, 0x5d,
+	0xbc, 0x08, 0x06, 0xd7, 0x66, 0xc9, 0x02, 0x9b, 0xc6, 0xbe, 0x04, 0x52, 0x5d, 0x3d, 0x4c, 0x45,
+	0xac, 0x53, 0xed, 0xe6, 0x64, 0xb4, 0xba, 0x01, 0x03, 0xcd, 0x25, 0xcb, 0xf1, 0xf4, 0x9e, 0x26,
+	0xfe, 0x14, 0x0c, 0xb4, 0x92, 0x06, 0x57, 0xd8, 0x1a, 0xf1, 0x69, 0x40, 0x70, 0x31, 0x8b, 0xe2,
+	0x14, 0xbf, 0x49, 0x34, 0xe2, 0x65, 0x30, 0xc6, 0x30, 0x9e, 0x4f, 0x85, 0x5c, 0x48, 0x69, 0xf8,
+	0x67, 0xc0, 0xa3, 0x41, 0x74, 0x4b, 0x24, 0x57, 0xec, 0xd7, 0xd8, 0x1a, 0x3d, 0x0b, 0xe6, 0x2a,
+	0x11, 0xfa, 0x97, 0x40, 0x68, 0x58, 0x3c, 0xf1, 0x70, 0x6a, 0x7c, 0xe7, 0xb3, 0xcc, 0xa7, 0xa7,
+	0x91, 0xbd, 0x5e, 0xef, 0x69, 0xcc, 0xeb, 0x66, 0x02, 0x98, 0x45, 0x9d, 0x46, 0x7e, 0x0e, 0xa6,
+	0xa8, 0x65, 0x62, 0x08, 0xda, 0xb0, 0x70, 0xd1, 0xf8, 0xcf, 0x9b, 0x21, 0x80, 0xe1, 0x94, 0xdb,
+	0x6e, 0x8d, 0xfc, 0x02, 0xe8, 0x27, 0x8d, 0x8b, 0x75, 0x15, 0x0d, 0xbf, 0x0a, 0x70, 0xf9, 0x91,
+	0x03, 0xbf, 0x66, 0x2c, 0x28, 0x4b, 0x08, 0x1a, 0xb1, 0x09, 0x1f, 0x70, 0x31, 0x89, 0x1e, 0xb8,
+	0x1f, 0xf4, 0xd9, 0x45, 0xfa, 0xac, 0xbd, 0xcd, 0xce, 0xbb, 0xdb, 0x96, 0x99, 0xbf, 0x90, 0x21,
+	0x35, 0xf8, 0x0d, 0x3d, 0x7f, 0x6d, 0xe0, 0x75, 0x50, 0x65, 0x47, 0xa4, 0x4e, 0x2a, 0xd5, 0xc8,
+	0x1b, 0xca, 0x6f, 0x61, 0x66, 0xdb, 0x1f, 0x0d, 0x8c, 0x8a, 0x98, 0xd4, 0x34, 0xfc, 0x4d, 0xed,
+	0xcf, 0x0e, 0xf4, 0xa6, 0xb1, 0x26, 0xc2, 0x75, 0x12, 0xd1, 0xf8, 0x5b, 0x1f, 0x6f, 0xb7, 0x47,
+	0xab, 0x8f, 0x1e, 0x3d, 0x7a, 0xe4, 0x05, 0x8f, 0xbc, 0x8a, 0x88, 0x5b, 0x9a, 0x33, 0xfb, 0xe5,
+	0x59, 0xbd, 0x7e, 0xf3, 0x9f, 0xff, 0x04, 0x0a, 0x23, 0x7a, 0x67, 0x35, 0x9f, 0x60, 0xc2, 0xef,
+	0x72, 0x0b, 0xc2, 0x5e, 0xa0, 0x8d, 0x9d, 0x83, 0x31, 0x66, 0xfb, 0x8a, 0x5d, 0x30, 0xe0, 0x37,
+	0xde, 0xa0, 0x8b, 0xfb, 0x4a, 0xd6, 0x65, 0x37, 0xb5, 0xf8, 0xf7, 0xf0, 0xd3, 0xf3, 0x1a, 0x5a,
+	0xa6, 0x1f, 0xd7, 0x1f, 0x07, 0x51, 0x69, 0x62, 0x29, 0xd3, 0x7f, 0xa3, 0x5f, 0xcd, 0xf2, 0xbe,
+	0x63, 0x87, 0x92, 0x0e, 0x0d, 0xc3, 0x9f, 0x91, 0xfa, 0x8c, 0x55, 0xbb, 0xb0, 0x2a, 0x1d, 0x02,
+	0xef, 0x49, 0x87, 0x00, 0x37, 0x3c, 0x32, 0xdd, 0x0d, 0xd5, 0x5a, 0xd7, 0x00, 0x36, 0xb6, 0xab,
+	0xd5, 0x1c, 0xa3, 0x9a, 0x1f, 0x73, 0x2c, 0x5b, 0xae, 0x85, 0xd1, 0xf7, 0x5b, 0xa4, 0x2e, 0xff,
+	0xd6, 0x6a, 0xab, 0x07, 0xc1, 0xb3, 0x06, 0xe1, 0x66, 0xb5, 0x74, 0x6f, 0xa3, 0x74, 0x97, 0xac,
+	0x41, 0x38, 0x4e, 0xb6, 0xef, 0x92, 0xe3, 0x73, 0xff, 0x13, 0x4b, 0xf8, 0xcb, 0xd5, 0x12, 0x1e,
+	0xa0, 0x84, 0x2f, 0x6a, 0xa7, 0x3e, 0x86, 0xb3, 0x91, 0xf3, 0x6f, 0x1b, 0xf5, 0xab, 0x8f, 0x27,
+	0x95, 0x11, 0xb6, 0x84, 0xb7, 0xc5, 0x3b, 0x6a, 0x61, 0x88, 0x55, 0x4e, 0xd5, 0x74, 0x8a, 0x2a,
+	0xcd, 0x5c, 0x69, 0xcc, 0x2e, 0x92, 0xb4, 0xdc, 0x52, 0x57, 0x45, 0xc1, 0x65, 0xa1, 0xb2, 0x6c,
+	0x86, 0x05, 0x8a, 0x03, 0xa1, 0x0c, 0x80, 0x05, 0xd2, 0x36, 0xb7, 0x41, 0xc5, 0x02, 0x05, 0x39,
+	0xbe, 0x40, 0x41, 0x1e, 0xbb, 0x40, 0x41, 0xca, 0x0b, 0x14, 0x75, 0xde, 0x7f, 0xe8, 0x78, 0x7f,
+	0xdd, 0x78, 0x98, 0x91, 0xfb, 0x4f, 0x52, 0xb9, 0x2a, 0xac, 0x1d, 0xb4, 0xb3, 0x74, 0xc1, 0x29,
+	0xc9, 0x2e, 0x98, 0xa9, 0x0b, 0x69, 0x37, 0x49, 0xc3, 0xc9, 0x4c, 0xd5, 0x31, 0x0c, 0x00, 0xb0,
+	0xc8, 0x06, 0x4b, 0x00, 0x4d, 0x79, 0xd0, 0x94, 0x01, 0x36, 0x6e, 0x54, 0xab, 0x36, 0x41, 0xd5,
+	0x2e, 0x38, 0x13, 0xbb, 0x20, 0xb0, 0xd1, 0xea, 0x1f, 0x48, 0xe5, 0x72, 0xf6, 0xa9, 0xb4, 0x0a,
+	0xe8, 0x09, 0xd3, 0x51, 0x76, 0x84, 0xe7, 0xc0, 0xea, 0xa4, 0x9f, 0x3a, 0xd2, 0x57, 0x08, 0x66,
+	0xa4, 0xff, 0x21, 0x29, 0x59, 0x6f, 0x7f, 0x38, 0x45, 0x82, 0x8d, 0x6b, 0xd5, 0x52, 0xff, 0x26,
+	0x4a, 0xed, 0x3b, 0x36, 0xb7, 0x04, 0x32, 0xf2, 0xde, 0x2b, 0xec, 0x03, 0x4a, 0xd3, 0xd3, 0x17,
+	0xaa, 0x59, 0xc5, 0x3d, 0x62, 0x55, 0x2c, 0x73, 0x9d, 0x19, 0x46, 0x5f, 0x29, 0xd9, 0x5b, 0x3c,
+	0xae, 0x5d, 0xea, 0x34, 0x4d, 0x1c, 0x4d, 0x0b, 0x2c, 0x8c, 0x00, 0x7f, 0x43, 0x4a, 0xb7, 0x31,
+	0xe0, 0x53, 0x40, 0x3f, 0x35, 0x72, 0x64, 0x6d, 0xc7, 0xdf, 0xbc, 0xba, 0x4a, 0x49, 0x23, 0x57,
+	0x25, 0xa8, 0xcb, 0xe7, 0xa9, 0x93, 0xcf, 0x4b, 0x44, 0x32, 0x32, 0xc7, 0xf9, 0x0d, 0x16, 0xbb,
+	0x28, 0xcf, 0xaf, 0xd5, 0x81, 0xce, 0x92, 0x75, 0x04, 0xca, 0x11, 0xb1, 0xf1, 0xf9, 0x6a, 0xc6,
+	0xf3, 0x1e, 0xb1, 0x0a, 0xa2, 0x6e, 0xc7, 0x86, 0xe7, 0x37, 0x49, 0xf5, 0x0e, 0xae, 0xd6, 0x58,
+	0x99, 0xf3, 0x7a, 0x96, 0xf3, 0x6e, 0x0c, 0xaa, 0xe5, 0x79, 0x80, 0xf2, 0x5c, 0x34, 0xf2, 0x94,
+	0xf2, 0x34, 0x92, 0xfd, 0x1f, 0xa9, 0xd9, 0x3d, 0x56, 0x56, 0xf1, 0xab, 0xc6, 0xaf, 0xa4, 0x8e,
+	0x24, 0x8b, 0x33, 0x79, 0x70, 0x56, 0x4d, 0x6c, 0xd6, 0x54, 0x13, 0x5b, 0xc5, 0x6a, 0xe2, 0xc6,
+	0x9b, 0xd5, 0xaa, 0x3f, 0x44, 0xd5, 0x7b, 0x6e, 0x4c, 0x2c, 0x2a, 0x65, 0x74, 0xff, 0x27, 0x52,
+	0xb9, 0x35, 0xfe, 0xf0, 0x34, 0xaf, 0x8b, 0x8b, 0xef, 0xba, 0x71, 0xb1, 0x5c, 0x34, 0x23, 0xff,
+	0xbf, 0x92, 0x8a, 0xdd, 0x3b, 0x48, 0x7a, 0x63, 0x77, 0x77, 0x88, 0x47, 0x99, 0xca, 0xa5, 0x74,
+	0xdb, 0x3e, 0x4a, 0x95, 0xc6, 0xcf, 0x1d, 0xa5, 0x22, 0x46, 0xaa, 0xa7, 0x9b, 0x60, 0x0d, 0x0e,
+	0x02, 0xca, 0x38, 0x8f, 0xff, 0xeb, 0x16, 0xf4, 0x5f, 0x2e, 0x59, 0xd0, 0xe7, 0x44, 0x34, 0x5a,
+	0x7c, 0x9d, 0x54, 0x14, 0x1a, 0x8e, 0xd3, 0xa2, 0x5c, 0xd6, 0x3a, 0xb9, 0x7e, 0xab, 0x62, 0xa3,
+	0x51, 0x2a, 0xd7, 0x17, 0x69, 0x57, 0xe3, 0x70, 0x7f, 0x99, 0x9d, 0x4b, 0x83, 0x28, 0x27, 0xd4,
+	0xb9, 0x34, 0x94, 0x08, 0x01, 0xa9, 0x2a, 0xed, 0x98, 0xde, 0x33, 0x80, 0x39, 0x69, 0x6e, 0x58,
+	0x27, 0xcd, 0x41, 0x54, 0x51, 0x22, 0xc9, 0x9f, 0x2f, 0xd4, 0x69, 0xf2, 0x15, 0x47, 0x93, 0xd2,
+	0xee, 0x8c, 0x26, 0xb3, 0x8a, 0xc2, 0x4b, 0x81, 0xe1, 0xf5, 0x6a, 0x86, 0x8f, 0x48, 0x09, 0xc7,
+	0x4a, 0xdb, 0xbd, 0x01, 0x0b, 0xcf, 0x64, 0x16, 0x4d, 0x13, 0x01, 0x4c, 0xee, 0xdc, 0x44, 0x26,
+	0x6d, 0xee, 0xdd, 0xb9, 0x09, 0x46, 0xd9, 0x8a, 0xe3, 0x28, 0x56, 0x15, 0x66, 0xd9, 0x30, 0xf7,
+	0x84, 0xe4, 0xd1, 0x84, 0x6c, 0x04, 0xff, 0x4c, 0xca, 0x0a, 0x43, 0x1f, 0x89, 0x7b, 0xd7, 0x24,
+	0x9b, 0xaf, 0x4a, 0x5b, 0x3c, 0x63, 0x82, 0x6c, 0xa5, 0xe9, 0xef, 0x16, 0x0b, 0x58, 0x05, 0xab,
+	0xd7, 0x24, 0xe2, 0xf7, 0x24, 0xa7, 0x73, 0x76, 0x44, 0xb0, 0xba, 0x32, 0x7c, 0xbe, 0x5c, 0x53,
+	0x12, 0x2b, 0x5d, 0x7c, 0xd4, 0x6c, 0xcb, 0xbe, 0x46, 0x9c, 0x40, 0x5a, 0xd9, 0xaf, 0xe1, 0xfe,
+	0x6f, 0xa4, 0xb2, 0xe4, 0x06, 0x56, 0x47, 0xe0, 0x40, 0x16, 0xbf, 0x1b, 0x5c, 0x37, 0x01, 0x83,
+	0x94, 0x83, 0x91, 0x9a, 0x39, 0xba, 0x09, 0x8b, 0xb3, 0xfe, 0x9e, 0xda, 0xec, 0xe0, 0xb2, 0x53,
+	0xb6, 0x00, 0xce, 0x67, 0x08, 0x97, 0x43, 0xab, 0x5a, 0x75, 0xf9, 0xf0, 0x77, 0x88, 0x13, 0x53,
+	0x2b, 0xa4, 0x34, 0xaa, 0x7c, 0x8f, 0x1c, 0x5f, 0x20, 0x7c, 0xe2, 0x1d, 0x26, 0xaf, 0x96, 0xef,
+	0x77, 0x89, 0xb3, 0xc5, 0x3c, 0x8e, 0xb5, 0x11, 0xf4, 0xe7, 0xa4, 0xba, 0x46, 0x89, 0x06, 0xbc,
+	0x66, 0x8d, 0xb9, 0x6a, 0x59, 0x06, 0xf4, 0x6c, 0x03, 0x66, 0x42, 0x37, 0xac, 0x6c, 0xf7, 0x78,
+	0x75, 0x1d, 0xf6, 0x3c, 0xf5, 0x06, 0x1c, 0x77, 0x97, 0x55, 0xb7, 0x0d, 0xbc, 0x01, 0xaf, 0x4b,
+	0xdb, 0x5f, 0x27, 0xce, 0x92, 0xa5, 0x4a, 0x27, 0xa3, 0xf9, 0xbf, 0x90, 0x62, 0xfd, 0xf5, 0x23,
+	0xd4, 0xb8, 0x6e, 0xbe, 0x7e, 0xc3, 0x9d, 0xaf, 0x79, 0x29, 0x8d, 0x0e, 0xff, 0x9e, 0xcd, 0x18,
+	0xb8, 0x0f, 0xe5, 0x54, 0x48, 0x41, 0xe4, 0xdd, 0x30, 0x39, 0x30, 0x47, 0xa4, 0xb2, 0x95, 0x1d,
+	0x9d, 0x8e, 0xd4, 0x5d, 0x4a, 0xd5, 0x82, 0x78, 0xd2, 0xbf, 0xa6, 0x14, 0xf1, 0xfa, 0xd7, 0xa0,
+	0x3d, 0xdc, 0x55, 0x97, 0x22, 0xbc, 0xe1, 0xae, 0x09, 0xb8, 0x2d, 0x2b, 0xe0, 0xd6, 0xcd, 0x99,
+	0x6f, 0x96, 0xcd, 0x99, 0x82, 0x9c, 0x46, 0x99, 0xff, 0x25, 0x25, 0xa5, 0xef, 0xe3, 0xf6, 0x95,
+	0xa5, 0xa3, 0xf2, 0x18, 0xfb, 0x4a, 0xdc, 0x33, 0xcf, 0x0e, 0xc7, 0xf2, 0xd6, 0x80, 0x3a, 0xfd,
+	0xcf, 0x00, 0x50, 0x84, 0x40, 0xea, 0x6b, 0xd1, 0x7c, 0x3a, 0xd2, 0x4b, 0x48, 0x1b, 0xb4, 0xb1,
+	0x59, 0xad, 0xf8, 0x1f, 0x12, 0x67, 0xe3, 0x53, 0xd0, 0xc9, 0xa8, 0xfc, 0x3f, 0xa4, 0xb4, 0xac,
+	0xff, 0x54, 0x4a, 0x17, 0x8e, 0x54, 0xbd, 0xfc, 0x91, 0xea, 0xab, 0xb4, 0xfb, 0xc6, 0x58, 0x1c,
+	0x8e, 0x76, 0x23, 0x39, 0x3b, 0xd4, 0xe9, 0x1f, 0x53, 0x72, 0x22, 0x4e, 0xca, 0xc1, 0x5d, 0xc2,
+	0x8d, 0xad, 0x6a, 0x65, 0xbf, 0x45, 0x9c, 0x3d, 0x53, 0x89, 0x36, 0x46, 0xdd, 0x01, 0x5d, 0xb2,
+	0x98, 0xc0, 0x10, 0x60, 0xd3, 0x9a, 0x6f, 0x06, 0x90, 0x61, 0xb3, 0x35, 0x51, 0x8b, 0x1b, 0x40,
+	0xf0, 0x8a, 0x3a, 0x94, 0x2c, 0xbd, 0x51, 0xb1, 0x96, 0xbf, 0x51, 0x61, 0x6e, 0x53, 0x04, 0xef,
+	0x13, 0xba, 0xec, 0x5e, 0x38, 0xf9, 0x88, 0x2e, 0x94, 0x7c, 0x5c, 0x5d, 0xc7, 0x10, 0xf9, 0x1b,
+	0x25, 0x99, 0x1e, 0x5c, 0x13, 0x04, 0x5f, 0x25, 0xca, 0xff, 0xd4, 0x5d, 0xc3, 0x2c, 0xfb, 0x69,
+	0x31, 0x75, 0x33, 0x2b, 0xfd, 0xec, 0x8c, 0xdf, 0x15, 0x6a, 0x42, 0x1b, 0x00, 0xba, 0xb1, 0x88,
+	0xc7, 0x22, 0xd9, 0x8c, 0xe6, 0xca, 0x27, 0x5a, 0xdc, 0x06, 0x41, 0xcf, 0xdb, 0xe1, 0x91, 0x35,
+	0x09, 0x74, 0x33, 0xf8, 0x35, 0xda, 0xe5, 0x33, 0x5b, 0x08, 0xe3, 0x78, 0xc4, 0x71, 0xbc, 0x0d,
+	0x4a, 0x33, 0xb2, 0x44, 0xd5, 0xa5, 0x99, 0x1d, 0xf6, 0xe4, 0xf7, 0xdc, 0xa2, 0x0a, 0xbe, 0x44,
+	0x29, 0x5c, 0xf4, 0x54, 0x3d, 0xcb, 0xd0, 0x43, 0xb2, 0xd0, 0x23, 0xaf, 0x86, 0xf6, 0xd5, 0xa1,
+	0x36, 0xfe, 0x67, 0x97, 0xe9, 0x22, 0x9f, 0x49, 0x16, 0x0d, 0xf7, 0xf2, 0x82, 0x2d, 0x24, 0xd7,
+	0x44, 0xc1, 0x1f, 0x10, 0x7a, 0xce, 0x3e, 0x18, 0xbb, 0x15, 0x85, 0xd9, 0xd2, 0x49, 0x5e, 0x33,
+	0xdd, 0x05, 0x42, 0x75, 0xbd, 0xf4, 0xa4, 0x75, 0x27, 0x56, 0xf5, 0x94, 0x91, 0xd4, 0xc5, 0xb8,
+	0x6f, 0xbb, 0x31, 0xae, 0x82, 0xa1, 0x99, 0x01, 0xef, 0x96, 0x1d, 0xca, 0xc1, 0xd9, 0x88, 0x89,
+	0x4d, 0x6a, 0x8d, 0x6b, 0x41, 0xea, 0x16, 0x91, 0x7f, 0xe4, 0x2e, 0x22, 0x8b, 0x9d, 0x1b, 0xde,
+	0x3f, 0x25, 0xf5, 0x27, 0x7f, 0x4f, 0x55, 0xc2, 0x3b, 0x36, 0xea, 0x6c, 0xdc, 0xae, 0x16, 0xfe,
+	0x8f, 0x89, 0x53, 0x5a, 0xad, 0x13, 0xce, 0xa8, 0xf1, 0x77, 0xa4, 0xea, 0x78, 0xf2, 0x43, 0x52,
+	0xa0, 0x66, 0xa7, 0xfd, 0x27, 0x52, 0x81, 0xe7, 0xac, 0x85, 0x75, 0xdd, 0x92, 0xe3, 0xfb, 0x84,
+	0x76, 0xd5, 0x51, 0x66, 0x2c, 0x6f, 0x85, 0x9e, 0x97, 0x57, 0xf7, 0xe5, 0x9e, 0x45, 0x4e, 0x6d,
+	0x03, 0xb0, 0x6e, 0x39, 0xd9, 0xa9, 0xba, 0x0f, 0xa9, 0x18, 0xae, 0x4b, 0xcb, 0x99, 0xd0, 0xe5,
+	0xb2, 0xc1, 0x5e, 0xa6, 0x1d, 0x5d, 0xce, 0xd6, 0x17, 0x3b, 0x7c, 0x7b, 0x1a, 0x6a, 0xa4, 0x7a,
+	0xcd, 0xa0, 0x49, 0xcd, 0xf6, 0xb2, 0x65, 0x6f, 0x2f, 0xbf, 0x43, 0x8a, 0x27, 0xbd, 0x4f, 0x65,
+	0x60, 0x2b, 0x76, 0x35, 0x9c, 0xd8, 0x55, 0xb7, 0x02, 0xfa, 0x53, 0x77, 0x05, 0x94, 0x17, 0xc4,
+	0x98, 0xf4, 0xb7, 0x49, 0xf9, 0xd1, 0xb3, 0xd9, 0x09, 0x12, 0xfb, 0xc5, 0xc8, 0x2a, 0x6d, 0x0c,
+	0x53, 0x9d, 0x14, 0xe0, 0x6f, 0xdd, 0xee, 0xf8, 0xcf, 0xa4, 0x10, 0xcf, 0x96, 0x19, 0xb1, 0x64,
+	0x77, 0xcc, 0x34, 0xae, 0x2f, 0x64, 0xb1, 0x25, 0x8a, 0xc1, 0x60, 0x50, 0x83, 0xdf, 0xd5, 0x17,
+	0x62, 0x9a, 0x3c, 0x6b, 0xc3, 0x2a, 0x05, 0xfe, 0xe7, 0xee, 0xb2, 0x3a, 0x30, 0xe7, 0x58, 0xa6,
+	0xe1, 0xde, 0x75, 0x0d, 0xfe, 0x9e, 0xd0, 0x15, 0xb5, 0x09, 0x82, 0x85, 0xfe, 0x5d, 0x75, 0xe7,
+	0xaf, 0x22, 0x51, 0xe4, 0xd7, 0x44, 0x5e, 0xc9, 0x9a, 0x48, 0x6f, 0xa5, 0xfa, 0x7b, 0x6a, 0x1e,
+	0xe8, 0x66, 0x86, 0x19, 0xa6, 0x6a, 0x45, 0xa8, 0x9b, 0xd6, 0xb0, 0xb7, 0xf2, 0x27, 0x16, 0xf2,
+	0x08, 0x02, 0x54, 0x5f, 0x40, 0x94, 0x01, 0x04, 0xd7, 0x69, 0x37, 0x1b, 0x53, 0x3d, 0x11, 0x4c,
+	0xce, 0x25, 0x35, 0x39, 0xd7, 0x73, 0x72, 0x2e, 0x5c, 0x91, 0x5a, 0xc1, 0xa1, 0xb5, 0x8c, 0x6e,
+	0x5d, 0x7c, 0x24, 0xce, 0xc5, 0x47, 0x30, 0x82, 0xf3, 0x9e, 0x44, 0x19, 0xc1, 0x86, 0xb1, 0x0d,
+	0xda, 0xc9, 0x44, 0x43, 0x33, 0x98, 0x54, 0xe3, 0x88, 0xcc, 0x0d, 0x59, 0xf0, 0x88, 0xd0, 0x93,
+	0x85, 0x39, 0xc6, 0x3e, 0x41, 0x5b, 0x38, 0x34, 0x3e, 0x71, 0xea, 0xf0, 0xb9, 0x31, 0xe3, 0x92,
+	0x88, 0xbd, 0x4e, 0x4f, 0xd8, 0x5f, 0xab, 0x44, 0xaa, 0x03, 0x7b, 0xd1, 0xb7, 0xb8, 0x43, 0x1e,
+	0xfc, 0x37, 0x51, 0x27, 0x71, 0xae, 0x5d, 0x1d, 0x6d, 0xc8, 0x63, 0x69, 0xc3, 0x5e, 0xa6, 0x54,
+	0x2e, 0x97, 0xb2, 0x17, 0x57, 0x46, 0xf8, 0x9c, 0xad, 0xb9, 0x45, 0xc9, 0x3e, 0x47, 0xbb, 0x8e,
+	0x11, 0x94, 0xf5, 0xaa, 0x83, 0x90, 0x4b, 0xee, 0xba, 0x4c, 0x13, 0x77, 0x19, 0x96, 0xcb, 0x4c,
+	0xe8, 0x19, 0x87, 0x3c, 0xab, 0x0c, 0xd5, 0xc7, 0x50, 0x27, 0x2a, 0x7a, 0x8f, 0x1d, 0x15, 0x83,
+	0x7f, 0x24, 0x95, 0x37, 0x57, 0x9e, 0xf6, 0xac, 0xcb, 0x71, 0xbd, 0x46, 0xd1, 0xf5, 0xea, 0x16,
+	0x1a, 0xef, 0x93, 0x92, 0xc3, 0xae, 0x82, 0x64, 0x4e, 0x2d, 0xa5, 0xe6, 0x6e, 0x4d, 0x4d, 0x9c,
+	0xd0, 0x37, 0x89, 0x3d, 0xeb, 0x26, 0xf1, 0x93, 0x16, 0x52, 0x6e, 0x55, 0xeb, 0xf1, 0xe7, 0xc4,
+	0x39, 0xad, 0xaf, 0x16, 0xd1, 0x39, 0x07, 0xdb, 0xc4, 0xfd, 0x53, 0x78, 0x38, 0x4e, 0x1f, 0x3e,
+	0xb5, 0x57, 0xf7, 0xe8, 0x92, 0xd5, 0x8d, 0xd2, 0xcf, 0x06, 0x05, 0x6f, 0xd3, 0x35, 0x3b, 0x7b,
+	0xe7, 0x78, 0x96, 0x95, 0xf2, 0x5f, 0xcd, 0xf7, 0x69, 0xbf, 0x10, 0xc8, 0x75, 0xe0, 0xf2, 0xfa,
+	0x12, 0x3d, 0x65, 0x35, 0x33, 0x5f, 0x7e, 0x05, 0xb2, 0xd6, 0xdd, 0x28, 0x51, 0xcb, 0xd2, 0x4b,
+	0xc5, 0xc7, 0x06, 0xf9, 0x5e, 0x25, 0x3d, 0x24, 0xb6, 0xad, 0x58, 0x17, 0x43, 0xe1, 0x6f, 0xf0,
+	0x93, 0xac, 0x36, 0x50, 0xb8, 0x3d, 0x55, 0xd8, 0xf1, 0xb8, 0x6f, 0xb4, 0x5a, 0xce, 0x1b, 0xa7,
+	0xd4, 0xae, 0x3c, 0xa7, 0xc5, 0x37, 0x4e, 0xcd, 0xfc, 0x1b, 0xa7, 0x3a, 0x37, 0xfe, 0x4e, 0x59,
+	0x4d, 0xa0, 0x20, 0x9f, 0x73, 0xe2, 0x8c, 0x4f, 0xbd, 0x70, 0x8b, 0xb0, 0x97, 0x6d, 0x11, 0xf6,
+	0xd8, 0x73, 0xd4, 0x1b, 0xa6, 0x2a, 0x36, 0xe5, 0xde, 0x86, 0x79, 0xc3, 0x14, 0x1e, 0x24, 0xaa,
+	0xdb, 0xfb, 0x0d, 0xf7, 0x41, 0xe2, 0xde, 0x30, 0x95, 0xf3, 0x3e, 0xd1, 0x6f, 0x63, 0xb0, 0xb1,
+	0xb6, 0x43, 0x97, 0x2c, 0xb0, 0xfd, 0x76, 0xa5, 0x29, 0xdf, 0xae, 0x5c, 0x76, 0x1f, 0xd1, 0x55,
+	0xc7, 0x10, 0xeb, 0x55, 0xcb, 0x7f, 0x11, 0xba, 0x9a, 0x7f, 0x0e, 0x08, 0x53, 0x4f, 0x60, 0x63,
+	0xa4, 0x9e, 0xc6, 0xe8, 0x26, 0x04, 0x32, 0x61, 0x9d, 0x02, 0xc0, 0x13, 0x19, 0x03, 0x00, 0xff,
+	0x8b, 0x66, 0xf8, 0x5e, 0x0e, 0x64, 0xc2, 0xff, 0xec, 0x39, 0xda, 0x98, 0xa5, 0xba, 0xd4, 0xb4,
+	0x64, 0xe9, 0xc8, 0x01, 0x0e, 0x1d, 0xee, 0xcf, 0xe3, 0x18, 0x6c, 0x2b, 0xb0, 0x6c, 0xd3, 0xe2,
+	0x06, 0x00, 0x51, 0x6c, 0x16, 0x0b, 0x89, 0x5c, 0x40, 0x64, 0xd6, 0x06, 0xfd, 0x93, 0x78, 0x1f,
+	0x2f, 0x24, 0x37, 0x39, 0xfc, 0x05, 0xf6, 0x23, 0x91, 0xa4, 0xf8, 0xa4, 0xa4, 0xc9, 0xf1, 0x3f,
+	0xbc, 0xbd, 0x2a, 0xb9, 0x83, 0xc7, 0x3e, 0xad, 0xf4, 0xc0, 0x34, 0x26, 0x67, 0x67, 0xe5, 0xe3,
+	0x48, 0x43, 0x59, 0xb7, 0xcb, 0xf9, 0xae, 0xbb, 0xcb, 0x29, 0xf2, 0x34, 0x1e, 0x03, 0x32, 0x15,
+	0xef, 0xff, 0x7d, 0x08, 0x32, 0x7d, 0xcf, 0x95, 0xa9, 0xc8, 0xd3, 0x29, 0x35, 0x96, 0xdd, 0x3d,
+	0x7c, 0x52, 0xa7, 0x3e, 0x4f, 0x3b, 0x98, 0x6d, 0xf1, 0xc5, 0xac, 0x74, 0x03, 0x03, 0x70, 0xde,
+	0x29, 0x12, 0xf3, 0xce, 0xb2, 0xae, 0x76, 0xf3, 0x17, 0x65, 0xb5, 0x1b, 0x47, 0x44, 0xa3, 0x43,
+	0x5a, 0x76, 0x4b, 0xd2, 0x75, 0x66, 0xcf, 0x72, 0xe6, 0x3a, 0xcb, 0xfd, 0xa5, 0x6b, 0xb9, 0x62,
+	0xb7, 0xf6, 0x81, 0x44, 0xdd, 0x1d, 0xcc, 0xc2, 0x19, 0x48, 0xcd, 0x2d, 0x9e, 0xef, 0x4b, 0xa6,
+	0x41, 0x96, 0x40, 0x2b, 0x7b, 0x35, 0xdc, 0x0f, 0x4b, 0x2e, 0x78, 0x96, 0x1e, 0x83, 0xd4, 0x14,
+	0x03, 0xff, 0x8a, 0x94, 0xdc, 0xf7, 0xb0, 0xfa, 0x33, 0xdc, 0xee, 0x17, 0xee, 0x8d, 0x96, 0xf2,
+	0xba, 0x5a, 0xcd, 0xeb, 0x07, 0xa4, 0x70, 0xe1, 0xa3, 0x94, 0xd3, 0x4f, 0x49, 0xe5, 0x6d, 0x54,
+	0x60, 0x09, 0x70, 0xcd, 0x12, 0xfe, 0x43, 0xba, 0x75, 0x5f, 0x9e, 0x54, 0x3d, 0x1d, 0x31, 0x64,
+	0xb8, 0x36, 0x10, 0x0f, 0xa2, 0x03, 0xe9, 0xa9, 0x6d, 0xae, 0x5a, 0x75, 0xc9, 0xe1, 0x03, 0x37,
+	0x39, 0x54, 0x48, 0x68, 0xd4, 0x78, 0x9f, 0x94, 0xdd, 0x9b, 0xad, 0xbd, 0x08, 0xa1, 0xb5, 0xf3,
+	0x2c, 0xed, 0xaa, 0x24, 0xad, 0xf1, 0xe0, 0x1f, 0x16, 0x8e, 0xee, 0x72, 0x42, 0x18, 0x21, 0xdf,
+	0x23, 0x65, 0x97, 0x78, 0xd9, 0x2f, 0xd0, 0x16, 0xb6, 0x55, 0x2c, 0x2a, 0xbe, 0xee, 0x96, 0xe8,
+	0x3a, 0x21, 0xfe, 0xba, 0x2c, 0x28, 0xda, 0x4c, 0x8c, 0x10, 0x6f, 0x17, 0x2f, 0x0c, 0x97, 0xfa,
+	0x56, 0xcd, 0x8e, 0xfc, 0x47, 0xc5, 0x33, 0xc4, 0x72, 0x5e, 0xdf, 0x26, 0x75, 0xf7, 0x90, 0xab,
+	0x6e, 0x44, 0x68, 0x32, 0xfd, 0xa2, 0x53, 0xb7, 0xeb, 0xd6, 0x92, 0x3f, 0x2e, 0x5b, 0x4b, 0x96,
+	0x31, 0xcd, 0x84, 0xfb, 0xff, 0x01, 0x00, 0x1d, 0x4e, 0x5c, 0xe8, 0x93, 0x42, 0x00, 0x00,
 }
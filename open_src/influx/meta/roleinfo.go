@@ -0,0 +1,175 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"regexp"
+
+	"github.com/gogo/protobuf/proto"
+	originql "github.com/influxdata/influxql"
+	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
+)
+
+// RoleInfo represents a named set of privileges which can be granted to users.
+type RoleInfo struct {
+	Name       string
+	Privileges []RolePrivilege
+}
+
+// RolePrivilege is a privilege scoped to a database, optionally narrowed to
+// a retention policy and to a measurement or a measurement regex.
+type RolePrivilege struct {
+	Database string
+
+	// Empty means all retention policies of the database.
+	RetentionPolicy string
+
+	// Empty means all measurements of the retention policy.
+	Measurement string
+
+	// Whether Measurement is a regular expression.
+	Regex bool
+
+	Privilege originql.Privilege
+
+	// compiled Measurement when Regex is set
+	re *regexp.Regexp
+}
+
+// NewRolePrivilege returns a privilege on the given scope, compiling the
+// measurement regex if there is one.
+func NewRolePrivilege(database, rp, measurement string, regex bool, p originql.Privilege) (RolePrivilege, error) {
+	rpi := RolePrivilege{
+		Database:        database,
+		RetentionPolicy: rp,
+		Measurement:     measurement,
+		Regex:           regex,
+		Privilege:       p,
+	}
+	if regex {
+		re, err := regexp.Compile(measurement)
+		if err != nil {
+			return rpi, err
+		}
+		rpi.re = re
+	}
+	return rpi, nil
+}
+
+// sameScope returns true if both privileges apply to the same scope.
+func (p *RolePrivilege) sameScope(other *RolePrivilege) bool {
+	return p.Database == other.Database && p.RetentionPolicy == other.RetentionPolicy &&
+		p.Measurement == other.Measurement && p.Regex == other.Regex
+}
+
+// allows returns true if the privilege grants privilege.
+func (p *RolePrivilege) allows(privilege originql.Privilege) bool {
+	return p.Privilege == privilege || p.Privilege == originql.AllPrivileges
+}
+
+// covers returns true if the scope of the privilege includes the measurement mst
+// of retention policy rp. An empty rp asks for all retention policies and an
+// empty mst asks for all measurements.
+func (p *RolePrivilege) covers(database, rp, mst string) bool {
+	if p.Database != database {
+		return false
+	}
+	if p.RetentionPolicy != "" && p.RetentionPolicy != rp {
+		return false
+	}
+	if p.Measurement == "" {
+		return true
+	}
+	if mst == "" {
+		return false
+	}
+	if p.Regex {
+		return p.re != nil && p.re.MatchString(mst)
+	}
+	return p.Measurement == mst
+}
+
+func (p *RolePrivilege) marshal() *proto2.RolePrivilege {
+	return &proto2.RolePrivilege{
+		Database:        proto.String(p.Database),
+		RetentionPolicy: proto.String(p.RetentionPolicy),
+		Measurement:     proto.String(p.Measurement),
+		Regex:           proto.Bool(p.Regex),
+		Privilege:       proto.Int32(int32(p.Privilege)),
+	}
+}
+
+func (p *RolePrivilege) unmarshal(pb *proto2.RolePrivilege) {
+	// the regex has been validated when the privilege was granted
+	*p, _ = NewRolePrivilege(pb.GetDatabase(), pb.GetRetentionPolicy(), pb.GetMeasurement(), pb.GetRegex(),
+		originql.Privilege(pb.GetPrivilege()))
+}
+
+// clone returns a deep copy of ri.
+func (ri RoleInfo) clone() RoleInfo {
+	other := ri
+	if ri.Privileges != nil {
+		other.Privileges = make([]RolePrivilege, len(ri.Privileges))
+		copy(other.Privileges, ri.Privileges)
+	}
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (ri RoleInfo) marshal() *proto2.RoleInfo {
+	pb := &proto2.RoleInfo{
+		Name: proto.String(ri.Name),
+	}
+	for i := range ri.Privileges {
+		pb.Privileges = append(pb.Privileges, ri.Privileges[i].marshal())
+	}
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ri *RoleInfo) unmarshal(pb *proto2.RoleInfo) {
+	ri.Name = pb.GetName()
+	ri.Privileges = nil
+	if len(pb.GetPrivileges()) > 0 {
+		ri.Privileges = make([]RolePrivilege, len(pb.GetPrivileges()))
+	}
+	for i, x := range pb.GetPrivileges() {
+		ri.Privileges[i].unmarshal(x)
+	}
+}
+
+// setPrivilege grants p on the scope of p, replacing the privilege previously
+// granted on the same scope. Revoking bit clears p from it instead.
+func (ri *RoleInfo) setPrivilege(p RolePrivilege, revoke bool) {
+	for i := range ri.Privileges {
+		if !ri.Privileges[i].sameScope(&p) {
+			continue
+		}
+		if revoke {
+			p.Privilege = ri.Privileges[i].Privilege &^ p.Privilege
+		}
+		if p.Privilege == originql.NoPrivileges {
+			ri.Privileges = append(ri.Privileges[:i], ri.Privileges[i+1:]...)
+			return
+		}
+		ri.Privileges[i] = p
+		return
+	}
+	if !revoke && p.Privilege != originql.NoPrivileges {
+		ri.Privileges = append(ri.Privileges, p)
+	}
+}
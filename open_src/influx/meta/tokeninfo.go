@@ -0,0 +1,200 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	originql "github.com/influxdata/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	proto2 "github.com/openGemini/openGemini/open_src/influx/meta/proto"
+)
+
+// TokenInfo represents an API token of a user. Only the hash of the token
+// secret is stored, the secret itself is returned once when the token is created.
+type TokenInfo struct {
+	Name string
+
+	// Name of the user the token authenticates.
+	User string
+
+	// Hex encoded sha256 of the token secret.
+	Hash string
+
+	// Map of database name to the privilege usable through the token.
+	// A token without scopes carries the database privileges of its user.
+	Scopes map[string]originql.Privilege
+
+	CreatedAt time.Time
+
+	// Zero means the token never expires.
+	ExpiresAt time.Time
+
+	// Last time the token authenticated a request, updated at most once per
+	// TokenLastUsedInterval.
+	LastUsed time.Time
+}
+
+// TokenLastUsedInterval is the resolution of TokenInfo.LastUsed.
+const TokenLastUsedInterval = time.Minute
+
+// HashToken returns the hash stored for the token secret.
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Expired returns true if the token cannot be used anymore at now.
+func (ti *TokenInfo) Expired(now time.Time) bool {
+	return !ti.ExpiresAt.IsZero() && !now.Before(ti.ExpiresAt)
+}
+
+// allows returns true if the token scopes let privilege be used on database.
+func (ti *TokenInfo) allows(privilege originql.Privilege, database string) bool {
+	if len(ti.Scopes) == 0 || privilege == originql.NoPrivileges {
+		return true
+	}
+	p, ok := ti.Scopes[database]
+	return ok && (p == privilege || p == originql.AllPrivileges)
+}
+
+// clone returns a deep copy of ti.
+func (ti TokenInfo) clone() TokenInfo {
+	other := ti
+	if ti.Scopes != nil {
+		other.Scopes = make(map[string]originql.Privilege, len(ti.Scopes))
+		for k, v := range ti.Scopes {
+			other.Scopes[k] = v
+		}
+	}
+	return other
+}
+
+// Marshal serializes to a protobuf representation.
+func (ti TokenInfo) Marshal() *proto2.TokenInfo {
+	pb := &proto2.TokenInfo{
+		Name:      proto.String(ti.Name),
+		User:      proto.String(ti.User),
+		Hash:      proto.String(ti.Hash),
+		CreatedAt: proto.Int64(unixNano(ti.CreatedAt)),
+		ExpiresAt: proto.Int64(unixNano(ti.ExpiresAt)),
+		LastUsed:  proto.Int64(unixNano(ti.LastUsed)),
+	}
+	for database, privilege := range ti.Scopes {
+		pb.Scopes = append(pb.Scopes, &proto2.UserPrivilege{
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(privilege)),
+		})
+	}
+	return pb
+}
+
+// Unmarshal deserializes from a protobuf representation.
+func (ti *TokenInfo) Unmarshal(pb *proto2.TokenInfo) {
+	ti.Name = pb.GetName()
+	ti.User = pb.GetUser()
+	ti.Hash = pb.GetHash()
+	ti.CreatedAt = fromUnixNano(pb.GetCreatedAt())
+	ti.ExpiresAt = fromUnixNano(pb.GetExpiresAt())
+	ti.LastUsed = fromUnixNano(pb.GetLastUsed())
+
+	ti.Scopes = nil
+	if len(pb.GetScopes()) > 0 {
+		ti.Scopes = make(map[string]originql.Privilege, len(pb.GetScopes()))
+	}
+	for _, p := range pb.GetScopes() {
+		ti.Scopes[p.GetDatabase()] = originql.Privilege(p.GetPrivilege())
+	}
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns).UTC()
+}
+
+// TokenUser is a user authenticated by an API token. It holds the database
+// privileges of the token user restricted to the token scopes, and never the
+// admin privilege.
+type TokenUser struct {
+	*UserInfo
+	Token TokenInfo
+}
+
+var _ User = (*TokenUser)(nil)
+
+// AuthorizeDatabase returns true if both the user and the token scopes allow privilege on database.
+func (u *TokenUser) AuthorizeDatabase(privilege originql.Privilege, database string) bool {
+	return u.Token.allows(privilege, database) && u.UserInfo.AuthorizeDatabase(privilege, database)
+}
+
+// AuthorizeMeasurement returns true if both the user and the token scopes allow privilege on the measurement.
+func (u *TokenUser) AuthorizeMeasurement(privilege originql.Privilege, database, rp, measurement string) bool {
+	return u.Token.allows(privilege, database) && u.UserInfo.AuthorizeMeasurement(privilege, database, rp, measurement)
+}
+
+// AuthorizeAnyMeasurement returns true if the token scopes allow privilege on database and the user
+// holds it on at least a part of the database.
+func (u *TokenUser) AuthorizeAnyMeasurement(privilege originql.Privilege, database string) bool {
+	return u.Token.allows(privilege, database) && u.UserInfo.AuthorizeAnyMeasurement(privilege, database)
+}
+
+// AuthorizeUnrestricted returns false, tokens never carry the admin privilege.
+func (u *TokenUser) AuthorizeUnrestricted() bool {
+	return false
+}
+
+// AuthorizeQuery returns an error if a statement of the query requires the admin
+// privilege or a privilege out of the token scopes.
+func (u *TokenUser) AuthorizeQuery(database string, query *influxql.Query) error {
+	for _, stmt := range query.Statements {
+		privs, err := stmt.RequiredPrivileges()
+		if err != nil {
+			return err
+		}
+		for _, p := range privs {
+			db := p.Name
+			if db == "" {
+				db = database
+			}
+			if p.Admin || !u.Token.allows(originql.Privilege(p.Privilege), db) {
+				return &ErrAuthorize{
+					Query:    query,
+					User:     u.Name,
+					Database: database,
+					Message:  fmt.Sprintf("statement '%s' with token %q", stmt, u.Token.Name),
+				}
+			}
+		}
+	}
+	if u.Admin || u.Rwuser {
+		return nil
+	}
+	return u.UserInfo.AuthorizeQuery(database, query)
+}
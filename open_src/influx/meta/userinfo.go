@@ -32,6 +32,12 @@ type UserInfo struct {
 
 	// Map of database name to granted privilege.
 	Privileges map[string]originql.Privilege
+
+	// Names of the roles granted to the user.
+	Roles []string
+
+	// privileges of the granted roles, resolved by Data.
+	rolePrivileges []RolePrivilege
 }
 
 type User interface {
@@ -41,6 +47,12 @@ type User interface {
 	// AuthorizeQuery returns an error if the query cannot be executed
 	AuthorizeQuery(database string, query *influxql.Query) error
 
+	// AuthorizeMeasurement indicates whether the given Privilege is authorized on a measurement.
+	AuthorizeMeasurement(p originql.Privilege, database, rp, measurement string) bool
+
+	// AuthorizeAnyMeasurement indicates whether the given Privilege is authorized on at least one measurement of the database.
+	AuthorizeAnyMeasurement(p originql.Privilege, database string) bool
+
 	query.FineAuthorizer
 	ID() string
 	AuthorizeUnrestricted() bool
@@ -56,7 +68,60 @@ func (u *UserInfo) AuthorizeDatabase(privilege originql.Privilege, database stri
 		return true
 	}
 	p, ok := u.Privileges[database]
-	return ok && (p == privilege || p == originql.AllPrivileges)
+	if ok && (p == privilege || p == originql.AllPrivileges) {
+		return true
+	}
+	return u.authorizeRoles(privilege, database, "", "")
+}
+
+// AuthorizeMeasurement returns true if the user is authorized for the given privilege
+// on the measurement of the retention policy. An empty measurement asks for all
+// measurements of the retention policy.
+func (u *UserInfo) AuthorizeMeasurement(privilege originql.Privilege, database, rp, measurement string) bool {
+	return u.AuthorizeDatabase(privilege, database) || u.authorizeRoles(privilege, database, rp, measurement)
+}
+
+// AuthorizeAnyMeasurement returns true if the user is authorized for the given privilege
+// on the whole database or on a part of it through a role.
+func (u *UserInfo) AuthorizeAnyMeasurement(privilege originql.Privilege, database string) bool {
+	if u.AuthorizeDatabase(privilege, database) {
+		return true
+	}
+	for i := range u.rolePrivileges {
+		if u.rolePrivileges[i].Database == database && u.rolePrivileges[i].allows(privilege) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *UserInfo) authorizeRoles(privilege originql.Privilege, database, rp, measurement string) bool {
+	for i := range u.rolePrivileges {
+		p := &u.rolePrivileges[i]
+		if p.allows(privilege) && p.covers(database, rp, measurement) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole returns true if the role is granted to the user.
+func (u *UserInfo) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *UserInfo) removeRole(role string) {
+	for i, r := range u.Roles {
+		if r == role {
+			u.Roles = append(u.Roles[:i:i], u.Roles[i+1:]...)
+			return
+		}
+	}
 }
 
 func (u *UserInfo) IsOpen() bool {
@@ -89,6 +154,11 @@ func (u UserInfo) clone() UserInfo {
 		}
 	}
 
+	if u.Roles != nil {
+		other.Roles = make([]string, len(u.Roles))
+		copy(other.Roles, u.Roles)
+	}
+
 	return other
 }
 
@@ -107,6 +177,7 @@ func (u UserInfo) marshal() *proto2.UserInfo {
 			Privilege: proto.Int32(int32(privilege)),
 		})
 	}
+	pb.Roles = append(pb.Roles, u.Roles...)
 
 	return pb
 }
@@ -125,4 +196,9 @@ func (u *UserInfo) unmarshal(pb *proto2.UserInfo) {
 	for _, p := range pb.GetPrivileges() {
 		u.Privileges[p.GetDatabase()] = originql.Privilege(p.GetPrivilege())
 	}
+
+	u.Roles = nil
+	if len(pb.GetRoles()) > 0 {
+		u.Roles = append(u.Roles, pb.GetRoles()...)
+	}
 }
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/models"
+	originql "github.com/influxdata/influxql"
 	"github.com/openGemini/openGemini/engine/hybridqp"
 	"github.com/openGemini/openGemini/engine/op"
 	"github.com/openGemini/openGemini/open_src/influx/influxql"
@@ -292,6 +293,8 @@ func (c *compiledField) compileExpr(expr influxql.Expr) error {
 		switch expr.Name {
 		case "percentile":
 			return c.compilePercentile(expr.Args)
+		case "approx_percentile":
+			return c.compileApproxPercentile(expr.Args)
 		case "histogram":
 			return c.compileHistogram(expr.Args)
 		case "sample":
@@ -407,7 +410,8 @@ func (c *compiledField) compileFunction(expr *influxql.Call) error {
 	switch expr.Name {
 	case "max", "min", "first", "last":
 		// top/bottom are not included here since they are not typical functions.
-	case "count", "sum", "mean", "median", "mode", "stddev", "spread", "rate", "irate", "absent":
+	case "count", "sum", "mean", "median", "mode", "stddev", "spread", "rate", "irate", "absent",
+		"approx_count_distinct", "approx_median":
 		// These functions are not considered selectors.
 		c.global.OnlySelectors = false
 	default:
@@ -502,6 +506,21 @@ func (c *compiledField) compilePercentile(args []influxql.Expr) error {
 	return c.compileSymbol("percentile", args[0])
 }
 
+func (c *compiledField) compileApproxPercentile(args []influxql.Expr) error {
+	if exp, got := 2, len(args); got != exp {
+		return fmt.Errorf("invalid number of arguments for approx_percentile, expected %d, got %d", exp, got)
+	}
+
+	switch args[1].(type) {
+	case *influxql.IntegerLiteral:
+	case *influxql.NumberLiteral:
+	default:
+		return fmt.Errorf("expected float argument in approx_percentile()")
+	}
+	c.global.OnlySelectors = false
+	return c.compileSymbol("approx_percentile", args[0])
+}
+
 func (c *compiledField) compileHistogram(args []influxql.Expr) error {
 	/*if exp, got := 2, len(args); got != exp {
 	        return fmt.Errorf("invalid number of arguments for histogram, expected %d, got %d", exp, got)
@@ -1036,7 +1055,8 @@ func (c *compiledField) validateSelector(expr *influxql.Call) {
 	switch expr.Name {
 	case "max", "min", "first", "last":
 		// top/bottom are not included here since they are not typical functions.
-	case "count", "sum", "mean", "median", "mode", "stddev", "spread", "rate", "irate", "absent":
+	case "count", "sum", "mean", "median", "mode", "stddev", "spread", "rate", "irate", "absent",
+		"approx_count_distinct", "approx_median":
 		// These functions are not considered selectors.
 		c.global.OnlySelectors = false
 	}
@@ -1075,6 +1095,8 @@ func (c *compiledField) compileCall(expr *influxql.Call) error {
 	switch expr.Name {
 	case "percentile":
 		return c.compilePercentile(expr.Args)
+	case "approx_percentile":
+		return c.compileApproxPercentile(expr.Args)
 	case "histogram":
 		return c.compileHistogram(expr.Args)
 	case "sample":
@@ -1395,6 +1417,10 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 		sopt.HintType = hybridqp.SpecificSeriesQuery
 	}
 
+	if err := AuthorizeMeasurements(c.stmt, sopt.Authorizer); err != nil {
+		return nil, err
+	}
+
 	// Create an iterator creator based on the shards in the cluster.
 	shards, err := shardMapper.MapShards(c.stmt.Sources, timeRange, sopt, c.stmt.Condition)
 	if err != nil {
@@ -1461,3 +1487,35 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 	columns := stmt.ColumnNames()
 	return NewPreparedStatement(stmt, &opt, shards, columns, sopt.MaxPointN, c.Options.Now), nil
 }
+
+// AuthorizeMeasurements checks the privileges on the measurements read and
+// written by the statement, sub queries included. A regex source needs the
+// read privilege on the whole retention policy.
+func AuthorizeMeasurements(stmt *influxql.SelectStatement, a FineAuthorizer) error {
+	ma, ok := a.(MeasurementAuthorizer)
+	if !ok {
+		return nil
+	}
+
+	authorize := func(p originql.Privilege, m *influxql.Measurement) error {
+		name := m.Name
+		if m.Regex != nil {
+			name = ""
+		}
+		if !ma.AuthorizeMeasurement(p, m.Database, m.RetentionPolicy, name) {
+			return fmt.Errorf("not authorized to %s measurement %s", strings.ToLower(p.String()), m.String())
+		}
+		return nil
+	}
+
+	var err error
+	influxql.WalkFunc(stmt.Sources, func(n influxql.Node) {
+		if m, ok := n.(*influxql.Measurement); ok && err == nil && m.SystemIterator == "" {
+			err = authorize(originql.ReadPrivilege, m)
+		}
+	})
+	if err == nil && stmt.Target != nil && stmt.Target.Measurement != nil {
+		err = authorize(originql.WritePrivilege, stmt.Target.Measurement)
+	}
+	return err
+}
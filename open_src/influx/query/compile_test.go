@@ -0,0 +1,119 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+	originql "github.com/influxdata/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/influxql"
+	"github.com/openGemini/openGemini/open_src/influx/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errShardsMapped = errors.New("shards mapped")
+
+type failShardMapper struct{}
+
+func (m failShardMapper) MapShards(influxql.Sources, influxql.TimeRange, query.SelectOptions, influxql.Expr) (query.ShardGroup, error) {
+	return nil, errShardsMapped
+}
+
+func (m failShardMapper) Close() error { return nil }
+
+func (m failShardMapper) GetSeriesKey() []byte { return nil }
+
+// measurementAuthorizer allows reading cpu and writing cpu_copy in db0.rp0
+type measurementAuthorizer struct{}
+
+func (a measurementAuthorizer) AuthorizeDatabase(originql.Privilege, string) bool { return false }
+
+func (a measurementAuthorizer) AuthorizeSeriesRead(string, []byte, models.Tags) bool { return true }
+
+func (a measurementAuthorizer) AuthorizeSeriesWrite(string, []byte, models.Tags) bool { return true }
+
+func (a measurementAuthorizer) AuthorizeMeasurement(p originql.Privilege, db, rp, mst string) bool {
+	if db != "db0" || rp != "rp0" {
+		return false
+	}
+	return (p == originql.ReadPrivilege && mst == "cpu") || (p == originql.WritePrivilege && mst == "cpu_copy")
+}
+
+func TestPrepare_AuthorizeMeasurements(t *testing.T) {
+	for _, tt := range []struct {
+		sql        string
+		into       string
+		authorized bool
+	}{
+		{sql: `SELECT value FROM db0.rp0.cpu`, authorized: true},
+		{sql: `SELECT value FROM db0.rp0.mem`},
+		{sql: `SELECT value FROM db0.rp1.cpu`},
+		{sql: `SELECT value FROM db0.rp0./cpu/`},
+		{sql: `SELECT value FROM (SELECT value FROM db0.rp0.mem)`},
+		{sql: `SELECT value FROM db0.rp0.cpu, db0.rp0.mem WHERE time > now() - 1h`},
+		{sql: `SELECT value FROM db0.rp0.cpu`, into: "cpu_copy", authorized: true},
+		{sql: `SELECT value FROM db0.rp0.cpu`, into: "cpu"},
+	} {
+		stmt := influxql.MustParseStatement(tt.sql).(*influxql.SelectStatement)
+		if tt.into != "" {
+			stmt.Target = &influxql.Target{Measurement: &influxql.Measurement{
+				Database: "db0", RetentionPolicy: "rp0", Name: tt.into, IsTarget: true}}
+		}
+		c, err := query.Compile(stmt, query.CompileOptions{})
+		require.NoError(t, err, tt.sql)
+		_, err = c.Prepare(failShardMapper{}, query.SelectOptions{Authorizer: measurementAuthorizer{}})
+		if tt.authorized {
+			assert.Equal(t, errShardsMapped, err, tt.sql)
+		} else {
+			assert.Error(t, err, tt.sql)
+			assert.NotEqual(t, errShardsMapped, err, tt.sql)
+		}
+	}
+
+	// authorizers without measurement privileges are left to the database check
+	stmt := influxql.MustParseStatement(`SELECT value FROM db0.rp0.mem`).(*influxql.SelectStatement)
+	c, err := query.Compile(stmt, query.CompileOptions{})
+	require.NoError(t, err)
+	_, err = c.Prepare(failShardMapper{}, query.SelectOptions{Authorizer: query.OpenAuthorizer})
+	assert.Equal(t, errShardsMapped, err)
+}
+
+func TestCompile_ApproxFunctions(t *testing.T) {
+	for _, tt := range []struct {
+		sql string
+		err string
+	}{
+		{sql: `SELECT approx_count_distinct(host) FROM cpu`},
+		{sql: `SELECT approx_median(value), approx_percentile(value, 99.9) FROM cpu GROUP BY time(1m)`},
+		{sql: `SELECT approx_percentile(value, 90) FROM cpu`},
+		{sql: `SELECT approx_count_distinct(value, 1) FROM cpu`, err: "invalid number of arguments for approx_count_distinct, expected 1, got 2"},
+		{sql: `SELECT approx_percentile(value) FROM cpu`, err: "invalid number of arguments for approx_percentile, expected 2, got 1"},
+		{sql: `SELECT approx_percentile(value, 'a') FROM cpu`, err: "expected float argument in approx_percentile()"},
+		{sql: `SELECT approx_median(1) FROM cpu`, err: "expected field argument in approx_median()"},
+	} {
+		stmt := influxql.MustParseStatement(tt.sql).(*influxql.SelectStatement)
+		_, err := query.Compile(stmt, query.CompileOptions{})
+		if tt.err == "" {
+			require.NoError(t, err, tt.sql)
+		} else {
+			assert.EqualError(t, err, tt.err, tt.sql)
+		}
+	}
+}
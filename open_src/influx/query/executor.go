@@ -107,6 +107,14 @@ type FineAuthorizer interface {
 	//IsOpen() bool
 }
 
+// MeasurementAuthorizer determines if an operation is authorized at the measurement level.
+// It is implemented by the FineAuthorizer of users holding privileges through roles.
+type MeasurementAuthorizer interface {
+	// AuthorizeMeasurement indicates whether the given Privilege is authorized on a measurement.
+	// An empty measurement asks for all measurements of the retention policy.
+	AuthorizeMeasurement(p originql.Privilege, database, rp, measurement string) bool
+}
+
 // OpenAuthorizer is the Authorizer used when authorization is disabled.
 // It allows all operations.
 type openAuthorizer struct{}
@@ -145,6 +153,12 @@ type RowsChan struct {
 	Partial bool        // is partial of rows
 }
 
+// ChunkWriter receives the result chunks of select statements in place of RowsChan.
+// The chunk is an executor.Chunk and must not be referenced after WriteChunk returns.
+type ChunkWriter interface {
+	WriteChunk(chunk interface{}) error
+}
+
 // ExecutionOptions contains the options for executing a query.
 type ExecutionOptions struct {
 	// The database the query is running against.
@@ -186,6 +200,13 @@ type ExecutionOptions struct {
 
 	// The results of the query executor
 	RowsChan chan RowsChan
+
+	// ChunkWriter, if set, receives the result chunks of select statements instead of RowsChan
+	ChunkWriter ChunkWriter
+
+	// The user executing the query and the address of its client, recorded by the audit log.
+	UserID     string
+	RemoteAddr string
 }
 
 type (
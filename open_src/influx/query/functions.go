@@ -95,10 +95,13 @@ func (m FunctionTypeMapper) CallType(name string, args []influxql.DataType) (inf
 		"kaufmans_adaptive_moving_average",
 		"chande_momentum_oscillator",
 		"holt_winters", "holt_winters_with_fit",
-		"rate", "irate":
+		"rate", "irate",
+		"approx_median", "approx_percentile", "tdigest_quantile":
 		return influxql.Float, nil
-	case "elapsed", "absent":
+	case "elapsed", "absent", "approx_count_distinct", "hll_count":
 		return influxql.Integer, nil
+	case "hll_sketch", "hll_merge", "tdigest_sketch", "tdigest_merge":
+		return influxql.String, nil
 	case "percentile", "histogram", "distinct", "top", "bottom",
 		"difference", "non_negative_difference", "mode", "spread", "sample", "cumulative_sum":
 		return args[0], nil
@@ -62,8 +62,9 @@ type SelectOptions struct {
 
 	Traceid uint64
 
-	AbortChan <-chan struct{}
-	RowsChan  chan RowsChan
+	AbortChan   <-chan struct{}
+	RowsChan    chan RowsChan
+	ChunkWriter ChunkWriter
 
 	HintType hybridqp.HintType
 }
@@ -185,6 +186,9 @@ type ProcessorOptions struct {
 	// Limits on the creation of iterators.
 	MaxSeriesN int
 
+	// Maximum bytes of memory the readers of a store can use for the query, 0 for no limit
+	MaxQueryMem int64
+
 	// If this channel is set and is closed, the iterator should try to exit
 	// and close as soon as possible.
 	InterruptCh <-chan struct{}
@@ -203,6 +207,7 @@ type ProcessorOptions struct {
 	MaxParallel int
 	AbortChan   <-chan struct{}
 	RowsChan    chan RowsChan
+	ChunkWriter ChunkWriter
 	Query       string
 
 	EnableBinaryTreeMerge int64
@@ -284,6 +289,7 @@ func NewProcessorOptionsStmt(stmt *influxql.SelectStatement, sopt SelectOptions)
 	opt.Limit, opt.Offset = stmt.Limit, stmt.Offset
 	opt.SLimit, opt.SOffset = stmt.SLimit, stmt.SOffset
 	opt.MaxSeriesN = sopt.MaxSeriesN
+	opt.MaxQueryMem = sopt.MaxQueryMem
 	opt.Authorizer = sopt.Authorizer
 
 	opt.ChunkedSize = sopt.ChunkedSize
@@ -296,6 +302,7 @@ func NewProcessorOptionsStmt(stmt *influxql.SelectStatement, sopt SelectOptions)
 	opt.MaxParallel = sopt.MaxQueryParallel
 	opt.AbortChan = sopt.AbortChan
 	opt.RowsChan = sopt.RowsChan
+	opt.ChunkWriter = sopt.ChunkWriter
 	opt.GroupByAllDims = stmt.GroupByAllDims
 
 	return opt, nil
@@ -303,8 +310,9 @@ func NewProcessorOptionsStmt(stmt *influxql.SelectStatement, sopt SelectOptions)
 
 func newProcessorOptionsSubstatement(ctx context.Context, stmt *influxql.SelectStatement, opt ProcessorOptions) (ProcessorOptions, error) {
 	subOpt, err := NewProcessorOptionsStmt(stmt, SelectOptions{
-		Authorizer: opt.Authorizer,
-		MaxSeriesN: opt.MaxSeriesN,
+		Authorizer:  opt.Authorizer,
+		MaxSeriesN:  opt.MaxSeriesN,
+		MaxQueryMem: opt.MaxQueryMem,
 	})
 	if err != nil {
 		return ProcessorOptions{}, err
@@ -200,16 +200,16 @@ func (t *TaskManager) AttachQuery(q *influxql.Query, opt ExecutionOptions, inter
 	}
 	t.queries[qid] = query
 
-	go t.waitForQuery(qid, query.closing, interrupt, query.monitorCh)
-	if t.LogQueriesAfter != 0 {
+	go t.waitForQuery(qid, t.QueryTimeout, query.closing, interrupt, query.monitorCh)
+	if logQueriesAfter := t.LogQueriesAfter; logQueriesAfter != 0 {
 		go query.monitor(func(closing <-chan struct{}) error {
-			timer := time.NewTimer(t.LogQueriesAfter)
+			timer := time.NewTimer(logQueriesAfter)
 			defer timer.Stop()
 
 			select {
 			case <-timer.C:
 				t.Logger.Warn(fmt.Sprintf("Detected slow query: %s (qid: %d, database: %s, threshold: %s)",
-					query.query, qid, query.database, t.LogQueriesAfter))
+					query.query, qid, query.database, logQueriesAfter))
 			case <-closing:
 			}
 			return nil
@@ -228,6 +228,17 @@ func (t *TaskManager) AttachQuery(q *influxql.Query, opt ExecutionOptions, inter
 	return ctx, func() { t.DetachQuery(qid) }, nil
 }
 
+// SetLimits changes the limits of the queries, the queries already attached
+// keep the limits they were attached with.
+func (t *TaskManager) SetLimits(queryTimeout, logQueriesAfter time.Duration, maxConcurrentQueries int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.QueryTimeout = queryTimeout
+	t.LogQueriesAfter = logQueriesAfter
+	t.MaxConcurrentQueries = maxConcurrentQueries
+}
+
 // KillQuery enters a query into the killed state and closes the channel
 // from the TaskManager. This method can be used to forcefully terminate a
 // running query.
@@ -286,10 +297,10 @@ func (t *TaskManager) Queries() []QueryInfo {
 	return queries
 }
 
-func (t *TaskManager) waitForQuery(qid uint64, interrupt <-chan struct{}, closing <-chan struct{}, monitorCh <-chan error) {
+func (t *TaskManager) waitForQuery(qid uint64, timeout time.Duration, interrupt <-chan struct{}, closing <-chan struct{}, monitorCh <-chan error) {
 	var timerCh <-chan time.Time
-	if t.QueryTimeout != 0 {
-		timer := time.NewTimer(t.QueryTimeout)
+	if timeout != 0 {
+		timer := time.NewTimer(timeout)
 		timerCh = timer.C
 		defer timer.Stop()
 	}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mergeset
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/filestream"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/openGemini/openGemini/lib/encrypt"
+	"github.com/openGemini/openGemini/lib/fileops"
+)
+
+// The part files are read and written through fileops when the encryption at rest
+// is enabled, so they are encrypted like the other files of ts-store. Encrypted
+// files can not be mapped into the memory, they are read by ReadAt.
+
+const fileBufferSize = 64 * 1024
+
+var tmpFileNum uint64
+
+func encrypted() bool {
+	return encrypt.DefaultKeyring() != nil
+}
+
+type fileReader struct {
+	f  fileops.File
+	br *bufio.Reader
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	return r.br.Read(p)
+}
+
+func (r *fileReader) MustClose() {
+	if err := r.f.Close(); err != nil {
+		logger.Panicf("FATAL: cannot close %q: %s", r.f.Name(), err)
+	}
+}
+
+func openReader(path string, nocache bool) (filestream.ReadCloser, error) {
+	if !encrypted() {
+		return filestream.Open(path, nocache)
+	}
+	f, err := fileops.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileReader{f: f, br: bufio.NewReaderSize(f, fileBufferSize)}, nil
+}
+
+type fileWriter struct {
+	f  fileops.File
+	bw *bufio.Writer
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	return w.bw.Write(p)
+}
+
+func (w *fileWriter) MustClose() {
+	if err := w.bw.Flush(); err != nil {
+		logger.Panicf("FATAL: cannot flush data to %q: %s", w.f.Name(), err)
+	}
+	if err := w.f.Sync(); err != nil {
+		logger.Panicf("FATAL: cannot sync %q: %s", w.f.Name(), err)
+	}
+	if err := w.f.Close(); err != nil {
+		logger.Panicf("FATAL: cannot close %q: %s", w.f.Name(), err)
+	}
+}
+
+func createWriter(path string, nocache bool) (filestream.WriteCloser, error) {
+	if !encrypted() {
+		return filestream.Create(path, nocache)
+	}
+	f, err := fileops.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileWriter{f: f, bw: bufio.NewWriterSize(f, fileBufferSize)}, nil
+}
+
+type fileReaderAt struct {
+	f fileops.File
+}
+
+func (r *fileReaderAt) MustReadAt(p []byte, off int64) {
+	if len(p) == 0 {
+		return
+	}
+	n, err := r.f.ReadAt(p, off)
+	if err != nil {
+		logger.Panicf("FATAL: cannot read %d bytes at offset %d of file %q: %s", len(p), off, r.f.Name(), err)
+	}
+	if n != len(p) {
+		logger.Panicf("FATAL: unexpected number of bytes read; got %d; want %d", n, len(p))
+	}
+}
+
+func (r *fileReaderAt) MustClose() {
+	if err := r.f.Close(); err != nil {
+		logger.Panicf("FATAL: cannot close %q: %s", r.f.Name(), err)
+	}
+}
+
+func mustOpenReaderAt(path string) fs.MustReadAtCloser {
+	if !encrypted() {
+		return fs.MustOpenReaderAt(path)
+	}
+	f, err := fileops.Open(path)
+	if err != nil {
+		logger.Panicf("FATAL: cannot open %q: %s", path, err)
+	}
+	return &fileReaderAt{f: f}
+}
+
+func mustFileSize(path string) uint64 {
+	if !encrypted() {
+		return fs.MustFileSize(path)
+	}
+	fi, err := fileops.Stat(path)
+	if err != nil {
+		logger.Panicf("FATAL: cannot stat %q: %s", path, err)
+	}
+	return uint64(fi.Size())
+}
+
+func readFile(path string) ([]byte, error) {
+	if !encrypted() {
+		return ioutil.ReadFile(path)
+	}
+	return fileops.ReadFile(path)
+}
+
+// writeFileAtomically writes data to a temporary file and renames it to path, as fs.WriteFileAtomically.
+func writeFileAtomically(path string, data []byte) error {
+	if !encrypted() {
+		return fs.WriteFileAtomically(path, data)
+	}
+	if fs.IsPathExist(path) {
+		return fmt.Errorf("cannot create file %q, since it already exists", path)
+	}
+
+	tmpPath := fmt.Sprintf("%s.tmp.%d", path, atomic.AddUint64(&tmpFileNum, 1))
+	w, err := createWriter(tmpPath, false)
+	if err != nil {
+		return fmt.Errorf("cannot create file %q: %w", tmpPath, err)
+	}
+	if _, err = w.Write(data); err != nil {
+		w.MustClose()
+		fs.MustRemoveAll(tmpPath)
+		return fmt.Errorf("cannot write %d bytes to file %q: %w", len(data), tmpPath, err)
+	}
+	w.MustClose()
+
+	if err = fileops.RenameFile(tmpPath, path); err != nil {
+		return fmt.Errorf("cannot move %q to %q: %w", tmpPath, path, err)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("cannot obtain absolute path to %q: %w", path, err)
+	}
+	fs.MustSyncPath(filepath.Dir(absPath))
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mergeset
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openGemini/openGemini/lib/encrypt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_Encrypted(t *testing.T) {
+	keyring, err := encrypt.ParseKeyring([]byte("1 000102030405060708090a0b0c0d0e0f"))
+	require.NoError(t, err)
+	encrypt.SetDefaultKeyring(keyring)
+	defer encrypt.SetDefaultKeyring(nil)
+
+	path := filepath.Join(t.TempDir(), "mergeset")
+	tb, err := OpenTable(path, nil, nil)
+	require.NoError(t, err)
+	var items []string
+	for i := 0; i < 1000; i++ {
+		item := fmt.Sprintf("cpu,host=server%04d", i)
+		items = append(items, item)
+		require.NoError(t, tb.AddItems([][]byte{[]byte(item)}))
+	}
+	tb.DebugFlush()
+	tb.MustClose()
+
+	// no part file holds the items in plaintext
+	files := 0
+	require.NoError(t, filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() || fi.Size() == 0 || filepath.Base(filepath.Dir(p)) == "txn" {
+			return err
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		// the lock and the flag of the conversion hold no item
+		if name := filepath.Base(p); name != "flock.lock" && name != convertToV1280FileName {
+			files++
+			require.True(t, bytes.HasPrefix(data, []byte("OGENC")), p)
+		}
+		require.False(t, bytes.Contains(data, []byte("server0001")), p)
+		return nil
+	}))
+	require.True(t, files > 0)
+
+	tb, err = OpenTable(path, nil, nil)
+	require.NoError(t, err)
+	defer tb.MustClose()
+	var ts TableSearch
+	ts.Init(tb)
+	defer ts.MustClose()
+	ts.Seek([]byte("cpu"))
+	var got []string
+	for ts.NextItem() {
+		got = append(got, string(ts.Item))
+	}
+	require.NoError(t, ts.Error())
+	require.Equal(t, items, got)
+}
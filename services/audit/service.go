@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"time"
+
+	"github.com/openGemini/openGemini/lib/audit"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/services/listener"
+)
+
+// Measurement receives the audit events in the audit database.
+const Measurement = "events"
+
+// Service writes the audit events of ts-sql into the audit database in batches.
+type Service struct {
+	listener.Listener
+}
+
+func NewService(c config.Audit) *Service {
+	s := &Service{}
+	s.InitListener("audit", "", c.Database, "", c.BatchSize, time.Duration(c.BatchTimeout))
+	return s
+}
+
+func (s *Service) Open() error {
+	if err := s.Listener.Open(); err != nil {
+		return err
+	}
+	audit.AddSink(s)
+	return nil
+}
+
+func (s *Service) Close() error {
+	audit.RemoveSink(s)
+	return s.Listener.Close()
+}
+
+// WriteEvent adds the event to the pending batch.
+func (s *Service) WriteEvent(e *audit.Event) {
+	s.AddRow(eventToRow(e))
+}
+
+func eventToRow(e *audit.Event) influx.Row {
+	row := influx.Row{
+		Name:      Measurement,
+		Timestamp: e.Time.UnixNano(),
+	}
+
+	// tags sorted by key, the empty ones are omitted
+	for _, tag := range []influx.Tag{
+		{Key: "result", Value: e.Result()},
+		{Key: "type", Value: e.Type},
+		{Key: "user", Value: e.User},
+	} {
+		if tag.Value != "" {
+			row.Tags = append(row.Tags, tag)
+		}
+	}
+
+	errMsg := ""
+	if e.Err != nil {
+		errMsg = e.Err.Error()
+	}
+	for _, f := range []influx.Field{
+		{Key: "db", StrValue: e.Database},
+		{Key: "error", StrValue: errMsg},
+		{Key: "object", StrValue: e.Object},
+		{Key: "source", StrValue: e.Source},
+		{Key: "statement", StrValue: e.Statement},
+	} {
+		// the statement is always written so that the point has a field
+		if f.StrValue != "" || f.Key == "statement" {
+			f.Type = influx.Field_Type_String
+			row.Fields = append(row.Fields, f)
+		}
+	}
+	return row
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/audit"
+	"github.com/openGemini/openGemini/lib/config"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMetaClient struct{}
+
+func (c *mockMetaClient) CreateDatabase(name string) (*meta2.DatabaseInfo, error) {
+	return &meta2.DatabaseInfo{Name: name}, nil
+}
+
+type mockPointsWriter struct {
+	mu   sync.Mutex
+	db   string
+	rows []influx.Row
+}
+
+func (w *mockPointsWriter) WritePointRows(database, retentionPolicy string, rows []influx.Row) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.db = database
+	w.rows = append(w.rows, rows...)
+	return nil
+}
+
+func TestEventToRow(t *testing.T) {
+	now := time.Now()
+	row := eventToRow(&audit.Event{Time: now, Type: audit.Login, Source: "127.0.0.1:1234", Err: errors.New("bad password")})
+	require.Equal(t, Measurement, row.Name)
+	require.Equal(t, now.UnixNano(), row.Timestamp)
+	require.Equal(t, influx.PointTags{{Key: "result", Value: "failure"}, {Key: "type", Value: "login"}}, row.Tags)
+	require.Len(t, row.Fields, 3)
+	require.Equal(t, "error", row.Fields[0].Key)
+	require.Equal(t, "bad password", row.Fields[0].StrValue)
+	require.Equal(t, "source", row.Fields[1].Key)
+	require.Equal(t, "statement", row.Fields[2].Key)
+	require.Equal(t, "", row.Fields[2].StrValue)
+
+	row = eventToRow(&audit.Event{Time: now, Type: audit.Statement, User: "admin", Database: "db0", Statement: "DROP DATABASE db0"})
+	require.Len(t, row.Tags, 3)
+	require.Equal(t, "user", row.Tags[2].Key)
+	require.Equal(t, "db", row.Fields[0].Key)
+	require.Equal(t, "DROP DATABASE db0", row.Fields[1].StrValue)
+	for _, f := range row.Fields {
+		require.Equal(t, int32(influx.Field_Type_String), f.Type)
+	}
+}
+
+func TestService(t *testing.T) {
+	logConf := config.NewLogger(config.AppSql)
+	logConf.Path = t.TempDir()
+	conf := config.NewAudit()
+	conf.Enabled = true
+	conf.WriteDatabase = true
+	audit.Open(conf, logConf)
+	defer audit.Close()
+
+	s := NewService(conf)
+	w := &mockPointsWriter{}
+	s.MetaClient, s.PointsWriter = &mockMetaClient{}, w
+	require.NoError(t, s.Open())
+
+	audit.Log(&audit.Event{Type: audit.Statement, User: "admin", Statement: "CREATE DATABASE db0"})
+	require.NoError(t, s.Close())
+	// events after the service is closed are not written
+	audit.Log(&audit.Event{Type: audit.Statement, User: "admin", Statement: "DROP DATABASE db0"})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	require.Equal(t, "_audit", w.db)
+	require.Len(t, w.rows, 1)
+	require.Equal(t, "CREATE DATABASE db0", w.rows[0].Fields[0].StrValue)
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphite
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/graphite"
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/services/listener"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxUDPPayload is the largest payload of a udp datagram
+	maxUDPPayload = 64 * 1024
+
+	// maxLineSize is the longest line read from a tcp connection, the connection is closed
+	// if a line is longer
+	maxLineSize = 64 * 1024
+)
+
+// Service receives the graphite plaintext protocol over tcp or udp,
+// the dotted names are mapped to measurement, tags and field by the templates.
+type Service struct {
+	listener.Listener
+
+	conf   config.Graphite
+	parser *graphite.Parser
+
+	ln      net.Listener
+	udpConn *net.UDPConn
+	addr    net.Addr
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	closing bool
+	conns   map[net.Conn]struct{}
+}
+
+func NewService(c config.Graphite) (*Service, error) {
+	tags := make(map[string]string, len(c.Tags))
+	for _, tag := range c.Tags {
+		k, v, err := config.ParseGraphiteTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		tags[k] = v
+	}
+
+	parser, err := graphite.NewParserWithOptions(graphite.Options{
+		Separator:   c.Separator,
+		Templates:   c.Templates,
+		DefaultTags: models.NewTags(tags),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		conf:   c,
+		parser: parser,
+		conns:  make(map[net.Conn]struct{}),
+	}
+	s.InitListener("graphite", c.BindAddress, c.Database, c.RetentionPolicy, c.BatchSize, time.Duration(c.BatchTimeout))
+	return s, nil
+}
+
+func (s *Service) Open() error {
+	switch s.conf.Protocol {
+	case "udp":
+		addr, err := net.ResolveUDPAddr("udp", s.conf.BindAddress)
+		if err != nil {
+			return err
+		}
+		conn, err := net.ListenUDP("udp", addr)
+		if err != nil {
+			return err
+		}
+		if s.conf.UDPReadBuffer != 0 {
+			if err := conn.SetReadBuffer(s.conf.UDPReadBuffer); err != nil {
+				_ = conn.Close()
+				return err
+			}
+		}
+		s.udpConn, s.addr = conn, conn.LocalAddr()
+		s.wg.Add(1)
+		go s.serveUDP()
+	default:
+		ln, err := net.Listen("tcp", s.conf.BindAddress)
+		if err != nil {
+			return err
+		}
+		s.ln, s.addr = ln, ln.Addr()
+		s.wg.Add(1)
+		go s.serveTCP()
+	}
+
+	s.Logger.Info("Listening on graphite", zap.String("protocol", s.conf.Protocol), zap.String("addr", s.addr.String()))
+	return s.Listener.Open()
+}
+
+func (s *Service) Close() error {
+	// no connection is added once closing is set
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+
+	if s.ln != nil {
+		_ = s.ln.Close()
+	}
+	if s.udpConn != nil {
+		_ = s.udpConn.Close()
+	}
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return s.Listener.Close()
+}
+
+// Addr returns the address the service listens on
+func (s *Service) Addr() net.Addr {
+	return s.addr
+}
+
+func (s *Service) serveTCP() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+
+		s.mu.Lock()
+		if s.closing {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.handleTCPConn(conn)
+	}
+}
+
+func (s *Service) handleTCPConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+		atomic.AddInt64(&s.Stats.ActiveConnections, -1)
+	}()
+	atomic.AddInt64(&s.Stats.ActiveConnections, 1)
+	atomic.AddInt64(&s.Stats.HandledConnections, 1)
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		atomic.AddInt64(&s.Stats.BytesReceived, int64(len(line)+1))
+		s.handleLine(line)
+	}
+	if err := scanner.Err(); err == bufio.ErrTooLong {
+		s.Logger.Warn("graphite line too long, connection closed", zap.Int("maxLineSize", maxLineSize),
+			zap.String("remote", conn.RemoteAddr().String()))
+	} else if err != nil {
+		s.Logger.Debug("graphite connection closed", zap.Error(err))
+	}
+}
+
+func (s *Service) serveUDP() {
+	defer s.wg.Done()
+	buf := make([]byte, maxUDPPayload)
+	for {
+		n, _, err := s.udpConn.ReadFromUDP(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		atomic.AddInt64(&s.Stats.BytesReceived, int64(n))
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			s.handleLine(line)
+		}
+	}
+}
+
+func (s *Service) handleLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	p, err := s.parser.Parse(line)
+	if err != nil {
+		atomic.AddInt64(&s.Stats.PointsParseFail, 1)
+		// NaN and Inf are sent by some collectors for missing values, they are dropped silently
+		if _, ok := err.(*graphite.UnsupportedValueError); !ok {
+			s.Logger.Debug("failed to parse graphite line", zap.String("line", line), zap.Error(err))
+		}
+		return
+	}
+
+	row, err := pointToRow(p)
+	if err != nil {
+		atomic.AddInt64(&s.Stats.PointsParseFail, 1)
+		s.Logger.Debug("invalid graphite point", zap.String("line", line), zap.Error(err))
+		return
+	}
+	s.AddRow(row)
+}
+
+func pointToRow(p models.Point) (influx.Row, error) {
+	row := influx.Row{
+		Name:      string(p.Name()),
+		Timestamp: p.UnixNano(),
+	}
+
+	// models.Tags are sorted by key
+	for _, tag := range p.Tags() {
+		row.Tags = append(row.Tags, influx.Tag{Key: string(tag.Key), Value: string(tag.Value)})
+	}
+
+	fields, err := p.Fields()
+	if err != nil {
+		return row, err
+	}
+	for k, v := range fields {
+		// graphite values are always parsed as float
+		if f, ok := v.(float64); ok {
+			row.Fields = append(row.Fields, influx.Field{Key: k, NumValue: f, Type: influx.Field_Type_Float})
+		}
+	}
+	return row, row.CheckValid()
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphite_test
+
+import (
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+	"github.com/openGemini/openGemini/lib/config"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/services/graphite"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMetaClient struct {
+	databases []string
+}
+
+func (c *mockMetaClient) CreateDatabase(name string) (*meta2.DatabaseInfo, error) {
+	c.databases = append(c.databases, name)
+	return &meta2.DatabaseInfo{Name: name}, nil
+}
+
+type mockPointsWriter struct {
+	mu   sync.Mutex
+	db   string
+	rp   string
+	rows []influx.Row
+}
+
+func (w *mockPointsWriter) WritePointRows(database, retentionPolicy string, rows []influx.Row) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.db, w.rp = database, retentionPolicy
+	w.rows = append(w.rows, rows...)
+	return nil
+}
+
+func (w *mockPointsWriter) wait(t *testing.T, n int) []influx.Row {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		w.mu.Lock()
+		if len(w.rows) >= n {
+			rows := w.rows
+			w.mu.Unlock()
+			return rows
+		}
+		w.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for %d rows", n)
+	return nil
+}
+
+func newTestService(t *testing.T, protocol string) (*graphite.Service, *mockPointsWriter, *mockMetaClient) {
+	c := config.NewGraphite()
+	c.Enabled = true
+	c.BindAddress = "127.0.0.1:0"
+	c.Protocol = protocol
+	c.BatchSize = 2
+	c.BatchTimeout = toml.Duration(50 * time.Millisecond)
+	c.RetentionPolicy = "rp0"
+	c.Tags = []string{"region=east"}
+	c.Templates = []string{"cpu.* .host.measurement.field*", "measurement.field"}
+	require.NoError(t, c.Validate())
+
+	s, err := graphite.NewService(c)
+	require.NoError(t, err)
+	w, mc := &mockPointsWriter{}, &mockMetaClient{}
+	s.PointsWriter, s.MetaClient = w, mc
+	require.NoError(t, s.Open())
+	return s, w, mc
+}
+
+func TestService_TCP(t *testing.T) {
+	s, w, mc := newTestService(t, "tcp")
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("cpu.server01.load.idle 1.5 1600000000\n" +
+		"mem.used 10 1600000001\n" +
+		"bad line\n" +
+		"mem.used NaN 1600000002\n" +
+		"disk.free 3 1600000003"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	// the first two rows fill a batch, the last one is flushed by the batch timeout
+	rows := w.wait(t, 3)
+	require.Equal(t, "graphite", w.db)
+	require.Equal(t, "rp0", w.rp)
+	require.Equal(t, []string{"graphite"}, mc.databases)
+
+	require.Equal(t, "load", rows[0].Name)
+	require.Equal(t, influx.PointTags{{Key: "host", Value: "server01"}, {Key: "region", Value: "east"}}, rows[0].Tags)
+	require.Equal(t, influx.Fields{{Key: "idle", NumValue: 1.5, Type: influx.Field_Type_Float}}, rows[0].Fields)
+	require.Equal(t, int64(1600000000)*int64(time.Second), rows[0].Timestamp)
+
+	require.Equal(t, "mem", rows[1].Name)
+	require.Equal(t, "used", rows[1].Fields[0].Key)
+	require.Equal(t, "disk", rows[2].Name)
+	require.Equal(t, 3.0, rows[2].Fields[0].NumValue)
+
+	require.NoError(t, s.Close())
+	require.Equal(t, int64(2), s.Stats.PointsParseFail)
+	require.Equal(t, int64(3), s.Stats.PointsWritten)
+}
+
+func TestService_TCPLineTooLong(t *testing.T) {
+	s, w, _ := newTestService(t, "tcp")
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("mem.used 10 1600000001\n"))
+	require.NoError(t, err)
+	rows := w.wait(t, 1)
+	require.Equal(t, "mem", rows[0].Name)
+
+	// the connection is closed instead of buffering the line
+	_, _ = conn.Write([]byte("mem.used " + strings.Repeat("1", 128*1024) + " 1600000002\nmem.free 1 1600000003\n"))
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	// closed with unread data, the peer may see a reset instead of EOF
+	_, err = io.Copy(io.Discard, conn)
+	if ne, ok := err.(net.Error); ok {
+		require.False(t, ne.Timeout())
+	}
+
+	require.NoError(t, s.Close())
+	require.Equal(t, int64(1), s.Stats.PointsWritten)
+}
+
+func TestService_UDP(t *testing.T) {
+	s, w, _ := newTestService(t, "udp")
+
+	conn, err := net.Dial("udp", s.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("mem.used 10 1600000001\nmem.free 20 1600000001\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	rows := w.wait(t, 2)
+	require.Equal(t, "used", rows[0].Fields[0].Key)
+	require.Equal(t, "free", rows[1].Fields[0].Key)
+	require.NoError(t, s.Close())
+}
+
+func TestNewService_InvalidTemplate(t *testing.T) {
+	c := config.NewGraphite()
+	c.Templates = []string{"cpu.* .host.field"}
+	_, err := graphite.NewService(c)
+	require.Error(t, err)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listener
+
+import (
+	"sync"
+
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+)
+
+// PointBatcher groups the rows received by a listener, a batch is written when it is full
+// or when Flush is called, which the listeners do every batch timeout.
+type PointBatcher struct {
+	mu    sync.Mutex
+	rows  []influx.Row
+	size  int
+	write func(rows []influx.Row)
+}
+
+func NewPointBatcher(size int, write func(rows []influx.Row)) *PointBatcher {
+	return &PointBatcher{
+		rows:  make([]influx.Row, 0, size),
+		size:  size,
+		write: write,
+	}
+}
+
+// Add appends a row, the caller is blocked while a full batch is written.
+func (b *PointBatcher) Add(row influx.Row) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rows = append(b.rows, row)
+	if len(b.rows) >= b.size {
+		b.flush()
+	}
+}
+
+func (b *PointBatcher) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flush()
+}
+
+func (b *PointBatcher) flush() {
+	if len(b.rows) == 0 {
+		return
+	}
+	// the rows are owned by the writer, they may be modified while writing
+	b.write(b.rows)
+	b.rows = make([]influx.Row, 0, b.size)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listener
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/statisticsPusher/statistics"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/services"
+	"go.uber.org/zap"
+)
+
+// Listener is the base of the protocol listeners, such as graphite and opentsdb.
+// The received rows are written in batches into the database of the listener,
+// the pending batch is flushed every batch timeout.
+type Listener struct {
+	services.Base
+
+	MetaClient interface {
+		CreateDatabase(name string) (*meta2.DatabaseInfo, error)
+	}
+
+	PointsWriter interface {
+		WritePointRows(database, retentionPolicy string, rows []influx.Row) error
+	}
+
+	Stats *statistics.ListenerStatistics
+
+	database        string
+	retentionPolicy string
+	batcher         *PointBatcher
+	// the database is created before the first write, guarded by the batcher
+	dbCreated bool
+}
+
+func (l *Listener) InitListener(name, bindAddress, database, retentionPolicy string, batchSize int, batchTimeout time.Duration) {
+	l.Init(name, batchTimeout, l.Flush)
+	l.Logger = l.Logger.With(zap.String("bind", bindAddress))
+	l.database = database
+	l.retentionPolicy = retentionPolicy
+	l.batcher = NewPointBatcher(batchSize, l.writeRows)
+	l.Stats = statistics.NewListenerStatistics(name, bindAddress)
+}
+
+// AddRow adds a received row to the pending batch
+func (l *Listener) AddRow(row influx.Row) {
+	atomic.AddInt64(&l.Stats.PointsReceived, 1)
+	l.batcher.Add(row)
+}
+
+// Flush writes the pending batch
+func (l *Listener) Flush() {
+	l.batcher.Flush()
+}
+
+// Close stops the batch timer and writes the pending batch, the listener stops accepting first.
+func (l *Listener) Close() error {
+	err := l.Base.Close()
+	l.Flush()
+	statistics.UnregisterListenerStatistics(l.Stats)
+	return err
+}
+
+func (l *Listener) writeRows(rows []influx.Row) {
+	if !l.dbCreated {
+		if _, err := l.MetaClient.CreateDatabase(l.database); err != nil {
+			l.Logger.Error("failed to create database", zap.String("db", l.database), zap.Error(err))
+			l.writeFailed(len(rows))
+			return
+		}
+		l.dbCreated = true
+	}
+
+	if err := l.PointsWriter.WritePointRows(l.database, l.retentionPolicy, rows); err != nil {
+		l.Logger.Error("failed to write points", zap.String("db", l.database), zap.Int("points", len(rows)), zap.Error(err))
+		l.writeFailed(len(rows))
+		return
+	}
+	atomic.AddInt64(&l.Stats.BatchesWritten, 1)
+	atomic.AddInt64(&l.Stats.PointsWritten, int64(len(rows)))
+}
+
+func (l *Listener) writeFailed(n int) {
+	atomic.AddInt64(&l.Stats.BatchesWriteFail, 1)
+	atomic.AddInt64(&l.Stats.PointsWriteFail, int64(n))
+}
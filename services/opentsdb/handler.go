@@ -0,0 +1,121 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentsdb
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Handler serves the opentsdb http api
+type Handler struct {
+	service *Service
+}
+
+// point is a data point of /api/put
+type point struct {
+	Metric string            `json:"metric"`
+	Time   int64             `json:"timestamp"`
+	Value  float64           `json:"value"`
+	Tags   map[string]string `json:"tags,omitempty"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/metadata/put":
+		w.WriteHeader(http.StatusNoContent)
+	case "/api/put":
+		h.servePut(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// servePut writes the data points, a single point or an array of points is accepted
+func (h *Handler) servePut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, "could not read gzip, "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gr.Close()
+		body = gr
+	}
+	cr := &countReader{r: body}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(cr).Decode(&raw); err != nil {
+		http.Error(w, "json error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	atomic.AddInt64(&h.service.Stats.BytesReceived, cr.n)
+
+	var points []point
+	if len(raw) > 0 && raw[0] == '[' {
+		if err := json.Unmarshal(raw, &points); err != nil {
+			http.Error(w, "json array decode error: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		var p point
+		if err := json.Unmarshal(raw, &p); err != nil {
+			http.Error(w, "json object decode error: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		points = append(points, p)
+	}
+
+	for i := range points {
+		p := &points[i]
+		// the timestamp is in seconds when less than 10 digits, otherwise in milliseconds
+		ts := p.Time * int64(time.Millisecond)
+		if p.Time < 10000000000 {
+			ts = p.Time * int64(time.Second)
+		}
+
+		row, err := newRow(p.Metric, ts, p.Value, p.Tags)
+		if err != nil {
+			b, _ := json.Marshal(p)
+			h.service.pointError(string(b), err)
+			continue
+		}
+		h.service.AddRow(row)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentsdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openGemini/openGemini/lib/config"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/openGemini/openGemini/services/listener"
+	"go.uber.org/zap"
+)
+
+const (
+	// ValueField is the field of the points received, opentsdb data points have a single value
+	ValueField = "value"
+
+	telnetPut = "put "
+)
+
+var errListenerClosed = errors.New("listener closed")
+
+// Service receives the opentsdb telnet put command and the http /api/put endpoint on the same port,
+// the connections are told apart by their first bytes.
+type Service struct {
+	listener.Listener
+
+	conf config.OpenTSDB
+
+	ln     net.Listener
+	httpLn *chanListener
+	server *http.Server
+
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func NewService(c config.OpenTSDB) *Service {
+	s := &Service{
+		conf:  c,
+		conns: make(map[net.Conn]struct{}),
+	}
+	s.InitListener("opentsdb", c.BindAddress, c.Database, c.RetentionPolicy, c.BatchSize, time.Duration(c.BatchTimeout))
+	return s
+}
+
+func (s *Service) Open() error {
+	ln, err := net.Listen("tcp", s.conf.BindAddress)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	s.httpLn = newChanListener(ln.Addr())
+	s.server = &http.Server{Handler: &Handler{service: s}}
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		_ = s.server.Serve(s.httpLn)
+	}()
+	go s.serve()
+
+	s.Logger.Info("Listening on opentsdb", zap.String("addr", ln.Addr().String()))
+	return s.Listener.Open()
+}
+
+func (s *Service) Close() error {
+	if s.ln != nil {
+		_ = s.ln.Close()
+	}
+	if s.server != nil {
+		_ = s.server.Close()
+	}
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return s.Listener.Close()
+}
+
+// Addr returns the address the service listens on
+func (s *Service) Addr() net.Addr {
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
+
+func (s *Service) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		s.wg.Add(1)
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Service) handleConn(conn net.Conn) {
+	defer s.wg.Done()
+	atomic.AddInt64(&s.Stats.HandledConnections, 1)
+
+	s.trackConn(conn, true)
+	r := bufio.NewReader(conn)
+	head, err := r.Peek(len(telnetPut))
+	if err != nil && len(head) == 0 {
+		s.trackConn(conn, false)
+		_ = conn.Close()
+		return
+	}
+	if string(head) != telnetPut {
+		// the http server closes the connection from now on
+		s.trackConn(conn, false)
+		s.httpLn.handoff(&readerConn{Conn: conn, r: r})
+		return
+	}
+
+	atomic.AddInt64(&s.Stats.ActiveConnections, 1)
+	defer func() {
+		s.trackConn(conn, false)
+		_ = conn.Close()
+		atomic.AddInt64(&s.Stats.ActiveConnections, -1)
+	}()
+	s.handleTelnet(r)
+}
+
+// trackConn records the connections closed by Close
+func (s *Service) trackConn(conn net.Conn, add bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if add {
+		s.conns[conn] = struct{}{}
+	} else {
+		delete(s.conns, conn)
+	}
+}
+
+func (s *Service) handleTelnet(r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		atomic.AddInt64(&s.Stats.BytesReceived, int64(len(line)))
+		if line = strings.TrimSpace(line); line != "" {
+			row, perr := parseTelnetPut(line)
+			if perr != nil {
+				s.pointError(line, perr)
+			} else {
+				s.AddRow(row)
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				s.Logger.Debug("opentsdb connection closed", zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+func (s *Service) pointError(point string, err error) {
+	atomic.AddInt64(&s.Stats.PointsParseFail, 1)
+	if s.conf.LogPointErrors {
+		s.Logger.Info("invalid opentsdb point", zap.String("point", point), zap.Error(err))
+	}
+}
+
+// parseTelnetPut parses "put <metric> <timestamp> <value> <tagk1=tagv1 ...>"
+func parseTelnetPut(line string) (influx.Row, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != strings.TrimSpace(telnetPut) {
+		return influx.Row{}, fmt.Errorf("malformed put command")
+	}
+
+	ts, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return influx.Row{}, fmt.Errorf("invalid timestamp %q", fields[2])
+	}
+	// the timestamp is in seconds or in milliseconds
+	switch len(fields[2]) {
+	case 10:
+		ts *= int64(time.Second)
+	case 13:
+		ts *= int64(time.Millisecond)
+	default:
+		return influx.Row{}, fmt.Errorf("timestamp %q must be 10 or 13 digits", fields[2])
+	}
+
+	value, err := strconv.ParseFloat(fields[3], 64)
+	if err != nil {
+		return influx.Row{}, fmt.Errorf("invalid value %q", fields[3])
+	}
+
+	tags := make(map[string]string, len(fields)-4)
+	for _, kv := range fields[4:] {
+		i := strings.IndexByte(kv, '=')
+		if i <= 0 || i == len(kv)-1 {
+			return influx.Row{}, fmt.Errorf("malformed tag %q", kv)
+		}
+		tags[kv[:i]] = kv[i+1:]
+	}
+	return newRow(fields[1], ts, value, tags)
+}
+
+func newRow(metric string, ts int64, value float64, tags map[string]string) (influx.Row, error) {
+	row := influx.Row{
+		Name:      metric,
+		Timestamp: ts,
+		Fields:    influx.Fields{{Key: ValueField, NumValue: value, Type: influx.Field_Type_Float}},
+	}
+	for k, v := range tags {
+		row.Tags = append(row.Tags, influx.Tag{Key: k, Value: v})
+	}
+	sort.Sort(&row.Tags)
+	return row, row.CheckValid()
+}
+
+// chanListener accepts the connections handed off by the service
+type chanListener struct {
+	addr net.Addr
+	ch   chan net.Conn
+	once sync.Once
+	done chan struct{}
+}
+
+func newChanListener(addr net.Addr) *chanListener {
+	return &chanListener{
+		addr: addr,
+		ch:   make(chan net.Conn),
+		done: make(chan struct{}),
+	}
+}
+
+func (l *chanListener) handoff(conn net.Conn) {
+	select {
+	case l.ch <- conn:
+	case <-l.done:
+		_ = conn.Close()
+	}
+}
+
+func (l *chanListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.ch:
+		return conn, nil
+	case <-l.done:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *chanListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *chanListener) Addr() net.Addr {
+	return l.addr
+}
+
+// readerConn reads the bytes peeked by the service before the connection
+type readerConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *readerConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 Huawei Cloud Computing Technologies Co., Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentsdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+	"github.com/openGemini/openGemini/lib/config"
+	meta2 "github.com/openGemini/openGemini/open_src/influx/meta"
+	"github.com/openGemini/openGemini/open_src/vm/protoparser/influx"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMetaClient struct{}
+
+func (c *mockMetaClient) CreateDatabase(name string) (*meta2.DatabaseInfo, error) {
+	return &meta2.DatabaseInfo{Name: name}, nil
+}
+
+type mockPointsWriter struct {
+	mu   sync.Mutex
+	rows []influx.Row
+}
+
+func (w *mockPointsWriter) WritePointRows(_, _ string, rows []influx.Row) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rows = append(w.rows, rows...)
+	return nil
+}
+
+func (w *mockPointsWriter) wait(t *testing.T, n int) []influx.Row {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		w.mu.Lock()
+		if len(w.rows) >= n {
+			rows := w.rows
+			w.mu.Unlock()
+			return rows
+		}
+		w.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for %d rows", n)
+	return nil
+}
+
+func newTestService(t *testing.T) (*Service, *mockPointsWriter) {
+	c := config.NewOpenTSDB()
+	c.Enabled = true
+	c.BindAddress = "127.0.0.1:0"
+	c.BatchTimeout = toml.Duration(50 * time.Millisecond)
+	require.NoError(t, c.Validate())
+
+	s := NewService(c)
+	w := &mockPointsWriter{}
+	s.PointsWriter, s.MetaClient = w, &mockMetaClient{}
+	require.NoError(t, s.Open())
+	return s, w
+}
+
+func TestService_Telnet(t *testing.T) {
+	s, w := newTestService(t)
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("put sys.cpu 1600000000 42.5 host=a dc=east\n" +
+		"put sys.cpu 1600000000001 1 host=b\n" +
+		"put sys.cpu 16000 1 host=b\n"))
+	require.NoError(t, err)
+
+	rows := w.wait(t, 2)
+	require.Equal(t, "sys.cpu", rows[0].Name)
+	require.Equal(t, influx.PointTags{{Key: "dc", Value: "east"}, {Key: "host", Value: "a"}}, rows[0].Tags)
+	require.Equal(t, influx.Fields{{Key: ValueField, NumValue: 42.5, Type: influx.Field_Type_Float}}, rows[0].Fields)
+	require.Equal(t, int64(1600000000)*int64(time.Second), rows[0].Timestamp)
+	require.Equal(t, int64(1600000000001)*int64(time.Millisecond), rows[1].Timestamp)
+	require.NoError(t, conn.Close())
+}
+
+func TestService_HTTP(t *testing.T) {
+	s, w := newTestService(t)
+	url := "http://" + s.Addr().String() + "/api/put"
+
+	resp, err := http.Post(url, "application/json",
+		bytes.NewBufferString(`{"metric":"sys.mem","timestamp":1600000000,"value":1,"tags":{"host":"a"}}`))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write([]byte(`[{"metric":"sys.mem","timestamp":1600000000001,"value":2},{"metric":"","timestamp":1,"value":3}]`))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	req, err := http.NewRequest(http.MethodPost, url, &buf)
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = http.Post(url, "application/json", bytes.NewBufferString(`{"metric"`))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	rows := w.wait(t, 2)
+	require.Equal(t, "sys.mem", rows[0].Name)
+	require.Equal(t, influx.PointTags{{Key: "host", Value: "a"}}, rows[0].Tags)
+	require.Equal(t, 2.0, rows[1].Fields[0].NumValue)
+	require.NoError(t, s.Close())
+	require.Equal(t, int64(1), s.Stats.PointsParseFail)
+}
+
+func TestParseTelnetPut(t *testing.T) {
+	for _, line := range []string{
+		"put sys.cpu 1600000000",
+		"put sys.cpu abc 1",
+		"put sys.cpu 1600000000 abc",
+		"put sys.cpu 1600000000 1 host",
+		"get sys.cpu 1600000000 1",
+	} {
+		_, err := parseTelnetPut(line)
+		require.Error(t, err, line)
+	}
+}
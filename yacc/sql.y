@@ -58,6 +58,34 @@ func deal_Fill (fill interface{})  (influxql.FillOption , interface{},bool) {
 	}
 }
 
+func privilegeByName(yylex yyLexer, name string) influxql.Privilege {
+	switch strings.ToLower(name) {
+	case "read":
+		return influxql.ReadPrivilege
+	case "write":
+		return influxql.WritePrivilege
+	default:
+		yylex.Error("wrong Privilege")
+		return influxql.NoPrivileges
+	}
+}
+
+// userPrivilegeScope returns the database of a privilege granted to a user.
+// Retention policy and measurement scopes can only be granted to roles.
+func userPrivilegeScope(yylex yyLexer, scope *influxql.Measurement) string {
+	if scope.RetentionPolicy != "" || scope.Name != "" || scope.Regex != nil {
+		yylex.Error("retention policy and measurement privileges can only be granted to a role")
+	}
+	return scope.Database
+}
+
+func regexLiteral(yylex yyLexer, expr string) *influxql.RegexLiteral {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		yylex.Error("Invalid regexprs")
+	}
+	return &influxql.RegexLiteral{Val: re}
+}
 
 %}
 
@@ -90,6 +118,7 @@ func deal_Fill (fill interface{})  (influxql.FillOption , interface{},bool) {
     strSlice            []string
     location            *time.Location
     indexType           *IndexType
+    tokenScopes         []influxql.TokenScope
 }
 
 %token <str>    FROM MEASUREMENT ON SELECT WHERE AS GROUP BY ORDER LIMIT OFFSET SLIMIT SOFFSET SHOW CREATE FULL PRIVILEGES OUTER JOIN
@@ -97,7 +126,7 @@ func deal_Fill (fill interface{})  (influxql.FillOption , interface{},bool) {
                 DATABASES DATABASE MEASUREMENTS RETENTION POLICIES POLICY DURATION DEFAULT SHARD INDEX GRANT HOT WARM TYPE SET FOR GRANTS
                 REPLICATION SERIES DROP CASE WHEN THEN ELSE END TRUE FALSE TAG FIELD KEYS VALUES KEY EXPLAIN ANALYZE EXACT CARDINALITY SHARDKEY
                 CONTINUOUS DIAGNOSTICS QUERIES QUERIE SHARDS STATS SUBSCRIPTIONS SUBSCRIPTION GROUPS INDEXTYPE INDEXLIST
-                QUERY PARTITION
+                QUERY PARTITION DECOMMISSION NODE ROLE ROLES TOKEN TOKENS
 %token <bool>   DESC ASC
 %token <str>    COMMA SEMICOLON LPAREN RPAREN REGEX
 %token <int>    EQ NEQ LT LTE GT GTE DOT DOUBLECOLON NEQREGEX EQREGEX
@@ -124,11 +153,14 @@ func deal_Fill (fill interface{})  (influxql.FillOption , interface{},bool) {
                                     SHOW_FIELD_KEY_CARDINALITY_STATEMENT CREATE_MEASUREMENT_STATEMENT DROP_SHARD_STATEMENT SET_PASSWORD_USER_STATEMENT
                                     SHOW_GRANTS_FOR_USER_STATEMENT SHOW_MEASUREMENT_CARDINALITY_STATEMENT SHOW_SERIES_CARDINALITY_STATEMENT SHOW_SHARDS_STATEMENT
                                     ALTER_SHARD_KEY_STATEMENT SHOW_SHARD_GROUPS_STATEMENT DROP_MEASUREMENT_STATEMENT
+                                    DECOMMISSION_NODE_STATEMENT CREATE_ROLE_STATEMENT DROP_ROLE_STATEMENT SHOW_ROLES_STATEMENT
+                                    SHOW_GRANTS_FOR_ROLE_STATEMENT CREATE_TOKEN_STATEMENT REVOKE_TOKEN_STATEMENT SHOW_TOKENS_STATEMENT
 %type <fields>                      COLUMN_CLAUSES IDENTS
 %type <field>                       COLUMN_CLAUSE
 %type <stmts>                       ALL_QUERIES ALL_QUERY
 %type <sources>                     FROM_CLAUSE TABLE_NAMES SUBQUERY_CLAUSE
 %type <ment>                        TABLE_OPTION JOIN_CLAUSES JOIN_CLAUSE TABLE_NAME_WITH_OPTION TABLE_CASE MEASUREMENT_WITH
+                                    PRIVILEGE_SCOPE
 %type <expr>                        WHERE_CLAUSE CONDITION OPERATION_EQUAL COLUMN_VAREF COLUMN CONDITION_COLUMN TAG_KEYS
 				    CASE_WHEN_CASE CASE_WHEN_CASES
 %type <int>                         CONDITION_OPERATOR
@@ -144,6 +176,7 @@ func deal_Fill (fill interface{})  (influxql.FillOption , interface{},bool) {
 %type <strSlice>                    SHARDKEYLIST INDEX_LIST
 %type <location>                    TIME_ZONE
 %type <indexType>                   INDEX_TYPE INDEX_TYPES
+%type <tokenScopes>                 TOKEN_SCOPES TOKEN_SCOPE
 %%
 
 ALL_QUERIES:
@@ -286,6 +319,38 @@ STATEMENT:
     {
         $$ = $1
     }
+    |DECOMMISSION_NODE_STATEMENT
+    {
+        $$ = $1
+    }
+    |CREATE_ROLE_STATEMENT
+    {
+        $$ = $1
+    }
+    |DROP_ROLE_STATEMENT
+    {
+        $$ = $1
+    }
+    |SHOW_ROLES_STATEMENT
+    {
+        $$ = $1
+    }
+    |SHOW_GRANTS_FOR_ROLE_STATEMENT
+    {
+        $$ = $1
+    }
+    |CREATE_TOKEN_STATEMENT
+    {
+        $$ = $1
+    }
+    |REVOKE_TOKEN_STATEMENT
+    {
+        $$ = $1
+    }
+    |SHOW_TOKENS_STATEMENT
+    {
+        $$ = $1
+    }
     |SET_PASSWORD_USER_STATEMENT
     {
         $$ = $1
@@ -1551,37 +1616,72 @@ DROP_RETENTION_POLICY_STATEMENT:
     }
 
 GRANT_STATEMENT:
-    GRANT ALL ON IDENT TO IDENT
+    GRANT ALL ON PRIVILEGE_SCOPE TO IDENT
     {
     	stmt := &influxql.GrantStatement{}
     	stmt.Privilege = influxql.AllPrivileges
-    	stmt.On = $4
+    	stmt.On = userPrivilegeScope(yylex, $4)
     	stmt.User = $6
     	$$ = stmt
     }
-    |GRANT ALL PRIVILEGES ON IDENT TO IDENT
+    |GRANT ALL PRIVILEGES ON PRIVILEGE_SCOPE TO IDENT
     {
     	stmt := &influxql.GrantStatement{}
     	stmt.Privilege = influxql.AllPrivileges
-    	stmt.On = $5
+    	stmt.On = userPrivilegeScope(yylex, $5)
     	stmt.User = $7
     	$$ = stmt
     }
-    |GRANT IDENT ON IDENT TO IDENT
+    |GRANT IDENT ON PRIVILEGE_SCOPE TO IDENT
     {
     	stmt := &influxql.GrantStatement{}
-    	switch strings.ToLower($2){
-    	case "read":
-    	    stmt.Privilege = influxql.ReadPrivilege
-    	case "write":
-    	    stmt.Privilege = influxql.WritePrivilege
-    	default:
-    	    yylex.Error("wrong Privilege")
-    	}
-    	stmt.On = $4
+    	stmt.Privilege = privilegeByName(yylex, $2)
+    	stmt.On = userPrivilegeScope(yylex, $4)
     	stmt.User = $6
     	$$ = stmt
     }
+    |GRANT ALL ON PRIVILEGE_SCOPE TO ROLE IDENT
+    {
+    	$$ = &influxql.GrantToRoleStatement{Privilege: influxql.AllPrivileges, On: $4, Role: $7}
+    }
+    |GRANT ALL PRIVILEGES ON PRIVILEGE_SCOPE TO ROLE IDENT
+    {
+    	$$ = &influxql.GrantToRoleStatement{Privilege: influxql.AllPrivileges, On: $5, Role: $8}
+    }
+    |GRANT IDENT ON PRIVILEGE_SCOPE TO ROLE IDENT
+    {
+    	$$ = &influxql.GrantToRoleStatement{Privilege: privilegeByName(yylex, $2), On: $4, Role: $7}
+    }
+    |GRANT ROLE IDENT TO IDENT
+    {
+    	$$ = &influxql.GrantRoleStatement{Role: $3, User: $5}
+    }
+
+PRIVILEGE_SCOPE:
+    IDENT
+    {
+    	$$ = &influxql.Measurement{Database: $1}
+    }
+    |IDENT RETENTION POLICY IDENT
+    {
+    	$$ = &influxql.Measurement{Database: $1, RetentionPolicy: $4}
+    }
+    |IDENT MEASUREMENT IDENT
+    {
+    	$$ = &influxql.Measurement{Database: $1, Name: $3}
+    }
+    |IDENT MEASUREMENT REGULAR_EXPRESSION
+    {
+    	$$ = &influxql.Measurement{Database: $1, Regex: regexLiteral(yylex, $3)}
+    }
+    |IDENT RETENTION POLICY IDENT MEASUREMENT IDENT
+    {
+    	$$ = &influxql.Measurement{Database: $1, RetentionPolicy: $4, Name: $6}
+    }
+    |IDENT RETENTION POLICY IDENT MEASUREMENT REGULAR_EXPRESSION
+    {
+    	$$ = &influxql.Measurement{Database: $1, RetentionPolicy: $4, Regex: regexLiteral(yylex, $6)}
+    }
 
 GRANT_ADMIN_STATEMENT:
     GRANT ALL PRIVILEGES TO IDENT
@@ -1594,37 +1694,46 @@ GRANT_ADMIN_STATEMENT:
     }
 
 REVOKE_STATEMENT:
-    REVOKE ALL ON IDENT FROM IDENT
+    REVOKE ALL ON PRIVILEGE_SCOPE FROM IDENT
     {
     	stmt := &influxql.RevokeStatement{}
     	stmt.Privilege = influxql.AllPrivileges
-    	stmt.On = $4
+    	stmt.On = userPrivilegeScope(yylex, $4)
     	stmt.User = $6
     	$$ = stmt
     }
-    |REVOKE ALL PRIVILEGES ON IDENT FROM IDENT
+    |REVOKE ALL PRIVILEGES ON PRIVILEGE_SCOPE FROM IDENT
     {
     	stmt := &influxql.RevokeStatement{}
     	stmt.Privilege = influxql.AllPrivileges
-    	stmt.On = $5
+    	stmt.On = userPrivilegeScope(yylex, $5)
     	stmt.User = $7
     	$$ = stmt
     }
-    |REVOKE IDENT ON IDENT FROM IDENT
+    |REVOKE IDENT ON PRIVILEGE_SCOPE FROM IDENT
     {
     	stmt := &influxql.RevokeStatement{}
-    	switch strings.ToLower($2){
-    	case "read":
-    	    stmt.Privilege = influxql.ReadPrivilege
-    	case "write":
-    	    stmt.Privilege = influxql.WritePrivilege
-    	default:
-    	    yylex.Error("wrong Privilege")
-    	}
-    	stmt.On = $4
+    	stmt.Privilege = privilegeByName(yylex, $2)
+    	stmt.On = userPrivilegeScope(yylex, $4)
     	stmt.User = $6
     	$$ = stmt
     }
+    |REVOKE ALL ON PRIVILEGE_SCOPE FROM ROLE IDENT
+    {
+    	$$ = &influxql.RevokeFromRoleStatement{Privilege: influxql.AllPrivileges, On: $4, Role: $7}
+    }
+    |REVOKE ALL PRIVILEGES ON PRIVILEGE_SCOPE FROM ROLE IDENT
+    {
+    	$$ = &influxql.RevokeFromRoleStatement{Privilege: influxql.AllPrivileges, On: $5, Role: $8}
+    }
+    |REVOKE IDENT ON PRIVILEGE_SCOPE FROM ROLE IDENT
+    {
+    	$$ = &influxql.RevokeFromRoleStatement{Privilege: privilegeByName(yylex, $2), On: $4, Role: $7}
+    }
+    |REVOKE ROLE IDENT FROM IDENT
+    {
+    	$$ = &influxql.RevokeRoleStatement{Role: $3, User: $5}
+    }
 
 REVOKE_ADMIN_STATEMENT:
     REVOKE ALL PRIVILEGES FROM IDENT
@@ -2095,6 +2204,14 @@ DROP_SHARD_STATEMENT:
         $$ = stmt
     }
 
+DECOMMISSION_NODE_STATEMENT:
+    DECOMMISSION NODE INTEGER
+    {
+        stmt := &influxql.DecommissionNodeStatement{}
+        stmt.NodeID = uint64($3)
+        $$ = stmt
+    }
+
 SET_PASSWORD_USER_STATEMENT:
     SET PASSWORD FOR IDENT EQ STRING
     {
@@ -2114,6 +2231,84 @@ SHOW_GRANTS_FOR_USER_STATEMENT:
         $$ = stmt
     }
 
+SHOW_GRANTS_FOR_ROLE_STATEMENT:
+    SHOW GRANTS FOR ROLE IDENT
+    {
+        $$ = &influxql.ShowGrantsForRoleStatement{Name: $5}
+    }
+
+CREATE_ROLE_STATEMENT:
+    CREATE ROLE IDENT
+    {
+        $$ = &influxql.CreateRoleStatement{Name: $3}
+    }
+
+DROP_ROLE_STATEMENT:
+    DROP ROLE IDENT
+    {
+        $$ = &influxql.DropRoleStatement{Name: $3}
+    }
+
+SHOW_ROLES_STATEMENT:
+    SHOW ROLES
+    {
+        $$ = &influxql.ShowRolesStatement{}
+    }
+
+CREATE_TOKEN_STATEMENT:
+    CREATE TOKEN IDENT FOR IDENT
+    {
+        $$ = &influxql.CreateTokenStatement{Name: $3, User: $5}
+    }
+    |CREATE TOKEN IDENT FOR IDENT DURATION DURATIONVAL
+    {
+        $$ = &influxql.CreateTokenStatement{Name: $3, User: $5, Duration: $7}
+    }
+    |CREATE TOKEN IDENT FOR IDENT WITH TOKEN_SCOPES
+    {
+        $$ = &influxql.CreateTokenStatement{Name: $3, User: $5, Scopes: $7}
+    }
+    |CREATE TOKEN IDENT FOR IDENT WITH TOKEN_SCOPES DURATION DURATIONVAL
+    {
+        $$ = &influxql.CreateTokenStatement{Name: $3, User: $5, Scopes: $7, Duration: $9}
+    }
+
+TOKEN_SCOPES:
+    TOKEN_SCOPE
+    {
+        $$ = $1
+    }
+    |TOKEN_SCOPES COMMA TOKEN_SCOPE
+    {
+        $$ = append($1, $3...)
+    }
+
+TOKEN_SCOPE:
+    ALL ON IDENT
+    {
+        $$ = []influxql.TokenScope{{Database: $3, Privilege: influxql.AllPrivileges}}
+    }
+    |IDENT ON IDENT
+    {
+        $$ = []influxql.TokenScope{{Database: $3, Privilege: privilegeByName(yylex, $1)}}
+    }
+
+REVOKE_TOKEN_STATEMENT:
+    REVOKE TOKEN IDENT
+    {
+        $$ = &influxql.RevokeTokenStatement{Name: $3}
+    }
+
+SHOW_TOKENS_STATEMENT:
+    SHOW TOKENS
+    {
+        $$ = &influxql.ShowTokensStatement{}
+    }
+    |SHOW TOKENS FOR IDENT
+    {
+        $$ = &influxql.ShowTokensStatement{User: $4}
+    }
+
 SHOW_MEASUREMENT_CARDINALITY_STATEMENT:
     SHOW MEASUREMENT EXACT CARDINALITY ON_DATABASE FROM_CLAUSE WHERE_CLAUSE GROUP_BY_CLAUSE LIMIT_OFFSET_OPTION
     {
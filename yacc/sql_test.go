@@ -120,6 +120,7 @@ func init() {
 		"SHOW MEASUREMENT EXACT CARDINALITY on db0",                  //add SHOW MEASUREMENT EXACT CARDINALITY
 		"SHOW GRANTS FOR db",                                         //add SHOW GRANTS
 		"DROP SHARD 3",                                               //add DROP SHARD
+		"DECOMMISSION NODE 2",                                        //add DECOMMISSION NODE
 		"set password for user3 = 'guass_345'",                       //add SET PASSWORD
 		"CREATE MEASUREMENT db0",                                     //add CREATE MEASUREMENT
 		"select * from db where a>0 tz('UTC')",                       //add time zone
@@ -131,6 +132,17 @@ func init() {
 		"create measurement cpu with indextype text indexlist msg text1 indexlist msg1,msg2",
 		"create measurement TSDB_SIT_AlterMeasurement_BaseFunction_002 with shardkey tag1,tag2",
 		"create user xxxxx with password 'xxxx' with partition privileges", // add partition privileges.
+		"CREATE ROLE reader",
+		"DROP ROLE reader",
+		"SHOW ROLES",
+		"SHOW GRANTS FOR ROLE reader",
+		"GRANT ROLE reader TO user1",
+		"REVOKE ROLE reader FROM user1",
+		"GRANT READ ON db0 RETENTION POLICY rp0 MEASUREMENT /^cpu/ TO ROLE reader",
+		"REVOKE ALL PRIVILEGES ON db0 MEASUREMENT mem FROM ROLE reader",
+		"CREATE TOKEN collector FOR user1 WITH WRITE ON db0 DURATION 30d",
+		"SHOW TOKENS",
+		"REVOKE TOKEN collector",
 	}
 
 	benchCases = []string{
@@ -324,3 +336,80 @@ func BenchmarkPreviousParser(b *testing.B) {
 		}
 	}
 }
+
+func TestRoleStatements(t *testing.T) {
+	for sql, want := range map[string]string{
+		"create role reader":                                                  `CREATE ROLE reader`,
+		"drop role reader":                                                    `DROP ROLE reader`,
+		"show roles":                                                          `SHOW ROLES`,
+		"show grants for role reader":                                         `SHOW GRANTS FOR ROLE reader`,
+		"grant role reader to user1":                                          `GRANT ROLE reader TO user1`,
+		"revoke role reader from user1":                                       `REVOKE ROLE reader FROM user1`,
+		"grant read on db0 to role reader":                                    `GRANT READ ON db0 TO ROLE reader`,
+		"grant all on db0 retention policy rp0 to role reader":                `GRANT ALL PRIVILEGES ON db0 RETENTION POLICY rp0 TO ROLE reader`,
+		"grant write on db0 measurement cpu to role writer":                   `GRANT WRITE ON db0 MEASUREMENT cpu TO ROLE writer`,
+		"grant read on db0 retention policy rp0 measurement /^cpu/ to role r": `GRANT READ ON db0 RETENTION POLICY rp0 MEASUREMENT /^cpu/ TO ROLE r`,
+		"revoke read on db0 retention policy rp0 from role r":                 `REVOKE READ ON db0 RETENTION POLICY rp0 FROM ROLE r`,
+		"grant read on db0 to user1":                                          `GRANT READ ON db0 TO user1`,
+	} {
+		YyParser := &yacc.YyParser{Query: influxql.Query{}}
+		YyParser.Scanner = influxql.NewScanner(strings.NewReader(sql))
+		YyParser.ParseTokens()
+		q, err := YyParser.GetQuery()
+		if err != nil {
+			t.Fatalf("parse %q: %v", sql, err)
+		}
+		if got := q.Statements[0].String(); got != want {
+			t.Fatalf("parse %q: got %q, want %q", sql, got, want)
+		}
+	}
+
+	for _, sql := range []string{
+		"grant read on db0 retention policy rp0 to user1",
+		"grant read on db0 measurement cpu to user1",
+		"grant read on db0 measurement /[/ to role r",
+	} {
+		YyParser := &yacc.YyParser{Query: influxql.Query{}}
+		YyParser.Scanner = influxql.NewScanner(strings.NewReader(sql))
+		YyParser.ParseTokens()
+		if _, err := YyParser.GetQuery(); err == nil {
+			t.Fatalf("parse %q: expected error", sql)
+		}
+	}
+}
+
+func TestTokenStatements(t *testing.T) {
+	for sql, want := range map[string]string{
+		"create token t1 for user1":                                          `CREATE TOKEN t1 FOR user1`,
+		"create token t1 for user1 duration 30d":                             `CREATE TOKEN t1 FOR user1 DURATION 30d`,
+		"create token t1 for user1 with write on db0":                        `CREATE TOKEN t1 FOR user1 WITH WRITE ON db0`,
+		"create token t1 for user1 with read on db0, all on db1 duration 1h": `CREATE TOKEN t1 FOR user1 WITH READ ON db0, ALL PRIVILEGES ON db1 DURATION 1h`,
+		"show tokens":           `SHOW TOKENS`,
+		"show tokens for user1": `SHOW TOKENS FOR user1`,
+		"revoke token t1":       `REVOKE TOKEN t1`,
+	} {
+		YyParser := &yacc.YyParser{Query: influxql.Query{}}
+		YyParser.Scanner = influxql.NewScanner(strings.NewReader(sql))
+		YyParser.ParseTokens()
+		q, err := YyParser.GetQuery()
+		if err != nil {
+			t.Fatalf("parse %q: %v", sql, err)
+		}
+		if got := q.Statements[0].String(); got != want {
+			t.Fatalf("parse %q: got %q, want %q", sql, got, want)
+		}
+	}
+
+	for _, sql := range []string{
+		"create token t1",
+		"create token t1 for user1 with admin on db0",
+		"create token t1 for user1 with read on db0 measurement cpu",
+	} {
+		YyParser := &yacc.YyParser{Query: influxql.Query{}}
+		YyParser.Scanner = influxql.NewScanner(strings.NewReader(sql))
+		YyParser.ParseTokens()
+		if _, err := YyParser.GetQuery(); err == nil {
+			t.Fatalf("parse %q: expected error", sql)
+		}
+	}
+}
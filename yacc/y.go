@@ -61,7 +61,36 @@ func deal_Fill(fill interface{}) (influxql.FillOption, interface{}, bool) {
 	}
 }
 
-//line sql.y:64
+func privilegeByName(yylex yyLexer, name string) influxql.Privilege {
+	switch strings.ToLower(name) {
+	case "read":
+		return influxql.ReadPrivilege
+	case "write":
+		return influxql.WritePrivilege
+	default:
+		yylex.Error("wrong Privilege")
+		return influxql.NoPrivileges
+	}
+}
+
+// userPrivilegeScope returns the database of a privilege granted to a user.
+// Retention policy and measurement scopes can only be granted to roles.
+func userPrivilegeScope(yylex yyLexer, scope *influxql.Measurement) string {
+	if scope.RetentionPolicy != "" || scope.Name != "" || scope.Regex != nil {
+		yylex.Error("retention policy and measurement privileges can only be granted to a role")
+	}
+	return scope.Database
+}
+
+func regexLiteral(yylex yyLexer, expr string) *influxql.RegexLiteral {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		yylex.Error("Invalid regexprs")
+	}
+	return &influxql.RegexLiteral{Val: re}
+}
+
+//line sql.y:92
 type yySymType struct {
 	yys              int
 	stmt             influxql.Statement
@@ -92,6 +121,7 @@ type yySymType struct {
 	strSlice         []string
 	location         *time.Location
 	indexType        *IndexType
+	tokenScopes      []influxql.TokenScope
 }
 
 const FROM = 57346
@@ -178,40 +208,46 @@ const INDEXTYPE = 57426
 const INDEXLIST = 57427
 const QUERY = 57428
 const PARTITION = 57429
-const DESC = 57430
-const ASC = 57431
-const COMMA = 57432
-const SEMICOLON = 57433
-const LPAREN = 57434
-const RPAREN = 57435
-const REGEX = 57436
-const EQ = 57437
-const NEQ = 57438
-const LT = 57439
-const LTE = 57440
-const GT = 57441
-const GTE = 57442
-const DOT = 57443
-const DOUBLECOLON = 57444
-const NEQREGEX = 57445
-const EQREGEX = 57446
-const IDENT = 57447
-const INTEGER = 57448
-const DURATIONVAL = 57449
-const STRING = 57450
-const NUMBER = 57451
-const HINT = 57452
-const AND = 57453
-const OR = 57454
-const ADD = 57455
-const SUB = 57456
-const BITWISE_OR = 57457
-const BITWISE_XOR = 57458
-const MUL = 57459
-const DIV = 57460
-const MOD = 57461
-const BITWISE_AND = 57462
-const UMINUS = 57463
+const DECOMMISSION = 57430
+const NODE = 57431
+const ROLE = 57432
+const ROLES = 57433
+const TOKEN = 57434
+const TOKENS = 57435
+const DESC = 57436
+const ASC = 57437
+const COMMA = 57438
+const SEMICOLON = 57439
+const LPAREN = 57440
+const RPAREN = 57441
+const REGEX = 57442
+const EQ = 57443
+const NEQ = 57444
+const LT = 57445
+const LTE = 57446
+const GT = 57447
+const GTE = 57448
+const DOT = 57449
+const DOUBLECOLON = 57450
+const NEQREGEX = 57451
+const EQREGEX = 57452
+const IDENT = 57453
+const INTEGER = 57454
+const DURATIONVAL = 57455
+const STRING = 57456
+const NUMBER = 57457
+const HINT = 57458
+const AND = 57459
+const OR = 57460
+const ADD = 57461
+const SUB = 57462
+const BITWISE_OR = 57463
+const BITWISE_XOR = 57464
+const MUL = 57465
+const DIV = 57466
+const MOD = 57467
+const BITWISE_AND = 57468
+const UMINUS = 57469
 
 var yyToknames = [...]string{
 	"$end",
@@ -301,6 +337,12 @@ var yyToknames = [...]string{
 	"INDEXLIST",
 	"QUERY",
 	"PARTITION",
+	"DECOMMISSION",
+	"NODE",
+	"ROLE",
+	"ROLES",
+	"TOKEN",
+	"TOKENS",
 	"DESC",
 	"ASC",
 	"COMMA",
@@ -343,220 +385,240 @@ const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-//line sql.y:2265
+//line sql.y:2460
 
 //line yacctab:1
 var yyExca = [...]int{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 333,
-	95, 125,
-	96, 125,
-	97, 125,
-	98, 125,
-	99, 125,
-	100, 125,
-	103, 125,
-	104, 125,
-	-2, 114,
+	-1, 367,
+	101, 133,
+	102, 133,
+	103, 133,
+	104, 133,
+	105, 133,
+	106, 133,
+	109, 133,
+	110, 133,
+	-2, 122,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 779
+const yyLast = 853
 
 var yyAct = [...]int{
-	364, 306, 632, 251, 281, 565, 465, 537, 572, 499,
-	363, 273, 417, 454, 399, 4, 435, 146, 398, 407,
-	304, 168, 239, 2, 197, 351, 624, 51, 157, 109,
-	110, 114, 115, 91, 61, 162, 67, 125, 169, 65,
-	66, 121, 68, 432, 111, 112, 116, 113, 109, 110,
-	114, 115, 61, 438, 625, 509, 441, 65, 66, 333,
-	406, 626, 279, 637, 101, 439, 243, 244, 56, 199,
-	68, 577, 561, 243, 244, 90, 492, 243, 244, 55,
-	105, 57, 63, 60, 64, 62, 56, 491, 68, 187,
-	58, 55, 188, 54, 490, 117, 161, 120, 68, 57,
-	63, 60, 64, 62, 52, 145, 489, 394, 58, 144,
-	128, 54, 147, 243, 244, 621, 180, 516, 517, 151,
-	148, 518, 610, 582, 156, 68, 68, 104, 526, 148,
-	108, 525, 148, 322, 184, 355, 413, 321, 182, 147,
-	147, 453, 202, 203, 40, 55, 198, 452, 189, 190,
-	191, 192, 193, 194, 195, 196, 397, 55, 395, 209,
-	207, 150, 213, 154, 205, 206, 359, 360, 215, 216,
-	217, 98, 222, 61, 362, 361, 227, 634, 65, 66,
-	96, 183, 201, 566, 235, 242, 111, 112, 116, 113,
-	109, 110, 114, 115, 68, 539, 573, 468, 124, 605,
-	246, 145, 456, 245, 212, 144, 419, 56, 147, 68,
-	567, 400, 513, 512, 502, 409, 446, 275, 285, 445,
-	57, 63, 60, 64, 62, 431, 522, 298, 429, 58,
-	428, 426, 54, 424, 111, 112, 116, 113, 109, 110,
-	114, 115, 419, 277, 122, 99, 284, 415, 405, 288,
-	290, 323, 286, 396, 97, 356, 348, 294, 347, 296,
-	344, 303, 300, 328, 301, 336, 326, 327, 466, 467,
-	148, 343, 283, 338, 148, 148, 470, 469, 68, 331,
-	332, 272, 271, 270, 267, 266, 369, 354, 265, 368,
-	262, 287, 289, 291, 260, 375, 520, 236, 297, 385,
-	373, 234, 384, 302, 111, 112, 116, 113, 109, 110,
-	114, 115, 230, 247, 248, 392, 225, 210, 357, 155,
-	371, 372, 153, 374, 149, 142, 393, 140, 118, 107,
-	383, 378, 411, 381, 388, 390, 391, 386, 119, 410,
-	412, 324, 414, 252, 253, 254, 255, 256, 257, 350,
-	418, 259, 258, 422, 233, 160, 433, 642, 636, 148,
-	425, 148, 370, 608, 635, 50, 607, 330, 614, 457,
-	379, 574, 382, 571, 461, 423, 387, 389, 245, 462,
-	442, 118, 606, 459, 460, 479, 463, 570, 508, 504,
-	440, 119, 444, 487, 503, 478, 421, 276, 447, 448,
-	483, 541, 485, 486, 458, 521, 434, 420, 337, 334,
-	249, 500, 50, 604, 587, 476, 477, 529, 530, 496,
-	481, 482, 528, 484, 519, 505, 488, 238, 497, 501,
-	237, 106, 498, 507, 562, 511, 506, 139, 126, 143,
-	341, 589, 320, 299, 514, 510, 295, 523, 148, 524,
-	488, 171, 319, 126, 471, 223, 224, 475, 220, 221,
-	532, 533, 480, 137, 138, 293, 226, 214, 546, 545,
-	531, 535, 578, 534, 40, 540, 134, 61, 135, 551,
-	474, 547, 65, 66, 555, 464, 557, 558, 549, 550,
-	563, 377, 576, 553, 554, 278, 556, 443, 204, 536,
-	131, 132, 133, 559, 218, 219, 542, 543, 124, 548,
-	600, 56, 181, 68, 552, 564, 185, 186, 569, 129,
-	130, 136, 3, 560, 57, 63, 60, 64, 62, 494,
-	404, 89, 584, 58, 579, 580, 575, 403, 100, 402,
-	401, 586, 583, 313, 316, 170, 314, 315, 152, 588,
-	594, 595, 590, 591, 597, 598, 440, 599, 141, 127,
-	593, 318, 87, 95, 596, 84, 102, 86, 544, 585,
-	93, 83, 88, 103, 603, 167, 166, 92, 495, 473,
-	92, 592, 85, 376, 261, 92, 232, 612, 609, 231,
-	611, 229, 208, 352, 619, 613, 615, 620, 250, 94,
-	263, 172, 82, 472, 618, 80, 380, 81, 61, 623,
-	622, 292, 627, 65, 66, 173, 335, 264, 174, 631,
-	427, 345, 342, 633, 616, 617, 416, 61, 629, 630,
-	639, 640, 65, 66, 329, 633, 641, 602, 601, 643,
-	638, 241, 164, 581, 68, 527, 178, 367, 176, 628,
-	450, 451, 77, 365, 366, 165, 63, 60, 64, 62,
-	92, 339, 177, 68, 58, 282, 353, 274, 282, 92,
-	93, 40, 568, 93, 57, 63, 60, 64, 62, 269,
-	126, 268, 340, 58, 73, 69, 325, 70, 71, 317,
-	211, 179, 175, 79, 309, 310, 280, 430, 349, 346,
-	92, 76, 408, 72, 437, 307, 311, 313, 316, 40,
-	314, 315, 74, 75, 538, 305, 308, 515, 449, 41,
-	42, 436, 455, 200, 123, 59, 78, 163, 358, 47,
-	158, 44, 240, 159, 1, 312, 53, 45, 39, 38,
-	37, 36, 35, 34, 33, 32, 31, 30, 29, 28,
-	46, 27, 26, 25, 49, 24, 23, 20, 19, 43,
-	21, 18, 22, 17, 16, 15, 13, 14, 12, 11,
-	493, 7, 48, 10, 9, 8, 228, 6, 5,
+	402, 339, 704, 280, 624, 596, 313, 551, 638, 546,
+	501, 305, 401, 482, 437, 512, 457, 337, 4, 445,
+	165, 436, 291, 389, 190, 268, 179, 2, 60, 127,
+	128, 132, 133, 697, 184, 105, 76, 696, 70, 191,
+	698, 561, 139, 74, 75, 479, 223, 129, 130, 134,
+	131, 127, 128, 132, 133, 444, 70, 272, 273, 272,
+	273, 74, 75, 77, 367, 393, 129, 130, 134, 131,
+	127, 128, 132, 133, 485, 118, 126, 488, 65, 225,
+	77, 311, 272, 273, 213, 709, 486, 214, 64, 671,
+	123, 66, 72, 69, 73, 71, 65, 643, 77, 620,
+	67, 64, 549, 63, 539, 135, 77, 138, 538, 66,
+	72, 69, 73, 71, 61, 575, 576, 451, 67, 577,
+	166, 63, 129, 130, 134, 131, 127, 128, 132, 133,
+	537, 167, 536, 432, 172, 183, 205, 77, 272, 273,
+	178, 397, 398, 167, 164, 77, 167, 113, 163, 400,
+	399, 166, 355, 210, 228, 229, 354, 515, 208, 166,
+	693, 682, 104, 648, 64, 224, 585, 215, 216, 217,
+	218, 219, 220, 221, 222, 70, 64, 233, 552, 235,
+	74, 75, 239, 231, 232, 110, 129, 130, 134, 131,
+	127, 128, 132, 133, 281, 282, 283, 284, 285, 286,
+	227, 706, 288, 287, 584, 77, 115, 264, 116, 48,
+	500, 271, 164, 77, 499, 65, 163, 77, 571, 166,
+	296, 275, 298, 122, 676, 302, 274, 114, 66, 72,
+	69, 73, 71, 567, 513, 514, 478, 67, 475, 570,
+	63, 473, 517, 516, 112, 307, 317, 467, 435, 77,
+	433, 189, 188, 206, 566, 330, 175, 477, 553, 474,
+	469, 171, 472, 142, 252, 111, 625, 598, 466, 639,
+	309, 675, 674, 673, 316, 503, 637, 320, 322, 636,
+	459, 626, 356, 438, 70, 251, 209, 572, 569, 74,
+	75, 336, 568, 360, 361, 362, 565, 370, 167, 564,
+	554, 447, 493, 167, 167, 372, 492, 365, 366, 238,
+	464, 455, 443, 459, 434, 140, 379, 394, 407, 392,
+	387, 385, 384, 406, 186, 292, 77, 382, 380, 413,
+	358, 423, 411, 333, 315, 304, 422, 187, 72, 69,
+	73, 71, 301, 295, 289, 265, 67, 262, 430, 258,
+	253, 395, 236, 204, 409, 410, 431, 412, 203, 198,
+	177, 276, 277, 176, 421, 174, 170, 169, 426, 168,
+	428, 429, 161, 159, 182, 448, 450, 581, 452, 136,
+	579, 125, 449, 357, 261, 388, 77, 458, 680, 137,
+	462, 679, 167, 59, 167, 364, 480, 465, 714, 708,
+	707, 686, 640, 634, 319, 321, 323, 504, 463, 633,
+	560, 329, 508, 274, 556, 470, 489, 555, 335, 509,
+	461, 506, 507, 526, 308, 678, 510, 600, 487, 580,
+	481, 534, 491, 525, 460, 136, 494, 495, 530, 371,
+	532, 533, 368, 278, 505, 137, 59, 547, 627, 670,
+	653, 588, 589, 119, 587, 523, 524, 578, 557, 70,
+	528, 529, 535, 531, 74, 75, 544, 267, 266, 162,
+	548, 124, 621, 545, 158, 563, 543, 559, 144, 558,
+	353, 408, 562, 375, 193, 144, 167, 331, 143, 417,
+	352, 420, 535, 573, 327, 425, 582, 427, 583, 65,
+	628, 77, 249, 250, 246, 247, 325, 591, 592, 156,
+	157, 254, 66, 72, 69, 73, 71, 590, 240, 655,
+	599, 67, 593, 152, 48, 153, 610, 594, 622, 605,
+	604, 614, 521, 616, 617, 608, 609, 606, 511, 415,
+	612, 613, 94, 615, 244, 245, 211, 212, 595, 103,
+	618, 147, 148, 601, 602, 149, 150, 151, 607, 644,
+	642, 490, 310, 611, 230, 623, 3, 142, 666, 263,
+	146, 207, 155, 93, 632, 154, 91, 619, 92, 518,
+	100, 468, 522, 97, 541, 99, 442, 527, 117, 454,
+	101, 650, 641, 441, 440, 645, 646, 439, 649, 192,
+	98, 603, 652, 453, 346, 349, 654, 347, 348, 660,
+	661, 173, 160, 663, 664, 487, 665, 145, 351, 659,
+	656, 657, 120, 662, 542, 378, 121, 95, 109, 96,
+	651, 106, 520, 669, 102, 414, 672, 241, 242, 243,
+	106, 248, 658, 106, 290, 88, 255, 260, 259, 257,
+	390, 107, 279, 684, 681, 369, 471, 519, 683, 685,
+	691, 377, 363, 692, 108, 381, 418, 376, 194, 324,
+	690, 687, 677, 234, 297, 694, 456, 82, 78, 695,
+	79, 80, 195, 668, 699, 196, 90, 667, 270, 497,
+	498, 703, 688, 689, 85, 705, 81, 293, 647, 586,
+	701, 702, 711, 712, 70, 83, 84, 705, 713, 74,
+	75, 715, 710, 314, 294, 405, 201, 106, 199, 89,
+	403, 404, 391, 700, 306, 314, 106, 48, 48, 318,
+	107, 86, 200, 87, 326, 107, 328, 49, 50, 332,
+	300, 631, 299, 334, 373, 630, 77, 55, 629, 52,
+	144, 374, 359, 350, 237, 53, 202, 66, 72, 69,
+	73, 71, 342, 343, 197, 635, 67, 312, 54, 476,
+	386, 383, 58, 340, 344, 346, 349, 51, 347, 348,
+	303, 106, 550, 446, 341, 484, 597, 338, 574, 496,
+	56, 483, 502, 226, 141, 68, 185, 396, 180, 269,
+	181, 1, 62, 345, 39, 38, 37, 36, 57, 35,
+	34, 33, 32, 47, 416, 46, 419, 45, 44, 43,
+	424, 42, 41, 40, 31, 30, 29, 28, 27, 26,
+	25, 24, 23, 20, 19, 21, 18, 22, 17, 16,
+	15, 13, 14, 12, 11, 540, 7, 10, 9, 8,
+	256, 6, 5,
 }
 
 var yyPact = [...]int{
-	702, -1000, 321, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	720, -1000, 349, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-6, 647, 566, 526, 665, 558, 149, 140, 467, 534,
-	702, 696, 115, 341, 227, 121, 419, 236, 419, -1000,
-	-1000, 139, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	674, 517, 447, -1000, 433, 409, 468, 391, -1000, 354,
-	222, 515, 220, 100, 219, 665, 505, 217, 57, 214,
-	662, -1000, 4, 550, 502, 100, 595, 686, 642, 685,
-	664, -1000, 459, -1000, 662, 696, 115, 451, -16, 419,
-	419, 419, 419, 419, 419, 419, 419, -69, -24, 77,
-	-1000, 437, 449, 449, 550, 562, 212, 684, 665, 394,
-	674, 674, 432, 386, 674, 383, 211, 393, 674, -1000,
-	561, 207, 559, 556, 253, 196, -1000, -1000, -1000, -1000,
-	662, -1000, 192, -1000, -1000, -1000, -1000, -1000, 340, 337,
-	622, 702, -45, -1000, 550, 289, 318, 572, 248, 191,
-	189, 554, 185, 594, 183, 180, 179, 675, 178, 177,
-	-1000, 176, 657, 662, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -88, -88, -88, -1000, -1000, -88, -1000, 304, -1000,
-	-1000, -1000, -1000, -1000, 419, 434, -1000, 2, 691, 653,
-	-1000, 167, 662, 653, 674, 665, 665, 581, 392, 674,
-	373, 674, 656, 370, 674, -1000, 674, 665, -1000, 661,
-	683, 529, 368, 32, 240, -1000, 680, 4, 4, -1000,
-	622, 613, 274, 550, 550, -69, -34, 317, 592, 664,
-	316, 569, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	676, 366, 599, 166, 155, -1000, 598, 695, 153, 151,
-	-1000, 694, 254, 565, 655, 657, -1000, 73, 150, 419,
-	71, 640, 636, -1000, 653, 640, 665, 662, 657, 662,
-	653, 553, 422, 674, 576, 674, 665, 653, 640, 674,
-	665, 665, 662, 657, -1000, 661, -1000, 0, 52, 148,
-	50, -1000, 106, 496, 495, 493, 486, 143, -48, 110,
-	106, 231, 31, -1000, 31, 142, -1000, -1000, -1000, 604,
-	-1000, -1000, -1000, -1000, 137, 315, 303, 664, -1000, 550,
-	128, 106, 126, 597, -1000, 125, 123, 693, -1000, 120,
-	-65, 653, 314, -52, 565, -1000, 435, 191, 662, 114,
-	111, 184, 184, -1000, 635, 41, 35, 97, 640, -1000,
-	662, 657, 657, 640, 653, 640, 416, 173, 573, 549,
-	411, 665, 662, 657, 640, -1000, 665, 662, 657, 662,
-	657, 657, 640, -1000, -1000, -1000, -1000, -1000, 336, -1000,
-	-1000, -1, -13, -20, -31, 485, 548, 345, 110, 347,
-	360, 31, -1000, -1000, -1000, -1000, 109, 301, 296, 335,
-	137, -1000, 295, -38, 661, 360, -1000, 108, -1000, -1000,
-	107, -1000, -1000, 640, 12, -1000, 334, 194, 313, 124,
-	-1000, -1000, 653, -1000, 653, -1000, -1000, -1000, -1000, -1000,
-	25, 22, 631, -1000, -1000, 332, 329, -1000, 657, 640,
-	640, -1000, 640, -1000, 173, 662, 90, 90, 309, 184,
-	184, 538, 400, 399, 173, 662, 657, 657, 640, -1000,
-	662, 657, 657, 640, 657, 640, 640, -1000, 106, -1000,
-	-1000, -1000, -1000, 478, -35, 403, 106, -1000, 78, -1000,
-	105, -1000, 666, -1000, -1000, 101, 294, 280, -1000, -1000,
-	-1000, -1000, -1000, -1000, 91, 278, -1000, -1000, -1000, -52,
-	427, -36, 407, 640, 640, 627, -1000, 17, 97, -1000,
-	-1000, 640, -1000, -1000, -1000, 662, 653, -1000, 324, -1000,
-	-1000, 90, -1000, -1000, 372, 173, 173, 662, 657, 640,
-	640, -1000, 657, 640, 640, -1000, 640, -1000, -1000, -1000,
-	-1000, 455, 618, 617, 360, -1000, 323, -1000, 94, -1000,
-	-1000, -1000, -1000, 290, -1000, -1000, -1000, 273, -1000, 91,
-	-1000, 16, -1000, -1000, -1000, 653, 640, 90, 275, 173,
-	662, 662, 657, 640, -1000, -1000, 640, -1000, -1000, -1000,
-	9, -1000, -1000, -1000, 78, 248, -82, -1000, -53, -1000,
-	-1000, 640, -1000, -1000, -1000, 662, 657, 657, 640, -1000,
-	-1000, 497, -1000, 72, 271, 265, -44, -1000, 657, 640,
-	640, -1000, -1000, 497, -1000, -1000, -1000, 264, 640, -1000,
-	-1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -2, 640,
+	537, 544, 722, 623, 154, 116, 517, 364, 590, 720,
+	777, 117, 375, 273, 67, 401, 281, 401, -1000, -1000,
+	204, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 744,
+	575, 479, -1000, 488, 456, 522, -1000, 519, 437, -1000,
+	391, 262, 569, 261, 105, 258, 256, 255, 722, 568,
+	254, 144, 252, 249, 727, -1000, 37, 226, 556, 105,
+	662, 758, 248, 712, 750, 247, 242, 721, -1000, 141,
+	518, -1000, 727, 777, 117, 481, -27, 401, 401, 401,
+	401, 401, 401, 401, 401, -53, -20, 89, -1000, 503,
+	508, 508, 226, 643, 241, 748, 722, 445, 744, 744,
+	472, 432, 744, 430, 174, 239, 438, 744, -1000, 619,
+	238, 618, 617, 277, 236, -1000, -1000, -1000, -1000, 516,
+	-1000, 727, -1000, 234, -1000, -1000, -1000, -1000, -1000, -1000,
+	372, 371, 669, 720, -60, -1000, 226, 337, 345, 626,
+	93, -72, 233, 614, 214, 691, 232, 214, 651, 214,
+	736, 231, 214, 776, -1000, -1000, -1000, 224, 714, 727,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -94, -94, -94,
+	-1000, -1000, -94, -1000, 325, -1000, -1000, -1000, -1000, -1000,
+	401, 501, -1000, 21, 762, 701, -1000, 223, 727, 701,
+	744, 722, 722, 639, 433, 744, 421, 744, 713, 414,
+	744, -1000, 222, -1000, 744, 722, -1000, 729, 747, 586,
+	406, 45, 276, 219, -1000, 746, 37, 37, -1000, 669,
+	641, 296, 226, 226, -53, -35, 344, 631, 721, 341,
+	646, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 745,
+	409, 644, 620, 214, 217, -1000, 642, 216, 767, 214,
+	210, -1000, 766, 209, 284, 622, 711, 714, -1000, 3,
+	206, 401, 40, 707, 704, -1000, 701, 707, 722, 727,
+	714, 727, 701, 605, 470, 744, 636, 744, 722, 701,
+	707, 744, 722, -1000, 722, 727, 714, -1000, 729, -1000,
+	20, 138, 203, 136, -1000, 172, 553, 550, 549, 542,
+	201, -59, 190, 172, 275, 6, -1000, 6, 559, 200,
+	-1000, -1000, -1000, 654, -1000, -1000, -1000, -1000, 202, 336,
+	321, 721, -1000, 226, 199, 172, 157, 538, 149, 633,
+	-1000, 151, -1000, 148, 765, -1000, 146, -1000, -69, 701,
+	332, -37, 622, -1000, 499, -72, 727, 195, 191, 286,
+	286, -1000, 674, 102, 98, 164, 707, -1000, 727, 714,
+	714, 707, 701, 707, 469, 133, 627, 602, 463, 722,
+	727, 714, 707, -1000, 722, 727, 714, 727, 714, 714,
+	707, -1000, -1000, -1000, -1000, -1000, 366, -1000, -1000, 19,
+	17, -5, -9, 540, 594, 402, 190, 388, 396, 6,
+	-1000, -1000, -1000, -11, 147, -1000, 189, 318, 315, 362,
+	202, -1000, 311, -58, 729, 396, -1000, 188, 185, -1000,
+	-1000, 143, -1000, 181, -1000, 177, 128, -1000, 176, -1000,
+	707, 4, -1000, 361, 272, 331, 269, -1000, -1000, 701,
+	-1000, 701, -1000, -1000, -1000, -1000, -1000, 92, 54, 685,
+	-1000, -1000, 358, 357, -1000, 714, 707, 707, -1000, 707,
+	-1000, 133, 727, 156, 156, 329, 286, 286, 571, 461,
+	460, 133, 727, 714, 714, 707, -1000, 727, 714, 714,
+	707, 714, 707, 707, -1000, 172, -1000, -1000, -1000, -1000,
+	532, -14, 441, 172, -1000, 155, -1000, 170, -1000, -1000,
+	404, -1000, 742, 739, 735, -1000, -1000, 169, 310, 304,
+	-1000, -1000, -1000, -1000, -1000, 760, -1000, 168, -1000, -1000,
+	-1000, 165, -1000, 158, 303, -1000, -1000, -1000, -37, 495,
+	-16, 494, 707, 707, 682, -1000, 51, 164, -1000, -1000,
+	707, -1000, -1000, -1000, 727, 701, -1000, 354, -1000, -1000,
+	156, -1000, -1000, 450, 133, 133, 727, 714, 707, 707,
+	-1000, 714, 707, 707, -1000, 707, -1000, -1000, -1000, -1000,
+	513, 667, 663, 396, -1000, 353, -1000, -24, 147, 162,
+	161, 160, -1000, -1000, -1000, 113, -1000, -1000, -1000, 327,
+	-1000, -1000, -1000, 292, -1000, 158, -1000, 49, -1000, -1000,
+	-1000, 701, 707, 156, 302, 133, 727, 727, 714, 707,
+	-1000, -1000, 707, -1000, -1000, -1000, 48, -1000, -1000, -1000,
+	155, -1000, -1000, -1000, -1000, 93, -1000, -1000, -77, -1000,
+	-80, -1000, -1000, 707, -1000, -1000, -1000, 727, 714, 714,
+	707, -1000, -1000, 558, -1000, 90, 301, 300, -28, -1000,
+	714, 707, 707, -1000, -1000, 558, -1000, -1000, -1000, 299,
+	707, -1000, -1000, -1000, -1000, -1000,
 }
 
 var yyPgo = [...]int{
-	0, 522, 778, 777, 776, 775, 15, 774, 773, 771,
-	770, 769, 768, 767, 766, 765, 764, 763, 762, 761,
-	760, 758, 757, 756, 755, 753, 6, 752, 751, 749,
-	748, 747, 746, 745, 744, 743, 742, 741, 740, 739,
-	738, 27, 12, 736, 734, 23, 75, 28, 733, 17,
-	22, 732, 730, 355, 728, 33, 35, 727, 725, 38,
-	21, 7, 724, 41, 3, 723, 13, 4, 722, 11,
-	16, 721, 10, 0, 718, 25, 717, 2, 1, 715,
-	20, 36, 714, 37, 9, 14, 704, 18, 5, 8,
-	702, 19,
+	0, 566, 852, 851, 850, 849, 18, 848, 847, 846,
+	845, 844, 843, 842, 841, 840, 839, 838, 837, 836,
+	835, 834, 833, 832, 831, 830, 15, 829, 828, 827,
+	826, 825, 824, 823, 822, 821, 819, 818, 817, 815,
+	813, 812, 811, 810, 809, 807, 806, 805, 804, 28,
+	16, 802, 801, 27, 162, 26, 800, 20, 25, 799,
+	798, 374, 797, 22, 35, 34, 796, 795, 39, 24,
+	5, 794, 42, 3, 793, 10, 6, 792, 11, 13,
+	791, 12, 0, 789, 23, 788, 2, 1, 787, 17,
+	36, 786, 488, 9, 14, 785, 21, 4, 8, 783,
+	19, 782, 7,
 }
 
 var yyR1 = [...]int{
-	0, 44, 45, 45, 45, 1, 1, 1, 1, 1,
+	0, 52, 53, 53, 53, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 6, 6, 41, 41, 43, 43, 43, 43, 43,
-	43, 63, 63, 62, 42, 42, 59, 59, 59, 59,
-	59, 59, 59, 59, 59, 59, 59, 59, 59, 59,
-	59, 59, 46, 47, 47, 47, 47, 48, 52, 53,
-	53, 53, 53, 53, 49, 49, 49, 50, 50, 51,
-	69, 69, 70, 70, 86, 86, 71, 71, 71, 71,
-	71, 71, 71, 71, 89, 89, 75, 75, 76, 76,
-	76, 55, 55, 56, 56, 56, 56, 56, 56, 56,
-	56, 56, 56, 57, 60, 60, 64, 64, 64, 64,
-	64, 64, 64, 64, 81, 58, 58, 58, 58, 58,
-	58, 58, 58, 65, 65, 65, 67, 67, 66, 66,
-	68, 68, 68, 72, 73, 73, 73, 73, 74, 74,
-	74, 74, 2, 3, 3, 4, 80, 80, 79, 79,
-	79, 79, 79, 79, 79, 7, 7, 54, 54, 54,
-	54, 8, 8, 9, 9, 5, 5, 5, 10, 10,
-	77, 77, 78, 78, 78, 78, 11, 11, 12, 14,
-	13, 13, 15, 15, 16, 17, 19, 19, 19, 21,
-	21, 20, 20, 20, 22, 22, 18, 23, 23, 83,
-	83, 24, 24, 25, 25, 26, 26, 26, 26, 26,
-	61, 61, 82, 27, 27, 28, 28, 28, 28, 29,
-	29, 29, 29, 30, 30, 30, 30, 31, 31, 31,
-	31, 90, 91, 91, 88, 88, 84, 84, 87, 87,
-	85, 32, 33, 34, 35, 35, 35, 35, 36, 36,
-	36, 36, 37, 38, 38, 39, 40,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 6,
+	6, 49, 49, 51, 51, 51, 51, 51, 51, 72,
+	72, 71, 50, 50, 68, 68, 68, 68, 68, 68,
+	68, 68, 68, 68, 68, 68, 68, 68, 68, 68,
+	54, 55, 55, 55, 55, 56, 60, 61, 61, 61,
+	61, 61, 57, 57, 57, 58, 58, 59, 78, 78,
+	79, 79, 95, 95, 80, 80, 80, 80, 80, 80,
+	80, 80, 98, 98, 84, 84, 85, 85, 85, 64,
+	64, 65, 65, 65, 65, 65, 65, 65, 65, 65,
+	65, 66, 69, 69, 73, 73, 73, 73, 73, 73,
+	73, 73, 90, 67, 67, 67, 67, 67, 67, 67,
+	67, 74, 74, 74, 76, 76, 75, 75, 77, 77,
+	77, 81, 82, 82, 82, 82, 83, 83, 83, 83,
+	2, 3, 3, 4, 89, 89, 88, 88, 88, 88,
+	88, 88, 88, 7, 7, 62, 62, 62, 62, 8,
+	8, 9, 9, 5, 5, 5, 10, 10, 86, 86,
+	87, 87, 87, 87, 11, 11, 12, 14, 13, 13,
+	15, 15, 16, 17, 19, 19, 19, 19, 19, 19,
+	19, 63, 63, 63, 63, 63, 63, 21, 21, 20,
+	20, 20, 20, 20, 20, 20, 22, 22, 18, 23,
+	23, 92, 92, 24, 24, 25, 25, 26, 26, 26,
+	26, 26, 70, 70, 91, 27, 27, 28, 28, 28,
+	28, 29, 29, 29, 29, 30, 30, 30, 30, 31,
+	31, 31, 31, 99, 100, 100, 97, 97, 93, 93,
+	96, 96, 94, 32, 41, 33, 34, 45, 42, 43,
+	44, 46, 46, 46, 46, 101, 101, 102, 102, 47,
+	48, 48, 35, 35, 35, 35, 36, 36, 36, 36,
+	37, 38, 38, 39, 40,
 }
 
 var yyR2 = [...]int{
@@ -564,98 +626,109 @@ var yyR2 = [...]int{
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 9, 10, 1, 3, 1, 3, 3, 1, 3,
-	3, 1, 2, 4, 1, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 4, 3, 2, 1, 1,
-	5, 6, 2, 1, 3, 1, 3, 3, 2, 5,
-	4, 4, 3, 1, 1, 1, 1, 2, 0, 8,
-	3, 0, 1, 3, 1, 1, 1, 3, 4, 6,
-	7, 1, 3, 1, 4, 0, 4, 0, 1, 1,
-	1, 2, 0, 1, 3, 3, 3, 5, 5, 4,
-	6, 6, 5, 3, 1, 3, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 3, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 3, 0, 1, 3,
-	1, 2, 2, 2, 4, 2, 2, 0, 4, 2,
-	2, 0, 2, 4, 3, 2, 1, 2, 1, 2,
-	2, 2, 2, 1, 2, 9, 6, 2, 2, 2,
-	2, 5, 3, 7, 8, 6, 9, 9, 5, 4,
-	1, 2, 3, 3, 3, 3, 7, 6, 2, 3,
-	4, 3, 3, 2, 7, 6, 6, 7, 6, 5,
-	4, 6, 7, 6, 5, 4, 3, 8, 7, 2,
-	0, 7, 6, 11, 10, 2, 2, 4, 2, 2,
-	1, 3, 1, 3, 2, 10, 9, 9, 8, 13,
-	12, 12, 11, 10, 9, 9, 8, 9, 7, 6,
-	3, 3, 2, 0, 1, 3, 2, 0, 1, 3,
-	1, 3, 6, 4, 9, 8, 8, 7, 9, 8,
-	8, 7, 2, 7, 3, 3, 3,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 9,
+	10, 1, 3, 1, 3, 3, 1, 3, 3, 1,
+	2, 4, 1, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 4, 3, 2, 1, 1, 5, 6,
+	2, 1, 3, 1, 3, 3, 2, 5, 4, 4,
+	3, 1, 1, 1, 1, 2, 0, 8, 3, 0,
+	1, 3, 1, 1, 1, 3, 4, 6, 7, 1,
+	3, 1, 4, 0, 4, 0, 1, 1, 1, 2,
+	0, 1, 3, 3, 3, 5, 5, 4, 6, 6,
+	5, 3, 1, 3, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 3, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 3, 0, 1, 3, 1, 2,
+	2, 2, 4, 2, 2, 0, 4, 2, 2, 0,
+	2, 4, 3, 2, 1, 2, 1, 2, 2, 2,
+	2, 1, 2, 9, 6, 2, 2, 2, 2, 5,
+	3, 7, 8, 6, 9, 9, 5, 4, 1, 2,
+	3, 3, 3, 3, 7, 6, 2, 3, 4, 3,
+	3, 2, 7, 6, 6, 7, 6, 7, 8, 7,
+	5, 1, 4, 3, 3, 6, 6, 5, 4, 6,
+	7, 6, 7, 8, 7, 5, 5, 4, 3, 8,
+	7, 2, 0, 7, 6, 11, 10, 2, 2, 4,
+	2, 2, 1, 3, 1, 3, 2, 10, 9, 9,
+	8, 13, 12, 12, 11, 10, 9, 9, 8, 9,
+	7, 6, 3, 3, 2, 0, 1, 3, 2, 0,
+	1, 3, 1, 3, 3, 6, 4, 5, 3, 3,
+	2, 5, 7, 7, 9, 1, 3, 3, 3, 3,
+	2, 4, 9, 8, 8, 7, 9, 8, 8, 7,
+	2, 7, 3, 3, 3,
 }
 
 var yyChk = [...]int{
-	-1000, -44, -45, -1, -6, -2, -3, -9, -5, -7,
+	-1000, -52, -53, -1, -6, -2, -3, -9, -5, -7,
 	-8, -11, -12, -14, -13, -15, -16, -17, -19, -21,
 	-22, -20, -18, -23, -24, -25, -27, -28, -29, -30,
-	-31, -32, -33, -34, -35, -36, -37, -38, -39, -40,
-	7, 17, 18, 57, 29, 35, 48, 27, 70, 52,
-	91, -41, 110, -43, 117, -59, 92, 105, 114, -58,
-	107, 58, 109, 106, 108, 63, 64, -81, 94, 38,
-	40, 41, 56, 37, 65, 66, 54, 5, 79, 46,
-	39, 41, 36, 5, 39, 56, 41, 36, 46, 5,
-	-46, -55, 4, 8, 41, 5, 31, 105, 31, 105,
-	71, -6, 32, -1, -46, -41, 90, 102, 9, 117,
-	118, 113, 114, 116, 119, 120, 115, -59, 92, 102,
-	-59, -63, 105, -62, 59, -83, 6, 42, -83, 72,
-	73, 67, 68, 69, 67, 69, 53, 72, 73, 83,
-	105, 43, 105, -53, 105, 101, -49, 108, -81, 105,
-	-46, -55, 43, 105, 106, 105, -55, -47, -52, -48,
-	-53, 92, -56, -57, 92, 105, 26, 25, -60, -59,
-	43, -53, 6, 20, 23, 6, 6, 20, 4, 6,
-	-6, 53, -55, -46, -41, 65, 66, 105, 108, -59,
-	-59, -59, -59, -59, -59, -59, -59, 93, -41, 93,
-	-65, 105, 65, 66, 61, -63, -63, -56, 30, -55,
-	105, 6, -46, -55, 73, -83, -83, -83, 72, 73,
-	72, 73, -83, 72, 73, 105, 73, -83, -4, 30,
-	105, 30, 30, 101, 105, -55, 105, 90, 90, -50,
-	-51, 19, -45, 111, 112, -59, -56, 24, 25, 92,
-	26, -64, 95, 96, 97, 98, 99, 100, 104, 103,
-	105, 30, 105, 6, 23, 105, 105, 105, 6, 4,
-	105, 105, 105, -69, 10, -55, 93, -59, 61, 60,
-	5, -67, 12, 105, -55, -67, -83, -46, -55, -46,
-	-55, -46, 30, 73, -83, 73, -83, -46, -67, 73,
-	-83, -83, -46, -55, -80, -79, -78, 44, 55, 33,
-	34, 45, 74, 46, 49, 50, 47, 6, 32, 84,
-	74, 105, 101, -49, 101, 6, -47, -47, -50, 21,
-	93, -56, -56, 93, 92, 24, -6, 92, -60, 92,
-	6, 74, 23, 105, 105, 23, 4, 105, 105, 4,
-	95, -75, 28, 11, -69, 62, 105, -59, -54, 95,
-	96, 104, 103, -72, -73, 13, 14, 11, -67, -73,
-	-46, -55, -55, -69, -55, -67, 30, 69, -83, -46,
-	30, -83, -46, -55, -67, -73, -83, -46, -55, -46,
-	-55, -55, -69, -80, 107, 106, 105, 106, -87, -85,
-	105, 44, 44, 44, 44, 105, 108, -91, -90, 105,
-	-87, 101, -49, 105, -49, 105, 22, -42, -6, 105,
-	92, 93, -6, -56, 105, -87, 105, 23, 105, 105,
-	4, 105, 108, -67, 92, -70, -71, -86, 105, 117,
-	-81, 108, -75, 62, -55, 105, 105, -81, -81, -74,
-	15, 16, 106, 106, -66, -68, 105, -73, -55, -69,
-	-69, -73, -67, -72, 69, -26, 95, 96, 24, 104,
-	103, -46, 30, 30, 69, -46, -55, -55, -69, -73,
-	-46, -55, -55, -69, -55, -69, -69, -73, 90, 107,
-	107, 107, 107, -10, 44, 30, 74, -91, 85, -84,
-	51, -49, 105, 93, 93, 90, -6, -42, 93, 93,
-	-80, -84, 105, 105, -72, -76, 105, 106, 109, 90,
-	102, 92, 102, -67, -67, 106, 106, 14, 90, 88,
-	89, -69, -73, -73, -72, -26, -55, -61, -82, 105,
-	-61, 92, -81, -81, 30, 69, 69, -26, -55, -69,
-	-69, -73, -55, -69, -69, -73, -69, -73, -73, -85,
-	45, 107, 31, 87, -87, -88, 105, 105, 6, -42,
-	93, 93, -89, 105, 93, -70, 65, 107, 65, -72,
-	-72, 16, 106, -66, -73, -55, -67, 90, -61, 69,
-	-26, -26, -55, -69, -73, -73, -69, -73, -73, -73,
-	55, 20, 20, -84, 90, 105, 92, 93, 90, -89,
-	106, -67, -73, -61, 93, -26, -55, -55, -69, -73,
-	-73, 106, -88, -64, 108, 107, 114, -73, -55, -69,
-	-69, -73, -77, -78, 105, 93, 93, 107, -69, -73,
-	-73, -77, 93, -73,
+	-31, -32, -41, -42, -43, -44, -45, -46, -47, -48,
+	-33, -34, -35, -36, -37, -38, -39, -40, 7, 17,
+	18, 57, 29, 35, 48, 27, 70, 88, 52, 97,
+	-49, 116, -51, 123, -68, 98, 111, 120, -67, 113,
+	58, 115, 112, 114, 63, 64, -90, 100, 38, 40,
+	41, 56, 37, 65, 66, 54, 91, 93, 5, 79,
+	46, 39, 41, 36, 5, 90, 92, 39, 56, 41,
+	36, 46, 90, 5, -54, -64, 4, 8, 41, 5,
+	31, 111, 90, 31, 111, 90, 92, 71, -6, 89,
+	32, -1, -54, -49, 96, 108, 9, 123, 124, 119,
+	120, 122, 125, 126, 121, -68, 98, 108, -68, -72,
+	111, -71, 59, -92, 6, 42, -92, 72, 73, 67,
+	68, 69, 67, 69, 53, 53, 72, 73, 83, 111,
+	43, 111, -61, 111, 107, -57, 114, -90, 111, 111,
+	111, -54, -64, 43, 111, 112, 111, 111, -64, -55,
+	-60, -56, -61, 98, -65, -66, 98, 111, 26, 25,
+	-69, -68, 43, -61, 6, 20, 23, 6, 111, 6,
+	20, 4, 6, 111, 111, -6, 112, 53, -64, -54,
+	-49, 65, 66, 111, 114, -68, -68, -68, -68, -68,
+	-68, -68, -68, 99, -49, 99, -74, 111, 65, 66,
+	61, -72, -72, -65, 30, -64, 111, 6, -54, -64,
+	73, -92, -92, -92, 72, 73, 72, 73, -92, 72,
+	73, 111, 90, 111, 73, -92, -4, 30, 111, 30,
+	30, 107, 111, 53, -64, 111, 96, 96, -58, -59,
+	19, -53, 117, 118, -68, -65, 24, 25, 98, 26,
+	-73, 101, 102, 103, 104, 105, 106, 110, 109, 111,
+	30, -63, 111, 6, 23, 111, -63, 23, -63, 6,
+	4, 111, -63, 4, 111, -78, 10, -64, 99, -68,
+	61, 60, 5, -76, 12, 111, -64, -76, -92, -54,
+	-64, -54, -64, -54, 30, 73, -92, 73, -92, -54,
+	-76, 73, -92, 111, -92, -54, -64, -89, -88, -87,
+	44, 55, 33, 34, 45, 74, 46, 49, 50, 47,
+	6, 32, 84, 74, 111, 107, -57, 107, 111, 6,
+	-55, -55, -58, 21, 99, -65, -65, 99, 98, 24,
+	-6, 98, -69, 98, 6, 74, 23, 41, 5, -63,
+	111, 23, 111, 4, -63, 111, 4, 111, 101, -84,
+	28, 11, -78, 62, 111, -68, -62, 101, 102, 110,
+	109, -81, -82, 13, 14, 11, -76, -82, -54, -64,
+	-64, -78, -64, -76, 30, 69, -92, -54, 30, -92,
+	-54, -64, -76, -82, -92, -54, -64, -54, -64, -64,
+	-78, -89, 113, 112, 111, 112, -96, -94, 111, 44,
+	44, 44, 44, 111, 114, -100, -99, 111, -96, 107,
+	-57, 111, -57, 44, 30, 111, 22, -50, -6, 111,
+	98, 99, -6, -65, 111, -96, 111, 90, 43, 111,
+	-90, 23, 111, 90, 111, 90, 4, 111, 90, 114,
+	-76, 98, -79, -80, -95, 111, 123, -90, 114, -84,
+	62, -64, 111, 111, -90, -90, -83, 15, 16, 112,
+	112, -75, -77, 111, -82, -64, -78, -78, -82, -76,
+	-81, 69, -26, 101, 102, 24, 110, 109, -54, 30,
+	30, 69, -54, -64, -64, -78, -82, -54, -64, -64,
+	-78, -64, -78, -78, -82, 96, 113, 113, 113, 113,
+	-10, 44, 30, 74, -100, 85, -93, 51, -57, 113,
+	-101, -102, 31, 111, 111, 99, 99, 96, -6, -50,
+	99, 99, -89, -93, 111, 111, 111, 90, 111, 111,
+	111, 90, 111, -81, -85, 111, 112, 115, 96, 108,
+	98, 108, -76, -76, 112, 112, 14, 96, 94, 95,
+	-78, -82, -82, -81, -26, -64, -70, -91, 111, -70,
+	98, -90, -90, 30, 69, 69, -26, -64, -78, -78,
+	-82, -64, -78, -78, -82, -78, -82, -82, -94, 45,
+	113, 31, 87, -96, -97, 111, 111, 44, 96, 6,
+	6, 6, -50, 99, 99, 5, 111, 111, -98, 111,
+	99, -79, 65, 113, 65, -81, -81, 16, 112, -75,
+	-82, -64, -76, 96, -70, 69, -26, -26, -64, -78,
+	-82, -82, -78, -82, -82, -82, 55, 20, 20, -93,
+	96, 113, -102, 111, 111, 111, 111, -90, 98, 99,
+	96, -98, 112, -76, -82, -70, 99, -26, -64, -64,
+	-78, -82, -82, 112, -97, -73, 114, 113, 120, -82,
+	-64, -78, -78, -82, -86, -87, 111, 99, 99, 113,
+	-78, -82, -82, -86, 99, -82,
 }
 
 var yyDef = [...]int{
@@ -663,67 +736,74 @@ var yyDef = [...]int{
 	11, 12, 13, 14, 15, 16, 17, 18, 19, 20,
 	21, 22, 23, 24, 25, 26, 27, 28, 29, 30,
 	31, 32, 33, 34, 35, 36, 37, 38, 39, 40,
-	0, 0, 0, 0, 112, 0, 0, 0, 0, 0,
-	3, 0, 0, 43, 45, 48, 0, 135, 0, 68,
-	69, 0, 137, 138, 139, 140, 141, 142, 134, 162,
-	220, 0, 220, 198, 0, 0, 0, 0, 272, 0,
-	0, 0, 0, 0, 0, 112, 0, 0, 0, 0,
-	112, 203, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 234, 0, 4, 112, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	67, 0, 0, 51, 0, 112, 0, 182, 112, 0,
-	220, 220, 220, 0, 220, 0, 0, 0, 220, 275,
-	164, 0, 0, 250, 84, 0, 83, 85, 86, 199,
-	112, 201, 0, 216, 261, 276, 202, 72, 73, 75,
-	88, 0, 111, 113, 0, 135, 0, 0, 0, 124,
-	0, 274, 0, 0, 0, 0, 0, 0, 0, 0,
-	233, 0, 91, 112, 44, 46, 47, 49, 50, 56,
-	57, 58, 59, 60, 61, 62, 63, 64, 0, 66,
-	136, 143, 144, 145, 0, 0, 52, 0, 0, 147,
-	219, 0, 112, 147, 220, 112, 112, 0, 0, 220,
-	0, 220, 147, 0, 220, 263, 220, 112, 163, 0,
-	0, 0, 0, 0, 0, 200, 0, 0, 0, 78,
-	88, 0, 0, 0, 0, 124, 0, 0, 0, 0,
-	0, 0, 126, 127, 128, 129, 130, 131, 132, 133,
-	0, 0, 0, 0, 0, 210, 0, 0, 0, 0,
-	215, 0, 0, 107, 0, 91, 65, 0, 0, 0,
-	0, 157, 0, 181, 147, 157, 112, 112, 91, 112,
-	147, 0, 0, 220, 0, 220, 112, 147, 157, 220,
-	112, 112, 112, 91, 165, 166, 168, 0, 0, 0,
-	0, 173, 0, 0, 0, 0, 0, 0, 0, 253,
-	0, 84, 0, 82, 0, 0, 74, 76, 87, 0,
-	77, 115, 116, -2, 0, 0, 0, 0, 123, 0,
-	0, 0, 0, 0, 209, 0, 0, 0, 214, 0,
-	0, 147, 0, 0, 107, 70, 0, 53, 112, 0,
-	0, 0, 0, 176, 161, 0, 0, 0, 157, 197,
-	112, 91, 91, 157, 147, 157, 0, 0, 0, 0,
-	0, 112, 112, 91, 157, 222, 112, 112, 91, 112,
-	91, 91, 157, 167, 169, 170, 171, 172, 174, 258,
-	260, 0, 0, 0, 0, 0, 185, 249, 253, 0,
-	257, 0, 81, 84, 80, 205, 0, 0, 0, 54,
-	0, 119, 0, 0, 0, 257, 206, 0, 208, 211,
-	0, 213, 262, 157, 0, 90, 92, 96, 94, 101,
-	103, 95, 147, 71, 147, 177, 178, 179, 180, 153,
-	0, 0, 155, 156, 146, 148, 150, 196, 91, 157,
-	157, 271, 157, 218, 0, 112, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 112, 91, 91, 157, 221,
-	112, 91, 91, 157, 91, 157, 157, 267, 0, 192,
-	193, 194, 195, 183, 0, 0, 0, 252, 0, 248,
-	0, 79, 0, 117, 118, 0, 0, 0, 122, 125,
-	204, 273, 207, 212, 105, 0, 108, 109, 110, 0,
-	0, 0, 0, 157, 157, 159, 160, 0, 0, 151,
-	152, 157, 269, 270, 217, 112, 147, 225, 230, 232,
-	226, 0, 228, 229, 0, 0, 0, 112, 91, 157,
-	157, 238, 91, 157, 157, 246, 157, 265, 266, 259,
-	184, 0, 0, 0, 257, 251, 254, 256, 0, 55,
-	120, 121, 41, 0, 106, 93, 97, 0, 102, 105,
-	175, 0, 154, 149, 268, 147, 157, 0, 0, 0,
-	112, 112, 91, 157, 236, 237, 157, 244, 245, 264,
-	0, 186, 187, 247, 0, 0, 0, 98, 0, 42,
-	158, 157, 224, 231, 227, 112, 91, 91, 157, 235,
-	243, 189, 255, 0, 0, 0, 0, 223, 91, 157,
-	157, 242, 188, 190, 89, 104, 99, 0, 157, 240,
-	241, 191, 100, 239,
+	41, 42, 43, 44, 45, 46, 47, 48, 0, 0,
+	0, 0, 120, 0, 0, 0, 0, 0, 0, 3,
+	0, 0, 51, 53, 56, 0, 143, 0, 76, 77,
+	0, 145, 146, 147, 148, 149, 150, 142, 170, 242,
+	0, 242, 206, 0, 0, 0, 290, 300, 0, 310,
+	0, 0, 0, 0, 0, 0, 0, 0, 120, 0,
+	0, 0, 0, 0, 120, 211, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 256, 0,
+	0, 4, 120, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 75, 0,
+	0, 59, 0, 120, 0, 190, 120, 0, 242, 242,
+	242, 0, 242, 0, 0, 0, 0, 242, 313, 172,
+	0, 0, 272, 92, 0, 91, 93, 94, 288, 0,
+	207, 120, 209, 0, 238, 283, 289, 314, 210, 80,
+	81, 83, 96, 0, 119, 121, 0, 143, 0, 0,
+	0, 132, 0, 312, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 299, 255, 284, 0, 99, 120,
+	52, 54, 55, 57, 58, 64, 65, 66, 67, 68,
+	69, 70, 71, 72, 0, 74, 144, 151, 152, 153,
+	0, 0, 60, 0, 0, 155, 241, 0, 120, 155,
+	242, 120, 120, 0, 0, 242, 0, 242, 155, 0,
+	242, 286, 0, 301, 242, 120, 171, 0, 0, 0,
+	0, 0, 0, 0, 208, 0, 0, 0, 86, 96,
+	0, 0, 0, 0, 132, 0, 0, 0, 0, 0,
+	0, 134, 135, 136, 137, 138, 139, 140, 141, 0,
+	0, 0, 221, 0, 0, 228, 0, 0, 0, 0,
+	0, 237, 0, 0, 0, 115, 0, 99, 73, 0,
+	0, 0, 0, 165, 0, 189, 155, 165, 120, 120,
+	99, 120, 155, 0, 0, 242, 0, 242, 120, 155,
+	165, 242, 120, 287, 120, 120, 99, 173, 174, 176,
+	0, 0, 0, 0, 181, 0, 0, 0, 0, 0,
+	0, 0, 275, 0, 92, 0, 90, 0, 291, 0,
+	82, 84, 95, 0, 85, 123, 124, -2, 0, 0,
+	0, 0, 131, 0, 0, 0, 0, 0, 0, 0,
+	227, 0, 220, 0, 0, 236, 0, 235, 0, 155,
+	0, 0, 115, 78, 0, 61, 120, 0, 0, 0,
+	0, 184, 169, 0, 0, 0, 165, 205, 120, 99,
+	99, 165, 155, 165, 0, 0, 0, 0, 0, 120,
+	120, 99, 165, 244, 120, 120, 99, 120, 99, 99,
+	165, 175, 177, 178, 179, 180, 182, 280, 282, 0,
+	0, 0, 0, 0, 193, 271, 275, 0, 279, 0,
+	89, 92, 88, 0, 0, 213, 0, 0, 0, 62,
+	0, 127, 0, 0, 0, 279, 214, 0, 0, 223,
+	224, 0, 216, 0, 229, 0, 0, 231, 0, 285,
+	165, 0, 98, 100, 104, 102, 109, 111, 103, 155,
+	79, 155, 185, 186, 187, 188, 161, 0, 0, 163,
+	164, 154, 156, 158, 204, 99, 165, 165, 309, 165,
+	240, 0, 120, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 120, 99, 99, 165, 243, 120, 99, 99,
+	165, 99, 165, 165, 305, 0, 200, 201, 202, 203,
+	191, 0, 0, 0, 274, 0, 270, 0, 87, 292,
+	293, 295, 0, 0, 0, 125, 126, 0, 0, 0,
+	130, 133, 212, 311, 217, 222, 215, 0, 219, 232,
+	230, 0, 234, 113, 0, 116, 117, 118, 0, 0,
+	0, 0, 165, 165, 167, 168, 0, 0, 159, 160,
+	165, 307, 308, 239, 120, 155, 247, 252, 254, 248,
+	0, 250, 251, 0, 0, 0, 120, 99, 165, 165,
+	260, 99, 165, 165, 268, 165, 303, 304, 281, 192,
+	0, 0, 0, 279, 273, 276, 278, 0, 0, 0,
+	0, 0, 63, 128, 129, 0, 218, 233, 49, 0,
+	114, 101, 105, 0, 110, 113, 183, 0, 162, 157,
+	306, 155, 165, 0, 0, 0, 120, 120, 99, 165,
+	258, 259, 165, 266, 267, 302, 0, 194, 195, 269,
+	0, 294, 296, 297, 298, 0, 225, 226, 0, 106,
+	0, 50, 166, 165, 246, 253, 249, 120, 99, 99,
+	165, 257, 265, 197, 277, 0, 0, 0, 0, 245,
+	99, 165, 165, 264, 196, 198, 97, 112, 107, 0,
+	165, 262, 263, 199, 108, 261,
 }
 
 var yyTok1 = [...]int{
@@ -743,6 +823,7 @@ var yyTok2 = [...]int{
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
 	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
 	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
+	122, 123, 124, 125, 126, 127,
 }
 
 var yyTok3 = [...]int{
@@ -827,9 +908,9 @@ func yyErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := yyPact[state]
+	base := int(yyPact[state])
 	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
-		if n := base + tok; n >= 0 && n < yyLast && yyChk[yyAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -839,13 +920,13 @@ func yyErrorMessage(state, lookAhead int) string {
 
 	if yyDef[state] == -2 {
 		i := 0
-		for yyExca[i] != -1 || yyExca[i+1] != state {
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; yyExca[i] >= 0; i += 2 {
-			tok := yyExca[i]
+			tok := int(yyExca[i])
 			if tok < TOKSTART || yyExca[i+1] == 0 {
 				continue
 			}
@@ -876,30 +957,30 @@ func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		token = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			token = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		token = yyTok3[i+0]
+		token = int(yyTok3[i+0])
 		if token == char {
-			token = yyTok3[i+1]
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = yyTok2[1] /* unknown char */
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
@@ -954,7 +1035,7 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
@@ -965,8 +1046,8 @@ yynewstate:
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yytoken { /* valid shift */
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
 		yyrcvr.char = -1
 		yytoken = -1
 		yyVAL = yyrcvr.lval
@@ -979,7 +1060,7 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
 		if yyrcvr.char < 0 {
 			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
@@ -988,18 +1069,18 @@ yydefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
+			yyn = int(yyExca[xi+0])
 			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -1021,10 +1102,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -1060,7 +1141,7 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
 	// yyp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if yyp+1 >= len(yyS) {
@@ -1071,16 +1152,16 @@ yydefault:
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -1088,19 +1169,19 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:151
+//line sql.y:184
 		{
 			setParseTree(yylex, yyDollar[1].stmts)
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:157
+//line sql.y:190
 		{
 			yyVAL.stmts = []influxql.Statement{yyDollar[1].stmt}
 		}
 	case 3:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:161
+//line sql.y:194
 		{
 
 			if len(yyDollar[1].stmts) == 1 {
@@ -1111,229 +1192,277 @@ yydefault:
 		}
 	case 4:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:170
+//line sql.y:203
 		{
 			yyVAL.stmts = append(yyDollar[1].stmts, yyDollar[3].stmt)
 		}
 	case 5:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:178
+//line sql.y:211
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 6:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:182
+//line sql.y:215
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 7:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:186
+//line sql.y:219
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 8:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:190
+//line sql.y:223
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 9:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:194
+//line sql.y:227
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 10:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:198
+//line sql.y:231
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 11:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:202
+//line sql.y:235
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 12:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:206
+//line sql.y:239
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 13:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:210
+//line sql.y:243
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:214
+//line sql.y:247
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 15:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:218
+//line sql.y:251
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 16:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:222
+//line sql.y:255
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 17:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:226
+//line sql.y:259
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 18:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:230
+//line sql.y:263
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 19:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:234
+//line sql.y:267
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 20:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:238
+//line sql.y:271
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 21:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:242
+//line sql.y:275
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 22:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:246
+//line sql.y:279
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 23:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:250
+//line sql.y:283
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 24:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:254
+//line sql.y:287
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 25:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:258
+//line sql.y:291
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 26:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:262
+//line sql.y:295
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 27:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:266
+//line sql.y:299
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 28:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:270
+//line sql.y:303
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 29:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:274
+//line sql.y:307
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 30:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:278
+//line sql.y:311
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 31:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:282
+//line sql.y:315
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 32:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:286
+//line sql.y:319
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 33:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:290
+//line sql.y:323
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 34:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:294
+//line sql.y:327
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 35:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:298
+//line sql.y:331
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 36:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:302
+//line sql.y:335
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 37:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:306
+//line sql.y:339
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 38:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:310
+//line sql.y:343
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 39:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:314
+//line sql.y:347
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 40:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:318
+//line sql.y:351
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 41:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:355
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 42:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:359
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 43:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:363
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 44:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:367
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 45:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:371
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 46:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:375
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 47:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:379
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 48:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:383
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 49:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:326
+//line sql.y:391
 		{
 			stmt := &influxql.SelectStatement{}
 			stmt.Fields = yyDollar[2].fields
@@ -1361,9 +1490,9 @@ yydefault:
 			stmt.Location = yyDollar[9].location
 			yyVAL.stmt = stmt
 		}
-	case 42:
+	case 50:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line sql.y:354
+//line sql.y:419
 		{
 			stmt := &influxql.SelectStatement{}
 			stmt.Hints = yyDollar[2].hints
@@ -1392,147 +1521,147 @@ yydefault:
 			stmt.Location = yyDollar[10].location
 			yyVAL.stmt = stmt
 		}
-	case 43:
+	case 51:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:387
+//line sql.y:452
 		{
 			yyVAL.fields = []*influxql.Field{yyDollar[1].field}
 		}
-	case 44:
+	case 52:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:391
+//line sql.y:456
 		{
 			yyVAL.fields = append([]*influxql.Field{yyDollar[1].field}, yyDollar[3].fields...)
 		}
-	case 45:
+	case 53:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:397
+//line sql.y:462
 		{
 			yyVAL.field = &influxql.Field{Expr: &influxql.Wildcard{Type: influxql.Token(yyDollar[1].int)}}
 		}
-	case 46:
+	case 54:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:401
+//line sql.y:466
 		{
 			yyVAL.field = &influxql.Field{Expr: &influxql.Wildcard{Type: influxql.TAG}}
 		}
-	case 47:
+	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:405
+//line sql.y:470
 		{
 			yyVAL.field = &influxql.Field{Expr: &influxql.Wildcard{Type: influxql.FIELD}}
 		}
-	case 48:
+	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:409
+//line sql.y:474
 		{
 			yyVAL.field = &influxql.Field{Expr: yyDollar[1].expr}
 		}
-	case 49:
+	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:413
+//line sql.y:478
 		{
 			yyVAL.field = &influxql.Field{Expr: yyDollar[1].expr, Alias: yyDollar[3].str}
 		}
-	case 50:
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:417
+//line sql.y:482
 		{
 			yyVAL.field = &influxql.Field{Expr: yyDollar[1].expr, Alias: yyDollar[3].str}
 		}
-	case 51:
+	case 59:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:423
+//line sql.y:488
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 52:
+	case 60:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:427
+//line sql.y:492
 		{
 			c := yyDollar[1].expr.(*influxql.CaseWhenExpr)
 			c.Conditions = append(c.Conditions, yyDollar[2].expr.(*influxql.CaseWhenExpr).Conditions...)
 			c.Assigners = append(c.Assigners, yyDollar[2].expr.(*influxql.CaseWhenExpr).Assigners...)
 			yyVAL.expr = c
 		}
-	case 53:
+	case 61:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:436
+//line sql.y:501
 		{
 			c := &influxql.CaseWhenExpr{}
 			c.Conditions = []influxql.Expr{yyDollar[2].expr}
 			c.Assigners = []influxql.Expr{yyDollar[4].expr}
 			yyVAL.expr = c
 		}
-	case 54:
+	case 62:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:445
+//line sql.y:510
 		{
 			yyVAL.fields = []*influxql.Field{&influxql.Field{Expr: &influxql.VarRef{Val: yyDollar[1].str}}}
 		}
-	case 55:
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:449
+//line sql.y:514
 		{
 			yyVAL.fields = append([]*influxql.Field{&influxql.Field{Expr: &influxql.VarRef{Val: yyDollar[1].str}}}, yyDollar[3].fields...)
 		}
-	case 56:
+	case 64:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:455
+//line sql.y:520
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.MUL), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 57:
+	case 65:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:459
+//line sql.y:524
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.DIV), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 58:
+	case 66:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:463
+//line sql.y:528
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.ADD), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 59:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:467
+//line sql.y:532
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.SUB), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 60:
+	case 68:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:471
+//line sql.y:536
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.BITWISE_XOR), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 61:
+	case 69:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:475
+//line sql.y:540
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.MOD), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 62:
+	case 70:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:479
+//line sql.y:544
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.BITWISE_AND), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 63:
+	case 71:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:483
+//line sql.y:548
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(influxql.BITWISE_OR), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 64:
+	case 72:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:487
+//line sql.y:552
 		{
 			yyVAL.expr = yyDollar[2].expr
 		}
-	case 65:
+	case 73:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:491
+//line sql.y:556
 		{
 			cols := &influxql.Call{Name: strings.ToLower(yyDollar[1].str), Args: []influxql.Expr{}}
 			for i := range yyDollar[3].fields {
@@ -1540,16 +1669,16 @@ yydefault:
 			}
 			yyVAL.expr = cols
 		}
-	case 66:
+	case 74:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:499
+//line sql.y:564
 		{
 			cols := &influxql.Call{Name: strings.ToLower(yyDollar[1].str)}
 			yyVAL.expr = cols
 		}
-	case 67:
+	case 75:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:504
+//line sql.y:569
 		{
 			switch s := yyDollar[2].expr.(type) {
 			case *influxql.NumberLiteral:
@@ -1563,66 +1692,66 @@ yydefault:
 			}
 
 		}
-	case 68:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:518
+//line sql.y:583
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 69:
+	case 77:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:522
+//line sql.y:587
 		{
 			yyVAL.expr = &influxql.DurationLiteral{Val: yyDollar[1].tdur}
 		}
-	case 70:
+	case 78:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:526
+//line sql.y:591
 		{
 			c := yyDollar[2].expr.(*influxql.CaseWhenExpr)
 			c.Assigners = append(c.Assigners, yyDollar[4].expr)
 			yyVAL.expr = c
 		}
-	case 71:
+	case 79:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:532
+//line sql.y:597
 		{
 			yyVAL.expr = &influxql.VarRef{}
 		}
-	case 72:
+	case 80:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:538
+//line sql.y:603
 		{
 			yyVAL.sources = yyDollar[2].sources
 		}
-	case 73:
+	case 81:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:544
+//line sql.y:609
 		{
 			yyVAL.sources = []influxql.Source{yyDollar[1].ment}
 		}
-	case 74:
+	case 82:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:548
+//line sql.y:613
 		{
 			yyVAL.sources = append([]influxql.Source{yyDollar[1].ment}, yyDollar[3].sources...)
 		}
-	case 75:
+	case 83:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:552
+//line sql.y:617
 		{
 			yyVAL.sources = yyDollar[1].sources
 
 		}
-	case 76:
+	case 84:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:557
+//line sql.y:622
 		{
 			yyVAL.sources = append(yyDollar[1].sources, yyDollar[3].sources...)
 		}
-	case 77:
+	case 85:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:563
+//line sql.y:628
 		{
 			all_subquerys := []influxql.Source{}
 			for _, temp_stmt := range yyDollar[2].stmts {
@@ -1636,66 +1765,66 @@ yydefault:
 			}
 			yyVAL.sources = all_subquerys
 		}
-	case 78:
+	case 86:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:579
+//line sql.y:644
 		{
 			yyVAL.ment = yyDollar[1].ment
 		}
-	case 79:
+	case 87:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:585
+//line sql.y:650
 		{
 			mst := yyDollar[5].ment
 			mst.Database = yyDollar[1].str
 			mst.RetentionPolicy = yyDollar[3].str
 			yyVAL.ment = mst
 		}
-	case 80:
+	case 88:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:592
+//line sql.y:657
 		{
 			mst := yyDollar[4].ment
 			mst.RetentionPolicy = yyDollar[2].str
 			yyVAL.ment = mst
 		}
-	case 81:
+	case 89:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:598
+//line sql.y:663
 		{
 			mst := yyDollar[4].ment
 			mst.Database = yyDollar[1].str
 			yyVAL.ment = mst
 		}
-	case 82:
+	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:604
+//line sql.y:669
 		{
 			mst := yyDollar[3].ment
 			mst.RetentionPolicy = yyDollar[1].str
 			yyVAL.ment = mst
 		}
-	case 83:
+	case 91:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:610
+//line sql.y:675
 		{
 			yyVAL.ment = yyDollar[1].ment
 		}
-	case 84:
+	case 92:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:616
+//line sql.y:681
 		{
 			yyVAL.ment = &influxql.Measurement{Name: yyDollar[1].str}
 		}
-	case 85:
+	case 93:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:620
+//line sql.y:685
 		{
 			yyVAL.ment = &influxql.Measurement{Name: yyDollar[1].str}
 		}
-	case 86:
+	case 94:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:624
+//line sql.y:689
 		{
 			re, err := regexp.Compile(yyDollar[1].str)
 			if err != nil {
@@ -1704,75 +1833,75 @@ yydefault:
 
 			yyVAL.ment = &influxql.Measurement{Regex: &influxql.RegexLiteral{Val: re}}
 		}
-	case 87:
+	case 95:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:635
+//line sql.y:700
 		{
 			yyVAL.ment = &influxql.Measurement{}
 		}
-	case 88:
+	case 96:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:639
+//line sql.y:704
 		{
 			yyVAL.ment = &influxql.Measurement{}
 		}
-	case 89:
+	case 97:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:645
+//line sql.y:710
 		{
 			yyVAL.ment = &influxql.Measurement{}
 		}
-	case 90:
+	case 98:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:651
+//line sql.y:716
 		{
 			yyVAL.dimens = yyDollar[3].dimens
 		}
-	case 91:
+	case 99:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:655
+//line sql.y:720
 		{
 			yyVAL.dimens = nil
 		}
-	case 92:
+	case 100:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:661
+//line sql.y:726
 		{
 			yyVAL.dimens = []*influxql.Dimension{yyDollar[1].dimen}
 		}
-	case 93:
+	case 101:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:665
+//line sql.y:730
 		{
 			yyVAL.dimens = append([]*influxql.Dimension{yyDollar[1].dimen}, yyDollar[3].dimens...)
 		}
-	case 94:
+	case 102:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:671
+//line sql.y:736
 		{
 			yyVAL.str = yyDollar[1].str
 		}
-	case 95:
+	case 103:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:675
+//line sql.y:740
 		{
 			yyVAL.str = yyDollar[1].str
 		}
-	case 96:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:681
+//line sql.y:746
 		{
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.VarRef{Val: yyDollar[1].str}}
 		}
-	case 97:
+	case 105:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:685
+//line sql.y:750
 		{
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.VarRef{Val: yyDollar[1].str}}
 		}
-	case 98:
+	case 106:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:689
+//line sql.y:754
 		{
 			if strings.ToLower(yyDollar[1].str) != "time" {
 				yylex.Error("Invalid group by combination for no-time tag and time duration")
@@ -1780,9 +1909,9 @@ yydefault:
 
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.Call{Name: "time", Args: []influxql.Expr{&influxql.DurationLiteral{Val: yyDollar[3].tdur}}}}
 		}
-	case 99:
+	case 107:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:697
+//line sql.y:762
 		{
 			if strings.ToLower(yyDollar[1].str) != "time" {
 				yylex.Error("Invalid group by combination for no-time tag and time duration")
@@ -1790,9 +1919,9 @@ yydefault:
 
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.Call{Name: "time", Args: []influxql.Expr{&influxql.DurationLiteral{Val: yyDollar[3].tdur}, &influxql.DurationLiteral{Val: yyDollar[5].tdur}}}}
 		}
-	case 100:
+	case 108:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:705
+//line sql.y:770
 		{
 			if strings.ToLower(yyDollar[1].str) != "time" {
 				yylex.Error("Invalid group by combination for no-time tag and time duration")
@@ -1800,21 +1929,21 @@ yydefault:
 
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.Call{Name: "time", Args: []influxql.Expr{&influxql.DurationLiteral{Val: yyDollar[3].tdur}, &influxql.DurationLiteral{Val: time.Duration(-yyDollar[6].tdur)}}}}
 		}
-	case 101:
+	case 109:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:713
+//line sql.y:778
 		{
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.Wildcard{Type: influxql.Token(yyDollar[1].int)}}
 		}
-	case 102:
+	case 110:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:717
+//line sql.y:782
 		{
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.Wildcard{Type: influxql.Token(yyDollar[1].int)}}
 		}
-	case 103:
+	case 111:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:721
+//line sql.y:786
 		{
 			re, err := regexp.Compile(yyDollar[1].str)
 			if err != nil {
@@ -1822,9 +1951,9 @@ yydefault:
 			}
 			yyVAL.dimen = &influxql.Dimension{Expr: &influxql.RegexLiteral{Val: re}}
 		}
-	case 104:
+	case 112:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:732
+//line sql.y:797
 		{
 			if strings.ToLower(yyDollar[1].str) != "tz" {
 				yylex.Error("Expect tz")
@@ -1835,117 +1964,117 @@ yydefault:
 			}
 			yyVAL.location = loc
 		}
-	case 105:
+	case 113:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:743
+//line sql.y:808
 		{
 			yyVAL.location = nil
 		}
-	case 106:
+	case 114:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:749
+//line sql.y:814
 		{
 			yyVAL.inter = yyDollar[3].inter
 		}
-	case 107:
+	case 115:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:753
+//line sql.y:818
 		{
 			yyVAL.inter = "null"
 		}
-	case 108:
+	case 116:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:759
+//line sql.y:824
 		{
 			yyVAL.inter = yyDollar[1].str
 		}
-	case 109:
+	case 117:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:763
+//line sql.y:828
 		{
 			yyVAL.inter = yyDollar[1].int64
 		}
-	case 110:
+	case 118:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:767
+//line sql.y:832
 		{
 			yyVAL.inter = yyDollar[1].float64
 		}
-	case 111:
+	case 119:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:773
+//line sql.y:838
 		{
 			yyVAL.expr = yyDollar[2].expr
 		}
-	case 112:
+	case 120:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:777
+//line sql.y:842
 		{
 			yyVAL.expr = nil
 		}
-	case 113:
+	case 121:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:783
+//line sql.y:848
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 114:
+	case 122:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:787
+//line sql.y:852
 		{
 			yyVAL.expr = &influxql.ParenExpr{Expr: yyDollar[2].expr}
 		}
-	case 115:
+	case 123:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:791
+//line sql.y:856
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(yyDollar[2].int), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 116:
+	case 124:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:795
+//line sql.y:860
 		{
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(yyDollar[2].int), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 117:
+	case 125:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:799
+//line sql.y:864
 		{
 			yyVAL.expr = &influxql.BinaryExpr{}
 		}
-	case 118:
+	case 126:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:803
+//line sql.y:868
 		{
 			yyVAL.expr = &influxql.BinaryExpr{}
 		}
-	case 119:
+	case 127:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:807
+//line sql.y:872
 		{
 			yyVAL.expr = &influxql.BinaryExpr{}
 		}
-	case 120:
+	case 128:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:811
+//line sql.y:876
 		{
 			yyVAL.expr = &influxql.BinaryExpr{}
 		}
-	case 121:
+	case 129:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:815
+//line sql.y:880
 		{
 			yyVAL.expr = &influxql.BinaryExpr{}
 		}
-	case 122:
+	case 130:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:819
+//line sql.y:884
 		{
 			yyVAL.expr = &influxql.BinaryExpr{}
 		}
-	case 123:
+	case 131:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:825
+//line sql.y:890
 		{
 			if yyDollar[2].int == influxql.NEQREGEX {
 				switch yyDollar[3].expr.(type) {
@@ -1956,117 +2085,117 @@ yydefault:
 			}
 			yyVAL.expr = &influxql.BinaryExpr{Op: influxql.Token(yyDollar[2].int), LHS: yyDollar[1].expr, RHS: yyDollar[3].expr}
 		}
-	case 124:
+	case 132:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:838
+//line sql.y:903
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 125:
+	case 133:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:842
+//line sql.y:907
 		{
 			yyVAL.expr = &influxql.ParenExpr{Expr: yyDollar[2].expr}
 		}
-	case 126:
+	case 134:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:848
+//line sql.y:913
 		{
 			yyVAL.int = influxql.EQ
 		}
-	case 127:
+	case 135:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:852
+//line sql.y:917
 		{
 			yyVAL.int = influxql.NEQ
 		}
-	case 128:
+	case 136:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:856
+//line sql.y:921
 		{
 			yyVAL.int = influxql.LT
 		}
-	case 129:
+	case 137:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:860
+//line sql.y:925
 		{
 			yyVAL.int = influxql.LTE
 		}
-	case 130:
+	case 138:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:864
+//line sql.y:929
 		{
 			yyVAL.int = influxql.GT
 		}
-	case 131:
+	case 139:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:868
+//line sql.y:933
 		{
 			yyVAL.int = influxql.GTE
 		}
-	case 132:
+	case 140:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:872
+//line sql.y:937
 		{
 			yyVAL.int = influxql.EQREGEX
 		}
-	case 133:
+	case 141:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:876
+//line sql.y:941
 		{
 			yyVAL.int = influxql.NEQREGEX
 		}
-	case 134:
+	case 142:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:882
+//line sql.y:947
 		{
 			yyVAL.str = yyDollar[1].str
 		}
-	case 135:
+	case 143:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:888
+//line sql.y:953
 		{
 			yyVAL.expr = &influxql.VarRef{Val: yyDollar[1].str}
 		}
-	case 136:
+	case 144:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:892
+//line sql.y:957
 		{
 			yyVAL.expr = &influxql.VarRef{Val: yyDollar[1].str, Type: yyDollar[3].dataType}
 		}
-	case 137:
+	case 145:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:896
+//line sql.y:961
 		{
 			yyVAL.expr = &influxql.NumberLiteral{Val: yyDollar[1].float64}
 		}
-	case 138:
+	case 146:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:900
+//line sql.y:965
 		{
 			yyVAL.expr = &influxql.IntegerLiteral{Val: yyDollar[1].int64}
 		}
-	case 139:
+	case 147:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:904
+//line sql.y:969
 		{
 			yyVAL.expr = &influxql.StringLiteral{Val: yyDollar[1].str}
 		}
-	case 140:
+	case 148:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:908
+//line sql.y:973
 		{
 			yyVAL.expr = &influxql.BooleanLiteral{Val: true}
 		}
-	case 141:
+	case 149:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:912
+//line sql.y:977
 		{
 			yyVAL.expr = &influxql.BooleanLiteral{Val: false}
 		}
-	case 142:
+	case 150:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:916
+//line sql.y:981
 		{
 			re, err := regexp.Compile(yyDollar[1].str)
 			if err != nil {
@@ -2074,9 +2203,9 @@ yydefault:
 			}
 			yyVAL.expr = &influxql.RegexLiteral{Val: re}
 		}
-	case 143:
+	case 151:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:926
+//line sql.y:991
 		{
 			switch strings.ToLower(yyDollar[1].str) {
 			case "float":
@@ -2097,141 +2226,141 @@ yydefault:
 				yylex.Error("wrong field dataType")
 			}
 		}
-	case 144:
+	case 152:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:947
+//line sql.y:1012
 		{
 			yyVAL.dataType = influxql.Tag
 		}
-	case 145:
+	case 153:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:951
+//line sql.y:1016
 		{
 			yyVAL.dataType = influxql.AnyField
 		}
-	case 146:
+	case 154:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:957
+//line sql.y:1022
 		{
 			yyVAL.sortfs = yyDollar[3].sortfs
 		}
-	case 147:
+	case 155:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:961
+//line sql.y:1026
 		{
 			yyVAL.sortfs = nil
 		}
-	case 148:
+	case 156:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:967
+//line sql.y:1032
 		{
 			yyVAL.sortfs = []*influxql.SortField{yyDollar[1].sortf}
 		}
-	case 149:
+	case 157:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:971
+//line sql.y:1036
 		{
 			yyVAL.sortfs = append([]*influxql.SortField{yyDollar[1].sortf}, yyDollar[3].sortfs...)
 		}
-	case 150:
+	case 158:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:977
+//line sql.y:1042
 		{
 			yyVAL.sortf = &influxql.SortField{Name: yyDollar[1].str, Ascending: true}
 		}
-	case 151:
+	case 159:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:981
+//line sql.y:1046
 		{
 			yyVAL.sortf = &influxql.SortField{Name: yyDollar[1].str, Ascending: false}
 		}
-	case 152:
+	case 160:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:985
+//line sql.y:1050
 		{
 			yyVAL.sortf = &influxql.SortField{Name: yyDollar[1].str, Ascending: true}
 		}
-	case 153:
+	case 161:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:991
+//line sql.y:1056
 		{
 			yyVAL.intSlice = append(yyDollar[1].intSlice, yyDollar[2].intSlice...)
 		}
-	case 154:
+	case 162:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:997
+//line sql.y:1062
 		{
 			yyVAL.intSlice = []int{int(yyDollar[2].int64), int(yyDollar[4].int64)}
 		}
-	case 155:
+	case 163:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1001
+//line sql.y:1066
 		{
 			yyVAL.intSlice = []int{int(yyDollar[2].int64), 0}
 		}
-	case 156:
+	case 164:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1005
+//line sql.y:1070
 		{
 			yyVAL.intSlice = []int{0, int(yyDollar[2].int64)}
 		}
-	case 157:
+	case 165:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:1009
+//line sql.y:1074
 		{
 			yyVAL.intSlice = []int{0, 0}
 		}
-	case 158:
+	case 166:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:1015
+//line sql.y:1080
 		{
 			yyVAL.intSlice = []int{int(yyDollar[2].int64), int(yyDollar[4].int64)}
 		}
-	case 159:
+	case 167:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1019
+//line sql.y:1084
 		{
 			yyVAL.intSlice = []int{int(yyDollar[2].int64), 0}
 		}
-	case 160:
+	case 168:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1023
+//line sql.y:1088
 		{
 			yyVAL.intSlice = []int{0, int(yyDollar[2].int64)}
 		}
-	case 161:
+	case 169:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:1027
+//line sql.y:1092
 		{
 			yyVAL.intSlice = []int{0, 0}
 		}
-	case 162:
+	case 170:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1033
+//line sql.y:1098
 		{
 			yyVAL.stmt = &influxql.ShowDatabasesStatement{}
 		}
-	case 163:
+	case 171:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:1039
+//line sql.y:1104
 		{
 			sms := yyDollar[4].stmt
 
 			sms.(*influxql.CreateDatabaseStatement).Name = yyDollar[3].str
 			yyVAL.stmt = sms
 		}
-	case 164:
+	case 172:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1046
+//line sql.y:1111
 		{
 			stmt := &influxql.CreateDatabaseStatement{}
 			stmt.RetentionPolicyCreate = false
 			stmt.Name = yyDollar[3].str
 			yyVAL.stmt = stmt
 		}
-	case 165:
+	case 173:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1055
+//line sql.y:1120
 		{
 			stmt := &influxql.CreateDatabaseStatement{}
 			stmt.RetentionPolicyCreate = true
@@ -2271,15 +2400,15 @@ yydefault:
 			stmt.ReplicaNum = yyDollar[2].durations.ReplicaNum
 			yyVAL.stmt = stmt
 		}
-	case 166:
+	case 174:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:1099
+//line sql.y:1164
 		{
 			yyVAL.durations = yyDollar[1].durations
 		}
-	case 167:
+	case 175:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1103
+//line sql.y:1168
 		{
 			if yyDollar[1].durations.ShardGroupDuration < 0 || yyDollar[2].durations.ShardGroupDuration < 0 {
 				if yyDollar[2].durations.ShardGroupDuration >= 0 {
@@ -2356,21 +2485,21 @@ yydefault:
 			}
 			yyVAL.durations = yyDollar[1].durations
 		}
-	case 168:
+	case 176:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:1182
+//line sql.y:1247
 		{
 			yyVAL.durations = yyDollar[1].durations
 		}
-	case 169:
+	case 177:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1186
+//line sql.y:1251
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: -1, PolicyDuration: &yyDollar[2].tdur}
 		}
-	case 170:
+	case 178:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1190
+//line sql.y:1255
 		{
 			if yyDollar[2].int64 < 1 || yyDollar[2].int64 > 2147483647 {
 				yylex.Error("REPLICATION must be 1 <= n <= 2147483647")
@@ -2378,36 +2507,36 @@ yydefault:
 			int_integer := *(*int)(unsafe.Pointer(&yyDollar[2].int64))
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: -1, Replication: &int_integer}
 		}
-	case 171:
+	case 179:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1198
+//line sql.y:1263
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: -1, PolicyName: yyDollar[2].str}
 		}
-	case 172:
+	case 180:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1202
+//line sql.y:1267
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: -1, ReplicaNum: uint32(yyDollar[2].int64)}
 		}
-	case 173:
+	case 181:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:1206
+//line sql.y:1271
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: -1, rpdefault: true}
 		}
-	case 174:
+	case 182:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1210
+//line sql.y:1275
 		{
 			if len(yyDollar[2].strSlice) == 0 {
 				yylex.Error("ShardKey should not be nil")
 			}
 			yyVAL.durations = &Durations{ShardKey: yyDollar[2].strSlice, ShardGroupDuration: -1, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: -1, rpdefault: false}
 		}
-	case 175:
+	case 183:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1221
+//line sql.y:1286
 		{
 			sms := &influxql.ShowMeasurementsStatement{}
 			sms.Database = yyDollar[3].str
@@ -2418,9 +2547,9 @@ yydefault:
 			sms.Offset = yyDollar[9].intSlice[1]
 			yyVAL.stmt = sms
 		}
-	case 176:
+	case 184:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1232
+//line sql.y:1297
 		{
 			sms := &influxql.ShowMeasurementsStatement{}
 			sms.Database = yyDollar[3].str
@@ -2430,21 +2559,21 @@ yydefault:
 			sms.Offset = yyDollar[6].intSlice[1]
 			yyVAL.stmt = sms
 		}
-	case 177:
+	case 185:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1245
+//line sql.y:1310
 		{
 			yyVAL.ment = &influxql.Measurement{Name: yyDollar[2].str}
 		}
-	case 178:
+	case 186:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1249
+//line sql.y:1314
 		{
 			yyVAL.ment = &influxql.Measurement{Name: yyDollar[2].str}
 		}
-	case 179:
+	case 187:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1253
+//line sql.y:1318
 		{
 			re, err := regexp.Compile(yyDollar[2].str)
 			if err != nil {
@@ -2452,9 +2581,9 @@ yydefault:
 			}
 			yyVAL.ment = &influxql.Measurement{Regex: &influxql.RegexLiteral{Val: re}}
 		}
-	case 180:
+	case 188:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1261
+//line sql.y:1326
 		{
 			re, err := regexp.Compile(yyDollar[2].str)
 			if err != nil {
@@ -2462,32 +2591,32 @@ yydefault:
 			}
 			yyVAL.ment = &influxql.Measurement{Regex: &influxql.RegexLiteral{Val: re}}
 		}
-	case 181:
+	case 189:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:1273
+//line sql.y:1338
 		{
 			yyVAL.stmt = &influxql.ShowRetentionPoliciesStatement{
 				Database: yyDollar[5].str,
 			}
 		}
-	case 182:
+	case 190:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1279
+//line sql.y:1344
 		{
 			yyVAL.stmt = &influxql.ShowRetentionPoliciesStatement{}
 		}
-	case 183:
+	case 191:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1286
+//line sql.y:1351
 		{
 			stmt := yyDollar[7].stmt.(*influxql.CreateRetentionPolicyStatement)
 			stmt.Name = yyDollar[4].str
 			stmt.Database = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 184:
+	case 192:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:1293
+//line sql.y:1358
 		{
 			stmt := yyDollar[7].stmt.(*influxql.CreateRetentionPolicyStatement)
 			stmt.Name = yyDollar[4].str
@@ -2495,18 +2624,18 @@ yydefault:
 			stmt.Default = true
 			yyVAL.stmt = stmt
 		}
-	case 185:
+	case 193:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1303
+//line sql.y:1368
 		{
 			stmt := &influxql.CreateUserStatement{}
 			stmt.Name = yyDollar[3].str
 			stmt.Password = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 186:
+	case 194:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1310
+//line sql.y:1375
 		{
 			stmt := &influxql.CreateUserStatement{}
 			stmt.Name = yyDollar[3].str
@@ -2514,9 +2643,9 @@ yydefault:
 			stmt.Admin = true
 			yyVAL.stmt = stmt
 		}
-	case 187:
+	case 195:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1318
+//line sql.y:1383
 		{
 			stmt := &influxql.CreateUserStatement{}
 			stmt.Name = yyDollar[3].str
@@ -2524,9 +2653,9 @@ yydefault:
 			stmt.Rwuser = true
 			yyVAL.stmt = stmt
 		}
-	case 188:
+	case 196:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:1329
+//line sql.y:1394
 		{
 			stmt := &influxql.CreateRetentionPolicyStatement{}
 			stmt.Duration = yyDollar[2].tdur
@@ -2561,9 +2690,9 @@ yydefault:
 
 			yyVAL.stmt = stmt
 		}
-	case 189:
+	case 197:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:1364
+//line sql.y:1429
 		{
 			stmt := &influxql.CreateRetentionPolicyStatement{}
 			stmt.Duration = yyDollar[2].tdur
@@ -2573,15 +2702,15 @@ yydefault:
 			stmt.Replication = int(yyDollar[4].int64)
 			yyVAL.stmt = stmt
 		}
-	case 190:
+	case 198:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:1377
+//line sql.y:1442
 		{
 			yyVAL.durations = yyDollar[1].durations
 		}
-	case 191:
+	case 199:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1381
+//line sql.y:1446
 		{
 			if yyDollar[1].durations.ShardGroupDuration < 0 || yyDollar[2].durations.ShardGroupDuration < 0 {
 				if yyDollar[2].durations.ShardGroupDuration >= 0 {
@@ -2616,33 +2745,33 @@ yydefault:
 			}
 			yyVAL.durations = yyDollar[1].durations
 		}
-	case 192:
+	case 200:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1419
+//line sql.y:1484
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: yyDollar[3].tdur, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: -1}
 		}
-	case 193:
+	case 201:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1423
+//line sql.y:1488
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: yyDollar[3].tdur, WarmDuration: -1, IndexGroupDuration: -1}
 		}
-	case 194:
+	case 202:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1427
+//line sql.y:1492
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: -1, WarmDuration: yyDollar[3].tdur, IndexGroupDuration: -1}
 		}
-	case 195:
+	case 203:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1431
+//line sql.y:1496
 		{
 			yyVAL.durations = &Durations{ShardGroupDuration: -1, HotDuration: -1, WarmDuration: -1, IndexGroupDuration: yyDollar[3].tdur}
 		}
-	case 196:
+	case 204:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1439
+//line sql.y:1504
 		{
 			stmt := &influxql.ShowSeriesStatement{}
 			stmt.Database = yyDollar[3].str
@@ -2653,9 +2782,9 @@ yydefault:
 			stmt.Offset = yyDollar[7].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 197:
+	case 205:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1450
+//line sql.y:1515
 		{
 			stmt := &influxql.ShowSeriesStatement{}
 			stmt.Database = yyDollar[3].str
@@ -2665,57 +2794,57 @@ yydefault:
 			stmt.Offset = yyDollar[6].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 198:
+	case 206:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1462
+//line sql.y:1527
 		{
 			yyVAL.stmt = &influxql.ShowUsersStatement{}
 		}
-	case 199:
+	case 207:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1468
+//line sql.y:1533
 		{
 			stmt := &influxql.DropDatabaseStatement{}
 			stmt.Name = yyDollar[3].str
 			yyVAL.stmt = stmt
 		}
-	case 200:
+	case 208:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:1476
+//line sql.y:1541
 		{
 			stmt := &influxql.DropSeriesStatement{}
 			stmt.Sources = yyDollar[3].sources
 			stmt.Condition = yyDollar[4].expr
 			yyVAL.stmt = stmt
 		}
-	case 201:
+	case 209:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1483
+//line sql.y:1548
 		{
 			stmt := &influxql.DropSeriesStatement{}
 			stmt.Condition = yyDollar[3].expr
 			yyVAL.stmt = stmt
 		}
-	case 202:
+	case 210:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1491
+//line sql.y:1556
 		{
 			stmt := &influxql.DeleteSeriesStatement{}
 			stmt.Sources = yyDollar[2].sources
 			stmt.Condition = yyDollar[3].expr
 			yyVAL.stmt = stmt
 		}
-	case 203:
+	case 211:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1498
+//line sql.y:1563
 		{
 			stmt := &influxql.DeleteSeriesStatement{}
 			stmt.Condition = yyDollar[2].expr
 			yyVAL.stmt = stmt
 		}
-	case 204:
+	case 212:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1507
+//line sql.y:1572
 		{
 			stmt := &influxql.AlterRetentionPolicyStatement{}
 			stmt.Name = yyDollar[4].str
@@ -2750,122 +2879,192 @@ yydefault:
 			yyVAL.stmt = stmt
 
 		}
-	case 205:
+	case 213:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1546
+//line sql.y:1611
 		{
 			stmt := &influxql.DropRetentionPolicyStatement{}
 			stmt.Name = yyDollar[4].str
 			stmt.Database = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 206:
+	case 214:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1555
+//line sql.y:1620
 		{
 			stmt := &influxql.GrantStatement{}
 			stmt.Privilege = influxql.AllPrivileges
-			stmt.On = yyDollar[4].str
+			stmt.On = userPrivilegeScope(yylex, yyDollar[4].ment)
 			stmt.User = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 207:
+	case 215:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1563
+//line sql.y:1628
 		{
 			stmt := &influxql.GrantStatement{}
 			stmt.Privilege = influxql.AllPrivileges
-			stmt.On = yyDollar[5].str
+			stmt.On = userPrivilegeScope(yylex, yyDollar[5].ment)
 			stmt.User = yyDollar[7].str
 			yyVAL.stmt = stmt
 		}
-	case 208:
+	case 216:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1571
+//line sql.y:1636
 		{
 			stmt := &influxql.GrantStatement{}
-			switch strings.ToLower(yyDollar[2].str) {
-			case "read":
-				stmt.Privilege = influxql.ReadPrivilege
-			case "write":
-				stmt.Privilege = influxql.WritePrivilege
-			default:
-				yylex.Error("wrong Privilege")
-			}
-			stmt.On = yyDollar[4].str
+			stmt.Privilege = privilegeByName(yylex, yyDollar[2].str)
+			stmt.On = userPrivilegeScope(yylex, yyDollar[4].ment)
 			stmt.User = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 209:
+	case 217:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line sql.y:1644
+		{
+			yyVAL.stmt = &influxql.GrantToRoleStatement{Privilege: influxql.AllPrivileges, On: yyDollar[4].ment, Role: yyDollar[7].str}
+		}
+	case 218:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line sql.y:1648
+		{
+			yyVAL.stmt = &influxql.GrantToRoleStatement{Privilege: influxql.AllPrivileges, On: yyDollar[5].ment, Role: yyDollar[8].str}
+		}
+	case 219:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line sql.y:1652
+		{
+			yyVAL.stmt = &influxql.GrantToRoleStatement{Privilege: privilegeByName(yylex, yyDollar[2].str), On: yyDollar[4].ment, Role: yyDollar[7].str}
+		}
+	case 220:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:1588
+//line sql.y:1656
+		{
+			yyVAL.stmt = &influxql.GrantRoleStatement{Role: yyDollar[3].str, User: yyDollar[5].str}
+		}
+	case 221:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:1662
+		{
+			yyVAL.ment = &influxql.Measurement{Database: yyDollar[1].str}
+		}
+	case 222:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line sql.y:1666
+		{
+			yyVAL.ment = &influxql.Measurement{Database: yyDollar[1].str, RetentionPolicy: yyDollar[4].str}
+		}
+	case 223:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:1670
+		{
+			yyVAL.ment = &influxql.Measurement{Database: yyDollar[1].str, Name: yyDollar[3].str}
+		}
+	case 224:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:1674
+		{
+			yyVAL.ment = &influxql.Measurement{Database: yyDollar[1].str, Regex: regexLiteral(yylex, yyDollar[3].str)}
+		}
+	case 225:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line sql.y:1678
+		{
+			yyVAL.ment = &influxql.Measurement{Database: yyDollar[1].str, RetentionPolicy: yyDollar[4].str, Name: yyDollar[6].str}
+		}
+	case 226:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line sql.y:1682
+		{
+			yyVAL.ment = &influxql.Measurement{Database: yyDollar[1].str, RetentionPolicy: yyDollar[4].str, Regex: regexLiteral(yylex, yyDollar[6].str)}
+		}
+	case 227:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line sql.y:1688
 		{
 			yyVAL.stmt = &influxql.GrantAdminStatement{User: yyDollar[5].str}
 		}
-	case 210:
+	case 228:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:1592
+//line sql.y:1692
 		{
 			yyVAL.stmt = &influxql.GrantAdminStatement{User: yyDollar[4].str}
 		}
-	case 211:
+	case 229:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1598
+//line sql.y:1698
 		{
 			stmt := &influxql.RevokeStatement{}
 			stmt.Privilege = influxql.AllPrivileges
-			stmt.On = yyDollar[4].str
+			stmt.On = userPrivilegeScope(yylex, yyDollar[4].ment)
 			stmt.User = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 212:
+	case 230:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1606
+//line sql.y:1706
 		{
 			stmt := &influxql.RevokeStatement{}
 			stmt.Privilege = influxql.AllPrivileges
-			stmt.On = yyDollar[5].str
+			stmt.On = userPrivilegeScope(yylex, yyDollar[5].ment)
 			stmt.User = yyDollar[7].str
 			yyVAL.stmt = stmt
 		}
-	case 213:
+	case 231:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1614
+//line sql.y:1714
 		{
 			stmt := &influxql.RevokeStatement{}
-			switch strings.ToLower(yyDollar[2].str) {
-			case "read":
-				stmt.Privilege = influxql.ReadPrivilege
-			case "write":
-				stmt.Privilege = influxql.WritePrivilege
-			default:
-				yylex.Error("wrong Privilege")
-			}
-			stmt.On = yyDollar[4].str
+			stmt.Privilege = privilegeByName(yylex, yyDollar[2].str)
+			stmt.On = userPrivilegeScope(yylex, yyDollar[4].ment)
 			stmt.User = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 214:
+	case 232:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line sql.y:1722
+		{
+			yyVAL.stmt = &influxql.RevokeFromRoleStatement{Privilege: influxql.AllPrivileges, On: yyDollar[4].ment, Role: yyDollar[7].str}
+		}
+	case 233:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line sql.y:1726
+		{
+			yyVAL.stmt = &influxql.RevokeFromRoleStatement{Privilege: influxql.AllPrivileges, On: yyDollar[5].ment, Role: yyDollar[8].str}
+		}
+	case 234:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line sql.y:1730
+		{
+			yyVAL.stmt = &influxql.RevokeFromRoleStatement{Privilege: privilegeByName(yylex, yyDollar[2].str), On: yyDollar[4].ment, Role: yyDollar[7].str}
+		}
+	case 235:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line sql.y:1631
+//line sql.y:1734
+		{
+			yyVAL.stmt = &influxql.RevokeRoleStatement{Role: yyDollar[3].str, User: yyDollar[5].str}
+		}
+	case 236:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line sql.y:1740
 		{
 			yyVAL.stmt = &influxql.RevokeAdminStatement{User: yyDollar[5].str}
 		}
-	case 215:
+	case 237:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:1635
+//line sql.y:1744
 		{
 			yyVAL.stmt = &influxql.RevokeAdminStatement{User: yyDollar[4].str}
 		}
-	case 216:
+	case 238:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1641
+//line sql.y:1750
 		{
 			yyVAL.stmt = &influxql.DropUserStatement{Name: yyDollar[3].str}
 		}
-	case 217:
+	case 239:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:1647
+//line sql.y:1756
 		{
 			stmt := &influxql.ShowTagKeysStatement{}
 			stmt.Database = yyDollar[4].str
@@ -2879,9 +3078,9 @@ yydefault:
 			yyVAL.stmt = stmt
 
 		}
-	case 218:
+	case 240:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1661
+//line sql.y:1770
 		{
 			stmt := &influxql.ShowTagKeysStatement{}
 			stmt.Database = yyDollar[4].str
@@ -2893,21 +3092,21 @@ yydefault:
 			stmt.SOffset = yyDollar[7].intSlice[3]
 			yyVAL.stmt = stmt
 		}
-	case 219:
+	case 241:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1675
+//line sql.y:1784
 		{
 			yyVAL.str = yyDollar[2].str
 		}
-	case 220:
+	case 242:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:1679
+//line sql.y:1788
 		{
 			yyVAL.str = ""
 		}
-	case 221:
+	case 243:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1685
+//line sql.y:1794
 		{
 			stmt := &influxql.ShowFieldKeysStatement{}
 			stmt.Database = yyDollar[4].str
@@ -2917,9 +3116,9 @@ yydefault:
 			stmt.Offset = yyDollar[7].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 222:
+	case 244:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:1695
+//line sql.y:1804
 		{
 			stmt := &influxql.ShowFieldKeysStatement{}
 			stmt.Database = yyDollar[4].str
@@ -2928,9 +3127,9 @@ yydefault:
 			stmt.Offset = yyDollar[6].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 223:
+	case 245:
 		yyDollar = yyS[yypt-11 : yypt+1]
-//line sql.y:1707
+//line sql.y:1816
 		{
 			stmt := yyDollar[8].stmt.(*influxql.ShowTagValuesStatement)
 			stmt.TagKeyCondition = nil
@@ -2943,9 +3142,9 @@ yydefault:
 			yyVAL.stmt = stmt
 
 		}
-	case 224:
+	case 246:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line sql.y:1720
+//line sql.y:1829
 		{
 			stmt := yyDollar[7].stmt.(*influxql.ShowTagValuesStatement)
 			stmt.TagKeyCondition = nil
@@ -2956,36 +3155,36 @@ yydefault:
 			stmt.Offset = yyDollar[10].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 225:
+	case 247:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1733
+//line sql.y:1842
 		{
 			stmt := &influxql.ShowTagValuesStatement{}
 			stmt.Op = influxql.EQ
 			stmt.TagKeyExpr = yyDollar[2].expr.(*influxql.ListLiteral)
 			yyVAL.stmt = stmt
 		}
-	case 226:
+	case 248:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1740
+//line sql.y:1849
 		{
 			stmt := &influxql.ShowTagValuesStatement{}
 			stmt.Op = influxql.NEQ
 			stmt.TagKeyExpr = yyDollar[2].expr.(*influxql.ListLiteral)
 			yyVAL.stmt = stmt
 		}
-	case 227:
+	case 249:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:1747
+//line sql.y:1856
 		{
 			stmt := &influxql.ShowTagValuesStatement{}
 			stmt.Op = influxql.IN
 			stmt.TagKeyExpr = yyDollar[3].expr.(*influxql.ListLiteral)
 			yyVAL.stmt = stmt
 		}
-	case 228:
+	case 250:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1754
+//line sql.y:1863
 		{
 			stmt := &influxql.ShowTagValuesStatement{}
 			stmt.Op = influxql.EQREGEX
@@ -2996,9 +3195,9 @@ yydefault:
 			stmt.TagKeyExpr = &influxql.RegexLiteral{Val: re}
 			yyVAL.stmt = stmt
 		}
-	case 229:
+	case 251:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1765
+//line sql.y:1874
 		{
 			stmt := &influxql.ShowTagValuesStatement{}
 			stmt.Op = influxql.NEQREGEX
@@ -3009,47 +3208,47 @@ yydefault:
 			stmt.TagKeyExpr = &influxql.RegexLiteral{Val: re}
 			yyVAL.stmt = stmt
 		}
-	case 230:
+	case 252:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:1779
+//line sql.y:1888
 		{
 			temp := []string{yyDollar[1].str}
 			yyVAL.expr = &influxql.ListLiteral{Vals: temp}
 		}
-	case 231:
+	case 253:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1784
+//line sql.y:1893
 		{
 			yyDollar[3].expr.(*influxql.ListLiteral).Vals = append(yyDollar[3].expr.(*influxql.ListLiteral).Vals, yyDollar[1].str)
 			yyVAL.expr = yyDollar[3].expr
 		}
-	case 232:
+	case 254:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:1791
+//line sql.y:1900
 		{
 			yyVAL.str = yyDollar[1].str
 		}
-	case 233:
+	case 255:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:1799
+//line sql.y:1908
 		{
 			stmt := &influxql.ExplainStatement{}
 			stmt.Statement = yyDollar[3].stmt.(*influxql.SelectStatement)
 			stmt.Analyze = true
 			yyVAL.stmt = stmt
 		}
-	case 234:
+	case 256:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:1806
+//line sql.y:1915
 		{
 			stmt := &influxql.ExplainStatement{}
 			stmt.Statement = yyDollar[2].stmt.(*influxql.SelectStatement)
 			stmt.Analyze = false
 			yyVAL.stmt = stmt
 		}
-	case 235:
+	case 257:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line sql.y:1816
+//line sql.y:1925
 		{
 			stmt := &influxql.ShowTagKeyCardinalityStatement{}
 			stmt.Database = yyDollar[6].str
@@ -3061,9 +3260,9 @@ yydefault:
 			stmt.Offset = yyDollar[10].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 236:
+	case 258:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1828
+//line sql.y:1937
 		{
 			stmt := &influxql.ShowTagKeyCardinalityStatement{}
 			stmt.Database = yyDollar[6].str
@@ -3074,9 +3273,9 @@ yydefault:
 			stmt.Offset = yyDollar[9].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 237:
+	case 259:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1839
+//line sql.y:1948
 		{
 			stmt := &influxql.ShowTagKeyCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3088,9 +3287,9 @@ yydefault:
 			stmt.Offset = yyDollar[9].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 238:
+	case 260:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:1851
+//line sql.y:1960
 		{
 			stmt := &influxql.ShowTagKeyCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3101,9 +3300,9 @@ yydefault:
 			stmt.Offset = yyDollar[8].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 239:
+	case 261:
 		yyDollar = yyS[yypt-13 : yypt+1]
-//line sql.y:1867
+//line sql.y:1976
 		{
 			stmt := &influxql.ShowTagValuesCardinalityStatement{}
 			stmt.Database = yyDollar[6].str
@@ -3120,9 +3319,9 @@ yydefault:
 			yyVAL.stmt = stmt
 
 		}
-	case 240:
+	case 262:
 		yyDollar = yyS[yypt-12 : yypt+1]
-//line sql.y:1884
+//line sql.y:1993
 		{
 			stmt := &influxql.ShowTagValuesCardinalityStatement{}
 			stmt.Database = yyDollar[6].str
@@ -3137,9 +3336,9 @@ yydefault:
 			stmt.TagKeyCondition = nil
 			yyVAL.stmt = stmt
 		}
-	case 241:
+	case 263:
 		yyDollar = yyS[yypt-12 : yypt+1]
-//line sql.y:1899
+//line sql.y:2008
 		{
 			stmt := &influxql.ShowTagValuesCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3156,9 +3355,9 @@ yydefault:
 			yyVAL.stmt = stmt
 
 		}
-	case 242:
+	case 264:
 		yyDollar = yyS[yypt-11 : yypt+1]
-//line sql.y:1916
+//line sql.y:2025
 		{
 			stmt := &influxql.ShowTagValuesCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3173,9 +3372,9 @@ yydefault:
 			stmt.TagKeyCondition = nil
 			yyVAL.stmt = stmt
 		}
-	case 243:
+	case 265:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line sql.y:1934
+//line sql.y:2043
 		{
 			stmt := &influxql.ShowFieldKeyCardinalityStatement{}
 			stmt.Database = yyDollar[6].str
@@ -3187,9 +3386,9 @@ yydefault:
 			stmt.Offset = yyDollar[10].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 244:
+	case 266:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1946
+//line sql.y:2055
 		{
 			stmt := &influxql.ShowFieldKeyCardinalityStatement{}
 			stmt.Database = yyDollar[6].str
@@ -3200,9 +3399,9 @@ yydefault:
 			stmt.Offset = yyDollar[9].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 245:
+	case 267:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1957
+//line sql.y:2066
 		{
 			stmt := &influxql.ShowFieldKeyCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3214,9 +3413,9 @@ yydefault:
 			stmt.Offset = yyDollar[9].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 246:
+	case 268:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:1969
+//line sql.y:2078
 		{
 			stmt := &influxql.ShowFieldKeyCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3227,9 +3426,9 @@ yydefault:
 			stmt.Offset = yyDollar[8].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 247:
+	case 269:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:1983
+//line sql.y:2092
 		{
 			stmt := &influxql.CreateMeasurementStatement{}
 			stmt.Database = yyDollar[3].ment.Database
@@ -3244,9 +3443,9 @@ yydefault:
 			stmt.Type = yyDollar[9].str
 			yyVAL.stmt = stmt
 		}
-	case 248:
+	case 270:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:1998
+//line sql.y:2107
 		{
 			stmt := &influxql.CreateMeasurementStatement{}
 			stmt.Database = yyDollar[3].ment.Database
@@ -3257,9 +3456,9 @@ yydefault:
 			stmt.Type = yyDollar[7].str
 			yyVAL.stmt = stmt
 		}
-	case 249:
+	case 271:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:2009
+//line sql.y:2118
 		{
 			stmt := &influxql.CreateMeasurementStatement{}
 			stmt.Database = yyDollar[3].ment.Database
@@ -3271,9 +3470,9 @@ yydefault:
 			}
 			yyVAL.stmt = stmt
 		}
-	case 250:
+	case 272:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2021
+//line sql.y:2130
 		{
 			stmt := &influxql.CreateMeasurementStatement{}
 			stmt.Database = yyDollar[3].ment.Database
@@ -3282,18 +3481,18 @@ yydefault:
 			stmt.Type = "hash"
 			yyVAL.stmt = stmt
 		}
-	case 251:
+	case 273:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2032
+//line sql.y:2141
 		{
 			yyVAL.indexType = &IndexType{
 				types: []string{yyDollar[1].str},
 				lists: [][]string{yyDollar[3].strSlice},
 			}
 		}
-	case 252:
+	case 274:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:2041
+//line sql.y:2150
 		{
 			indextype := yyDollar[1].indexType
 			if yyDollar[2].indexType != nil {
@@ -3302,83 +3501,181 @@ yydefault:
 			}
 			yyVAL.indexType = indextype
 		}
-	case 253:
+	case 275:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:2050
+//line sql.y:2159
 		{
 			yyVAL.indexType = nil
 		}
-	case 254:
+	case 276:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:2056
+//line sql.y:2165
 		{
 			yyVAL.strSlice = []string{yyDollar[1].str}
 		}
-	case 255:
+	case 277:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2060
+//line sql.y:2169
 		{
 
 			yyVAL.strSlice = append([]string{yyDollar[1].str}, yyDollar[3].strSlice...)
 		}
-	case 256:
+	case 278:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:2067
+//line sql.y:2176
 		{
 			yyVAL.str = yyDollar[2].str
 		}
-	case 257:
+	case 279:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line sql.y:2071
+//line sql.y:2180
 		{
 			yyVAL.str = "hash"
 		}
-	case 258:
+	case 280:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:2077
+//line sql.y:2186
 		{
 			yyVAL.strSlice = []string{yyDollar[1].str}
 		}
-	case 259:
+	case 281:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2081
+//line sql.y:2190
 		{
 			yyVAL.strSlice = append(yyDollar[1].strSlice, yyDollar[3].str)
 		}
-	case 260:
+	case 282:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line sql.y:2086
+//line sql.y:2195
 		{
 			yyVAL.str = yyDollar[1].str
 		}
-	case 261:
+	case 283:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2092
+//line sql.y:2201
 		{
 			stmt := &influxql.DropShardStatement{}
 			stmt.ID = uint64(yyDollar[3].int64)
 			yyVAL.stmt = stmt
 		}
-	case 262:
+	case 284:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:2209
+		{
+			stmt := &influxql.DecommissionNodeStatement{}
+			stmt.NodeID = uint64(yyDollar[3].int64)
+			yyVAL.stmt = stmt
+		}
+	case 285:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line sql.y:2100
+//line sql.y:2217
 		{
 			stmt := &influxql.SetPasswordUserStatement{}
 			stmt.Name = yyDollar[4].str
 			stmt.Password = yyDollar[6].str
 			yyVAL.stmt = stmt
 		}
-	case 263:
+	case 286:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line sql.y:2111
+//line sql.y:2228
 		{
 			stmt := &influxql.ShowGrantsForUserStatement{}
 			stmt.Name = yyDollar[4].str
 			yyVAL.stmt = stmt
 		}
-	case 264:
+	case 287:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line sql.y:2236
+		{
+			yyVAL.stmt = &influxql.ShowGrantsForRoleStatement{Name: yyDollar[5].str}
+		}
+	case 288:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:2242
+		{
+			yyVAL.stmt = &influxql.CreateRoleStatement{Name: yyDollar[3].str}
+		}
+	case 289:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:2248
+		{
+			yyVAL.stmt = &influxql.DropRoleStatement{Name: yyDollar[3].str}
+		}
+	case 290:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line sql.y:2254
+		{
+			yyVAL.stmt = &influxql.ShowRolesStatement{}
+		}
+	case 291:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line sql.y:2260
+		{
+			yyVAL.stmt = &influxql.CreateTokenStatement{Name: yyDollar[3].str, User: yyDollar[5].str}
+		}
+	case 292:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line sql.y:2264
+		{
+			yyVAL.stmt = &influxql.CreateTokenStatement{Name: yyDollar[3].str, User: yyDollar[5].str, Duration: yyDollar[7].tdur}
+		}
+	case 293:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line sql.y:2268
+		{
+			yyVAL.stmt = &influxql.CreateTokenStatement{Name: yyDollar[3].str, User: yyDollar[5].str, Scopes: yyDollar[7].tokenScopes}
+		}
+	case 294:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:2119
+//line sql.y:2272
+		{
+			yyVAL.stmt = &influxql.CreateTokenStatement{Name: yyDollar[3].str, User: yyDollar[5].str, Scopes: yyDollar[7].tokenScopes, Duration: yyDollar[9].tdur}
+		}
+	case 295:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:2278
+		{
+			yyVAL.tokenScopes = yyDollar[1].tokenScopes
+		}
+	case 296:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:2282
+		{
+			yyVAL.tokenScopes = append(yyDollar[1].tokenScopes, yyDollar[3].tokenScopes...)
+		}
+	case 297:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:2288
+		{
+			yyVAL.tokenScopes = []influxql.TokenScope{{Database: yyDollar[3].str, Privilege: influxql.AllPrivileges}}
+		}
+	case 298:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:2292
+		{
+			yyVAL.tokenScopes = []influxql.TokenScope{{Database: yyDollar[3].str, Privilege: privilegeByName(yylex, yyDollar[1].str)}}
+		}
+	case 299:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:2298
+		{
+			yyVAL.stmt = &influxql.RevokeTokenStatement{Name: yyDollar[3].str}
+		}
+	case 300:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line sql.y:2304
+		{
+			yyVAL.stmt = &influxql.ShowTokensStatement{}
+		}
+	case 301:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line sql.y:2308
+		{
+			yyVAL.stmt = &influxql.ShowTokensStatement{User: yyDollar[4].str}
+		}
+	case 302:
+		yyDollar = yyS[yypt-9 : yypt+1]
+//line sql.y:2314
 		{
 			stmt := &influxql.ShowMeasurementCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3390,9 +3687,9 @@ yydefault:
 			stmt.Offset = yyDollar[9].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 265:
+	case 303:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:2131
+//line sql.y:2326
 		{
 			stmt := &influxql.ShowMeasurementCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3403,9 +3700,9 @@ yydefault:
 			stmt.Offset = yyDollar[8].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 266:
+	case 304:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:2142
+//line sql.y:2337
 		{
 			stmt := &influxql.ShowMeasurementCardinalityStatement{}
 			stmt.Database = yyDollar[4].str
@@ -3417,9 +3714,9 @@ yydefault:
 			stmt.Offset = yyDollar[8].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 267:
+	case 305:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:2154
+//line sql.y:2349
 		{
 			stmt := &influxql.ShowMeasurementCardinalityStatement{}
 			stmt.Database = yyDollar[4].str
@@ -3430,9 +3727,9 @@ yydefault:
 			stmt.Offset = yyDollar[7].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 268:
+	case 306:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line sql.y:2168
+//line sql.y:2363
 		{
 			stmt := &influxql.ShowSeriesCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3444,9 +3741,9 @@ yydefault:
 			stmt.Offset = yyDollar[9].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 269:
+	case 307:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:2180
+//line sql.y:2375
 		{
 			stmt := &influxql.ShowSeriesCardinalityStatement{}
 			stmt.Database = yyDollar[5].str
@@ -3457,9 +3754,9 @@ yydefault:
 			stmt.Offset = yyDollar[8].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 270:
+	case 308:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line sql.y:2191
+//line sql.y:2386
 		{
 			stmt := &influxql.ShowSeriesCardinalityStatement{}
 			stmt.Database = yyDollar[4].str
@@ -3471,9 +3768,9 @@ yydefault:
 			stmt.Offset = yyDollar[8].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 271:
+	case 309:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:2203
+//line sql.y:2398
 		{
 			stmt := &influxql.ShowSeriesCardinalityStatement{}
 			stmt.Database = yyDollar[4].str
@@ -3484,16 +3781,16 @@ yydefault:
 			stmt.Offset = yyDollar[7].intSlice[1]
 			yyVAL.stmt = stmt
 		}
-	case 272:
+	case 310:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line sql.y:2217
+//line sql.y:2412
 		{
 			stmt := &influxql.ShowShardsStatement{}
 			yyVAL.stmt = stmt
 		}
-	case 273:
+	case 311:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line sql.y:2225
+//line sql.y:2420
 		{
 			stmt := &influxql.AlterShardKeyStatement{}
 			stmt.Database = yyDollar[3].ment.Database
@@ -3504,9 +3801,9 @@ yydefault:
 			stmt.Type = yyDollar[7].str
 			yyVAL.stmt = stmt
 		}
-	case 274:
+	case 312:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2236
+//line sql.y:2431
 		{
 			stmt := &influxql.AlterShardKeyStatement{}
 			stmt.Database = yyDollar[3].ment.Database
@@ -3515,16 +3812,16 @@ yydefault:
 			stmt.Type = "hash"
 			yyVAL.stmt = stmt
 		}
-	case 275:
+	case 313:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2250
+//line sql.y:2445
 		{
 			stmt := &influxql.ShowShardGroupsStatement{}
 			yyVAL.stmt = stmt
 		}
-	case 276:
+	case 314:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line sql.y:2257
+//line sql.y:2452
 		{
 			stmt := &influxql.DropMeasurementStatement{}
 			stmt.Name = yyDollar[3].str